@@ -1,12 +1,17 @@
 package clients
 
 import (
+	"errors"
+	"fmt"
 	"polybot/clients/discord"
 	"polybot/clients/gist"
 	"polybot/clients/notifier"
 	"polybot/clients/polymarketapi"
 	"polybot/clients/polymarketevents"
+	"polybot/clients/slack"
+	"polybot/clients/storage"
 	"polybot/clients/telegram"
+	"polybot/clients/webhook"
 	"polybot/config"
 
 	"go.uber.org/zap"
@@ -17,26 +22,76 @@ type Clients struct {
 
 	Discord          *discord.DiscordClient
 	Telegram         *telegram.TelegramClient
-	Notifier         notifier.Notifier // Combined notifier for all channels
+	Slack            *slack.SlackClient
+	Webhooks         []*webhook.WebhookClient
+	Notifier         notifier.Notifier          // Combined notifier for all channels
+	ChatNotifier     *notifier.MultiNotifier    // Discord, Telegram and Slack channels of Notifier
+	WebhookNotifier  *notifier.MultiNotifier    // Webhook endpoints of Notifier
+	NotifierQueues   []*notifier.QueuedNotifier // Per-channel delivery queues behind Notifier
 	Polymarket       *polymarketapi.PolymarketApiClient
 	PolymarketEvents *polymarketevents.PolymarketEventsClient
 	Gist             *gist.Client
+	Storage          gist.Storage // Persistence backend (Gist unless a local backend is configured)
 }
 
-func NewClients(logger *zap.Logger, cfg *config.Config) *Clients {
+// NewClients creates every client. It fails if the configured storage backend
+// can't be created, since namespaces have already been mapped to it.
+func NewClients(logger *zap.Logger, cfg *config.Config) (*Clients, error) {
+	gistClient := gist.NewClient(logger, cfg)
+	storageBackend, err := NewStorage(logger, cfg, gistClient)
+	if err != nil {
+		return nil, fmt.Errorf("create %s storage backend: %w", cfg.Storage.Backend, err)
+	}
+
 	discordClient := discord.NewDiscordClient(logger, cfg)
 	telegramClient := telegram.NewTelegramClient(logger, cfg)
+	slackClient := slack.NewSlackClient(logger, cfg)
+	webhookClients := webhook.NewWebhookClients(logger, cfg)
 
-	// Create combined notifier for all channels
-	multiNotifier := notifier.NewMultiNotifier(discordClient, telegramClient)
+	// Queue each configured channel so slow or rate-limited sends never block detection
+	queueConfig := NotifierQueueConfig(cfg.NotifierQueue)
+	var queues []*notifier.QueuedNotifier
+	var chats, hooks []notifier.Notifier
+	if discordClient.IsEnabled() {
+		q := notifier.NewQueuedNotifier(logger, discordClient, queueConfig)
+		queues = append(queues, q)
+		chats = append(chats, q)
+	}
+	if telegramClient.IsEnabled() {
+		q := notifier.NewQueuedNotifier(logger, telegramClient, queueConfig)
+		queues = append(queues, q)
+		chats = append(chats, q)
+	}
+	if slackClient.IsEnabled() {
+		q := notifier.NewQueuedNotifier(logger, slackClient, queueConfig)
+		queues = append(queues, q)
+		chats = append(chats, q)
+	}
+	for _, wc := range webhookClients {
+		// Filter before queueing so skipped reasons don't count as deliveries
+		q := notifier.NewQueuedNotifier(logger, wc, queueConfig)
+		queues = append(queues, q)
+		hooks = append(hooks, notifier.NewFilteredNotifier(q, wc.Matches))
+	}
+
+	// Create combined notifiers for chat channels, webhooks and both
+	chatNotifier := notifier.NewMultiNotifier(chats...)
+	webhookNotifier := notifier.NewMultiNotifier(hooks...)
+	multiNotifier := notifier.NewMultiNotifier(chatNotifier, webhookNotifier)
 
 	c := &Clients{
-		Logger:     logger,
-		Discord:    discordClient,
-		Telegram:   telegramClient,
-		Notifier:   multiNotifier,
-		Polymarket: polymarketapi.NewPolymarketApiClient(logger, cfg),
-		Gist:       gist.NewClient(logger, cfg),
+		Logger:          logger,
+		Discord:         discordClient,
+		Telegram:        telegramClient,
+		Slack:           slackClient,
+		Webhooks:        webhookClients,
+		Notifier:        multiNotifier,
+		ChatNotifier:    chatNotifier,
+		WebhookNotifier: webhookNotifier,
+		NotifierQueues:  queues,
+		Polymarket:      polymarketapi.NewPolymarketApiClient(logger, cfg),
+		Gist:            gistClient,
+		Storage:         storageBackend,
 	}
 
 	// Only create WebSocket client if configured to use it
@@ -44,5 +99,80 @@ func NewClients(logger *zap.Logger, cfg *config.Config) *Clients {
 		c.PolymarketEvents = polymarketevents.NewPolymarketEventsClient(logger)
 	}
 
-	return c
+	return c, nil
+}
+
+// NewStorage creates the configured persistence backend, defaulting to the
+// gist client. Other backends use the cache gist ID as their default namespace.
+func NewStorage(logger *zap.Logger, cfg *config.Config, gistClient *gist.Client) (gist.Storage, error) {
+	switch cfg.Storage.Backend {
+	case "", config.StorageBackendGist:
+		return gistClient, nil
+	case config.StorageBackendFile:
+		s, err := storage.NewFileStore(logger, cfg.Storage.Dir, cfg.Gist.GistID)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case config.StorageBackendSQLite:
+		s, err := storage.NewSQLiteStore(logger, cfg.Storage.SQLitePath, cfg.Gist.GistID)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case config.StorageBackendS3:
+		s, err := storage.NewS3Store(logger, cfg.Storage.S3, cfg.Gist.GistID)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+// NotifierQueueConfig maps notifier queue settings from config.
+func NotifierQueueConfig(cfg config.NotifierQueueConfig) notifier.QueueConfig {
+	return notifier.QueueConfig{
+		BufferSize:     cfg.BufferSize,
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialBackoff: cfg.InitialBackoff,
+		MaxBackoff:     cfg.MaxBackoff,
+		MinInterval:    cfg.MinInterval,
+	}
+}
+
+// NewRouteNotifier creates a queued notifier that delivers to a routing
+// destination, reusing the configured bot of the destination's channel.
+func (c *Clients) NewRouteNotifier(dest config.RouteDestination, queueConfig notifier.QueueConfig) (*notifier.QueuedNotifier, error) {
+	var inner notifier.DeliveryNotifier
+	switch dest.Channel {
+	case config.RouteChannelDiscord:
+		if c.Discord == nil || !c.Discord.IsEnabled() {
+			return nil, errors.New("discord bot is not configured")
+		}
+		inner = c.Discord.WithChannel(dest.Target)
+	case config.RouteChannelTelegram:
+		if c.Telegram == nil {
+			return nil, errors.New("telegram bot is not configured")
+		}
+		telegramClient := c.Telegram.WithChat(dest.Target)
+		if !telegramClient.IsEnabled() {
+			return nil, errors.New("telegram bot is not configured")
+		}
+		inner = telegramClient
+	case config.RouteChannelSlack:
+		if c.Slack == nil {
+			return nil, errors.New("slack is not configured")
+		}
+		slackClient, err := c.Slack.WithChannel(dest.Target)
+		if err != nil {
+			return nil, err
+		}
+		inner = slackClient
+	default:
+		return nil, fmt.Errorf("unknown channel %q", dest.Channel)
+	}
+
+	return notifier.NewQueuedNotifier(c.Logger, notifier.WithName(inner, dest.Channel+":"+dest.Name), queueConfig), nil
 }
@@ -1,6 +1,8 @@
 package clients
 
 import (
+	"io"
+	"path/filepath"
 	"polybot/config"
 	"testing"
 
@@ -28,7 +30,10 @@ func TestNewClients(t *testing.T) {
 	}
 
 	logger := zap.NewNop()
-	clients := NewClients(logger, cfg)
+	clients, err := NewClients(logger, cfg)
+	if err != nil {
+		t.Fatalf("NewClients: %v", err)
+	}
 
 	if clients.Logger != logger {
 		t.Error("unexpected logger")
@@ -45,6 +50,9 @@ func TestNewClients(t *testing.T) {
 	if clients.Gist == nil {
 		t.Error("expected Gist client to be set")
 	}
+	if clients.Storage != clients.Gist {
+		t.Error("expected the gist client as default storage")
+	}
 }
 
 func TestNewClients_PollingMode(t *testing.T) {
@@ -60,7 +68,10 @@ func TestNewClients_PollingMode(t *testing.T) {
 		Gist: config.GistConfig{},
 	}
 
-	clients := NewClients(zap.NewNop(), cfg)
+	clients, err := NewClients(zap.NewNop(), cfg)
+	if err != nil {
+		t.Fatalf("NewClients: %v", err)
+	}
 
 	if clients.PolymarketEvents != nil {
 		t.Error("expected PolymarketEvents client to be nil when UseWebSocket is false")
@@ -77,7 +88,10 @@ func TestNewClients_NilLogger(t *testing.T) {
 		Gist: config.GistConfig{},
 	}
 
-	clients := NewClients(nil, cfg)
+	clients, err := NewClients(nil, cfg)
+	if err != nil {
+		t.Fatalf("NewClients: %v", err)
+	}
 
 	if clients.Logger != nil {
 		t.Error("expected nil logger to remain nil")
@@ -87,3 +101,58 @@ func TestNewClients_NilLogger(t *testing.T) {
 		t.Error("expected Discord client to be set")
 	}
 }
+
+func TestNewStorage(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Gist: config.GistConfig{GistID: "cache"},
+		Storage: config.StorageConfig{
+			Dir:        filepath.Join(dir, "files"),
+			SQLitePath: filepath.Join(dir, "polybot.db"),
+		},
+	}
+
+	for _, backend := range []string{config.StorageBackendFile, config.StorageBackendSQLite} {
+		cfg.Storage.Backend = backend
+		s, err := NewStorage(zap.NewNop(), cfg, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", backend, err)
+		}
+		if !s.IsEnabled() || s.GetGistID() != "cache" {
+			t.Errorf("%s: expected enabled store with default namespace, got %v %q", backend, s.IsEnabled(), s.GetGistID())
+		}
+		if err := s.Save(t.Context(), "a.json", "{}"); err != nil {
+			t.Errorf("%s: save failed: %v", backend, err)
+		}
+		if closer, ok := s.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+
+	// S3 needs a bucket
+	cfg.Storage.Backend = config.StorageBackendS3
+	if _, err := NewStorage(zap.NewNop(), cfg, nil); err == nil {
+		t.Error("expected error for s3 without a bucket")
+	}
+	cfg.Storage.S3 = config.S3Config{Endpoint: "http://localhost:9000", Bucket: "polybot", AccessKeyID: "k", SecretAccessKey: "s"}
+	if s, err := NewStorage(zap.NewNop(), cfg, nil); err != nil || s.GetGistID() != "cache" {
+		t.Errorf("unexpected s3 storage: %v", err)
+	}
+
+	cfg.Storage.Backend = "dynamo"
+	if _, err := NewStorage(zap.NewNop(), cfg, nil); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
+
+func TestNewClients_StorageError(t *testing.T) {
+	cfg := &config.Config{
+		Gist:    config.GistConfig{GistID: "cache"},
+		Storage: config.StorageConfig{Backend: config.StorageBackendS3},
+	}
+
+	// No silent fallback to gist: namespaces are already mapped to the backend
+	if clients, err := NewClients(zap.NewNop(), cfg); err == nil || clients != nil {
+		t.Errorf("expected an error for an unusable backend, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"polybot/clients/notifier"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// maxFieldLength is Discord's limit on embed field values.
+const maxFieldLength = 1024
+
+// SendDigest sends an alert digest embed.
+// Implements notifier.DigestNotifier interface.
+func (dc *DiscordClient) SendDigest(digest notifier.AlertDigest) {
+	if dc.session == nil {
+		dc.logger.Warn("discord session not initialized, skipping digest")
+		return
+	}
+
+	embed := dc.buildDigestEmbed(digest)
+	if _, err := dc.session.ChannelMessageSendEmbed(dc.channelID, embed, discordgo.WithContext(context.Background())); err != nil {
+		dc.logger.Error("failed to send discord digest", zap.Error(err))
+		return
+	}
+
+	dc.logger.Info("sent discord alert digest", zap.Int("alerts", digest.TotalAlerts))
+}
+
+func (dc *DiscordClient) buildDigestEmbed(digest notifier.AlertDigest) *discordgo.MessageEmbed {
+	description := fmt.Sprintf("**%d alerts** in the last %s\nLast hour: %d · 24h: %d · 7d: %d",
+		digest.TotalAlerts,
+		digest.WindowLabel(),
+		digest.AlertsLastHour, digest.AlertsLastDay, digest.AlertsLastWeek,
+	)
+
+	var fields []*discordgo.MessageEmbedField
+
+	if len(digest.ReasonCounts) > 0 {
+		var lines []string
+		for _, rc := range digest.ReasonCounts {
+			lines = append(lines, fmt.Sprintf("`%s` — %d", rc.Reason, rc.Count))
+		}
+		fields = append(fields, digestField("📋 By Reason", lines))
+	}
+
+	if len(digest.TopWallets) > 0 {
+		var lines []string
+		for _, w := range digest.TopWallets {
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			if w.URL != "" {
+				name = fmt.Sprintf("[%s](%s)", name, w.URL)
+			}
+			lines = append(lines, fmt.Sprintf("%s — %d alerts · $%.0f", name, w.Count, w.Notional))
+		}
+		fields = append(fields, digestField("👛 Top Wallets", lines))
+	}
+
+	if len(digest.TopMarkets) > 0 {
+		var lines []string
+		for _, m := range digest.TopMarkets {
+			title := m.Title
+			if title == "" {
+				title = shortAddress(m.ConditionID)
+			}
+			if m.URL != "" {
+				title = fmt.Sprintf("[%s](%s)", title, m.URL)
+			}
+			lines = append(lines, fmt.Sprintf("%s — %d alerts · $%.0f", title, m.Count, m.Notional))
+		}
+		fields = append(fields, digestField("📈 Top Markets", lines))
+	}
+
+	if len(digest.LargestTrades) > 0 {
+		var lines []string
+		for _, a := range digest.LargestTrades {
+			sideEmoji := "🟢"
+			if strings.ToUpper(a.Side) == "SELL" {
+				sideEmoji = "🔴"
+			}
+			market := a.MarketTitle
+			if a.MarketURL != "" {
+				market = fmt.Sprintf("[%s](%s)", market, a.MarketURL)
+			}
+			lines = append(lines, fmt.Sprintf("%s **$%.0f** %s @ $%.3f — %s", sideEmoji, a.Notional, a.Outcome, a.Price, market))
+		}
+		fields = append(fields, digestField("💰 Largest Trades", lines))
+	}
+
+	// Format timestamp for footer (PST)
+	pst, _ := time.LoadLocation("America/Los_Angeles")
+	footerText := fmt.Sprintf("polybot digest * %s", digest.WindowEnd.In(pst).Format("1/2/2006, 3:04:05PM (MST)"))
+
+	return &discordgo.MessageEmbed{
+		Title:       "📊 Alert Digest",
+		Description: description,
+		Color:       0x3498DB,
+		Fields:      fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: footerText,
+		},
+		Timestamp: digest.WindowEnd.Format(time.RFC3339),
+	}
+}
+
+// digestField joins lines into an embed field, dropping lines past Discord's limit.
+func digestField(name string, lines []string) *discordgo.MessageEmbedField {
+	var sb strings.Builder
+	for _, line := range lines {
+		if sb.Len()+len(line)+1 > maxFieldLength {
+			break
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(line)
+	}
+	return &discordgo.MessageEmbedField{Name: name, Value: sb.String()}
+}
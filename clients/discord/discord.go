@@ -1,16 +1,23 @@
 package discord
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"polybot/clients/notifier"
 	"polybot/config"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
 )
 
+// outcomeFieldName is the embed field AnnotateMessage writes the market outcome to.
+const outcomeFieldName = "Market Outcome"
+
 // DiscordClient sends alerts to Discord.
 // Implements notifier.Notifier interface.
 type DiscordClient struct {
@@ -18,6 +25,12 @@ type DiscordClient struct {
 	session   *discordgo.Session
 	channelID string
 	isProd    bool
+	shared    bool // Session is borrowed from another client (see WithChannel)
+
+	// Alert buttons are attached while interactions are handled (see StartInteractions).
+	// Shared with WithChannel clients.
+	interactive   *atomic.Bool
+	removeHandler func()
 }
 
 func NewDiscordClient(logger *zap.Logger, cfg *config.Config) *DiscordClient {
@@ -34,9 +47,10 @@ func NewDiscordClient(logger *zap.Logger, cfg *config.Config) *DiscordClient {
 	if token == "" {
 		logger.Warn("DISCORD_BOT_TOKEN not set, Discord alerts disabled")
 		return &DiscordClient{
-			logger:    logger,
-			channelID: channelID,
-			isProd:    cfg.IsProd,
+			logger:      logger,
+			channelID:   channelID,
+			isProd:      cfg.IsProd,
+			interactive: new(atomic.Bool),
 		}
 	}
 
@@ -44,22 +58,39 @@ func NewDiscordClient(logger *zap.Logger, cfg *config.Config) *DiscordClient {
 	if err != nil {
 		logger.Error("failed to create discord session", zap.Error(err))
 		return &DiscordClient{
-			logger:    logger,
-			channelID: channelID,
-			isProd:    cfg.IsProd,
+			logger:      logger,
+			channelID:   channelID,
+			isProd:      cfg.IsProd,
+			interactive: new(atomic.Bool),
 		}
 	}
 
+	// Rate limits are retried by the alert queue, which honors retry_after
+	session.ShouldRetryOnRateLimit = false
+
 	logger.Info("discord bot initialized",
 		zap.Bool("isProd", cfg.IsProd),
 		zap.String("channelID", channelID),
 	)
 
 	return &DiscordClient{
-		logger:    logger,
-		session:   session,
-		channelID: channelID,
-		isProd:    cfg.IsProd,
+		logger:      logger,
+		session:     session,
+		channelID:   channelID,
+		isProd:      cfg.IsProd,
+		interactive: new(atomic.Bool),
+	}
+}
+
+// WithChannel returns a client that posts to another channel over the same session.
+func (dc *DiscordClient) WithChannel(channelID string) *DiscordClient {
+	return &DiscordClient{
+		logger:      dc.logger.With(zap.String("channelID", channelID)),
+		session:     dc.session,
+		channelID:   channelID,
+		isProd:      dc.isProd,
+		shared:      true,
+		interactive: dc.interactive,
 	}
 }
 
@@ -79,6 +110,16 @@ func (dc *DiscordClient) SendMessage(message string) {
 	dc.logger.Info("sent discord message")
 }
 
+// Name returns the channel name. Implements notifier.DeliveryNotifier interface.
+func (dc *DiscordClient) Name() string {
+	return "discord"
+}
+
+// IsEnabled returns true if the bot session is initialized.
+func (dc *DiscordClient) IsEnabled() bool {
+	return dc.session != nil
+}
+
 // SendTradeAlert sends a rich embedded trade alert.
 // Implements notifier.Notifier interface.
 func (dc *DiscordClient) SendTradeAlert(alert notifier.TradeAlert) {
@@ -87,18 +128,116 @@ func (dc *DiscordClient) SendTradeAlert(alert notifier.TradeAlert) {
 		return
 	}
 
-	embed := dc.buildTradeEmbed(alert)
+	if err := dc.DeliverTradeAlert(context.Background(), alert); err != nil {
+		dc.logger.Error("failed to send discord embed", zap.Error(err))
+	}
+}
+
+// DeliverTradeAlert sends a rich embedded trade alert and returns any error.
+// Implements notifier.DeliveryNotifier interface.
+func (dc *DiscordClient) DeliverTradeAlert(ctx context.Context, alert notifier.TradeAlert) error {
+	_, err := dc.DeliverThreadedAlert(ctx, alert, nil)
+	return err
+}
+
+// DeliverThreadedAlert sends a trade alert, as a reply to replyTo when it was
+// sent to this client's channel, and returns the sent message.
+// Implements notifier.ThreadedNotifier interface.
+func (dc *DiscordClient) DeliverThreadedAlert(ctx context.Context, alert notifier.TradeAlert, replyTo *notifier.MessageRef) (notifier.MessageRef, error) {
+	if dc.session == nil {
+		return notifier.MessageRef{}, notifier.Permanent(errors.New("discord session not initialized"))
+	}
+
+	msg := &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{dc.buildTradeEmbed(alert)},
+	}
+	if dc.interactive != nil && dc.interactive.Load() {
+		msg.Components = buildAlertButtons(alert)
+	}
+	if replyTo != nil && replyTo.ChatID == dc.channelID {
+		failIfNotExists := false // Post normally if the original was deleted
+		msg.Reference = &discordgo.MessageReference{
+			MessageID:       replyTo.MessageID,
+			ChannelID:       replyTo.ChatID,
+			FailIfNotExists: &failIfNotExists,
+		}
+	}
 
-	_, err := dc.session.ChannelMessageSendEmbed(dc.channelID, embed)
+	sent, err := dc.session.ChannelMessageSendComplex(dc.channelID, msg, discordgo.WithContext(ctx))
 	if err != nil {
-		dc.logger.Error("failed to send discord embed", zap.Error(err))
-		return
+		return notifier.MessageRef{}, classifyDiscordError(err)
 	}
 
 	dc.logger.Info("sent discord trade alert",
 		zap.String("trader", alert.TraderName),
 		zap.String("market", alert.MarketTitle),
+		zap.Bool("reply", msg.Reference != nil),
 	)
+	return notifier.MessageRef{
+		ChatID:    dc.channelID,
+		MessageID: sent.ID,
+		SentAt:    time.Now(),
+	}, nil
+}
+
+// AnnotateMessage adds the note as a field on a sent alert's embed.
+// Implements notifier.ThreadedNotifier interface.
+func (dc *DiscordClient) AnnotateMessage(ctx context.Context, ref notifier.MessageRef, note string) error {
+	if dc.session == nil {
+		return errors.New("discord session not initialized")
+	}
+
+	msg, err := dc.session.ChannelMessage(ref.ChatID, ref.MessageID, discordgo.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("fetch discord message: %w", err)
+	}
+	if len(msg.Embeds) == 0 {
+		return errors.New("discord message has no embed to annotate")
+	}
+
+	embeds := msg.Embeds
+	annotateEmbed(embeds[0], note)
+	edit := discordgo.NewMessageEdit(ref.ChatID, ref.MessageID)
+	edit.Embeds = &embeds
+	if _, err := dc.session.ChannelMessageEditComplex(edit, discordgo.WithContext(ctx)); err != nil {
+		return fmt.Errorf("edit discord message: %w", err)
+	}
+	return nil
+}
+
+// annotateEmbed sets the market outcome field on an alert embed.
+func annotateEmbed(embed *discordgo.MessageEmbed, note string) {
+	for _, f := range embed.Fields {
+		if f.Name == outcomeFieldName {
+			f.Value = note
+			return
+		}
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  outcomeFieldName,
+		Value: note,
+	})
+}
+
+// classifyDiscordError maps discordgo errors to retry semantics.
+func classifyDiscordError(err error) error {
+	var rateLimit *discordgo.RateLimitError
+	if errors.As(err, &rateLimit) && rateLimit.RateLimit != nil && rateLimit.TooManyRequests != nil {
+		return &notifier.RetryAfterError{Err: err, RetryAfter: rateLimit.RetryAfter}
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		status := restErr.Response.StatusCode
+		if status == http.StatusTooManyRequests {
+			return &notifier.RetryAfterError{Err: err}
+		}
+		// Other client errors (bad embed, missing permissions) won't succeed on retry
+		if status >= 400 && status < 500 {
+			return notifier.Permanent(err)
+		}
+	}
+	return err
 }
 
 func (dc *DiscordClient) buildTradeEmbed(alert notifier.TradeAlert) *discordgo.MessageEmbed {
@@ -112,6 +251,9 @@ func (dc *DiscordClient) buildTradeEmbed(alert notifier.TradeAlert) *discordgo.M
 
 	// Build title based on alert reasons
 	title := dc.buildAlertTitle(alert.Reasons)
+	if alert.IsUpdate {
+		title = "🔁 Update: " + title
+	}
 
 	// Format trader display with link
 	traderDisplay := alert.TraderName
@@ -128,6 +270,11 @@ func (dc *DiscordClient) buildTradeEmbed(alert notifier.TradeAlert) *discordgo.M
 
 	// Format trade info
 	tradeInfo := fmt.Sprintf("%.2f shares @ $%.3f", alert.Shares, alert.Price)
+	if alert.MergedFills > 1 {
+		tradeInfo = fmt.Sprintf("%d fills: %.2f shares @ $%.3f avg", alert.MergedFills, alert.Shares, alert.Price)
+	} else if len(alert.Fills) > 1 {
+		tradeInfo = fmt.Sprintf("%.2f shares @ $%.3f VWAP (%d maker fills)", alert.Shares, alert.Price, len(alert.Fills))
+	}
 
 	// Format win rate
 	winRateStr := "N/A"
@@ -190,6 +337,45 @@ func (dc *DiscordClient) buildTradeEmbed(alert notifier.TradeAlert) *discordgo.M
 		},
 	}
 
+	if alert.TotalFills > 1 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "🔁 Running Total",
+			Value: fmt.Sprintf("%d fills · %.2f shares · $%.2f", alert.TotalFills, alert.TotalShares, alert.TotalNotional),
+		})
+	}
+	if alert.HasImpactInfo {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "💥 Market Impact",
+			Value: alert.ImpactSummary(),
+		})
+	}
+	if alert.HasClusterInfo {
+		lines := []string{alert.ClusterSummary()}
+		for _, w := range alert.ClusterWallets {
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			if w.URL != "" {
+				name = fmt.Sprintf("[%s](%s)", name, w.URL)
+			}
+			lines = append(lines, fmt.Sprintf("%s — $%.0f · %.0f shares · %d markets", name, w.Notional, w.Shares, w.UniqueMarkets))
+		}
+		fields = append(fields, digestField("👥 Coordinated Entry", lines))
+	}
+	if alert.WalletClusterID != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "🔗 Wallet Cluster",
+			Value: alert.WalletClusterSummary(),
+		})
+	}
+	if alert.HasBookInfo {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "📖 Order Book",
+			Value: alert.BookSummary(),
+		})
+	}
+
 	// Build description with market info
 	description := fmt.Sprintf("**%s**\nOutcome: %s", alert.MarketTitle, alert.Outcome)
 
@@ -240,6 +426,8 @@ func (dc *DiscordClient) buildAlertTitle(reasons []notifier.AlertReason) string
 	hasPerfectExitTiming := false
 	hasStealthAccumulation := false
 	hasPreMovePositioning := false
+	hasMarketImpact := false
+	hasCoordinatedEntry := false
 
 	for _, r := range reasons {
 		switch r {
@@ -275,6 +463,10 @@ func (dc *DiscordClient) buildAlertTitle(reasons []notifier.AlertReason) string
 			hasStealthAccumulation = true
 		case notifier.AlertReasonPreMovePositioning:
 			hasPreMovePositioning = true
+		case notifier.AlertReasonMarketImpact:
+			hasMarketImpact = true
+		case notifier.AlertReasonCoordinatedEntry:
+			hasCoordinatedEntry = true
 		}
 	}
 
@@ -328,6 +520,12 @@ func (dc *DiscordClient) buildAlertTitle(reasons []notifier.AlertReason) string
 	if hasPreMovePositioning {
 		count++
 	}
+	if hasMarketImpact {
+		count++
+	}
+	if hasCoordinatedEntry {
+		count++
+	}
 
 	// For 3+ reasons, use generic multi-alert title
 	if count >= 3 {
@@ -442,7 +640,32 @@ func (dc *DiscordClient) buildAlertTitle(reasons []notifier.AlertReason) string
 		return "🎯 Pre-Move Positioning + Massive Trade"
 	}
 
+	// Market impact combos (size relative to the market)
+	if hasMarketImpact && hasHighWinRate {
+		return "💥 Market Impact + High Win Rate"
+	}
+	if hasMarketImpact && hasNewWallet {
+		return "💥 Market Impact + New Wallet"
+	}
+	if hasMarketImpact && hasLowActivity {
+		return "💥 Market Impact + Low Activity"
+	}
+	if hasMarketImpact && hasMassiveTrade {
+		return "💥 Massive Trade + Market Impact"
+	}
+
+	// Coordinated entry combos (fresh wallets buying together)
+	if hasCoordinatedEntry && hasLowActivity {
+		return "👥 Coordinated Entry + Low Activity"
+	}
+	if hasCoordinatedEntry && hasNewWallet {
+		return "👥 Coordinated Entry + New Wallet"
+	}
+
 	// Single reasons - Advanced patterns (highest priority)
+	if hasCoordinatedEntry {
+		return "👥 Coordinated Entry"
+	}
 	if hasPreMovePositioning {
 		return "🎯 Pre-Move Positioning"
 	}
@@ -469,6 +692,9 @@ func (dc *DiscordClient) buildAlertTitle(reasons []notifier.AlertReason) string
 	if hasContrarianWinner {
 		return "🏆 Proven Contrarian Winner"
 	}
+	if hasMarketImpact {
+		return "💥 Outsized Market Impact"
+	}
 	if hasMassiveTrade {
 		return "🐋 Massive Trade"
 	}
@@ -500,9 +726,9 @@ func shortAddress(addr string) string {
 	return addr[:6] + "…" + addr[len(addr)-6:]
 }
 
-// Close closes the Discord session.
+// Close closes the Discord session. Clients from WithChannel leave it open.
 func (dc *DiscordClient) Close() error {
-	if dc.session != nil {
+	if dc.session != nil && !dc.shared {
 		return dc.session.Close()
 	}
 	return nil
@@ -1,11 +1,16 @@
 package discord
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"polybot/clients/notifier"
 	"polybot/config"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
 )
 
@@ -820,3 +825,207 @@ func TestBuildTradeEmbed_CopyTrader(t *testing.T) {
 		t.Errorf("unexpected title: %s", embed.Title)
 	}
 }
+
+func TestClassifyDiscordError(t *testing.T) {
+	rateLimited := &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{
+		TooManyRequests: &discordgo.TooManyRequests{RetryAfter: 3 * time.Second},
+	}}
+	var retryAfter *notifier.RetryAfterError
+	if err := classifyDiscordError(rateLimited); !errors.As(err, &retryAfter) || retryAfter.RetryAfter != 3*time.Second {
+		t.Errorf("expected retry after 3s, got %v", err)
+	}
+
+	forbidden := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+	var permanent *notifier.PermanentError
+	if err := classifyDiscordError(forbidden); !errors.As(err, &permanent) {
+		t.Errorf("expected permanent error for 403, got %v", err)
+	}
+
+	serverErr := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+	if err := classifyDiscordError(serverErr); errors.As(err, &permanent) || errors.As(err, &retryAfter) {
+		t.Errorf("expected plain retryable error for 502, got %v", err)
+	}
+}
+
+func TestDiscordClient_DeliverNotInitialized(t *testing.T) {
+	client := &DiscordClient{logger: zap.NewNop()}
+
+	err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{})
+	var permanent *notifier.PermanentError
+	if !errors.As(err, &permanent) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	if client.IsEnabled() {
+		t.Error("expected client without session to be disabled")
+	}
+}
+
+func TestBuildDigestEmbed(t *testing.T) {
+	client := &DiscordClient{logger: zap.NewNop()}
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	digest := notifier.AlertDigest{
+		WindowStart:    start,
+		WindowEnd:      start.Add(time.Hour),
+		TotalAlerts:    12,
+		ReasonCounts:   []notifier.DigestReasonCount{{Reason: notifier.AlertReasonNewWallet, Count: 8}},
+		TopWallets:     []notifier.DigestWallet{{Address: "0x1234567890abcdef1234567890abcdef12345678", URL: "https://polymarket.com/profile/0x123", Count: 3, Notional: 4500}},
+		TopMarkets:     []notifier.DigestMarket{{ConditionID: "0xcond", Title: "Will it rain?", URL: "https://polymarket.com/event/rain", Count: 5, Notional: 9000}},
+		LargestTrades:  []notifier.TradeAlert{{Side: "SELL", Notional: 2500, Outcome: "Yes", Price: 0.4, MarketTitle: "Will it rain?"}},
+		AlertsLastHour: 12,
+		AlertsLastDay:  80,
+	}
+
+	embed := client.buildDigestEmbed(digest)
+
+	if !strings.Contains(embed.Description, "**12 alerts** in the last 1h") {
+		t.Errorf("unexpected description: %s", embed.Description)
+	}
+	if len(embed.Fields) != 4 {
+		t.Fatalf("expected 4 fields, got %d", len(embed.Fields))
+	}
+	if !strings.Contains(embed.Fields[1].Value, "[0x1234…345678](https://polymarket.com/profile/0x123) — 3 alerts · $4500") {
+		t.Errorf("unexpected wallets field: %s", embed.Fields[1].Value)
+	}
+	if !strings.Contains(embed.Fields[3].Value, "🔴 **$2500**") {
+		t.Errorf("unexpected largest trades field: %s", embed.Fields[3].Value)
+	}
+}
+
+func TestDigestField_Truncates(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", 50)
+	}
+
+	field := digestField("Test", lines)
+
+	if len(field.Value) > maxFieldLength {
+		t.Errorf("expected field value within %d chars, got %d", maxFieldLength, len(field.Value))
+	}
+}
+
+func TestDeliverThreadedAlert_NoSession(t *testing.T) {
+	client := &DiscordClient{logger: zap.NewNop()}
+
+	_, err := client.DeliverThreadedAlert(context.Background(), notifier.TradeAlert{}, &notifier.MessageRef{MessageID: "1"})
+	var permanent *notifier.PermanentError
+	if !errors.As(err, &permanent) {
+		t.Errorf("expected a permanent error, got %v", err)
+	}
+}
+
+func TestAnnotateEmbed(t *testing.T) {
+	embed := &discordgo.MessageEmbed{
+		Fields: []*discordgo.MessageEmbedField{{Name: "Trader", Value: "alice"}},
+	}
+
+	annotateEmbed(embed, "🏆 Won")
+	annotateEmbed(embed, "💀 Lost") // Replaces rather than appends
+
+	if len(embed.Fields) != 2 {
+		t.Fatalf("expected one outcome field added, got %d fields", len(embed.Fields))
+	}
+	if f := embed.Fields[1]; f.Name != outcomeFieldName || f.Value != "💀 Lost" {
+		t.Errorf("unexpected outcome field: %+v", f)
+	}
+}
+
+func TestBuildMarketAlertEmbed(t *testing.T) {
+	client := &DiscordClient{logger: zap.NewNop()}
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	alert := notifier.MarketAlert{
+		Kind:         notifier.MarketAlertVolumeSpike,
+		MarketTitle:  "Will it rain?",
+		MarketURL:    "https://polymarket.com/event/rain",
+		WindowStart:  start,
+		WindowEnd:    start.Add(5 * time.Minute),
+		Trades:       37,
+		Notional:     84200,
+		BaselineMean: 9100,
+		ZScore:       4.2,
+		TopWallets:   []notifier.MarketAlertWallet{{Address: "0x1234567890abcdef1234567890abcdef12345678", Trades: 9, BuyNotional: 40000, SellNotional: 1200}},
+	}
+
+	embed := client.buildMarketAlertEmbed(alert)
+
+	if embed.Title != "📈 Market Volume Spike" {
+		t.Errorf("unexpected title: %s", embed.Title)
+	}
+	if !strings.Contains(embed.Description, "[Will it rain?](https://polymarket.com/event/rain)") ||
+		!strings.Contains(embed.Description, "4.2σ above the $9100 baseline") {
+		t.Errorf("unexpected description: %s", embed.Description)
+	}
+	if len(embed.Fields) != 1 || !strings.Contains(embed.Fields[0].Value, "0x1234…345678 — 9 trades · 🟢 $40000 / 🔴 $1200") {
+		t.Errorf("unexpected fields: %+v", embed.Fields)
+	}
+}
+
+func TestBuildTradeEmbed_CoordinatedEntry(t *testing.T) {
+	client := &DiscordClient{
+		logger: zap.NewNop(),
+	}
+
+	alert := notifier.TradeAlert{
+		TraderName: "TestTrader",
+		Side:       "BUY",
+		Outcome:    "Yes",
+		Reasons:    []notifier.AlertReason{notifier.AlertReasonLowActivity, notifier.AlertReasonCoordinatedEntry},
+		ClusterWallets: []notifier.ClusterWallet{
+			{Address: "0xaaa", URL: "https://polymarket.com/profile/0xaaa", UniqueMarkets: 1, Shares: 10000, Notional: 4200},
+			{Address: "0xbbb", Name: "bob", Shares: 8000, Notional: 3360},
+		},
+		ClusterShares:   18000,
+		ClusterNotional: 7560,
+		ClusterSpanSecs: 600,
+		HasClusterInfo:  true,
+	}
+
+	embed := client.buildTradeEmbed(alert)
+	if embed.Title != "👥 Coordinated Entry + Low Activity" {
+		t.Errorf("unexpected title: %s", embed.Title)
+	}
+
+	var field *discordgo.MessageEmbedField
+	for _, f := range embed.Fields {
+		if f.Name == "👥 Coordinated Entry" {
+			field = f
+		}
+	}
+	if field == nil {
+		t.Fatal("expected coordinated entry field")
+	}
+	want := "2 fresh wallets bought Yes within 10m: 18000 shares @ $0.420 avg ($7560)\n" +
+		"[0xaaa](https://polymarket.com/profile/0xaaa) — $4200 · 10000 shares · 1 markets\n" +
+		"bob — $3360 · 8000 shares · 0 markets"
+	if field.Value != want {
+		t.Errorf("unexpected field value:\n%s", field.Value)
+	}
+}
+
+func TestBuildTradeEmbed_WalletCluster(t *testing.T) {
+	client := &DiscordClient{
+		logger: zap.NewNop(),
+	}
+
+	alert := notifier.TradeAlert{
+		TraderName:           "TestTrader",
+		Side:                 "BUY",
+		Reasons:              []notifier.AlertReason{notifier.AlertReasonLowActivity},
+		WalletClusterID:      "C-1a2b3c4d",
+		WalletClusterSize:    3,
+		WalletClusterSignals: []string{"co-trading", "funding"},
+	}
+
+	embed := client.buildTradeEmbed(alert)
+	for _, f := range embed.Fields {
+		if f.Name == "🔗 Wallet Cluster" {
+			if f.Value != "C-1a2b3c4d · 3 wallets · co-trading, funding" {
+				t.Errorf("unexpected field value: %s", f.Value)
+			}
+			return
+		}
+	}
+	t.Error("expected wallet cluster field")
+}
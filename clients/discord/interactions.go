@@ -0,0 +1,300 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"polybot/clients/notifier"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// Alert button actions.
+const (
+	ActionMuteWallet    = "mute_wallet"
+	ActionMuteMarket    = "mute_market"
+	ActionFalsePositive = "false_positive"
+	ActionShowHolders   = "show_holders"
+)
+
+const (
+	// commandName is the registered slash command; features are subcommands.
+	commandName = "polybot"
+	// customIDPrefix namespaces button custom IDs.
+	customIDPrefix = "polybot"
+	// maxCustomIDLength is Discord's limit on component custom IDs.
+	maxCustomIDLength = 100
+	// maxMessageLength is Discord's limit on message content.
+	maxMessageLength = 2000
+	// interactionTimeout bounds a command; interaction tokens expire after 15 minutes.
+	interactionTimeout = 10 * time.Minute
+)
+
+// actionCodes shortens button actions so custom IDs fit in 100 characters
+// alongside a condition ID and an alert ID.
+var actionCodes = map[string]string{
+	ActionMuteWallet:    "mw",
+	ActionMuteMarket:    "mm",
+	ActionFalsePositive: "fp",
+	ActionShowHolders:   "ho",
+}
+
+// Interaction is a /polybot slash command or an alert button click.
+type Interaction struct {
+	UserID    string
+	UserName  string
+	ChannelID string
+
+	// Slash commands: the subcommand ("wallet") and its options
+	Command string
+	Options map[string]string
+
+	// Buttons: the action, its wallet or condition ID, and the alert it was attached to
+	Action  string
+	Target  string
+	AlertID string
+}
+
+// IsButton reports whether the interaction is an alert button click.
+func (in Interaction) IsButton() bool {
+	return in.Action != ""
+}
+
+// InteractionHandler runs an interaction and returns the Markdown reply.
+type InteractionHandler func(ctx context.Context, in Interaction) string
+
+// polybotCommand is the /polybot slash command.
+var polybotCommand = &discordgo.ApplicationCommand{
+	Name:        commandName,
+	Description: "Investigate wallets and markets",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "wallet",
+			Description: "Wallet activity",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "address",
+					Description: "Wallet address",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "duration",
+					Description: "Lookback window (default 1m)",
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "1 day", Value: "1d"},
+						{Name: "1 week", Value: "1w"},
+						{Name: "2 weeks", Value: "2w"},
+						{Name: "1 month", Value: "1m"},
+						{Name: "3 months", Value: "3m"},
+						{Name: "6 months", Value: "6m"},
+						{Name: "1 year", Value: "1y"},
+					},
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "holders",
+			Description: "Top holders of a market",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "condition_id",
+					Description: "Market condition ID",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "mute",
+			Description: "Mute alerts for a wallet or market (no target lists active mutes)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "target",
+					Description: "Wallet address or market condition ID",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "duration",
+					Description: "How long to mute, e.g. 6h, 3d or 1w",
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "stats",
+			Description: "Service stats",
+		},
+	},
+}
+
+// StartInteractions connects to the gateway, registers the /polybot command
+// (in guildID, or globally if empty) and answers commands and alert buttons
+// with handler. Alerts sent after this carry buttons.
+func (dc *DiscordClient) StartInteractions(guildID string, handler InteractionHandler) error {
+	if dc.session == nil {
+		return errors.New("discord session not initialized")
+	}
+
+	// Interactions are delivered without any gateway intents
+	dc.session.Identify.Intents = discordgo.IntentsNone
+	dc.removeHandler = dc.session.AddHandler(func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+		dc.handleInteraction(ic.Interaction, handler)
+	})
+
+	if err := dc.session.Open(); err != nil {
+		dc.removeHandler()
+		return fmt.Errorf("open discord gateway: %w", err)
+	}
+
+	if _, err := dc.session.ApplicationCommandCreate(dc.session.State.User.ID, guildID, polybotCommand); err != nil {
+		dc.removeHandler()
+		return fmt.Errorf("register discord command: %w", err)
+	}
+
+	dc.interactive.Store(true)
+	dc.logger.Info("discord interactions enabled", zap.String("guildID", guildID))
+	return nil
+}
+
+// StopInteractions stops answering interactions. The command stays registered.
+func (dc *DiscordClient) StopInteractions() {
+	if dc.removeHandler != nil {
+		dc.removeHandler()
+		dc.removeHandler = nil
+	}
+	dc.interactive.Store(false)
+}
+
+// handleInteraction defers the response, runs handler and edits in its reply.
+func (dc *DiscordClient) handleInteraction(i *discordgo.Interaction, handler InteractionHandler) {
+	in, ok := parseInteraction(i)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), interactionTimeout)
+	defer cancel()
+
+	// Discord requires an answer within 3 seconds; tasks can take minutes
+	err := dc.session.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}, discordgo.WithContext(ctx))
+	if err != nil {
+		dc.logger.Error("failed to acknowledge discord interaction", zap.Error(err))
+		return
+	}
+
+	reply := truncateMessage(handler(ctx, in))
+	if _, err := dc.session.InteractionResponseEdit(i, &discordgo.WebhookEdit{Content: &reply}, discordgo.WithContext(ctx)); err != nil {
+		dc.logger.Error("failed to send discord interaction reply", zap.Error(err))
+	}
+}
+
+// parseInteraction converts a /polybot command or alert button click.
+// Returns false for other interactions.
+func parseInteraction(i *discordgo.Interaction) (Interaction, bool) {
+	in := Interaction{ChannelID: i.ChannelID}
+	user := i.User
+	if i.Member != nil && i.Member.User != nil {
+		user = i.Member.User
+	}
+	if user != nil {
+		in.UserID = user.ID
+		in.UserName = user.Username
+	}
+
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		data := i.ApplicationCommandData()
+		if data.Name != commandName || len(data.Options) == 0 {
+			return Interaction{}, false
+		}
+		sub := data.Options[0]
+		in.Command = sub.Name
+		in.Options = make(map[string]string, len(sub.Options))
+		for _, opt := range sub.Options {
+			in.Options[opt.Name] = strings.TrimSpace(fmt.Sprint(opt.Value))
+		}
+		return in, true
+	case discordgo.InteractionMessageComponent:
+		action, target, alertID, ok := parseButtonCustomID(i.MessageComponentData().CustomID)
+		if !ok {
+			return Interaction{}, false
+		}
+		in.Action, in.Target, in.AlertID = action, target, alertID
+		return in, true
+	}
+	return Interaction{}, false
+}
+
+// buttonCustomID encodes a button as "polybot:<code>:<target>:<alertID>".
+func buttonCustomID(action, target, alertID string) string {
+	return strings.Join([]string{customIDPrefix, actionCodes[action], target, alertID}, ":")
+}
+
+// parseButtonCustomID decodes a custom ID built by buttonCustomID.
+func parseButtonCustomID(customID string) (action, target, alertID string, ok bool) {
+	parts := strings.SplitN(customID, ":", 4)
+	if len(parts) != 4 || parts[0] != customIDPrefix {
+		return "", "", "", false
+	}
+	for a, code := range actionCodes {
+		if code == parts[1] {
+			return a, parts[2], parts[3], true
+		}
+	}
+	return "", "", "", false
+}
+
+// buildAlertButtons returns the action row attached to alert embeds. Buttons
+// whose target is missing from the alert are left out.
+func buildAlertButtons(alert notifier.TradeAlert) []discordgo.MessageComponent {
+	var buttons []discordgo.MessageComponent
+	add := func(label string, style discordgo.ButtonStyle, action, target string) {
+		customID := buttonCustomID(action, target, alert.ID)
+		if len(customID) > maxCustomIDLength {
+			return
+		}
+		buttons = append(buttons, discordgo.Button{Label: label, Style: style, CustomID: customID})
+	}
+
+	if alert.TraderAddress != "" {
+		add("Mute wallet 24h", discordgo.SecondaryButton, ActionMuteWallet, alert.TraderAddress)
+	}
+	if alert.ConditionID != "" {
+		add("Mute market", discordgo.SecondaryButton, ActionMuteMarket, alert.ConditionID)
+	}
+	if alert.ID != "" {
+		add("Mark false positive", discordgo.DangerButton, ActionFalsePositive, "")
+	}
+	if alert.ConditionID != "" {
+		add("Show holders", discordgo.PrimaryButton, ActionShowHolders, alert.ConditionID)
+	}
+
+	if len(buttons) == 0 {
+		return nil
+	}
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// truncateMessage trims content to Discord's message length limit.
+func truncateMessage(s string) string {
+	if len(s) <= maxMessageLength {
+		return s
+	}
+	cut := maxMessageLength - len("…")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
@@ -0,0 +1,135 @@
+package discord
+
+import (
+	"polybot/clients/notifier"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	testWallet = "0x1111111111111111111111111111111111111111"
+	testMarket = "0x3333333333333333333333333333333333333333333333333333333333333333"
+)
+
+func TestButtonCustomID_RoundTrip(t *testing.T) {
+	for _, action := range []string{ActionMuteWallet, ActionMuteMarket, ActionFalsePositive, ActionShowHolders} {
+		id := buttonCustomID(action, testMarket, "0123456789abcdef")
+		if len(id) > maxCustomIDLength {
+			t.Errorf("%s custom ID too long: %d", action, len(id))
+		}
+		gotAction, target, alertID, ok := parseButtonCustomID(id)
+		if !ok || gotAction != action || target != testMarket || alertID != "0123456789abcdef" {
+			t.Errorf("round trip of %q = %q %q %q %v", id, gotAction, target, alertID, ok)
+		}
+	}
+
+	for _, bad := range []string{"", "polybot", "other:mw:x:y", "polybot:zz:x:y"} {
+		if _, _, _, ok := parseButtonCustomID(bad); ok {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestBuildAlertButtons(t *testing.T) {
+	components := buildAlertButtons(notifier.TradeAlert{
+		ID:            "0123456789abcdef",
+		TraderAddress: testWallet,
+		ConditionID:   testMarket,
+	})
+	if len(components) != 1 {
+		t.Fatalf("expected one action row, got %d", len(components))
+	}
+	row := components[0].(discordgo.ActionsRow)
+
+	var labels []string
+	for _, c := range row.Components {
+		labels = append(labels, c.(discordgo.Button).Label)
+	}
+	want := "Mute wallet 24h,Mute market,Mark false positive,Show holders"
+	if strings.Join(labels, ",") != want {
+		t.Errorf("unexpected buttons: %v", labels)
+	}
+
+	// Buttons without a target are left out
+	if got := buildAlertButtons(notifier.TradeAlert{}); got != nil {
+		t.Errorf("expected no buttons for an empty alert, got %+v", got)
+	}
+}
+
+func TestParseInteraction_SlashCommand(t *testing.T) {
+	i := &discordgo.Interaction{
+		Type:      discordgo.InteractionApplicationCommand,
+		ChannelID: "chan",
+		Member:    &discordgo.Member{User: &discordgo.User{ID: "1001", Username: "alice"}},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: commandName,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+				Name: "wallet",
+				Type: discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "address", Type: discordgo.ApplicationCommandOptionString, Value: testWallet},
+					{Name: "duration", Type: discordgo.ApplicationCommandOptionString, Value: "1w"},
+				},
+			}},
+		},
+	}
+
+	in, ok := parseInteraction(i)
+	if !ok || in.IsButton() {
+		t.Fatalf("expected a slash command, got %+v", in)
+	}
+	if in.Command != "wallet" || in.Options["address"] != testWallet || in.Options["duration"] != "1w" {
+		t.Errorf("unexpected command: %+v", in)
+	}
+	if in.UserID != "1001" || in.UserName != "alice" || in.ChannelID != "chan" {
+		t.Errorf("unexpected user: %+v", in)
+	}
+}
+
+func TestParseInteraction_Button(t *testing.T) {
+	i := &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		User: &discordgo.User{ID: "1001", Username: "alice"},
+		Data: discordgo.MessageComponentInteractionData{
+			CustomID: buttonCustomID(ActionMuteWallet, testWallet, "0123456789abcdef"),
+		},
+	}
+
+	in, ok := parseInteraction(i)
+	if !ok || !in.IsButton() {
+		t.Fatalf("expected a button, got %+v", in)
+	}
+	if in.Action != ActionMuteWallet || in.Target != testWallet || in.AlertID != "0123456789abcdef" {
+		t.Errorf("unexpected button: %+v", in)
+	}
+
+	i.Data = discordgo.MessageComponentInteractionData{CustomID: "someone-else"}
+	if _, ok := parseInteraction(i); ok {
+		t.Error("expected foreign buttons to be ignored")
+	}
+}
+
+func TestWithChannel_SharesInteractiveState(t *testing.T) {
+	client := &DiscordClient{logger: zap.NewNop(), interactive: new(atomic.Bool)}
+	routed := client.WithChannel("other")
+
+	client.interactive.Store(true)
+	if !routed.interactive.Load() {
+		t.Error("expected routed clients to attach buttons once interactions start")
+	}
+}
+
+func TestTruncateMessage(t *testing.T) {
+	long := strings.Repeat("é", maxMessageLength)
+	got := truncateMessage(long)
+	if len(got) > maxMessageLength || !strings.HasSuffix(got, "…") {
+		t.Errorf("unexpected truncation to %d bytes", len(got))
+	}
+	if truncateMessage("short") != "short" {
+		t.Error("expected short messages unchanged")
+	}
+}
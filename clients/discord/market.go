@@ -0,0 +1,79 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"polybot/clients/notifier"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// SendMarketAlert sends a market anomaly embed.
+// Implements notifier.MarketAlertNotifier interface.
+func (dc *DiscordClient) SendMarketAlert(alert notifier.MarketAlert) {
+	if dc.session == nil {
+		dc.logger.Warn("discord session not initialized, skipping market alert")
+		return
+	}
+
+	embed := dc.buildMarketAlertEmbed(alert)
+	if _, err := dc.session.ChannelMessageSendEmbed(dc.channelID, embed, discordgo.WithContext(context.Background())); err != nil {
+		dc.logger.Error("failed to send discord market alert", zap.Error(err))
+		return
+	}
+
+	dc.logger.Info("sent discord market alert",
+		zap.String("kind", string(alert.Kind)),
+		zap.String("market", alert.MarketTitle),
+	)
+}
+
+func (dc *DiscordClient) buildMarketAlertEmbed(alert notifier.MarketAlert) *discordgo.MessageEmbed {
+	title := alert.MarketTitle
+	if alert.MarketURL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, alert.MarketURL)
+	}
+	description := fmt.Sprintf("**%s**\n%s", title, alert.Summary())
+
+	var fields []*discordgo.MessageEmbedField
+	if len(alert.TopWallets) > 0 {
+		var lines []string
+		for _, w := range alert.TopWallets {
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			if w.URL != "" {
+				name = fmt.Sprintf("[%s](%s)", name, w.URL)
+			}
+			lines = append(lines, fmt.Sprintf("%s — %d trades · 🟢 $%.0f / 🔴 $%.0f", name, w.Trades, w.BuyNotional, w.SellNotional))
+		}
+		fields = append(fields, digestField("👛 Top Wallets", lines))
+	}
+
+	// Format timestamp for footer (PST)
+	pst, _ := time.LoadLocation("America/Los_Angeles")
+	ts := alert.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       alert.Title(),
+		Description: description,
+		Color:       0x9B59B6,
+		Fields:      fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("polybot market * %s", ts.In(pst).Format("1/2/2006, 3:04:05PM (MST)")),
+		},
+		Timestamp: ts.Format(time.RFC3339),
+	}
+	if alert.MarketImage != "" {
+		embed.Image = &discordgo.MessageEmbedImage{
+			URL: alert.MarketImage,
+		}
+	}
+	return embed
+}
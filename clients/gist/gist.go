@@ -3,11 +3,15 @@ package gist
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"polybot/clients/storage"
 	"polybot/config"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -17,32 +21,64 @@ const (
 	apiBaseURL = "https://api.github.com"
 )
 
-// Storage is the interface for gist storage operations.
-// This allows for easy mocking in tests.
-type Storage interface {
-	IsEnabled() bool
-	Load(ctx context.Context, filename string, gistID ...string) (string, error)
-	Save(ctx context.Context, filename, content string, gistID ...string) error
-	LoadJSON(ctx context.Context, filename string, dest any) error
-	SaveJSON(ctx context.Context, filename string, data any) error
-	GetGistID() string
-}
+// Storage is the persistence interface shared by all backends. For the gist
+// backend a namespace is a gist ID.
+type Storage = storage.Storage
 
-// Ensure Client implements Storage interface
-var _ Storage = (*Client)(nil)
+// Ensure Client implements Storage, Source, Merger and ConflictReporter interfaces
+var (
+	_ Storage                  = (*Client)(nil)
+	_ storage.Source           = (*Client)(nil)
+	_ storage.Merger           = (*Client)(nil)
+	_ storage.ConflictReporter = (*Client)(nil)
+)
 
 // Client is a GitHub Gist API client for storing JSON data.
+//
+// Saves to an existing gist are versioned: the client remembers the revision
+// and content hash of every file it loads or saves, and before writing checks
+// the gist's current revision. If the file changed since, the save is merged
+// with the stored content when a merger is registered for the file, and
+// refused with storage.ErrConflict otherwise, until the file is loaded again.
+// The API has no conditional PATCH, so a write landing between the check and
+// the update still wins.
+//
+// Fetched gists are cached and revalidated with their ETag, so checking an
+// unchanged gist before a save doesn't download it again or count against
+// the rate limit.
 type Client struct {
 	logger     *zap.Logger
 	httpClient *http.Client
 	token      string
 	gistID     string // If set, updates this gist; otherwise creates new ones
+
+	saveMu  sync.Mutex // Serializes check-and-write so saves don't conflict with each other
+	stateMu sync.Mutex
+	files   map[string]fileState  // "gistID/filename" -> state last loaded or saved
+	gists   map[string]cachedGist // gist ID -> gist last fetched
+
+	storage.ConflictLog
+}
+
+// cachedGist is a fetched gist and its ETag.
+type cachedGist struct {
+	etag string
+	gist *Gist
+}
+
+// fileState is what the client last saw of a gist file.
+type fileState struct {
+	revision string // Gist revision the file was seen at
+	hash     string // SHA-256 of the content; "" if the file didn't exist
+	base     string // Content, kept only for files with a merger
 }
 
 // GistFile represents a file in a gist.
 type GistFile struct {
-	Filename string `json:"filename,omitempty"`
-	Content  string `json:"content"`
+	Filename  string `json:"filename,omitempty"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated,omitempty"` // Content cut off; full file at RawURL
+	RawURL    string `json:"raw_url,omitempty"`
 }
 
 // Gist represents a GitHub gist.
@@ -53,6 +89,25 @@ type Gist struct {
 	Files       map[string]GistFile `json:"files"`
 	CreatedAt   time.Time           `json:"created_at"`
 	UpdatedAt   time.Time           `json:"updated_at"`
+	History     []GistHistory       `json:"history,omitempty"`
+}
+
+// GistHistory is a revision of a gist, newest first in Gist.History.
+type GistHistory struct {
+	Version     string    `json:"version"`
+	CommittedAt time.Time `json:"committed_at"`
+}
+
+// revision identifies the gist's current version: the latest history entry,
+// falling back to the update time.
+func (g *Gist) revision() string {
+	if len(g.History) > 0 && g.History[0].Version != "" {
+		return g.History[0].Version
+	}
+	if !g.UpdatedAt.IsZero() {
+		return g.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return ""
 }
 
 // createGistRequest is the request body for creating/updating a gist.
@@ -105,6 +160,8 @@ func (c *Client) SaveJSON(ctx context.Context, filename string, data any) error
 
 // Save saves content to a gist file.
 // If gistID is provided (non-empty), it overrides the client's default gist ID.
+// Saving over a file changed since it was last loaded merges or returns
+// storage.ErrConflict; see Client.
 func (c *Client) Save(ctx context.Context, filename, content string, gistID ...string) error {
 	if !c.IsEnabled() {
 		return fmt.Errorf("gist client not configured")
@@ -115,6 +172,17 @@ func (c *Client) Save(ctx context.Context, filename, content string, gistID ...s
 		targetGistID = gistID[0]
 	}
 
+	if targetGistID != "" {
+		c.saveMu.Lock()
+		defer c.saveMu.Unlock()
+
+		resolved, err := c.checkConflict(ctx, targetGistID, filename, content)
+		if err != nil {
+			return err
+		}
+		content = resolved
+	}
+
 	reqBody := gistRequest{
 		Description: "polybot cache",
 		Public:      false,
@@ -159,15 +227,22 @@ func (c *Client) Save(ctx context.Context, filename, content string, gistID ...s
 		return fmt.Errorf("api error status=%d body=%s", resp.StatusCode, string(body))
 	}
 
+	var gist Gist
+	decodeErr := json.NewDecoder(resp.Body).Decode(&gist)
+
 	// If we created a new gist, save its ID for future updates
 	if c.gistID == "" {
-		var gist Gist
-		if err := json.NewDecoder(resp.Body).Decode(&gist); err != nil {
-			return fmt.Errorf("decode response: %w", err)
+		if decodeErr != nil {
+			return fmt.Errorf("decode response: %w", decodeErr)
 		}
 		c.gistID = gist.ID
 		c.logger.Info("created new gist", zap.String("id", gist.ID))
 	}
+	if targetGistID == "" {
+		targetGistID = gist.ID
+	}
+	// Without a revision the next save falls back to comparing content
+	c.recordFile(targetGistID, filename, gist.revision(), content, true)
 
 	c.logger.Debug("saved to gist",
 		zap.String("filename", filename),
@@ -211,47 +286,238 @@ func (c *Client) Load(ctx context.Context, filename string, gistID ...string) (s
 		return "", fmt.Errorf("no gist ID configured")
 	}
 
-	url := fmt.Sprintf("%s/gists/%s", apiBaseURL, targetGistID)
+	gist, err := c.fetchGist(ctx, targetGistID)
+	if err != nil {
+		return "", err
+	}
+
+	file, ok := gist.Files[filename]
+	if !ok {
+		c.recordFile(targetGistID, filename, gist.revision(), "", false)
+		return "", fmt.Errorf("file %q not found in gist: %w", filename, storage.ErrNotFound)
+	}
+
+	content, err := c.fileContent(ctx, file)
+	if err != nil {
+		return "", err
+	}
+	c.recordFile(targetGistID, filename, gist.revision(), content, true)
+
+	c.logger.Debug("loaded from gist",
+		zap.String("filename", filename),
+		zap.Int("bytes", len(content)),
+	)
+
+	return content, nil
+}
+
+// Files returns every file in a gist.
+func (c *Client) Files(ctx context.Context, gistID string) (map[string]string, error) {
+	if !c.IsEnabled() {
+		return nil, fmt.Errorf("gist client not configured")
+	}
+	if gistID == "" {
+		return nil, fmt.Errorf("no gist ID configured")
+	}
+
+	gist, err := c.fetchGist(ctx, gistID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string, len(gist.Files))
+	for name, file := range gist.Files {
+		content, err := c.fileContent(ctx, file)
+		if err != nil {
+			return nil, fmt.Errorf("file %q: %w", name, err)
+		}
+		files[name] = content
+	}
+	return files, nil
+}
+
+// checkConflict compares a gist file with the state this client last loaded
+// or saved, and returns the content to write. The revision is checked first;
+// if the gist moved on, possibly through changes to other files, the file's
+// content decides. A changed file is merged if a merger is registered for it
+// and refused otherwise.
+func (c *Client) checkConflict(ctx context.Context, gistID, filename, content string) (string, error) {
+	current, err := c.fetchGist(ctx, gistID)
+	if err != nil {
+		return "", fmt.Errorf("check revision: %w", err)
+	}
+
+	known, seen := c.fileState(gistID, filename)
+	if seen && known.revision != "" && known.revision == current.revision() {
+		return content, nil
+	}
+
+	file, exists := current.Files[filename]
+	if !exists {
+		return content, nil
+	}
+	stored, err := c.fileContent(ctx, file)
+	if err != nil {
+		return "", fmt.Errorf("check revision: %w", err)
+	}
+	// Files never seen only count as changed if they hold something else
+	if stored == content || (seen && contentHash(stored) == known.hash) {
+		return content, nil
+	}
+
+	conflict := storage.Conflict{Namespace: gistID, Filename: filename, Resolution: storage.ConflictRefused}
+	if merge := c.MergerFor(filename); merge != nil {
+		merged, err := merge(known.base, stored, content)
+		if err == nil {
+			conflict.Resolution = storage.ConflictMerged
+			c.Record(conflict)
+			c.logger.Warn("merged concurrent change to gist file",
+				zap.String("gistID", gistID),
+				zap.String("filename", filename),
+			)
+			return merged, nil
+		}
+		c.logger.Warn("failed to merge gist file",
+			zap.String("gistID", gistID),
+			zap.String("filename", filename),
+			zap.Error(err),
+		)
+	}
+
+	c.Record(conflict)
+	c.logger.Warn("refusing to overwrite gist file changed since last load",
+		zap.String("gistID", gistID),
+		zap.String("filename", filename),
+		zap.String("knownRevision", known.revision),
+		zap.String("currentRevision", current.revision()),
+	)
+	return "", fmt.Errorf("gist file %q: %w", filename, storage.ErrConflict)
+}
+
+// fileState returns the state a file was last loaded or saved in.
+func (c *Client) fileState(gistID, filename string) (fileState, bool) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	state, ok := c.files[gistID+"/"+filename]
+	return state, ok
+}
+
+// recordFile remembers the state a file was loaded or saved in.
+func (c *Client) recordFile(gistID, filename, revision, content string, exists bool) {
+	state := fileState{revision: revision}
+	if exists {
+		state.hash = contentHash(content)
+		if c.MergerFor(filename) != nil {
+			state.base = content
+		}
+	}
+
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	if c.files == nil {
+		c.files = make(map[string]fileState)
+	}
+	c.files[gistID+"/"+filename] = state
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// fetchGist gets a gist with its files. A cached copy is reused if the API
+// reports it unchanged.
+func (c *Client) fetchGist(ctx context.Context, gistID string) (*Gist, error) {
+	url := fmt.Sprintf("%s/gists/%s", apiBaseURL, gistID)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	cached, ok := c.cachedGist(gistID)
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("send request: %w", err)
+		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && ok {
+		return cached.gist, nil
+	}
 	if resp.StatusCode == http.StatusNotFound {
-		return "", fmt.Errorf("gist not found")
+		return nil, fmt.Errorf("gist not found")
 	}
 
 	if resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("api error status=%d body=%s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("api error status=%d body=%s", resp.StatusCode, string(body))
 	}
 
 	var gist Gist
 	if err := json.NewDecoder(resp.Body).Decode(&gist); err != nil {
-		return "", fmt.Errorf("decode response: %w", err)
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.cacheGist(gistID, cachedGist{etag: etag, gist: &gist})
 	}
+	return &gist, nil
+}
 
-	file, ok := gist.Files[filename]
-	if !ok {
-		return "", fmt.Errorf("file %q not found in gist", filename)
+func (c *Client) cachedGist(gistID string) (cachedGist, bool) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	cached, ok := c.gists[gistID]
+	return cached, ok
+}
+
+func (c *Client) cacheGist(gistID string, cached cachedGist) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	if c.gists == nil {
+		c.gists = make(map[string]cachedGist)
 	}
+	c.gists[gistID] = cached
+}
 
-	c.logger.Debug("loaded from gist",
-		zap.String("filename", filename),
-		zap.Int("bytes", len(file.Content)),
-	)
+// fileContent returns a file's full content. The API cuts off files over
+// 1 MB in gist responses; those are downloaded from their raw URL.
+func (c *Client) fileContent(ctx context.Context, file GistFile) (string, error) {
+	if !file.Truncated {
+		return file.Content, nil
+	}
+	if file.RawURL == "" {
+		return "", fmt.Errorf("truncated file has no raw URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.RawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("create raw request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send raw request: %w", err)
+	}
+	defer resp.Body.Close()
 
-	return file.Content, nil
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("raw api error status=%d body=%s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read raw content: %w", err)
+	}
+	return string(body), nil
 }
 
 // GetGistID returns the current gist ID.
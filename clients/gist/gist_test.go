@@ -3,9 +3,13 @@ package gist
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"polybot/clients/storage"
 	"polybot/config"
+	"sync"
 	"testing"
 	"time"
 
@@ -99,6 +103,11 @@ func TestSave_Disabled(t *testing.T) {
 
 func TestSave_UpdateExisting(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The current revision is checked before updating
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(Gist{ID: "existing-id"})
+			return
+		}
 		if r.Method != http.MethodPatch {
 			t.Errorf("expected PATCH, got %s", r.Method)
 		}
@@ -277,6 +286,9 @@ func TestLoad_FileNotFound(t *testing.T) {
 	if err == nil {
 		t.Error("expected error for nonexistent file")
 	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
 }
 
 func TestLoad_GistNotFound(t *testing.T) {
@@ -304,8 +316,53 @@ func TestLoad_GistNotFound(t *testing.T) {
 	}
 }
 
+func TestFiles_Truncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/raw/big.json" {
+			w.Write([]byte(`{"big": true}`))
+			return
+		}
+		gist := Gist{
+			ID: "test-gist-id",
+			Files: map[string]GistFile{
+				"small.json": {Content: `{"small": true}`},
+				"big.json":   {Content: `{"bi`, Truncated: true, RawURL: "https://gist.githubusercontent.com/raw/big.json"},
+			},
+		}
+		json.NewEncoder(w).Encode(gist)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		logger: zap.NewNop(),
+		token:  "test-token",
+		gistID: "test-gist-id",
+		httpClient: &http.Client{
+			Transport: &testTransport{baseURL: server.URL},
+		},
+	}
+
+	files, err := client.Files(context.Background(), "test-gist-id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 2 || files["small.json"] != `{"small": true}` || files["big.json"] != `{"big": true}` {
+		t.Errorf("unexpected files: %v", files)
+	}
+
+	// Load downloads truncated files too
+	content, err := client.Load(context.Background(), "big.json")
+	if err != nil || content != `{"big": true}` {
+		t.Errorf("unexpected load: %q, %v", content, err)
+	}
+}
+
 func TestSaveJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(Gist{ID: "test-id"})
+			return
+		}
 		var req gistRequest
 		json.NewDecoder(r.Body).Decode(&req)
 
@@ -573,3 +630,203 @@ func TestLoadJSON_LoadError(t *testing.T) {
 		t.Error("expected error when load fails")
 	}
 }
+
+// fakeGist serves one gist, bumping its revision on every update. Reads
+// are revalidated with the revision as ETag.
+type fakeGist struct {
+	mu        sync.Mutex
+	files     map[string]string
+	version   int
+	downloads int // Reads answered with the full gist
+}
+
+func (f *fakeGist) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Method == http.MethodPatch {
+		var req gistRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		for name, file := range req.Files {
+			f.files[name] = file.Content
+		}
+		f.version++
+	}
+	etag := fmt.Sprintf(`"v%d"`, f.version)
+	if r.Method == http.MethodGet {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		f.downloads++
+	}
+	gist := Gist{ID: "shared", Files: map[string]GistFile{}, History: []GistHistory{{Version: fmt.Sprintf("v%d", f.version)}}}
+	for name, content := range f.files {
+		gist.Files[name] = GistFile{Filename: name, Content: content}
+	}
+	json.NewEncoder(w).Encode(gist)
+}
+
+// edit changes a file as another writer would.
+func (f *fakeGist) edit(name, content string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[name] = content
+	f.version++
+}
+
+func newFakeGistClient(server *httptest.Server) *Client {
+	return &Client{
+		logger: zap.NewNop(),
+		token:  "test-token",
+		gistID: "shared",
+		httpClient: &http.Client{
+			Transport: &testTransport{baseURL: server.URL},
+		},
+	}
+}
+
+func TestSave_Conflict(t *testing.T) {
+	fake := &fakeGist{files: map[string]string{"settings.json": "v1", "wallet_cache.json": "cache"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := newFakeGistClient(server)
+	ctx := context.Background()
+
+	if _, err := client.Load(ctx, "settings.json"); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if err := client.Save(ctx, "settings.json", "mine"); err != nil {
+		t.Fatalf("save after load failed: %v", err)
+	}
+
+	// Changes to other files move the revision but don't conflict
+	fake.edit("wallet_cache.json", "other instance")
+	if err := client.Save(ctx, "settings.json", "mine again"); err != nil {
+		t.Fatalf("save after unrelated change failed: %v", err)
+	}
+
+	fake.edit("settings.json", "edited by hand")
+	err := client.Save(ctx, "settings.json", "stale")
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if fake.files["settings.json"] != "edited by hand" {
+		t.Errorf("expected the hand edit kept, got %q", fake.files["settings.json"])
+	}
+
+	// Files never loaded can't be overwritten either
+	if err := client.Save(ctx, "wallet_cache.json", "blind"); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("expected ErrConflict for unseen file, got %v", err)
+	}
+
+	conflicts := client.Conflicts()
+	if len(conflicts) != 2 || conflicts[0].Filename != "wallet_cache.json" || conflicts[1].Filename != "settings.json" {
+		t.Fatalf("unexpected conflicts: %+v", conflicts)
+	}
+	if conflicts[1].Namespace != "shared" || conflicts[1].Resolution != storage.ConflictRefused {
+		t.Errorf("unexpected conflict: %+v", conflicts[1])
+	}
+
+	// Reloading picks up the edit and allows saving again
+	if content, _ := client.Load(ctx, "settings.json"); content != "edited by hand" {
+		t.Errorf("unexpected reload: %q", content)
+	}
+	if err := client.Save(ctx, "settings.json", "after reload"); err != nil {
+		t.Errorf("save after reload failed: %v", err)
+	}
+}
+
+func TestSave_ConflictMerged(t *testing.T) {
+	fake := &fakeGist{files: map[string]string{"passkeys.json": "a"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := newFakeGistClient(server)
+	ctx := context.Background()
+
+	var gotBase, gotStored, gotLocal string
+	client.SetMerger("passkeys.json", func(base, stored, local string) (string, error) {
+		gotBase, gotStored, gotLocal = base, stored, local
+		return stored + "+" + local, nil
+	})
+
+	if _, err := client.Load(ctx, "passkeys.json"); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	fake.edit("passkeys.json", "a,b")
+	if err := client.Save(ctx, "passkeys.json", "a,c"); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	if gotBase != "a" || gotStored != "a,b" || gotLocal != "a,c" {
+		t.Errorf("unexpected merge inputs: %q, %q, %q", gotBase, gotStored, gotLocal)
+	}
+	if fake.files["passkeys.json"] != "a,b+a,c" {
+		t.Errorf("expected merged content saved, got %q", fake.files["passkeys.json"])
+	}
+	if conflicts := client.Conflicts(); len(conflicts) != 1 || conflicts[0].Resolution != storage.ConflictMerged {
+		t.Errorf("unexpected conflicts: %+v", conflicts)
+	}
+
+	// The merged content is the new base
+	fake.edit("passkeys.json", "d")
+	client.Save(ctx, "passkeys.json", "e")
+	if gotBase != "a,b+a,c" {
+		t.Errorf("expected merged content as base, got %q", gotBase)
+	}
+}
+
+func TestSave_ConflictRefusedUntilLoad(t *testing.T) {
+	fake := &fakeGist{files: map[string]string{"settings.json": "v1"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := newFakeGistClient(server)
+	ctx := context.Background()
+
+	if _, err := client.Load(ctx, "settings.json"); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	fake.edit("settings.json", "edited by hand")
+
+	// Every save is refused, not just the first, so the edit is never lost
+	for i := 0; i < 2; i++ {
+		if err := client.Save(ctx, "settings.json", "mine"); !errors.Is(err, storage.ErrConflict) {
+			t.Fatalf("save %d: expected ErrConflict, got %v", i+1, err)
+		}
+	}
+	if fake.files["settings.json"] != "edited by hand" {
+		t.Errorf("expected the edit kept, got %q", fake.files["settings.json"])
+	}
+
+	// Loading picks up the edit and allows saving again
+	if _, err := client.Load(ctx, "settings.json"); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if err := client.Save(ctx, "settings.json", "mine"); err != nil {
+		t.Errorf("save after load failed: %v", err)
+	}
+}
+
+func TestSave_ReusesUnchangedGist(t *testing.T) {
+	fake := &fakeGist{files: map[string]string{"settings.json": "v1"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := newFakeGistClient(server)
+	ctx := context.Background()
+
+	if _, err := client.Load(ctx, "settings.json"); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if err := client.Save(ctx, "settings.json", "mine"); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if fake.downloads != 1 {
+		t.Errorf("expected the check before saving to reuse the loaded gist, got %d downloads", fake.downloads)
+	}
+
+	// A changed gist is downloaded again
+	fake.edit("settings.json", "edited by hand")
+	if content, _ := client.Load(ctx, "settings.json"); content != "edited by hand" || fake.downloads != 2 {
+		t.Errorf("unexpected reload: %q after %d downloads", content, fake.downloads)
+	}
+}
@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// minBookMove is the smallest mid price change reported as moving the market.
+const minBookMove = 0.005
+
+// FormatCents formats a 0-1 price in cents, e.g. "12¢" or "12.5¢".
+func FormatCents(price float64) string {
+	cents := price * 100
+	if math.Abs(cents-math.Round(cents)) < 0.05 {
+		return fmt.Sprintf("%.0f¢", cents)
+	}
+	return fmt.Sprintf("%.1f¢", cents)
+}
+
+// MovedMarket reports whether the book's mid price moved across the trade.
+func (a TradeAlert) MovedMarket() bool {
+	return a.HasBookInfo && a.BookPriceBefore > 0 && a.BookPriceAfter > 0 &&
+		math.Abs(a.BookPriceAfter-a.BookPriceBefore) >= minBookMove
+}
+
+// BookSummary describes the order book around the trade on one line, e.g.
+// "Moved the market from 12¢ to 19¢ · 18¢ bid / 20¢ ask · $12400 / $8100 depth".
+// Returns "" if the alert has no book info.
+func (a TradeAlert) BookSummary() string {
+	if !a.HasBookInfo {
+		return ""
+	}
+
+	var parts []string
+	if a.MovedMarket() {
+		parts = append(parts, fmt.Sprintf("Moved the market from %s to %s",
+			FormatCents(a.BookPriceBefore), FormatCents(a.BookPriceAfter)))
+	}
+
+	bid, ask := "no bids", "no asks"
+	if a.BookBestBid > 0 {
+		bid = FormatCents(a.BookBestBid) + " bid"
+	}
+	if a.BookBestAsk > 0 {
+		ask = FormatCents(a.BookBestAsk) + " ask"
+	}
+	parts = append(parts, bid+" / "+ask)
+	parts = append(parts, fmt.Sprintf("$%.0f / $%.0f depth", a.BookBidDepth, a.BookAskDepth))
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,39 @@
+package notifier
+
+import "testing"
+
+func TestFormatCents(t *testing.T) {
+	tests := map[float64]string{0.12: "12¢", 0.125: "12.5¢", 0.5: "50¢", 0.001: "0.1¢"}
+	for price, want := range tests {
+		if got := FormatCents(price); got != want {
+			t.Errorf("FormatCents(%v) = %q, want %q", price, got, want)
+		}
+	}
+}
+
+func TestTradeAlert_BookSummary(t *testing.T) {
+	if got := (TradeAlert{}).BookSummary(); got != "" {
+		t.Errorf("expected no summary without book info, got %q", got)
+	}
+
+	alert := TradeAlert{
+		HasBookInfo:     true,
+		BookPriceBefore: 0.12,
+		BookPriceAfter:  0.19,
+		BookBestBid:     0.18,
+		BookBestAsk:     0.20,
+		BookBidDepth:    12400,
+		BookAskDepth:    8100,
+	}
+	want := "Moved the market from 12¢ to 19¢ · 18¢ bid / 20¢ ask · $12400 / $8100 depth"
+	if got := alert.BookSummary(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	alert.BookPriceBefore = 0.188
+	alert.BookBestAsk = 0
+	want = "18¢ bid / no asks · $12400 / $8100 depth"
+	if got := alert.BookSummary(); got != want {
+		t.Errorf("expected no move under half a cent, got %q", got)
+	}
+}
@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClusterWallet is one wallet of a coordinated entry.
+type ClusterWallet struct {
+	Address       string
+	Name          string
+	URL           string
+	UniqueMarkets int     // Markets the wallet had traded when it joined
+	Trades        int     // Buys in the cluster
+	Shares        float64 // Shares bought
+	Notional      float64 // USD bought
+}
+
+// ClusterAvgPrice returns the cluster's combined average price, or 0 if unknown.
+func (a TradeAlert) ClusterAvgPrice() float64 {
+	if a.ClusterShares <= 0 {
+		return 0
+	}
+	return a.ClusterNotional / a.ClusterShares
+}
+
+// ClusterSummary describes a coordinated entry on one line,
+// e.g. "6 fresh wallets bought Yes within 25m: 41200 shares @ $0.420 avg ($17304)".
+// Returns "" if the alert has no coordinated entry info.
+func (a TradeAlert) ClusterSummary() string {
+	if !a.HasClusterInfo {
+		return ""
+	}
+
+	span := time.Duration(a.ClusterSpanSecs * float64(time.Second))
+	if span < time.Minute {
+		span = time.Minute
+	}
+	window := AlertDigest{WindowEnd: time.Time{}.Add(span)}.WindowLabel()
+	return fmt.Sprintf("%d fresh wallets bought %s within %s: %.0f shares @ $%.3f avg ($%.0f)",
+		len(a.ClusterWallets), a.Outcome, window, a.ClusterShares, a.ClusterAvgPrice(), a.ClusterNotional)
+}
@@ -0,0 +1,28 @@
+package notifier
+
+import "testing"
+
+func TestTradeAlert_ClusterSummary(t *testing.T) {
+	alert := TradeAlert{
+		Outcome:         "Yes",
+		ClusterWallets:  make([]ClusterWallet, 6),
+		ClusterShares:   41200,
+		ClusterNotional: 17304,
+		ClusterSpanSecs: 1490,
+		HasClusterInfo:  true,
+	}
+	want := "6 fresh wallets bought Yes within 25m: 41200 shares @ $0.420 avg ($17304)"
+	if got := alert.ClusterSummary(); got != want {
+		t.Errorf("ClusterSummary() = %q, want %q", got, want)
+	}
+
+	// Buys within the same minute still read as a window
+	alert.ClusterSpanSecs = 0
+	if got := alert.ClusterSummary(); got != "6 fresh wallets bought Yes within 1m: 41200 shares @ $0.420 avg ($17304)" {
+		t.Errorf("unexpected summary for a short span: %q", got)
+	}
+
+	if got := (TradeAlert{}).ClusterSummary(); got != "" {
+		t.Errorf("expected empty summary without cluster info, got %q", got)
+	}
+}
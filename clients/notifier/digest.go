@@ -0,0 +1,86 @@
+package notifier
+
+import (
+	"strings"
+	"time"
+)
+
+// AlertDigest summarizes the alerts batched over a digest window.
+type AlertDigest struct {
+	WindowStart time.Time
+	WindowEnd   time.Time
+	TotalAlerts int
+
+	ReasonCounts  []DigestReasonCount // Sorted by count descending
+	TopWallets    []DigestWallet      // Wallets with the most alerts in the window
+	TopMarkets    []DigestMarket      // Markets with the most alerts in the window
+	LargestTrades []TradeAlert        // Alerts with the largest notional in the window
+
+	// Rolling totals from the trade monitor, for context
+	AlertsLastHour int
+	AlertsLastDay  int
+	AlertsLastWeek int
+}
+
+// WindowLabel formats the digest window for display (e.g. "1h", "30m", "1h30m").
+func (d AlertDigest) WindowLabel() string {
+	label := d.WindowEnd.Sub(d.WindowStart).Round(time.Minute).String()
+	label = strings.TrimSuffix(label, "0s")
+	if strings.HasSuffix(label, "h0m") {
+		label = strings.TrimSuffix(label, "0m")
+	}
+	return label
+}
+
+// DigestReasonCount is the number of alerts with a reason in a digest window.
+type DigestReasonCount struct {
+	Reason AlertReason
+	Count  int
+}
+
+// DigestWallet is a wallet's alert activity in a digest window.
+type DigestWallet struct {
+	Address  string
+	Name     string
+	URL      string
+	Count    int
+	Notional float64
+}
+
+// DigestMarket is a market's alert activity in a digest window.
+type DigestMarket struct {
+	ConditionID string
+	Title       string
+	URL         string
+	Count       int
+	Notional    float64
+}
+
+// DigestNotifier is implemented by notifiers that can deliver alert digests.
+type DigestNotifier interface {
+	SendDigest(digest AlertDigest)
+}
+
+// SendDigest sends the digest to every notifier that supports digests.
+func (m *MultiNotifier) SendDigest(digest AlertDigest) {
+	for _, n := range m.notifiers {
+		if d, ok := n.(DigestNotifier); ok {
+			d.SendDigest(digest)
+		}
+	}
+}
+
+// SendDigest forwards the digest. Digests are not filtered.
+func (f *FilteredNotifier) SendDigest(digest AlertDigest) {
+	if d, ok := f.notifier.(DigestNotifier); ok {
+		d.SendDigest(digest)
+	}
+}
+
+// SendDigest sends the digest directly through the wrapped channel. Digests
+// are infrequent, so they bypass the alert queue.
+func (q *QueuedNotifier) SendDigest(digest AlertDigest) {
+	if d, ok := q.inner.(DigestNotifier); ok {
+		d.SendDigest(digest)
+	}
+}
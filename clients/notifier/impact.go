@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImpactSummary describes the trade's size relative to its market on one line,
+// e.g. "18% of 24h volume · 65% of ask depth · moved price +12%".
+// Returns "" if the alert has no market impact info.
+func (a TradeAlert) ImpactSummary() string {
+	if !a.HasImpactInfo {
+		return ""
+	}
+
+	var parts []string
+	if a.ImpactVolumePct > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f%% of 24h volume", a.ImpactVolumePct*100))
+	}
+	if a.ImpactDepthPct > 0 {
+		side := "ask"
+		if a.Side == "SELL" {
+			side = "bid"
+		}
+		parts = append(parts, fmt.Sprintf("%.0f%% of %s depth", a.ImpactDepthPct*100, side))
+	}
+	if a.ImpactPriceMove > 0 {
+		parts = append(parts, fmt.Sprintf("moved price %+.0f%%", a.ImpactPriceMove*100))
+	}
+	return strings.Join(parts, " · ")
+}
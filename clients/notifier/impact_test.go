@@ -0,0 +1,26 @@
+package notifier
+
+import "testing"
+
+func TestTradeAlert_ImpactSummary(t *testing.T) {
+	if got := (TradeAlert{}).ImpactSummary(); got != "" {
+		t.Errorf("expected no summary without impact info, got %q", got)
+	}
+
+	alert := TradeAlert{
+		Side:            "BUY",
+		HasImpactInfo:   true,
+		ImpactVolumePct: 0.18,
+		ImpactDepthPct:  0.65,
+		ImpactPriceMove: 0.12,
+	}
+	want := "18% of 24h volume · 65% of ask depth · moved price +12%"
+	if got := alert.ImpactSummary(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	alert = TradeAlert{Side: "SELL", HasImpactInfo: true, ImpactDepthPct: 1.5}
+	if got := alert.ImpactSummary(); got != "150% of bid depth" {
+		t.Errorf("expected only sell-side depth, got %q", got)
+	}
+}
@@ -0,0 +1,127 @@
+package notifier
+
+import (
+	"fmt"
+	"time"
+)
+
+// MarketAlertKind identifies the market anomaly behind a market alert.
+type MarketAlertKind string
+
+const (
+	MarketAlertVolumeSpike MarketAlertKind = "volume_spike" // Window volume far above the market's baseline
+	MarketAlertPriceMove   MarketAlertKind = "price_move"   // Price moved sharply within a short window
+)
+
+// MarketAlert is a market-level alert: the market as a whole did something
+// unusual, rather than a single trade or wallet.
+type MarketAlert struct {
+	Kind MarketAlertKind
+
+	// Market info
+	ConditionID string
+	MarketTitle string
+	MarketURL   string
+	MarketImage string
+	Outcome     string // Outcome whose price moved (price moves only)
+
+	// Window the anomaly was measured over
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Trades      int
+	Notional    float64
+
+	// Volume spike info
+	BaselineMean   float64 // Mean notional per window over the baseline
+	BaselineStdDev float64 // Standard deviation of notional per window
+	ZScore         float64 // Standard deviations above the baseline mean
+
+	// Price move info
+	PriceBefore float64
+	PriceAfter  float64
+
+	// Wallets that traded the most in the window, largest first
+	TopWallets []MarketAlertWallet
+
+	Timestamp time.Time
+}
+
+// MarketAlertWallet is a wallet's trading in a market alert's window.
+type MarketAlertWallet struct {
+	Address      string
+	Name         string
+	URL          string
+	Trades       int
+	BuyNotional  float64
+	SellNotional float64
+}
+
+// Notional returns the wallet's total traded notional.
+func (w MarketAlertWallet) Notional() float64 {
+	return w.BuyNotional + w.SellNotional
+}
+
+// WindowLabel formats the alert window for display (e.g. "5m", "15m").
+func (a MarketAlert) WindowLabel() string {
+	return AlertDigest{WindowStart: a.WindowStart, WindowEnd: a.WindowEnd}.WindowLabel()
+}
+
+// Title returns the alert title.
+func (a MarketAlert) Title() string {
+	switch a.Kind {
+	case MarketAlertVolumeSpike:
+		return "📈 Market Volume Spike"
+	case MarketAlertPriceMove:
+		return "⚡ Sharp Price Move"
+	default:
+		return "📈 Market Anomaly"
+	}
+}
+
+// Summary describes the anomaly on one line, e.g.
+// "$84200 in 5m across 37 trades (4.2σ above the $9100 baseline)" or
+// "Yes moved from 41¢ to 58¢ in 15m across 12 trades".
+func (a MarketAlert) Summary() string {
+	switch a.Kind {
+	case MarketAlertPriceMove:
+		outcome := a.Outcome
+		if outcome == "" {
+			outcome = "Price"
+		}
+		return fmt.Sprintf("%s moved from %s to %s in %s across %d trades ($%.0f)",
+			outcome, FormatCents(a.PriceBefore), FormatCents(a.PriceAfter), a.WindowLabel(), a.Trades, a.Notional)
+	default:
+		return fmt.Sprintf("$%.0f in %s across %d trades (%.1fσ above the $%.0f baseline)",
+			a.Notional, a.WindowLabel(), a.Trades, a.ZScore, a.BaselineMean)
+	}
+}
+
+// MarketAlertNotifier is implemented by notifiers that can deliver market alerts.
+type MarketAlertNotifier interface {
+	SendMarketAlert(alert MarketAlert)
+}
+
+// SendMarketAlert sends the alert to every notifier that supports market alerts.
+func (m *MultiNotifier) SendMarketAlert(alert MarketAlert) {
+	for _, n := range m.notifiers {
+		if ma, ok := n.(MarketAlertNotifier); ok {
+			ma.SendMarketAlert(alert)
+		}
+	}
+}
+
+// SendMarketAlert forwards the alert. Market alerts are not filtered.
+func (f *FilteredNotifier) SendMarketAlert(alert MarketAlert) {
+	if ma, ok := f.notifier.(MarketAlertNotifier); ok {
+		ma.SendMarketAlert(alert)
+	}
+}
+
+// SendMarketAlert sends the alert directly through the wrapped channel.
+// Market alerts are rare and rate limited per market, so they bypass the
+// alert queue like digests.
+func (q *QueuedNotifier) SendMarketAlert(alert MarketAlert) {
+	if ma, ok := q.inner.(MarketAlertNotifier); ok {
+		ma.SendMarketAlert(alert)
+	}
+}
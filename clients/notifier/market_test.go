@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+)
+
+// mockMarketAlertNotifier records market alerts in addition to trade alerts.
+type mockMarketAlertNotifier struct {
+	mockNotifier
+	marketAlerts []MarketAlert
+}
+
+func (m *mockMarketAlertNotifier) SendMarketAlert(alert MarketAlert) {
+	m.marketAlerts = append(m.marketAlerts, alert)
+}
+
+func TestMultiNotifier_SendMarketAlert(t *testing.T) {
+	plain := &mockNotifier{}
+	capable := &mockMarketAlertNotifier{}
+	multi := NewMultiNotifier(plain, NewFilteredNotifier(capable, func(TradeAlert) bool { return false }))
+
+	multi.SendMarketAlert(MarketAlert{Kind: MarketAlertVolumeSpike, ConditionID: "0xmarket"})
+
+	if len(capable.marketAlerts) != 1 || capable.marketAlerts[0].ConditionID != "0xmarket" {
+		t.Errorf("expected market alert forwarded through filter, got %+v", capable.marketAlerts)
+	}
+	if len(plain.alerts) != 0 {
+		t.Error("expected notifiers without market alert support to be skipped")
+	}
+}
+
+func TestMarketAlert_Summary(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	spike := MarketAlert{
+		Kind:         MarketAlertVolumeSpike,
+		WindowStart:  start,
+		WindowEnd:    start.Add(5 * time.Minute),
+		Trades:       37,
+		Notional:     84200,
+		BaselineMean: 9100,
+		ZScore:       4.2,
+	}
+	if got, want := spike.Summary(), "$84200 in 5m across 37 trades (4.2σ above the $9100 baseline)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	move := MarketAlert{
+		Kind:        MarketAlertPriceMove,
+		Outcome:     "Yes",
+		WindowStart: start,
+		WindowEnd:   start.Add(15 * time.Minute),
+		Trades:      12,
+		Notional:    20500,
+		PriceBefore: 0.41,
+		PriceAfter:  0.58,
+	}
+	if got, want := move.Summary(), "Yes moved from 41¢ to 58¢ in 15m across 12 trades ($20500)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if move.Title() == spike.Title() {
+		t.Error("expected distinct titles per kind")
+	}
+}
@@ -24,8 +24,18 @@ const (
 	AlertReasonPerfectExitTiming    AlertReason = "perfect_exit_timing"   // Consistently exits near price peaks
 	AlertReasonStealthAccumulation  AlertReason = "stealth_accumulation"  // Gradual position building to avoid detection
 	AlertReasonPreMovePositioning   AlertReason = "pre_move_positioning"  // Consistently positioned before price moves
+	AlertReasonMarketImpact         AlertReason = "market_impact"         // Trade is large relative to the market's volume, book or price
+	AlertReasonCoordinatedEntry     AlertReason = "coordinated_entry"     // Several fresh wallets bought the same outcome within a window
 )
 
+// Fill is one maker fill of an order that was combined into a single trade.
+type Fill struct {
+	TradeID string  `json:"trade_id,omitempty"`
+	Maker   string  `json:"maker,omitempty"`
+	Shares  float64 `json:"shares"`
+	Price   float64 `json:"price"`
+}
+
 // TradeAlert contains all the data needed for a trade alert notification.
 type TradeAlert struct {
 	// Wallet info
@@ -115,7 +125,46 @@ type TradeAlert struct {
 	PreMoveAvgMoveSize     float64 // Average favorable move size
 	HasPreMoveInfo         bool    // True if pre-move data is present
 
+	// Market impact info
+	ImpactScore     float64 // Largest ratio of a measure to its threshold (>= 1 when flagged)
+	ImpactVolumePct float64 // Notional as a share of the market's 24h volume (0 if unknown)
+	ImpactDepthPct  float64 // Notional as a share of the book depth on the side the trade took (0 if unknown)
+	ImpactPriceMove float64 // Relative price move across the trade in its direction (0.10 = 10%)
+	HasImpactInfo   bool    // True if market impact data is present
+
+	// Coordinated entry info
+	ClusterWallets  []ClusterWallet // Fresh wallets that bought the outcome together, largest first
+	ClusterShares   float64         // Combined shares bought
+	ClusterNotional float64         // Combined USD bought
+	ClusterSpanSecs float64         // Time from the first buy to the last (seconds)
+	HasClusterInfo  bool            // True if coordinated entry data is present
+
+	// Linked wallet cluster (wallets that appear to be the same actor)
+	WalletClusterID      string   // Empty if the wallet isn't linked to others
+	WalletClusterSize    int      // Wallets in the cluster, including this one
+	WalletClusterSignals []string // How the cluster is linked, e.g. "co-trading", "copying"
+
+	// Order fills (set when an order filled against several makers)
+	Fills []Fill // Fills combined into the trade; Shares, Price and Notional are their totals and VWAP
+
+	// Order book info (WebSocket only)
+	HasBookInfo     bool
+	BookPriceBefore float64 // Mid price when the order's first fill arrived (0 if unknown)
+	BookPriceAfter  float64 // Mid price once the order was evaluated
+	BookBestBid     float64
+	BookBestAsk     float64
+	BookBidDepth    float64 // USD resting on the bid side
+	BookAskDepth    float64 // USD resting on the ask side
+
+	// Repeat info (repeats within the cooldown are merged into updates)
+	IsUpdate      bool    // Reports fills merged since an earlier alert rather than a new trade
+	MergedFills   int     // Fills merged into this alert
+	TotalFills    int     // Fills since the first alert on the wallet, market and side
+	TotalShares   float64 // Running share total since the first alert
+	TotalNotional float64 // Running notional total since the first alert
+
 	// Alert metadata
+	ID        string // Set when the alert is sent; lets alert buttons refer back to it
 	Reasons   []AlertReason
 	Timestamp time.Time
 }
@@ -168,3 +217,27 @@ func (m *MultiNotifier) Close() error {
 func (m *MultiNotifier) Count() int {
 	return len(m.notifiers)
 }
+
+// FilteredNotifier forwards only the alerts accepted by a predicate.
+type FilteredNotifier struct {
+	notifier Notifier
+	accept   func(TradeAlert) bool
+}
+
+// NewFilteredNotifier wraps a notifier with a filter. A nil accept forwards everything.
+func NewFilteredNotifier(n Notifier, accept func(TradeAlert) bool) *FilteredNotifier {
+	return &FilteredNotifier{notifier: n, accept: accept}
+}
+
+// SendTradeAlert forwards the alert if the filter accepts it.
+func (f *FilteredNotifier) SendTradeAlert(alert TradeAlert) {
+	if f.accept != nil && !f.accept(alert) {
+		return
+	}
+	f.notifier.SendTradeAlert(alert)
+}
+
+// Close closes the wrapped notifier.
+func (f *FilteredNotifier) Close() error {
+	return f.notifier.Close()
+}
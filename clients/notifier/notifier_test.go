@@ -272,3 +272,67 @@ func TestAlertReason_Values(t *testing.T) {
 		})
 	}
 }
+
+func TestFilteredNotifier(t *testing.T) {
+	mock := &mockNotifier{}
+	f := NewFilteredNotifier(mock, func(alert TradeAlert) bool {
+		return len(alert.Reasons) > 0 && alert.Reasons[0] == AlertReasonMassiveTrade
+	})
+
+	f.SendTradeAlert(TradeAlert{Reasons: []AlertReason{AlertReasonNewWallet}})
+	f.SendTradeAlert(TradeAlert{Reasons: []AlertReason{AlertReasonMassiveTrade}})
+
+	if len(mock.alerts) != 1 {
+		t.Fatalf("expected 1 forwarded alert, got %d", len(mock.alerts))
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if !mock.closeCalled {
+		t.Error("expected wrapped notifier to be closed")
+	}
+}
+
+// mockDigestNotifier records digests in addition to alerts.
+type mockDigestNotifier struct {
+	mockNotifier
+	digests []AlertDigest
+}
+
+func (m *mockDigestNotifier) SendDigest(digest AlertDigest) {
+	m.digests = append(m.digests, digest)
+}
+
+func TestMultiNotifier_SendDigest(t *testing.T) {
+	plain := &mockNotifier{}
+	digestible := &mockDigestNotifier{}
+	multi := NewMultiNotifier(plain, NewFilteredNotifier(digestible, func(TradeAlert) bool { return false }))
+
+	multi.SendDigest(AlertDigest{TotalAlerts: 3})
+
+	if len(digestible.digests) != 1 || digestible.digests[0].TotalAlerts != 3 {
+		t.Errorf("expected digest forwarded through filter, got %+v", digestible.digests)
+	}
+	if len(plain.alerts) != 0 {
+		t.Error("expected notifiers without digest support to be skipped")
+	}
+}
+
+func TestAlertDigest_WindowLabel(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		window time.Duration
+		want   string
+	}{
+		{time.Hour, "1h"},
+		{24 * time.Hour, "24h"},
+		{30 * time.Minute, "30m"},
+		{90 * time.Minute, "1h30m"},
+	}
+	for _, tt := range tests {
+		d := AlertDigest{WindowStart: start, WindowEnd: start.Add(tt.window)}
+		if got := d.WindowLabel(); got != tt.want {
+			t.Errorf("WindowLabel(%v) = %q, want %q", tt.window, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,485 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeliveryNotifier is a notifier channel that reports delivery errors,
+// so a QueuedNotifier can retry it.
+type DeliveryNotifier interface {
+	Notifier
+
+	// Name identifies the channel in logs and stats (e.g. "discord").
+	Name() string
+
+	// DeliverTradeAlert sends the alert and returns any delivery error.
+	// Return a *RetryAfterError when the channel asks to back off and
+	// Permanent(err) when retrying cannot help.
+	DeliverTradeAlert(ctx context.Context, alert TradeAlert) error
+}
+
+// RetryAfterError is returned when a channel rate limits a send.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.RetryAfter)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// PermanentError marks a delivery failure that retrying will not fix.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so it is dead-lettered without retries.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// QueueConfig holds delivery settings for a QueuedNotifier.
+type QueueConfig struct {
+	BufferSize     int           // Alerts buffered per channel before new alerts are dead-lettered
+	MaxAttempts    int           // Delivery attempts before an alert is dead-lettered
+	InitialBackoff time.Duration // First retry delay, doubled on each attempt
+	MaxBackoff     time.Duration // Retry delay cap (retry_after from the channel takes precedence)
+	MinInterval    time.Duration // Minimum spacing between sends on the channel
+}
+
+// DefaultQueueConfig returns sensible defaults.
+func DefaultQueueConfig() QueueConfig {
+	return QueueConfig{
+		BufferSize:     100,
+		MaxAttempts:    5,
+		InitialBackoff: 2 * time.Second,
+		MaxBackoff:     2 * time.Minute,
+		MinInterval:    1 * time.Second,
+	}
+}
+
+// DeadLetter is an alert that could not be delivered to a channel.
+type DeadLetter struct {
+	Channel  string     `json:"channel"`
+	Alert    TradeAlert `json:"alert"`
+	Error    string     `json:"error"`
+	Attempts int        `json:"attempts"`
+	FailedAt time.Time  `json:"failed_at"`
+}
+
+// DeadLetterHandler receives alerts that exhausted their retries.
+type DeadLetterHandler func(DeadLetter)
+
+// DeliveryStats holds delivery metrics for a single channel.
+type DeliveryStats struct {
+	Channel      string    `json:"channel"`
+	Queued       int       `json:"queued"`        // Alerts waiting to be sent
+	Sent         int64     `json:"sent"`          // Alerts delivered
+	Retries      int64     `json:"retries"`       // Failed attempts that were retried
+	RateLimited  int64     `json:"rate_limited"`  // Attempts rejected with retry_after
+	DeadLettered int64     `json:"dead_lettered"` // Alerts that exhausted retries
+	Dropped      int64     `json:"dropped"`       // Alerts rejected because the buffer was full
+	LastError    string    `json:"last_error,omitempty"`
+	LastErrorAt  time.Time `json:"last_error_at,omitempty"`
+	LastSentAt   time.Time `json:"last_sent_at,omitempty"`
+}
+
+// QueuedNotifier delivers alerts to a channel from a background worker,
+// so a slow or rate-limited channel never blocks the caller.
+// Failed sends are retried with exponential backoff; alerts that still
+// fail are handed to the dead-letter handler.
+// Implements Notifier interface.
+type QueuedNotifier struct {
+	logger *zap.Logger
+	inner  DeliveryNotifier
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   QueueConfig
+
+	deadLetterMu sync.RWMutex
+	onDeadLetter DeadLetterHandler
+
+	threadsMu sync.RWMutex
+	threads   MessageThreads
+
+	queue    chan TradeAlert
+	lastSend time.Time
+
+	statsMu sync.Mutex
+	stats   DeliveryStats
+
+	// sendMu orders enqueues before Close, so an alert is either queued
+	// before the worker drains or dead-lettered by SendTradeAlert
+	sendMu    sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	doneCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewQueuedNotifier wraps a channel with a bounded queue and starts its worker.
+func NewQueuedNotifier(logger *zap.Logger, inner DeliveryNotifier, config QueueConfig) *QueuedNotifier {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultQueueConfig().BufferSize
+	}
+
+	q := &QueuedNotifier{
+		logger: logger.Named("notify-queue").With(zap.String("channel", inner.Name())),
+		inner:  inner,
+		config: config,
+		queue:  make(chan TradeAlert, bufferSize),
+		stats:  DeliveryStats{Channel: inner.Name()},
+		doneCh: make(chan struct{}),
+	}
+
+	q.wg.Add(1)
+	go q.run()
+	return q
+}
+
+// Name returns the wrapped channel's name.
+func (q *QueuedNotifier) Name() string {
+	return q.inner.Name()
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (q *QueuedNotifier) getConfig() QueueConfig {
+	q.configMu.RLock()
+	defer q.configMu.RUnlock()
+	return q.config
+}
+
+// UpdateConfig updates retry and rate limit settings. The buffer size is fixed at creation.
+func (q *QueuedNotifier) UpdateConfig(cfg QueueConfig) {
+	q.configMu.Lock()
+	defer q.configMu.Unlock()
+	q.config = cfg
+}
+
+// SetDeadLetterHandler sets the handler for alerts that could not be delivered.
+func (q *QueuedNotifier) SetDeadLetterHandler(handler DeadLetterHandler) {
+	q.deadLetterMu.Lock()
+	defer q.deadLetterMu.Unlock()
+	q.onDeadLetter = handler
+}
+
+// SetMessageThreads sets the store used to post follow-up alerts about the
+// same wallet and market as replies. Ignored by channels that can't reply.
+func (q *QueuedNotifier) SetMessageThreads(threads MessageThreads) {
+	q.threadsMu.Lock()
+	defer q.threadsMu.Unlock()
+	q.threads = threads
+}
+
+// getThreads returns the message thread store, if any.
+func (q *QueuedNotifier) getThreads() MessageThreads {
+	q.threadsMu.RLock()
+	defer q.threadsMu.RUnlock()
+	return q.threads
+}
+
+// AnnotateMessage adds a note to a message this channel sent.
+func (q *QueuedNotifier) AnnotateMessage(ctx context.Context, ref MessageRef, note string) error {
+	threaded, ok := q.inner.(ThreadedNotifier)
+	if !ok {
+		return fmt.Errorf("channel %s cannot edit messages", q.Name())
+	}
+	return threaded.AnnotateMessage(ctx, ref, note)
+}
+
+// SendTradeAlert queues the alert without blocking.
+// Implements Notifier interface.
+func (q *QueuedNotifier) SendTradeAlert(alert TradeAlert) {
+	if err := q.enqueue(alert); err != nil {
+		q.deadLetter(alert, err, 0)
+	}
+}
+
+// enqueue queues the alert unless the notifier is closed or the queue is full.
+func (q *QueuedNotifier) enqueue(alert TradeAlert) error {
+	q.sendMu.RLock()
+	defer q.sendMu.RUnlock()
+
+	if q.closed {
+		return errors.New("notifier closed")
+	}
+
+	select {
+	case q.queue <- alert:
+		return nil
+	default:
+		q.statsMu.Lock()
+		q.stats.Dropped++
+		q.statsMu.Unlock()
+		return errors.New("queue full")
+	}
+}
+
+// Stats returns delivery metrics for the channel.
+func (q *QueuedNotifier) Stats() DeliveryStats {
+	q.statsMu.Lock()
+	defer q.statsMu.Unlock()
+	stats := q.stats
+	stats.Queued = len(q.queue)
+	return stats
+}
+
+// Close stops the worker, dead-letters anything still queued and closes the channel.
+// Implements Notifier interface.
+func (q *QueuedNotifier) Close() error {
+	q.closeOnce.Do(func() {
+		q.sendMu.Lock()
+		q.closed = true
+		close(q.doneCh)
+		q.sendMu.Unlock()
+	})
+	q.wg.Wait()
+	return q.inner.Close()
+}
+
+// run delivers queued alerts until the notifier is closed.
+func (q *QueuedNotifier) run() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.doneCh:
+			q.drain()
+			return
+		case alert := <-q.queue:
+			q.deliver(alert)
+		}
+	}
+}
+
+// drain dead-letters queued alerts on shutdown, so they are redelivered on the
+// next start.
+func (q *QueuedNotifier) drain() {
+	for {
+		select {
+		case alert := <-q.queue:
+			q.deadLetter(alert, errors.New("notifier closed"), 0)
+		default:
+			return
+		}
+	}
+}
+
+// deliver sends one alert, retrying until it succeeds, fails permanently,
+// runs out of attempts or the notifier closes.
+func (q *QueuedNotifier) deliver(alert TradeAlert) {
+	cfg := q.getConfig()
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var err error
+	attempt := 0
+	for attempt < maxAttempts {
+		// Per-channel rate limit
+		if wait := time.Until(q.lastSend.Add(cfg.MinInterval)); wait > 0 {
+			if !q.sleep(wait) {
+				break
+			}
+		}
+
+		attempt++
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err = q.send(ctx, alert)
+		cancel()
+		q.lastSend = time.Now()
+
+		if err == nil {
+			q.statsMu.Lock()
+			q.stats.Sent++
+			q.stats.LastSentAt = q.lastSend
+			q.statsMu.Unlock()
+			return
+		}
+
+		var retryAfter *RetryAfterError
+		isRateLimited := errors.As(err, &retryAfter)
+
+		q.statsMu.Lock()
+		q.stats.LastError = err.Error()
+		q.stats.LastErrorAt = time.Now()
+		if isRateLimited {
+			q.stats.RateLimited++
+		}
+		q.statsMu.Unlock()
+
+		var permanent *PermanentError
+		if errors.As(err, &permanent) || attempt >= maxAttempts {
+			break
+		}
+
+		delay := backoffDelay(cfg, attempt)
+		if isRateLimited && retryAfter.RetryAfter > 0 {
+			delay = retryAfter.RetryAfter
+		}
+
+		q.logger.Warn("alert delivery failed, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+
+		q.statsMu.Lock()
+		q.stats.Retries++
+		q.statsMu.Unlock()
+
+		if !q.sleep(delay) {
+			err = fmt.Errorf("notifier closed during retry: %w", err)
+			break
+		}
+	}
+
+	if err == nil {
+		err = errors.New("notifier closed")
+	}
+	q.deadLetter(alert, err, attempt)
+}
+
+// send makes one delivery attempt. When the channel supports it, alerts
+// about a wallet and market already alerted on are sent as replies to the
+// first message about them.
+func (q *QueuedNotifier) send(ctx context.Context, alert TradeAlert) error {
+	threaded, ok := q.inner.(ThreadedNotifier)
+	threads := q.getThreads()
+	key := alert.ThreadKey()
+	if !ok || threads == nil || key == "" {
+		return q.inner.DeliverTradeAlert(ctx, alert)
+	}
+
+	var replyTo *MessageRef
+	if root, found := threads.ThreadRoot(key, q.Name()); found {
+		replyTo = &root
+	}
+
+	ref, err := threaded.DeliverThreadedAlert(ctx, alert, replyTo)
+	if err != nil {
+		return err
+	}
+	if ref.MessageID != "" {
+		ref.Channel = q.Name()
+		if ref.SentAt.IsZero() {
+			ref.SentAt = time.Now()
+		}
+		threads.RecordMessage(key, ref)
+	}
+	return nil
+}
+
+// sleep waits for d, returning false if the notifier closes first.
+func (q *QueuedNotifier) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-q.doneCh:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// deadLetter hands an undeliverable alert to the dead-letter handler.
+func (q *QueuedNotifier) deadLetter(alert TradeAlert, err error, attempts int) {
+	q.statsMu.Lock()
+	q.stats.DeadLettered++
+	q.statsMu.Unlock()
+
+	q.logger.Error("alert dead-lettered",
+		zap.String("market", alert.MarketTitle),
+		zap.Int("attempts", attempts),
+		zap.Error(err),
+	)
+
+	q.deadLetterMu.RLock()
+	handler := q.onDeadLetter
+	q.deadLetterMu.RUnlock()
+
+	if handler != nil {
+		handler(DeadLetter{
+			Channel:  q.inner.Name(),
+			Alert:    alert,
+			Error:    err.Error(),
+			Attempts: attempts,
+			FailedAt: time.Now(),
+		})
+	}
+}
+
+// backoffDelay returns the exponential backoff delay after the given attempt.
+func backoffDelay(cfg QueueConfig, attempt int) time.Duration {
+	delay := cfg.InitialBackoff
+	if delay <= 0 {
+		delay = DefaultQueueConfig().InitialBackoff
+	}
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if cfg.MaxBackoff > 0 && delay >= cfg.MaxBackoff {
+			return cfg.MaxBackoff
+		}
+	}
+	if cfg.MaxBackoff > 0 && delay > cfg.MaxBackoff {
+		return cfg.MaxBackoff
+	}
+	return delay
+}
+
+// namedNotifier overrides the channel name of a DeliveryNotifier.
+type namedNotifier struct {
+	DeliveryNotifier
+	name string
+}
+
+func (n *namedNotifier) Name() string {
+	return n.name
+}
+
+// namedThreadedNotifier overrides the channel name of a ThreadedNotifier.
+type namedThreadedNotifier struct {
+	ThreadedNotifier
+	name string
+}
+
+func (n *namedThreadedNotifier) Name() string {
+	return n.name
+}
+
+// WithName returns a DeliveryNotifier reported under a different channel name
+// in logs, stats and dead letters. Threading support is kept.
+func WithName(n DeliveryNotifier, name string) DeliveryNotifier {
+	if threaded, ok := n.(ThreadedNotifier); ok {
+		return &namedThreadedNotifier{ThreadedNotifier: threaded, name: name}
+	}
+	return &namedNotifier{DeliveryNotifier: n, name: name}
+}
@@ -0,0 +1,255 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// mockDeliveryNotifier returns scripted errors, then succeeds.
+type mockDeliveryNotifier struct {
+	mu       sync.Mutex
+	errs     []error
+	block    chan struct{} // If set, deliveries wait until closed
+	attempts int
+	sent     []TradeAlert
+	closed   bool
+}
+
+func (m *mockDeliveryNotifier) Name() string { return "mock" }
+
+func (m *mockDeliveryNotifier) SendTradeAlert(alert TradeAlert) {
+	_ = m.DeliverTradeAlert(context.Background(), alert)
+}
+
+func (m *mockDeliveryNotifier) DeliverTradeAlert(ctx context.Context, alert TradeAlert) error {
+	if m.block != nil {
+		<-m.block
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts++
+	if len(m.errs) > 0 {
+		err := m.errs[0]
+		m.errs = m.errs[1:]
+		return err
+	}
+	m.sent = append(m.sent, alert)
+	return nil
+}
+
+func (m *mockDeliveryNotifier) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockDeliveryNotifier) Attempts() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.attempts
+}
+
+// deadLetterRecorder collects dead letters from a queue.
+type deadLetterRecorder struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func (r *deadLetterRecorder) Add(dl DeadLetter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.letters = append(r.letters, dl)
+}
+
+func (r *deadLetterRecorder) Letters() []DeadLetter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]DeadLetter(nil), r.letters...)
+}
+
+func fastQueueConfig() QueueConfig {
+	return QueueConfig{
+		BufferSize:     10,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+// waitFor polls until cond is true or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueuedNotifier_DeliversAlert(t *testing.T) {
+	inner := &mockDeliveryNotifier{}
+	q := NewQueuedNotifier(zap.NewNop(), inner, fastQueueConfig())
+
+	q.SendTradeAlert(TradeAlert{TraderName: "trader"})
+	waitFor(t, func() bool { return q.Stats().Sent == 1 })
+
+	if err := q.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if !inner.closed {
+		t.Error("expected inner notifier to be closed")
+	}
+	if stats := q.Stats(); stats.Channel != "mock" || stats.Retries != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestQueuedNotifier_RetriesTransientErrors(t *testing.T) {
+	inner := &mockDeliveryNotifier{errs: []error{errors.New("timeout"), errors.New("timeout")}}
+	q := NewQueuedNotifier(zap.NewNop(), inner, fastQueueConfig())
+	defer q.Close()
+
+	q.SendTradeAlert(TradeAlert{TraderName: "trader"})
+	waitFor(t, func() bool { return q.Stats().Sent == 1 })
+
+	stats := q.Stats()
+	if stats.Retries != 2 || stats.DeadLettered != 0 {
+		t.Errorf("expected 2 retries and no dead letters, got %+v", stats)
+	}
+	if stats.LastError != "timeout" {
+		t.Errorf("expected last error to be recorded, got %q", stats.LastError)
+	}
+}
+
+func TestQueuedNotifier_DeadLettersAfterMaxAttempts(t *testing.T) {
+	inner := &mockDeliveryNotifier{errs: []error{errors.New("a"), errors.New("b"), errors.New("c")}}
+	q := NewQueuedNotifier(zap.NewNop(), inner, fastQueueConfig())
+	defer q.Close()
+	recorder := &deadLetterRecorder{}
+	q.SetDeadLetterHandler(recorder.Add)
+
+	q.SendTradeAlert(TradeAlert{TraderName: "trader"})
+	waitFor(t, func() bool { return len(recorder.Letters()) == 1 })
+
+	dl := recorder.Letters()[0]
+	if dl.Channel != "mock" || dl.Attempts != 3 || dl.Error != "c" || dl.Alert.TraderName != "trader" {
+		t.Errorf("unexpected dead letter: %+v", dl)
+	}
+}
+
+func TestQueuedNotifier_PermanentErrorSkipsRetries(t *testing.T) {
+	inner := &mockDeliveryNotifier{errs: []error{Permanent(errors.New("bad request"))}}
+	q := NewQueuedNotifier(zap.NewNop(), inner, fastQueueConfig())
+	defer q.Close()
+	recorder := &deadLetterRecorder{}
+	q.SetDeadLetterHandler(recorder.Add)
+
+	q.SendTradeAlert(TradeAlert{})
+	waitFor(t, func() bool { return len(recorder.Letters()) == 1 })
+
+	if inner.Attempts() != 1 {
+		t.Errorf("expected a single attempt, got %d", inner.Attempts())
+	}
+}
+
+func TestQueuedNotifier_HonorsRetryAfter(t *testing.T) {
+	retryAfter := 50 * time.Millisecond
+	inner := &mockDeliveryNotifier{errs: []error{&RetryAfterError{Err: errors.New("429"), RetryAfter: retryAfter}}}
+	q := NewQueuedNotifier(zap.NewNop(), inner, fastQueueConfig())
+	defer q.Close()
+
+	start := time.Now()
+	q.SendTradeAlert(TradeAlert{})
+	waitFor(t, func() bool { return q.Stats().Sent == 1 })
+
+	if elapsed := time.Since(start); elapsed < retryAfter {
+		t.Errorf("expected retry to wait at least %v, took %v", retryAfter, elapsed)
+	}
+	if q.Stats().RateLimited != 1 {
+		t.Errorf("expected 1 rate limited attempt, got %d", q.Stats().RateLimited)
+	}
+}
+
+func TestQueuedNotifier_FullQueueDeadLetters(t *testing.T) {
+	inner := &mockDeliveryNotifier{block: make(chan struct{})}
+	cfg := fastQueueConfig()
+	cfg.BufferSize = 1
+	q := NewQueuedNotifier(zap.NewNop(), inner, cfg)
+	recorder := &deadLetterRecorder{}
+	q.SetDeadLetterHandler(recorder.Add)
+
+	// First alert is picked up by the blocked worker, second fills the buffer
+	q.SendTradeAlert(TradeAlert{TraderName: "1"})
+	waitFor(t, func() bool { return q.Stats().Queued == 0 })
+	q.SendTradeAlert(TradeAlert{TraderName: "2"})
+	q.SendTradeAlert(TradeAlert{TraderName: "3"})
+
+	if stats := q.Stats(); stats.Dropped != 1 {
+		t.Errorf("expected 1 dropped alert, got %+v", stats)
+	}
+	letters := recorder.Letters()
+	if len(letters) != 1 || letters[0].Alert.TraderName != "3" {
+		t.Fatalf("expected alert 3 to be dead-lettered, got %+v", letters)
+	}
+
+	// Closing dead-letters the alert still waiting in the buffer
+	close(inner.block)
+	_ = q.Close()
+	if sent := q.Stats().Sent; sent+int64(len(recorder.Letters())) != 3 {
+		t.Errorf("expected every alert to be sent or dead-lettered, got %d sent and %d dead letters", sent, len(recorder.Letters()))
+	}
+}
+
+func TestQueuedNotifier_SendDuringClose(t *testing.T) {
+	inner := &mockDeliveryNotifier{}
+	q := NewQueuedNotifier(zap.NewNop(), inner, fastQueueConfig())
+	recorder := &deadLetterRecorder{}
+	q.SetDeadLetterHandler(recorder.Add)
+
+	// Alerts racing Close are either delivered or dead-lettered, never lost
+	const senders, perSender = 8, 50
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perSender; j++ {
+				q.SendTradeAlert(TradeAlert{})
+			}
+		}()
+	}
+	_ = q.Close()
+	wg.Wait()
+
+	if sent := q.Stats().Sent; sent+int64(len(recorder.Letters())) != senders*perSender {
+		t.Errorf("expected every alert to be sent or dead-lettered, got %d sent and %d dead letters", sent, len(recorder.Letters()))
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	cfg := QueueConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := backoffDelay(cfg, tt.attempt); got != tt.expected {
+			t.Errorf("attempt %d: expected %v, got %v", tt.attempt, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// MessageRef identifies a chat message an alert was delivered as, so later
+// alerts about the same wallet and market can reply to it.
+type MessageRef struct {
+	Channel   string    `json:"channel"`    // Delivery channel name (e.g. "discord" or "discord:whales")
+	ChatID    string    `json:"chat_id"`    // Discord channel ID or Telegram chat ID
+	MessageID string    `json:"message_id"` // Discord message ID or Telegram message_id
+	SentAt    time.Time `json:"sent_at"`
+}
+
+// ThreadKey groups alerts about the same wallet and market.
+// Returns "" if the alert is missing either.
+func (a TradeAlert) ThreadKey() string {
+	if a.TraderAddress == "" || a.ConditionID == "" {
+		return ""
+	}
+	return strings.ToLower(a.TraderAddress) + ":" + a.ConditionID
+}
+
+// ThreadedNotifier is a channel that can reply to and edit messages it sent.
+type ThreadedNotifier interface {
+	DeliveryNotifier
+
+	// DeliverThreadedAlert sends the alert as a reply to replyTo (nil for a
+	// new message) and returns a reference to the sent message.
+	DeliverThreadedAlert(ctx context.Context, alert TradeAlert, replyTo *MessageRef) (MessageRef, error)
+
+	// AnnotateMessage adds a note, such as the market outcome, to a sent message.
+	AnnotateMessage(ctx context.Context, ref MessageRef, note string) error
+}
+
+// MessageThreads stores the first message sent per thread on each channel.
+type MessageThreads interface {
+	// ThreadRoot returns the message that started the thread on a channel.
+	ThreadRoot(threadKey, channel string) (MessageRef, bool)
+
+	// RecordMessage stores a delivered message. The first message per
+	// channel stays the thread root.
+	RecordMessage(threadKey string, ref MessageRef)
+}
@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// mockThreadedNotifier numbers sent messages and records what they replied to.
+type mockThreadedNotifier struct {
+	mockDeliveryNotifier
+	replies   []*MessageRef
+	annotated map[string]string // messageID -> note
+}
+
+func (m *mockThreadedNotifier) DeliverThreadedAlert(ctx context.Context, alert TradeAlert, replyTo *MessageRef) (MessageRef, error) {
+	if err := m.DeliverTradeAlert(ctx, alert); err != nil {
+		return MessageRef{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replies = append(m.replies, replyTo)
+	return MessageRef{ChatID: "chat", MessageID: fmt.Sprintf("m%d", len(m.sent))}, nil
+}
+
+func (m *mockThreadedNotifier) AnnotateMessage(ctx context.Context, ref MessageRef, note string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.annotated == nil {
+		m.annotated = make(map[string]string)
+	}
+	m.annotated[ref.MessageID] = note
+	return nil
+}
+
+func (m *mockThreadedNotifier) Replies() []*MessageRef {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*MessageRef(nil), m.replies...)
+}
+
+// memoryThreads is an in-memory MessageThreads.
+type memoryThreads struct {
+	mu    sync.Mutex
+	roots map[string]MessageRef // threadKey|channel -> root
+}
+
+func (t *memoryThreads) ThreadRoot(threadKey, channel string) (MessageRef, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ref, ok := t.roots[threadKey+"|"+channel]
+	return ref, ok
+}
+
+func (t *memoryThreads) RecordMessage(threadKey string, ref MessageRef) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.roots == nil {
+		t.roots = make(map[string]MessageRef)
+	}
+	if _, ok := t.roots[threadKey+"|"+ref.Channel]; !ok {
+		t.roots[threadKey+"|"+ref.Channel] = ref
+	}
+}
+
+func TestTradeAlert_ThreadKey(t *testing.T) {
+	alert := TradeAlert{TraderAddress: "0xABC", ConditionID: "0xmarket"}
+	if got := alert.ThreadKey(); got != "0xabc:0xmarket" {
+		t.Errorf("unexpected thread key %q", got)
+	}
+	if got := (TradeAlert{TraderAddress: "0xabc"}).ThreadKey(); got != "" {
+		t.Errorf("expected no thread key without a market, got %q", got)
+	}
+}
+
+func TestQueuedNotifier_ThreadsFollowUpAlerts(t *testing.T) {
+	inner := &mockThreadedNotifier{}
+	threads := &memoryThreads{}
+	q := NewQueuedNotifier(zap.NewNop(), WithName(inner, "discord:whales"), fastQueueConfig())
+	defer q.Close()
+	q.SetMessageThreads(threads)
+
+	alert := TradeAlert{TraderAddress: "0xabc", ConditionID: "0xmarket"}
+	q.SendTradeAlert(alert)
+	q.SendTradeAlert(alert)
+	q.SendTradeAlert(TradeAlert{TraderAddress: "0xabc", ConditionID: "0xother"})
+	waitFor(t, func() bool { return q.Stats().Sent == 3 })
+
+	replies := inner.Replies()
+	if replies[0] != nil || replies[2] != nil {
+		t.Errorf("expected first alerts per market to start threads, got %+v", replies)
+	}
+	if replies[1] == nil || replies[1].MessageID != "m1" || replies[1].Channel != "discord:whales" {
+		t.Errorf("expected follow-up to reply to m1, got %+v", replies[1])
+	}
+
+	root, _ := threads.ThreadRoot("0xabc:0xmarket", "discord:whales")
+	if err := q.AnnotateMessage(context.Background(), root, "Resolved: won"); err != nil {
+		t.Fatalf("unexpected annotate error: %v", err)
+	}
+	if inner.annotated["m1"] != "Resolved: won" {
+		t.Errorf("expected root annotated, got %+v", inner.annotated)
+	}
+}
+
+func TestQueuedNotifier_UnthreadedChannel(t *testing.T) {
+	inner := &mockDeliveryNotifier{}
+	q := NewQueuedNotifier(zap.NewNop(), inner, fastQueueConfig())
+	defer q.Close()
+	q.SetMessageThreads(&memoryThreads{})
+
+	q.SendTradeAlert(TradeAlert{TraderAddress: "0xabc", ConditionID: "0xmarket"})
+	waitFor(t, func() bool { return q.Stats().Sent == 1 })
+
+	if err := q.AnnotateMessage(context.Background(), MessageRef{MessageID: "1"}, "note"); err == nil {
+		t.Error("expected annotate to fail on a channel that can't edit messages")
+	}
+}
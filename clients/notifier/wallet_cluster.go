@@ -0,0 +1,21 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WalletClusterSummary describes the wallet's linked cluster on one line,
+// e.g. "C-1a2b3c4d · 4 wallets · co-trading, copying".
+// Returns "" if the wallet isn't in a cluster.
+func (a TradeAlert) WalletClusterSummary() string {
+	if a.WalletClusterID == "" {
+		return ""
+	}
+
+	summary := fmt.Sprintf("%s · %d wallets", a.WalletClusterID, a.WalletClusterSize)
+	if len(a.WalletClusterSignals) > 0 {
+		summary += " · " + strings.Join(a.WalletClusterSignals, ", ")
+	}
+	return summary
+}
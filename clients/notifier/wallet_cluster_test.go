@@ -0,0 +1,19 @@
+package notifier
+
+import "testing"
+
+func TestTradeAlert_WalletClusterSummary(t *testing.T) {
+	if got := (TradeAlert{}).WalletClusterSummary(); got != "" {
+		t.Errorf("expected no summary without a cluster, got %q", got)
+	}
+
+	alert := TradeAlert{
+		WalletClusterID:      "C-1a2b3c4d",
+		WalletClusterSize:    4,
+		WalletClusterSignals: []string{"co-trading", "copying"},
+	}
+	want := "C-1a2b3c4d · 4 wallets · co-trading, copying"
+	if got := alert.WalletClusterSummary(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -68,6 +68,12 @@ type GammaMarket struct {
 	// Market image
 	Image string `json:"image"`
 
+	// Category label from Gamma (e.g. "Sports"); often empty on newer markets
+	Category string `json:"category,omitempty"`
+
+	// Tag slugs the market was fetched under by GetTopMarketsByVolumeFiltered
+	TagSlugs []string `json:"-"`
+
 	// Resolution info (for closed markets)
 	WinningOutcome string `json:"winningOutcome,omitempty"`
 	ClosedTime     string `json:"closedTime,omitempty"`
@@ -323,6 +329,10 @@ func (c *PolymarketApiClient) GetTopMarketsByVolumeFiltered(
 		for _, event := range events {
 			for _, market := range event.Markets {
 				if market.ConditionID != "" && market.Active && !market.Closed {
+					if existing, ok := marketMap[market.ConditionID]; ok {
+						market.TagSlugs = existing.TagSlugs
+					}
+					market.TagSlugs = append(market.TagSlugs, category)
 					marketMap[market.ConditionID] = market
 				}
 			}
@@ -0,0 +1,198 @@
+package polymarketapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ResponseCacheMode controls how a ResponseCache interacts with requests.
+type ResponseCacheMode int
+
+const (
+	// ResponseCacheRecord performs real requests and records successful responses.
+	ResponseCacheRecord ResponseCacheMode = iota
+	// ResponseCacheReplay serves responses from the cache only (no network).
+	ResponseCacheReplay
+)
+
+// ResponseCache stores REST responses keyed by request URL so a run can be
+// replayed offline with the same API answers.
+type ResponseCache struct {
+	mu      sync.RWMutex
+	path    string
+	entries map[string]string // URL -> response body
+	dirty   bool
+
+	hits   uint64
+	misses uint64
+}
+
+// NewResponseCache creates an empty cache backed by path.
+func NewResponseCache(path string) *ResponseCache {
+	return &ResponseCache{
+		path:    path,
+		entries: make(map[string]string),
+	}
+}
+
+// LoadResponseCache loads a cache from path. A missing file yields an empty cache.
+func LoadResponseCache(path string) (*ResponseCache, error) {
+	cache := NewResponseCache(path)
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open response cache: %w", err)
+	}
+	defer file.Close()
+
+	var src io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("open response cache gzip: %w", err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	if err := json.NewDecoder(src).Decode(&cache.entries); err != nil {
+		return nil, fmt.Errorf("decode response cache: %w", err)
+	}
+	return cache, nil
+}
+
+// Save writes the cache to disk if it has changed.
+func (rc *ResponseCache) Save() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if !rc.dirty {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	var dst io.Writer = &buf
+	var gz *gzip.Writer
+	if strings.HasSuffix(rc.path, ".gz") {
+		gz = gzip.NewWriter(&buf)
+		dst = gz
+	}
+	if err := json.NewEncoder(dst).Encode(rc.entries); err != nil {
+		return fmt.Errorf("encode response cache: %w", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("close response cache gzip: %w", err)
+		}
+	}
+
+	// Write to a temp file first so a crash can't leave a truncated cache
+	tmp := rc.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write response cache: %w", err)
+	}
+	if err := os.Rename(tmp, rc.path); err != nil {
+		return fmt.Errorf("rename response cache: %w", err)
+	}
+
+	rc.dirty = false
+	return nil
+}
+
+// Get returns the cached body for a URL.
+func (rc *ResponseCache) Get(url string) (string, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	body, ok := rc.entries[url]
+	if ok {
+		rc.hits++
+	} else {
+		rc.misses++
+	}
+	return body, ok
+}
+
+// Put records the body for a URL.
+func (rc *ResponseCache) Put(url, body string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[url] = body
+	rc.dirty = true
+}
+
+// Stats returns the number of entries, cache hits and cache misses.
+func (rc *ResponseCache) Stats() (entries int, hits, misses uint64) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return len(rc.entries), rc.hits, rc.misses
+}
+
+// cacheTransport is an http.RoundTripper that records to or replays from a ResponseCache.
+type cacheTransport struct {
+	base  http.RoundTripper
+	cache *ResponseCache
+	mode  ResponseCacheMode
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.URL.String()
+
+	if t.mode == ResponseCacheReplay {
+		body, ok := t.cache.Get(key)
+		if !ok {
+			return newCachedResponse(req, http.StatusNotFound, `{"error":"not in response cache"}`), nil
+		}
+		return newCachedResponse(req, http.StatusOK, body), nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		t.cache.Put(key, string(body))
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func newCachedResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		Status:        http.StatusText(status),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// UseResponseCache routes all requests through the cache.
+// In record mode, real responses are stored; in replay mode, no network requests are made.
+func (c *PolymarketApiClient) UseResponseCache(cache *ResponseCache, mode ResponseCacheMode) {
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.httpClient.Transport = &cacheTransport{
+		base:  base,
+		cache: cache,
+		mode:  mode,
+	}
+}
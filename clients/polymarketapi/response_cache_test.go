@@ -0,0 +1,117 @@
+package polymarketapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"polybot/config"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestResponseCache_RecordAndReplay(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/positions" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode([]Activity{{ConditionID: "cond1"}, {ConditionID: "cond2"}})
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Polymarket: config.PolymarketConfig{DataAPIURL: server.URL, GammaAPIURL: server.URL}}
+	path := filepath.Join(t.TempDir(), "rest.json.gz")
+
+	// Record
+	recordCache, err := LoadResponseCache(path)
+	if err != nil {
+		t.Fatalf("LoadResponseCache failed: %v", err)
+	}
+	live := NewPolymarketApiClient(zap.NewNop(), cfg)
+	live.UseResponseCache(recordCache, ResponseCacheRecord)
+
+	activity, err := live.GetUserActivity(context.Background(), "0xabc", 10)
+	if err != nil {
+		t.Fatalf("GetUserActivity failed: %v", err)
+	}
+	if len(activity) != 2 {
+		t.Fatalf("expected 2 activities, got %d", len(activity))
+	}
+	if _, err := live.GetPositions(context.Background(), "0xabc", "cond1", 10); err == nil {
+		t.Error("expected error for failed request")
+	}
+	if err := recordCache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Replay with the server closed: only cached responses are served
+	server.Close()
+	before := atomic.LoadInt32(&requests)
+
+	replayCache, err := LoadResponseCache(path)
+	if err != nil {
+		t.Fatalf("LoadResponseCache failed: %v", err)
+	}
+	if entries, _, _ := replayCache.Stats(); entries != 1 {
+		t.Errorf("expected 1 cached entry (errors are not cached), got %d", entries)
+	}
+
+	replay := NewPolymarketApiClient(zap.NewNop(), cfg)
+	replay.UseResponseCache(replayCache, ResponseCacheReplay)
+
+	activity, err = replay.GetUserActivity(context.Background(), "0xabc", 10)
+	if err != nil {
+		t.Fatalf("replayed GetUserActivity failed: %v", err)
+	}
+	if len(activity) != 2 || activity[0].ConditionID != "cond1" {
+		t.Errorf("unexpected replayed activity: %+v", activity)
+	}
+
+	if _, err := replay.GetUserActivity(context.Background(), "0xother", 10); err == nil {
+		t.Error("expected error for uncached request")
+	}
+
+	if atomic.LoadInt32(&requests) != before {
+		t.Error("expected no network requests during replay")
+	}
+
+	_, hits, misses := replayCache.Stats()
+	if hits != 1 || misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got %d/%d", hits, misses)
+	}
+}
+
+func TestLoadResponseCache_Missing(t *testing.T) {
+	cache, err := LoadResponseCache(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("expected no error for missing cache, got %v", err)
+	}
+	if entries, _, _ := cache.Stats(); entries != 0 {
+		t.Errorf("expected empty cache, got %d entries", entries)
+	}
+}
+
+func TestResponseCache_PlainJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rest.json")
+
+	cache := NewResponseCache(path)
+	cache.Put("https://example.com/a", `{"ok":true}`)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadResponseCache(path)
+	if err != nil {
+		t.Fatalf("LoadResponseCache failed: %v", err)
+	}
+	body, ok := loaded.Get("https://example.com/a")
+	if !ok || body != `{"ok":true}` {
+		t.Errorf("unexpected cached body: %q (found=%v)", body, ok)
+	}
+}
@@ -30,6 +30,10 @@ type PolymarketEventsClient struct {
 
 	msgCount        uint64
 	lastMsgUnixNano int64
+
+	// Optional recorder for raw frames (tape mode)
+	recorderMu sync.RWMutex
+	recorder   FrameRecorder
 }
 
 func NewPolymarketEventsClient(logger *zap.Logger) *PolymarketEventsClient {
@@ -134,6 +138,14 @@ func (c *PolymarketEventsClient) UnsubscribeAssets(assetIDs []string) error {
 	return c.sendOp("unsubscribe", assetIDs)
 }
 
+// SetRecorder sets a recorder that receives every raw frame before it is
+// split into events. Pass nil to stop recording.
+func (c *PolymarketEventsClient) SetRecorder(recorder FrameRecorder) {
+	c.recorderMu.Lock()
+	defer c.recorderMu.Unlock()
+	c.recorder = recorder
+}
+
 func (c *PolymarketEventsClient) Messages() <-chan json.RawMessage {
 	return c.msgCh
 }
@@ -176,6 +188,135 @@ func ParseTradeEvent(data json.RawMessage) *TradeEvent {
 	return &event
 }
 
+// OrderSummary is one price level of an order book.
+type OrderSummary struct {
+	Price string `json:"price"`
+	Size  string `json:"size"`
+}
+
+// BookEvent is a full order book snapshot for one token. It is sent when
+// subscribing and after trades that change the book.
+type BookEvent struct {
+	EventType string         `json:"event_type"`
+	AssetID   string         `json:"asset_id"`
+	Market    string         `json:"market"`
+	Bids      []OrderSummary `json:"bids"`
+	Asks      []OrderSummary `json:"asks"`
+	Buys      []OrderSummary `json:"buys"`  // Older name for bids
+	Sells     []OrderSummary `json:"sells"` // Older name for asks
+	Timestamp string         `json:"timestamp"`
+	Hash      string         `json:"hash"`
+}
+
+// ParseBookEvent attempts to parse a JSON message as a BookEvent.
+// Returns nil if the message is not a book snapshot.
+func ParseBookEvent(data json.RawMessage) *BookEvent {
+	var event BookEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil
+	}
+	if event.EventType != "book" || event.AssetID == "" {
+		return nil
+	}
+	if len(event.Bids) == 0 {
+		event.Bids = event.Buys
+	}
+	if len(event.Asks) == 0 {
+		event.Asks = event.Sells
+	}
+	return &event
+}
+
+// GetTimestampUnixMilli returns the timestamp as Unix milliseconds.
+func (e *BookEvent) GetTimestampUnixMilli() int64 {
+	var ts int64
+	fmt.Sscanf(e.Timestamp, "%d", &ts)
+	return ts
+}
+
+// PriceChange sets the size resting at one price level. A size of 0 removes the level.
+type PriceChange struct {
+	AssetID string `json:"asset_id"`
+	Price   string `json:"price"`
+	Size    string `json:"size"`
+	Side    string `json:"side"` // BUY = bid, SELL = ask
+	Hash    string `json:"hash"`
+	BestBid string `json:"best_bid"`
+	BestAsk string `json:"best_ask"`
+}
+
+// PriceChangeEvent carries level updates for one or more tokens of a market.
+type PriceChangeEvent struct {
+	EventType    string        `json:"event_type"`
+	Market       string        `json:"market"`
+	PriceChanges []PriceChange `json:"price_changes"`
+	Timestamp    string        `json:"timestamp"`
+
+	// Older single-token format
+	AssetID string        `json:"asset_id"`
+	Changes []PriceChange `json:"changes"`
+}
+
+// ParsePriceChangeEvent attempts to parse a JSON message as a PriceChangeEvent.
+// Both the current (price_changes) and older (asset_id + changes) formats are
+// returned with every change carrying its asset ID. Returns nil if the message
+// is not a price change.
+func ParsePriceChangeEvent(data json.RawMessage) *PriceChangeEvent {
+	var event PriceChangeEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil
+	}
+	if event.EventType != "price_change" {
+		return nil
+	}
+	for _, c := range event.Changes {
+		if c.AssetID == "" {
+			c.AssetID = event.AssetID
+		}
+		event.PriceChanges = append(event.PriceChanges, c)
+	}
+	event.Changes = nil
+	if len(event.PriceChanges) == 0 {
+		return nil
+	}
+	return &event
+}
+
+// GetTimestampUnixMilli returns the timestamp as Unix milliseconds.
+func (e *PriceChangeEvent) GetTimestampUnixMilli() int64 {
+	var ts int64
+	fmt.Sscanf(e.Timestamp, "%d", &ts)
+	return ts
+}
+
+// GetPriceFloat returns the price as a float64.
+func (l OrderSummary) GetPriceFloat() float64 {
+	var price float64
+	fmt.Sscanf(l.Price, "%f", &price)
+	return price
+}
+
+// GetSizeFloat returns the size as a float64.
+func (l OrderSummary) GetSizeFloat() float64 {
+	var size float64
+	fmt.Sscanf(l.Size, "%f", &size)
+	return size
+}
+
+// GetPriceFloat returns the price as a float64.
+func (c PriceChange) GetPriceFloat() float64 {
+	var price float64
+	fmt.Sscanf(c.Price, "%f", &price)
+	return price
+}
+
+// GetSizeFloat returns the size as a float64.
+func (c PriceChange) GetSizeFloat() float64 {
+	var size float64
+	fmt.Sscanf(c.Size, "%f", &size)
+	return size
+}
+
 // ParseEventType extracts just the event_type from a message for debugging.
 func ParseEventType(data json.RawMessage) string {
 	var m struct {
@@ -356,6 +497,13 @@ func (c *PolymarketEventsClient) readLoop() {
 			)
 		}
 
+		c.recorderMu.RLock()
+		recorder := c.recorder
+		c.recorderMu.RUnlock()
+		if recorder != nil {
+			recorder.Record(b)
+		}
+
 		// The server may send either:
 		// - a single JSON object event
 		// - a JSON array of events (batch)
@@ -364,40 +512,24 @@ func (c *PolymarketEventsClient) readLoop() {
 }
 
 func (c *PolymarketEventsClient) emitFrame(b []byte) {
-	trimmed := b
-	for len(trimmed) > 0 && (trimmed[0] == ' ' || trimmed[0] == '\n' || trimmed[0] == '\t' || trimmed[0] == '\r') {
-		trimmed = trimmed[1:]
-	}
-
-	if len(trimmed) == 0 {
+	events, err := SplitFrame(b)
+	if err != nil {
+		c.logger.Warn(
+			"polymarket ws bad json array frame",
+			zap.Error(err),
+			zap.ByteString("frame", b),
+		)
 		return
 	}
 
-	// Batch case: JSON array
-	if trimmed[0] == '[' {
-		var arr []json.RawMessage
-		if err := json.Unmarshal(trimmed, &arr); err != nil {
-			c.logger.Warn(
-				"polymarket ws bad json array frame",
-				zap.Error(err),
-				zap.ByteString("frame", b),
-			)
-			return
-		}
-
-		if len(arr) == 0 {
-			c.logger.Info("polymarket ws empty batch frame received")
-			return
-		}
-
-		for _, one := range arr {
-			c.forward(one)
-		}
+	if events != nil && len(events) == 0 {
+		c.logger.Info("polymarket ws empty batch frame received")
 		return
 	}
 
-	// Single event case: JSON object (or something else)
-	c.forward(json.RawMessage(append([]byte(nil), trimmed...)))
+	for _, one := range events {
+		c.forward(one)
+	}
 }
 
 func (c *PolymarketEventsClient) forward(msg json.RawMessage) {
@@ -752,3 +752,76 @@ func TestClient_ChannelAccess(t *testing.T) {
 		t.Error("expected message from Messages() channel")
 	}
 }
+
+func TestParseBookEvent(t *testing.T) {
+	data := []byte(`{"event_type": "book", "asset_id": "token1", "market": "0xcond", "bids": [{"price": "0.48", "size": "30"}], "asks": [{"price": "0.52", "size": "25"}], "timestamp": "1700000000000"}`)
+
+	event := ParseBookEvent(data)
+
+	if event == nil {
+		t.Fatal("expected non-nil event")
+	}
+	if len(event.Bids) != 1 || event.Bids[0].GetPriceFloat() != 0.48 || event.Bids[0].GetSizeFloat() != 30 {
+		t.Errorf("unexpected bids: %+v", event.Bids)
+	}
+	if len(event.Asks) != 1 || event.Asks[0].GetPriceFloat() != 0.52 {
+		t.Errorf("unexpected asks: %+v", event.Asks)
+	}
+	if event.GetTimestampUnixMilli() != 1700000000000 {
+		t.Errorf("unexpected timestamp: %d", event.GetTimestampUnixMilli())
+	}
+}
+
+func TestParseBookEvent_LegacyBuysSells(t *testing.T) {
+	data := []byte(`{"event_type": "book", "asset_id": "token1", "buys": [{"price": "0.48", "size": "30"}], "sells": [{"price": "0.52", "size": "25"}]}`)
+
+	event := ParseBookEvent(data)
+
+	if event == nil || len(event.Bids) != 1 || len(event.Asks) != 1 {
+		t.Fatalf("expected buys and sells mapped to bids and asks, got %+v", event)
+	}
+}
+
+func TestParseBookEvent_NonBookEvent(t *testing.T) {
+	for _, data := range []string{
+		`{"event_type": "trade", "asset_id": "token1"}`,
+		`{"event_type": "book"}`,
+		`not valid json`,
+	} {
+		if event := ParseBookEvent([]byte(data)); event != nil {
+			t.Errorf("expected nil for %s", data)
+		}
+	}
+}
+
+func TestParsePriceChangeEvent(t *testing.T) {
+	data := []byte(`{"event_type": "price_change", "market": "0xcond", "price_changes": [{"asset_id": "token1", "price": "0.5", "size": "200", "side": "BUY"}, {"asset_id": "token2", "price": "0.5", "size": "0", "side": "SELL"}]}`)
+
+	event := ParsePriceChangeEvent(data)
+
+	if event == nil || len(event.PriceChanges) != 2 {
+		t.Fatalf("expected 2 price changes, got %+v", event)
+	}
+	if c := event.PriceChanges[1]; c.AssetID != "token2" || c.GetSizeFloat() != 0 || c.Side != "SELL" {
+		t.Errorf("unexpected change: %+v", c)
+	}
+}
+
+func TestParsePriceChangeEvent_LegacyFormat(t *testing.T) {
+	data := []byte(`{"event_type": "price_change", "asset_id": "token1", "changes": [{"price": "0.4", "size": "100", "side": "BUY"}]}`)
+
+	event := ParsePriceChangeEvent(data)
+
+	if event == nil || len(event.PriceChanges) != 1 {
+		t.Fatalf("expected 1 price change, got %+v", event)
+	}
+	if c := event.PriceChanges[0]; c.AssetID != "token1" || c.GetPriceFloat() != 0.4 {
+		t.Errorf("expected change to carry the event's asset ID, got %+v", c)
+	}
+}
+
+func TestParsePriceChangeEvent_Empty(t *testing.T) {
+	if event := ParsePriceChangeEvent([]byte(`{"event_type": "price_change", "asset_id": "token1"}`)); event != nil {
+		t.Error("expected nil for a price change without changes")
+	}
+}
@@ -0,0 +1,238 @@
+package polymarketevents
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// tapeFlushInterval bounds how much of a tape can be lost on a crash.
+const tapeFlushInterval = time.Second
+
+// FrameRecorder receives every raw frame read from the WebSocket.
+type FrameRecorder interface {
+	Record(frame []byte)
+}
+
+// TapeEntry is a single line of a tape: a raw frame and when it was received.
+type TapeEntry struct {
+	Timestamp time.Time       `json:"ts"`
+	Frame     json.RawMessage `json:"frame"`
+}
+
+// Bytes returns the raw frame as it was received.
+// Non-JSON frames are stored as JSON strings and are unwrapped here.
+func (e *TapeEntry) Bytes() []byte {
+	if len(e.Frame) > 0 && e.Frame[0] == '"' {
+		var s string
+		if err := json.Unmarshal(e.Frame, &s); err == nil {
+			return []byte(s)
+		}
+	}
+	return e.Frame
+}
+
+// TapeRecorder writes raw frames to a gzip-compressed JSONL tape on disk.
+type TapeRecorder struct {
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	path      string
+	file      *os.File
+	gz        *gzip.Writer
+	enc       *json.Encoder
+	count     uint64
+	lastFlush time.Time
+	closed    bool
+}
+
+// NewTapeRecorder creates a new tape in dir, named after the current time
+// (e.g., tape-20240102T150405Z.jsonl.gz).
+func NewTapeRecorder(logger *zap.Logger, dir string) (*TapeRecorder, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create tape dir: %w", err)
+	}
+
+	name := fmt.Sprintf("tape-%s.jsonl.gz", time.Now().UTC().Format("20060102T150405Z"))
+	path := filepath.Join(dir, name)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create tape: %w", err)
+	}
+
+	gz := gzip.NewWriter(file)
+	logger.Info("recording websocket tape", zap.String("path", path))
+
+	return &TapeRecorder{
+		logger:    logger,
+		path:      path,
+		file:      file,
+		gz:        gz,
+		enc:       json.NewEncoder(gz),
+		lastFlush: time.Now(),
+	}, nil
+}
+
+// Path returns the tape's file path.
+func (r *TapeRecorder) Path() string {
+	return r.path
+}
+
+// Count returns the number of frames recorded.
+func (r *TapeRecorder) Count() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Record appends a frame to the tape.
+func (r *TapeRecorder) Record(frame []byte) {
+	entry := TapeEntry{Timestamp: time.Now().UTC()}
+	if json.Valid(frame) {
+		entry.Frame = append(json.RawMessage(nil), frame...)
+	} else {
+		quoted, _ := json.Marshal(string(frame))
+		entry.Frame = quoted
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	if err := r.enc.Encode(entry); err != nil {
+		r.logger.Warn("failed to record tape frame", zap.Error(err))
+		return
+	}
+	r.count++
+
+	if time.Since(r.lastFlush) >= tapeFlushInterval {
+		if err := r.gz.Flush(); err != nil {
+			r.logger.Warn("failed to flush tape", zap.Error(err))
+		}
+		r.lastFlush = time.Now()
+	}
+}
+
+// Close flushes and closes the tape.
+func (r *TapeRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	if err := r.gz.Close(); err != nil {
+		_ = r.file.Close()
+		return fmt.Errorf("close tape gzip: %w", err)
+	}
+	return r.file.Close()
+}
+
+// TapeReader reads entries from a tape written by TapeRecorder.
+// Plain (uncompressed) .jsonl tapes are also accepted.
+type TapeReader struct {
+	file    *os.File
+	gz      *gzip.Reader
+	scanner *bufio.Scanner
+}
+
+// OpenTape opens a tape for reading.
+func OpenTape(path string) (*TapeReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open tape: %w", err)
+	}
+
+	reader := &TapeReader{file: file}
+	var src io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("open tape gzip: %w", err)
+		}
+		reader.gz = gz
+		src = gz
+	}
+
+	reader.scanner = bufio.NewScanner(src)
+	// Frames can be large batches of book snapshots
+	reader.scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	return reader, nil
+}
+
+// Next returns the next entry, or io.EOF at the end of the tape.
+func (r *TapeReader) Next() (*TapeEntry, error) {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry TapeEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("decode tape entry: %w", err)
+		}
+		return &entry, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		// A truncated gzip stream (e.g., the recorder crashed) ends the tape
+		if err == io.ErrUnexpectedEOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("read tape: %w", err)
+	}
+	return nil, io.EOF
+}
+
+// Close closes the tape.
+func (r *TapeReader) Close() error {
+	if r.gz != nil {
+		_ = r.gz.Close()
+	}
+	return r.file.Close()
+}
+
+// SplitFrame splits a raw frame into individual events.
+// The server may send either a single JSON object or a JSON array of events.
+func SplitFrame(b []byte) ([]json.RawMessage, error) {
+	trimmed := b
+	for len(trimmed) > 0 && (trimmed[0] == ' ' || trimmed[0] == '\n' || trimmed[0] == '\t' || trimmed[0] == '\r') {
+		trimmed = trimmed[1:]
+	}
+
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	// Batch case: JSON array
+	if trimmed[0] == '[' {
+		var arr []json.RawMessage
+		if err := json.Unmarshal(trimmed, &arr); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	}
+
+	// Single event case: JSON object (or something else)
+	return []json.RawMessage{append(json.RawMessage(nil), trimmed...)}, nil
+}
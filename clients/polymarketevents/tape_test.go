@@ -0,0 +1,160 @@
+package polymarketevents
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestTapeRecorder_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder, err := NewTapeRecorder(zap.NewNop(), dir)
+	if err != nil {
+		t.Fatalf("NewTapeRecorder failed: %v", err)
+	}
+	if !strings.HasSuffix(recorder.Path(), ".jsonl.gz") {
+		t.Errorf("expected .jsonl.gz tape, got %s", recorder.Path())
+	}
+
+	recorder.Record([]byte(`{"event_type":"trade","asset_id":"a1"}`))
+	recorder.Record([]byte(`[{"event_type":"book"},{"event_type":"trade"}]`))
+	recorder.Record([]byte(`not json`))
+
+	if recorder.Count() != 3 {
+		t.Errorf("expected 3 recorded frames, got %d", recorder.Count())
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Recording after close is a no-op
+	recorder.Record([]byte(`{}`))
+
+	tape, err := OpenTape(recorder.Path())
+	if err != nil {
+		t.Fatalf("OpenTape failed: %v", err)
+	}
+	defer tape.Close()
+
+	expected := []string{
+		`{"event_type":"trade","asset_id":"a1"}`,
+		`[{"event_type":"book"},{"event_type":"trade"}]`,
+		`not json`,
+	}
+	for i, want := range expected {
+		entry, err := tape.Next()
+		if err != nil {
+			t.Fatalf("entry %d: Next failed: %v", i, err)
+		}
+		if string(entry.Bytes()) != want {
+			t.Errorf("entry %d: expected %s, got %s", i, want, entry.Bytes())
+		}
+		if entry.Timestamp.IsZero() {
+			t.Errorf("entry %d: expected timestamp", i)
+		}
+	}
+
+	if _, err := tape.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF at end of tape, got %v", err)
+	}
+}
+
+func TestTapeRecorder_CloseTwice(t *testing.T) {
+	recorder, err := NewTapeRecorder(nil, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTapeRecorder failed: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("first Close failed: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Errorf("second Close should be a no-op, got %v", err)
+	}
+}
+
+func TestOpenTape_PlainJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tape.jsonl")
+	content := `{"ts":"2024-01-02T15:04:05Z","frame":{"event_type":"trade"}}` + "\n\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tape, err := OpenTape(path)
+	if err != nil {
+		t.Fatalf("OpenTape failed: %v", err)
+	}
+	defer tape.Close()
+
+	entry, err := tape.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if string(entry.Bytes()) != `{"event_type":"trade"}` {
+		t.Errorf("unexpected frame: %s", entry.Bytes())
+	}
+	if _, err := tape.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestOpenTape_Missing(t *testing.T) {
+	if _, err := OpenTape(filepath.Join(t.TempDir(), "missing.jsonl.gz")); err == nil {
+		t.Error("expected error for missing tape")
+	}
+}
+
+func TestSplitFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		frame    string
+		expected int
+		wantErr  bool
+	}{
+		{"empty", "", 0, false},
+		{"whitespace", " \n\t", 0, false},
+		{"object", `{"event_type":"trade"}`, 1, false},
+		{"array", `[{"a":1},{"b":2},{"c":3}]`, 3, false},
+		{"empty array", `[]`, 0, false},
+		{"bad array", `[{"a":1},`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := SplitFrame([]byte(tt.frame))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != tt.expected {
+				t.Errorf("expected %d events, got %d", tt.expected, len(events))
+			}
+		})
+	}
+}
+
+type captureRecorder struct {
+	frames [][]byte
+}
+
+func (c *captureRecorder) Record(frame []byte) {
+	c.frames = append(c.frames, frame)
+}
+
+func TestSetRecorder(t *testing.T) {
+	client := NewPolymarketEventsClient(nil)
+	recorder := &captureRecorder{}
+
+	client.SetRecorder(recorder)
+	if client.recorder != recorder {
+		t.Error("expected recorder to be set")
+	}
+
+	client.SetRecorder(nil)
+	if client.recorder != nil {
+		t.Error("expected recorder to be cleared")
+	}
+}
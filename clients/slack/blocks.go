@@ -0,0 +1,561 @@
+package slack
+
+import (
+	"fmt"
+	"polybot/clients/notifier"
+	"strings"
+	"time"
+)
+
+// slackMessage is a chat.postMessage / incoming webhook payload.
+type slackMessage struct {
+	Channel     string       `json:"channel,omitempty"`
+	Text        string       `json:"text"` // Fallback for notifications and clients without Block Kit
+	Blocks      []slackBlock `json:"blocks"`
+	UnfurlLinks bool         `json:"unfurl_links"`
+	UnfurlMedia bool         `json:"unfurl_media"`
+}
+
+// slackBlock is a Block Kit layout block (header, section, context or divider).
+type slackBlock struct {
+	Type      string          `json:"type"`
+	Text      *slackText      `json:"text,omitempty"`
+	Fields    []*slackText    `json:"fields,omitempty"`
+	Elements  []*slackText    `json:"elements,omitempty"`
+	Accessory *slackAccessory `json:"accessory,omitempty"`
+}
+
+// slackText is a Block Kit text object.
+type slackText struct {
+	Type  string `json:"type"` // plain_text or mrkdwn
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// slackAccessory is an image element shown beside a section.
+type slackAccessory struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// Block Kit limits
+const (
+	maxHeaderLen = 150
+	maxFieldLen  = 2000
+)
+
+func mrkdwn(text string) *slackText {
+	return &slackText{Type: "mrkdwn", Text: text}
+}
+
+func (sc *SlackClient) buildTradeMessage(alert notifier.TradeAlert) slackMessage {
+	sideEmoji := "🟢"
+	if strings.ToUpper(alert.Side) == "SELL" {
+		sideEmoji = "🔴"
+	}
+
+	// Build title based on alert reasons
+	title := sc.buildAlertTitle(alert.Reasons)
+	if alert.IsUpdate {
+		title = "🔁 Update: " + title
+	}
+
+	// Format trader display with link
+	traderDisplay := alert.TraderName
+	if alert.TraderAddress != "" {
+		shortAddr := shortAddress(alert.TraderAddress)
+		if traderDisplay != shortAddr {
+			traderDisplay = fmt.Sprintf("%s (%s)", alert.TraderName, shortAddr)
+		}
+	}
+	traderDisplay = escapeMrkdwn(traderDisplay)
+	if alert.WalletURL != "" {
+		traderDisplay = fmt.Sprintf("<%s|%s>", alert.WalletURL, traderDisplay)
+	}
+
+	// Format trade info
+	tradeInfo := fmt.Sprintf("%.2f shares @ $%.3f", alert.Shares, alert.Price)
+	if alert.MergedFills > 1 {
+		tradeInfo = fmt.Sprintf("%d fills: %.2f shares @ $%.3f avg", alert.MergedFills, alert.Shares, alert.Price)
+	} else if len(alert.Fills) > 1 {
+		tradeInfo = fmt.Sprintf("%.2f shares @ $%.3f VWAP (%d maker fills)", alert.Shares, alert.Price, len(alert.Fills))
+	}
+
+	// Format win rate
+	winRateStr := "N/A"
+	if alert.WinCount+alert.LossCount > 0 {
+		winRateStr = fmt.Sprintf("%.1f%% (%d-%d)", alert.WinRate*100, alert.WinCount, alert.LossCount)
+	}
+
+	// Format inventory info
+	inventoryStr := "N/A"
+	if alert.HasInventory {
+		if alert.InventoryShares > 0 {
+			// Show current position after this trade (estimated due to API lag)
+			inventoryStr = fmt.Sprintf("~%.2f shares @ $%.3f avg (est.)\nValue: ~$%.2f",
+				alert.InventoryShares, alert.InventoryAvgPrice, alert.InventoryValue)
+		} else if alert.HasClosedInfo {
+			// Position was fully closed - show cost basis and P&L
+			pnlSign := "+"
+			if alert.ClosedRealizedPnl < 0 {
+				pnlSign = ""
+			}
+			inventoryStr = fmt.Sprintf("Closed position\nCost basis: $%.3f\nRealized P&L: %s$%.2f",
+				alert.ClosedCostBasis, pnlSign, alert.ClosedRealizedPnl)
+		} else {
+			inventoryStr = "0 shares (closed)"
+		}
+	}
+
+	// Market info, with the market image beside it
+	marketTitle := escapeMrkdwn(alert.MarketTitle)
+	if alert.MarketURL != "" {
+		marketTitle = fmt.Sprintf("<%s|%s>", alert.MarketURL, marketTitle)
+	}
+	market := slackBlock{
+		Type: "section",
+		Text: mrkdwn(fmt.Sprintf("*%s*\nOutcome: %s", marketTitle, escapeMrkdwn(alert.Outcome))),
+	}
+	if alert.MarketImage != "" {
+		market.Accessory = &slackAccessory{Type: "image", ImageURL: alert.MarketImage, AltText: "market"}
+	}
+
+	blocks := []slackBlock{
+		{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: truncate(title, maxHeaderLen), Emoji: true},
+		},
+		market,
+		{
+			Type: "section",
+			Fields: []*slackText{
+				mrkdwn("*Trader*\n" + traderDisplay),
+				mrkdwn(fmt.Sprintf("*Side*\n%s %s", sideEmoji, escapeMrkdwn(alert.Side))),
+				mrkdwn("*Trade*\n" + tradeInfo),
+				mrkdwn(fmt.Sprintf("*Notional*\n$%.2f", alert.Notional)),
+				mrkdwn("*Position After (est.)*\n" + inventoryStr),
+				mrkdwn("*Win Rate (resolved)*\n" + winRateStr),
+			},
+		},
+	}
+
+	// Pattern details
+	var details []*slackText
+	if alert.HasHedgeInfo {
+		details = append(details, mrkdwn(fmt.Sprintf("*🛡️ Hedge*\nBefore: %.0f Yes / %.0f No\nAfter: %.0f Yes / %.0f No\nSold %s: %.0f%%",
+			alert.HedgeYesSizeBefore, alert.HedgeNoSizeBefore,
+			alert.HedgeYesSizeAfter, alert.HedgeNoSizeAfter,
+			escapeMrkdwn(alert.HedgeSoldSide), alert.HedgeSoldPct*100)))
+	}
+	if alert.HasResolutionInfo {
+		result := "Kept the losing side"
+		if alert.ResolutionRemovedLoser {
+			result = "Removed the losing side ✅"
+		}
+		details = append(details, mrkdwn(fmt.Sprintf("*⚠️ Resolution*\nWinner: %s\n%s",
+			escapeMrkdwn(alert.ResolutionWinner), result)))
+	}
+	if alert.HasConvictionInfo {
+		details = append(details, mrkdwn(fmt.Sprintf("*💪 Conviction*\nExisting: %.0f @ $%.3f\nNow $%.3f (-%.1f%%)\nAdded: %.0f ($%.2f)",
+			alert.ConvictionExistingSize, alert.ConvictionExistingAvg,
+			alert.ConvictionCurrentPrice, alert.ConvictionLossPct*100,
+			alert.ConvictionAddedSize, alert.ConvictionAddedValue)))
+	}
+	if alert.HasStealthInfo {
+		details = append(details, mrkdwn(fmt.Sprintf("*🥷 Stealth Accumulation*\n%d trades over %d min\n%.0f shares @ $%.3f avg ($%.2f)",
+			alert.StealthTradeCount, alert.StealthSpreadMins,
+			alert.StealthTotalSize, alert.StealthAvgPrice, alert.StealthTotalValue)))
+	}
+	if alert.TotalFills > 1 {
+		details = append(details, mrkdwn(fmt.Sprintf("*🔁 Running Total*\n%d fills\n%.2f shares ($%.2f)",
+			alert.TotalFills, alert.TotalShares, alert.TotalNotional)))
+	}
+	if alert.HasImpactInfo {
+		details = append(details, mrkdwn("*💥 Market Impact*\n"+strings.ReplaceAll(alert.ImpactSummary(), " · ", "\n")))
+	}
+	if alert.HasBookInfo {
+		details = append(details, mrkdwn("*📖 Order Book*\n"+strings.ReplaceAll(alert.BookSummary(), " · ", "\n")))
+	}
+	if alert.WalletClusterID != "" {
+		details = append(details, mrkdwn("*🔗 Wallet Cluster*\n"+escapeMrkdwn(strings.ReplaceAll(alert.WalletClusterSummary(), " · ", "\n"))))
+	}
+	if alert.HasPreMoveInfo {
+		details = append(details, mrkdwn(fmt.Sprintf("*🎯 Pre-Move*\n%d/%d trades before moves\nAlpha: %.0f%%\nAvg move: %.1f¢",
+			alert.PreMoveSuccessfulMoves, alert.PreMoveTotalTrades,
+			alert.PreMoveAlphaScore*100, alert.PreMoveAvgMoveSize*100)))
+	}
+	if len(details) > 0 {
+		for _, d := range details {
+			d.Text = truncate(d.Text, maxFieldLen)
+		}
+		blocks = append(blocks, slackBlock{Type: "divider"}, slackBlock{Type: "section", Fields: details})
+	}
+	if alert.HasClusterInfo {
+		lines := []string{escapeMrkdwn(alert.ClusterSummary())}
+		for _, w := range alert.ClusterWallets {
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			name = escapeMrkdwn(name)
+			if w.URL != "" {
+				name = fmt.Sprintf("<%s|%s>", w.URL, name)
+			}
+			lines = append(lines, fmt.Sprintf("• %s: $%.0f, %.0f shares, %d markets", name, w.Notional, w.Shares, w.UniqueMarkets))
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: mrkdwn(truncate("*👥 Coordinated Entry*\n"+strings.Join(lines, "\n"), maxFieldLen))})
+	}
+
+	// Footer timestamp (PST)
+	pst, _ := time.LoadLocation("America/Los_Angeles")
+	ts := alert.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	blocks = append(blocks, slackBlock{
+		Type:     "context",
+		Elements: []*slackText{mrkdwn(fmt.Sprintf("polybot • %s", ts.In(pst).Format("1/2/2006, 3:04:05PM (MST)")))},
+	})
+
+	return slackMessage{
+		Text:   fmt.Sprintf("%s: %s %s $%.2f on %s", title, alert.TraderName, alert.Side, alert.Notional, alert.MarketTitle),
+		Blocks: blocks,
+	}
+}
+
+func (sc *SlackClient) buildAlertTitle(reasons []notifier.AlertReason) string {
+	hasLowActivity := false
+	hasHighWinRate := false
+	hasExtremeBet := false
+	hasRapidTrading := false
+	hasNewWallet := false
+	hasContrarianBet := false
+	hasMassiveTrade := false
+	hasContrarianWinner := false
+	hasCopyTrader := false
+	hasHedgeRemoval := false
+	hasAsymmetricExit := false
+	hasResolutionConfirmed := false
+	hasConvictionDoubling := false
+	hasPerfectExitTiming := false
+	hasStealthAccumulation := false
+	hasPreMovePositioning := false
+	hasMarketImpact := false
+	hasCoordinatedEntry := false
+
+	for _, r := range reasons {
+		switch r {
+		case notifier.AlertReasonLowActivity:
+			hasLowActivity = true
+		case notifier.AlertReasonHighWinRate:
+			hasHighWinRate = true
+		case notifier.AlertReasonExtremeBet:
+			hasExtremeBet = true
+		case notifier.AlertReasonRapidTrading:
+			hasRapidTrading = true
+		case notifier.AlertReasonNewWallet:
+			hasNewWallet = true
+		case notifier.AlertReasonContrarianBet:
+			hasContrarianBet = true
+		case notifier.AlertReasonMassiveTrade:
+			hasMassiveTrade = true
+		case notifier.AlertReasonContrarianWinner:
+			hasContrarianWinner = true
+		case notifier.AlertReasonCopyTrader:
+			hasCopyTrader = true
+		case notifier.AlertReasonHedgeRemoval:
+			hasHedgeRemoval = true
+		case notifier.AlertReasonAsymmetricExit:
+			hasAsymmetricExit = true
+		case notifier.AlertReasonResolutionConfirmed:
+			hasResolutionConfirmed = true
+		case notifier.AlertReasonConvictionDoubling:
+			hasConvictionDoubling = true
+		case notifier.AlertReasonPerfectExitTiming:
+			hasPerfectExitTiming = true
+		case notifier.AlertReasonStealthAccumulation:
+			hasStealthAccumulation = true
+		case notifier.AlertReasonPreMovePositioning:
+			hasPreMovePositioning = true
+		case notifier.AlertReasonMarketImpact:
+			hasMarketImpact = true
+		case notifier.AlertReasonCoordinatedEntry:
+			hasCoordinatedEntry = true
+		}
+	}
+
+	// Count active reasons for multi-flag handling
+	count := 0
+	if hasLowActivity {
+		count++
+	}
+	if hasHighWinRate {
+		count++
+	}
+	if hasExtremeBet {
+		count++
+	}
+	if hasRapidTrading {
+		count++
+	}
+	if hasNewWallet {
+		count++
+	}
+	if hasContrarianBet {
+		count++
+	}
+	if hasMassiveTrade {
+		count++
+	}
+	if hasContrarianWinner {
+		count++
+	}
+	if hasCopyTrader {
+		count++
+	}
+	if hasHedgeRemoval {
+		count++
+	}
+	if hasAsymmetricExit {
+		count++
+	}
+	if hasResolutionConfirmed {
+		count++
+	}
+	if hasConvictionDoubling {
+		count++
+	}
+	if hasPerfectExitTiming {
+		count++
+	}
+	if hasStealthAccumulation {
+		count++
+	}
+	if hasPreMovePositioning {
+		count++
+	}
+	if hasMarketImpact {
+		count++
+	}
+	if hasCoordinatedEntry {
+		count++
+	}
+
+	// For 3+ reasons, use generic multi-alert title
+	if count >= 3 {
+		return "🚨 Multiple Alert Triggers"
+	}
+
+	// Build title based on combinations (two reasons)
+	if hasMassiveTrade && hasHighWinRate {
+		return "🐋 Massive Trade + High Win Rate"
+	}
+	if hasMassiveTrade && hasLowActivity {
+		return "🐋 Massive Trade + Low Activity"
+	}
+	if hasMassiveTrade && hasNewWallet {
+		return "🐋 Massive Trade + New Wallet"
+	}
+	if hasContrarianBet && hasNewWallet {
+		return "🔄 Contrarian + New Wallet Bet"
+	}
+	if hasContrarianBet && hasLowActivity {
+		return "🔄 Contrarian + Low Activity Bet"
+	}
+	if hasContrarianBet && hasHighWinRate {
+		return "🔄 Contrarian + High Win Rate Bet"
+	}
+	if hasNewWallet && hasExtremeBet {
+		return "🆕 New Wallet + Extreme Odds Bet"
+	}
+	if hasNewWallet && hasLowActivity {
+		return "🆕 New Wallet + Low Activity"
+	}
+	if hasLowActivity && hasHighWinRate {
+		return "🚨 Low Activity + High Win Rate"
+	}
+	if hasLowActivity && hasExtremeBet {
+		return "🚨 Low Activity + Extreme Odds Bet"
+	}
+	if hasLowActivity && hasRapidTrading {
+		return "🚨 Low Activity + Rapid Trading"
+	}
+	if hasHighWinRate && hasExtremeBet {
+		return "🎯 High Win Rate + Extreme Odds Bet"
+	}
+	if hasHighWinRate && hasRapidTrading {
+		return "🎯 High Win Rate + Rapid Trading"
+	}
+	if hasExtremeBet && hasRapidTrading {
+		return "⚡ Extreme Odds + Rapid Trading"
+	}
+
+	// Contrarian winner combos (high priority - proven track record)
+	if hasContrarianWinner && hasContrarianBet {
+		return "🏆 Proven Contrarian + Another Contrarian Bet"
+	}
+	if hasContrarianWinner && hasMassiveTrade {
+		return "🏆 Proven Contrarian + Massive Trade"
+	}
+
+	// Copy trader combos
+	if hasCopyTrader && hasContrarianWinner {
+		return "🔍 Copy Trader Following Contrarian Winner"
+	}
+	if hasCopyTrader && hasHighWinRate {
+		return "🔍 Copy Trader with High Win Rate"
+	}
+
+	// Hedge-related combos (high priority - potential insider activity)
+	if hasResolutionConfirmed {
+		return "⚠️ Hedge Removal Confirmed"
+	}
+	if hasHedgeRemoval && hasAsymmetricExit {
+		return "🛡️ Hedge Removal + Asymmetric Exit Pattern"
+	}
+	if hasHedgeRemoval && hasHighWinRate {
+		return "🛡️ Hedge Removal + High Win Rate"
+	}
+	if hasAsymmetricExit && hasHighWinRate {
+		return "📊 Asymmetric Exit + High Win Rate"
+	}
+
+	// Advanced pattern combos (high priority - sophisticated insider signals)
+	if hasConvictionDoubling && hasHighWinRate {
+		return "💪 Conviction Doubling + High Win Rate"
+	}
+	if hasConvictionDoubling && hasContrarianWinner {
+		return "💪 Conviction Doubling + Proven Contrarian"
+	}
+	if hasPerfectExitTiming && hasHighWinRate {
+		return "⏱️ Perfect Exit Timing + High Win Rate"
+	}
+	if hasPerfectExitTiming && hasAsymmetricExit {
+		return "⏱️ Perfect Exit Timing + Asymmetric Exit"
+	}
+	if hasStealthAccumulation && hasLowActivity {
+		return "🥷 Stealth Accumulation + Low Activity"
+	}
+	if hasStealthAccumulation && hasContrarianBet {
+		return "🥷 Stealth Accumulation + Contrarian Bet"
+	}
+	if hasStealthAccumulation && hasHighWinRate {
+		return "🥷 Stealth Accumulation + High Win Rate"
+	}
+
+	// Pre-Move Positioning combos (high priority - demonstrated alpha)
+	if hasPreMovePositioning && hasHighWinRate {
+		return "🎯 Pre-Move Positioning + High Win Rate"
+	}
+	if hasPreMovePositioning && hasContrarianWinner {
+		return "🎯 Pre-Move Positioning + Proven Contrarian"
+	}
+	if hasPreMovePositioning && hasMassiveTrade {
+		return "🎯 Pre-Move Positioning + Massive Trade"
+	}
+
+	// Market impact combos (size relative to the market)
+	if hasMarketImpact && hasHighWinRate {
+		return "💥 Market Impact + High Win Rate"
+	}
+	if hasMarketImpact && hasNewWallet {
+		return "💥 Market Impact + New Wallet"
+	}
+	if hasMarketImpact && hasLowActivity {
+		return "💥 Market Impact + Low Activity"
+	}
+	if hasMarketImpact && hasMassiveTrade {
+		return "💥 Massive Trade + Market Impact"
+	}
+
+	// Coordinated entry combos (fresh wallets buying together)
+	if hasCoordinatedEntry && hasLowActivity {
+		return "👥 Coordinated Entry + Low Activity"
+	}
+	if hasCoordinatedEntry && hasNewWallet {
+		return "👥 Coordinated Entry + New Wallet"
+	}
+
+	// Single reasons - Advanced patterns (highest priority)
+	if hasCoordinatedEntry {
+		return "👥 Coordinated Entry"
+	}
+	if hasPreMovePositioning {
+		return "🎯 Pre-Move Positioning"
+	}
+	if hasPerfectExitTiming {
+		return "⏱️ Perfect Exit Timing"
+	}
+	if hasConvictionDoubling {
+		return "💪 Conviction Doubling"
+	}
+	if hasStealthAccumulation {
+		return "🥷 Stealth Accumulation"
+	}
+
+	// Single reasons - Hedge patterns
+	if hasHedgeRemoval {
+		return "🛡️ Hedge Removal Detected"
+	}
+	if hasAsymmetricExit {
+		return "📊 Asymmetric Exit Pattern"
+	}
+	if hasCopyTrader {
+		return "🔍 Copy Trader Detected"
+	}
+	if hasContrarianWinner {
+		return "🏆 Proven Contrarian Winner"
+	}
+	if hasMarketImpact {
+		return "💥 Outsized Market Impact"
+	}
+	if hasMassiveTrade {
+		return "🐋 Massive Trade"
+	}
+	if hasContrarianBet {
+		return "🔄 Contrarian Large Bet"
+	}
+	if hasNewWallet {
+		return "🆕 New Wallet Large Bet"
+	}
+	if hasRapidTrading {
+		return "⚡ Rapid Trading Detected"
+	}
+	if hasExtremeBet {
+		return "💰 Extreme Odds Bet"
+	}
+	if hasHighWinRate {
+		return "🎯 High Win Rate Trader"
+	}
+	if hasLowActivity {
+		return "🚨 Low Activity Wallet"
+	}
+	return "🚨 Trade Alert"
+}
+
+func shortAddress(addr string) string {
+	if len(addr) <= 14 {
+		return addr
+	}
+	return addr[:6] + "…" + addr[len(addr)-6:]
+}
+
+// escapeMrkdwn escapes the control characters Slack requires in mrkdwn text.
+func escapeMrkdwn(s string) string {
+	replacer := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+	return replacer.Replace(s)
+}
+
+// truncate shortens s to max runes, marking the cut with an ellipsis.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,113 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"polybot/clients/notifier"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SendDigest sends a Block Kit alert digest.
+// Implements notifier.DigestNotifier interface.
+func (sc *SlackClient) SendDigest(digest notifier.AlertDigest) {
+	if !sc.IsEnabled() {
+		sc.logger.Warn("slack not configured, skipping digest")
+		return
+	}
+
+	if err := sc.send(context.Background(), sc.buildDigestMessage(digest)); err != nil {
+		sc.logger.Error("failed to send slack digest", zap.Error(err))
+		return
+	}
+
+	sc.logger.Info("sent slack alert digest", zap.Int("alerts", digest.TotalAlerts))
+}
+
+func (sc *SlackClient) buildDigestMessage(digest notifier.AlertDigest) slackMessage {
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: "📊 Alert Digest", Emoji: true}},
+		{Type: "section", Text: mrkdwn(fmt.Sprintf("*%d alerts* in the last %s\nLast hour: %d · 24h: %d · 7d: %d",
+			digest.TotalAlerts, digest.WindowLabel(),
+			digest.AlertsLastHour, digest.AlertsLastDay, digest.AlertsLastWeek))},
+	}
+
+	var fields []*slackText
+
+	if len(digest.ReasonCounts) > 0 {
+		var lines []string
+		for _, rc := range digest.ReasonCounts {
+			lines = append(lines, fmt.Sprintf("`%s` %d", rc.Reason, rc.Count))
+		}
+		fields = append(fields, mrkdwn("*📋 By Reason*\n"+strings.Join(lines, "\n")))
+	}
+
+	if len(digest.TopWallets) > 0 {
+		var lines []string
+		for _, w := range digest.TopWallets {
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			name = escapeMrkdwn(name)
+			if w.URL != "" {
+				name = fmt.Sprintf("<%s|%s>", w.URL, name)
+			}
+			lines = append(lines, fmt.Sprintf("%s: %d alerts, $%.0f", name, w.Count, w.Notional))
+		}
+		fields = append(fields, mrkdwn("*👛 Top Wallets*\n"+strings.Join(lines, "\n")))
+	}
+
+	if len(fields) > 0 {
+		for _, f := range fields {
+			f.Text = truncate(f.Text, maxFieldLen)
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Fields: fields})
+	}
+
+	if len(digest.TopMarkets) > 0 {
+		var lines []string
+		for _, m := range digest.TopMarkets {
+			title := m.Title
+			if title == "" {
+				title = shortAddress(m.ConditionID)
+			}
+			title = escapeMrkdwn(title)
+			if m.URL != "" {
+				title = fmt.Sprintf("<%s|%s>", m.URL, title)
+			}
+			lines = append(lines, fmt.Sprintf("• %s: %d alerts, $%.0f", title, m.Count, m.Notional))
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: mrkdwn(truncate("*📈 Top Markets*\n"+strings.Join(lines, "\n"), maxFieldLen))})
+	}
+
+	if len(digest.LargestTrades) > 0 {
+		var lines []string
+		for _, a := range digest.LargestTrades {
+			sideEmoji := "🟢"
+			if strings.ToUpper(a.Side) == "SELL" {
+				sideEmoji = "🔴"
+			}
+			market := escapeMrkdwn(a.MarketTitle)
+			if a.MarketURL != "" {
+				market = fmt.Sprintf("<%s|%s>", a.MarketURL, market)
+			}
+			lines = append(lines, fmt.Sprintf("%s *$%.0f* %s @ $%.3f: %s", sideEmoji, a.Notional, escapeMrkdwn(a.Outcome), a.Price, market))
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: mrkdwn(truncate("*💰 Largest Trades*\n"+strings.Join(lines, "\n"), maxFieldLen))})
+	}
+
+	// Footer timestamp (PST)
+	pst, _ := time.LoadLocation("America/Los_Angeles")
+	blocks = append(blocks, slackBlock{
+		Type:     "context",
+		Elements: []*slackText{mrkdwn(fmt.Sprintf("polybot digest • %s", digest.WindowEnd.In(pst).Format("1/2/2006, 3:04:05PM (MST)")))},
+	})
+
+	return slackMessage{
+		Text:   fmt.Sprintf("Alert digest: %d alerts in the last %s", digest.TotalAlerts, digest.WindowLabel()),
+		Blocks: blocks,
+	}
+}
@@ -0,0 +1,74 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"polybot/clients/notifier"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SendMarketAlert sends a Block Kit market anomaly alert.
+// Implements notifier.MarketAlertNotifier interface.
+func (sc *SlackClient) SendMarketAlert(alert notifier.MarketAlert) {
+	if !sc.IsEnabled() {
+		sc.logger.Warn("slack not configured, skipping market alert")
+		return
+	}
+
+	if err := sc.send(context.Background(), sc.buildMarketAlertMessage(alert)); err != nil {
+		sc.logger.Error("failed to send slack market alert", zap.Error(err))
+		return
+	}
+
+	sc.logger.Info("sent slack market alert",
+		zap.String("kind", string(alert.Kind)),
+		zap.String("market", alert.MarketTitle),
+	)
+}
+
+func (sc *SlackClient) buildMarketAlertMessage(alert notifier.MarketAlert) slackMessage {
+	title := escapeMrkdwn(alert.MarketTitle)
+	if alert.MarketURL != "" {
+		title = fmt.Sprintf("<%s|%s>", alert.MarketURL, title)
+	}
+
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: alert.Title(), Emoji: true}},
+		{Type: "section", Text: mrkdwn(fmt.Sprintf("*%s*\n%s", title, escapeMrkdwn(alert.Summary())))},
+	}
+
+	if len(alert.TopWallets) > 0 {
+		var lines []string
+		for _, w := range alert.TopWallets {
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			name = escapeMrkdwn(name)
+			if w.URL != "" {
+				name = fmt.Sprintf("<%s|%s>", w.URL, name)
+			}
+			lines = append(lines, fmt.Sprintf("• %s: %d trades, 🟢 $%.0f / 🔴 $%.0f", name, w.Trades, w.BuyNotional, w.SellNotional))
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: mrkdwn(truncate("*👛 Top Wallets*\n"+strings.Join(lines, "\n"), maxFieldLen))})
+	}
+
+	// Footer timestamp (PST)
+	pst, _ := time.LoadLocation("America/Los_Angeles")
+	ts := alert.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	blocks = append(blocks, slackBlock{
+		Type:     "context",
+		Elements: []*slackText{mrkdwn(fmt.Sprintf("polybot market • %s", ts.In(pst).Format("1/2/2006, 3:04:05PM (MST)")))},
+	})
+
+	return slackMessage{
+		Text:   fmt.Sprintf("%s: %s", alert.Title(), alert.MarketTitle),
+		Blocks: blocks,
+	}
+}
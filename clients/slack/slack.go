@@ -0,0 +1,250 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"polybot/clients/notifier"
+	"polybot/config"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// SlackClient sends alerts to Slack as Block Kit messages, either through a
+// bot token (chat.postMessage) or an incoming webhook.
+// Implements notifier.Notifier interface.
+type SlackClient struct {
+	logger     *zap.Logger
+	botToken   string
+	channelID  string
+	webhookURL string
+	isProd     bool
+	client     *http.Client
+	apiURL     string // chat.postMessage endpoint (overridable for tests)
+}
+
+func NewSlackClient(logger *zap.Logger, cfg *config.Config) *SlackClient {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	channelID := cfg.Slack.BetaChannelID
+	webhookURL := cfg.Slack.BetaWebhookURL
+	if cfg.IsProd {
+		channelID = cfg.Slack.ProdChannelID
+		webhookURL = cfg.Slack.ProdWebhookURL
+	}
+
+	sc := &SlackClient{
+		logger:   logger,
+		botToken: cfg.Slack.BotToken, // Kept in webhook mode so routed channels can use it
+		isProd:   cfg.IsProd,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		apiURL:   slackPostMessageURL,
+	}
+
+	// Prefer the bot token when it has a channel to post to
+	switch {
+	case cfg.Slack.BotToken != "" && channelID != "":
+		sc.channelID = channelID
+		logger.Info("slack bot initialized",
+			zap.Bool("isProd", cfg.IsProd),
+			zap.String("channelID", channelID),
+		)
+	case webhookURL != "":
+		sc.webhookURL = webhookURL
+		logger.Info("slack incoming webhook initialized",
+			zap.Bool("isProd", cfg.IsProd),
+		)
+	default:
+		logger.Warn("SLACK_BOT_TOKEN/SLACK_*_WEBHOOK_URL not set, Slack alerts disabled")
+	}
+
+	return sc
+}
+
+// WithChannel returns a client that posts to another channel with the same bot.
+// Incoming webhooks are bound to one channel, so this requires a bot token.
+func (sc *SlackClient) WithChannel(channelID string) (*SlackClient, error) {
+	if sc.botToken == "" {
+		return nil, errors.New("slack bot token required to post to other channels")
+	}
+	return &SlackClient{
+		logger:    sc.logger.With(zap.String("channelID", channelID)),
+		botToken:  sc.botToken,
+		channelID: channelID,
+		isProd:    sc.isProd,
+		client:    sc.client,
+		apiURL:    sc.apiURL,
+	}, nil
+}
+
+// Name returns the channel name. Implements notifier.DeliveryNotifier interface.
+func (sc *SlackClient) Name() string {
+	return "slack"
+}
+
+// IsEnabled returns true if a bot channel or incoming webhook is configured.
+func (sc *SlackClient) IsEnabled() bool {
+	return (sc.botToken != "" && sc.channelID != "") || sc.webhookURL != ""
+}
+
+// SendTradeAlert sends a Block Kit trade alert.
+// Implements notifier.Notifier interface.
+func (sc *SlackClient) SendTradeAlert(alert notifier.TradeAlert) {
+	if !sc.IsEnabled() {
+		sc.logger.Warn("slack not configured, skipping alert")
+		return
+	}
+
+	if err := sc.DeliverTradeAlert(context.Background(), alert); err != nil {
+		sc.logger.Error("failed to send slack message", zap.Error(err))
+	}
+}
+
+// DeliverTradeAlert sends a Block Kit trade alert and returns any error.
+// Implements notifier.DeliveryNotifier interface.
+func (sc *SlackClient) DeliverTradeAlert(ctx context.Context, alert notifier.TradeAlert) error {
+	if !sc.IsEnabled() {
+		return notifier.Permanent(errors.New("slack not configured"))
+	}
+
+	if err := sc.send(ctx, sc.buildTradeMessage(alert)); err != nil {
+		return err
+	}
+
+	sc.logger.Info("sent slack trade alert",
+		zap.String("trader", alert.TraderName),
+		zap.String("market", alert.MarketTitle),
+	)
+	return nil
+}
+
+// send posts a message to the bot channel, or to the incoming webhook when
+// no bot channel is configured.
+func (sc *SlackClient) send(ctx context.Context, msg slackMessage) error {
+	if sc.botToken != "" && sc.channelID != "" {
+		msg.Channel = sc.channelID
+		return sc.postMessage(ctx, msg)
+	}
+	return sc.postWebhook(ctx, msg)
+}
+
+// slackAPIResponse is the body returned by chat.postMessage.
+type slackAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// slackRetryableErrors are chat.postMessage errors worth retrying; any other
+// error (bad auth, unknown channel, invalid blocks) won't succeed on retry.
+var slackRetryableErrors = map[string]bool{
+	"ratelimited":         true,
+	"internal_error":      true,
+	"fatal_error":         true,
+	"service_unavailable": true,
+	"request_timeout":     true,
+}
+
+func (sc *SlackClient) postMessage(ctx context.Context, msg slackMessage) error {
+	apiURL := sc.apiURL
+	if apiURL == "" {
+		apiURL = slackPostMessageURL
+	}
+
+	resp, err := sc.post(ctx, apiURL, msg, "Bearer "+sc.botToken)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return classifySlackStatus(resp)
+	}
+
+	var apiResp slackAPIResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&apiResp); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if !apiResp.OK {
+		err := fmt.Errorf("slack API error: %s", apiResp.Error)
+		if slackRetryableErrors[apiResp.Error] {
+			return err
+		}
+		return notifier.Permanent(err)
+	}
+
+	return nil
+}
+
+func (sc *SlackClient) postWebhook(ctx context.Context, msg slackMessage) error {
+	resp, err := sc.post(ctx, sc.webhookURL, msg, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return classifySlackStatus(resp)
+	}
+	return nil
+}
+
+func (sc *SlackClient) post(ctx context.Context, url string, msg slackMessage, auth string) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, notifier.Permanent(fmt.Errorf("marshal payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, notifier.Permanent(fmt.Errorf("create request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := sc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	return resp, nil
+}
+
+// classifySlackStatus maps a non-200 response to retry semantics.
+func classifySlackStatus(resp *http.Response) error {
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	err := fmt.Errorf("slack returned status %d", resp.StatusCode)
+	if detail := strings.TrimSpace(string(respBody)); detail != "" {
+		err = fmt.Errorf("slack returned status %d: %s", resp.StatusCode, detail)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		var retryAfter time.Duration
+		if secs, convErr := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); convErr == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		return &notifier.RetryAfterError{Err: err, RetryAfter: retryAfter}
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		// Invalid blocks, revoked webhook or archived channel won't succeed on retry
+		return notifier.Permanent(err)
+	}
+	return err
+}
+
+// Close cleans up resources. Implements notifier.Notifier interface.
+func (sc *SlackClient) Close() error {
+	return nil
+}
@@ -0,0 +1,402 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"polybot/clients/notifier"
+	"polybot/config"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestNewSlackClient_Disabled(t *testing.T) {
+	client := NewSlackClient(zap.NewNop(), &config.Config{})
+
+	if client.IsEnabled() {
+		t.Error("expected client to be disabled without token or webhook")
+	}
+
+	// Should not panic
+	client.SendTradeAlert(notifier.TradeAlert{TraderName: "test"})
+}
+
+func TestNewSlackClient_ProdBetaSelection(t *testing.T) {
+	slackCfg := config.SlackConfig{
+		BotToken:       "xoxb-test",
+		ProdChannelID:  "C-PROD",
+		BetaChannelID:  "C-BETA",
+		ProdWebhookURL: "https://hooks.slack.com/prod",
+		BetaWebhookURL: "https://hooks.slack.com/beta",
+	}
+
+	prod := NewSlackClient(nil, &config.Config{IsProd: true, Slack: slackCfg})
+	if prod.channelID != "C-PROD" {
+		t.Errorf("expected prod channel, got %s", prod.channelID)
+	}
+
+	beta := NewSlackClient(nil, &config.Config{IsProd: false, Slack: slackCfg})
+	if beta.channelID != "C-BETA" {
+		t.Errorf("expected beta channel, got %s", beta.channelID)
+	}
+	if beta.webhookURL != "" {
+		t.Error("expected bot token to take precedence over webhook")
+	}
+}
+
+func TestNewSlackClient_WebhookFallback(t *testing.T) {
+	cfg := &config.Config{
+		IsProd: true,
+		Slack: config.SlackConfig{
+			BotToken:       "xoxb-test", // No channel for this stage
+			BetaChannelID:  "C-BETA",
+			ProdWebhookURL: "https://hooks.slack.com/prod",
+		},
+	}
+
+	client := NewSlackClient(zap.NewNop(), cfg)
+	if !client.IsEnabled() || client.webhookURL != "https://hooks.slack.com/prod" || client.channelID != "" {
+		t.Errorf("expected prod webhook mode, got %+v", client)
+	}
+}
+
+func TestDeliverTradeAlert_BotToken(t *testing.T) {
+	var gotAuth string
+	var gotMsg slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotMsg)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := &SlackClient{
+		logger:    zap.NewNop(),
+		botToken:  "xoxb-test",
+		channelID: "C-BETA",
+		client:    server.Client(),
+		apiURL:    server.URL,
+	}
+
+	if err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{TraderName: "test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "Bearer xoxb-test" {
+		t.Errorf("unexpected auth header %q", gotAuth)
+	}
+	if gotMsg.Channel != "C-BETA" || len(gotMsg.Blocks) == 0 || gotMsg.Text == "" {
+		t.Errorf("unexpected message: %+v", gotMsg)
+	}
+}
+
+func TestDeliverTradeAlert_APIErrors(t *testing.T) {
+	tests := []struct {
+		body      string
+		permanent bool
+	}{
+		{`{"ok":false,"error":"channel_not_found"}`, true},
+		{`{"ok":false,"error":"invalid_auth"}`, true},
+		{`{"ok":false,"error":"internal_error"}`, false},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tt.body))
+		}))
+
+		client := &SlackClient{
+			logger:    zap.NewNop(),
+			botToken:  "xoxb-test",
+			channelID: "C-BETA",
+			client:    server.Client(),
+			apiURL:    server.URL,
+		}
+
+		err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{})
+		server.Close()
+		if err == nil {
+			t.Errorf("%s: expected error", tt.body)
+			continue
+		}
+		var permanent *notifier.PermanentError
+		if errors.As(err, &permanent) != tt.permanent {
+			t.Errorf("%s: expected permanent=%v, got %v", tt.body, tt.permanent, err)
+		}
+	}
+}
+
+func TestDeliverTradeAlert_WebhookRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := &SlackClient{
+		logger:     zap.NewNop(),
+		webhookURL: server.URL,
+		client:     server.Client(),
+	}
+
+	err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{})
+	var retryAfter *notifier.RetryAfterError
+	if !errors.As(err, &retryAfter) {
+		t.Fatalf("expected retry after error, got %v", err)
+	}
+	if retryAfter.RetryAfter != 30*time.Second {
+		t.Errorf("expected retry after 30s, got %v", retryAfter.RetryAfter)
+	}
+}
+
+func TestDeliverTradeAlert_WebhookInvalidBlocksIsPermanent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid_blocks"))
+	}))
+	defer server.Close()
+
+	client := &SlackClient{
+		logger:     zap.NewNop(),
+		webhookURL: server.URL,
+		client:     server.Client(),
+	}
+
+	err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{})
+	var permanent *notifier.PermanentError
+	if !errors.As(err, &permanent) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "invalid_blocks") {
+		t.Errorf("expected response body in error, got %v", err)
+	}
+}
+
+func TestBuildTradeMessage_FullAlert(t *testing.T) {
+	client := &SlackClient{logger: zap.NewNop()}
+
+	alert := notifier.TradeAlert{
+		TraderName:         "Trader<1>",
+		TraderAddress:      "0x1234567890abcdef1234567890abcdef12345678",
+		WalletURL:          "https://polymarket.com/profile/0x123",
+		Side:               "SELL",
+		Shares:             100.5,
+		Price:              0.75,
+		Notional:           75.375,
+		MarketTitle:        "Rates & Fed",
+		MarketURL:          "https://polymarket.com/event/test",
+		MarketImage:        "https://example.com/img.png",
+		Outcome:            "Yes",
+		WinRate:            0.65,
+		WinCount:           13,
+		LossCount:          7,
+		HasInventory:       true,
+		InventoryShares:    50,
+		InventoryAvgPrice:  0.5,
+		InventoryValue:     37.5,
+		HasHedgeInfo:       true,
+		HedgeYesSizeBefore: 100,
+		HedgeNoSizeBefore:  100,
+		HedgeNoSizeAfter:   100,
+		HedgeSoldSide:      "Yes",
+		HedgeSoldPct:       1,
+		HasConvictionInfo:  true,
+		HasStealthInfo:     true,
+		HasPreMoveInfo:     true,
+		Reasons:            []notifier.AlertReason{notifier.AlertReasonHedgeRemoval},
+		Timestamp:          time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+	}
+
+	msg := client.buildTradeMessage(alert)
+
+	if msg.Blocks[0].Type != "header" || msg.Blocks[0].Text.Text != "🛡️ Hedge Removal Detected" {
+		t.Errorf("unexpected header block: %+v", msg.Blocks[0])
+	}
+
+	text := blockText(msg)
+	for _, want := range []string{
+		"<https://polymarket.com/event/test|Rates &amp; Fed>",
+		"Trader&lt;1&gt;",
+		"🔴 SELL",
+		"65.0% (13-7)",
+		"~50.00 shares",
+		"Sold Yes: 100%",
+		"Conviction",
+		"Stealth Accumulation",
+		"Pre-Move",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected message to contain %q", want)
+		}
+	}
+	if msg.Blocks[1].Accessory == nil || msg.Blocks[1].Accessory.ImageURL != "https://example.com/img.png" {
+		t.Error("expected market image accessory")
+	}
+}
+
+// blockText joins the text of every block in a message.
+func blockText(msg slackMessage) string {
+	var sb strings.Builder
+	for _, b := range msg.Blocks {
+		if b.Text != nil {
+			sb.WriteString(b.Text.Text + "\n")
+		}
+		for _, f := range append(b.Fields, b.Elements...) {
+			sb.WriteString(f.Text + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func TestBuildTradeMessage_NoOptionalSections(t *testing.T) {
+	client := &SlackClient{logger: zap.NewNop()}
+
+	msg := client.buildTradeMessage(notifier.TradeAlert{TraderName: "test", Side: "BUY"})
+
+	for _, b := range msg.Blocks {
+		if b.Type == "divider" {
+			t.Error("expected no detail sections without pattern info")
+		}
+	}
+	if msg.Blocks[1].Accessory != nil {
+		t.Error("expected no image accessory without market image")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("abcdef", 4); got != "abc…" {
+		t.Errorf("unexpected truncation %q", got)
+	}
+	if got := truncate("abc", 4); got != "abc" {
+		t.Errorf("expected short string unchanged, got %q", got)
+	}
+}
+
+func TestBuildDigestMessage(t *testing.T) {
+	client := &SlackClient{logger: zap.NewNop()}
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	digest := notifier.AlertDigest{
+		WindowStart:   start,
+		WindowEnd:     start.Add(time.Hour),
+		TotalAlerts:   9,
+		ReasonCounts:  []notifier.DigestReasonCount{{Reason: notifier.AlertReasonNewWallet, Count: 5}},
+		TopWallets:    []notifier.DigestWallet{{Address: "0xabc", Name: "A&B", URL: "https://polymarket.com/profile/0xabc", Count: 2, Notional: 700}},
+		TopMarkets:    []notifier.DigestMarket{{ConditionID: "0xcond", Title: "Rates", Count: 3, Notional: 900}},
+		LargestTrades: []notifier.TradeAlert{{Side: "BUY", Notional: 500, Outcome: "Yes", Price: 0.5, MarketTitle: "Rates", MarketURL: "https://polymarket.com/event/rates"}},
+	}
+
+	msg := client.buildDigestMessage(digest)
+
+	if msg.Blocks[0].Type != "header" || msg.Text != "Alert digest: 9 alerts in the last 1h" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	text := blockText(msg)
+	for _, want := range []string{
+		"*9 alerts* in the last 1h",
+		"`new_wallet` 5",
+		"<https://polymarket.com/profile/0xabc|A&amp;B>: 2 alerts, $700",
+		"• Rates: 3 alerts, $900",
+		"🟢 *$500* Yes @ $0.500: <https://polymarket.com/event/rates|Rates>",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected message to contain %q", want)
+		}
+	}
+}
+
+func TestBuildMarketAlertMessage(t *testing.T) {
+	client := &SlackClient{logger: zap.NewNop()}
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	alert := notifier.MarketAlert{
+		Kind:         notifier.MarketAlertVolumeSpike,
+		MarketTitle:  "Rates",
+		MarketURL:    "https://polymarket.com/event/rates",
+		WindowStart:  start,
+		WindowEnd:    start.Add(5 * time.Minute),
+		Trades:       20,
+		Notional:     50000,
+		BaselineMean: 4000,
+		ZScore:       6,
+		TopWallets:   []notifier.MarketAlertWallet{{Address: "0xabc", Name: "A&B", Trades: 3, SellNotional: 9000}},
+	}
+
+	msg := client.buildMarketAlertMessage(alert)
+
+	if msg.Blocks[0].Type != "header" || msg.Text != "📈 Market Volume Spike: Rates" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	text := blockText(msg)
+	for _, want := range []string{
+		"*<https://polymarket.com/event/rates|Rates>*",
+		"$50000 in 5m across 20 trades (6.0σ above the $4000 baseline)",
+		"• A&amp;B: 3 trades, 🟢 $0 / 🔴 $9000",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected message to contain %q", want)
+		}
+	}
+}
+
+func TestBuildTradeMessage_CoordinatedEntry(t *testing.T) {
+	client := &SlackClient{logger: zap.NewNop()}
+
+	msg := client.buildTradeMessage(notifier.TradeAlert{
+		TraderName: "test",
+		Side:       "BUY",
+		Outcome:    "Yes",
+		Reasons:    []notifier.AlertReason{notifier.AlertReasonCoordinatedEntry},
+		ClusterWallets: []notifier.ClusterWallet{
+			{Address: "0xaaa", URL: "https://polymarket.com/profile/0xaaa", UniqueMarkets: 1, Shares: 10000, Notional: 4200},
+		},
+		ClusterShares:   10000,
+		ClusterNotional: 4200,
+		HasClusterInfo:  true,
+	})
+
+	if !strings.Contains(msg.Text, "👥 Coordinated Entry") {
+		t.Errorf("unexpected text: %s", msg.Text)
+	}
+	found := false
+	for _, b := range msg.Blocks {
+		if b.Text != nil && strings.HasPrefix(b.Text.Text, "*👥 Coordinated Entry*") {
+			found = true
+			if !strings.Contains(b.Text.Text, "• <https://polymarket.com/profile/0xaaa|0xaaa>: $4200, 10000 shares, 1 markets") {
+				t.Errorf("unexpected cluster section: %s", b.Text.Text)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected a coordinated entry section")
+	}
+}
+
+func TestBuildTradeMessage_WalletCluster(t *testing.T) {
+	client := &SlackClient{logger: zap.NewNop()}
+
+	msg := client.buildTradeMessage(notifier.TradeAlert{
+		TraderName:           "test",
+		Side:                 "BUY",
+		Reasons:              []notifier.AlertReason{notifier.AlertReasonLowActivity},
+		WalletClusterID:      "C-1a2b3c4d",
+		WalletClusterSize:    3,
+		WalletClusterSignals: []string{"sizing"},
+	})
+
+	for _, b := range msg.Blocks {
+		for _, f := range b.Fields {
+			if strings.HasPrefix(f.Text, "*🔗 Wallet Cluster*") {
+				if f.Text != "*🔗 Wallet Cluster*\nC-1a2b3c4d\n3 wallets\nsizing" {
+					t.Errorf("unexpected wallet cluster field: %q", f.Text)
+				}
+				return
+			}
+		}
+	}
+	t.Error("expected a wallet cluster field")
+}
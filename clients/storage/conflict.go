@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrConflict is returned when a save finds that the stored file changed
+// since this instance last read or wrote it.
+var ErrConflict = errors.New("file changed since last read")
+
+// Conflict resolutions.
+const (
+	ConflictRefused = "refused" // The save was rejected; the stored file was kept
+	ConflictMerged  = "merged"  // Both versions were merged and the result saved
+)
+
+// maxConflicts caps how many recent conflicts a ConflictLog keeps.
+const maxConflicts = 50
+
+// Conflict records a save that found the stored file changed by someone else.
+type Conflict struct {
+	Namespace  string    `json:"namespace"`
+	Filename   string    `json:"filename"`
+	Resolution string    `json:"resolution"`
+	At         time.Time `json:"at"`
+}
+
+// MergeFunc merges a file changed concurrently. base is the content this
+// instance last read or wrote ("" if unknown), stored is the current content
+// and local is the content being saved. It returns the content to save.
+type MergeFunc func(base, stored, local string) (string, error)
+
+// Merger is implemented by backends that can merge conflicting saves.
+type Merger interface {
+	SetMerger(filename string, merge MergeFunc)
+}
+
+// ConflictReporter is implemented by backends that detect conflicting saves.
+type ConflictReporter interface {
+	Conflicts() []Conflict
+}
+
+// ConflictLog keeps the merge functions of a backend and its recent conflicts.
+// The zero value is ready to use.
+type ConflictLog struct {
+	mu        sync.Mutex
+	mergers   map[string]MergeFunc
+	conflicts []Conflict
+}
+
+// SetMerger registers the merge function used when saves of filename conflict.
+func (l *ConflictLog) SetMerger(filename string, merge MergeFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mergers == nil {
+		l.mergers = make(map[string]MergeFunc)
+	}
+	l.mergers[filename] = merge
+}
+
+// MergerFor returns the merge function registered for filename, or nil.
+func (l *ConflictLog) MergerFor(filename string) MergeFunc {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.mergers[filename]
+}
+
+// Record adds a conflict, dropping the oldest beyond the cap.
+func (l *ConflictLog) Record(c Conflict) {
+	if c.At.IsZero() {
+		c.At = time.Now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.conflicts = append(l.conflicts, c)
+	if len(l.conflicts) > maxConflicts {
+		l.conflicts = l.conflicts[len(l.conflicts)-maxConflicts:]
+	}
+}
+
+// Conflicts returns the recorded conflicts, newest first.
+func (l *ConflictLog) Conflicts() []Conflict {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]Conflict, len(l.conflicts))
+	for i, c := range l.conflicts {
+		out[len(l.conflicts)-1-i] = c
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestConflictLog(t *testing.T) {
+	var log ConflictLog
+	if log.MergerFor("passkeys.json") != nil {
+		t.Error("expected no merger")
+	}
+	log.SetMerger("passkeys.json", func(base, stored, local string) (string, error) { return local, nil })
+	if log.MergerFor("passkeys.json") == nil || log.MergerFor("settings.json") != nil {
+		t.Error("expected a merger for passkeys.json only")
+	}
+
+	for i := 0; i < maxConflicts+5; i++ {
+		log.Record(Conflict{Filename: fmt.Sprintf("f%d", i)})
+	}
+	conflicts := log.Conflicts()
+	if len(conflicts) != maxConflicts {
+		t.Fatalf("expected %d conflicts, got %d", maxConflicts, len(conflicts))
+	}
+	if conflicts[0].Filename != fmt.Sprintf("f%d", maxConflicts+4) || conflicts[maxConflicts-1].Filename != "f5" {
+		t.Errorf("expected newest first, got %s ... %s", conflicts[0].Filename, conflicts[maxConflicts-1].Filename)
+	}
+	if conflicts[0].At.IsZero() {
+		t.Error("expected time to be set")
+	}
+}
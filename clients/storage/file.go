@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+// Ensure FileStore implements Storage and Source
+var (
+	_ Storage = (*FileStore)(nil)
+	_ Source  = (*FileStore)(nil)
+)
+
+// FileStore stores each namespace as a directory of files under a root
+// directory. Writes go to a temporary file that is renamed over the target,
+// so a crash mid-save never leaves a truncated file behind.
+type FileStore struct {
+	logger    *zap.Logger
+	dir       string
+	namespace string // Default namespace
+}
+
+// NewFileStore creates a filesystem store rooted at dir, creating it if needed.
+func NewFileStore(logger *zap.Logger, dir, namespace string) (*FileStore, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if dir == "" {
+		return nil, errors.New("storage directory not configured")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create storage directory: %w", err)
+	}
+
+	return &FileStore{
+		logger:    logger,
+		dir:       dir,
+		namespace: namespace,
+	}, nil
+}
+
+// IsEnabled always returns true; the directory was created on construction.
+func (s *FileStore) IsEnabled() bool {
+	return true
+}
+
+// path returns the location of a file, validating both name parts.
+func (s *FileStore) path(filename string, namespace []string) (string, error) {
+	ns, err := resolveNamespace(s.namespace, namespace)
+	if err != nil {
+		return "", err
+	}
+	if err := validName(filename); err != nil {
+		return "", fmt.Errorf("filename: %w", err)
+	}
+	return filepath.Join(s.dir, ns, filename), nil
+}
+
+// Load loads content from a file.
+// If namespace is provided (non-empty), it overrides the default namespace.
+func (s *FileStore) Load(ctx context.Context, filename string, namespace ...string) (string, error) {
+	path, err := s.path(filename, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("file %q: %w", filename, ErrNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+
+	s.logger.Debug("loaded from file",
+		zap.String("path", path),
+		zap.Int("bytes", len(data)),
+	)
+
+	return string(data), nil
+}
+
+// Save atomically replaces the content of a file.
+// If namespace is provided (non-empty), it overrides the default namespace.
+func (s *FileStore) Save(ctx context.Context, filename, content string, namespace ...string) error {
+	path, err := s.path(filename, namespace)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create namespace directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filename+".*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	s.logger.Debug("saved to file",
+		zap.String("path", path),
+		zap.Int("bytes", len(content)),
+	)
+
+	return nil
+}
+
+// LoadJSON loads JSON data from a file in the default namespace.
+func (s *FileStore) LoadJSON(ctx context.Context, filename string, dest any) error {
+	return loadJSON(ctx, s, filename, dest)
+}
+
+// SaveJSON saves JSON data to a file in the default namespace.
+func (s *FileStore) SaveJSON(ctx context.Context, filename string, data any) error {
+	return saveJSON(ctx, s, filename, data)
+}
+
+// GetGistID returns the default namespace.
+func (s *FileStore) GetGistID() string {
+	return s.namespace
+}
+
+// Files returns every file in a namespace.
+func (s *FileStore) Files(ctx context.Context, namespace string) (map[string]string, error) {
+	ns, err := resolveNamespace(s.namespace, []string{namespace})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(s.dir, ns))
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read namespace directory: %w", err)
+	}
+
+	files := make(map[string]string, len(entries))
+	for _, e := range entries {
+		// Skip directories and temp files of in-flight saves
+		if !e.Type().IsRegular() || e.Name()[0] == '.' {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, ns, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read file: %w", err)
+		}
+		files[e.Name()] = string(data)
+	}
+	return files, nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(zap.NewNop(), t.TempDir(), "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testStore(t, s)
+}
+
+func TestFileStore_Layout(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	s, err := NewFileStore(nil, dir, "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Save(t.Context(), "tasks.json", "[]", "tasks"); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	// One directory per namespace, no temp files left behind
+	entries, err := os.ReadDir(filepath.Join(dir, "tasks"))
+	if err != nil {
+		t.Fatalf("read dir failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "tasks.json" {
+		t.Errorf("unexpected namespace directory: %v", entries)
+	}
+
+	// A new store over the same directory sees the file
+	reopened, err := NewFileStore(nil, dir, "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content, err := reopened.Load(t.Context(), "tasks.json", "tasks"); err != nil || content != "[]" {
+		t.Errorf("expected file after reopen, got %q, %v", content, err)
+	}
+}
+
+func TestFileStore_NoDir(t *testing.T) {
+	if _, err := NewFileStore(nil, "", "cache"); err == nil {
+		t.Error("expected error without a directory")
+	}
+}
+
+func TestFileStore_NoNamespace(t *testing.T) {
+	s, err := NewFileStore(nil, t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Save(t.Context(), "a.json", "{}"); err == nil {
+		t.Error("expected error without a namespace")
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// MigrationResult counts the files a migration copied and left alone.
+type MigrationResult struct {
+	Copied  int
+	Skipped int // Already present in the destination
+}
+
+// Migrate copies every file of the given namespaces from src to dst under the
+// same namespace and filename. Files that already exist in dst are never
+// overwritten, so it is safe to run on every start: after the first run it
+// only picks up files the destination has not written yet. A namespace that
+// fails to copy does not stop the others; all failures are returned joined.
+func Migrate(ctx context.Context, logger *zap.Logger, src Source, dst Storage, namespaces []string) (MigrationResult, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	var result MigrationResult
+	var errs []error
+	for _, ns := range namespaces {
+		files, err := src.Files(ctx, ns)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list %s: %w", ns, err))
+			continue
+		}
+
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			_, err := dst.Load(ctx, name, ns)
+			if err == nil {
+				result.Skipped++
+				continue
+			}
+			if !errors.Is(err, ErrNotFound) {
+				errs = append(errs, fmt.Errorf("check %s/%s: %w", ns, name, err))
+				continue
+			}
+			if err := dst.Save(ctx, name, files[name], ns); err != nil {
+				errs = append(errs, fmt.Errorf("copy %s/%s: %w", ns, name, err))
+				continue
+			}
+			result.Copied++
+			logger.Info("migrated file",
+				zap.String("namespace", ns),
+				zap.String("filename", name),
+				zap.Int("bytes", len(files[name])),
+			)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// staticSource serves fixed files per namespace, failing unknown namespaces.
+type staticSource map[string]map[string]string
+
+func (s staticSource) Files(ctx context.Context, namespace string) (map[string]string, error) {
+	files, ok := s[namespace]
+	if !ok {
+		return nil, errors.New("gist not found")
+	}
+	return files, nil
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := t.Context()
+	dst, err := NewSQLiteStore(nil, filepath.Join(t.TempDir(), "polybot.db"), "cache-gist")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer dst.Close()
+
+	src := staticSource{
+		"cache-gist": {"wallet_cache.json": "old-cache", "seen_trades.json": "seen"},
+		"hedge-gist": {"hedges.json": "hedges"},
+	}
+
+	// Files already stored locally are newer than the gist and kept
+	if err := dst.Save(ctx, "wallet_cache.json", "local-cache"); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	result, err := Migrate(ctx, nil, src, dst, []string{"cache-gist", "hedge-gist", "missing-gist"})
+	if err == nil {
+		t.Error("expected error for the missing gist")
+	}
+	if result.Copied != 2 || result.Skipped != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	for _, c := range []struct{ ns, file, want string }{
+		{"cache-gist", "wallet_cache.json", "local-cache"},
+		{"cache-gist", "seen_trades.json", "seen"},
+		{"hedge-gist", "hedges.json", "hedges"},
+	} {
+		if got, _ := dst.Load(ctx, c.file, c.ns); got != c.want {
+			t.Errorf("%s/%s: expected %q, got %q", c.ns, c.file, c.want, got)
+		}
+	}
+
+	// A second run copies nothing
+	result, err = Migrate(ctx, nil, src, dst, []string{"cache-gist", "hedge-gist"})
+	if err != nil || result.Copied != 0 || result.Skipped != 3 {
+		t.Errorf("expected rerun to skip everything, got %+v, %v", result, err)
+	}
+}
+
+func TestMigrate_BetweenLocalBackends(t *testing.T) {
+	ctx := t.Context()
+	src, err := NewFileStore(nil, t.TempDir(), "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dst, err := NewFileStore(nil, t.TempDir(), "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := src.Save(ctx, "tasks.json", "[]", "tasks"); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	if result, err := Migrate(ctx, nil, src, dst, []string{"tasks"}); err != nil || result.Copied != 1 {
+		t.Errorf("unexpected result: %+v, %v", result, err)
+	}
+	if got, _ := dst.Load(ctx, "tasks.json", "tasks"); got != "[]" {
+		t.Errorf("expected migrated file, got %q", got)
+	}
+}
@@ -0,0 +1,457 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"polybot/config"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Ensure S3Store implements Storage, Merger and ConflictReporter
+var (
+	_ Storage          = (*S3Store)(nil)
+	_ Merger           = (*S3Store)(nil)
+	_ ConflictReporter = (*S3Store)(nil)
+)
+
+const (
+	s3Service       = "s3"
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+	s3TimeFormat    = "20060102T150405Z"
+	s3DateFormat    = "20060102"
+	s3EmptyBodyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3Store stores files as gzip-compressed objects in an S3-compatible bucket,
+// keyed prefix/namespace/filename. Requests are signed with SigV4.
+//
+// Writes are conditional: an object is only replaced if its ETag still matches
+// the one this instance last read or wrote, and only created if it doesn't
+// exist yet. A second instance sharing the bucket therefore gets ErrConflict
+// instead of silently overwriting the first one's state, unless a merger is
+// registered for the file, in which case both versions are merged. Saves keep
+// being refused until the object is loaded again, which is how callers pick
+// up the other instance's version before overwriting it.
+type S3Store struct {
+	logger     *zap.Logger
+	httpClient *http.Client
+	cfg        config.S3Config
+	endpoint   *url.URL
+	namespace  string // Default namespace
+
+	mu    sync.Mutex
+	etags map[string]string // Object key -> ETag last seen ("" = seen absent)
+	bases map[string]string // Object key -> content last seen, for files with a merger
+
+	ConflictLog
+
+	// now returns the current time (overridden in tests)
+	now func() time.Time
+}
+
+// NewS3Store creates an object storage client for the configured bucket.
+func NewS3Store(logger *zap.Logger, cfg config.S3Config, namespace string) (*S3Store, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("s3 credentials not configured")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	return &S3Store{
+		logger: logger,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		cfg:       cfg,
+		endpoint:  endpoint,
+		namespace: namespace,
+		etags:     make(map[string]string),
+		bases:     make(map[string]string),
+		now:       time.Now,
+	}, nil
+}
+
+// IsEnabled always returns true; the bucket was configured on construction.
+func (s *S3Store) IsEnabled() bool {
+	return true
+}
+
+// key returns the object key of a file, validating both name parts.
+func (s *S3Store) key(filename string, namespace []string) (string, error) {
+	ns, err := resolveNamespace(s.namespace, namespace)
+	if err != nil {
+		return "", err
+	}
+	if err := validName(filename); err != nil {
+		return "", fmt.Errorf("filename: %w", err)
+	}
+	key := ns + "/" + filename
+	if prefix := strings.Trim(s.cfg.Prefix, "/"); prefix != "" {
+		key = prefix + "/" + key
+	}
+	return key, nil
+}
+
+// objectURL returns the URL of an object, path-style or virtual-hosted.
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.cfg.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket + "/" + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	}
+	return &u
+}
+
+// Load downloads a file and decompresses it.
+// If namespace is provided (non-empty), it overrides the default namespace.
+func (s *S3Store) Load(ctx context.Context, filename string, namespace ...string) (string, error) {
+	key, err := s.key(filename, namespace)
+	if err != nil {
+		return "", err
+	}
+	content, etag, err := s.get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		s.setETag(key, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	s.setETag(key, etag)
+	if s.MergerFor(filename) != nil {
+		s.setBase(key, content)
+	}
+	return content, nil
+}
+
+// get downloads an object and returns it with its ETag. Recording the ETag,
+// and so allowing the object to be overwritten, is left to the caller.
+func (s *S3Store) get(ctx context.Context, key string) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return "", "", fmt.Errorf("create request: %w", err)
+	}
+	// Set explicitly so the transport hands back the stored bytes undecoded
+	req.Header.Set("Accept-Encoding", "gzip")
+	s.sign(req, s3EmptyBodyHash)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", "", fmt.Errorf("object %q: %w", key, ErrNotFound)
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", "", fmt.Errorf("s3 error status=%d body=%s", resp.StatusCode, string(body))
+	}
+
+	var reader io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return "", "", fmt.Errorf("open gzip: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", "", fmt.Errorf("read object: %w", err)
+	}
+
+	s.logger.Debug("loaded from s3",
+		zap.String("key", key),
+		zap.Int("bytes", len(content)),
+	)
+
+	return string(content), resp.Header.Get("ETag"), nil
+}
+
+// Save compresses and uploads a file. The write only succeeds if the object
+// is unchanged since this instance last saw it; otherwise it is merged with
+// the stored object if a merger is registered for the file, or ErrConflict is
+// returned and the object is left alone.
+// If namespace is provided (non-empty), it overrides the default namespace.
+func (s *S3Store) Save(ctx context.Context, filename, content string, namespace ...string) error {
+	key, err := s.key(filename, namespace)
+	if err != nil {
+		return err
+	}
+
+	err = s.put(ctx, key, content)
+	if errors.Is(err, ErrConflict) {
+		ns, _ := resolveNamespace(s.namespace, namespace)
+		content, err = s.resolveConflict(ctx, ns, filename, key, content)
+		if err == nil {
+			err = s.put(ctx, key, content)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if s.MergerFor(filename) != nil {
+		s.setBase(key, content)
+	}
+	return nil
+}
+
+// resolveConflict merges a rejected save with the stored object if a merger
+// is registered, re-reading the object so the retry is conditional on the
+// version merged with. Otherwise the conflict is recorded and returned, and
+// the ETag is left as it was, so later saves are refused until a Load.
+func (s *S3Store) resolveConflict(ctx context.Context, namespace, filename, key, content string) (string, error) {
+	conflict := Conflict{Namespace: namespace, Filename: filename, Resolution: ConflictRefused}
+	if merge := s.MergerFor(filename); merge != nil {
+		stored, etag, err := s.get(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			stored, etag, err = "", "", nil // Deleted meanwhile; the retry recreates it
+		}
+		if err == nil {
+			var merged string
+			if merged, err = merge(s.getBase(key), stored, content); err == nil {
+				s.setETag(key, etag)
+				conflict.Resolution = ConflictMerged
+				s.Record(conflict)
+				s.logger.Warn("merged concurrent change to s3 object", zap.String("key", key))
+				return merged, nil
+			}
+		}
+		s.logger.Warn("failed to merge s3 object", zap.String("key", key), zap.Error(err))
+	}
+
+	s.Record(conflict)
+	s.logger.Warn("refusing to overwrite s3 object changed since last read", zap.String("key", key))
+	return "", fmt.Errorf("object %q: %w", key, ErrConflict)
+}
+
+// put uploads an object, conditional on the ETag last seen.
+func (s *S3Store) put(ctx context.Context, key, content string) error {
+	var body bytes.Buffer
+	gz := gzip.NewWriter(&body)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		return fmt.Errorf("gzip content: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("gzip content: %w", err)
+	}
+	payload := body.Bytes()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	// Objects never seen are only created, never overwritten
+	if etag := s.getETag(key); etag != "" {
+		req.Header.Set("If-Match", etag)
+	} else {
+		req.Header.Set("If-None-Match", "*")
+	}
+
+	sum := sha256.Sum256(payload)
+	s.sign(req, hex.EncodeToString(sum[:]))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 409 is returned for a conditional write racing another one
+	if resp.StatusCode == http.StatusPreconditionFailed || resp.StatusCode == http.StatusConflict {
+		return fmt.Errorf("object %q: %w", key, ErrConflict)
+	}
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("s3 error status=%d body=%s", resp.StatusCode, string(respBody))
+	}
+	s.setETag(key, resp.Header.Get("ETag"))
+
+	s.logger.Debug("saved to s3",
+		zap.String("key", key),
+		zap.Int("bytes", len(content)),
+		zap.Int("compressed", len(payload)),
+	)
+
+	return nil
+}
+
+// LoadJSON loads JSON data from a file in the default namespace.
+func (s *S3Store) LoadJSON(ctx context.Context, filename string, dest any) error {
+	return loadJSON(ctx, s, filename, dest)
+}
+
+// SaveJSON saves JSON data to a file in the default namespace.
+func (s *S3Store) SaveJSON(ctx context.Context, filename string, data any) error {
+	return saveJSON(ctx, s, filename, data)
+}
+
+// GetGistID returns the default namespace.
+func (s *S3Store) GetGistID() string {
+	return s.namespace
+}
+
+func (s *S3Store) getETag(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.etags[key]
+}
+
+func (s *S3Store) setETag(key, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.etags[key] = etag
+}
+
+func (s *S3Store) getBase(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bases[key]
+}
+
+func (s *S3Store) setBase(key, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bases[key] = content
+}
+
+// sign adds SigV4 authentication headers to a request.
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (s *S3Store) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format(s3TimeFormat)
+	scope := strings.Join([]string{now.Format(s3DateFormat), s.cfg.Region, s3Service, "aws4_request"}, "/")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	// Sign every header set so far, plus the host
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath URI-encodes every byte of a path except unreserved characters
+// and slashes.
+func s3EscapePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery encodes query parameters sorted by name.
+func s3CanonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, s3EscapeQuery(k)+"="+s3EscapeQuery(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3EscapeQuery URI-encodes a query component (spaces as %20, not +).
+func s3EscapeQuery(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
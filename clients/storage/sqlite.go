@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	_ "modernc.org/sqlite" // Pure Go driver, registers "sqlite"
+)
+
+// Ensure SQLiteStore implements Storage and Source
+var (
+	_ Storage = (*SQLiteStore)(nil)
+	_ Source  = (*SQLiteStore)(nil)
+)
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS files (
+	namespace  TEXT    NOT NULL,
+	filename   TEXT    NOT NULL,
+	content    TEXT    NOT NULL,
+	updated_at INTEGER NOT NULL,
+	PRIMARY KEY (namespace, filename)
+)`
+
+// SQLiteStore stores files as rows of an embedded SQLite database, keyed by
+// namespace and filename.
+type SQLiteStore struct {
+	logger    *zap.Logger
+	db        *sql.DB
+	namespace string // Default namespace
+}
+
+// NewSQLiteStore opens (or creates) the database at path.
+func NewSQLiteStore(logger *zap.Logger, path, namespace string) (*SQLiteStore, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if path == "" {
+		return nil, errors.New("sqlite path not configured")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create database directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	// SQLite allows one writer; a single connection avoids SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range []string{
+		"PRAGMA journal_mode=WAL",
+		"PRAGMA busy_timeout=5000",
+		sqliteSchema,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("initialize database: %w", err)
+		}
+	}
+
+	return &SQLiteStore{
+		logger:    logger,
+		db:        db,
+		namespace: namespace,
+	}, nil
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// IsEnabled always returns true; the database was opened on construction.
+func (s *SQLiteStore) IsEnabled() bool {
+	return true
+}
+
+// Load loads content from a file.
+// If namespace is provided (non-empty), it overrides the default namespace.
+func (s *SQLiteStore) Load(ctx context.Context, filename string, namespace ...string) (string, error) {
+	ns, err := resolveNamespace(s.namespace, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	var content string
+	err = s.db.QueryRowContext(ctx,
+		"SELECT content FROM files WHERE namespace = ? AND filename = ?",
+		ns, filename,
+	).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("file %q: %w", filename, ErrNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("query file: %w", err)
+	}
+
+	s.logger.Debug("loaded from sqlite",
+		zap.String("namespace", ns),
+		zap.String("filename", filename),
+		zap.Int("bytes", len(content)),
+	)
+
+	return content, nil
+}
+
+// Save replaces the content of a file.
+// If namespace is provided (non-empty), it overrides the default namespace.
+func (s *SQLiteStore) Save(ctx context.Context, filename, content string, namespace ...string) error {
+	ns, err := resolveNamespace(s.namespace, namespace)
+	if err != nil {
+		return err
+	}
+	if err := validName(filename); err != nil {
+		return fmt.Errorf("filename: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO files (namespace, filename, content, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (namespace, filename) DO UPDATE SET content = excluded.content, updated_at = excluded.updated_at`,
+		ns, filename, content, time.Now().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("save file: %w", err)
+	}
+
+	s.logger.Debug("saved to sqlite",
+		zap.String("namespace", ns),
+		zap.String("filename", filename),
+		zap.Int("bytes", len(content)),
+	)
+
+	return nil
+}
+
+// LoadJSON loads JSON data from a file in the default namespace.
+func (s *SQLiteStore) LoadJSON(ctx context.Context, filename string, dest any) error {
+	return loadJSON(ctx, s, filename, dest)
+}
+
+// SaveJSON saves JSON data to a file in the default namespace.
+func (s *SQLiteStore) SaveJSON(ctx context.Context, filename string, data any) error {
+	return saveJSON(ctx, s, filename, data)
+}
+
+// GetGistID returns the default namespace.
+func (s *SQLiteStore) GetGistID() string {
+	return s.namespace
+}
+
+// Files returns every file in a namespace.
+func (s *SQLiteStore) Files(ctx context.Context, namespace string) (map[string]string, error) {
+	ns, err := resolveNamespace(s.namespace, []string{namespace})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT filename, content FROM files WHERE namespace = ?", ns)
+	if err != nil {
+		return nil, fmt.Errorf("query files: %w", err)
+	}
+	defer rows.Close()
+
+	files := make(map[string]string)
+	for rows.Next() {
+		var filename, content string
+		if err := rows.Scan(&filename, &content); err != nil {
+			return nil, fmt.Errorf("scan file: %w", err)
+		}
+		files[filename] = content
+	}
+	return files, rows.Err()
+}
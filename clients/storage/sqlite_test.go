@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	s, err := NewSQLiteStore(zap.NewNop(), filepath.Join(t.TempDir(), "polybot.db"), "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer s.Close()
+	testStore(t, s)
+}
+
+func TestSQLiteStore_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "polybot.db")
+	s, err := NewSQLiteStore(nil, path, "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Save(t.Context(), "hedges.json", `{"a":1}`, "hedge_tracker"); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	s.Close()
+
+	reopened, err := NewSQLiteStore(nil, path, "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer reopened.Close()
+	if content, err := reopened.Load(t.Context(), "hedges.json", "hedge_tracker"); err != nil || content != `{"a":1}` {
+		t.Errorf("expected row after reopen, got %q, %v", content, err)
+	}
+}
+
+func TestSQLiteStore_NoPath(t *testing.T) {
+	if _, err := NewSQLiteStore(nil, "", "cache"); err == nil {
+		t.Error("expected error without a path")
+	}
+}
@@ -0,0 +1,78 @@
+// Package storage provides the persistence abstraction behind every stateful
+// component, with local filesystem, SQLite and S3-compatible backends. Files
+// are grouped by namespace: a gist ID for the gist backend, a directory or key
+// prefix for the others.
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned when a file does not exist in its namespace.
+var ErrNotFound = errors.New("file not found")
+
+// Storage is the interface for persistent storage operations.
+// This allows for easy mocking in tests.
+type Storage interface {
+	IsEnabled() bool
+	Load(ctx context.Context, filename string, namespace ...string) (string, error)
+	Save(ctx context.Context, filename, content string, namespace ...string) error
+	LoadJSON(ctx context.Context, filename string, dest any) error
+	SaveJSON(ctx context.Context, filename string, data any) error
+	GetGistID() string // Default namespace
+}
+
+// Source lists every file stored in a namespace, for migrations.
+type Source interface {
+	Files(ctx context.Context, namespace string) (map[string]string, error)
+}
+
+// resolveNamespace returns the override if provided (non-empty), else the default.
+func resolveNamespace(def string, override []string) (string, error) {
+	namespace := def
+	if len(override) > 0 && override[0] != "" {
+		namespace = override[0]
+	}
+	if err := validName(namespace); err != nil {
+		return "", fmt.Errorf("namespace: %w", err)
+	}
+	return namespace, nil
+}
+
+// validName rejects names that could escape their directory.
+func validName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("empty name")
+	case name == "." || name == "..":
+		return fmt.Errorf("invalid name %q", name)
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("name %q contains a path separator", name)
+	}
+	return nil
+}
+
+// loadJSON loads a file from the default namespace and unmarshals it.
+func loadJSON(ctx context.Context, s Storage, filename string, dest any) error {
+	content, err := s.Load(ctx, filename)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(content), dest); err != nil {
+		return fmt.Errorf("unmarshal json: %w", err)
+	}
+	return nil
+}
+
+// saveJSON marshals data and saves it to the default namespace.
+func saveJSON(ctx context.Context, s Storage, filename string, data any) error {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	return s.Save(ctx, filename, string(jsonData))
+}
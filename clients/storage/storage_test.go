@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+// testStore runs the behavior every backend shares. s must be empty and have
+// the default namespace "cache".
+func testStore(t *testing.T, s interface {
+	Storage
+	Source
+}) {
+	t.Helper()
+	ctx := t.Context()
+
+	if !s.IsEnabled() || s.GetGistID() != "cache" {
+		t.Fatalf("expected enabled store with namespace cache, got %v %q", s.IsEnabled(), s.GetGistID())
+	}
+
+	// Missing files are reported as ErrNotFound
+	if _, err := s.Load(ctx, "wallet_cache.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	// Save and load in the default namespace, then overwrite
+	if err := s.Save(ctx, "wallet_cache.json", "v1"); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if err := s.Save(ctx, "wallet_cache.json", "v2"); err != nil {
+		t.Fatalf("overwrite failed: %v", err)
+	}
+	if content, err := s.Load(ctx, "wallet_cache.json"); err != nil || content != "v2" {
+		t.Errorf("expected v2, got %q, %v", content, err)
+	}
+
+	// Namespaces are isolated
+	if err := s.Save(ctx, "wallet_cache.json", "hedges", "hedge_tracker"); err != nil {
+		t.Fatalf("save to namespace failed: %v", err)
+	}
+	if content, _ := s.Load(ctx, "wallet_cache.json", "hedge_tracker"); content != "hedges" {
+		t.Errorf("expected namespaced content, got %q", content)
+	}
+	if content, _ := s.Load(ctx, "wallet_cache.json", ""); content != "v2" {
+		t.Errorf("expected empty override to use the default namespace, got %q", content)
+	}
+
+	// JSON helpers use the default namespace
+	if err := s.SaveJSON(ctx, "settings.json", map[string]int{"version": 3}); err != nil {
+		t.Fatalf("save json failed: %v", err)
+	}
+	var dest map[string]int
+	if err := s.LoadJSON(ctx, "settings.json", &dest); err != nil || dest["version"] != 3 {
+		t.Errorf("unexpected json load: %v, %v", dest, err)
+	}
+	if err := s.LoadJSON(ctx, "wallet_cache.json", &dest); err == nil {
+		t.Error("expected unmarshal error for non-JSON content")
+	}
+
+	// Files lists one namespace
+	files, err := s.Files(ctx, "cache")
+	if err != nil {
+		t.Fatalf("files failed: %v", err)
+	}
+	if len(files) != 2 || files["wallet_cache.json"] != "v2" || files["settings.json"] == "" {
+		t.Errorf("unexpected files: %v", files)
+	}
+	if files, err := s.Files(ctx, "empty"); err != nil || len(files) != 0 {
+		t.Errorf("expected no files in an unused namespace, got %v, %v", files, err)
+	}
+
+	// Names that could escape their directory are rejected
+	for _, name := range []string{"../x", "a/b", `a\b`, ".."} {
+		if err := s.Save(ctx, name, "x"); err == nil {
+			t.Errorf("expected error saving %q", name)
+		}
+		if err := s.Save(ctx, "x.json", "x", name); err == nil {
+			t.Errorf("expected error saving to namespace %q", name)
+		}
+	}
+}
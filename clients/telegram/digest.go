@@ -0,0 +1,87 @@
+package telegram
+
+import (
+	"fmt"
+	"polybot/clients/notifier"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// SendDigest sends an alert digest message.
+// Implements notifier.DigestNotifier interface.
+func (tc *TelegramClient) SendDigest(digest notifier.AlertDigest) {
+	if !tc.IsEnabled() {
+		tc.logger.Warn("telegram not configured, skipping digest")
+		return
+	}
+
+	if err := tc.sendMessage(tc.buildDigestMessage(digest)); err != nil {
+		tc.logger.Error("failed to send telegram digest", zap.Error(err))
+		return
+	}
+
+	tc.logger.Info("sent telegram alert digest", zap.Int("alerts", digest.TotalAlerts))
+}
+
+func (tc *TelegramClient) buildDigestMessage(digest notifier.AlertDigest) string {
+	var sb strings.Builder
+
+	sb.WriteString("*📊 Alert Digest*\n\n")
+	sb.WriteString(fmt.Sprintf("*%d alerts* in the last %s\n", digest.TotalAlerts, digest.WindowLabel()))
+	sb.WriteString(fmt.Sprintf("Last hour: %d · 24h: %d · 7d: %d\n", digest.AlertsLastHour, digest.AlertsLastDay, digest.AlertsLastWeek))
+
+	if len(digest.ReasonCounts) > 0 {
+		sb.WriteString("\n*📋 By Reason*\n")
+		for _, rc := range digest.ReasonCounts {
+			sb.WriteString(fmt.Sprintf("• %s: %d\n", escapeMarkdown(string(rc.Reason)), rc.Count))
+		}
+	}
+
+	if len(digest.TopWallets) > 0 {
+		sb.WriteString("\n*👛 Top Wallets*\n")
+		for _, w := range digest.TopWallets {
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			name = escapeMarkdown(name)
+			if w.URL != "" {
+				name = fmt.Sprintf("[%s](%s)", name, w.URL)
+			}
+			sb.WriteString(fmt.Sprintf("• %s: %d alerts, $%.0f\n", name, w.Count, w.Notional))
+		}
+	}
+
+	if len(digest.TopMarkets) > 0 {
+		sb.WriteString("\n*📈 Top Markets*\n")
+		for _, m := range digest.TopMarkets {
+			title := m.Title
+			if title == "" {
+				title = shortAddress(m.ConditionID)
+			}
+			title = escapeMarkdown(title)
+			if m.URL != "" {
+				title = fmt.Sprintf("[%s](%s)", title, m.URL)
+			}
+			sb.WriteString(fmt.Sprintf("• %s: %d alerts, $%.0f\n", title, m.Count, m.Notional))
+		}
+	}
+
+	if len(digest.LargestTrades) > 0 {
+		sb.WriteString("\n*💰 Largest Trades*\n")
+		for _, a := range digest.LargestTrades {
+			sideEmoji := "🟢"
+			if strings.ToUpper(a.Side) == "SELL" {
+				sideEmoji = "🔴"
+			}
+			market := escapeMarkdown(a.MarketTitle)
+			if a.MarketURL != "" {
+				market = fmt.Sprintf("[%s](%s)", market, a.MarketURL)
+			}
+			sb.WriteString(fmt.Sprintf("%s *$%.0f* %s @ $%.3f: %s\n", sideEmoji, a.Notional, escapeMarkdown(a.Outcome), a.Price, market))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"fmt"
+	"polybot/clients/notifier"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// SendMarketAlert sends a market anomaly message.
+// Implements notifier.MarketAlertNotifier interface.
+func (tc *TelegramClient) SendMarketAlert(alert notifier.MarketAlert) {
+	if !tc.IsEnabled() {
+		tc.logger.Warn("telegram not configured, skipping market alert")
+		return
+	}
+
+	if err := tc.sendMessage(tc.buildMarketAlertMessage(alert)); err != nil {
+		tc.logger.Error("failed to send telegram market alert", zap.Error(err))
+		return
+	}
+
+	tc.logger.Info("sent telegram market alert",
+		zap.String("kind", string(alert.Kind)),
+		zap.String("market", alert.MarketTitle),
+	)
+}
+
+func (tc *TelegramClient) buildMarketAlertMessage(alert notifier.MarketAlert) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("*%s*\n\n", alert.Title()))
+	title := escapeMarkdown(alert.MarketTitle)
+	if alert.MarketURL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, alert.MarketURL)
+	}
+	sb.WriteString(fmt.Sprintf("*Market:* %s\n", title))
+	sb.WriteString(escapeMarkdown(alert.Summary()) + "\n")
+
+	if len(alert.TopWallets) > 0 {
+		sb.WriteString("\n*👛 Top Wallets*\n")
+		for _, w := range alert.TopWallets {
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			name = escapeMarkdown(name)
+			if w.URL != "" {
+				name = fmt.Sprintf("[%s](%s)", name, w.URL)
+			}
+			sb.WriteString(fmt.Sprintf("• %s: %d trades, 🟢 $%.0f / 🔴 $%.0f\n", name, w.Trades, w.BuyNotional, w.SellNotional))
+		}
+	}
+
+	return sb.String()
+}
@@ -2,11 +2,15 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"polybot/clients/notifier"
 	"polybot/config"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +19,10 @@ import (
 
 const telegramAPIURL = "https://api.telegram.org/bot%s/%s"
 
+// maxClusterWallets caps the coordinated entry wallets listed, keeping alerts
+// under Telegram's message length limit.
+const maxClusterWallets = 20
+
 // TelegramClient sends alerts to Telegram.
 // Implements notifier.Notifier interface.
 type TelegramClient struct {
@@ -23,6 +31,7 @@ type TelegramClient struct {
 	chatID   string
 	isProd   bool
 	client   *http.Client
+	apiURL   string // Format string for API URLs (overridable for tests)
 }
 
 func NewTelegramClient(logger *zap.Logger, cfg *config.Config) *TelegramClient {
@@ -56,9 +65,32 @@ func NewTelegramClient(logger *zap.Logger, cfg *config.Config) *TelegramClient {
 		chatID:   chatID,
 		isProd:   cfg.IsProd,
 		client:   &http.Client{Timeout: 10 * time.Second},
+		apiURL:   telegramAPIURL,
+	}
+}
+
+// WithChat returns a client that sends to another chat with the same bot.
+func (tc *TelegramClient) WithChat(chatID string) *TelegramClient {
+	return &TelegramClient{
+		logger:   tc.logger.With(zap.String("chatID", chatID)),
+		botToken: tc.botToken,
+		chatID:   chatID,
+		isProd:   tc.isProd,
+		client:   tc.client,
+		apiURL:   tc.apiURL,
 	}
 }
 
+// Name returns the channel name. Implements notifier.DeliveryNotifier interface.
+func (tc *TelegramClient) Name() string {
+	return "telegram"
+}
+
+// IsEnabled returns true if the bot token and chat are configured.
+func (tc *TelegramClient) IsEnabled() bool {
+	return tc.botToken != "" && tc.chatID != ""
+}
+
 // SendTradeAlert sends a trade alert notification.
 // Implements notifier.Notifier interface.
 func (tc *TelegramClient) SendTradeAlert(alert notifier.TradeAlert) {
@@ -67,17 +99,94 @@ func (tc *TelegramClient) SendTradeAlert(alert notifier.TradeAlert) {
 		return
 	}
 
+	if err := tc.DeliverTradeAlert(context.Background(), alert); err != nil {
+		tc.logger.Error("failed to send telegram message", zap.Error(err))
+	}
+}
+
+// DeliverTradeAlert sends a trade alert and returns any error.
+// Implements notifier.DeliveryNotifier interface.
+func (tc *TelegramClient) DeliverTradeAlert(ctx context.Context, alert notifier.TradeAlert) error {
+	if !tc.IsEnabled() {
+		return notifier.Permanent(errors.New("telegram not configured"))
+	}
+
 	message := tc.buildAlertMessage(alert)
 
-	if err := tc.sendMessage(message); err != nil {
-		tc.logger.Error("failed to send telegram message", zap.Error(err))
-		return
+	if err := tc.sendMessageContext(ctx, message); err != nil {
+		return err
 	}
 
 	tc.logger.Info("sent telegram trade alert",
 		zap.String("trader", alert.TraderName),
 		zap.String("market", alert.MarketTitle),
 	)
+	return nil
+}
+
+// DeliverThreadedAlert sends a trade alert, as a reply to replyTo when it was
+// sent to this client's chat, and returns the sent message.
+// Implements notifier.ThreadedNotifier interface.
+func (tc *TelegramClient) DeliverThreadedAlert(ctx context.Context, alert notifier.TradeAlert, replyTo *notifier.MessageRef) (notifier.MessageRef, error) {
+	if !tc.IsEnabled() {
+		return notifier.MessageRef{}, notifier.Permanent(errors.New("telegram not configured"))
+	}
+
+	payload := map[string]interface{}{
+		"chat_id":    tc.chatID,
+		"text":       tc.buildAlertMessage(alert),
+		"parse_mode": "Markdown",
+	}
+	isReply := tc.addReplyTo(payload, replyTo)
+
+	var sent Message
+	if err := tc.callMethod(ctx, tc.client, "sendMessage", payload, &sent); err != nil {
+		return notifier.MessageRef{}, err
+	}
+
+	tc.logger.Info("sent telegram trade alert",
+		zap.String("trader", alert.TraderName),
+		zap.String("market", alert.MarketTitle),
+		zap.Bool("reply", isReply),
+	)
+	return notifier.MessageRef{
+		ChatID:    tc.chatID,
+		MessageID: strconv.FormatInt(sent.MessageID, 10),
+		SentAt:    time.Now(),
+	}, nil
+}
+
+// AnnotateMessage posts the note as a reply to a sent alert. Alerts are not
+// edited in place since Telegram returns their text without the Markdown.
+// Implements notifier.ThreadedNotifier interface.
+func (tc *TelegramClient) AnnotateMessage(ctx context.Context, ref notifier.MessageRef, note string) error {
+	if !tc.IsEnabled() {
+		return errors.New("telegram not configured")
+	}
+
+	payload := map[string]interface{}{
+		"chat_id": tc.chatID,
+		"text":    note,
+	}
+	if !tc.addReplyTo(payload, &ref) {
+		return fmt.Errorf("message %s was not sent to this chat", ref.MessageID)
+	}
+	return tc.callMethod(ctx, tc.client, "sendMessage", payload, nil)
+}
+
+// addReplyTo makes a sendMessage payload reply to ref if it is in this chat.
+// The message is still sent if the original was deleted.
+func (tc *TelegramClient) addReplyTo(payload map[string]interface{}, ref *notifier.MessageRef) bool {
+	if ref == nil || ref.ChatID != tc.chatID {
+		return false
+	}
+	messageID, err := strconv.ParseInt(ref.MessageID, 10, 64)
+	if err != nil {
+		return false
+	}
+	payload["reply_to_message_id"] = messageID
+	payload["allow_sending_without_reply"] = true
+	return true
 }
 
 func (tc *TelegramClient) buildAlertMessage(alert notifier.TradeAlert) string {
@@ -85,6 +194,9 @@ func (tc *TelegramClient) buildAlertMessage(alert notifier.TradeAlert) string {
 
 	// Title based on reasons
 	title := tc.buildAlertTitle(alert.Reasons)
+	if alert.IsUpdate {
+		title = "🔁 Update: " + title
+	}
 	sb.WriteString(fmt.Sprintf("*%s*\n\n", escapeMarkdown(title)))
 
 	// Market info
@@ -115,8 +227,46 @@ func (tc *TelegramClient) buildAlertMessage(alert notifier.TradeAlert) string {
 		sideEmoji = "🔴"
 	}
 	sb.WriteString(fmt.Sprintf("*Side:* %s %s\n", sideEmoji, alert.Side))
-	sb.WriteString(fmt.Sprintf("*Trade:* %.2f shares @ $%.3f\n", alert.Shares, alert.Price))
-	sb.WriteString(fmt.Sprintf("*Notional:* $%.2f\n\n", alert.Notional))
+	if alert.MergedFills > 1 {
+		sb.WriteString(fmt.Sprintf("*Trade:* %d fills: %.2f shares @ $%.3f avg\n", alert.MergedFills, alert.Shares, alert.Price))
+	} else if len(alert.Fills) > 1 {
+		sb.WriteString(fmt.Sprintf("*Trade:* %.2f shares @ $%.3f VWAP (%d maker fills)\n", alert.Shares, alert.Price, len(alert.Fills)))
+	} else {
+		sb.WriteString(fmt.Sprintf("*Trade:* %.2f shares @ $%.3f\n", alert.Shares, alert.Price))
+	}
+	sb.WriteString(fmt.Sprintf("*Notional:* $%.2f\n", alert.Notional))
+	if alert.TotalFills > 1 {
+		sb.WriteString(fmt.Sprintf("*Running Total:* %d fills · %.2f shares · $%.2f\n",
+			alert.TotalFills, alert.TotalShares, alert.TotalNotional))
+	}
+	if alert.HasImpactInfo {
+		sb.WriteString(fmt.Sprintf("*Market Impact:* %s\n", alert.ImpactSummary()))
+	}
+	if alert.HasBookInfo {
+		sb.WriteString(fmt.Sprintf("*Order Book:* %s\n", alert.BookSummary()))
+	}
+	if alert.WalletClusterID != "" {
+		sb.WriteString(fmt.Sprintf("*Wallet Cluster:* %s\n", escapeMarkdown(alert.WalletClusterSummary())))
+	}
+	if alert.HasClusterInfo {
+		sb.WriteString(fmt.Sprintf("*Coordinated Entry:* %s\n", escapeMarkdown(alert.ClusterSummary())))
+		for i, w := range alert.ClusterWallets {
+			if i == maxClusterWallets {
+				sb.WriteString(fmt.Sprintf("• … and %d more\n", len(alert.ClusterWallets)-i))
+				break
+			}
+			name := w.Name
+			if name == "" {
+				name = shortAddress(w.Address)
+			}
+			name = escapeMarkdown(name)
+			if w.URL != "" {
+				name = fmt.Sprintf("[%s](%s)", name, w.URL)
+			}
+			sb.WriteString(fmt.Sprintf("• %s: $%.0f, %.0f shares, %d markets\n", name, w.Notional, w.Shares, w.UniqueMarkets))
+		}
+	}
+	sb.WriteString("\n")
 
 	// Position info
 	if alert.HasInventory {
@@ -170,6 +320,8 @@ func (tc *TelegramClient) buildAlertTitle(reasons []notifier.AlertReason) string
 	hasPerfectExitTiming := false
 	hasStealthAccumulation := false
 	hasPreMovePositioning := false
+	hasMarketImpact := false
+	hasCoordinatedEntry := false
 
 	for _, r := range reasons {
 		switch r {
@@ -205,6 +357,10 @@ func (tc *TelegramClient) buildAlertTitle(reasons []notifier.AlertReason) string
 			hasStealthAccumulation = true
 		case notifier.AlertReasonPreMovePositioning:
 			hasPreMovePositioning = true
+		case notifier.AlertReasonMarketImpact:
+			hasMarketImpact = true
+		case notifier.AlertReasonCoordinatedEntry:
+			hasCoordinatedEntry = true
 		}
 	}
 
@@ -257,6 +413,12 @@ func (tc *TelegramClient) buildAlertTitle(reasons []notifier.AlertReason) string
 	if hasPreMovePositioning {
 		count++
 	}
+	if hasMarketImpact {
+		count++
+	}
+	if hasCoordinatedEntry {
+		count++
+	}
 
 	if count >= 3 {
 		return "🚨 Multiple Alert Triggers"
@@ -370,7 +532,32 @@ func (tc *TelegramClient) buildAlertTitle(reasons []notifier.AlertReason) string
 		return "🎯 Pre-Move Positioning + Massive Trade"
 	}
 
+	// Market impact combos (size relative to the market)
+	if hasMarketImpact && hasHighWinRate {
+		return "💥 Market Impact + High Win Rate"
+	}
+	if hasMarketImpact && hasNewWallet {
+		return "💥 Market Impact + New Wallet"
+	}
+	if hasMarketImpact && hasLowActivity {
+		return "💥 Market Impact + Low Activity"
+	}
+	if hasMarketImpact && hasMassiveTrade {
+		return "💥 Massive Trade + Market Impact"
+	}
+
+	// Coordinated entry combos (fresh wallets buying together)
+	if hasCoordinatedEntry && hasLowActivity {
+		return "👥 Coordinated Entry + Low Activity"
+	}
+	if hasCoordinatedEntry && hasNewWallet {
+		return "👥 Coordinated Entry + New Wallet"
+	}
+
 	// Single reasons - Advanced patterns (highest priority)
+	if hasCoordinatedEntry {
+		return "👥 Coordinated Entry"
+	}
 	if hasPreMovePositioning {
 		return "🎯 Pre-Move Positioning"
 	}
@@ -397,6 +584,9 @@ func (tc *TelegramClient) buildAlertTitle(reasons []notifier.AlertReason) string
 	if hasContrarianWinner {
 		return "🏆 Proven Contrarian Winner"
 	}
+	if hasMarketImpact {
+		return "💥 Outsized Market Impact"
+	}
 	if hasMassiveTrade {
 		return "🐋 Massive Trade"
 	}
@@ -422,32 +612,95 @@ func (tc *TelegramClient) buildAlertTitle(reasons []notifier.AlertReason) string
 }
 
 func (tc *TelegramClient) sendMessage(text string) error {
-	url := fmt.Sprintf(telegramAPIURL, tc.botToken, "sendMessage")
+	return tc.sendMessageContext(context.Background(), text)
+}
 
+// telegramErrorResponse is the error body returned by the Bot API.
+type telegramErrorResponse struct {
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"` // Seconds to wait when flood controlled
+	} `json:"parameters"`
+}
+
+func (tc *TelegramClient) sendMessageContext(ctx context.Context, text string) error {
 	payload := map[string]interface{}{
 		"chat_id":    tc.chatID,
 		"text":       text,
 		"parse_mode": "Markdown",
 	}
+	return tc.callMethod(ctx, tc.client, "sendMessage", payload, nil)
+}
+
+// callMethod posts a Bot API method and decodes its result into out (if non-nil).
+func (tc *TelegramClient) callMethod(ctx context.Context, client *http.Client, method string, payload interface{}, out interface{}) error {
+	apiURL := tc.apiURL
+	if apiURL == "" {
+		apiURL = telegramAPIURL
+	}
+	url := fmt.Sprintf(apiURL, tc.botToken, method)
 
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	resp, err := tc.client.Post(url, "application/json", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+		return classifyTelegramError(resp)
 	}
 
+	if out == nil {
+		return nil
+	}
+
+	var result struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if err := json.Unmarshal(result.Result, out); err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
 	return nil
 }
 
+// classifyTelegramError maps a failed Bot API response to retry semantics.
+func classifyTelegramError(resp *http.Response) error {
+	var apiErr telegramErrorResponse
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	_ = json.Unmarshal(respBody, &apiErr)
+
+	err := fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+	if apiErr.Description != "" {
+		err = fmt.Errorf("telegram API returned status %d: %s", resp.StatusCode, apiErr.Description)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &notifier.RetryAfterError{
+			Err:        err,
+			RetryAfter: time.Duration(apiErr.Parameters.RetryAfter) * time.Second,
+		}
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		// Bad markdown, unknown chat or revoked token won't succeed on retry
+		return notifier.Permanent(err)
+	}
+	return err
+}
+
 // Close cleans up resources. Implements notifier.Notifier interface.
 func (tc *TelegramClient) Close() error {
 	return nil
@@ -1,10 +1,14 @@
 package telegram
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"polybot/clients/notifier"
 	"polybot/config"
+	"strings"
 	"testing"
 	"time"
 
@@ -147,6 +151,131 @@ func TestSendTradeAlert_APIError(t *testing.T) {
 	client.SendTradeAlert(alert)
 }
 
+func TestDeliverTradeAlert_Success(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &TelegramClient{
+		logger:   zap.NewNop(),
+		botToken: "test-token",
+		chatID:   "test-chat",
+		client:   server.Client(),
+		apiURL:   server.URL + "/bot%s/%s",
+	}
+
+	if err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{TraderName: "test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/bottest-token/sendMessage" {
+		t.Errorf("unexpected request path %s", gotPath)
+	}
+}
+
+func TestDeliverTradeAlert_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`))
+	}))
+	defer server.Close()
+
+	client := &TelegramClient{
+		logger:   zap.NewNop(),
+		botToken: "test-token",
+		chatID:   "test-chat",
+		client:   server.Client(),
+		apiURL:   server.URL + "/bot%s/%s",
+	}
+
+	err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{TraderName: "test"})
+	var retryAfter *notifier.RetryAfterError
+	if !errors.As(err, &retryAfter) {
+		t.Fatalf("expected retry after error, got %v", err)
+	}
+	if retryAfter.RetryAfter != 7*time.Second {
+		t.Errorf("expected retry after 7s, got %v", retryAfter.RetryAfter)
+	}
+}
+
+func TestDeliverTradeAlert_BadRequestIsPermanent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`))
+	}))
+	defer server.Close()
+
+	client := &TelegramClient{
+		logger:   zap.NewNop(),
+		botToken: "test-token",
+		chatID:   "test-chat",
+		client:   server.Client(),
+		apiURL:   server.URL + "/bot%s/%s",
+	}
+
+	err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{TraderName: "test"})
+	var permanent *notifier.PermanentError
+	if !errors.As(err, &permanent) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+}
+
+func TestDeliverTradeAlert_NotConfigured(t *testing.T) {
+	client := &TelegramClient{logger: zap.NewNop()}
+
+	err := client.DeliverTradeAlert(context.Background(), notifier.TradeAlert{})
+	var permanent *notifier.PermanentError
+	if !errors.As(err, &permanent) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+}
+
+func TestDeliverThreadedAlert_RepliesToThreadRoot(t *testing.T) {
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+		w.Write([]byte(`{"ok":true,"result":{"message_id":43,"chat":{"id":1}}}`))
+	}))
+	defer server.Close()
+
+	client := &TelegramClient{
+		logger:   zap.NewNop(),
+		botToken: "test-token",
+		chatID:   "test-chat",
+		client:   server.Client(),
+		apiURL:   server.URL + "/bot%s/%s",
+	}
+
+	root := &notifier.MessageRef{ChatID: "test-chat", MessageID: "42"}
+	ref, err := client.DeliverThreadedAlert(context.Background(), notifier.TradeAlert{TraderName: "test"}, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref.ChatID != "test-chat" || ref.MessageID != "43" {
+		t.Errorf("unexpected message ref: %+v", ref)
+	}
+	if payloads[0]["reply_to_message_id"] != float64(42) || payloads[0]["allow_sending_without_reply"] != true {
+		t.Errorf("expected reply to message 42, got %+v", payloads[0])
+	}
+
+	// Roots from another chat (e.g. before a chat change) are not replied to
+	client.DeliverThreadedAlert(context.Background(), notifier.TradeAlert{}, &notifier.MessageRef{ChatID: "old-chat", MessageID: "42"})
+	if _, ok := payloads[1]["reply_to_message_id"]; ok {
+		t.Errorf("expected no reply across chats, got %+v", payloads[1])
+	}
+
+	if err := client.AnnotateMessage(context.Background(), *root, "Resolved"); err != nil {
+		t.Fatalf("unexpected annotate error: %v", err)
+	}
+	if payloads[2]["text"] != "Resolved" || payloads[2]["reply_to_message_id"] != float64(42) {
+		t.Errorf("expected outcome posted as a reply, got %+v", payloads[2])
+	}
+}
+
 func TestBuildAlertMessage_FullAlert(t *testing.T) {
 	client := &TelegramClient{
 		logger: zap.NewNop(),
@@ -390,6 +519,16 @@ func TestBuildAlertTitle_AllReasons(t *testing.T) {
 			reasons:  []notifier.AlertReason{},
 			expected: "🚨 Trade Alert",
 		},
+		{
+			name:     "coordinated entry only",
+			reasons:  []notifier.AlertReason{notifier.AlertReasonCoordinatedEntry},
+			expected: "👥 Coordinated Entry",
+		},
+		{
+			name:     "coordinated entry + low activity",
+			reasons:  []notifier.AlertReason{notifier.AlertReasonLowActivity, notifier.AlertReasonCoordinatedEntry},
+			expected: "👥 Coordinated Entry + Low Activity",
+		},
 		{
 			name:     "low activity + high win rate",
 			reasons:  []notifier.AlertReason{notifier.AlertReasonLowActivity, notifier.AlertReasonHighWinRate},
@@ -564,3 +703,126 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestBuildDigestMessage(t *testing.T) {
+	client := &TelegramClient{logger: zap.NewNop()}
+
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	digest := notifier.AlertDigest{
+		WindowStart:   start,
+		WindowEnd:     start.Add(24 * time.Hour),
+		TotalAlerts:   40,
+		ReasonCounts:  []notifier.DigestReasonCount{{Reason: notifier.AlertReasonMassiveTrade, Count: 6}},
+		TopWallets:    []notifier.DigestWallet{{Address: "0xabc", Name: "big_whale", Count: 4, Notional: 12000}},
+		TopMarkets:    []notifier.DigestMarket{{ConditionID: "0xcond", Title: "Will it rain?", URL: "https://polymarket.com/event/rain", Count: 7, Notional: 30000}},
+		LargestTrades: []notifier.TradeAlert{{Side: "BUY", Notional: 9000, Outcome: "No", Price: 0.1, MarketTitle: "Will it rain?"}},
+	}
+
+	msg := client.buildDigestMessage(digest)
+
+	for _, want := range []string{
+		"*40 alerts* in the last 24h",
+		"massive\\_trade: 6",
+		"big\\_whale: 4 alerts, $12000",
+		"[Will it rain?](https://polymarket.com/event/rain): 7 alerts",
+		"🟢 *$9000* No @ $0.100",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestSendDigest_NotConfigured(t *testing.T) {
+	client := &TelegramClient{logger: zap.NewNop()}
+
+	// Should not panic
+	client.SendDigest(notifier.AlertDigest{TotalAlerts: 1})
+}
+
+func TestBuildMarketAlertMessage(t *testing.T) {
+	client := &TelegramClient{logger: zap.NewNop()}
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	alert := notifier.MarketAlert{
+		Kind:        notifier.MarketAlertPriceMove,
+		MarketTitle: "Will it rain?",
+		Outcome:     "Yes",
+		WindowStart: start,
+		WindowEnd:   start.Add(15 * time.Minute),
+		Trades:      12,
+		Notional:    20500,
+		PriceBefore: 0.41,
+		PriceAfter:  0.58,
+		TopWallets:  []notifier.MarketAlertWallet{{Address: "0xabc", Name: "big_whale", Trades: 4, BuyNotional: 12000}},
+	}
+
+	msg := client.buildMarketAlertMessage(alert)
+
+	for _, want := range []string{
+		"*⚡ Sharp Price Move*",
+		"Yes moved from 41¢ to 58¢ in 15m across 12 trades ($20500)",
+		"big\\_whale: 4 trades, 🟢 $12000 / 🔴 $0",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestSendMarketAlert_NotConfigured(t *testing.T) {
+	client := &TelegramClient{logger: zap.NewNop()}
+
+	// Should not panic
+	client.SendMarketAlert(notifier.MarketAlert{Kind: notifier.MarketAlertVolumeSpike})
+}
+
+func TestBuildAlertMessage_CoordinatedEntry(t *testing.T) {
+	client := &TelegramClient{
+		logger: zap.NewNop(),
+	}
+
+	alert := notifier.TradeAlert{
+		TraderName: "TestTrader",
+		Side:       "BUY",
+		Outcome:    "Yes",
+		Reasons:    []notifier.AlertReason{notifier.AlertReasonCoordinatedEntry},
+		ClusterWallets: []notifier.ClusterWallet{
+			{Address: "0x1234567890abcdef1234567890abcdef12345678", URL: "https://polymarket.com/profile/0x123", UniqueMarkets: 1, Shares: 10000, Notional: 4200},
+			{Address: "0xabc", Name: "fresh_one", Shares: 8000, Notional: 3360},
+		},
+		ClusterShares:   18000,
+		ClusterNotional: 7560,
+		ClusterSpanSecs: 600,
+		HasClusterInfo:  true,
+	}
+
+	msg := client.buildAlertMessage(alert)
+	for _, want := range []string{
+		"*Coordinated Entry:* 2 fresh wallets bought Yes within 10m",
+		"• [0x1234…345678](https://polymarket.com/profile/0x123): $4200, 10000 shares, 1 markets",
+		"• fresh\\_one: $3360, 8000 shares, 0 markets",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestBuildAlertMessage_WalletCluster(t *testing.T) {
+	client := &TelegramClient{
+		logger: zap.NewNop(),
+	}
+
+	msg := client.buildAlertMessage(notifier.TradeAlert{
+		TraderName:           "TestTrader",
+		Side:                 "BUY",
+		Reasons:              []notifier.AlertReason{notifier.AlertReasonLowActivity},
+		WalletClusterID:      "C-1a2b3c4d",
+		WalletClusterSize:    3,
+		WalletClusterSignals: []string{"copying"},
+	})
+	if !strings.Contains(msg, "*Wallet Cluster:* C-1a2b3c4d · 3 wallets · copying") {
+		t.Errorf("expected wallet cluster line, got:\n%s", msg)
+	}
+}
@@ -0,0 +1,114 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"polybot/clients/notifier"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pollTimeoutSlack is added to the long-poll timeout for the HTTP request so
+// the server can answer before the client gives up.
+const pollTimeoutSlack = 10 * time.Second
+
+// Update is an incoming Bot API update. Only messages are requested.
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// Message is a chat message received by the bot.
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Date      int64  `json:"date"`
+	Text      string `json:"text"`
+}
+
+// User is the sender of a message.
+type User struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+}
+
+// Chat is the chat a message was sent in.
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// Command parses a bot command such as "/wallet@polybot 0xabc 1w" into its
+// lowercased name ("wallet") and arguments. Returns an empty name if the
+// message is not a command.
+func (m *Message) Command() (string, []string) {
+	fields := strings.Fields(m.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", nil
+	}
+	name := strings.TrimPrefix(fields[0], "/")
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(name), fields[1:]
+}
+
+// SenderID returns the sender's user ID as a string, or "" if unknown.
+func (m *Message) SenderID() string {
+	if m.From == nil {
+		return ""
+	}
+	return strconv.FormatInt(m.From.ID, 10)
+}
+
+// ChatID returns the chat alerts are sent to.
+func (tc *TelegramClient) ChatID() string {
+	return tc.chatID
+}
+
+// GetUpdates long-polls the Bot API for messages after offset, waiting up to
+// timeout for one to arrive. Pass the last update ID + 1 as offset to
+// acknowledge earlier updates.
+func (tc *TelegramClient) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	if tc.botToken == "" {
+		return nil, notifier.Permanent(errors.New("telegram not configured"))
+	}
+
+	payload := map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+
+	// The shared client's timeout is shorter than a long poll
+	client := &http.Client{Transport: tc.client.Transport, Timeout: timeout + pollTimeoutSlack}
+
+	var updates []Update
+	if err := tc.callMethod(ctx, client, "getUpdates", payload, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// SendReply sends a Markdown message to the alert chat as a reply to a message.
+func (tc *TelegramClient) SendReply(ctx context.Context, replyToMessageID int64, text string) error {
+	if !tc.IsEnabled() {
+		return notifier.Permanent(errors.New("telegram not configured"))
+	}
+
+	payload := map[string]interface{}{
+		"chat_id":                  tc.chatID,
+		"text":                     text,
+		"parse_mode":               "Markdown",
+		"disable_web_page_preview": true,
+	}
+	if replyToMessageID != 0 {
+		payload["reply_to_message_id"] = replyToMessageID
+	}
+	return tc.callMethod(ctx, tc.client, "sendMessage", payload, nil)
+}
@@ -0,0 +1,103 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMessage_Command(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantName string
+		wantArgs int
+	}{
+		{"/wallet 0xabc 1w", "wallet", 2},
+		{"/Stats@polybot_bot", "stats", 0},
+		{"  /mute   0xabc   6h ", "mute", 2},
+		{"hello /wallet", "", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		msg := &Message{Text: tt.text}
+		name, args := msg.Command()
+		if name != tt.wantName || len(args) != tt.wantArgs {
+			t.Errorf("Command(%q) = %q, %v; want %q with %d args", tt.text, name, args, tt.wantName, tt.wantArgs)
+		}
+	}
+}
+
+func TestGetUpdates(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Write([]byte(`{"ok":true,"result":[{"update_id":5,"message":{"message_id":9,"from":{"id":42,"username":"alice"},"chat":{"id":-100123,"type":"supergroup"},"date":1700000000,"text":"/stats"}}]}`))
+	}))
+	defer server.Close()
+
+	client := &TelegramClient{
+		logger:   zap.NewNop(),
+		botToken: "test-token",
+		chatID:   "-100123",
+		client:   server.Client(),
+		apiURL:   server.URL + "/bot%s/%s",
+	}
+
+	updates, err := client.GetUpdates(context.Background(), 5, 30*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/bottest-token/getUpdates" {
+		t.Errorf("unexpected request path %s", gotPath)
+	}
+	if gotBody["offset"] != float64(5) || gotBody["timeout"] != float64(30) {
+		t.Errorf("unexpected request body %v", gotBody)
+	}
+	if len(updates) != 1 || updates[0].Message == nil {
+		t.Fatalf("expected 1 message update, got %+v", updates)
+	}
+	msg := updates[0].Message
+	if msg.Chat.ID != -100123 || msg.SenderID() != "42" || msg.Text != "/stats" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+}
+
+func TestGetUpdates_NotConfigured(t *testing.T) {
+	client := &TelegramClient{logger: zap.NewNop()}
+
+	if _, err := client.GetUpdates(context.Background(), 0, time.Second); err == nil {
+		t.Error("expected error without a bot token")
+	}
+}
+
+func TestSendReply(t *testing.T) {
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	client := &TelegramClient{
+		logger:   zap.NewNop(),
+		botToken: "test-token",
+		chatID:   "-100123",
+		client:   server.Client(),
+		apiURL:   server.URL + "/bot%s/%s",
+	}
+
+	if err := client.SendReply(context.Background(), 9, "*hi*"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBody["chat_id"] != "-100123" || gotBody["reply_to_message_id"] != float64(9) || gotBody["parse_mode"] != "Markdown" {
+		t.Errorf("unexpected request body %v", gotBody)
+	}
+}
@@ -0,0 +1,375 @@
+package webhook
+
+import (
+	"polybot/clients/notifier"
+	"time"
+)
+
+// Payload is the versioned JSON body POSTed to webhook endpoints.
+type Payload struct {
+	SchemaVersion int       `json:"schema_version"`
+	Event         string    `json:"event"`
+	ID            string    `json:"id"` // Same as the Idempotency-Key header
+	Alert         AlertData `json:"alert"`
+}
+
+// AlertData describes a trade alert. Optional sections are omitted when the
+// alert carries no data for them.
+type AlertData struct {
+	Reasons     []string    `json:"reasons"`
+	Timestamp   time.Time   `json:"timestamp"`
+	Trader      Trader      `json:"trader"`
+	Market      Market      `json:"market"`
+	Trade       Trade       `json:"trade"`
+	WalletStats WalletStats `json:"wallet_stats"`
+
+	Inventory      *Inventory      `json:"inventory,omitempty"`
+	ClosedPosition *ClosedPosition `json:"closed_position,omitempty"`
+	Hedge          *Hedge          `json:"hedge,omitempty"`
+	Resolution     *Resolution     `json:"resolution,omitempty"`
+	AsymmetricExit *AsymmetricExit `json:"asymmetric_exit,omitempty"`
+	Conviction     *Conviction     `json:"conviction,omitempty"`
+	PerfectExit    *PerfectExit    `json:"perfect_exit,omitempty"`
+	Stealth        *Stealth        `json:"stealth,omitempty"`
+	PreMove        *PreMove        `json:"pre_move,omitempty"`
+	Repeat         *Repeat         `json:"repeat,omitempty"`
+	Book           *Book           `json:"book,omitempty"`
+	Impact         *Impact         `json:"impact,omitempty"`
+	Cluster        *Cluster        `json:"cluster,omitempty"`
+	WalletCluster  *WalletCluster  `json:"wallet_cluster,omitempty"`
+}
+
+// Trader identifies the wallet behind the alert.
+type Trader struct {
+	Address string `json:"address"`
+	Name    string `json:"name,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// Market identifies the market traded.
+type Market struct {
+	ConditionID string `json:"condition_id"`
+	Title       string `json:"title"`
+	URL         string `json:"url,omitempty"`
+	Image       string `json:"image,omitempty"`
+}
+
+// Trade describes the alerted trade.
+type Trade struct {
+	Side     string  `json:"side"`
+	Outcome  string  `json:"outcome"`
+	Shares   float64 `json:"shares"`
+	Price    float64 `json:"price"` // VWAP when the order filled against several makers
+	Notional float64 `json:"notional"`
+	Fills    []Fill  `json:"fills,omitempty"` // Maker fills combined into the trade
+}
+
+// Fill is one maker fill of the alerted order.
+type Fill struct {
+	TradeID string  `json:"trade_id,omitempty"`
+	Maker   string  `json:"maker,omitempty"`
+	Shares  float64 `json:"shares"`
+	Price   float64 `json:"price"`
+}
+
+// WalletStats summarizes the wallet's history.
+type WalletStats struct {
+	UniqueMarkets int     `json:"unique_markets"`
+	WinRate       float64 `json:"win_rate"`
+	WinCount      int     `json:"win_count"`
+	LossCount     int     `json:"loss_count"`
+}
+
+// Inventory is the wallet's position in the market after the trade.
+type Inventory struct {
+	Shares   float64 `json:"shares"`
+	AvgPrice float64 `json:"avg_price"`
+	Value    float64 `json:"value"`
+}
+
+// ClosedPosition describes a position closed by the trade.
+type ClosedPosition struct {
+	CostBasis   float64 `json:"cost_basis"`
+	RealizedPnl float64 `json:"realized_pnl"`
+}
+
+// Hedge describes a hedge removal.
+type Hedge struct {
+	YesSizeBefore float64 `json:"yes_size_before"`
+	NoSizeBefore  float64 `json:"no_size_before"`
+	YesSizeAfter  float64 `json:"yes_size_after"`
+	NoSizeAfter   float64 `json:"no_size_after"`
+	SoldSide      string  `json:"sold_side"`
+	SoldPct       float64 `json:"sold_pct"`
+}
+
+// Resolution confirms a hedge removal after the market resolved.
+type Resolution struct {
+	WinningOutcome string `json:"winning_outcome"`
+	RemovedLoser   bool   `json:"removed_loser"`
+}
+
+// AsymmetricExit describes a wallet exiting winners faster than losers.
+type AsymmetricExit struct {
+	WinExits       int     `json:"win_exits"`
+	LossExits      int     `json:"loss_exits"`
+	WinAvgHoldSec  float64 `json:"win_avg_hold_sec"`
+	LossAvgHoldSec float64 `json:"loss_avg_hold_sec"`
+	Ratio          float64 `json:"ratio"`
+}
+
+// Conviction describes adding to an underwater position.
+type Conviction struct {
+	ExistingSize float64 `json:"existing_size"`
+	ExistingAvg  float64 `json:"existing_avg"`
+	CurrentPrice float64 `json:"current_price"`
+	LossPct      float64 `json:"loss_pct"`
+	AddedSize    float64 `json:"added_size"`
+	AddedValue   float64 `json:"added_value"`
+}
+
+// PerfectExit describes exit timing near price peaks.
+type PerfectExit struct {
+	Score        float64 `json:"score"`
+	Exits        int     `json:"exits"`
+	PerfectExits int     `json:"perfect_exits"`
+}
+
+// Stealth describes gradual accumulation.
+type Stealth struct {
+	TradeCount int     `json:"trade_count"`
+	TotalSize  float64 `json:"total_size"`
+	TotalValue float64 `json:"total_value"`
+	AvgPrice   float64 `json:"avg_price"`
+	SpreadMins int     `json:"spread_mins"`
+}
+
+// PreMove describes trading ahead of favorable price moves.
+type PreMove struct {
+	TotalTrades     int     `json:"total_trades"`
+	SuccessfulMoves int     `json:"successful_moves"`
+	AlphaScore      float64 `json:"alpha_score"`
+	AvgMoveSize     float64 `json:"avg_move_size"`
+}
+
+// Repeat carries running totals when repeats of an alert were merged.
+type Repeat struct {
+	IsUpdate      bool    `json:"is_update"` // Reports fills merged since an earlier alert
+	MergedFills   int     `json:"merged_fills"`
+	TotalFills    int     `json:"total_fills"`
+	TotalShares   float64 `json:"total_shares"`
+	TotalNotional float64 `json:"total_notional"`
+}
+
+// Book describes the token's order book around the trade.
+type Book struct {
+	MidBefore float64 `json:"mid_before,omitempty"` // When the order's first fill arrived
+	MidAfter  float64 `json:"mid_after"`
+	BestBid   float64 `json:"best_bid"`
+	BestAsk   float64 `json:"best_ask"`
+	BidDepth  float64 `json:"bid_depth"` // USD resting on the bid side
+	AskDepth  float64 `json:"ask_depth"` // USD resting on the ask side
+}
+
+// Impact is the trade's size relative to its market.
+type Impact struct {
+	Score     float64 `json:"score"`                // Largest ratio of a measure to its threshold
+	VolumePct float64 `json:"volume_pct,omitempty"` // Notional as a share of 24h volume
+	DepthPct  float64 `json:"depth_pct,omitempty"`  // Notional as a share of the book depth taken from
+	PriceMove float64 `json:"price_move,omitempty"` // Relative price move across the trade
+}
+
+// Cluster is a coordinated entry: fresh wallets buying the same outcome together.
+type Cluster struct {
+	Wallets  []ClusterWallet `json:"wallets"`
+	Shares   float64         `json:"shares"`
+	Notional float64         `json:"notional"`
+	SpanSecs float64         `json:"span_secs"` // From the first buy to the last
+}
+
+// ClusterWallet is one wallet of a coordinated entry.
+type ClusterWallet struct {
+	Address       string  `json:"address"`
+	Name          string  `json:"name,omitempty"`
+	URL           string  `json:"url,omitempty"`
+	UniqueMarkets int     `json:"unique_markets"`
+	Trades        int     `json:"trades"`
+	Shares        float64 `json:"shares"`
+	Notional      float64 `json:"notional"`
+}
+
+// WalletCluster is the group of wallets linked to the trader as one actor.
+type WalletCluster struct {
+	ID      string   `json:"id"`
+	Size    int      `json:"size"`              // Wallets in the cluster, including the trader
+	Signals []string `json:"signals,omitempty"` // How the cluster is linked
+}
+
+// BuildPayload converts a trade alert to the webhook schema.
+func BuildPayload(alert notifier.TradeAlert) Payload {
+	reasons := make([]string, len(alert.Reasons))
+	for i, r := range alert.Reasons {
+		reasons[i] = string(r)
+	}
+
+	data := AlertData{
+		Reasons:   reasons,
+		Timestamp: alert.Timestamp.UTC(),
+		Trader: Trader{
+			Address: alert.TraderAddress,
+			Name:    alert.TraderName,
+			URL:     alert.WalletURL,
+		},
+		Market: Market{
+			ConditionID: alert.ConditionID,
+			Title:       alert.MarketTitle,
+			URL:         alert.MarketURL,
+			Image:       alert.MarketImage,
+		},
+		Trade: Trade{
+			Side:     alert.Side,
+			Outcome:  alert.Outcome,
+			Shares:   alert.Shares,
+			Price:    alert.Price,
+			Notional: alert.Notional,
+		},
+		WalletStats: WalletStats{
+			UniqueMarkets: alert.UniqueMarkets,
+			WinRate:       alert.WinRate,
+			WinCount:      alert.WinCount,
+			LossCount:     alert.LossCount,
+		},
+	}
+
+	if len(alert.Fills) > 1 {
+		data.Trade.Fills = make([]Fill, len(alert.Fills))
+		for i, f := range alert.Fills {
+			data.Trade.Fills[i] = Fill{TradeID: f.TradeID, Maker: f.Maker, Shares: f.Shares, Price: f.Price}
+		}
+	}
+	if alert.HasInventory {
+		data.Inventory = &Inventory{
+			Shares:   alert.InventoryShares,
+			AvgPrice: alert.InventoryAvgPrice,
+			Value:    alert.InventoryValue,
+		}
+	}
+	if alert.HasClosedInfo {
+		data.ClosedPosition = &ClosedPosition{
+			CostBasis:   alert.ClosedCostBasis,
+			RealizedPnl: alert.ClosedRealizedPnl,
+		}
+	}
+	if alert.HasHedgeInfo {
+		data.Hedge = &Hedge{
+			YesSizeBefore: alert.HedgeYesSizeBefore,
+			NoSizeBefore:  alert.HedgeNoSizeBefore,
+			YesSizeAfter:  alert.HedgeYesSizeAfter,
+			NoSizeAfter:   alert.HedgeNoSizeAfter,
+			SoldSide:      alert.HedgeSoldSide,
+			SoldPct:       alert.HedgeSoldPct,
+		}
+	}
+	if alert.HasResolutionInfo {
+		data.Resolution = &Resolution{
+			WinningOutcome: alert.ResolutionWinner,
+			RemovedLoser:   alert.ResolutionRemovedLoser,
+		}
+	}
+	if alert.HasAsymmetricInfo {
+		data.AsymmetricExit = &AsymmetricExit{
+			WinExits:       alert.AsymmetricWinExits,
+			LossExits:      alert.AsymmetricLossExits,
+			WinAvgHoldSec:  alert.AsymmetricWinAvgHoldSec,
+			LossAvgHoldSec: alert.AsymmetricLossAvgHoldSec,
+			Ratio:          alert.AsymmetricRatio,
+		}
+	}
+	if alert.HasConvictionInfo {
+		data.Conviction = &Conviction{
+			ExistingSize: alert.ConvictionExistingSize,
+			ExistingAvg:  alert.ConvictionExistingAvg,
+			CurrentPrice: alert.ConvictionCurrentPrice,
+			LossPct:      alert.ConvictionLossPct,
+			AddedSize:    alert.ConvictionAddedSize,
+			AddedValue:   alert.ConvictionAddedValue,
+		}
+	}
+	if alert.HasPerfectExitInfo {
+		data.PerfectExit = &PerfectExit{
+			Score:        alert.PerfectExitScore,
+			Exits:        alert.PerfectExitCount,
+			PerfectExits: alert.PerfectExitPerfectCount,
+		}
+	}
+	if alert.HasStealthInfo {
+		data.Stealth = &Stealth{
+			TradeCount: alert.StealthTradeCount,
+			TotalSize:  alert.StealthTotalSize,
+			TotalValue: alert.StealthTotalValue,
+			AvgPrice:   alert.StealthAvgPrice,
+			SpreadMins: alert.StealthSpreadMins,
+		}
+	}
+	if alert.HasPreMoveInfo {
+		data.PreMove = &PreMove{
+			TotalTrades:     alert.PreMoveTotalTrades,
+			SuccessfulMoves: alert.PreMoveSuccessfulMoves,
+			AlphaScore:      alert.PreMoveAlphaScore,
+			AvgMoveSize:     alert.PreMoveAvgMoveSize,
+		}
+	}
+	if alert.HasBookInfo {
+		data.Book = &Book{
+			MidBefore: alert.BookPriceBefore,
+			MidAfter:  alert.BookPriceAfter,
+			BestBid:   alert.BookBestBid,
+			BestAsk:   alert.BookBestAsk,
+			BidDepth:  alert.BookBidDepth,
+			AskDepth:  alert.BookAskDepth,
+		}
+	}
+	if alert.HasImpactInfo {
+		data.Impact = &Impact{
+			Score:     alert.ImpactScore,
+			VolumePct: alert.ImpactVolumePct,
+			DepthPct:  alert.ImpactDepthPct,
+			PriceMove: alert.ImpactPriceMove,
+		}
+	}
+	if alert.HasClusterInfo {
+		cluster := &Cluster{
+			Wallets:  make([]ClusterWallet, len(alert.ClusterWallets)),
+			Shares:   alert.ClusterShares,
+			Notional: alert.ClusterNotional,
+			SpanSecs: alert.ClusterSpanSecs,
+		}
+		for i, w := range alert.ClusterWallets {
+			cluster.Wallets[i] = ClusterWallet(w)
+		}
+		data.Cluster = cluster
+	}
+	if alert.WalletClusterID != "" {
+		data.WalletCluster = &WalletCluster{
+			ID:      alert.WalletClusterID,
+			Size:    alert.WalletClusterSize,
+			Signals: alert.WalletClusterSignals,
+		}
+	}
+	if alert.IsUpdate || alert.TotalFills > 1 {
+		data.Repeat = &Repeat{
+			IsUpdate:      alert.IsUpdate,
+			MergedFills:   alert.MergedFills,
+			TotalFills:    alert.TotalFills,
+			TotalShares:   alert.TotalShares,
+			TotalNotional: alert.TotalNotional,
+		}
+	}
+
+	return Payload{
+		SchemaVersion: SchemaVersion,
+		Event:         EventTradeAlert,
+		ID:            IdempotencyKey(alert),
+		Alert:         data,
+	}
+}
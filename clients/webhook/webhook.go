@@ -0,0 +1,254 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"polybot/clients/notifier"
+	"polybot/config"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SchemaVersion is the version of the JSON payload. It is bumped only on
+// breaking changes; new optional fields may be added within a version.
+const SchemaVersion = 1
+
+// Request headers sent with every webhook.
+const (
+	HeaderSignature   = "X-Polybot-Signature" // "sha256=" + hex HMAC of "<timestamp>.<body>"
+	HeaderTimestamp   = "X-Polybot-Timestamp" // Unix seconds when the request was signed
+	HeaderEvent       = "X-Polybot-Event"     // Event type, e.g. "trade_alert"
+	HeaderIdempotency = "Idempotency-Key"     // Stable per alert, identical across retries
+)
+
+// EventTradeAlert is the event type for trade alerts.
+const EventTradeAlert = "trade_alert"
+
+// WebhookClient POSTs signed trade alerts to a single endpoint.
+// Implements notifier.DeliveryNotifier interface.
+type WebhookClient struct {
+	logger  *zap.Logger
+	name    string
+	url     string
+	secret  string
+	reasons map[notifier.AlertReason]bool // Empty = all reasons
+	client  *http.Client
+	now     func() time.Time
+}
+
+// NewWebhookClient creates a client for one configured endpoint.
+func NewWebhookClient(logger *zap.Logger, endpoint config.WebhookEndpointConfig, timeout time.Duration) (*WebhookClient, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	parsed, err := url.Parse(endpoint.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", endpoint.URL)
+	}
+
+	name := endpoint.Name
+	if name == "" {
+		name = parsed.Host
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	reasons := make(map[notifier.AlertReason]bool, len(endpoint.Reasons))
+	for _, r := range endpoint.Reasons {
+		reasons[notifier.AlertReason(strings.TrimSpace(r))] = true
+	}
+
+	return &WebhookClient{
+		logger:  logger.Named("webhook").With(zap.String("endpoint", name)),
+		name:    name,
+		url:     endpoint.URL,
+		secret:  endpoint.Secret,
+		reasons: reasons,
+		client:  &http.Client{Timeout: timeout},
+		now:     time.Now,
+	}, nil
+}
+
+// NewWebhookClients creates clients for all configured endpoints, skipping invalid ones.
+func NewWebhookClients(logger *zap.Logger, cfg *config.Config) []*WebhookClient {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	var clients []*WebhookClient
+	for _, endpoint := range cfg.Webhook.Endpoints {
+		client, err := NewWebhookClient(logger, endpoint, cfg.Webhook.Timeout)
+		if err != nil {
+			logger.Warn("skipping webhook endpoint", zap.Error(err))
+			continue
+		}
+		if endpoint.Secret == "" {
+			logger.Warn("webhook endpoint has no secret, requests will be unsigned", zap.String("endpoint", client.name))
+		}
+		clients = append(clients, client)
+	}
+
+	if len(clients) > 0 {
+		logger.Info("webhooks initialized", zap.Int("endpoints", len(clients)))
+	}
+	return clients
+}
+
+// Name returns the channel name. Implements notifier.DeliveryNotifier interface.
+func (wc *WebhookClient) Name() string {
+	return "webhook:" + wc.name
+}
+
+// Matches returns true if the endpoint wants the alert based on its reason filter.
+func (wc *WebhookClient) Matches(alert notifier.TradeAlert) bool {
+	if len(wc.reasons) == 0 {
+		return true
+	}
+	for _, r := range alert.Reasons {
+		if wc.reasons[r] {
+			return true
+		}
+	}
+	return false
+}
+
+// SendTradeAlert posts the alert and logs any failure.
+// Implements notifier.Notifier interface.
+func (wc *WebhookClient) SendTradeAlert(alert notifier.TradeAlert) {
+	if !wc.Matches(alert) {
+		return
+	}
+	if err := wc.DeliverTradeAlert(context.Background(), alert); err != nil {
+		wc.logger.Error("failed to send webhook", zap.Error(err))
+	}
+}
+
+// DeliverTradeAlert posts the alert and returns any error.
+// Implements notifier.DeliveryNotifier interface.
+func (wc *WebhookClient) DeliverTradeAlert(ctx context.Context, alert notifier.TradeAlert) error {
+	payload := BuildPayload(alert)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return notifier.Permanent(fmt.Errorf("marshal payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wc.url, bytes.NewReader(body))
+	if err != nil {
+		return notifier.Permanent(fmt.Errorf("create request: %w", err))
+	}
+
+	timestamp := strconv.FormatInt(wc.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("polybot-webhook/%d", SchemaVersion))
+	req.Header.Set(HeaderEvent, payload.Event)
+	req.Header.Set(HeaderIdempotency, payload.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if wc.secret != "" {
+		req.Header.Set(HeaderSignature, Sign(wc.secret, timestamp, body))
+	}
+
+	resp, err := wc.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return classifyStatus(resp)
+	}
+
+	wc.logger.Info("sent webhook trade alert",
+		zap.String("id", payload.ID),
+		zap.String("market", alert.MarketTitle),
+	)
+	return nil
+}
+
+// Close cleans up resources. Implements notifier.Notifier interface.
+func (wc *WebhookClient) Close() error {
+	return nil
+}
+
+// classifyStatus maps a non-2xx response to retry semantics.
+func classifyStatus(resp *http.Response) error {
+	err := fmt.Errorf("webhook returned status %d", resp.StatusCode)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		var retryAfter time.Duration
+		if secs, convErr := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); convErr == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		return &notifier.RetryAfterError{Err: err, RetryAfter: retryAfter}
+	case resp.StatusCode == http.StatusRequestTimeout:
+		return err
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return notifier.Permanent(err)
+	}
+	return err
+}
+
+// Sign returns the signature header value for a request body.
+// The HMAC-SHA256 covers "<timestamp>.<body>" so a captured request can't be replayed later.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature and that the timestamp is within tolerance of now.
+// Receivers can use it to authenticate requests.
+func Verify(secret, timestamp string, body []byte, signature string, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid timestamp")
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return errors.New("timestamp outside tolerance")
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// IdempotencyKey returns a stable key for an alert, so receivers can drop
+// duplicates caused by retries.
+func IdempotencyKey(alert notifier.TradeAlert) string {
+	reasons := make([]string, len(alert.Reasons))
+	for i, r := range alert.Reasons {
+		reasons[i] = string(r)
+	}
+	sort.Strings(reasons)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s|%s|%d|%.6f|%.6f|%s",
+		strings.ToLower(alert.TraderAddress),
+		alert.ConditionID,
+		alert.Outcome,
+		strings.ToUpper(alert.Side),
+		alert.Timestamp.UnixNano(),
+		alert.Shares,
+		alert.Price,
+		strings.Join(reasons, ","),
+	)
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
@@ -0,0 +1,318 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"polybot/clients/notifier"
+	"polybot/config"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func testAlert() notifier.TradeAlert {
+	return notifier.TradeAlert{
+		TraderAddress: "0xABC",
+		TraderName:    "whale",
+		MarketTitle:   "Will it rain?",
+		ConditionID:   "0xcond",
+		Outcome:       "Yes",
+		Side:          "BUY",
+		Shares:        1000,
+		Price:         0.42,
+		Notional:      420,
+		Reasons:       []notifier.AlertReason{notifier.AlertReasonMassiveTrade},
+		Timestamp:     time.Unix(1700000000, 0),
+	}
+}
+
+func newTestClient(t *testing.T, url string, secret string, reasons ...string) *WebhookClient {
+	t.Helper()
+	client, err := NewWebhookClient(zap.NewNop(), config.WebhookEndpointConfig{
+		Name:    "test",
+		URL:     url,
+		Secret:  secret,
+		Reasons: reasons,
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return client
+}
+
+func TestNewWebhookClient_InvalidURL(t *testing.T) {
+	for _, u := range []string{"", "not a url", "ftp://example.com/hook"} {
+		if _, err := NewWebhookClient(zap.NewNop(), config.WebhookEndpointConfig{URL: u}, time.Second); err == nil {
+			t.Errorf("expected error for URL %q", u)
+		}
+	}
+}
+
+func TestNewWebhookClient_DefaultsNameToHost(t *testing.T) {
+	client, err := NewWebhookClient(zap.NewNop(), config.WebhookEndpointConfig{URL: "https://hooks.example.com/polybot"}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.Name() != "webhook:hooks.example.com" {
+		t.Errorf("unexpected name %q", client.Name())
+	}
+}
+
+func TestDeliverTradeAlert_SignedRequest(t *testing.T) {
+	var gotHeaders http.Header
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	now := time.Unix(1700000100, 0)
+	client := newTestClient(t, server.URL, "s3cret")
+	client.now = func() time.Time { return now }
+
+	alert := testAlert()
+	if err := client.DeliverTradeAlert(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotHeaders.Get(HeaderTimestamp) != "1700000100" {
+		t.Errorf("unexpected timestamp header %q", gotHeaders.Get(HeaderTimestamp))
+	}
+	if gotHeaders.Get(HeaderEvent) != EventTradeAlert {
+		t.Errorf("unexpected event header %q", gotHeaders.Get(HeaderEvent))
+	}
+	if gotHeaders.Get(HeaderIdempotency) != IdempotencyKey(alert) {
+		t.Errorf("unexpected idempotency key %q", gotHeaders.Get(HeaderIdempotency))
+	}
+	if err := Verify("s3cret", gotHeaders.Get(HeaderTimestamp), gotBody, gotHeaders.Get(HeaderSignature), 5*time.Minute, now); err != nil {
+		t.Errorf("signature did not verify: %v", err)
+	}
+	if err := Verify("wrong", gotHeaders.Get(HeaderTimestamp), gotBody, gotHeaders.Get(HeaderSignature), 5*time.Minute, now); err == nil {
+		t.Error("expected verification with wrong secret to fail")
+	}
+	if err := Verify("s3cret", gotHeaders.Get(HeaderTimestamp), gotBody, gotHeaders.Get(HeaderSignature), 5*time.Minute, now.Add(time.Hour)); err == nil {
+		t.Error("expected stale timestamp to fail")
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.SchemaVersion != SchemaVersion || payload.ID != IdempotencyKey(alert) {
+		t.Errorf("unexpected envelope: %+v", payload)
+	}
+	if payload.Alert.Trade.Notional != 420 || payload.Alert.Market.ConditionID != "0xcond" || payload.Alert.Reasons[0] != "massive_trade" {
+		t.Errorf("unexpected alert data: %+v", payload.Alert)
+	}
+	if payload.Alert.Hedge != nil {
+		t.Error("expected optional sections to be omitted")
+	}
+}
+
+func TestDeliverTradeAlert_NoSecretIsUnsigned(t *testing.T) {
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(HeaderSignature)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "")
+	if err := client.DeliverTradeAlert(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if signature != "" {
+		t.Errorf("expected no signature, got %q", signature)
+	}
+}
+
+func TestDeliverTradeAlert_IdempotencyKeyStableAcrossRetries(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(HeaderIdempotency))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "s3cret")
+	alert := testAlert()
+
+	if err := client.DeliverTradeAlert(context.Background(), alert); err == nil {
+		t.Fatal("expected error on 502")
+	}
+	if err := client.DeliverTradeAlert(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != keys[1] || keys[0] == "" {
+		t.Errorf("expected identical idempotency keys, got %v", keys)
+	}
+}
+
+func TestDeliverTradeAlert_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		header    string
+		permanent bool
+		retryIn   time.Duration
+	}{
+		{"rate limited", http.StatusTooManyRequests, "12", false, 12 * time.Second},
+		{"bad request", http.StatusBadRequest, "", true, 0},
+		{"gone", http.StatusGone, "", true, 0},
+		{"request timeout", http.StatusRequestTimeout, "", false, 0},
+		{"server error", http.StatusInternalServerError, "", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("Retry-After", tt.header)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := newTestClient(t, server.URL, "").DeliverTradeAlert(context.Background(), testAlert())
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			var permanent *notifier.PermanentError
+			if errors.As(err, &permanent) != tt.permanent {
+				t.Errorf("expected permanent=%v, got %v", tt.permanent, err)
+			}
+			var retryAfter *notifier.RetryAfterError
+			if tt.retryIn > 0 && (!errors.As(err, &retryAfter) || retryAfter.RetryAfter != tt.retryIn) {
+				t.Errorf("expected retry after %v, got %v", tt.retryIn, err)
+			}
+		})
+	}
+}
+
+func TestMatches_ReasonFilter(t *testing.T) {
+	alert := testAlert()
+
+	if !newTestClient(t, "https://example.com", "").Matches(alert) {
+		t.Error("expected endpoint without filter to match everything")
+	}
+	if !newTestClient(t, "https://example.com", "", "new_wallet", "massive_trade").Matches(alert) {
+		t.Error("expected endpoint to match listed reason")
+	}
+	if newTestClient(t, "https://example.com", "", "new_wallet").Matches(alert) {
+		t.Error("expected endpoint not to match unlisted reason")
+	}
+}
+
+func TestIdempotencyKey_ReasonOrderIndependent(t *testing.T) {
+	a := testAlert()
+	a.Reasons = []notifier.AlertReason{notifier.AlertReasonNewWallet, notifier.AlertReasonMassiveTrade}
+	b := testAlert()
+	b.Reasons = []notifier.AlertReason{notifier.AlertReasonMassiveTrade, notifier.AlertReasonNewWallet}
+
+	if IdempotencyKey(a) != IdempotencyKey(b) {
+		t.Error("expected key to ignore reason order")
+	}
+
+	b.Shares = 1001
+	if IdempotencyKey(a) == IdempotencyKey(b) {
+		t.Error("expected different trades to have different keys")
+	}
+}
+
+func TestBuildPayload_Repeat(t *testing.T) {
+	if BuildPayload(testAlert()).Alert.Repeat != nil {
+		t.Error("expected no repeat block on a first alert")
+	}
+
+	alert := testAlert()
+	alert.IsUpdate = true
+	alert.MergedFills = 2
+	alert.TotalFills = 3
+	alert.TotalShares = 3000
+	alert.TotalNotional = 1260
+
+	repeat := BuildPayload(alert).Alert.Repeat
+	if repeat == nil || !repeat.IsUpdate || repeat.MergedFills != 2 || repeat.TotalFills != 3 || repeat.TotalNotional != 1260 {
+		t.Errorf("unexpected repeat block: %+v", repeat)
+	}
+}
+
+func TestBuildPayload_Fills(t *testing.T) {
+	alert := testAlert()
+	alert.Fills = []notifier.Fill{{Maker: "0xm1", Shares: 400, Price: 0.40}, {Maker: "0xm2", Shares: 600, Price: 0.433}}
+
+	fills := BuildPayload(alert).Alert.Trade.Fills
+	if len(fills) != 2 || fills[1].Maker != "0xm2" || fills[1].Shares != 600 {
+		t.Errorf("unexpected fills: %+v", fills)
+	}
+
+	alert.Fills = alert.Fills[:1]
+	if BuildPayload(alert).Alert.Trade.Fills != nil {
+		t.Error("expected no fills for a single-fill trade")
+	}
+}
+
+func TestBuildPayload_Impact(t *testing.T) {
+	if BuildPayload(testAlert()).Alert.Impact != nil {
+		t.Error("expected no impact block without impact info")
+	}
+
+	alert := testAlert()
+	alert.HasImpactInfo = true
+	alert.ImpactScore = 1.8
+	alert.ImpactVolumePct = 0.18
+
+	impact := BuildPayload(alert).Alert.Impact
+	if impact == nil || impact.Score != 1.8 || impact.VolumePct != 0.18 || impact.DepthPct != 0 {
+		t.Errorf("unexpected impact block: %+v", impact)
+	}
+}
+
+func TestBuildPayload_Cluster(t *testing.T) {
+	if BuildPayload(testAlert()).Alert.Cluster != nil {
+		t.Error("expected no cluster block without cluster info")
+	}
+
+	alert := testAlert()
+	alert.HasClusterInfo = true
+	alert.ClusterWallets = []notifier.ClusterWallet{
+		{Address: "0xaaa", UniqueMarkets: 1, Trades: 2, Shares: 10000, Notional: 4200},
+		{Address: "0xbbb", Name: "bob", UniqueMarkets: 0, Trades: 1, Shares: 8000, Notional: 3360},
+	}
+	alert.ClusterShares = 18000
+	alert.ClusterNotional = 7560
+	alert.ClusterSpanSecs = 600
+
+	cluster := BuildPayload(alert).Alert.Cluster
+	if cluster == nil || len(cluster.Wallets) != 2 || cluster.Notional != 7560 || cluster.SpanSecs != 600 {
+		t.Fatalf("unexpected cluster block: %+v", cluster)
+	}
+	if w := cluster.Wallets[1]; w.Address != "0xbbb" || w.Name != "bob" || w.Trades != 1 || w.Shares != 8000 {
+		t.Errorf("unexpected cluster wallet: %+v", w)
+	}
+}
+
+func TestBuildPayload_WalletCluster(t *testing.T) {
+	if BuildPayload(testAlert()).Alert.WalletCluster != nil {
+		t.Error("expected no wallet cluster block without a cluster")
+	}
+
+	alert := testAlert()
+	alert.WalletClusterID = "C-1a2b3c4d"
+	alert.WalletClusterSize = 3
+	alert.WalletClusterSignals = []string{"co-trading"}
+
+	cluster := BuildPayload(alert).Alert.WalletCluster
+	if cluster == nil || cluster.ID != "C-1a2b3c4d" || cluster.Size != 3 || len(cluster.Signals) != 1 {
+		t.Errorf("unexpected wallet cluster block: %+v", cluster)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +20,12 @@ type Config struct {
 	// Telegram
 	Telegram TelegramConfig `json:"telegram"`
 
+	// Slack
+	Slack SlackConfig `json:"slack"`
+
+	// Generic webhooks
+	Webhook WebhookConfig `json:"webhook"`
+
 	// Trade monitoring
 	TradeMonitor TradeMonitorConfig `json:"trade_monitor"`
 
@@ -37,17 +44,59 @@ type Config struct {
 	// Hedge pattern tracking
 	HedgeTracker HedgeTrackerConfig `json:"hedge_tracker"`
 
+	// Copy trading graph persistence
+	CopyTracker CopyTrackerConfig `json:"copy_tracker"`
+
 	// Advanced pattern tracking
 	PatternTracker PatternTrackerConfig `json:"pattern_tracker"`
 
+	// Alert outcome tracking
+	AlertOutcomes AlertOutcomesConfig `json:"alert_outcomes"`
+
+	// Deferred alert delivery (alerts raised by background trackers)
+	DeferredAlerts DeferredAlertsConfig `json:"deferred_alerts"`
+
+	// Notification delivery (queueing, retries, dead letters)
+	NotifierQueue NotifierQueueConfig `json:"notifier_queue"`
+
+	// Alert routing rules (which alerts go to which channels)
+	Routing RoutingConfig `json:"routing"`
+
+	// Digest mode (batch default-channel alerts into periodic summaries)
+	Digest DigestConfig `json:"digest"`
+
+	// Repeat alerts merged per wallet, market and side within a cooldown
+	Suppression SuppressionConfig `json:"suppression"`
+
+	// Alert mutes set from chat commands
+	Mutes MutesConfig `json:"mutes"`
+
+	// Alert feedback from Discord buttons
+	Feedback FeedbackConfig `json:"feedback"`
+
+	// Follow-up alerts posted as replies to the first alert per wallet and market
+	Threads ThreadsConfig `json:"threads"`
+
+	// Market-level volume spikes and sharp price moves
+	MarketAnomaly MarketAnomalyConfig `json:"market_anomaly"`
+
+	// Links wallets that appear to be the same actor
+	WalletCluster WalletClusterConfig `json:"wallet_cluster"`
+
 	// GitHub Gist - excluded from settings (env var only)
 	Gist GistConfig `json:"-"`
 
+	// Persistence backend - excluded from settings (env var only)
+	Storage StorageConfig `json:"-"`
+
 	// Polymarket API
 	Polymarket PolymarketConfig `json:"polymarket"`
 
 	// Health server
 	HealthServer HealthServerConfig `json:"health_server"`
+
+	// WebSocket tape record/replay - excluded from settings (env var only)
+	Tape TapeConfig `json:"-"`
 }
 
 // DiscordConfig holds Discord-related configuration.
@@ -55,6 +104,11 @@ type DiscordConfig struct {
 	BotToken      string `json:"-"` // Excluded - env var only
 	ProdChannelID string `json:"prod_channel_id"`
 	BetaChannelID string `json:"beta_channel_id"`
+
+	// Slash commands and alert buttons (gateway connection)
+	CommandsEnabled bool     `json:"-"` // Excluded - env var only
+	GuildID         string   `json:"-"` // Excluded - env var only - empty registers the command globally
+	AllowedUserIDs  []string `json:"-"` // Excluded - env var only - empty allows anyone who can see the command
 }
 
 // TelegramConfig holds Telegram-related configuration.
@@ -62,6 +116,34 @@ type TelegramConfig struct {
 	BotToken   string `json:"-"` // Excluded - env var only
 	ProdChatID string `json:"prod_chat_id"`
 	BetaChatID string `json:"beta_chat_id"`
+
+	// Bot commands (long-polled getUpdates in the alert chat)
+	CommandsEnabled bool     `json:"-"` // Excluded - env var only
+	AllowedUserIDs  []string `json:"-"` // Excluded - env var only - empty allows anyone in the chat
+}
+
+// SlackConfig holds Slack-related configuration.
+// A bot token (chat.postMessage) takes precedence over incoming webhooks.
+type SlackConfig struct {
+	BotToken       string `json:"-"` // Excluded - env var only
+	ProdWebhookURL string `json:"-"` // Excluded - env var only (incoming webhook, posts to its own channel)
+	BetaWebhookURL string `json:"-"` // Excluded - env var only
+	ProdChannelID  string `json:"prod_channel_id"`
+	BetaChannelID  string `json:"beta_channel_id"`
+}
+
+// WebhookConfig holds generic webhook notifier configuration.
+type WebhookConfig struct {
+	Endpoints []WebhookEndpointConfig `json:"-"` // Excluded - env var only (URLs and secrets)
+	Timeout   time.Duration           `json:"timeout"`
+}
+
+// WebhookEndpointConfig configures a single webhook endpoint.
+type WebhookEndpointConfig struct {
+	Name    string   `json:"name"` // Label for logs and stats (defaults to the URL host)
+	URL     string   `json:"url"`
+	Secret  string   `json:"secret"`  // HMAC-SHA256 signing secret
+	Reasons []string `json:"reasons"` // Only send alerts with one of these reasons. Empty = all
 }
 
 // TradeMonitorConfig holds trade monitoring configuration.
@@ -97,9 +179,24 @@ type TradeMonitorConfig struct {
 	MassiveTradeMinNotional float64 `json:"massive_trade_min_notional"` // Minimum notional for massive trade alerts (e.g., 50000)
 	MassiveTradeMaxPrice    float64 `json:"massive_trade_max_price"`    // Max entry price to alert on (e.g., 0.70 = ignore obvious 70¢+ bets)
 
+	// Market impact detection (size relative to the market; 0 disables a measure)
+	ImpactMinNotional  float64 `json:"impact_min_notional"`   // Minimum notional for market impact alerts (e.g., 2000)
+	ImpactVolumePct    float64 `json:"impact_volume_pct"`     // Notional as a share of the market's 24h volume (e.g., 0.10 = 10%)
+	ImpactDepthPct     float64 `json:"impact_depth_pct"`      // Notional as a share of the book depth on the side taken (e.g., 0.50 = 50%)
+	ImpactPriceMovePct float64 `json:"impact_price_move_pct"` // Relative price move across the trade (e.g., 0.10 = 10%)
+
+	// Coordinated entry detection (fresh wallets buying the same outcome together; 0 wallets disables)
+	ClusterMinWallets  int           `json:"cluster_min_wallets"`  // Distinct fresh wallets needed (e.g., 4)
+	ClusterMaxMarkets  int           `json:"cluster_max_markets"`  // Max prior markets for a wallet to count as fresh (e.g., 3)
+	ClusterWindow      time.Duration `json:"cluster_window"`       // How close together the buys must be (e.g., 30m)
+	ClusterMinNotional float64       `json:"cluster_min_notional"` // Minimum combined notional (e.g., 20000)
+
 	// Global obvious price filter
 	ObviousPrice float64 `json:"obvious_price"` // Skip ALL alerts for trades at or above this price (e.g., 0.85 = skip 85¢+ trades)
 
+	// Order aggregation (WebSocket only)
+	FillAggregationWindow time.Duration `json:"fill_aggregation_window"` // How long to collect fills of one order before evaluating it (0 = evaluate each fill)
+
 	// Copy trading detection
 	CopyTradeWindow       time.Duration `json:"copy_trade_window"`         // Time window after leader trade to detect copies (e.g., 10 min)
 	CopyTradeMinCount     int           `json:"copy_trade_min_count"`      // Minimum copy trades to trigger alert (e.g., 3)
@@ -151,6 +248,180 @@ type HedgeTrackerConfig struct {
 	ResolutionCheckInterval time.Duration `json:"resolution_check_interval"`  // How often to check pending events for resolution
 }
 
+// CopyTrackerConfig holds persistence for the copy trading graph (which
+// followers copy which leaders). Detection thresholds live in TradeMonitorConfig.
+type CopyTrackerConfig struct {
+	GistID       string        `json:"-"` // Excluded - env var only
+	FileName     string        `json:"file_name"`
+	SaveInterval time.Duration `json:"save_interval"`
+	MaxPairs     int           `json:"max_pairs"` // Follower/leader pairs kept (least recently matched are dropped first)
+}
+
+// AlertOutcomesConfig holds alert outcome tracking configuration.
+type AlertOutcomesConfig struct {
+	GistID                  string        `json:"-"` // Excluded - env var only
+	FileName                string        `json:"file_name"`
+	SaveInterval            time.Duration `json:"save_interval"`
+	ResolutionCheckInterval time.Duration `json:"resolution_check_interval"` // How often to check alerted markets for resolution
+	MaxAlerts               int           `json:"max_alerts"`                // Max alerts to keep for scoring (oldest dropped first)
+}
+
+// DeferredAlertsConfig holds configuration for delivering alerts raised by background trackers.
+type DeferredAlertsConfig struct {
+	GistID        string        `json:"-"` // Excluded - env var only
+	FileName      string        `json:"file_name"`
+	CheckInterval time.Duration `json:"check_interval"` // How often to collect alerts from trackers
+	SentRetention time.Duration `json:"sent_retention"` // How long to remember sent alerts to avoid resends
+}
+
+// NotifierQueueConfig holds per-channel notification delivery configuration.
+type NotifierQueueConfig struct {
+	BufferSize     int           `json:"buffer_size"`     // Alerts buffered per channel (applied at startup)
+	MaxAttempts    int           `json:"max_attempts"`    // Delivery attempts before dead-lettering
+	InitialBackoff time.Duration `json:"initial_backoff"` // First retry delay, doubled per attempt
+	MaxBackoff     time.Duration `json:"max_backoff"`     // Retry delay cap
+	MinInterval    time.Duration `json:"min_interval"`    // Minimum spacing between sends per channel
+
+	// Dead-letter queue persistence
+	DeadLetterGistID       string        `json:"-"` // Excluded - env var only
+	DeadLetterFileName     string        `json:"dead_letter_file_name"`
+	DeadLetterSaveInterval time.Duration `json:"dead_letter_save_interval"`
+	DeadLetterMaxEntries   int           `json:"dead_letter_max_entries"`
+}
+
+// DigestConfig holds configuration for batching alerts into periodic summaries.
+type DigestConfig struct {
+	Enabled        bool          `json:"enabled"`
+	Window         time.Duration `json:"window"`          // How long alerts are batched before a digest is sent
+	TopN           int           `json:"top_n"`           // Wallets, markets and trades listed per digest
+	InstantReasons []string      `json:"instant_reasons"` // Reasons still delivered immediately
+}
+
+// SuppressionConfig holds alert cooldown settings. Alerts repeating a wallet,
+// market and side within the cooldown are merged into a periodic update with
+// running totals instead of being sent one by one.
+type SuppressionConfig struct {
+	Enabled         bool             `json:"enabled"`
+	Cooldown        time.Duration    `json:"cooldown"`         // Default window per wallet, market and side
+	ReasonCooldowns []ReasonCooldown `json:"reason_cooldowns"` // Per-reason overrides; the shortest applies
+}
+
+// ReasonCooldown overrides the suppression cooldown for alerts with a reason.
+type ReasonCooldown struct {
+	Reason   string        `json:"reason"`
+	Cooldown time.Duration `json:"cooldown"` // 0 never suppresses alerts with the reason
+}
+
+// DefaultSuppressionReasonCooldowns returns the one-off alert reasons that are
+// never suppressed by default.
+func DefaultSuppressionReasonCooldowns() []ReasonCooldown {
+	return []ReasonCooldown{
+		{Reason: "hedge_removal"},
+		{Reason: "resolution_confirmed"},
+		{Reason: "contrarian_winner"},
+	}
+}
+
+// MutesConfig holds persistence for wallet and market mutes.
+type MutesConfig struct {
+	GistID       string        `json:"-"` // Excluded - env var only
+	FileName     string        `json:"file_name"`
+	SaveInterval time.Duration `json:"save_interval"`
+}
+
+// FeedbackConfig holds persistence for alert feedback (false positives and
+// mutes from alert buttons).
+type FeedbackConfig struct {
+	GistID       string        `json:"-"` // Excluded - env var only
+	FileName     string        `json:"file_name"`
+	SaveInterval time.Duration `json:"save_interval"`
+	MaxAlerts    int           `json:"max_alerts"` // Recent alerts kept so buttons can find them
+}
+
+// ThreadsConfig holds settings for threading follow-up alerts. Alerts about a
+// wallet and market already alerted on reply to the first message, and that
+// message is annotated with the market outcome once it resolves.
+type ThreadsConfig struct {
+	Enabled          bool          `json:"enabled"`
+	AnnotateOutcomes bool          `json:"annotate_outcomes"` // Add the market outcome to the first message on resolution
+	Retention        time.Duration `json:"retention"`         // Threads idle longer than this start over
+	MaxThreads       int           `json:"max_threads"`       // Threads kept (least recently used are dropped first)
+	GistID           string        `json:"-"`                 // Excluded - env var only
+	FileName         string        `json:"file_name"`
+	SaveInterval     time.Duration `json:"save_interval"`
+}
+
+// MarketAnomalyConfig holds settings for market-level anomaly alerts. Each
+// market's trade stream is bucketed into windows; a window whose notional is
+// far above the market's baseline, or a sharp price move within a short time,
+// raises an alert listing the wallets behind it.
+type MarketAnomalyConfig struct {
+	Enabled           bool          `json:"enabled"`
+	Window            time.Duration `json:"window"`              // Volume window
+	BaselineWindows   int           `json:"baseline_windows"`    // Prior windows the baseline is measured over
+	VolumeStdDevs     float64       `json:"volume_stddevs"`      // Standard deviations above the baseline mean to alert
+	VolumeMinNotional float64       `json:"volume_min_notional"` // Minimum window notional for a volume spike
+	PriceMove         float64       `json:"price_move"`          // Minimum price change (0.10 = 10¢)
+	PriceWindow       time.Duration `json:"price_window"`        // How far back a price move is measured
+	PriceMinNotional  float64       `json:"price_min_notional"`  // Minimum notional traded during the move
+	Cooldown          time.Duration `json:"cooldown"`            // Minimum time between alerts of one kind per market
+	TopWallets        int           `json:"top_wallets"`         // Contributing wallets listed per alert
+}
+
+// WalletClusterConfig holds settings for linking wallets that appear to be
+// the same actor. Two wallets are linked when they repeatedly trade the same
+// markets within seconds of each other, repeatedly trade identical sizes, or
+// one keeps copying the other; linked wallets form a cluster.
+type WalletClusterConfig struct {
+	Enabled           bool          `json:"enabled"`
+	MinNotional       float64       `json:"min_notional"`         // Trades below this are not compared
+	CoTradeWindow     time.Duration `json:"co_trade_window"`      // How close together two trades count as co-trading
+	CoTradeMinMarkets int           `json:"co_trade_min_markets"` // Markets co-traded before two wallets are linked
+	SizeWindow        time.Duration `json:"size_window"`          // How far apart identical sizes are compared
+	SizeTolerance     float64       `json:"size_tolerance"`       // Relative difference counted as identical (0.01 = 1%)
+	SizeMinMarkets    int           `json:"size_min_markets"`     // Markets with identical sizes before two wallets are linked
+	CopyMinCount      int           `json:"copy_min_count"`       // Copies of one leader before a follower is linked (0 = off)
+	EvidenceMaxAge    time.Duration `json:"evidence_max_age"`     // How long evidence and funders are kept after last seen
+}
+
+// RoutingConfig holds alert routing rules. Alerts matching no rule go to the
+// default channels.
+type RoutingConfig struct {
+	Destinations []RouteDestination `json:"destinations"`
+	Rules        []RoutingRule      `json:"rules"`
+}
+
+// Channels a route destination can deliver to.
+const (
+	RouteChannelDiscord  = "discord"
+	RouteChannelTelegram = "telegram"
+	RouteChannelSlack    = "slack"
+)
+
+// RouteDestination is a named channel that rules can send alerts to.
+type RouteDestination struct {
+	Name    string `json:"name"`    // Referenced by rules, e.g. "whales"
+	Channel string `json:"channel"` // discord, telegram or slack
+	Target  string `json:"target"`  // Discord/Slack channel ID or Telegram chat ID
+}
+
+// RoutingRule sends matching alerts to destinations. Empty criteria match everything.
+type RoutingRule struct {
+	Name           string   `json:"name"`
+	Disabled       bool     `json:"disabled"`
+	Reasons        []string `json:"reasons"`         // Alert has any of these reasons
+	AllReasons     bool     `json:"all_reasons"`     // Require all of Reasons instead of any
+	MinNotional    float64  `json:"min_notional"`    // 0 = no lower bound
+	MaxNotional    float64  `json:"max_notional"`    // 0 = no upper bound
+	MinPrice       float64  `json:"min_price"`       // 0 = no lower bound
+	MaxPrice       float64  `json:"max_price"`       // 0 = no upper bound
+	Categories     []string `json:"categories"`      // Market category or tag slug, e.g. "sports"
+	ConditionIDs   []string `json:"condition_ids"`   // Specific markets
+	Wallets        []string `json:"wallets"`         // Specific trader addresses
+	Destinations   []string `json:"destinations"`    // Destination names
+	IncludeDefault bool     `json:"include_default"` // Also send to the default channels
+}
+
 // PatternTrackerConfig holds advanced pattern detection configuration.
 type PatternTrackerConfig struct {
 	GistID       string        `json:"-"` // Excluded - env var only
@@ -198,6 +469,40 @@ type GistConfig struct {
 	TasksGistID string `json:"-"` // Excluded - env var only - for tasks feature
 }
 
+// Storage backends for persistent state.
+const (
+	StorageBackendGist   = "gist"
+	StorageBackendFile   = "file"
+	StorageBackendSQLite = "sqlite"
+	StorageBackendS3     = "s3"
+)
+
+// StorageConfig holds persistence backend configuration.
+type StorageConfig struct {
+	Backend         string   // gist, file, sqlite or s3
+	Dir             string   // Root directory of the file backend
+	SQLitePath      string   // Database file of the sqlite backend
+	S3              S3Config // Bucket of the s3 backend
+	MigrateFromGist bool     // Copy gist files missing from the backend at startup
+}
+
+// IsGist returns true if state is stored in GitHub gists.
+func (c StorageConfig) IsGist() bool {
+	return c.Backend == "" || c.Backend == StorageBackendGist
+}
+
+// S3Config holds S3-compatible object storage configuration.
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.us-east-1.amazonaws.com or http://localhost:9000
+	Region          string
+	Bucket          string
+	Prefix          string // Key prefix for all objects (optional)
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // For temporary credentials (optional)
+	PathStyle       bool   // endpoint/bucket/key instead of bucket.endpoint/key (needed by MinIO)
+}
+
 // PolymarketConfig holds Polymarket API configuration.
 type PolymarketConfig struct {
 	GammaAPIURL string `json:"gamma_api_url"`
@@ -210,6 +515,19 @@ type HealthServerConfig struct {
 	Port    int  `json:"port"`
 }
 
+// TapeConfig holds WebSocket tape recording and replay configuration.
+type TapeConfig struct {
+	RecordDir     string  // Directory to write tapes to (empty = recording disabled)
+	ReplayFile    string  // Tape to replay instead of connecting (empty = live mode)
+	ReplaySpeed   float64 // 1 = real time, >1 = accelerated, 0 = as fast as possible
+	RESTCacheFile string  // REST response cache (recorded while recording, served during replay)
+}
+
+// IsReplay returns true if the bot should replay a tape instead of running live.
+func (c TapeConfig) IsReplay() bool {
+	return c.ReplayFile != ""
+}
+
 // WalletFilterConfig holds wallet filtering configuration.
 type WalletFilterConfig struct {
 	SpecificWallets []string `json:"specific_wallets"` // Wallet addresses to monitor (empty = all)
@@ -234,9 +552,115 @@ func (c *Config) Clone() *Config {
 		clone.WalletFilter.SpecificWallets = make([]string, len(c.WalletFilter.SpecificWallets))
 		copy(clone.WalletFilter.SpecificWallets, c.WalletFilter.SpecificWallets)
 	}
+	if c.Discord.AllowedUserIDs != nil {
+		clone.Discord.AllowedUserIDs = make([]string, len(c.Discord.AllowedUserIDs))
+		copy(clone.Discord.AllowedUserIDs, c.Discord.AllowedUserIDs)
+	}
+	if c.Telegram.AllowedUserIDs != nil {
+		clone.Telegram.AllowedUserIDs = make([]string, len(c.Telegram.AllowedUserIDs))
+		copy(clone.Telegram.AllowedUserIDs, c.Telegram.AllowedUserIDs)
+	}
+	if c.Digest.InstantReasons != nil {
+		clone.Digest.InstantReasons = make([]string, len(c.Digest.InstantReasons))
+		copy(clone.Digest.InstantReasons, c.Digest.InstantReasons)
+	}
+	if c.Suppression.ReasonCooldowns != nil {
+		clone.Suppression.ReasonCooldowns = append([]ReasonCooldown(nil), c.Suppression.ReasonCooldowns...)
+	}
+	if c.Routing.Destinations != nil {
+		clone.Routing.Destinations = append([]RouteDestination(nil), c.Routing.Destinations...)
+	}
+	if c.Routing.Rules != nil {
+		clone.Routing.Rules = make([]RoutingRule, len(c.Routing.Rules))
+		for i, rule := range c.Routing.Rules {
+			clone.Routing.Rules[i] = rule
+			clone.Routing.Rules[i].Reasons = append([]string(nil), rule.Reasons...)
+			clone.Routing.Rules[i].Categories = append([]string(nil), rule.Categories...)
+			clone.Routing.Rules[i].ConditionIDs = append([]string(nil), rule.ConditionIDs...)
+			clone.Routing.Rules[i].Wallets = append([]string(nil), rule.Wallets...)
+			clone.Routing.Rules[i].Destinations = append([]string(nil), rule.Destinations...)
+		}
+	}
+	if c.Webhook.Endpoints != nil {
+		clone.Webhook.Endpoints = make([]WebhookEndpointConfig, len(c.Webhook.Endpoints))
+		for i, e := range c.Webhook.Endpoints {
+			clone.Webhook.Endpoints[i] = e
+			if e.Reasons != nil {
+				clone.Webhook.Endpoints[i].Reasons = append([]string(nil), e.Reasons...)
+			}
+		}
+	}
 	return &clone
 }
 
+// DisablePersistence clears the gist token and IDs and deselects other backends
+// so a run can neither read nor overwrite live state. Used for tape replays.
+func (c *Config) DisablePersistence() {
+	c.Storage.Backend = StorageBackendGist
+	c.Storage.MigrateFromGist = false
+	c.Gist.Token = ""
+	c.Gist.GistID = ""
+	c.Gist.TasksGistID = ""
+	c.ContrarianCache.GistID = ""
+	c.HedgeTracker.GistID = ""
+	c.CopyTracker.GistID = ""
+	c.PatternTracker.GistID = ""
+	c.AlertOutcomes.GistID = ""
+	c.DeferredAlerts.GistID = ""
+	c.NotifierQueue.DeadLetterGistID = ""
+	c.Mutes.GistID = ""
+	c.Feedback.GistID = ""
+	c.Threads.GistID = ""
+}
+
+// persistenceIDs maps each persistent component's default local namespace to
+// its gist ID.
+func (c *Config) persistenceIDs() map[string]*string {
+	return map[string]*string{
+		"cache":            &c.Gist.GistID,
+		"tasks":            &c.Gist.TasksGistID,
+		"contrarian_cache": &c.ContrarianCache.GistID,
+		"hedge_tracker":    &c.HedgeTracker.GistID,
+		"copy_tracker":     &c.CopyTracker.GistID,
+		"pattern_tracker":  &c.PatternTracker.GistID,
+		"alert_outcomes":   &c.AlertOutcomes.GistID,
+		"deferred_alerts":  &c.DeferredAlerts.GistID,
+		"dead_letters":     &c.NotifierQueue.DeadLetterGistID,
+		"mutes":            &c.Mutes.GistID,
+		"feedback":         &c.Feedback.GistID,
+		"threads":          &c.Threads.GistID,
+	}
+}
+
+// GistIDs returns the distinct gist IDs configured for persistence, sorted.
+func (c *Config) GistIDs() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range c.persistenceIDs() {
+		if *id != "" && !seen[*id] {
+			seen[*id] = true
+			ids = append(ids, *id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ApplyStorageNamespaces gives every component without a gist ID a namespace
+// of its own when a backend other than gists is selected, so all state persists
+// without a GitHub token. Components with a gist ID keep it as their
+// namespace, which is where a migration from that gist puts their files.
+func (c *Config) ApplyStorageNamespaces() {
+	if c.Storage.IsGist() {
+		return
+	}
+	for name, id := range c.persistenceIDs() {
+		if *id == "" {
+			*id = name
+		}
+	}
+}
+
 // ToJSON serializes the config to JSON.
 func (c *Config) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(c, "", "  ")
@@ -254,6 +678,12 @@ func ConfigFromJSON(data []byte, base *Config) (*Config, error) {
 	return cfg, nil
 }
 
+// DefaultDigestInstantReasons returns the high-severity alert reasons that
+// bypass digest batching by default.
+func DefaultDigestInstantReasons() []string {
+	return []string{"hedge_removal", "resolution_confirmed", "contrarian_winner"}
+}
+
 // Defaults returns a config with hardcoded default values.
 func Defaults() *Config {
 	return &Config{
@@ -263,6 +693,10 @@ func Defaults() *Config {
 			BetaChannelID: "",
 		},
 		Telegram: TelegramConfig{},
+		Slack:    SlackConfig{},
+		Webhook: WebhookConfig{
+			Timeout: 10 * time.Second,
+		},
 		TradeMonitor: TradeMonitorConfig{
 			PollInterval:          10 * time.Second,
 			MinNotional:           4000.0,
@@ -282,7 +716,16 @@ func Defaults() *Config {
 			ContrarianMinNotional: 5000.0,
 			MassiveTradeMinNotional: 50000.0,
 			MassiveTradeMaxPrice:    0.70,
+			ImpactMinNotional:       2000.0,
+			ImpactVolumePct:         0.10,
+			ImpactDepthPct:          0.50,
+			ImpactPriceMovePct:      0.10,
+			ClusterMinWallets:       4,
+			ClusterMaxMarkets:       3,
+			ClusterWindow:           30 * time.Minute,
+			ClusterMinNotional:      20000.0,
 			ObviousPrice:            0.75,
+			FillAggregationWindow:   2 * time.Second,
 			CopyTradeWindow:         10 * time.Minute,
 			CopyTradeMinCount:       3,
 			CopyTradeLeaderMinWin:   0.70,
@@ -321,6 +764,11 @@ func Defaults() *Config {
 			AsymmetricThreshold:     2.0,
 			ResolutionCheckInterval: 1 * time.Hour,
 		},
+		CopyTracker: CopyTrackerConfig{
+			FileName:     "copy_tracker.json",
+			SaveInterval: 5 * time.Minute,
+			MaxPairs:     5000,
+		},
 		PatternTracker: PatternTrackerConfig{
 			FileName:     "pattern_tracker.json",
 			SaveInterval: 5 * time.Minute,
@@ -348,6 +796,77 @@ func Defaults() *Config {
 			PositionCheckInterval: 5 * time.Minute,
 			MaxPositionChecks:     60,
 		},
+		AlertOutcomes: AlertOutcomesConfig{
+			FileName:                "alert_outcomes.json",
+			SaveInterval:            5 * time.Minute,
+			ResolutionCheckInterval: 1 * time.Hour,
+			MaxAlerts:               5000,
+		},
+		DeferredAlerts: DeferredAlertsConfig{
+			FileName:      "deferred_alerts.json",
+			CheckInterval: 1 * time.Minute,
+			SentRetention: 30 * 24 * time.Hour,
+		},
+		NotifierQueue: NotifierQueueConfig{
+			BufferSize:             100,
+			MaxAttempts:            5,
+			InitialBackoff:         2 * time.Second,
+			MaxBackoff:             2 * time.Minute,
+			MinInterval:            1 * time.Second,
+			DeadLetterFileName:     "notifier_dead_letters.json",
+			DeadLetterSaveInterval: 1 * time.Minute,
+			DeadLetterMaxEntries:   500,
+		},
+		Digest: DigestConfig{
+			Window:         1 * time.Hour,
+			TopN:           5,
+			InstantReasons: DefaultDigestInstantReasons(),
+		},
+		Suppression: SuppressionConfig{
+			Enabled:         true,
+			Cooldown:        10 * time.Minute,
+			ReasonCooldowns: DefaultSuppressionReasonCooldowns(),
+		},
+		Mutes: MutesConfig{
+			FileName:     "alert_mutes.json",
+			SaveInterval: 1 * time.Minute,
+		},
+		Feedback: FeedbackConfig{
+			FileName:     "alert_feedback.json",
+			SaveInterval: 1 * time.Minute,
+			MaxAlerts:    2000,
+		},
+		Threads: ThreadsConfig{
+			Enabled:          true,
+			AnnotateOutcomes: true,
+			Retention:        7 * 24 * time.Hour,
+			MaxThreads:       2000,
+			FileName:         "message_threads.json",
+			SaveInterval:     1 * time.Minute,
+		},
+		MarketAnomaly: MarketAnomalyConfig{
+			Enabled:           true,
+			Window:            5 * time.Minute,
+			BaselineWindows:   12,
+			VolumeStdDevs:     3.0,
+			VolumeMinNotional: 25000,
+			PriceMove:         0.10,
+			PriceWindow:       15 * time.Minute,
+			PriceMinNotional:  5000,
+			Cooldown:          1 * time.Hour,
+			TopWallets:        5,
+		},
+		WalletCluster: WalletClusterConfig{
+			Enabled:           true,
+			MinNotional:       1000,
+			CoTradeWindow:     10 * time.Second,
+			CoTradeMinMarkets: 3,
+			SizeWindow:        1 * time.Hour,
+			SizeTolerance:     0.01,
+			SizeMinMarkets:    3,
+			CopyMinCount:      5,
+			EvidenceMaxAge:    7 * 24 * time.Hour,
+		},
 		Polymarket: PolymarketConfig{
 			GammaAPIURL: "https://gamma-api.polymarket.com",
 			DataAPIURL:  "https://data-api.polymarket.com",
@@ -356,6 +875,18 @@ func Defaults() *Config {
 			Enabled: true,
 			Port:    8080,
 		},
+		Storage: StorageConfig{
+			Backend:    StorageBackendGist,
+			Dir:        "data",
+			SQLitePath: "polybot.db",
+			S3: S3Config{
+				Region:    "us-east-1",
+				PathStyle: true,
+			},
+		},
+		Tape: TapeConfig{
+			ReplaySpeed: 1,
+		},
 	}
 }
 
@@ -368,12 +899,32 @@ func Load() *Config {
 			BotToken:      envString("DISCORD_BOT_TOKEN", ""),
 			ProdChannelID: envString("DISCORD_PROD_CHANNEL_ID", ""),
 			BetaChannelID: envString("DISCORD_BETA_CHANNEL_ID", ""),
+
+			CommandsEnabled: envBoolDefault("DISCORD_COMMANDS_ENABLED", false),
+			GuildID:         envString("DISCORD_GUILD_ID", ""),
+			AllowedUserIDs:  envStringSlice("DISCORD_ALLOWED_USER_IDS"),
 		},
 
 		Telegram: TelegramConfig{
 			BotToken:   envString("TELEGRAM_BOT_KEY", ""),
 			ProdChatID: envString("TELEGRAM_PROD_CHAT_ID", ""),
 			BetaChatID: envString("TELEGRAM_BETA_CHAT_ID", ""),
+
+			CommandsEnabled: envBoolDefault("TELEGRAM_COMMANDS_ENABLED", false),
+			AllowedUserIDs:  envStringSlice("TELEGRAM_ALLOWED_USER_IDS"),
+		},
+
+		Slack: SlackConfig{
+			BotToken:       envString("SLACK_BOT_TOKEN", ""),
+			ProdWebhookURL: envString("SLACK_PROD_WEBHOOK_URL", ""),
+			BetaWebhookURL: envString("SLACK_BETA_WEBHOOK_URL", ""),
+			ProdChannelID:  envString("SLACK_PROD_CHANNEL_ID", ""),
+			BetaChannelID:  envString("SLACK_BETA_CHANNEL_ID", ""),
+		},
+
+		Webhook: WebhookConfig{
+			Endpoints: envWebhookEndpoints(),
+			Timeout:   envDuration("WEBHOOK_TIMEOUT", 10*time.Second),
 		},
 
 		TradeMonitor: TradeMonitorConfig{
@@ -395,7 +946,16 @@ func Load() *Config {
 			ContrarianMinNotional:   envFloat("TRADE_CONTRARIAN_MIN_NOTIONAL", 5000.0),
 			MassiveTradeMinNotional: envFloat("TRADE_MASSIVE_MIN_NOTIONAL", 50000.0),
 			MassiveTradeMaxPrice:    envFloat("TRADE_MASSIVE_MAX_PRICE", 0.70),
+			ImpactMinNotional:       envFloat("TRADE_IMPACT_MIN_NOTIONAL", 2000.0),
+			ImpactVolumePct:         envFloat("TRADE_IMPACT_VOLUME_PCT", 0.10),
+			ImpactDepthPct:          envFloat("TRADE_IMPACT_DEPTH_PCT", 0.50),
+			ImpactPriceMovePct:      envFloat("TRADE_IMPACT_PRICE_MOVE_PCT", 0.10),
+			ClusterMinWallets:       envInt("TRADE_CLUSTER_MIN_WALLETS", 4),
+			ClusterMaxMarkets:       envInt("TRADE_CLUSTER_MAX_MARKETS", 3),
+			ClusterWindow:           envDuration("TRADE_CLUSTER_WINDOW", 30*time.Minute),
+			ClusterMinNotional:      envFloat("TRADE_CLUSTER_MIN_NOTIONAL", 20000.0),
 			ObviousPrice:            envFloat("TRADE_OBVIOUS_PRICE", 0.75),
+			FillAggregationWindow:   envDuration("TRADE_FILL_AGGREGATION_WINDOW", 2*time.Second),
 			CopyTradeWindow:         envDuration("COPY_TRADE_WINDOW", 10*time.Minute),
 			CopyTradeMinCount:       envInt("COPY_TRADE_MIN_COUNT", 3),
 			CopyTradeLeaderMinWin:   envFloat("COPY_TRADE_LEADER_MIN_WIN", 0.70),
@@ -446,6 +1006,13 @@ func Load() *Config {
 			ResolutionCheckInterval: envDuration("HEDGE_RESOLUTION_CHECK_INTERVAL", 1*time.Hour),
 		},
 
+		CopyTracker: CopyTrackerConfig{
+			GistID:       envString("COPY_TRACKER_GIST_ID", ""),
+			FileName:     envString("COPY_TRACKER_FILE_NAME", "copy_tracker.json"),
+			SaveInterval: envDuration("COPY_TRACKER_SAVE_INTERVAL", 5*time.Minute),
+			MaxPairs:     envInt("COPY_TRACKER_MAX_PAIRS", 5000),
+		},
+
 		PatternTracker: PatternTrackerConfig{
 			GistID:       envString("PATTERN_TRACKER_GIST_ID", ""),
 			FileName:     envString("PATTERN_TRACKER_FILE_NAME", "pattern_tracker.json"),
@@ -485,12 +1052,117 @@ func Load() *Config {
 			MaxPositionChecks:     envInt("PATTERN_MAX_POSITION_CHECKS", 60),
 		},
 
+		AlertOutcomes: AlertOutcomesConfig{
+			GistID:                  envString("ALERT_OUTCOMES_GIST_ID", ""),
+			FileName:                envString("ALERT_OUTCOMES_FILE_NAME", "alert_outcomes.json"),
+			SaveInterval:            envDuration("ALERT_OUTCOMES_SAVE_INTERVAL", 5*time.Minute),
+			ResolutionCheckInterval: envDuration("ALERT_OUTCOMES_RESOLUTION_CHECK_INTERVAL", 1*time.Hour),
+			MaxAlerts:               envInt("ALERT_OUTCOMES_MAX_ALERTS", 5000),
+		},
+
+		DeferredAlerts: DeferredAlertsConfig{
+			GistID:        envString("DEFERRED_ALERTS_GIST_ID", ""),
+			FileName:      envString("DEFERRED_ALERTS_FILE_NAME", "deferred_alerts.json"),
+			CheckInterval: envDuration("DEFERRED_ALERTS_CHECK_INTERVAL", 1*time.Minute),
+			SentRetention: envDuration("DEFERRED_ALERTS_SENT_RETENTION", 30*24*time.Hour),
+		},
+
+		NotifierQueue: NotifierQueueConfig{
+			BufferSize:             envInt("NOTIFIER_QUEUE_BUFFER_SIZE", 100),
+			MaxAttempts:            envInt("NOTIFIER_MAX_ATTEMPTS", 5),
+			InitialBackoff:         envDuration("NOTIFIER_INITIAL_BACKOFF", 2*time.Second),
+			MaxBackoff:             envDuration("NOTIFIER_MAX_BACKOFF", 2*time.Minute),
+			MinInterval:            envDuration("NOTIFIER_MIN_INTERVAL", 1*time.Second),
+			DeadLetterGistID:       envString("NOTIFIER_DLQ_GIST_ID", ""),
+			DeadLetterFileName:     envString("NOTIFIER_DLQ_FILE_NAME", "notifier_dead_letters.json"),
+			DeadLetterSaveInterval: envDuration("NOTIFIER_DLQ_SAVE_INTERVAL", 1*time.Minute),
+			DeadLetterMaxEntries:   envInt("NOTIFIER_DLQ_MAX_ENTRIES", 500),
+		},
+
+		Digest: DigestConfig{
+			Enabled:        envBoolDefault("DIGEST_ENABLED", false),
+			Window:         envDuration("DIGEST_WINDOW", 1*time.Hour),
+			TopN:           envInt("DIGEST_TOP_N", 5),
+			InstantReasons: envStringSliceDefault("DIGEST_INSTANT_REASONS", DefaultDigestInstantReasons()),
+		},
+
+		Suppression: SuppressionConfig{
+			Enabled:         envBoolDefault("SUPPRESSION_ENABLED", true),
+			Cooldown:        envDuration("SUPPRESSION_COOLDOWN", 10*time.Minute),
+			ReasonCooldowns: envReasonCooldowns("SUPPRESSION_REASON_COOLDOWNS", DefaultSuppressionReasonCooldowns()),
+		},
+
+		Mutes: MutesConfig{
+			GistID:       envString("MUTES_GIST_ID", ""),
+			FileName:     envString("MUTES_FILE_NAME", "alert_mutes.json"),
+			SaveInterval: envDuration("MUTES_SAVE_INTERVAL", 1*time.Minute),
+		},
+
+		Feedback: FeedbackConfig{
+			GistID:       envString("ALERT_FEEDBACK_GIST_ID", ""),
+			FileName:     envString("ALERT_FEEDBACK_FILE_NAME", "alert_feedback.json"),
+			SaveInterval: envDuration("ALERT_FEEDBACK_SAVE_INTERVAL", 1*time.Minute),
+			MaxAlerts:    envInt("ALERT_FEEDBACK_MAX_ALERTS", 2000),
+		},
+
+		Threads: ThreadsConfig{
+			Enabled:          envBoolDefault("MESSAGE_THREADS_ENABLED", true),
+			AnnotateOutcomes: envBoolDefault("MESSAGE_THREADS_ANNOTATE_OUTCOMES", true),
+			Retention:        envDuration("MESSAGE_THREADS_RETENTION", 7*24*time.Hour),
+			MaxThreads:       envInt("MESSAGE_THREADS_MAX", 2000),
+			GistID:           envString("MESSAGE_THREADS_GIST_ID", ""),
+			FileName:         envString("MESSAGE_THREADS_FILE_NAME", "message_threads.json"),
+			SaveInterval:     envDuration("MESSAGE_THREADS_SAVE_INTERVAL", 1*time.Minute),
+		},
+
+		MarketAnomaly: MarketAnomalyConfig{
+			Enabled:           envBoolDefault("MARKET_ANOMALY_ENABLED", true),
+			Window:            envDuration("MARKET_ANOMALY_WINDOW", 5*time.Minute),
+			BaselineWindows:   envInt("MARKET_ANOMALY_BASELINE_WINDOWS", 12),
+			VolumeStdDevs:     envFloat("MARKET_ANOMALY_VOLUME_STDDEVS", 3.0),
+			VolumeMinNotional: envFloat("MARKET_ANOMALY_VOLUME_MIN_NOTIONAL", 25000),
+			PriceMove:         envFloat("MARKET_ANOMALY_PRICE_MOVE", 0.10),
+			PriceWindow:       envDuration("MARKET_ANOMALY_PRICE_WINDOW", 15*time.Minute),
+			PriceMinNotional:  envFloat("MARKET_ANOMALY_PRICE_MIN_NOTIONAL", 5000),
+			Cooldown:          envDuration("MARKET_ANOMALY_COOLDOWN", 1*time.Hour),
+			TopWallets:        envInt("MARKET_ANOMALY_TOP_WALLETS", 5),
+		},
+
+		WalletCluster: WalletClusterConfig{
+			Enabled:           envBoolDefault("WALLET_CLUSTER_ENABLED", true),
+			MinNotional:       envFloat("WALLET_CLUSTER_MIN_NOTIONAL", 1000),
+			CoTradeWindow:     envDuration("WALLET_CLUSTER_CO_TRADE_WINDOW", 10*time.Second),
+			CoTradeMinMarkets: envInt("WALLET_CLUSTER_CO_TRADE_MIN_MARKETS", 3),
+			SizeWindow:        envDuration("WALLET_CLUSTER_SIZE_WINDOW", 1*time.Hour),
+			SizeTolerance:     envFloat("WALLET_CLUSTER_SIZE_TOLERANCE", 0.01),
+			SizeMinMarkets:    envInt("WALLET_CLUSTER_SIZE_MIN_MARKETS", 3),
+			CopyMinCount:      envInt("WALLET_CLUSTER_COPY_MIN_COUNT", 5),
+			EvidenceMaxAge:    envDuration("WALLET_CLUSTER_EVIDENCE_MAX_AGE", 7*24*time.Hour),
+		},
+
 		Gist: GistConfig{
 			Token:       envString("GITHUB_TOKEN", ""),
 			GistID:      envString("CACHE_GIST_ID", ""),
 			TasksGistID: envString("TASKS_GIST_ID", ""),
 		},
 
+		Storage: StorageConfig{
+			Backend:         strings.ToLower(envString("STORAGE_BACKEND", StorageBackendGist)),
+			Dir:             envString("STORAGE_DIR", "data"),
+			SQLitePath:      envString("STORAGE_SQLITE_PATH", "polybot.db"),
+			S3: S3Config{
+				Endpoint:        envString("STORAGE_S3_ENDPOINT", ""),
+				Region:          envString("STORAGE_S3_REGION", "us-east-1"),
+				Bucket:          envString("STORAGE_S3_BUCKET", ""),
+				Prefix:          envString("STORAGE_S3_PREFIX", ""),
+				AccessKeyID:     envString("STORAGE_S3_ACCESS_KEY_ID", os.Getenv("AWS_ACCESS_KEY_ID")),
+				SecretAccessKey: envString("STORAGE_S3_SECRET_ACCESS_KEY", os.Getenv("AWS_SECRET_ACCESS_KEY")),
+				SessionToken:    envString("STORAGE_S3_SESSION_TOKEN", os.Getenv("AWS_SESSION_TOKEN")),
+				PathStyle:       envBoolDefault("STORAGE_S3_PATH_STYLE", true),
+			},
+			MigrateFromGist: envBoolDefault("STORAGE_MIGRATE_FROM_GIST", false),
+		},
+
 		Polymarket: PolymarketConfig{
 			GammaAPIURL: envString("POLYMARKET_GAMMA_API_URL", "https://gamma-api.polymarket.com"),
 			DataAPIURL:  envString("POLYMARKET_DATA_API_URL", "https://data-api.polymarket.com"),
@@ -500,6 +1172,13 @@ func Load() *Config {
 			Enabled: envBoolDefault("HEALTH_SERVER_ENABLED", true),
 			Port:    envInt("HEALTH_SERVER_PORT", 8080),
 		},
+
+		Tape: TapeConfig{
+			RecordDir:     envString("TAPE_RECORD_DIR", ""),
+			ReplayFile:    envString("TAPE_REPLAY_FILE", ""),
+			ReplaySpeed:   envFloat("TAPE_REPLAY_SPEED", 1),
+			RESTCacheFile: envString("TAPE_REST_CACHE_FILE", ""),
+		},
 	}
 }
 
@@ -560,6 +1239,48 @@ func envBoolDefault(key string, defaultVal bool) bool {
 	return strings.EqualFold(v, "true") || strings.EqualFold(v, "1") || strings.EqualFold(v, "yes")
 }
 
+// envWebhookEndpoints reads webhook endpoints from WEBHOOK_ENDPOINTS (a JSON
+// array of endpoints), plus a single endpoint from WEBHOOK_URL, WEBHOOK_SECRET
+// and WEBHOOK_REASONS.
+func envWebhookEndpoints() []WebhookEndpointConfig {
+	var endpoints []WebhookEndpointConfig
+	if v := strings.TrimSpace(os.Getenv("WEBHOOK_ENDPOINTS")); v != "" {
+		if err := json.Unmarshal([]byte(v), &endpoints); err != nil {
+			endpoints = nil
+		}
+	}
+	if url := envString("WEBHOOK_URL", ""); url != "" {
+		endpoints = append(endpoints, WebhookEndpointConfig{
+			URL:     url,
+			Secret:  envString("WEBHOOK_SECRET", ""),
+			Reasons: envStringSlice("WEBHOOK_REASONS"),
+		})
+	}
+	return endpoints
+}
+
+// envReasonCooldowns reads per-reason cooldowns as "reason=duration" pairs,
+// e.g. "hedge_removal=0s,rapid_trading=30m". Invalid pairs are skipped.
+func envReasonCooldowns(key string, defaultVal []ReasonCooldown) []ReasonCooldown {
+	pairs := envStringSlice(key)
+	if pairs == nil {
+		return defaultVal
+	}
+	result := make([]ReasonCooldown, 0, len(pairs))
+	for _, pair := range pairs {
+		reason, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		result = append(result, ReasonCooldown{Reason: strings.TrimSpace(reason), Cooldown: d})
+	}
+	return result
+}
+
 func envStringSlice(key string) []string {
 	val := os.Getenv(key)
 	if val == "" {
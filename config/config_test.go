@@ -1,7 +1,11 @@
 package config
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"os"
+	"sync"
 	"testing"
 	"time"
 )
@@ -468,3 +472,571 @@ func TestLoad_FilteringOptions(t *testing.T) {
 		t.Errorf("expected lowercase wallet, got %s", cfg.WalletFilter.SpecificWallets[0])
 	}
 }
+
+func TestLoad_Tape(t *testing.T) {
+	os.Unsetenv("TAPE_RECORD_DIR")
+	os.Unsetenv("TAPE_REPLAY_FILE")
+	os.Unsetenv("TAPE_REPLAY_SPEED")
+	os.Unsetenv("TAPE_REST_CACHE_FILE")
+
+	cfg := Load()
+	if cfg.Tape.IsReplay() {
+		t.Error("expected live mode by default")
+	}
+	if cfg.Tape.ReplaySpeed != 1 {
+		t.Errorf("expected default replay speed 1, got %v", cfg.Tape.ReplaySpeed)
+	}
+
+	os.Setenv("TAPE_REPLAY_FILE", "/tmp/tape.jsonl.gz")
+	os.Setenv("TAPE_REPLAY_SPEED", "0")
+	os.Setenv("TAPE_REST_CACHE_FILE", "/tmp/rest.json.gz")
+	defer func() {
+		os.Unsetenv("TAPE_REPLAY_FILE")
+		os.Unsetenv("TAPE_REPLAY_SPEED")
+		os.Unsetenv("TAPE_REST_CACHE_FILE")
+	}()
+
+	cfg = Load()
+	if !cfg.Tape.IsReplay() {
+		t.Error("expected replay mode")
+	}
+	if cfg.Tape.ReplaySpeed != 0 {
+		t.Errorf("expected replay speed 0, got %v", cfg.Tape.ReplaySpeed)
+	}
+	if cfg.Tape.RESTCacheFile != "/tmp/rest.json.gz" {
+		t.Errorf("unexpected REST cache file: %s", cfg.Tape.RESTCacheFile)
+	}
+}
+
+func TestLoad_WebhookEndpoints(t *testing.T) {
+	os.Setenv("WEBHOOK_ENDPOINTS", `[{"name":"ops","url":"https://ops.example.com/hook","secret":"a","reasons":["massive_trade"]}]`)
+	os.Setenv("WEBHOOK_URL", "https://example.com/hook")
+	os.Setenv("WEBHOOK_SECRET", "b")
+	os.Setenv("WEBHOOK_REASONS", "new_wallet, extreme_bet")
+	defer func() {
+		os.Unsetenv("WEBHOOK_ENDPOINTS")
+		os.Unsetenv("WEBHOOK_URL")
+		os.Unsetenv("WEBHOOK_SECRET")
+		os.Unsetenv("WEBHOOK_REASONS")
+	}()
+
+	cfg := Load()
+	if len(cfg.Webhook.Endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(cfg.Webhook.Endpoints))
+	}
+	ops := cfg.Webhook.Endpoints[0]
+	if ops.Name != "ops" || ops.Secret != "a" || len(ops.Reasons) != 1 {
+		t.Errorf("unexpected JSON endpoint: %+v", ops)
+	}
+	single := cfg.Webhook.Endpoints[1]
+	if single.URL != "https://example.com/hook" || single.Secret != "b" || len(single.Reasons) != 2 {
+		t.Errorf("unexpected single endpoint: %+v", single)
+	}
+
+	clone := cfg.Clone()
+	clone.Webhook.Endpoints[0].Name = "changed"
+	if cfg.Webhook.Endpoints[0].Name != "ops" {
+		t.Error("expected Clone to deep-copy webhook endpoints")
+	}
+}
+
+func TestLoad_Digest(t *testing.T) {
+	cfg := Load()
+	if cfg.Digest.Enabled || cfg.Digest.Window != time.Hour || len(cfg.Digest.InstantReasons) != 3 {
+		t.Errorf("unexpected digest defaults: %+v", cfg.Digest)
+	}
+
+	os.Setenv("DIGEST_ENABLED", "true")
+	os.Setenv("DIGEST_WINDOW", "24h")
+	os.Setenv("DIGEST_INSTANT_REASONS", "massive_trade")
+	defer func() {
+		os.Unsetenv("DIGEST_ENABLED")
+		os.Unsetenv("DIGEST_WINDOW")
+		os.Unsetenv("DIGEST_INSTANT_REASONS")
+	}()
+
+	cfg = Load()
+	if !cfg.Digest.Enabled || cfg.Digest.Window != 24*time.Hour {
+		t.Errorf("unexpected digest config: %+v", cfg.Digest)
+	}
+	if len(cfg.Digest.InstantReasons) != 1 || cfg.Digest.InstantReasons[0] != "massive_trade" {
+		t.Errorf("unexpected instant reasons: %v", cfg.Digest.InstantReasons)
+	}
+
+	clone := cfg.Clone()
+	clone.Digest.InstantReasons[0] = "changed"
+	if cfg.Digest.InstantReasons[0] != "massive_trade" {
+		t.Error("expected Clone to deep-copy digest instant reasons")
+	}
+}
+
+func TestLoad_TelegramCommands(t *testing.T) {
+	cfg := Load()
+	if cfg.Telegram.CommandsEnabled || cfg.Telegram.AllowedUserIDs != nil {
+		t.Errorf("expected telegram commands disabled by default, got %+v", cfg.Telegram)
+	}
+
+	os.Setenv("TELEGRAM_COMMANDS_ENABLED", "true")
+	os.Setenv("TELEGRAM_ALLOWED_USER_IDS", "42, 77")
+	os.Setenv("MUTES_GIST_ID", "mutes-gist")
+	defer func() {
+		os.Unsetenv("TELEGRAM_COMMANDS_ENABLED")
+		os.Unsetenv("TELEGRAM_ALLOWED_USER_IDS")
+		os.Unsetenv("MUTES_GIST_ID")
+	}()
+
+	cfg = Load()
+	if !cfg.Telegram.CommandsEnabled {
+		t.Error("expected telegram commands enabled")
+	}
+	if len(cfg.Telegram.AllowedUserIDs) != 2 || cfg.Telegram.AllowedUserIDs[1] != "77" {
+		t.Errorf("unexpected allowed user IDs: %v", cfg.Telegram.AllowedUserIDs)
+	}
+	if cfg.Mutes.GistID != "mutes-gist" || cfg.Mutes.FileName != "alert_mutes.json" {
+		t.Errorf("unexpected mutes config: %+v", cfg.Mutes)
+	}
+
+	// Env-only fields survive a settings merge
+	merged := mergeConfigs(cfg, Defaults())
+	if !merged.Telegram.CommandsEnabled || len(merged.Telegram.AllowedUserIDs) != 2 || merged.Mutes.GistID != "mutes-gist" {
+		t.Errorf("expected env-only fields preserved, got %+v %+v", merged.Telegram, merged.Mutes)
+	}
+}
+
+func TestLoad_DiscordCommands(t *testing.T) {
+	cfg := Load()
+	if cfg.Discord.CommandsEnabled || cfg.Discord.AllowedUserIDs != nil {
+		t.Errorf("expected discord commands disabled by default, got %+v", cfg.Discord)
+	}
+	if cfg.Feedback.FileName != "alert_feedback.json" || cfg.Feedback.MaxAlerts != 2000 {
+		t.Errorf("unexpected feedback defaults: %+v", cfg.Feedback)
+	}
+
+	os.Setenv("DISCORD_COMMANDS_ENABLED", "true")
+	os.Setenv("DISCORD_GUILD_ID", "guild-1")
+	os.Setenv("DISCORD_ALLOWED_USER_IDS", "1001,1002")
+	os.Setenv("ALERT_FEEDBACK_GIST_ID", "feedback-gist")
+	defer func() {
+		os.Unsetenv("DISCORD_COMMANDS_ENABLED")
+		os.Unsetenv("DISCORD_GUILD_ID")
+		os.Unsetenv("DISCORD_ALLOWED_USER_IDS")
+		os.Unsetenv("ALERT_FEEDBACK_GIST_ID")
+	}()
+
+	cfg = Load()
+	if !cfg.Discord.CommandsEnabled || cfg.Discord.GuildID != "guild-1" || len(cfg.Discord.AllowedUserIDs) != 2 {
+		t.Errorf("unexpected discord config: %+v", cfg.Discord)
+	}
+	if cfg.Feedback.GistID != "feedback-gist" {
+		t.Errorf("unexpected feedback config: %+v", cfg.Feedback)
+	}
+
+	// Env-only fields survive a settings merge
+	merged := mergeConfigs(cfg, Defaults())
+	if !merged.Discord.CommandsEnabled || merged.Discord.GuildID != "guild-1" ||
+		len(merged.Discord.AllowedUserIDs) != 2 || merged.Feedback.GistID != "feedback-gist" {
+		t.Errorf("expected env-only fields preserved, got %+v %+v", merged.Discord, merged.Feedback)
+	}
+
+	cfg.DisablePersistence()
+	if cfg.Feedback.GistID != "" {
+		t.Error("expected feedback gist cleared")
+	}
+}
+
+func TestDisablePersistence(t *testing.T) {
+	cfg := Defaults()
+	cfg.Gist = GistConfig{Token: "token", GistID: "g1", TasksGistID: "g2"}
+	cfg.ContrarianCache.GistID = "g3"
+	cfg.HedgeTracker.GistID = "g4"
+	cfg.PatternTracker.GistID = "g5"
+
+	cfg.DisablePersistence()
+
+	if cfg.Gist.Token != "" || cfg.Gist.GistID != "" || cfg.Gist.TasksGistID != "" {
+		t.Errorf("expected gist config cleared, got %+v", cfg.Gist)
+	}
+	if cfg.ContrarianCache.GistID != "" || cfg.HedgeTracker.GistID != "" || cfg.PatternTracker.GistID != "" {
+		t.Error("expected tracker gist IDs cleared")
+	}
+}
+
+func TestLoad_Storage(t *testing.T) {
+	cfg := Load()
+	if cfg.Storage.Backend != StorageBackendGist || !cfg.Storage.IsGist() || cfg.Storage.MigrateFromGist {
+		t.Errorf("unexpected storage defaults: %+v", cfg.Storage)
+	}
+
+	os.Setenv("STORAGE_BACKEND", "SQLite")
+	os.Setenv("STORAGE_SQLITE_PATH", "/var/lib/polybot/state.db")
+	os.Setenv("STORAGE_MIGRATE_FROM_GIST", "true")
+	os.Setenv("HEDGE_TRACKER_GIST_ID", "hedge-gist")
+	defer func() {
+		os.Unsetenv("STORAGE_BACKEND")
+		os.Unsetenv("STORAGE_SQLITE_PATH")
+		os.Unsetenv("STORAGE_MIGRATE_FROM_GIST")
+		os.Unsetenv("HEDGE_TRACKER_GIST_ID")
+	}()
+
+	cfg = Load()
+	if cfg.Storage.Backend != StorageBackendSQLite || cfg.Storage.IsGist() ||
+		cfg.Storage.SQLitePath != "/var/lib/polybot/state.db" || !cfg.Storage.MigrateFromGist {
+		t.Errorf("unexpected storage config: %+v", cfg.Storage)
+	}
+	if ids := cfg.GistIDs(); len(ids) != 1 || ids[0] != "hedge-gist" {
+		t.Errorf("expected only the hedge gist, got %v", ids)
+	}
+
+	// Unset gist IDs get local namespaces; set ones are kept
+	cfg.ApplyStorageNamespaces()
+	if cfg.HedgeTracker.GistID != "hedge-gist" || cfg.Gist.GistID != "cache" ||
+		cfg.Gist.TasksGistID != "tasks" || cfg.ContrarianCache.GistID != "contrarian_cache" {
+		t.Errorf("unexpected namespaces: %+v %+v %+v", cfg.Gist, cfg.HedgeTracker, cfg.ContrarianCache)
+	}
+
+	// Replays never persist, whatever the backend
+	cfg.DisablePersistence()
+	cfg.ApplyStorageNamespaces()
+	if !cfg.Storage.IsGist() || cfg.Gist.GistID != "" || cfg.HedgeTracker.GistID != "" {
+		t.Errorf("expected persistence disabled, got %+v %+v", cfg.Storage, cfg.Gist)
+	}
+}
+
+func TestLoad_StorageS3(t *testing.T) {
+	cfg := Load()
+	if cfg.Storage.S3.Region != "us-east-1" || !cfg.Storage.S3.PathStyle {
+		t.Errorf("unexpected s3 defaults: %+v", cfg.Storage.S3)
+	}
+
+	os.Setenv("STORAGE_BACKEND", "s3")
+	os.Setenv("STORAGE_S3_ENDPOINT", "http://minio:9000")
+	os.Setenv("STORAGE_S3_BUCKET", "polybot")
+	os.Setenv("STORAGE_S3_PATH_STYLE", "false")
+	os.Setenv("AWS_ACCESS_KEY_ID", "aws-key")
+	os.Setenv("STORAGE_S3_SECRET_ACCESS_KEY", "secret")
+	defer func() {
+		os.Unsetenv("STORAGE_BACKEND")
+		os.Unsetenv("STORAGE_S3_ENDPOINT")
+		os.Unsetenv("STORAGE_S3_BUCKET")
+		os.Unsetenv("STORAGE_S3_PATH_STYLE")
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+		os.Unsetenv("STORAGE_S3_SECRET_ACCESS_KEY")
+	}()
+
+	cfg = Load()
+	s3 := cfg.Storage.S3
+	if cfg.Storage.IsGist() || s3.Endpoint != "http://minio:9000" || s3.Bucket != "polybot" || s3.PathStyle {
+		t.Errorf("unexpected s3 config: %+v", s3)
+	}
+	if s3.AccessKeyID != "aws-key" || s3.SecretAccessKey != "secret" {
+		t.Error("expected credentials from env")
+	}
+}
+
+func TestApplyStorageNamespaces_Gist(t *testing.T) {
+	cfg := Defaults()
+	cfg.ApplyStorageNamespaces()
+	if len(cfg.GistIDs()) != 0 {
+		t.Errorf("expected gist backend to keep IDs unset, got %v", cfg.GistIDs())
+	}
+}
+
+func TestLoad_Threads(t *testing.T) {
+	cfg := Load()
+	if !cfg.Threads.Enabled || !cfg.Threads.AnnotateOutcomes || cfg.Threads.Retention != 7*24*time.Hour {
+		t.Errorf("unexpected thread defaults: %+v", cfg.Threads)
+	}
+
+	os.Setenv("MESSAGE_THREADS_ENABLED", "false")
+	os.Setenv("MESSAGE_THREADS_GIST_ID", "threads-gist")
+	defer func() {
+		os.Unsetenv("MESSAGE_THREADS_ENABLED")
+		os.Unsetenv("MESSAGE_THREADS_GIST_ID")
+	}()
+
+	cfg = Load()
+	if cfg.Threads.Enabled || cfg.Threads.GistID != "threads-gist" {
+		t.Errorf("unexpected thread config: %+v", cfg.Threads)
+	}
+
+	// The gist ID survives a settings merge
+	if merged := mergeConfigs(cfg, Defaults()); merged.Threads.GistID != "threads-gist" {
+		t.Errorf("expected thread gist preserved, got %+v", merged.Threads)
+	}
+
+	cfg.DisablePersistence()
+	if cfg.Threads.GistID != "" {
+		t.Error("expected thread gist cleared")
+	}
+}
+
+func TestLoad_Suppression(t *testing.T) {
+	cfg := Load()
+	if !cfg.Suppression.Enabled || cfg.Suppression.Cooldown != 10*time.Minute || len(cfg.Suppression.ReasonCooldowns) != 3 {
+		t.Errorf("unexpected suppression defaults: %+v", cfg.Suppression)
+	}
+
+	os.Setenv("SUPPRESSION_COOLDOWN", "30m")
+	os.Setenv("SUPPRESSION_REASON_COOLDOWNS", "rapid_trading=1h, hedge_removal=0, bogus, bad=xyz")
+	defer func() {
+		os.Unsetenv("SUPPRESSION_COOLDOWN")
+		os.Unsetenv("SUPPRESSION_REASON_COOLDOWNS")
+	}()
+
+	cfg = Load()
+	if cfg.Suppression.Cooldown != 30*time.Minute {
+		t.Errorf("expected 30m cooldown, got %v", cfg.Suppression.Cooldown)
+	}
+	want := []ReasonCooldown{{Reason: "rapid_trading", Cooldown: time.Hour}, {Reason: "hedge_removal", Cooldown: 0}}
+	if len(cfg.Suppression.ReasonCooldowns) != len(want) {
+		t.Fatalf("expected %d reason cooldowns, got %+v", len(want), cfg.Suppression.ReasonCooldowns)
+	}
+	for i, rc := range want {
+		if cfg.Suppression.ReasonCooldowns[i] != rc {
+			t.Errorf("reason cooldown %d: expected %+v, got %+v", i, rc, cfg.Suppression.ReasonCooldowns[i])
+		}
+	}
+
+	// Clones don't share the override list
+	clone := cfg.Clone()
+	clone.Suppression.ReasonCooldowns[0].Cooldown = 0
+	if cfg.Suppression.ReasonCooldowns[0].Cooldown != time.Hour {
+		t.Error("expected clone to copy reason cooldowns")
+	}
+}
+
+func TestLoad_MarketImpact(t *testing.T) {
+	cfg := Load()
+	if cfg.TradeMonitor.ImpactMinNotional != 2000 || cfg.TradeMonitor.ImpactVolumePct != 0.10 ||
+		cfg.TradeMonitor.ImpactDepthPct != 0.50 || cfg.TradeMonitor.ImpactPriceMovePct != 0.10 {
+		t.Errorf("unexpected market impact defaults: %+v", cfg.TradeMonitor)
+	}
+
+	os.Setenv("TRADE_IMPACT_VOLUME_PCT", "0.25")
+	os.Setenv("TRADE_IMPACT_DEPTH_PCT", "0")
+	defer func() {
+		os.Unsetenv("TRADE_IMPACT_VOLUME_PCT")
+		os.Unsetenv("TRADE_IMPACT_DEPTH_PCT")
+	}()
+
+	cfg = Load()
+	if cfg.TradeMonitor.ImpactVolumePct != 0.25 || cfg.TradeMonitor.ImpactDepthPct != 0 {
+		t.Errorf("expected env overrides, got %+v", cfg.TradeMonitor)
+	}
+
+	cfg.TradeMonitor.ImpactPriceMovePct = -0.1
+	errs := validateTradeMonitor(&cfg.TradeMonitor)
+	if len(errs) != 1 || errs[0].Field != "trade_monitor.impact_price_move_pct" {
+		t.Errorf("expected a price move validation error, got %+v", errs)
+	}
+}
+
+func TestLoad_CoordinatedEntry(t *testing.T) {
+	cfg := Load()
+	if cfg.TradeMonitor.ClusterMinWallets != 4 || cfg.TradeMonitor.ClusterMaxMarkets != 3 ||
+		cfg.TradeMonitor.ClusterWindow != 30*time.Minute || cfg.TradeMonitor.ClusterMinNotional != 20000 {
+		t.Errorf("unexpected coordinated entry defaults: %+v", cfg.TradeMonitor)
+	}
+
+	os.Setenv("TRADE_CLUSTER_MIN_WALLETS", "1")
+	os.Setenv("TRADE_CLUSTER_WINDOW", "1h")
+	defer func() {
+		os.Unsetenv("TRADE_CLUSTER_MIN_WALLETS")
+		os.Unsetenv("TRADE_CLUSTER_WINDOW")
+	}()
+
+	cfg = Load()
+	if cfg.TradeMonitor.ClusterWindow != time.Hour {
+		t.Errorf("expected env override, got %v", cfg.TradeMonitor.ClusterWindow)
+	}
+	errs := validateTradeMonitor(&cfg.TradeMonitor)
+	if len(errs) != 1 || errs[0].Field != "trade_monitor.cluster_min_wallets" {
+		t.Errorf("expected a min wallets validation error, got %+v", errs)
+	}
+}
+
+func TestLoad_MarketAnomaly(t *testing.T) {
+	cfg := Load()
+	if !cfg.MarketAnomaly.Enabled || cfg.MarketAnomaly.Window != 5*time.Minute || cfg.MarketAnomaly.BaselineWindows != 12 {
+		t.Errorf("unexpected market anomaly defaults: %+v", cfg.MarketAnomaly)
+	}
+	if errs := validateMarketAnomaly(&cfg.MarketAnomaly); len(errs) != 0 {
+		t.Errorf("expected defaults to validate, got %v", errs)
+	}
+
+	os.Setenv("MARKET_ANOMALY_WINDOW", "15m")
+	os.Setenv("MARKET_ANOMALY_PRICE_MOVE", "0.2")
+	os.Setenv("MARKET_ANOMALY_TOP_WALLETS", "0")
+	defer func() {
+		os.Unsetenv("MARKET_ANOMALY_WINDOW")
+		os.Unsetenv("MARKET_ANOMALY_PRICE_MOVE")
+		os.Unsetenv("MARKET_ANOMALY_TOP_WALLETS")
+	}()
+
+	cfg = Load()
+	if cfg.MarketAnomaly.Window != 15*time.Minute || cfg.MarketAnomaly.PriceMove != 0.2 {
+		t.Errorf("unexpected market anomaly config: %+v", cfg.MarketAnomaly)
+	}
+	errs := validateMarketAnomaly(&cfg.MarketAnomaly)
+	if len(errs) != 1 || errs[0].Field != "market_anomaly.top_wallets" {
+		t.Errorf("expected top_wallets error, got %v", errs)
+	}
+}
+
+func TestLoad_WalletCluster(t *testing.T) {
+	cfg := Load()
+	if !cfg.WalletCluster.Enabled || cfg.WalletCluster.CoTradeWindow != 10*time.Second || cfg.WalletCluster.CopyMinCount != 5 {
+		t.Errorf("unexpected wallet cluster defaults: %+v", cfg.WalletCluster)
+	}
+	if errs := validateWalletCluster(&cfg.WalletCluster); len(errs) != 0 {
+		t.Errorf("expected defaults to validate, got %v", errs)
+	}
+
+	os.Setenv("WALLET_CLUSTER_CO_TRADE_WINDOW", "30s")
+	os.Setenv("WALLET_CLUSTER_SIZE_TOLERANCE", "0.5")
+	defer func() {
+		os.Unsetenv("WALLET_CLUSTER_CO_TRADE_WINDOW")
+		os.Unsetenv("WALLET_CLUSTER_SIZE_TOLERANCE")
+	}()
+
+	cfg = Load()
+	if cfg.WalletCluster.CoTradeWindow != 30*time.Second {
+		t.Errorf("expected env override, got %v", cfg.WalletCluster.CoTradeWindow)
+	}
+	errs := validateWalletCluster(&cfg.WalletCluster)
+	if len(errs) != 1 || errs[0].Field != "wallet_cluster.size_tolerance" {
+		t.Errorf("expected size_tolerance error, got %v", errs)
+	}
+}
+
+// fakeSettingsGist stores one settings snapshot, optionally failing saves.
+type fakeSettingsGist struct {
+	content string
+	saveErr error
+}
+
+func (f *fakeSettingsGist) IsEnabled() bool   { return true }
+func (f *fakeSettingsGist) GetGistID() string { return "settings" }
+
+func (f *fakeSettingsGist) LoadJSON(ctx context.Context, filename string, dest any) error {
+	return json.Unmarshal([]byte(f.content), dest)
+}
+
+func (f *fakeSettingsGist) SaveJSON(ctx context.Context, filename string, data any) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	b, err := json.Marshal(data)
+	f.content = string(b)
+	return err
+}
+
+func TestSettingsManager_SaveErrorAndReload(t *testing.T) {
+	gist := &fakeSettingsGist{}
+	live := NewLiveConfig(Defaults())
+	sm := NewSettingsManager(nil, gist, "settings", live)
+	ctx := context.Background()
+
+	remote := Defaults()
+	remote.TradeMonitor.MinNotional = 1234
+	gist.saveErr = errors.New("gist file changed since last read")
+
+	local := Defaults()
+	local.TradeMonitor.MinNotional = 99
+	if err := sm.UpdateAndSave(ctx, local); err != nil {
+		t.Fatalf("update should not fail on save errors: %v", err)
+	}
+	if info := sm.GetSettingsInfo(); info.LastSaveError != "save to gist: gist file changed since last read" {
+		t.Errorf("expected the save error in info, got %q", info.LastSaveError)
+	}
+
+	b, _ := json.Marshal(SettingsSnapshot{Version: 1, Config: remote})
+	gist.content = string(b)
+	if err := sm.ReloadSettings(ctx); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if got := live.Get().TradeMonitor.MinNotional; got != 1234 {
+		t.Errorf("expected the stored settings applied, got %v", got)
+	}
+	if info := sm.GetSettingsInfo(); info.LastSaveError != "" {
+		t.Errorf("expected the save error cleared, got %q", info.LastSaveError)
+	}
+}
+
+func TestSettingsManager_Update(t *testing.T) {
+	live := NewLiveConfig(Defaults())
+	sm := NewSettingsManager(nil, nil, "", live)
+	ctx := context.Background()
+
+	// Concurrent read-modify-writes each see the previous result
+	const workers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := sm.Update(ctx, func(cfg *Config) error {
+				cfg.TradeMonitor.MinNotional++
+				return nil
+			})
+			if err != nil {
+				t.Errorf("update failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := Defaults().TradeMonitor.MinNotional + workers
+	if got := live.Get().TradeMonitor.MinNotional; got != want {
+		t.Errorf("expected %v after %d updates, got %v", want, workers, got)
+	}
+
+	// An error from fn leaves the config unchanged
+	abort := errors.New("abort")
+	err := sm.Update(ctx, func(cfg *Config) error {
+		cfg.TradeMonitor.MinNotional = 1
+		return abort
+	})
+	if !errors.Is(err, abort) {
+		t.Errorf("expected fn's error, got %v", err)
+	}
+	if got := live.Get().TradeMonitor.MinNotional; got != want {
+		t.Errorf("expected config unchanged after abort, got %v", got)
+	}
+}
+
+func TestLoad_CopyTracker(t *testing.T) {
+	cfg := Load()
+	if cfg.CopyTracker.GistID != "" || cfg.CopyTracker.FileName != "copy_tracker.json" || cfg.CopyTracker.MaxPairs != 5000 {
+		t.Errorf("unexpected copy tracker defaults: %+v", cfg.CopyTracker)
+	}
+
+	os.Setenv("COPY_TRACKER_GIST_ID", "copy-gist")
+	os.Setenv("COPY_TRACKER_MAX_PAIRS", "0")
+	defer func() {
+		os.Unsetenv("COPY_TRACKER_GIST_ID")
+		os.Unsetenv("COPY_TRACKER_MAX_PAIRS")
+	}()
+
+	cfg = Load()
+	if cfg.CopyTracker.GistID != "copy-gist" {
+		t.Errorf("unexpected copy tracker config: %+v", cfg.CopyTracker)
+	}
+	found := false
+	for _, e := range cfg.Validate().Errors {
+		found = found || e.Field == "copy_tracker.max_pairs"
+	}
+	if !found {
+		t.Error("expected max_pairs validation error")
+	}
+
+	// The gist ID survives a settings merge
+	if merged := mergeConfigs(cfg, Defaults()); merged.CopyTracker.GistID != "copy-gist" {
+		t.Errorf("expected copy tracker gist preserved, got %+v", merged.CopyTracker)
+	}
+
+	cfg.DisablePersistence()
+	if cfg.CopyTracker.GistID != "" {
+		t.Error("expected copy tracker gist cleared")
+	}
+}
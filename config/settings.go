@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -36,6 +37,11 @@ type SettingsManager struct {
 	gist         GistStorage
 	settingsGist string // Separate Gist ID for settings (optional)
 	liveConfig   *LiveConfig
+
+	updateMu sync.Mutex // Serializes read-modify-write cycles on the config
+
+	mu          sync.Mutex
+	lastSaveErr string // Error of the last failed save, cleared on success
 }
 
 // NewSettingsManager creates a new SettingsManager.
@@ -119,6 +125,29 @@ func (sm *SettingsManager) SaveSettings(ctx context.Context) error {
 
 // UpdateAndSave updates the config and saves to Gist.
 func (sm *SettingsManager) UpdateAndSave(ctx context.Context, newConfig *Config) error {
+	sm.updateMu.Lock()
+	defer sm.updateMu.Unlock()
+
+	return sm.applyAndSave(ctx, newConfig)
+}
+
+// Update applies fn to a copy of the current config and saves the result.
+// The whole read-modify-write runs under the manager's lock, so concurrent
+// edits from chat commands and the settings page don't overwrite each other.
+// If fn returns an error, nothing is changed and the error is returned.
+func (sm *SettingsManager) Update(ctx context.Context, fn func(cfg *Config) error) error {
+	sm.updateMu.Lock()
+	defer sm.updateMu.Unlock()
+
+	cfg := sm.liveConfig.Get()
+	if err := fn(cfg); err != nil {
+		return err
+	}
+	return sm.applyAndSave(ctx, cfg)
+}
+
+// applyAndSave updates the live config and saves it. Callers hold updateMu.
+func (sm *SettingsManager) applyAndSave(ctx context.Context, newConfig *Config) error {
 	// Update live config (validates internally)
 	if err := sm.liveConfig.Update(newConfig); err != nil {
 		return fmt.Errorf("update config: %w", err)
@@ -126,22 +155,64 @@ func (sm *SettingsManager) UpdateAndSave(ctx context.Context, newConfig *Config)
 
 	// Save to Gist if enabled
 	if sm.IsEnabled() {
-		if err := sm.SaveSettings(ctx); err != nil {
+		err := sm.SaveSettings(ctx)
+		if err != nil {
 			sm.logger.Error("failed to save settings to gist", zap.Error(err))
-			// Don't fail the update, just log the error
+			// Don't fail the update, just log the error and surface it in the info
+		}
+		sm.setLastSaveError(err)
+	}
+
+	return nil
+}
+
+// ReloadSettings re-loads settings from Gist and applies them, discarding
+// unsaved changes. Used after a save was refused because the settings were
+// changed elsewhere.
+func (sm *SettingsManager) ReloadSettings(ctx context.Context) error {
+	if !sm.IsEnabled() {
+		return fmt.Errorf("settings gist not configured")
+	}
+
+	sm.updateMu.Lock()
+	defer sm.updateMu.Unlock()
+
+	var snapshot SettingsSnapshot
+	if err := sm.loadFromGist(ctx, &snapshot); err != nil {
+		return fmt.Errorf("load from gist: %w", err)
+	}
+	if snapshot.Config != nil {
+		if err := sm.liveConfig.Update(mergeConfigs(sm.liveConfig.Get(), snapshot.Config)); err != nil {
+			return fmt.Errorf("update config: %w", err)
 		}
 	}
+	sm.setLastSaveError(nil)
 
+	sm.logger.Info("reloaded settings from gist",
+		zap.Time("updated_at", snapshot.UpdatedAt),
+	)
 	return nil
 }
 
+func (sm *SettingsManager) setLastSaveError(err error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.lastSaveErr = ""
+	if err != nil {
+		sm.lastSaveErr = err.Error()
+	}
+}
+
 // UpdatePartialAndSave updates specific fields and saves to Gist.
 func (sm *SettingsManager) UpdatePartialAndSave(ctx context.Context, partial *Config) error {
+	sm.updateMu.Lock()
+	defer sm.updateMu.Unlock()
+
 	// Get current config and merge
 	current := sm.liveConfig.Get()
 	merged := mergeConfigs(current, partial)
 
-	return sm.UpdateAndSave(ctx, merged)
+	return sm.applyAndSave(ctx, merged)
 }
 
 // loadFromGist loads settings from the configured Gist.
@@ -197,10 +268,40 @@ func mergeConfigs(base, overlay *Config) *Config {
 	if result.Discord.BotToken == "" {
 		result.Discord.BotToken = base.Discord.BotToken
 	}
+	result.Discord.CommandsEnabled = overlay.Discord.CommandsEnabled || base.Discord.CommandsEnabled
+	result.Discord.GuildID = overlay.Discord.GuildID
+	if result.Discord.GuildID == "" {
+		result.Discord.GuildID = base.Discord.GuildID
+	}
+	result.Discord.AllowedUserIDs = overlay.Discord.AllowedUserIDs
+	if len(result.Discord.AllowedUserIDs) == 0 {
+		result.Discord.AllowedUserIDs = base.Discord.AllowedUserIDs
+	}
 	result.Telegram.BotToken = overlay.Telegram.BotToken
 	if result.Telegram.BotToken == "" {
 		result.Telegram.BotToken = base.Telegram.BotToken
 	}
+	result.Telegram.CommandsEnabled = overlay.Telegram.CommandsEnabled || base.Telegram.CommandsEnabled
+	result.Telegram.AllowedUserIDs = overlay.Telegram.AllowedUserIDs
+	if len(result.Telegram.AllowedUserIDs) == 0 {
+		result.Telegram.AllowedUserIDs = base.Telegram.AllowedUserIDs
+	}
+	result.Slack.BotToken = overlay.Slack.BotToken
+	if result.Slack.BotToken == "" {
+		result.Slack.BotToken = base.Slack.BotToken
+	}
+	result.Slack.ProdWebhookURL = overlay.Slack.ProdWebhookURL
+	if result.Slack.ProdWebhookURL == "" {
+		result.Slack.ProdWebhookURL = base.Slack.ProdWebhookURL
+	}
+	result.Slack.BetaWebhookURL = overlay.Slack.BetaWebhookURL
+	if result.Slack.BetaWebhookURL == "" {
+		result.Slack.BetaWebhookURL = base.Slack.BetaWebhookURL
+	}
+	result.Webhook.Endpoints = overlay.Webhook.Endpoints
+	if len(result.Webhook.Endpoints) == 0 {
+		result.Webhook.Endpoints = base.Webhook.Endpoints
+	}
 
 	// For Gist config, prefer overlay values if set
 	result.Gist.Token = overlay.Gist.Token
@@ -224,22 +325,51 @@ func mergeConfigs(base, overlay *Config) *Config {
 	if result.HedgeTracker.GistID == "" {
 		result.HedgeTracker.GistID = base.HedgeTracker.GistID
 	}
+	result.CopyTracker.GistID = overlay.CopyTracker.GistID
+	if result.CopyTracker.GistID == "" {
+		result.CopyTracker.GistID = base.CopyTracker.GistID
+	}
 	result.PatternTracker.GistID = overlay.PatternTracker.GistID
 	if result.PatternTracker.GistID == "" {
 		result.PatternTracker.GistID = base.PatternTracker.GistID
 	}
+	result.AlertOutcomes.GistID = overlay.AlertOutcomes.GistID
+	if result.AlertOutcomes.GistID == "" {
+		result.AlertOutcomes.GistID = base.AlertOutcomes.GistID
+	}
+	result.DeferredAlerts.GistID = overlay.DeferredAlerts.GistID
+	if result.DeferredAlerts.GistID == "" {
+		result.DeferredAlerts.GistID = base.DeferredAlerts.GistID
+	}
+	result.NotifierQueue.DeadLetterGistID = overlay.NotifierQueue.DeadLetterGistID
+	if result.NotifierQueue.DeadLetterGistID == "" {
+		result.NotifierQueue.DeadLetterGistID = base.NotifierQueue.DeadLetterGistID
+	}
+	result.Mutes.GistID = overlay.Mutes.GistID
+	if result.Mutes.GistID == "" {
+		result.Mutes.GistID = base.Mutes.GistID
+	}
+	result.Feedback.GistID = overlay.Feedback.GistID
+	if result.Feedback.GistID == "" {
+		result.Feedback.GistID = base.Feedback.GistID
+	}
+	result.Threads.GistID = overlay.Threads.GistID
+	if result.Threads.GistID == "" {
+		result.Threads.GistID = base.Threads.GistID
+	}
 
 	return result
 }
 
 // SettingsInfo provides metadata about the current settings state.
 type SettingsInfo struct {
-	Source       string    `json:"source"`        // "gist", "env", "default"
-	LastUpdated  time.Time `json:"last_updated"`
-	GistEnabled  bool      `json:"gist_enabled"`
-	GistID       string    `json:"gist_id,omitempty"`
-	IsValid      bool      `json:"is_valid"`
-	Errors       []string  `json:"errors,omitempty"`
+	Source        string    `json:"source"` // "gist", "env", "default"
+	LastUpdated   time.Time `json:"last_updated"`
+	GistEnabled   bool      `json:"gist_enabled"`
+	GistID        string    `json:"gist_id,omitempty"`
+	IsValid       bool      `json:"is_valid"`
+	Errors        []string  `json:"errors,omitempty"`
+	LastSaveError string    `json:"last_save_error,omitempty"` // Set while the latest change isn't persisted
 }
 
 // GetSettingsInfo returns metadata about the current settings.
@@ -264,5 +394,9 @@ func (sm *SettingsManager) GetSettingsInfo() SettingsInfo {
 		info.Errors = append(info.Errors, e.Field+": "+e.Message)
 	}
 
+	sm.mu.Lock()
+	info.LastSaveError = sm.lastSaveErr
+	sm.mu.Unlock()
+
 	return info
 }
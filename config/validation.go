@@ -24,6 +24,9 @@ func (c *Config) Validate() ValidationResult {
 	// TradeMonitor validation
 	errors = append(errors, validateTradeMonitor(&c.TradeMonitor)...)
 
+	// Webhook validation
+	errors = append(errors, validateWebhook(&c.Webhook)...)
+
 	// Markets validation
 	errors = append(errors, validateMarkets(&c.Markets)...)
 
@@ -36,9 +39,45 @@ func (c *Config) Validate() ValidationResult {
 	// HedgeTracker validation
 	errors = append(errors, validateHedgeTracker(&c.HedgeTracker)...)
 
+	// CopyTracker validation
+	errors = append(errors, validateCopyTracker(&c.CopyTracker)...)
+
 	// PatternTracker validation
 	errors = append(errors, validatePatternTracker(&c.PatternTracker)...)
 
+	// AlertOutcomes validation
+	errors = append(errors, validateAlertOutcomes(&c.AlertOutcomes)...)
+
+	// DeferredAlerts validation
+	errors = append(errors, validateDeferredAlerts(&c.DeferredAlerts)...)
+
+	// NotifierQueue validation
+	errors = append(errors, validateNotifierQueue(&c.NotifierQueue)...)
+
+	// Routing validation
+	errors = append(errors, validateRouting(&c.Routing)...)
+
+	// Digest validation
+	errors = append(errors, validateDigest(&c.Digest)...)
+
+	// Suppression validation
+	errors = append(errors, validateSuppression(&c.Suppression)...)
+
+	// Mutes validation
+	errors = append(errors, validateMutes(&c.Mutes)...)
+
+	// Feedback validation
+	errors = append(errors, validateFeedback(&c.Feedback)...)
+
+	// Threads validation
+	errors = append(errors, validateThreads(&c.Threads)...)
+
+	// MarketAnomaly validation
+	errors = append(errors, validateMarketAnomaly(&c.MarketAnomaly)...)
+
+	// WalletCluster validation
+	errors = append(errors, validateWalletCluster(&c.WalletCluster)...)
+
 	// HealthServer validation
 	errors = append(errors, validateHealthServer(&c.HealthServer)...)
 
@@ -170,6 +209,62 @@ func validateTradeMonitor(tm *TradeMonitorConfig) []ValidationError {
 		})
 	}
 
+	if tm.ImpactMinNotional < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.impact_min_notional",
+			Message: "must be non-negative",
+		})
+	}
+
+	if tm.ImpactVolumePct < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.impact_volume_pct",
+			Message: "must be non-negative",
+		})
+	}
+
+	if tm.ImpactDepthPct < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.impact_depth_pct",
+			Message: "must be non-negative",
+		})
+	}
+
+	if tm.ImpactPriceMovePct < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.impact_price_move_pct",
+			Message: "must be non-negative",
+		})
+	}
+
+	if tm.ClusterMinWallets < 0 || tm.ClusterMinWallets == 1 {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.cluster_min_wallets",
+			Message: "must be 0 (disabled) or at least 2",
+		})
+	}
+
+	if tm.ClusterMaxMarkets < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.cluster_max_markets",
+			Message: "must be non-negative",
+		})
+	}
+
+	if tm.ClusterMinWallets > 0 && (tm.ClusterWindow < 1*time.Minute || tm.ClusterWindow > 24*time.Hour) {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.cluster_window",
+			Message: "must be between 1 minute and 24 hours",
+		})
+	}
+
+	if tm.ClusterMinNotional < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.cluster_min_notional",
+			Message: "must be non-negative",
+		})
+	}
+
 	if tm.ObviousPrice < 0 || tm.ObviousPrice > 1 {
 		errors = append(errors, ValidationError{
 			Field:   "trade_monitor.obvious_price",
@@ -177,6 +272,13 @@ func validateTradeMonitor(tm *TradeMonitorConfig) []ValidationError {
 		})
 	}
 
+	if tm.FillAggregationWindow < 0 || tm.FillAggregationWindow > time.Minute {
+		errors = append(errors, ValidationError{
+			Field:   "trade_monitor.fill_aggregation_window",
+			Message: "must be between 0 and 1 minute",
+		})
+	}
+
 	if tm.CopyTradeWindow < 1*time.Second {
 		errors = append(errors, ValidationError{
 			Field:   "trade_monitor.copy_trade_window",
@@ -358,6 +460,33 @@ func validateHedgeTracker(ht *HedgeTrackerConfig) []ValidationError {
 	return errors
 }
 
+func validateAlertOutcomes(ao *AlertOutcomesConfig) []ValidationError {
+	var errors []ValidationError
+
+	if ao.SaveInterval < 1*time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "alert_outcomes.save_interval",
+			Message: "must be at least 1 second",
+		})
+	}
+
+	if ao.ResolutionCheckInterval < 1*time.Minute {
+		errors = append(errors, ValidationError{
+			Field:   "alert_outcomes.resolution_check_interval",
+			Message: "must be at least 1 minute",
+		})
+	}
+
+	if ao.MaxAlerts < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "alert_outcomes.max_alerts",
+			Message: "must be at least 1",
+		})
+	}
+
+	return errors
+}
+
 func validatePatternTracker(pt *PatternTrackerConfig) []ValidationError {
 	var errors []ValidationError
 
@@ -549,3 +678,390 @@ func validateHealthServer(hs *HealthServerConfig) []ValidationError {
 
 	return errors
 }
+
+func validateDeferredAlerts(da *DeferredAlertsConfig) []ValidationError {
+	var errors []ValidationError
+
+	if da.CheckInterval < 1*time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "deferred_alerts.check_interval",
+			Message: "must be at least 1 second",
+		})
+	}
+
+	if da.SentRetention < 1*time.Hour {
+		errors = append(errors, ValidationError{
+			Field:   "deferred_alerts.sent_retention",
+			Message: "must be at least 1 hour",
+		})
+	}
+
+	return errors
+}
+
+func validateNotifierQueue(nq *NotifierQueueConfig) []ValidationError {
+	var errors []ValidationError
+
+	if nq.BufferSize < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "notifier_queue.buffer_size",
+			Message: "must be at least 1",
+		})
+	}
+
+	if nq.MaxAttempts < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "notifier_queue.max_attempts",
+			Message: "must be at least 1",
+		})
+	}
+
+	if nq.InitialBackoff < 100*time.Millisecond {
+		errors = append(errors, ValidationError{
+			Field:   "notifier_queue.initial_backoff",
+			Message: "must be at least 100ms",
+		})
+	}
+
+	if nq.MaxBackoff < nq.InitialBackoff {
+		errors = append(errors, ValidationError{
+			Field:   "notifier_queue.max_backoff",
+			Message: "must be at least initial_backoff",
+		})
+	}
+
+	if nq.MinInterval < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "notifier_queue.min_interval",
+			Message: "must not be negative",
+		})
+	}
+
+	if nq.DeadLetterSaveInterval < 1*time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "notifier_queue.dead_letter_save_interval",
+			Message: "must be at least 1 second",
+		})
+	}
+
+	if nq.DeadLetterMaxEntries < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "notifier_queue.dead_letter_max_entries",
+			Message: "must be at least 1",
+		})
+	}
+
+	return errors
+}
+
+func validateMutes(m *MutesConfig) []ValidationError {
+	var errors []ValidationError
+
+	if m.SaveInterval < 1*time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "mutes.save_interval",
+			Message: "must be at least 1 second",
+		})
+	}
+
+	return errors
+}
+
+func validateFeedback(f *FeedbackConfig) []ValidationError {
+	var errors []ValidationError
+
+	if f.SaveInterval < 1*time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "feedback.save_interval",
+			Message: "must be at least 1 second",
+		})
+	}
+	if f.MaxAlerts < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "feedback.max_alerts",
+			Message: "must be at least 1",
+		})
+	}
+
+	return errors
+}
+
+func validateRouting(rc *RoutingConfig) []ValidationError {
+	var errors []ValidationError
+
+	names := make(map[string]bool, len(rc.Destinations))
+	for i, d := range rc.Destinations {
+		field := fmt.Sprintf("routing.destinations[%d]", i)
+		if d.Name == "" {
+			errors = append(errors, ValidationError{Field: field + ".name", Message: "is required"})
+		} else if names[d.Name] {
+			errors = append(errors, ValidationError{Field: field + ".name", Message: "must be unique"})
+		}
+		names[d.Name] = true
+
+		switch d.Channel {
+		case RouteChannelDiscord, RouteChannelTelegram, RouteChannelSlack:
+		default:
+			errors = append(errors, ValidationError{Field: field + ".channel", Message: "must be discord, telegram or slack"})
+		}
+		if d.Target == "" {
+			errors = append(errors, ValidationError{Field: field + ".target", Message: "is required"})
+		}
+	}
+
+	for i, rule := range rc.Rules {
+		field := fmt.Sprintf("routing.rules[%d]", i)
+		if len(rule.Destinations) == 0 && !rule.IncludeDefault {
+			errors = append(errors, ValidationError{Field: field + ".destinations", Message: "must list at least one destination"})
+		}
+		for _, name := range rule.Destinations {
+			if !names[name] {
+				errors = append(errors, ValidationError{Field: field + ".destinations", Message: fmt.Sprintf("unknown destination %q", name)})
+			}
+		}
+		if rule.MinNotional < 0 || rule.MaxNotional < 0 {
+			errors = append(errors, ValidationError{Field: field + ".min_notional", Message: "must not be negative"})
+		}
+		if rule.MaxNotional > 0 && rule.MaxNotional < rule.MinNotional {
+			errors = append(errors, ValidationError{Field: field + ".max_notional", Message: "must be at least min_notional"})
+		}
+		if rule.MinPrice < 0 || rule.MaxPrice > 1 {
+			errors = append(errors, ValidationError{Field: field + ".min_price", Message: "prices must be between 0 and 1"})
+		}
+		if rule.MaxPrice > 0 && rule.MaxPrice < rule.MinPrice {
+			errors = append(errors, ValidationError{Field: field + ".max_price", Message: "must be at least min_price"})
+		}
+	}
+
+	return errors
+}
+
+func validateDigest(dc *DigestConfig) []ValidationError {
+	var errors []ValidationError
+
+	if dc.Window < 1*time.Minute {
+		errors = append(errors, ValidationError{
+			Field:   "digest.window",
+			Message: "must be at least 1 minute",
+		})
+	}
+
+	if dc.TopN < 1 || dc.TopN > 25 {
+		errors = append(errors, ValidationError{
+			Field:   "digest.top_n",
+			Message: "must be between 1 and 25",
+		})
+	}
+
+	return errors
+}
+
+func validateSuppression(sc *SuppressionConfig) []ValidationError {
+	var errors []ValidationError
+
+	if sc.Cooldown < 0 || sc.Cooldown > 24*time.Hour {
+		errors = append(errors, ValidationError{
+			Field:   "suppression.cooldown",
+			Message: "must be between 0 and 24 hours",
+		})
+	}
+
+	seen := make(map[string]bool)
+	for i, rc := range sc.ReasonCooldowns {
+		field := fmt.Sprintf("suppression.reason_cooldowns[%d]", i)
+		if rc.Reason == "" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".reason",
+				Message: "is required",
+			})
+		} else if seen[rc.Reason] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".reason",
+				Message: fmt.Sprintf("duplicate reason %q", rc.Reason),
+			})
+		}
+		seen[rc.Reason] = true
+
+		if rc.Cooldown < 0 || rc.Cooldown > 24*time.Hour {
+			errors = append(errors, ValidationError{
+				Field:   field + ".cooldown",
+				Message: "must be between 0 and 24 hours",
+			})
+		}
+	}
+
+	return errors
+}
+
+func validateWebhook(wh *WebhookConfig) []ValidationError {
+	var errors []ValidationError
+
+	if wh.Timeout < 1*time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "webhook.timeout",
+			Message: "must be at least 1 second",
+		})
+	}
+
+	return errors
+}
+
+func validateCopyTracker(ct *CopyTrackerConfig) []ValidationError {
+	var errors []ValidationError
+
+	if ct.SaveInterval < 1*time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "copy_tracker.save_interval",
+			Message: "must be at least 1 second",
+		})
+	}
+	if ct.MaxPairs < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "copy_tracker.max_pairs",
+			Message: "must be at least 1",
+		})
+	}
+
+	return errors
+}
+
+func validateThreads(t *ThreadsConfig) []ValidationError {
+	var errors []ValidationError
+
+	if t.Retention < 1*time.Hour {
+		errors = append(errors, ValidationError{
+			Field:   "threads.retention",
+			Message: "must be at least 1 hour",
+		})
+	}
+	if t.MaxThreads < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "threads.max_threads",
+			Message: "must be at least 1",
+		})
+	}
+	if t.SaveInterval < 1*time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "threads.save_interval",
+			Message: "must be at least 1 second",
+		})
+	}
+
+	return errors
+}
+
+func validateMarketAnomaly(ma *MarketAnomalyConfig) []ValidationError {
+	var errors []ValidationError
+
+	if ma.Window < 1*time.Minute || ma.Window > 24*time.Hour {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.window",
+			Message: "must be between 1 minute and 24 hours",
+		})
+	}
+	if ma.BaselineWindows < 2 || ma.BaselineWindows > 288 {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.baseline_windows",
+			Message: "must be between 2 and 288",
+		})
+	}
+	if ma.VolumeStdDevs < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.volume_stddevs",
+			Message: "must be non-negative",
+		})
+	}
+	if ma.VolumeMinNotional < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.volume_min_notional",
+			Message: "must be non-negative",
+		})
+	}
+	if ma.PriceMove < 0 || ma.PriceMove > 1 {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.price_move",
+			Message: "must be between 0 and 1",
+		})
+	}
+	if ma.PriceWindow < 0 || ma.PriceWindow > 24*time.Hour {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.price_window",
+			Message: "must be between 0 and 24 hours",
+		})
+	}
+	if ma.PriceMinNotional < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.price_min_notional",
+			Message: "must be non-negative",
+		})
+	}
+	if ma.Cooldown < 0 || ma.Cooldown > 7*24*time.Hour {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.cooldown",
+			Message: "must be between 0 and 7 days",
+		})
+	}
+	if ma.TopWallets < 1 || ma.TopWallets > 25 {
+		errors = append(errors, ValidationError{
+			Field:   "market_anomaly.top_wallets",
+			Message: "must be between 1 and 25",
+		})
+	}
+
+	return errors
+}
+
+func validateWalletCluster(wc *WalletClusterConfig) []ValidationError {
+	var errors []ValidationError
+
+	if wc.MinNotional < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "wallet_cluster.min_notional",
+			Message: "must be non-negative",
+		})
+	}
+	if wc.CoTradeWindow < 1*time.Second || wc.CoTradeWindow > 10*time.Minute {
+		errors = append(errors, ValidationError{
+			Field:   "wallet_cluster.co_trade_window",
+			Message: "must be between 1 second and 10 minutes",
+		})
+	}
+	if wc.CoTradeMinMarkets < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "wallet_cluster.co_trade_min_markets",
+			Message: "must be at least 1",
+		})
+	}
+	if wc.SizeWindow < 0 || wc.SizeWindow > 24*time.Hour {
+		errors = append(errors, ValidationError{
+			Field:   "wallet_cluster.size_window",
+			Message: "must be between 0 and 24 hours",
+		})
+	}
+	if wc.SizeTolerance < 0 || wc.SizeTolerance > 0.10 {
+		errors = append(errors, ValidationError{
+			Field:   "wallet_cluster.size_tolerance",
+			Message: "must be between 0 and 0.10",
+		})
+	}
+	if wc.SizeMinMarkets < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "wallet_cluster.size_min_markets",
+			Message: "must be at least 1",
+		})
+	}
+	if wc.CopyMinCount < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "wallet_cluster.copy_min_count",
+			Message: "must be non-negative",
+		})
+	}
+	if wc.EvidenceMaxAge < 1*time.Hour || wc.EvidenceMaxAge > 90*24*time.Hour {
+		errors = append(errors, ValidationError{
+			Field:   "wallet_cluster.evidence_max_age",
+			Message: "must be between 1 hour and 90 days",
+		})
+	}
+
+	return errors
+}
@@ -0,0 +1,407 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// maxDigestTopN caps the entries listed per digest section.
+const maxDigestTopN = 25
+
+// digestCheckInterval is how often the digester checks for a finished window.
+const digestCheckInterval = 15 * time.Second
+
+// AlertDigesterConfig holds configuration for digest mode.
+type AlertDigesterConfig struct {
+	Enabled        bool
+	Window         time.Duration // Batching window; digests are sent on window boundaries
+	TopN           int           // Entries listed per digest section
+	InstantReasons []AlertReason // Reasons delivered immediately instead of batched
+}
+
+// DefaultAlertDigesterConfig returns sensible defaults.
+func DefaultAlertDigesterConfig() AlertDigesterConfig {
+	return AlertDigesterConfig{
+		Window: 1 * time.Hour,
+		TopN:   5,
+		InstantReasons: []AlertReason{
+			AlertReasonHedgeRemoval,
+			AlertReasonResolutionConfirmed,
+			AlertReasonContrarianWinner,
+		},
+	}
+}
+
+// AlertCountSource provides rolling alert totals for digests.
+// Implemented by TradeMonitor.
+type AlertCountSource interface {
+	AlertCountsInPeriods() (hour, day, week int)
+}
+
+// DigestStats summarizes digest mode.
+type DigestStats struct {
+	Enabled    bool      `json:"enabled"`
+	Window     string    `json:"window"`
+	Pending    int       `json:"pending"` // Alerts batched in the current window
+	Instant    int64     `json:"instant"` // Alerts delivered immediately
+	Batched    int64     `json:"batched"` // Alerts summarized in digests
+	Digests    int64     `json:"digests"` // Digests sent
+	LastSentAt time.Time `json:"last_sent_at,omitempty"`
+}
+
+// digestWindow aggregates the alerts batched in one window.
+type digestWindow struct {
+	start   time.Time
+	total   int
+	reasons map[AlertReason]int
+	wallets map[string]*notifier.DigestWallet
+	markets map[string]*notifier.DigestMarket
+	largest []notifier.TradeAlert // Sorted by notional descending, capped at maxDigestTopN
+}
+
+func newDigestWindow(start time.Time) *digestWindow {
+	return &digestWindow{
+		start:   start,
+		reasons: make(map[AlertReason]int),
+		wallets: make(map[string]*notifier.DigestWallet),
+		markets: make(map[string]*notifier.DigestMarket),
+	}
+}
+
+// AlertDigester batches alerts into periodic digest messages so busy channels
+// get one summary per window. Alerts with an instant reason are still
+// delivered immediately. Implements notifier.Notifier interface.
+type AlertDigester struct {
+	logger *zap.Logger
+	target notifier.Notifier // Receives instant alerts and digests
+	counts AlertCountSource  // Rolling totals (may be nil)
+	now    func() time.Time
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   AlertDigesterConfig
+
+	mu      sync.Mutex
+	window  *digestWindow
+	instant int64
+	batched int64
+	digests int64
+	lastAt  time.Time
+
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewAlertDigester creates a digester in front of target.
+func NewAlertDigester(logger *zap.Logger, config AlertDigesterConfig, target notifier.Notifier) *AlertDigester {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	ad := &AlertDigester{
+		logger: logger.Named("alert-digest"),
+		target: target,
+		config: config,
+		now:    time.Now,
+		doneCh: make(chan struct{}),
+	}
+	ad.window = newDigestWindow(ad.now())
+	return ad
+}
+
+// SetAlertCountSource sets where rolling alert totals come from.
+func (ad *AlertDigester) SetAlertCountSource(counts AlertCountSource) {
+	ad.counts = counts
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (ad *AlertDigester) getConfig() AlertDigesterConfig {
+	ad.configMu.RLock()
+	defer ad.configMu.RUnlock()
+	return ad.config
+}
+
+// UpdateConfig updates the digest config. Turning digest mode off sends the
+// alerts batched so far.
+func (ad *AlertDigester) UpdateConfig(cfg AlertDigesterConfig) {
+	ad.configMu.Lock()
+	wasEnabled := ad.config.Enabled
+	ad.config = cfg
+	ad.configMu.Unlock()
+
+	ad.logger.Info("alert digest config updated",
+		zap.Bool("enabled", cfg.Enabled),
+		zap.Duration("window", cfg.Window),
+	)
+
+	if wasEnabled && !cfg.Enabled {
+		ad.Flush()
+	}
+}
+
+// SendTradeAlert delivers the alert immediately or batches it for the next
+// digest. Implements notifier.Notifier interface.
+func (ad *AlertDigester) SendTradeAlert(alert notifier.TradeAlert) {
+	cfg := ad.getConfig()
+	if !cfg.Enabled || isInstantAlert(alert, cfg.InstantReasons) {
+		ad.mu.Lock()
+		if cfg.Enabled {
+			ad.instant++
+		}
+		ad.mu.Unlock()
+		if ad.target != nil {
+			ad.target.SendTradeAlert(alert)
+		}
+		return
+	}
+
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	ad.batched++
+	ad.window.add(alert)
+}
+
+// isInstantAlert reports whether an alert has a reason that bypasses batching.
+func isInstantAlert(alert notifier.TradeAlert, instant []AlertReason) bool {
+	for _, r := range alert.Reasons {
+		for _, ir := range instant {
+			if r == ir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// add aggregates an alert into the window.
+func (w *digestWindow) add(alert notifier.TradeAlert) {
+	w.total++
+	for _, r := range alert.Reasons {
+		w.reasons[r]++
+	}
+
+	if alert.TraderAddress != "" {
+		key := strings.ToLower(alert.TraderAddress)
+		wallet := w.wallets[key]
+		if wallet == nil {
+			wallet = &notifier.DigestWallet{Address: alert.TraderAddress}
+			w.wallets[key] = wallet
+		}
+		wallet.Count++
+		wallet.Notional += alert.Notional
+		if alert.TraderName != "" {
+			wallet.Name = alert.TraderName
+		}
+		if alert.WalletURL != "" {
+			wallet.URL = alert.WalletURL
+		}
+	}
+
+	if alert.ConditionID != "" {
+		market := w.markets[alert.ConditionID]
+		if market == nil {
+			market = &notifier.DigestMarket{ConditionID: alert.ConditionID}
+			w.markets[alert.ConditionID] = market
+		}
+		market.Count++
+		market.Notional += alert.Notional
+		if alert.MarketTitle != "" {
+			market.Title = alert.MarketTitle
+		}
+		if alert.MarketURL != "" {
+			market.URL = alert.MarketURL
+		}
+	}
+
+	// Keep the largest alerts sorted by notional
+	idx := sort.Search(len(w.largest), func(i int) bool {
+		return w.largest[i].Notional < alert.Notional
+	})
+	if idx < maxDigestTopN {
+		w.largest = append(w.largest, notifier.TradeAlert{})
+		copy(w.largest[idx+1:], w.largest[idx:])
+		w.largest[idx] = alert
+		if len(w.largest) > maxDigestTopN {
+			w.largest = w.largest[:maxDigestTopN]
+		}
+	}
+}
+
+// Start begins sending digests at the end of each window.
+func (ad *AlertDigester) Start(ctx context.Context) {
+	go ad.run(ctx)
+}
+
+// run checks for finished windows until stopped.
+func (ad *AlertDigester) run(ctx context.Context) {
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ad.doneCh:
+			return
+		case <-ticker.C:
+			ad.checkWindow()
+		}
+	}
+}
+
+// checkWindow sends a digest once the current window has ended. Windows end
+// on multiples of the window size (e.g. on the hour, or at midnight UTC).
+func (ad *AlertDigester) checkWindow() {
+	cfg := ad.getConfig()
+	if !cfg.Enabled {
+		return
+	}
+	window := cfg.Window
+	if window <= 0 {
+		window = DefaultAlertDigesterConfig().Window
+	}
+
+	ad.mu.Lock()
+	start := ad.window.start
+	ad.mu.Unlock()
+
+	if ad.now().Truncate(window).After(start) {
+		ad.Flush()
+	}
+}
+
+// Flush sends a digest of the alerts batched so far and starts a new window.
+// Returns false if there was nothing to send.
+func (ad *AlertDigester) Flush() bool {
+	now := ad.now()
+
+	ad.mu.Lock()
+	window := ad.window
+	ad.window = newDigestWindow(now)
+	ad.mu.Unlock()
+
+	if window.total == 0 {
+		return false
+	}
+
+	digest := ad.buildDigest(window, now)
+
+	ad.mu.Lock()
+	ad.digests++
+	ad.lastAt = now
+	ad.mu.Unlock()
+
+	ad.logger.Info("sending alert digest",
+		zap.Int("alerts", digest.TotalAlerts),
+		zap.String("window", digest.WindowLabel()),
+	)
+
+	if d, ok := ad.target.(notifier.DigestNotifier); ok {
+		d.SendDigest(digest)
+	}
+	return true
+}
+
+// buildDigest converts a window into a digest message.
+func (ad *AlertDigester) buildDigest(w *digestWindow, end time.Time) notifier.AlertDigest {
+	topN := ad.getConfig().TopN
+	if topN <= 0 {
+		topN = DefaultAlertDigesterConfig().TopN
+	}
+	if topN > maxDigestTopN {
+		topN = maxDigestTopN
+	}
+
+	digest := notifier.AlertDigest{
+		WindowStart: w.start,
+		WindowEnd:   end,
+		TotalAlerts: w.total,
+	}
+
+	for reason, count := range w.reasons {
+		digest.ReasonCounts = append(digest.ReasonCounts, notifier.DigestReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(digest.ReasonCounts, func(i, j int) bool {
+		a, b := digest.ReasonCounts[i], digest.ReasonCounts[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Reason < b.Reason
+	})
+
+	for _, wallet := range w.wallets {
+		digest.TopWallets = append(digest.TopWallets, *wallet)
+	}
+	sort.Slice(digest.TopWallets, func(i, j int) bool {
+		a, b := digest.TopWallets[i], digest.TopWallets[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Notional > b.Notional
+	})
+	if len(digest.TopWallets) > topN {
+		digest.TopWallets = digest.TopWallets[:topN]
+	}
+
+	for _, market := range w.markets {
+		digest.TopMarkets = append(digest.TopMarkets, *market)
+	}
+	sort.Slice(digest.TopMarkets, func(i, j int) bool {
+		a, b := digest.TopMarkets[i], digest.TopMarkets[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Notional > b.Notional
+	})
+	if len(digest.TopMarkets) > topN {
+		digest.TopMarkets = digest.TopMarkets[:topN]
+	}
+
+	digest.LargestTrades = w.largest
+	if len(digest.LargestTrades) > topN {
+		digest.LargestTrades = digest.LargestTrades[:topN]
+	}
+
+	if ad.counts != nil {
+		digest.AlertsLastHour, digest.AlertsLastDay, digest.AlertsLastWeek = ad.counts.AlertCountsInPeriods()
+	}
+
+	return digest
+}
+
+// Stats returns digest counters.
+func (ad *AlertDigester) Stats() DigestStats {
+	cfg := ad.getConfig()
+
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	return DigestStats{
+		Enabled:    cfg.Enabled,
+		Window:     cfg.Window.String(),
+		Pending:    ad.window.total,
+		Instant:    ad.instant,
+		Batched:    ad.batched,
+		Digests:    ad.digests,
+		LastSentAt: ad.lastAt,
+	}
+}
+
+// Close stops the digester, sends any batched alerts and closes the target.
+// Implements notifier.Notifier interface.
+func (ad *AlertDigester) Close() error {
+	ad.closeOnce.Do(func() {
+		close(ad.doneCh)
+	})
+	ad.Flush()
+	if ad.target != nil {
+		return ad.target.Close()
+	}
+	return nil
+}
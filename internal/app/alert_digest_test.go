@@ -0,0 +1,155 @@
+package app
+
+import (
+	"polybot/clients/notifier"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// digestCapture records alerts and digests sent through it.
+type digestCapture struct {
+	captureNotifier
+	digestMu sync.Mutex
+	digests  []notifier.AlertDigest
+}
+
+func (c *digestCapture) SendDigest(digest notifier.AlertDigest) {
+	c.digestMu.Lock()
+	defer c.digestMu.Unlock()
+	c.digests = append(c.digests, digest)
+}
+
+func (c *digestCapture) Digests() []notifier.AlertDigest {
+	c.digestMu.Lock()
+	defer c.digestMu.Unlock()
+	return append([]notifier.AlertDigest(nil), c.digests...)
+}
+
+// staticAlertCounts is an AlertCountSource with fixed totals.
+type staticAlertCounts struct{ hour, day, week int }
+
+func (s staticAlertCounts) AlertCountsInPeriods() (int, int, int) { return s.hour, s.day, s.week }
+
+func enabledDigestConfig() AlertDigesterConfig {
+	cfg := DefaultAlertDigesterConfig()
+	cfg.Enabled = true
+	cfg.TopN = 2
+	return cfg
+}
+
+func TestAlertDigester_DisabledPassesThrough(t *testing.T) {
+	target := &digestCapture{}
+	ad := NewAlertDigester(zap.NewNop(), DefaultAlertDigesterConfig(), target)
+
+	ad.SendTradeAlert(notifier.TradeAlert{Reasons: []AlertReason{AlertReasonNewWallet}})
+
+	if len(target.Alerts()) != 1 {
+		t.Errorf("expected alert delivered immediately when disabled, got %d", len(target.Alerts()))
+	}
+	if ad.Flush() {
+		t.Error("expected nothing to flush when disabled")
+	}
+}
+
+func TestAlertDigester_InstantReasonsBypassBatching(t *testing.T) {
+	target := &digestCapture{}
+	ad := NewAlertDigester(zap.NewNop(), enabledDigestConfig(), target)
+
+	ad.SendTradeAlert(notifier.TradeAlert{Reasons: []AlertReason{AlertReasonNewWallet}})
+	ad.SendTradeAlert(notifier.TradeAlert{Reasons: []AlertReason{AlertReasonNewWallet, AlertReasonHedgeRemoval}})
+
+	alerts := target.Alerts()
+	if len(alerts) != 1 || alerts[0].Reasons[1] != AlertReasonHedgeRemoval {
+		t.Errorf("expected only the hedge removal alert delivered immediately, got %+v", alerts)
+	}
+	stats := ad.Stats()
+	if stats.Pending != 1 || stats.Instant != 1 || stats.Batched != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestAlertDigester_FlushBuildsDigest(t *testing.T) {
+	target := &digestCapture{}
+	ad := NewAlertDigester(zap.NewNop(), enabledDigestConfig(), target)
+	ad.SetAlertCountSource(staticAlertCounts{hour: 4, day: 40, week: 400})
+
+	alerts := []notifier.TradeAlert{
+		{TraderAddress: "0xAAA", TraderName: "whale", ConditionID: "m1", MarketTitle: "Market 1", Notional: 5000, Reasons: []AlertReason{AlertReasonMassiveTrade}},
+		{TraderAddress: "0xaaa", TraderName: "whale", ConditionID: "m2", MarketTitle: "Market 2", Notional: 1000, Reasons: []AlertReason{AlertReasonNewWallet}},
+		{TraderAddress: "0xBBB", ConditionID: "m1", MarketTitle: "Market 1", Notional: 20000, Reasons: []AlertReason{AlertReasonNewWallet}},
+		{TraderAddress: "0xCCC", ConditionID: "m3", MarketTitle: "Market 3", Notional: 100, Reasons: []AlertReason{AlertReasonNewWallet, AlertReasonExtremeBet}},
+	}
+	for _, a := range alerts {
+		ad.SendTradeAlert(a)
+	}
+
+	if !ad.Flush() {
+		t.Fatal("expected a digest to be sent")
+	}
+	digests := target.Digests()
+	if len(digests) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(digests))
+	}
+	d := digests[0]
+
+	if d.TotalAlerts != 4 {
+		t.Errorf("expected 4 alerts, got %d", d.TotalAlerts)
+	}
+	if d.ReasonCounts[0].Reason != AlertReasonNewWallet || d.ReasonCounts[0].Count != 3 {
+		t.Errorf("expected new_wallet first with 3, got %+v", d.ReasonCounts)
+	}
+	// Wallet addresses are matched case-insensitively
+	if len(d.TopWallets) != 2 || d.TopWallets[0].Name != "whale" || d.TopWallets[0].Count != 2 || d.TopWallets[0].Notional != 6000 {
+		t.Errorf("unexpected top wallets: %+v", d.TopWallets)
+	}
+	if len(d.TopMarkets) != 2 || d.TopMarkets[0].ConditionID != "m1" || d.TopMarkets[0].Count != 2 {
+		t.Errorf("unexpected top markets: %+v", d.TopMarkets)
+	}
+	if len(d.LargestTrades) != 2 || d.LargestTrades[0].Notional != 20000 || d.LargestTrades[1].Notional != 5000 {
+		t.Errorf("unexpected largest trades: %+v", d.LargestTrades)
+	}
+	if d.AlertsLastHour != 4 || d.AlertsLastDay != 40 || d.AlertsLastWeek != 400 {
+		t.Errorf("expected rolling totals from the trade monitor, got %+v", d)
+	}
+
+	if ad.Flush() {
+		t.Error("expected the window to be reset after a flush")
+	}
+}
+
+func TestAlertDigester_CheckWindowOnBoundary(t *testing.T) {
+	target := &digestCapture{}
+	ad := NewAlertDigester(zap.NewNop(), enabledDigestConfig(), target)
+
+	now := time.Date(2024, 1, 15, 10, 20, 0, 0, time.UTC)
+	ad.now = func() time.Time { return now }
+	ad.window = newDigestWindow(now)
+	ad.SendTradeAlert(notifier.TradeAlert{Reasons: []AlertReason{AlertReasonNewWallet}})
+
+	now = now.Add(30 * time.Minute) // 10:50, same hour
+	ad.checkWindow()
+	if len(target.Digests()) != 0 {
+		t.Fatal("expected no digest before the window boundary")
+	}
+
+	now = now.Add(15 * time.Minute) // 11:05, next hour
+	ad.checkWindow()
+	if len(target.Digests()) != 1 {
+		t.Fatal("expected a digest after the window boundary")
+	}
+}
+
+func TestAlertDigester_DisableFlushesPending(t *testing.T) {
+	target := &digestCapture{}
+	ad := NewAlertDigester(zap.NewNop(), enabledDigestConfig(), target)
+
+	ad.SendTradeAlert(notifier.TradeAlert{Reasons: []AlertReason{AlertReasonNewWallet}})
+	ad.UpdateConfig(DefaultAlertDigesterConfig())
+
+	if len(target.Digests()) != 1 {
+		t.Error("expected pending alerts to be sent when digest mode is turned off")
+	}
+}
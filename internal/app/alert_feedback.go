@@ -0,0 +1,452 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/gist"
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// FeedbackVerdict is feedback given on an alert through its buttons.
+type FeedbackVerdict string
+
+const (
+	FeedbackFalsePositive FeedbackVerdict = "false_positive"
+	FeedbackMutedWallet   FeedbackVerdict = "muted_wallet"
+	FeedbackMutedMarket   FeedbackVerdict = "muted_market"
+)
+
+// maxRecentFeedback caps the feedback listed on the dashboard.
+const maxRecentFeedback = 10
+
+// AlertFeedbackConfig holds configuration for the alert feedback store.
+type AlertFeedbackConfig struct {
+	GistID       string
+	FileName     string
+	SaveInterval time.Duration
+	MaxAlerts    int // Max alerts to keep (oldest are dropped first)
+}
+
+// DefaultAlertFeedbackConfig returns sensible defaults.
+func DefaultAlertFeedbackConfig() AlertFeedbackConfig {
+	return AlertFeedbackConfig{
+		FileName:     "alert_feedback.json",
+		SaveInterval: 1 * time.Minute,
+		MaxAlerts:    2000,
+	}
+}
+
+// AlertFeedback is a single verdict on an alert.
+type AlertFeedback struct {
+	Verdict FeedbackVerdict `json:"verdict"`
+	By      string          `json:"by,omitempty"`
+	At      time.Time       `json:"at"`
+}
+
+// FeedbackAlert is a notified alert that feedback can be given on.
+type FeedbackAlert struct {
+	ID          string          `json:"id"`
+	Wallet      string          `json:"wallet"`
+	ConditionID string          `json:"condition_id"`
+	MarketTitle string          `json:"market_title"`
+	Outcome     string          `json:"outcome"`
+	Reasons     []string        `json:"reasons"`
+	AlertedAt   time.Time       `json:"alerted_at"`
+	Feedback    []AlertFeedback `json:"feedback,omitempty"`
+}
+
+// hasVerdict reports whether the alert already has the verdict.
+func (a *FeedbackAlert) hasVerdict(verdict FeedbackVerdict) bool {
+	for _, f := range a.Feedback {
+		if f.Verdict == verdict {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMute reports whether the alert led to a wallet or market mute.
+func (a *FeedbackAlert) hasMute() bool {
+	return a.hasVerdict(FeedbackMutedWallet) || a.hasVerdict(FeedbackMutedMarket)
+}
+
+// AlertFeedbackStats counts feedback for a single reason.
+type AlertFeedbackStats struct {
+	Key               string  `json:"key"` // Reason, or "all"
+	Alerts            int     `json:"alerts"`
+	FalsePositives    int     `json:"false_positives"`
+	Muted             int     `json:"muted"` // Alerts whose wallet or market was muted from the alert
+	FalsePositiveRate float64 `json:"false_positive_rate"`
+}
+
+// RecentAlertFeedback is a verdict shown in the dashboard feed.
+type RecentAlertFeedback struct {
+	AlertFeedback
+	AlertID     string   `json:"alert_id"`
+	Wallet      string   `json:"wallet"`
+	MarketTitle string   `json:"market_title"`
+	Reasons     []string `json:"reasons"`
+}
+
+// AlertFeedbackSummary is the feedback report shown on the dashboard.
+type AlertFeedbackSummary struct {
+	Overall  AlertFeedbackStats    `json:"overall"`
+	ByReason []AlertFeedbackStats  `json:"by_reason"`
+	Recent   []RecentAlertFeedback `json:"recent"`
+}
+
+// AlertFeedbackSnapshot is the persisted state format.
+type AlertFeedbackSnapshot struct {
+	Version   int                       `json:"version"`
+	Timestamp time.Time                 `json:"timestamp"`
+	Alerts    map[string]*FeedbackAlert `json:"alerts"` // alertID -> alert
+}
+
+// AlertFeedbackStore indexes notified alerts so buttons on them can be
+// resolved by alert ID, and records the feedback given, so false positive
+// rates can be reported per heuristic.
+type AlertFeedbackStore struct {
+	logger     *zap.Logger
+	gistClient gist.Storage
+	now        func() time.Time
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   AlertFeedbackConfig
+
+	mu     sync.RWMutex
+	alerts map[string]*FeedbackAlert // alertID -> alert
+
+	// Persistence
+	dirty  bool
+	doneCh chan struct{}
+}
+
+// NewAlertFeedbackStore creates an empty feedback store.
+func NewAlertFeedbackStore(logger *zap.Logger, gistClient gist.Storage, config AlertFeedbackConfig) *AlertFeedbackStore {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &AlertFeedbackStore{
+		logger:     logger.Named("alert-feedback"),
+		gistClient: gistClient,
+		config:     config,
+		now:        time.Now,
+		alerts:     make(map[string]*FeedbackAlert),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// IsEnabled returns true if feedback is persisted.
+func (fs *AlertFeedbackStore) IsEnabled() bool {
+	cfg := fs.getConfig()
+	return fs.gistClient != nil && cfg.GistID != ""
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (fs *AlertFeedbackStore) getConfig() AlertFeedbackConfig {
+	fs.configMu.RLock()
+	defer fs.configMu.RUnlock()
+	return fs.config
+}
+
+// UpdateConfig updates the feedback store config.
+func (fs *AlertFeedbackStore) UpdateConfig(cfg AlertFeedbackConfig) {
+	fs.configMu.Lock()
+	defer fs.configMu.Unlock()
+	fs.config = cfg
+}
+
+// RecordAlert indexes a notified alert.
+func (fs *AlertFeedbackStore) RecordAlert(alert notifier.TradeAlert) {
+	if alert.ID == "" {
+		return
+	}
+
+	alertedAt := alert.Timestamp
+	if alertedAt.IsZero() || alertedAt.Unix() <= 0 {
+		alertedAt = fs.now()
+	}
+
+	reasons := make([]string, len(alert.Reasons))
+	for i, r := range alert.Reasons {
+		reasons[i] = string(r)
+	}
+
+	cfg := fs.getConfig()
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if _, exists := fs.alerts[alert.ID]; exists {
+		return
+	}
+	fs.alerts[alert.ID] = &FeedbackAlert{
+		ID:          alert.ID,
+		Wallet:      strings.ToLower(alert.TraderAddress),
+		ConditionID: alert.ConditionID,
+		MarketTitle: alert.MarketTitle,
+		Outcome:     alert.Outcome,
+		Reasons:     reasons,
+		AlertedAt:   alertedAt,
+	}
+	fs.pruneLocked(cfg.MaxAlerts)
+	fs.dirty = true
+}
+
+// pruneLocked drops the oldest alerts beyond maxAlerts (must hold lock).
+func (fs *AlertFeedbackStore) pruneLocked(maxAlerts int) {
+	if maxAlerts <= 0 || len(fs.alerts) <= maxAlerts {
+		return
+	}
+
+	all := make([]*FeedbackAlert, 0, len(fs.alerts))
+	for _, a := range fs.alerts {
+		all = append(all, a)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].AlertedAt.Before(all[j].AlertedAt)
+	})
+	for _, a := range all[:len(all)-maxAlerts] {
+		delete(fs.alerts, a.ID)
+	}
+}
+
+// Alert returns an indexed alert by ID.
+func (fs *AlertFeedbackStore) Alert(alertID string) (FeedbackAlert, bool) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	a, ok := fs.alerts[alertID]
+	if !ok {
+		return FeedbackAlert{}, false
+	}
+	return *a, true
+}
+
+// Record adds a verdict to an alert. Repeated verdicts are ignored. Returns
+// false if the alert is not indexed (never notified, or pruned).
+func (fs *AlertFeedbackStore) Record(alertID string, verdict FeedbackVerdict, by string) (FeedbackAlert, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	a, ok := fs.alerts[alertID]
+	if !ok {
+		return FeedbackAlert{}, false
+	}
+	if !a.hasVerdict(verdict) {
+		a.Feedback = append(a.Feedback, AlertFeedback{Verdict: verdict, By: by, At: fs.now()})
+		fs.dirty = true
+
+		fs.logger.Info("alert feedback",
+			zap.String("alertID", alertID),
+			zap.String("verdict", string(verdict)),
+			zap.Strings("reasons", a.Reasons),
+			zap.String("by", by),
+		)
+	}
+	return *a, true
+}
+
+// Summary computes false positive rates per reason and lists recent feedback.
+func (fs *AlertFeedbackStore) Summary() AlertFeedbackSummary {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	summary := AlertFeedbackSummary{
+		ByReason: []AlertFeedbackStats{},
+		Recent:   []RecentAlertFeedback{},
+	}
+
+	overall := &AlertFeedbackStats{Key: "all"}
+	byReason := make(map[string]*AlertFeedbackStats)
+	add := func(s *AlertFeedbackStats, a *FeedbackAlert) {
+		s.Alerts++
+		if a.hasVerdict(FeedbackFalsePositive) {
+			s.FalsePositives++
+		}
+		if a.hasMute() {
+			s.Muted++
+		}
+	}
+
+	for _, a := range fs.alerts {
+		add(overall, a)
+		for _, reason := range a.Reasons {
+			s, ok := byReason[reason]
+			if !ok {
+				s = &AlertFeedbackStats{Key: reason}
+				byReason[reason] = s
+			}
+			add(s, a)
+		}
+		for _, f := range a.Feedback {
+			summary.Recent = append(summary.Recent, RecentAlertFeedback{
+				AlertFeedback: f,
+				AlertID:       a.ID,
+				Wallet:        a.Wallet,
+				MarketTitle:   a.MarketTitle,
+				Reasons:       a.Reasons,
+			})
+		}
+	}
+
+	finish := func(s *AlertFeedbackStats) AlertFeedbackStats {
+		if s.Alerts > 0 {
+			s.FalsePositiveRate = float64(s.FalsePositives) / float64(s.Alerts)
+		}
+		return *s
+	}
+
+	summary.Overall = finish(overall)
+	for _, s := range byReason {
+		summary.ByReason = append(summary.ByReason, finish(s))
+	}
+	sort.Slice(summary.ByReason, func(i, j int) bool {
+		a, b := summary.ByReason[i], summary.ByReason[j]
+		if a.FalsePositives != b.FalsePositives {
+			return a.FalsePositives > b.FalsePositives
+		}
+		if a.Alerts != b.Alerts {
+			return a.Alerts > b.Alerts
+		}
+		return a.Key < b.Key
+	})
+
+	sort.Slice(summary.Recent, func(i, j int) bool {
+		return summary.Recent[i].At.After(summary.Recent[j].At)
+	})
+	if len(summary.Recent) > maxRecentFeedback {
+		summary.Recent = summary.Recent[:maxRecentFeedback]
+	}
+
+	return summary
+}
+
+// Start begins periodic saving.
+func (fs *AlertFeedbackStore) Start(ctx context.Context) {
+	go fs.periodicSave(ctx)
+}
+
+// Stop gracefully shuts down, saving pending changes.
+func (fs *AlertFeedbackStore) Stop() {
+	close(fs.doneCh)
+}
+
+// periodicSave saves state periodically.
+func (fs *AlertFeedbackStore) periodicSave(ctx context.Context) {
+	interval := fs.getConfig().SaveInterval
+	if interval <= 0 {
+		interval = DefaultAlertFeedbackConfig().SaveInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Final save on shutdown
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = fs.Save(saveCtx)
+			cancel()
+			return
+		case <-fs.doneCh:
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = fs.Save(saveCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			if err := fs.Save(ctx); err != nil {
+				fs.logger.Warn("failed to save alert feedback", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Load loads state from gist.
+func (fs *AlertFeedbackStore) Load(ctx context.Context) error {
+	if !fs.IsEnabled() {
+		return nil
+	}
+
+	cfg := fs.getConfig()
+	content, err := fs.gistClient.Load(ctx, cfg.FileName, cfg.GistID)
+	if err != nil {
+		return fmt.Errorf("load alert feedback: %w", err)
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	var snapshot AlertFeedbackSnapshot
+	if err := json.Unmarshal([]byte(content), &snapshot); err != nil {
+		return fmt.Errorf("unmarshal alert feedback: %w", err)
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for id, a := range snapshot.Alerts {
+		if _, exists := fs.alerts[id]; !exists {
+			fs.alerts[id] = a // Keep alerts indexed before the load finished
+		}
+	}
+	fs.pruneLocked(cfg.MaxAlerts)
+
+	fs.logger.Info("loaded alert feedback",
+		zap.Int("alerts", len(fs.alerts)),
+	)
+
+	return nil
+}
+
+// Save saves state to gist.
+func (fs *AlertFeedbackStore) Save(ctx context.Context) error {
+	if !fs.IsEnabled() {
+		return nil
+	}
+
+	fs.mu.Lock()
+	if !fs.dirty {
+		fs.mu.Unlock()
+		return nil
+	}
+
+	snapshot := AlertFeedbackSnapshot{
+		Version:   1,
+		Timestamp: time.Now(),
+		Alerts:    fs.alerts,
+	}
+
+	// Marshal under the lock since the map is shared
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	fs.dirty = false
+	fs.mu.Unlock()
+
+	if err != nil {
+		fs.mu.Lock()
+		fs.dirty = true
+		fs.mu.Unlock()
+		return fmt.Errorf("marshal alert feedback: %w", err)
+	}
+
+	cfg := fs.getConfig()
+	if err := fs.gistClient.Save(ctx, cfg.FileName, string(data), cfg.GistID); err != nil {
+		fs.mu.Lock()
+		fs.dirty = true
+		fs.mu.Unlock()
+		return fmt.Errorf("save alert feedback: %w", err)
+	}
+
+	fs.logger.Debug("saved alert feedback",
+		zap.Int("alerts", len(snapshot.Alerts)),
+	)
+
+	return nil
+}
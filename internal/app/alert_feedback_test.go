@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"polybot/clients/notifier"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func feedbackTestAlert(id string, reasons ...notifier.AlertReason) notifier.TradeAlert {
+	return notifier.TradeAlert{
+		ID:            id,
+		TraderAddress: "0xABC",
+		ConditionID:   "0xmarket",
+		MarketTitle:   "Will it rain?",
+		Reasons:       reasons,
+		Timestamp:     time.Now(),
+	}
+}
+
+func TestAlertFeedbackStore_RecordAndSummary(t *testing.T) {
+	fs := NewAlertFeedbackStore(zap.NewNop(), nil, DefaultAlertFeedbackConfig())
+	fs.RecordAlert(feedbackTestAlert("a1", notifier.AlertReasonMassiveTrade, notifier.AlertReasonNewWallet))
+	fs.RecordAlert(feedbackTestAlert("a2", notifier.AlertReasonMassiveTrade))
+	fs.RecordAlert(feedbackTestAlert("a3", notifier.AlertReasonHighWinRate))
+
+	alert, ok := fs.Record("a1", FeedbackFalsePositive, "alice")
+	if !ok || alert.Wallet != "0xabc" {
+		t.Fatalf("expected feedback recorded on indexed alert, got %+v", alert)
+	}
+	fs.Record("a1", FeedbackFalsePositive, "bob") // Duplicate verdicts are ignored
+	fs.Record("a3", FeedbackMutedWallet, "alice")
+
+	if _, ok := fs.Record("unknown", FeedbackFalsePositive, "alice"); ok {
+		t.Error("expected feedback on an unknown alert to be rejected")
+	}
+
+	summary := fs.Summary()
+	if summary.Overall.Alerts != 3 || summary.Overall.FalsePositives != 1 || summary.Overall.Muted != 1 {
+		t.Errorf("unexpected overall stats: %+v", summary.Overall)
+	}
+	if len(summary.ByReason) == 0 || summary.ByReason[0].Key != string(notifier.AlertReasonMassiveTrade) {
+		t.Fatalf("expected massive_trade first, got %+v", summary.ByReason)
+	}
+	if got := summary.ByReason[0]; got.Alerts != 2 || got.FalsePositives != 1 || got.FalsePositiveRate != 0.5 {
+		t.Errorf("unexpected massive_trade stats: %+v", got)
+	}
+	if len(summary.Recent) != 2 {
+		t.Errorf("expected 2 recent verdicts, got %+v", summary.Recent)
+	}
+}
+
+func TestAlertFeedbackStore_PrunesOldestAlerts(t *testing.T) {
+	cfg := DefaultAlertFeedbackConfig()
+	cfg.MaxAlerts = 2
+	fs := NewAlertFeedbackStore(zap.NewNop(), nil, cfg)
+
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a1", "a2", "a3"} {
+		alert := feedbackTestAlert(id)
+		alert.Timestamp = base.Add(time.Duration(i) * time.Minute)
+		fs.RecordAlert(alert)
+	}
+
+	if _, ok := fs.Alert("a1"); ok {
+		t.Error("expected oldest alert pruned")
+	}
+	if _, ok := fs.Alert("a3"); !ok {
+		t.Error("expected newest alert kept")
+	}
+}
+
+func TestAlertFeedbackStore_SaveAndLoad(t *testing.T) {
+	gistClient := NewMockGistStorage()
+	cfg := DefaultAlertFeedbackConfig()
+	cfg.GistID = "gist123"
+
+	fs := NewAlertFeedbackStore(zap.NewNop(), gistClient, cfg)
+	fs.RecordAlert(feedbackTestAlert("a1", notifier.AlertReasonContrarianBet))
+	fs.Record("a1", FeedbackFalsePositive, "alice")
+
+	if err := fs.Save(context.Background()); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	loaded := NewAlertFeedbackStore(zap.NewNop(), gistClient, cfg)
+	if err := loaded.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	alert, ok := loaded.Alert("a1")
+	if !ok || len(alert.Feedback) != 1 || alert.Feedback[0].By != "alice" {
+		t.Fatalf("expected feedback to survive a restart, got %+v", alert)
+	}
+	if loaded.Summary().Overall.FalsePositives != 1 {
+		t.Error("expected loaded false positive in summary")
+	}
+}
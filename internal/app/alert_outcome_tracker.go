@@ -0,0 +1,601 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/gist"
+	"polybot/clients/notifier"
+	"polybot/clients/polymarketapi"
+
+	"go.uber.org/zap"
+)
+
+// AlertOutcomeAPIClient defines the API methods needed by AlertOutcomeTracker.
+type AlertOutcomeAPIClient interface {
+	GetMarketByConditionID(ctx context.Context, conditionID string) (*polymarketapi.GammaMarket, error)
+}
+
+// AlertOutcomeTrackerConfig holds configuration for alert outcome tracking.
+type AlertOutcomeTrackerConfig struct {
+	// Persistence
+	GistID       string
+	FileName     string
+	SaveInterval time.Duration
+
+	// Resolution tracking
+	ResolutionCheckInterval time.Duration // How often to check unresolved alerts
+	MaxAlerts               int           // Max alerts to keep (oldest are dropped first)
+}
+
+// DefaultAlertOutcomeTrackerConfig returns sensible defaults.
+func DefaultAlertOutcomeTrackerConfig() AlertOutcomeTrackerConfig {
+	return AlertOutcomeTrackerConfig{
+		FileName:                "alert_outcomes.json",
+		SaveInterval:            5 * time.Minute,
+		ResolutionCheckInterval: 1 * time.Hour,
+		MaxAlerts:               5000,
+	}
+}
+
+// TrackedAlert is a sent alert awaiting (or scored after) market resolution.
+type TrackedAlert struct {
+	ID          string    `json:"id"` // wallet:conditionID:outcome:timestamp
+	Wallet      string    `json:"wallet"`
+	ConditionID string    `json:"condition_id"`
+	MarketTitle string    `json:"market_title"`
+	Outcome     string    `json:"outcome"`
+	Side        string    `json:"side"`
+	Price       float64   `json:"price"`
+	Notional    float64   `json:"notional"`
+	Reasons     []string  `json:"reasons"`
+	AlertedAt   time.Time `json:"alerted_at"`
+
+	// Resolution tracking
+	Resolved       bool      `json:"resolved"`
+	ResolvedAt     time.Time `json:"resolved_at,omitempty"`
+	WinningOutcome string    `json:"winning_outcome,omitempty"`
+	Won            bool      `json:"won,omitempty"`        // Alert backed the winning outcome
+	CopyPnl        float64   `json:"copy_pnl,omitempty"`   // P&L of copying the trade with the same notional
+	CopyStake      float64   `json:"copy_stake,omitempty"` // Capital at risk when copying the trade
+}
+
+// AlertOutcomeStats scores alerts for a single reason or reason combination.
+//
+// Precision is wins over resolved alerts. Hit rate is wins over all alerts,
+// so alerts on markets that have not resolved yet count against it. ROI is the
+// P&L of copying every resolved alert at its alerted price, over the capital risked.
+type AlertOutcomeStats struct {
+	Key       string  `json:"key"` // Reason, or reasons joined with "+"
+	Alerts    int     `json:"alerts"`
+	Resolved  int     `json:"resolved"`
+	Wins      int     `json:"wins"`
+	Losses    int     `json:"losses"`
+	Precision float64 `json:"precision"`
+	HitRate   float64 `json:"hit_rate"`
+	CopyPnl   float64 `json:"copy_pnl"`
+	ROI       float64 `json:"roi"`
+}
+
+// AlertOutcomeSummary is the scoreboard shown on the dashboard.
+type AlertOutcomeSummary struct {
+	Overall      AlertOutcomeStats   `json:"overall"`
+	Pending      int                 `json:"pending"`
+	ByReason     []AlertOutcomeStats `json:"by_reason"`
+	ByCombo      []AlertOutcomeStats `json:"by_combo"`
+	LastResolved time.Time           `json:"last_resolved,omitempty"`
+}
+
+// AlertOutcomeSnapshot is the persisted state format.
+type AlertOutcomeSnapshot struct {
+	Version   int                      `json:"version"`
+	Timestamp time.Time                `json:"timestamp"`
+	Alerts    map[string]*TrackedAlert `json:"alerts"` // alertID -> alert
+}
+
+// AlertOutcomeTracker records sent alerts and scores them once their markets resolve.
+type AlertOutcomeTracker struct {
+	logger     *zap.Logger
+	apiClient  AlertOutcomeAPIClient
+	gistClient gist.Storage
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   AlertOutcomeTrackerConfig
+
+	mu     sync.RWMutex
+	alerts map[string]*TrackedAlert // alertID -> alert
+
+	// Called with the alerts scored when a market resolves
+	resolutionMu sync.RWMutex
+	onResolution func(resolved []TrackedAlert)
+
+	// Persistence
+	dirty  bool
+	doneCh chan struct{}
+}
+
+// NewAlertOutcomeTracker creates a new alert outcome tracker.
+func NewAlertOutcomeTracker(
+	logger *zap.Logger,
+	apiClient AlertOutcomeAPIClient,
+	gistClient gist.Storage,
+	config AlertOutcomeTrackerConfig,
+) *AlertOutcomeTracker {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &AlertOutcomeTracker{
+		logger:     logger.Named("alert-outcomes"),
+		apiClient:  apiClient,
+		gistClient: gistClient,
+		config:     config,
+		alerts:     make(map[string]*TrackedAlert),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// IsEnabled returns true if alert outcomes are persisted.
+func (ot *AlertOutcomeTracker) IsEnabled() bool {
+	cfg := ot.getConfig()
+	return ot.gistClient != nil && cfg.GistID != ""
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (ot *AlertOutcomeTracker) getConfig() AlertOutcomeTrackerConfig {
+	ot.configMu.RLock()
+	defer ot.configMu.RUnlock()
+	return ot.config
+}
+
+// UpdateConfig updates the alert outcome tracker config.
+func (ot *AlertOutcomeTracker) UpdateConfig(cfg AlertOutcomeTrackerConfig) {
+	ot.configMu.Lock()
+	defer ot.configMu.Unlock()
+	ot.config = cfg
+	ot.logger.Info("alert outcome tracker config updated",
+		zap.Duration("resolutionCheckInterval", cfg.ResolutionCheckInterval),
+		zap.Int("maxAlerts", cfg.MaxAlerts),
+	)
+}
+
+// Start begins periodic saving and resolution checking.
+func (ot *AlertOutcomeTracker) Start(ctx context.Context) {
+	go ot.periodicSave(ctx)
+	go ot.runResolutionChecker(ctx)
+}
+
+// Stop gracefully shuts down, saving pending changes.
+func (ot *AlertOutcomeTracker) Stop() {
+	close(ot.doneCh)
+}
+
+// SetResolutionHandler sets a handler called with the alerts scored each
+// time a market resolves.
+func (ot *AlertOutcomeTracker) SetResolutionHandler(handler func(resolved []TrackedAlert)) {
+	ot.resolutionMu.Lock()
+	defer ot.resolutionMu.Unlock()
+	ot.onResolution = handler
+}
+
+// RecordAlert starts tracking a sent alert.
+func (ot *AlertOutcomeTracker) RecordAlert(alert notifier.TradeAlert) {
+	if alert.ConditionID == "" || alert.Outcome == "" {
+		return
+	}
+	// Sent after the market resolved, so there is nothing left to predict
+	for _, r := range alert.Reasons {
+		if r == AlertReasonResolutionConfirmed {
+			return
+		}
+	}
+
+	alertedAt := alert.Timestamp
+	if alertedAt.IsZero() || alertedAt.Unix() <= 0 {
+		alertedAt = time.Now()
+	}
+
+	reasons := make([]string, len(alert.Reasons))
+	for i, r := range alert.Reasons {
+		reasons[i] = string(r)
+	}
+
+	tracked := &TrackedAlert{
+		ID:          fmt.Sprintf("%s:%s:%s:%d", alert.TraderAddress, alert.ConditionID, alert.Outcome, alertedAt.UnixNano()),
+		Wallet:      alert.TraderAddress,
+		ConditionID: alert.ConditionID,
+		MarketTitle: alert.MarketTitle,
+		Outcome:     alert.Outcome,
+		Side:        strings.ToUpper(alert.Side),
+		Price:       alert.Price,
+		Notional:    alert.Notional,
+		Reasons:     reasons,
+		AlertedAt:   alertedAt,
+	}
+
+	cfg := ot.getConfig()
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+	ot.alerts[tracked.ID] = tracked
+	ot.pruneLocked(cfg.MaxAlerts)
+	ot.dirty = true
+}
+
+// pruneLocked drops the oldest alerts beyond maxAlerts (must hold lock).
+func (ot *AlertOutcomeTracker) pruneLocked(maxAlerts int) {
+	if maxAlerts <= 0 || len(ot.alerts) <= maxAlerts {
+		return
+	}
+
+	all := make([]*TrackedAlert, 0, len(ot.alerts))
+	for _, a := range ot.alerts {
+		all = append(all, a)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].AlertedAt.Before(all[j].AlertedAt)
+	})
+	for _, a := range all[:len(all)-maxAlerts] {
+		delete(ot.alerts, a.ID)
+	}
+}
+
+// periodicSave saves state periodically.
+func (ot *AlertOutcomeTracker) periodicSave(ctx context.Context) {
+	interval := ot.getConfig().SaveInterval
+	if interval <= 0 {
+		interval = DefaultAlertOutcomeTrackerConfig().SaveInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Final save on shutdown
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = ot.Save(saveCtx)
+			cancel()
+			return
+		case <-ot.doneCh:
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = ot.Save(saveCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			if err := ot.Save(ctx); err != nil {
+				ot.logger.Warn("failed to save alert outcomes", zap.Error(err))
+			}
+		}
+	}
+}
+
+// runResolutionChecker periodically checks for market resolutions.
+func (ot *AlertOutcomeTracker) runResolutionChecker(ctx context.Context) {
+	interval := ot.getConfig().ResolutionCheckInterval
+	if interval <= 0 {
+		interval = DefaultAlertOutcomeTrackerConfig().ResolutionCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ot.doneCh:
+			return
+		case <-ticker.C:
+			ot.CheckResolutions(ctx)
+		}
+	}
+}
+
+// CheckResolutions checks each market with unresolved alerts and scores
+// the alerts of any market that has closed. Returns the number of alerts resolved.
+func (ot *AlertOutcomeTracker) CheckResolutions(ctx context.Context) int {
+	if ot.apiClient == nil {
+		return 0
+	}
+
+	// Group unresolved alerts by market so each market is fetched once
+	ot.mu.RLock()
+	pendingMarkets := make(map[string]struct{})
+	for _, a := range ot.alerts {
+		if !a.Resolved {
+			pendingMarkets[a.ConditionID] = struct{}{}
+		}
+	}
+	ot.mu.RUnlock()
+
+	resolved := 0
+	for conditionID := range pendingMarkets {
+		if ctx.Err() != nil {
+			break
+		}
+
+		market, err := ot.apiClient.GetMarketByConditionID(ctx, conditionID)
+		if err != nil {
+			ot.logger.Debug("failed to check market resolution",
+				zap.String("conditionId", conditionID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		winningOutcome, _ := market.GetWinningOutcome()
+		if winningOutcome == "" {
+			continue
+		}
+		resolved += ot.resolveMarket(conditionID, winningOutcome)
+	}
+
+	if resolved > 0 {
+		ot.logger.Info("scored resolved alerts",
+			zap.Int("resolved", resolved),
+			zap.Int("markets", len(pendingMarkets)),
+		)
+	}
+	return resolved
+}
+
+// resolveMarket marks every unresolved alert in a market as a win or loss.
+func (ot *AlertOutcomeTracker) resolveMarket(conditionID, winningOutcome string) int {
+	ot.mu.Lock()
+	now := time.Now()
+	var resolved []TrackedAlert
+	for _, a := range ot.alerts {
+		if a.Resolved || a.ConditionID != conditionID {
+			continue
+		}
+
+		won, pnl, stake := scoreAlert(a.Side, a.Price, a.Notional, strings.EqualFold(a.Outcome, winningOutcome))
+		a.Resolved = true
+		a.ResolvedAt = now
+		a.WinningOutcome = winningOutcome
+		a.Won = won
+		a.CopyPnl = pnl
+		a.CopyStake = stake
+		resolved = append(resolved, *a)
+	}
+
+	if len(resolved) > 0 {
+		ot.dirty = true
+	}
+	ot.mu.Unlock()
+
+	ot.resolutionMu.RLock()
+	handler := ot.onResolution
+	ot.resolutionMu.RUnlock()
+	if handler != nil && len(resolved) > 0 {
+		handler(resolved)
+	}
+	return len(resolved)
+}
+
+// scoreAlert scores copying an alerted trade with the same notional at the same price.
+// A buy wins if its outcome won; a sell wins if its outcome lost.
+// Selling at price p is treated as buying the other side at 1-p.
+func scoreAlert(side string, price, notional float64, outcomeWon bool) (won bool, pnl, stake float64) {
+	entry := price
+	won = outcomeWon
+	if strings.EqualFold(side, "SELL") {
+		entry = 1 - price
+		won = !outcomeWon
+	}
+
+	// Prices at the bounds have no upside or no cost to score
+	if entry <= 0 || entry >= 1 || notional <= 0 {
+		return won, 0, 0
+	}
+
+	stake = notional
+	if won {
+		pnl = notional * (1 - entry) / entry
+	} else {
+		pnl = -notional
+	}
+	return won, pnl, stake
+}
+
+// Summary computes the precision scoreboard per reason and per reason combination.
+func (ot *AlertOutcomeTracker) Summary() AlertOutcomeSummary {
+	ot.mu.RLock()
+	defer ot.mu.RUnlock()
+
+	summary := AlertOutcomeSummary{
+		Overall:  AlertOutcomeStats{Key: "all"},
+		ByReason: []AlertOutcomeStats{},
+		ByCombo:  []AlertOutcomeStats{},
+	}
+
+	type accumulator struct {
+		stats AlertOutcomeStats
+		stake float64
+	}
+	overall := &accumulator{stats: summary.Overall}
+	byReason := make(map[string]*accumulator)
+	byCombo := make(map[string]*accumulator)
+
+	add := func(acc *accumulator, a *TrackedAlert) {
+		acc.stats.Alerts++
+		if !a.Resolved {
+			return
+		}
+		acc.stats.Resolved++
+		if a.Won {
+			acc.stats.Wins++
+		} else {
+			acc.stats.Losses++
+		}
+		acc.stats.CopyPnl += a.CopyPnl
+		acc.stake += a.CopyStake
+	}
+	get := func(m map[string]*accumulator, key string) *accumulator {
+		acc, ok := m[key]
+		if !ok {
+			acc = &accumulator{stats: AlertOutcomeStats{Key: key}}
+			m[key] = acc
+		}
+		return acc
+	}
+
+	for _, a := range ot.alerts {
+		add(overall, a)
+		if !a.Resolved {
+			summary.Pending++
+		} else if a.ResolvedAt.After(summary.LastResolved) {
+			summary.LastResolved = a.ResolvedAt
+		}
+
+		for _, reason := range a.Reasons {
+			add(get(byReason, reason), a)
+		}
+		add(get(byCombo, reasonComboKey(a.Reasons)), a)
+	}
+
+	finish := func(acc *accumulator) AlertOutcomeStats {
+		s := acc.stats
+		if s.Resolved > 0 {
+			s.Precision = float64(s.Wins) / float64(s.Resolved)
+		}
+		if s.Alerts > 0 {
+			s.HitRate = float64(s.Wins) / float64(s.Alerts)
+		}
+		if acc.stake > 0 {
+			s.ROI = s.CopyPnl / acc.stake
+		}
+		return s
+	}
+
+	summary.Overall = finish(overall)
+	for _, acc := range byReason {
+		summary.ByReason = append(summary.ByReason, finish(acc))
+	}
+	for _, acc := range byCombo {
+		summary.ByCombo = append(summary.ByCombo, finish(acc))
+	}
+	sortOutcomeStats(summary.ByReason)
+	sortOutcomeStats(summary.ByCombo)
+
+	return summary
+}
+
+// reasonComboKey returns a stable key for a set of reasons.
+func reasonComboKey(reasons []string) string {
+	sorted := make([]string, len(reasons))
+	copy(sorted, reasons)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "+")
+}
+
+// sortOutcomeStats sorts by resolved count, then alert count, then key.
+func sortOutcomeStats(stats []AlertOutcomeStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Resolved != stats[j].Resolved {
+			return stats[i].Resolved > stats[j].Resolved
+		}
+		if stats[i].Alerts != stats[j].Alerts {
+			return stats[i].Alerts > stats[j].Alerts
+		}
+		return stats[i].Key < stats[j].Key
+	})
+}
+
+// Stats returns the number of tracked and pending alerts.
+func (ot *AlertOutcomeTracker) Stats() (tracked, pending int) {
+	ot.mu.RLock()
+	defer ot.mu.RUnlock()
+
+	for _, a := range ot.alerts {
+		if !a.Resolved {
+			pending++
+		}
+	}
+	return len(ot.alerts), pending
+}
+
+// Load loads state from gist.
+func (ot *AlertOutcomeTracker) Load(ctx context.Context) error {
+	if !ot.IsEnabled() {
+		return nil
+	}
+
+	cfg := ot.getConfig()
+	content, err := ot.gistClient.Load(ctx, cfg.FileName, cfg.GistID)
+	if err != nil {
+		return fmt.Errorf("load alert outcomes: %w", err)
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	var snapshot AlertOutcomeSnapshot
+	if err := json.Unmarshal([]byte(content), &snapshot); err != nil {
+		return fmt.Errorf("unmarshal alert outcomes: %w", err)
+	}
+
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	if snapshot.Alerts != nil {
+		ot.alerts = snapshot.Alerts
+	}
+
+	ot.logger.Info("loaded alert outcomes",
+		zap.Int("alerts", len(ot.alerts)),
+	)
+
+	return nil
+}
+
+// Save saves state to gist.
+func (ot *AlertOutcomeTracker) Save(ctx context.Context) error {
+	if !ot.IsEnabled() {
+		return nil
+	}
+
+	ot.mu.Lock()
+	if !ot.dirty {
+		ot.mu.Unlock()
+		return nil
+	}
+
+	snapshot := AlertOutcomeSnapshot{
+		Version:   1,
+		Timestamp: time.Now(),
+		Alerts:    ot.alerts,
+	}
+
+	// Marshal under the lock since the map is shared
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	ot.dirty = false
+	ot.mu.Unlock()
+
+	if err != nil {
+		ot.mu.Lock()
+		ot.dirty = true
+		ot.mu.Unlock()
+		return fmt.Errorf("marshal alert outcomes: %w", err)
+	}
+
+	cfg := ot.getConfig()
+	if err := ot.gistClient.Save(ctx, cfg.FileName, string(data), cfg.GistID); err != nil {
+		ot.mu.Lock()
+		ot.dirty = true
+		ot.mu.Unlock()
+		return fmt.Errorf("save alert outcomes: %w", err)
+	}
+
+	ot.logger.Debug("saved alert outcomes",
+		zap.Int("alerts", len(snapshot.Alerts)),
+	)
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math"
+	"polybot/clients/notifier"
+	"polybot/clients/polymarketapi"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// mockAlertOutcomeAPIClient implements AlertOutcomeAPIClient for testing.
+type mockAlertOutcomeAPIClient struct {
+	markets map[string]*polymarketapi.GammaMarket
+	calls   int
+}
+
+func (m *mockAlertOutcomeAPIClient) GetMarketByConditionID(ctx context.Context, conditionID string) (*polymarketapi.GammaMarket, error) {
+	m.calls++
+	market, ok := m.markets[conditionID]
+	if !ok {
+		return nil, errors.New("market not found")
+	}
+	return market, nil
+}
+
+func newTestOutcomeAlert(wallet, conditionID, outcome, side string, price, notional float64, reasons ...AlertReason) notifier.TradeAlert {
+	return notifier.TradeAlert{
+		TraderAddress: wallet,
+		ConditionID:   conditionID,
+		Outcome:       outcome,
+		Side:          side,
+		Price:         price,
+		Notional:      notional,
+		Reasons:       reasons,
+		Timestamp:     time.Now(),
+	}
+}
+
+func TestDefaultAlertOutcomeTrackerConfig(t *testing.T) {
+	cfg := DefaultAlertOutcomeTrackerConfig()
+
+	if cfg.FileName != "alert_outcomes.json" {
+		t.Errorf("expected filename alert_outcomes.json, got %s", cfg.FileName)
+	}
+	if cfg.SaveInterval != 5*time.Minute {
+		t.Errorf("expected save interval 5m, got %v", cfg.SaveInterval)
+	}
+	if cfg.ResolutionCheckInterval != 1*time.Hour {
+		t.Errorf("expected resolution check interval 1h, got %v", cfg.ResolutionCheckInterval)
+	}
+	if cfg.MaxAlerts != 5000 {
+		t.Errorf("expected max alerts 5000, got %d", cfg.MaxAlerts)
+	}
+}
+
+func TestAlertOutcomeTracker_RecordAlert(t *testing.T) {
+	tracker := NewAlertOutcomeTracker(zap.NewNop(), nil, nil, DefaultAlertOutcomeTrackerConfig())
+
+	tracker.RecordAlert(newTestOutcomeAlert("0xabc", "cond1", "Yes", "buy", 0.2, 1000, AlertReasonNewWallet))
+	// Alerts without a market or outcome cannot be scored
+	tracker.RecordAlert(newTestOutcomeAlert("0xabc", "", "Yes", "BUY", 0.2, 1000, AlertReasonNewWallet))
+	tracker.RecordAlert(newTestOutcomeAlert("0xabc", "cond1", "", "BUY", 0.2, 1000, AlertReasonNewWallet))
+
+	tracked, pending := tracker.Stats()
+	if tracked != 1 || pending != 1 {
+		t.Errorf("expected 1 tracked and 1 pending alert, got %d and %d", tracked, pending)
+	}
+	for _, a := range tracker.alerts {
+		if a.Side != "BUY" {
+			t.Errorf("expected normalized side BUY, got %s", a.Side)
+		}
+	}
+}
+
+func TestAlertOutcomeTracker_PrunesOldest(t *testing.T) {
+	cfg := DefaultAlertOutcomeTrackerConfig()
+	cfg.MaxAlerts = 2
+	tracker := NewAlertOutcomeTracker(zap.NewNop(), nil, nil, cfg)
+
+	base := time.Now()
+	for i, cond := range []string{"old", "mid", "new"} {
+		alert := newTestOutcomeAlert("0xabc", cond, "Yes", "BUY", 0.5, 100, AlertReasonNewWallet)
+		alert.Timestamp = base.Add(time.Duration(i) * time.Minute)
+		tracker.RecordAlert(alert)
+	}
+
+	if len(tracker.alerts) != 2 {
+		t.Fatalf("expected 2 alerts after pruning, got %d", len(tracker.alerts))
+	}
+	for _, a := range tracker.alerts {
+		if a.ConditionID == "old" {
+			t.Error("expected the oldest alert to be pruned")
+		}
+	}
+}
+
+func TestAlertOutcomeTracker_CheckResolutions(t *testing.T) {
+	api := &mockAlertOutcomeAPIClient{markets: map[string]*polymarketapi.GammaMarket{
+		"resolved": {Closed: true, WinningOutcome: "Yes"},
+		"open":     {Closed: false},
+	}}
+	tracker := NewAlertOutcomeTracker(zap.NewNop(), api, nil, DefaultAlertOutcomeTrackerConfig())
+
+	tracker.RecordAlert(newTestOutcomeAlert("0xa", "resolved", "Yes", "BUY", 0.25, 100, AlertReasonNewWallet))
+	tracker.RecordAlert(newTestOutcomeAlert("0xb", "resolved", "No", "BUY", 0.75, 100, AlertReasonNewWallet, AlertReasonHighWinRate))
+	tracker.RecordAlert(newTestOutcomeAlert("0xc", "open", "Yes", "BUY", 0.5, 100, AlertReasonNewWallet))
+
+	if resolved := tracker.CheckResolutions(context.Background()); resolved != 2 {
+		t.Fatalf("expected 2 resolved alerts, got %d", resolved)
+	}
+	if api.calls != 2 {
+		t.Errorf("expected one lookup per market, got %d", api.calls)
+	}
+
+	// Already resolved alerts are not checked again
+	tracker.CheckResolutions(context.Background())
+	if api.calls != 3 {
+		t.Errorf("expected only the open market to be rechecked, got %d calls", api.calls)
+	}
+
+	summary := tracker.Summary()
+	if summary.Pending != 1 {
+		t.Errorf("expected 1 pending alert, got %d", summary.Pending)
+	}
+	overall := summary.Overall
+	if overall.Alerts != 3 || overall.Resolved != 2 || overall.Wins != 1 || overall.Losses != 1 {
+		t.Errorf("unexpected overall stats: %+v", overall)
+	}
+	if overall.Precision != 0.5 {
+		t.Errorf("expected precision 0.5, got %f", overall.Precision)
+	}
+	if math.Abs(overall.HitRate-1.0/3.0) > 1e-9 {
+		t.Errorf("expected hit rate 1/3, got %f", overall.HitRate)
+	}
+	// Win pays 100 * 0.75/0.25 = 300, loss costs 100
+	if math.Abs(overall.CopyPnl-200) > 1e-9 || math.Abs(overall.ROI-1.0) > 1e-9 {
+		t.Errorf("expected copy pnl 200 and ROI 1.0, got %f and %f", overall.CopyPnl, overall.ROI)
+	}
+
+	byReason := make(map[string]AlertOutcomeStats)
+	for _, s := range summary.ByReason {
+		byReason[s.Key] = s
+	}
+	if s := byReason[string(AlertReasonHighWinRate)]; s.Resolved != 1 || s.Wins != 0 {
+		t.Errorf("unexpected high win rate stats: %+v", s)
+	}
+	if s := byReason[string(AlertReasonNewWallet)]; s.Alerts != 3 || s.Wins != 1 {
+		t.Errorf("unexpected new wallet stats: %+v", s)
+	}
+
+	combos := make(map[string]AlertOutcomeStats)
+	for _, s := range summary.ByCombo {
+		combos[s.Key] = s
+	}
+	comboKey := reasonComboKey([]string{string(AlertReasonNewWallet), string(AlertReasonHighWinRate)})
+	if s, ok := combos[comboKey]; !ok || s.Losses != 1 {
+		t.Errorf("expected combo %s with 1 loss, got %+v", comboKey, combos)
+	}
+}
+
+func TestAlertOutcomeTracker_ResolutionHandler(t *testing.T) {
+	api := &mockAlertOutcomeAPIClient{markets: map[string]*polymarketapi.GammaMarket{
+		"resolved": {Closed: true, WinningOutcome: "Yes"},
+	}}
+	tracker := NewAlertOutcomeTracker(zap.NewNop(), api, nil, DefaultAlertOutcomeTrackerConfig())
+
+	var got []TrackedAlert
+	tracker.SetResolutionHandler(func(resolved []TrackedAlert) {
+		got = append(got, resolved...)
+	})
+	tracker.RecordAlert(newTestOutcomeAlert("0xa", "resolved", "Yes", "BUY", 0.25, 100, AlertReasonNewWallet))
+
+	tracker.CheckResolutions(context.Background())
+	if len(got) != 1 || !got[0].Won || got[0].WinningOutcome != "Yes" {
+		t.Fatalf("expected the scored alert passed to the handler, got %+v", got)
+	}
+
+	// Already resolved alerts are not passed again
+	tracker.CheckResolutions(context.Background())
+	if len(got) != 1 {
+		t.Errorf("expected no repeat resolution, got %d alerts", len(got))
+	}
+}
+
+func TestScoreAlert(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       string
+		price      float64
+		outcomeWon bool
+		wantWon    bool
+		wantPnl    float64
+		wantStake  float64
+	}{
+		{"buy winner", "BUY", 0.25, true, true, 300, 100},
+		{"buy loser", "BUY", 0.25, false, false, -100, 100},
+		{"sell loser", "SELL", 0.75, false, true, 300, 100},
+		{"sell winner", "SELL", 0.75, true, false, -100, 100},
+		{"price at bound", "BUY", 1.0, true, true, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			won, pnl, stake := scoreAlert(tt.side, tt.price, 100, tt.outcomeWon)
+			if won != tt.wantWon || math.Abs(pnl-tt.wantPnl) > 1e-9 || stake != tt.wantStake {
+				t.Errorf("expected (%v, %f, %f), got (%v, %f, %f)", tt.wantWon, tt.wantPnl, tt.wantStake, won, pnl, stake)
+			}
+		})
+	}
+}
+
+func TestReasonComboKey(t *testing.T) {
+	a := reasonComboKey([]string{"new_wallet", "high_win_rate"})
+	b := reasonComboKey([]string{"high_win_rate", "new_wallet"})
+	if a != b || a != "high_win_rate+new_wallet" {
+		t.Errorf("expected stable combo key, got %s and %s", a, b)
+	}
+}
+
+func TestAlertOutcomeTracker_SaveAndLoad(t *testing.T) {
+	gistClient := NewMockGistStorage()
+	cfg := DefaultAlertOutcomeTrackerConfig()
+	cfg.GistID = "gist123"
+
+	api := &mockAlertOutcomeAPIClient{markets: map[string]*polymarketapi.GammaMarket{
+		"cond1": {Closed: true, WinningOutcome: "Yes"},
+	}}
+	tracker := NewAlertOutcomeTracker(zap.NewNop(), api, gistClient, cfg)
+	if !tracker.IsEnabled() {
+		t.Fatal("expected tracker to be enabled with gist configured")
+	}
+	tracker.RecordAlert(newTestOutcomeAlert("0xa", "cond1", "Yes", "BUY", 0.5, 100, AlertReasonNewWallet))
+	tracker.RecordAlert(newTestOutcomeAlert("0xb", "cond2", "Yes", "BUY", 0.5, 100, AlertReasonNewWallet))
+	tracker.CheckResolutions(context.Background())
+
+	if err := tracker.Save(context.Background()); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	if gistClient.GetContent(cfg.FileName) == "" {
+		t.Fatal("expected alert outcomes to be saved")
+	}
+
+	loaded := NewAlertOutcomeTracker(zap.NewNop(), api, gistClient, cfg)
+	if err := loaded.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	tracked, pending := loaded.Stats()
+	if tracked != 2 || pending != 1 {
+		t.Errorf("expected 2 tracked and 1 pending after load, got %d and %d", tracked, pending)
+	}
+	if summary := loaded.Summary(); summary.Overall.Wins != 1 {
+		t.Errorf("expected resolved win to survive reload, got %+v", summary.Overall)
+	}
+}
+
+func TestAlertOutcomeTracker_SaveError(t *testing.T) {
+	gistClient := NewMockGistStorage()
+	gistClient.SetSaveError(errors.New("save failed"))
+	cfg := DefaultAlertOutcomeTrackerConfig()
+	cfg.GistID = "gist123"
+
+	tracker := NewAlertOutcomeTracker(zap.NewNop(), nil, gistClient, cfg)
+	tracker.RecordAlert(newTestOutcomeAlert("0xa", "cond1", "Yes", "BUY", 0.5, 100, AlertReasonNewWallet))
+
+	if err := tracker.Save(context.Background()); err == nil {
+		t.Fatal("expected save error")
+	}
+	if !tracker.dirty {
+		t.Error("expected tracker to stay dirty after a failed save")
+	}
+}
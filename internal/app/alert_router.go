@@ -0,0 +1,288 @@
+package app
+
+import (
+	"strings"
+	"sync"
+
+	"polybot/clients/notifier"
+	"polybot/config"
+
+	"go.uber.org/zap"
+)
+
+// RouteNotifierFactory creates the delivery queue for a routing destination.
+type RouteNotifierFactory func(dest config.RouteDestination) (*notifier.QueuedNotifier, error)
+
+// RoutingStats summarizes alert routing.
+type RoutingStats struct {
+	Rules        int            `json:"rules"`
+	Destinations int            `json:"destinations"`
+	Routed       int64          `json:"routed"`    // Alerts that matched at least one rule
+	Unmatched    int64          `json:"unmatched"` // Alerts sent to the default channels only
+	ByRule       map[string]int `json:"by_rule"`   // Matches per rule name
+}
+
+// AlertRouter sends each alert to the destinations of the routing rules it
+// matches, falling back to the default channels when no rule matches.
+// Implements notifier.Notifier interface.
+type AlertRouter struct {
+	logger     *zap.Logger
+	fallback   notifier.Notifier // Default channels (may be nil)
+	factory    RouteNotifierFactory
+	categories func(conditionID string) []string
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   config.RoutingConfig
+
+	mu     sync.Mutex
+	queues map[string]*notifier.QueuedNotifier // channel:target -> queue
+	failed map[string]bool                     // channel:target that could not be created
+
+	statsMu   sync.Mutex
+	routed    int64
+	unmatched int64
+	byRule    map[string]int
+}
+
+// NewAlertRouter creates a router in front of the default notifier.
+func NewAlertRouter(logger *zap.Logger, cfg config.RoutingConfig, fallback notifier.Notifier, factory RouteNotifierFactory) *AlertRouter {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &AlertRouter{
+		logger:   logger.Named("alert-router"),
+		fallback: fallback,
+		factory:  factory,
+		config:   cfg,
+		queues:   make(map[string]*notifier.QueuedNotifier),
+		failed:   make(map[string]bool),
+		byRule:   make(map[string]int),
+	}
+}
+
+// SetCategoryLookup sets how market categories are resolved for category rules.
+func (ar *AlertRouter) SetCategoryLookup(lookup func(conditionID string) []string) {
+	ar.categories = lookup
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (ar *AlertRouter) getConfig() config.RoutingConfig {
+	ar.configMu.RLock()
+	defer ar.configMu.RUnlock()
+	return ar.config
+}
+
+// UpdateConfig updates the routing rules. Destinations whose queue failed to
+// be created are retried.
+func (ar *AlertRouter) UpdateConfig(cfg config.RoutingConfig) {
+	ar.configMu.Lock()
+	ar.config = cfg
+	ar.configMu.Unlock()
+
+	ar.mu.Lock()
+	ar.failed = make(map[string]bool)
+	ar.mu.Unlock()
+}
+
+// SendTradeAlert routes the alert. Implements notifier.Notifier interface.
+func (ar *AlertRouter) SendTradeAlert(alert notifier.TradeAlert) {
+	cfg := ar.getConfig()
+
+	var categories []string
+	if ar.categories != nil && alert.ConditionID != "" {
+		categories = ar.categories(alert.ConditionID)
+	}
+
+	destinations := make(map[string]config.RouteDestination, len(cfg.Destinations))
+	for _, d := range cfg.Destinations {
+		destinations[d.Name] = d
+	}
+
+	// Collect destinations across all matching rules, deduplicated by target
+	var matched []string
+	includeDefault := false
+	targets := make(map[string]config.RouteDestination)
+	for _, rule := range cfg.Rules {
+		if !ruleMatches(rule, alert, categories) {
+			continue
+		}
+		matched = append(matched, rule.Name)
+		if rule.IncludeDefault {
+			includeDefault = true
+		}
+		for _, name := range rule.Destinations {
+			if d, ok := destinations[name]; ok {
+				targets[routeKey(d)] = d
+			}
+		}
+	}
+
+	ar.statsMu.Lock()
+	if len(matched) > 0 {
+		ar.routed++
+		for _, name := range matched {
+			ar.byRule[name]++
+		}
+	} else {
+		ar.unmatched++
+	}
+	ar.statsMu.Unlock()
+
+	sent := 0
+	for key, d := range targets {
+		if q := ar.queue(key, d); q != nil {
+			q.SendTradeAlert(alert)
+			sent++
+		}
+	}
+
+	// Never drop an alert because its destinations are unavailable
+	if (sent == 0 || includeDefault) && ar.fallback != nil {
+		ar.fallback.SendTradeAlert(alert)
+	}
+}
+
+// queue returns the delivery queue for a destination, creating it on first use.
+func (ar *AlertRouter) queue(key string, dest config.RouteDestination) *notifier.QueuedNotifier {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if q, ok := ar.queues[key]; ok {
+		return q
+	}
+	if ar.failed[key] || ar.factory == nil {
+		return nil
+	}
+
+	q, err := ar.factory(dest)
+	if err != nil {
+		ar.failed[key] = true
+		ar.logger.Warn("cannot deliver to routing destination",
+			zap.String("destination", dest.Name),
+			zap.String("channel", dest.Channel),
+			zap.Error(err),
+		)
+		return nil
+	}
+	ar.queues[key] = q
+	return q
+}
+
+// Queues returns the delivery queues created for routing destinations.
+func (ar *AlertRouter) Queues() []*notifier.QueuedNotifier {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	queues := make([]*notifier.QueuedNotifier, 0, len(ar.queues))
+	for _, q := range ar.queues {
+		queues = append(queues, q)
+	}
+	return queues
+}
+
+// Stats returns routing counters.
+func (ar *AlertRouter) Stats() RoutingStats {
+	cfg := ar.getConfig()
+
+	ar.statsMu.Lock()
+	defer ar.statsMu.Unlock()
+
+	byRule := make(map[string]int, len(ar.byRule))
+	for name, count := range ar.byRule {
+		byRule[name] = count
+	}
+	return RoutingStats{
+		Rules:        len(cfg.Rules),
+		Destinations: len(cfg.Destinations),
+		Routed:       ar.routed,
+		Unmatched:    ar.unmatched,
+		ByRule:       byRule,
+	}
+}
+
+// Close closes the destination queues (unsent alerts are dead-lettered) and
+// the default notifier. Implements notifier.Notifier interface.
+func (ar *AlertRouter) Close() error {
+	var lastErr error
+	for _, q := range ar.Queues() {
+		if err := q.Close(); err != nil {
+			lastErr = err
+		}
+	}
+	if ar.fallback != nil {
+		if err := ar.fallback.Close(); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// routeKey identifies a destination's delivery target.
+func routeKey(d config.RouteDestination) string {
+	return d.Channel + ":" + d.Target
+}
+
+// ruleMatches reports whether an alert satisfies every criterion of a rule.
+func ruleMatches(rule config.RoutingRule, alert notifier.TradeAlert, categories []string) bool {
+	if rule.Disabled {
+		return false
+	}
+
+	if len(rule.Reasons) > 0 {
+		has := make(map[string]bool, len(alert.Reasons))
+		for _, r := range alert.Reasons {
+			has[string(r)] = true
+		}
+		hits := 0
+		for _, r := range rule.Reasons {
+			if has[r] {
+				hits++
+			}
+		}
+		if hits == 0 || (rule.AllReasons && hits < len(rule.Reasons)) {
+			return false
+		}
+	}
+
+	if rule.MinNotional > 0 && alert.Notional < rule.MinNotional {
+		return false
+	}
+	if rule.MaxNotional > 0 && alert.Notional > rule.MaxNotional {
+		return false
+	}
+	if rule.MinPrice > 0 && alert.Price < rule.MinPrice {
+		return false
+	}
+	if rule.MaxPrice > 0 && alert.Price > rule.MaxPrice {
+		return false
+	}
+
+	if len(rule.Categories) > 0 && !containsFold(rule.Categories, categories...) {
+		return false
+	}
+	if len(rule.ConditionIDs) > 0 && !containsFold(rule.ConditionIDs, alert.ConditionID) {
+		return false
+	}
+	if len(rule.Wallets) > 0 && !containsFold(rule.Wallets, alert.TraderAddress) {
+		return false
+	}
+
+	return true
+}
+
+// containsFold reports whether any value appears in list, ignoring case.
+func containsFold(list []string, values ...string) bool {
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		for _, item := range list {
+			if strings.EqualFold(strings.TrimSpace(item), v) {
+				return true
+			}
+		}
+	}
+	return false
+}
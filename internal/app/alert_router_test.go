@@ -0,0 +1,223 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"polybot/clients/notifier"
+	"polybot/config"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// routeCapture records deliveries per routing destination.
+type routeCapture struct {
+	mu        sync.Mutex
+	delivered map[string][]notifier.TradeAlert
+	created   map[string]int
+	fail      map[string]bool
+	done      chan string
+}
+
+func newRouteCapture() *routeCapture {
+	return &routeCapture{
+		delivered: make(map[string][]notifier.TradeAlert),
+		created:   make(map[string]int),
+		fail:      make(map[string]bool),
+		done:      make(chan string, 16),
+	}
+}
+
+func (rc *routeCapture) factory(dest config.RouteDestination) (*notifier.QueuedNotifier, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.created[dest.Name]++
+	if rc.fail[dest.Name] {
+		return nil, errors.New("channel not configured")
+	}
+	inner := &routeDelivery{name: dest.Name, capture: rc}
+	return notifier.NewQueuedNotifier(zap.NewNop(), inner, notifier.QueueConfig{MaxAttempts: 1}), nil
+}
+
+// wait blocks until n deliveries have been recorded.
+func (rc *routeCapture) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-rc.done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for delivery %d of %d", i+1, n)
+		}
+	}
+}
+
+func (rc *routeCapture) count(name string) int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.delivered[name])
+}
+
+// routeDelivery is a notifier.DeliveryNotifier that reports to a routeCapture.
+type routeDelivery struct {
+	name    string
+	capture *routeCapture
+}
+
+func (d *routeDelivery) Name() string                             { return d.name }
+func (d *routeDelivery) SendTradeAlert(alert notifier.TradeAlert) {}
+func (d *routeDelivery) Close() error                             { return nil }
+
+func (d *routeDelivery) DeliverTradeAlert(ctx context.Context, alert notifier.TradeAlert) error {
+	d.capture.mu.Lock()
+	d.capture.delivered[d.name] = append(d.capture.delivered[d.name], alert)
+	d.capture.mu.Unlock()
+	d.capture.done <- d.name
+	return nil
+}
+
+func testRoutingConfig() config.RoutingConfig {
+	return config.RoutingConfig{
+		Destinations: []config.RouteDestination{
+			{Name: "whales", Channel: config.RouteChannelDiscord, Target: "111"},
+			{Name: "hedges", Channel: config.RouteChannelTelegram, Target: "-222"},
+			{Name: "hedges-alias", Channel: config.RouteChannelTelegram, Target: "-222"},
+		},
+		Rules: []config.RoutingRule{
+			{Name: "big", MinNotional: 10000, Destinations: []string{"whales"}},
+			{Name: "hedge", Reasons: []string{"hedge_removal"}, Destinations: []string{"hedges", "hedges-alias"}, IncludeDefault: true},
+		},
+	}
+}
+
+func TestAlertRouter_UnmatchedGoesToFallback(t *testing.T) {
+	fallback := &captureNotifier{}
+	capture := newRouteCapture()
+	router := NewAlertRouter(zap.NewNop(), testRoutingConfig(), fallback, capture.factory)
+	defer router.Close()
+
+	router.SendTradeAlert(notifier.TradeAlert{Notional: 500, Reasons: []AlertReason{AlertReasonNewWallet}})
+
+	if len(fallback.Alerts()) != 1 {
+		t.Errorf("expected unmatched alert on default channels, got %d", len(fallback.Alerts()))
+	}
+	if len(router.Queues()) != 0 {
+		t.Error("expected no destination queues to be created")
+	}
+	if stats := router.Stats(); stats.Unmatched != 1 || stats.Routed != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestAlertRouter_MatchedRuleReplacesFallback(t *testing.T) {
+	fallback := &captureNotifier{}
+	capture := newRouteCapture()
+	router := NewAlertRouter(zap.NewNop(), testRoutingConfig(), fallback, capture.factory)
+	defer router.Close()
+
+	router.SendTradeAlert(notifier.TradeAlert{Notional: 25000})
+	capture.wait(t, 1)
+
+	if capture.count("whales") != 1 {
+		t.Errorf("expected alert on whales destination, got %d", capture.count("whales"))
+	}
+	if len(fallback.Alerts()) != 0 {
+		t.Error("expected routed alert to skip default channels")
+	}
+}
+
+func TestAlertRouter_IncludeDefaultAndDedupe(t *testing.T) {
+	fallback := &captureNotifier{}
+	capture := newRouteCapture()
+	router := NewAlertRouter(zap.NewNop(), testRoutingConfig(), fallback, capture.factory)
+	defer router.Close()
+
+	router.SendTradeAlert(notifier.TradeAlert{Notional: 50000, Reasons: []AlertReason{AlertReasonHedgeRemoval}})
+	capture.wait(t, 2)
+
+	if capture.count("whales") != 1 {
+		t.Errorf("expected alert on whales destination, got %d", capture.count("whales"))
+	}
+	// Destinations sharing a target get the alert once
+	if got := capture.count("hedges") + capture.count("hedges-alias"); got != 1 {
+		t.Errorf("expected one delivery to the shared telegram chat, got %d", got)
+	}
+	if len(fallback.Alerts()) != 1 {
+		t.Error("expected include_default rule to also notify default channels")
+	}
+
+	stats := router.Stats()
+	if stats.Routed != 1 || stats.ByRule["big"] != 1 || stats.ByRule["hedge"] != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestAlertRouter_FailedDestinationFallsBack(t *testing.T) {
+	fallback := &captureNotifier{}
+	capture := newRouteCapture()
+	capture.fail["whales"] = true
+	router := NewAlertRouter(zap.NewNop(), testRoutingConfig(), fallback, capture.factory)
+	defer router.Close()
+
+	router.SendTradeAlert(notifier.TradeAlert{Notional: 25000})
+	router.SendTradeAlert(notifier.TradeAlert{Notional: 25000})
+
+	if len(fallback.Alerts()) != 2 {
+		t.Errorf("expected alerts for unavailable destination on default channels, got %d", len(fallback.Alerts()))
+	}
+	if capture.created["whales"] != 1 {
+		t.Errorf("expected failed destination to be cached, created %d times", capture.created["whales"])
+	}
+
+	// Config updates retry failed destinations
+	capture.fail["whales"] = false
+	router.UpdateConfig(testRoutingConfig())
+	router.SendTradeAlert(notifier.TradeAlert{Notional: 25000})
+	capture.wait(t, 1)
+	if capture.count("whales") != 1 {
+		t.Errorf("expected delivery after config update, got %d", capture.count("whales"))
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	alert := notifier.TradeAlert{
+		TraderAddress: "0xABC",
+		ConditionID:   "0xcond",
+		Notional:      5000,
+		Price:         0.2,
+		Reasons:       []AlertReason{AlertReasonNewWallet, AlertReasonExtremeBet},
+	}
+	categories := []string{"politics", "elections"}
+
+	tests := []struct {
+		name string
+		rule config.RoutingRule
+		want bool
+	}{
+		{"empty rule matches everything", config.RoutingRule{}, true},
+		{"disabled", config.RoutingRule{Disabled: true}, false},
+		{"any reason", config.RoutingRule{Reasons: []string{"extreme_bet", "hedge_removal"}}, true},
+		{"no reason", config.RoutingRule{Reasons: []string{"hedge_removal"}}, false},
+		{"all reasons", config.RoutingRule{Reasons: []string{"new_wallet", "extreme_bet"}, AllReasons: true}, true},
+		{"all reasons missing one", config.RoutingRule{Reasons: []string{"new_wallet", "hedge_removal"}, AllReasons: true}, false},
+		{"notional in range", config.RoutingRule{MinNotional: 1000, MaxNotional: 10000}, true},
+		{"notional below min", config.RoutingRule{MinNotional: 10000}, false},
+		{"notional above max", config.RoutingRule{MaxNotional: 1000}, false},
+		{"price in range", config.RoutingRule{MinPrice: 0.1, MaxPrice: 0.3}, true},
+		{"price above max", config.RoutingRule{MaxPrice: 0.1}, false},
+		{"category", config.RoutingRule{Categories: []string{"Politics"}}, true},
+		{"other category", config.RoutingRule{Categories: []string{"sports"}}, false},
+		{"condition id", config.RoutingRule{ConditionIDs: []string{"0xCOND"}}, true},
+		{"wallet", config.RoutingRule{Wallets: []string{"0xabc"}}, true},
+		{"other wallet", config.RoutingRule{Wallets: []string{"0xdef"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleMatches(tt.rule, alert, categories); got != tt.want {
+				t.Errorf("ruleMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// suppressionFlushInterval is how often closed windows are checked for updates to send.
+const suppressionFlushInterval = 15 * time.Second
+
+// AlertSuppressorConfig holds configuration for alert suppression.
+type AlertSuppressorConfig struct {
+	Enabled         bool
+	Cooldown        time.Duration                 // Default window per wallet, market and side
+	ReasonCooldowns map[AlertReason]time.Duration // Per-reason overrides; the shortest applies
+}
+
+// DefaultAlertSuppressorConfig returns sensible defaults.
+func DefaultAlertSuppressorConfig() AlertSuppressorConfig {
+	return AlertSuppressorConfig{
+		Enabled:  true,
+		Cooldown: 10 * time.Minute,
+		ReasonCooldowns: map[AlertReason]time.Duration{
+			AlertReasonHedgeRemoval:        0,
+			AlertReasonResolutionConfirmed: 0,
+			AlertReasonContrarianWinner:    0,
+		},
+	}
+}
+
+// cooldownFor returns the shortest cooldown of an alert's reasons.
+func (c AlertSuppressorConfig) cooldownFor(reasons []AlertReason) time.Duration {
+	if len(reasons) == 0 {
+		return c.Cooldown
+	}
+
+	cooldown := time.Duration(-1)
+	for _, r := range reasons {
+		d := c.Cooldown
+		if override, ok := c.ReasonCooldowns[r]; ok {
+			d = override
+		}
+		if cooldown < 0 || d < cooldown {
+			cooldown = d
+		}
+	}
+	return cooldown
+}
+
+// SuppressionWindow tracks alerts on a wallet, market and side within the cooldown.
+type SuppressionWindow struct {
+	Key      string        `json:"key"`     // wallet:conditionID:side
+	Reasons  []string      `json:"reasons"` // Reasons already notified in the window
+	Cooldown time.Duration `json:"cooldown"`
+	Until    time.Time     `json:"until"`
+
+	// Running totals since the first alert
+	TotalFills    int     `json:"total_fills"`
+	TotalShares   float64 `json:"total_shares"`
+	TotalNotional float64 `json:"total_notional"`
+
+	// Repeats merged since the last alert or update, sent when the window closes
+	Pending         *notifier.TradeAlert `json:"pending,omitempty"` // Latest repeat
+	PendingFills    int                  `json:"pending_fills,omitempty"`
+	PendingShares   float64              `json:"pending_shares,omitempty"`
+	PendingNotional float64              `json:"pending_notional,omitempty"`
+}
+
+// add counts a fill in the running totals.
+func (w *SuppressionWindow) add(alert notifier.TradeAlert) {
+	w.TotalFills++
+	w.TotalShares += alert.Shares
+	w.TotalNotional += alert.Notional
+}
+
+// hasReasons reports whether every reason was already notified in the window.
+func (w *SuppressionWindow) hasReasons(reasons []AlertReason) bool {
+	for _, r := range reasons {
+		found := false
+		for _, have := range w.Reasons {
+			if have == string(r) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// addReasons records reasons notified in the window.
+func (w *SuppressionWindow) addReasons(reasons []AlertReason) {
+	for _, r := range reasons {
+		if !w.hasReasons([]AlertReason{r}) {
+			w.Reasons = append(w.Reasons, string(r))
+		}
+	}
+}
+
+// withTotals sets the window's running totals on an alert.
+func (w *SuppressionWindow) withTotals(alert notifier.TradeAlert) notifier.TradeAlert {
+	alert.TotalFills = w.TotalFills
+	alert.TotalShares = w.TotalShares
+	alert.TotalNotional = w.TotalNotional
+	return alert
+}
+
+// clearPending drops merged repeats (after they were sent or folded into an alert).
+func (w *SuppressionWindow) clearPending() {
+	w.Pending = nil
+	w.PendingFills = 0
+	w.PendingShares = 0
+	w.PendingNotional = 0
+}
+
+// takeUpdate builds an update from the merged repeats and clears them.
+// Returns false if nothing was merged.
+func (w *SuppressionWindow) takeUpdate() (notifier.TradeAlert, bool) {
+	if w.Pending == nil {
+		return notifier.TradeAlert{}, false
+	}
+
+	update := w.withTotals(*w.Pending)
+	update.ID = ""
+	update.Fills = nil // Belong to the last repeat only
+	update.IsUpdate = true
+	update.MergedFills = w.PendingFills
+	update.Shares = w.PendingShares
+	update.Notional = w.PendingNotional
+	if w.PendingShares > 0 {
+		update.Price = w.PendingNotional / w.PendingShares
+	}
+	w.clearPending()
+	return update, true
+}
+
+// SuppressionStats holds suppression metrics for the dashboard.
+type SuppressionStats struct {
+	Enabled    bool  `json:"enabled"`
+	Windows    int   `json:"windows"`    // Wallet, market and side combinations in cooldown
+	Suppressed int64 `json:"suppressed"` // Repeats merged instead of sent
+	Updates    int64 `json:"updates"`    // Updates sent for merged repeats
+}
+
+// AlertSuppressor sits in front of sendAlert and merges repeat alerts. The
+// first alert on a wallet, market and side is sent and opens a cooldown
+// window; repeats inside the window with no new reasons are merged, and sent
+// as a single update with running totals once the window closes. A wallet
+// splitting one order into many fills gets one alert plus periodic updates.
+type AlertSuppressor struct {
+	logger *zap.Logger
+	now    func() time.Time
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   AlertSuppressorConfig
+
+	mu         sync.Mutex
+	windows    map[string]*SuppressionWindow // wallet:conditionID:side -> window
+	suppressed int64
+	updates    int64
+
+	handlerMu sync.RWMutex
+	onUpdate  func(notifier.TradeAlert)
+
+	doneCh chan struct{}
+}
+
+// NewAlertSuppressor creates an alert suppressor.
+func NewAlertSuppressor(logger *zap.Logger, config AlertSuppressorConfig) *AlertSuppressor {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &AlertSuppressor{
+		logger:  logger.Named("alert-suppressor"),
+		now:     time.Now,
+		config:  config,
+		windows: make(map[string]*SuppressionWindow),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (s *AlertSuppressor) getConfig() AlertSuppressorConfig {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
+// UpdateConfig updates the suppressor config. Open windows keep their cooldown.
+func (s *AlertSuppressor) UpdateConfig(cfg AlertSuppressorConfig) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config = cfg
+}
+
+// SetUpdateHandler sets the function updates for merged repeats are sent through.
+func (s *AlertSuppressor) SetUpdateHandler(handler func(notifier.TradeAlert)) {
+	s.handlerMu.Lock()
+	defer s.handlerMu.Unlock()
+	s.onUpdate = handler
+}
+
+// emit sends updates through the update handler.
+func (s *AlertSuppressor) emit(updates []notifier.TradeAlert) {
+	s.handlerMu.RLock()
+	handler := s.onUpdate
+	s.handlerMu.RUnlock()
+	if handler == nil {
+		return
+	}
+	for _, u := range updates {
+		handler(u)
+	}
+}
+
+// suppressionKey groups alerts by wallet, market and side.
+// Returns "" if the alert is missing any of them.
+func suppressionKey(alert notifier.TradeAlert) string {
+	if alert.TraderAddress == "" || alert.ConditionID == "" {
+		return ""
+	}
+	return strings.ToLower(alert.TraderAddress) + ":" + alert.ConditionID + ":" + strings.ToUpper(alert.Side)
+}
+
+// Check decides whether an alert is sent. Repeats within the cooldown are
+// merged and false is returned. Sent alerts are returned with running totals
+// when earlier fills were merged. Updates always pass.
+func (s *AlertSuppressor) Check(alert notifier.TradeAlert) (notifier.TradeAlert, bool) {
+	cfg := s.getConfig()
+	key := suppressionKey(alert)
+	if !cfg.Enabled || alert.IsUpdate || key == "" {
+		return alert, true
+	}
+
+	cooldown := cfg.cooldownFor(alert.Reasons)
+	now := s.now()
+	var due []notifier.TradeAlert
+
+	s.mu.Lock()
+	w, ok := s.windows[key]
+	if ok && !now.Before(w.Until) {
+		// Window closed before the flush got to it
+		if update, hasUpdate := w.takeUpdate(); hasUpdate {
+			due = append(due, update)
+			s.updates++
+			w.Until = now.Add(w.Cooldown)
+		} else {
+			delete(s.windows, key)
+			ok = false
+		}
+	}
+
+	send := true
+	switch {
+	case cooldown <= 0:
+		// Reasons that are never suppressed
+	case !ok:
+		w = &SuppressionWindow{Key: key, Cooldown: cooldown, Until: now.Add(cooldown)}
+		w.addReasons(alert.Reasons)
+		w.add(alert)
+		s.windows[key] = w
+	case w.hasReasons(alert.Reasons):
+		// Repeat: merge into the next update
+		w.add(alert)
+		pending := alert
+		w.Pending = &pending
+		w.PendingFills++
+		w.PendingShares += alert.Shares
+		w.PendingNotional += alert.Notional
+		s.suppressed++
+		send = false
+	default:
+		// New reasons are sent right away, carrying the running totals
+		w.add(alert)
+		w.addReasons(alert.Reasons)
+		w.clearPending()
+		w.Cooldown = cooldown
+		w.Until = now.Add(cooldown)
+		alert = w.withTotals(alert)
+	}
+	s.mu.Unlock()
+
+	s.emit(due)
+	if !send {
+		s.logger.Debug("alert merged into update",
+			zap.String("key", key),
+			zap.Float64("notional", alert.Notional),
+		)
+	}
+	return alert, send
+}
+
+// Flush sends updates for windows that closed with merged repeats. Windows
+// that sent an update stay open for another cooldown, so later repeats keep
+// adding to the running total. Returns the number of updates sent.
+func (s *AlertSuppressor) Flush() int {
+	now := s.now()
+	var due []notifier.TradeAlert
+
+	s.mu.Lock()
+	for key, w := range s.windows {
+		if now.Before(w.Until) {
+			continue
+		}
+		if update, ok := w.takeUpdate(); ok {
+			due = append(due, update)
+			w.Until = now.Add(w.Cooldown)
+			continue
+		}
+		delete(s.windows, key)
+	}
+	s.updates += int64(len(due))
+	s.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Timestamp.Before(due[j].Timestamp)
+	})
+	s.emit(due)
+	return len(due)
+}
+
+// Start begins sending updates as windows close.
+func (s *AlertSuppressor) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Stop stops sending updates. Merged repeats are kept for Export.
+func (s *AlertSuppressor) Stop() {
+	close(s.doneCh)
+}
+
+// run flushes closed windows periodically.
+func (s *AlertSuppressor) run(ctx context.Context) {
+	ticker := time.NewTicker(suppressionFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.doneCh:
+			return
+		case <-ticker.C:
+			if n := s.Flush(); n > 0 {
+				s.logger.Info("sent updates for merged alerts", zap.Int("updates", n))
+			}
+		}
+	}
+}
+
+// Export returns the open windows for persistence.
+func (s *AlertSuppressor) Export() []SuppressionWindow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	windows := make([]SuppressionWindow, 0, len(s.windows))
+	for _, w := range s.windows {
+		c := *w
+		c.Reasons = append([]string(nil), w.Reasons...)
+		if w.Pending != nil {
+			pending := *w.Pending
+			c.Pending = &pending
+		}
+		windows = append(windows, c)
+	}
+	return windows
+}
+
+// Import restores persisted windows. Closed windows with nothing merged are
+// skipped; closed windows with merged repeats send their update on the next flush.
+func (s *AlertSuppressor) Import(windows []SuppressionWindow) int {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imported := 0
+	for i := range windows {
+		w := windows[i]
+		if w.Key == "" || (w.Pending == nil && !now.Before(w.Until)) {
+			continue
+		}
+		if _, exists := s.windows[w.Key]; exists {
+			continue
+		}
+		s.windows[w.Key] = &w
+		imported++
+	}
+	return imported
+}
+
+// Stats returns suppression metrics.
+func (s *AlertSuppressor) Stats() SuppressionStats {
+	cfg := s.getConfig()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SuppressionStats{
+		Enabled:    cfg.Enabled,
+		Windows:    len(s.windows),
+		Suppressed: s.suppressed,
+		Updates:    s.updates,
+	}
+}
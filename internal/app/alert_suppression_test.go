@@ -0,0 +1,188 @@
+package app
+
+import (
+	"polybot/clients/notifier"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// newTestSuppressor returns a suppressor on a controllable clock that records its updates.
+func newTestSuppressor(cfg AlertSuppressorConfig) (*AlertSuppressor, *time.Time, func() []notifier.TradeAlert) {
+	s := NewAlertSuppressor(zap.NewNop(), cfg)
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	var mu sync.Mutex
+	var updates []notifier.TradeAlert
+	s.SetUpdateHandler(func(alert notifier.TradeAlert) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, alert)
+	})
+	return s, &now, func() []notifier.TradeAlert {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]notifier.TradeAlert(nil), updates...)
+	}
+}
+
+func suppressionTestFill(shares, price float64, reasons ...AlertReason) notifier.TradeAlert {
+	return notifier.TradeAlert{
+		TraderAddress: "0xABC",
+		ConditionID:   "0xmarket",
+		Side:          "BUY",
+		Shares:        shares,
+		Price:         price,
+		Notional:      shares * price,
+		Reasons:       reasons,
+	}
+}
+
+func TestAlertSuppressor_MergesRepeatsIntoUpdate(t *testing.T) {
+	s, now, updates := newTestSuppressor(DefaultAlertSuppressorConfig())
+
+	if _, send := s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade)); !send {
+		t.Fatal("expected first alert sent")
+	}
+	for i := 0; i < 3; i++ {
+		if _, send := s.Check(suppressionTestFill(100, 0.6, AlertReasonMassiveTrade)); send {
+			t.Fatalf("expected repeat %d merged", i)
+		}
+	}
+
+	// Nothing is sent until the window closes
+	if n := s.Flush(); n != 0 {
+		t.Fatalf("expected no update inside the window, got %d", n)
+	}
+	*now = now.Add(11 * time.Minute)
+	if n := s.Flush(); n != 1 {
+		t.Fatalf("expected one update, got %d", n)
+	}
+
+	update := updates()[0]
+	if !update.IsUpdate || update.MergedFills != 3 || update.Shares != 300 {
+		t.Errorf("unexpected update: %+v", update)
+	}
+	if update.TotalFills != 4 || update.TotalShares != 400 || update.TotalNotional != 230 {
+		t.Errorf("unexpected running totals: fills=%d shares=%.0f notional=%.0f",
+			update.TotalFills, update.TotalShares, update.TotalNotional)
+	}
+	if update.Price < 0.599 || update.Price > 0.601 {
+		t.Errorf("expected average price of merged fills, got %f", update.Price)
+	}
+
+	// The window stays open after an update so later repeats keep the running total
+	s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade))
+	*now = now.Add(11 * time.Minute)
+	s.Flush()
+	if got := updates(); len(got) != 2 || got[1].TotalFills != 5 || got[1].MergedFills != 1 {
+		t.Errorf("expected second update with running total of 5 fills, got %+v", got)
+	}
+
+	// A quiet window closes; the next alert starts over
+	*now = now.Add(11 * time.Minute)
+	s.Flush()
+	if stats := s.Stats(); stats.Windows != 0 || stats.Suppressed != 4 || stats.Updates != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if _, send := s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade)); !send {
+		t.Error("expected alert after the window closed to be sent")
+	}
+}
+
+func TestAlertSuppressor_NewReasonsAreSent(t *testing.T) {
+	s, _, _ := newTestSuppressor(DefaultAlertSuppressorConfig())
+
+	s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade))
+	s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade))
+
+	alert, send := s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade, AlertReasonHighWinRate))
+	if !send {
+		t.Fatal("expected alert with a new reason sent")
+	}
+	if alert.TotalFills != 3 || alert.TotalShares != 300 {
+		t.Errorf("expected running totals on the alert, got %+v", alert)
+	}
+}
+
+func TestAlertSuppressor_KeysByWalletMarketAndSide(t *testing.T) {
+	s, _, _ := newTestSuppressor(DefaultAlertSuppressorConfig())
+	s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade))
+
+	sell := suppressionTestFill(100, 0.5, AlertReasonMassiveTrade)
+	sell.Side = "SELL"
+	other := suppressionTestFill(100, 0.5, AlertReasonMassiveTrade)
+	other.ConditionID = "0xother"
+	sameWallet := suppressionTestFill(100, 0.5, AlertReasonMassiveTrade)
+	sameWallet.TraderAddress = "0xabc"
+
+	for name, alert := range map[string]notifier.TradeAlert{"sell": sell, "other market": other} {
+		if _, send := s.Check(alert); !send {
+			t.Errorf("expected %s alert sent", name)
+		}
+	}
+	if _, send := s.Check(sameWallet); send {
+		t.Error("expected wallet addresses matched case-insensitively")
+	}
+}
+
+func TestAlertSuppressor_ReasonCooldowns(t *testing.T) {
+	cfg := DefaultAlertSuppressorConfig()
+	cfg.ReasonCooldowns[AlertReasonRapidTrading] = time.Hour
+	s, now, _ := newTestSuppressor(cfg)
+
+	// Zero cooldown reasons are never suppressed
+	for i := 0; i < 2; i++ {
+		if _, send := s.Check(suppressionTestFill(100, 0.5, AlertReasonHedgeRemoval)); !send {
+			t.Errorf("expected hedge removal %d sent", i)
+		}
+	}
+
+	// Longer per-reason cooldowns hold the window open
+	s.Check(suppressionTestFill(100, 0.5, AlertReasonRapidTrading))
+	*now = now.Add(30 * time.Minute)
+	if _, send := s.Check(suppressionTestFill(100, 0.5, AlertReasonRapidTrading)); send {
+		t.Error("expected repeat within the 1h reason cooldown merged")
+	}
+
+	if got := cfg.cooldownFor([]AlertReason{AlertReasonRapidTrading, AlertReasonHedgeRemoval}); got != 0 {
+		t.Errorf("expected the shortest reason cooldown to apply, got %v", got)
+	}
+}
+
+func TestAlertSuppressor_Disabled(t *testing.T) {
+	cfg := DefaultAlertSuppressorConfig()
+	cfg.Enabled = false
+	s, _, _ := newTestSuppressor(cfg)
+
+	for i := 0; i < 2; i++ {
+		if _, send := s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade)); !send {
+			t.Errorf("expected alert %d sent while disabled", i)
+		}
+	}
+}
+
+func TestAlertSuppressor_ExportImport(t *testing.T) {
+	s, now, _ := newTestSuppressor(DefaultAlertSuppressorConfig())
+	s.Check(suppressionTestFill(100, 0.5, AlertReasonMassiveTrade))
+	s.Check(suppressionTestFill(50, 0.5, AlertReasonMassiveTrade))
+
+	restored, restoredNow, updates := newTestSuppressor(DefaultAlertSuppressorConfig())
+	*restoredNow = *now
+	if n := restored.Import(s.Export()); n != 1 {
+		t.Fatalf("expected 1 window imported, got %d", n)
+	}
+
+	// Repeats stay merged across the restart
+	if _, send := restored.Check(suppressionTestFill(50, 0.5, AlertReasonMassiveTrade)); send {
+		t.Error("expected repeat after restart merged")
+	}
+	*restoredNow = restoredNow.Add(11 * time.Minute)
+	restored.Flush()
+	if got := updates(); len(got) != 1 || got[0].MergedFills != 2 || got[0].TotalShares != 200 {
+		t.Errorf("expected update with merged fills from before the restart, got %+v", got)
+	}
+}
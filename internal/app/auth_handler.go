@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"polybot/clients/gist"
+	"polybot/clients/storage"
 	"strings"
 	"sync"
 	"time"
@@ -90,7 +91,7 @@ type pendingSession struct {
 // AuthHandler handles passkey authentication.
 type AuthHandler struct {
 	logger     *zap.Logger
-	gistClient *gist.Client
+	gistClient gist.Storage
 	gistID     string
 	webauthn   *webauthn.WebAuthn
 
@@ -106,7 +107,7 @@ type AuthHandler struct {
 }
 
 // NewAuthHandler creates a new AuthHandler.
-func NewAuthHandler(logger *zap.Logger, gistClient *gist.Client, gistID string, rpID string, rpOrigins []string) (*AuthHandler, error) {
+func NewAuthHandler(logger *zap.Logger, gistClient gist.Storage, gistID string, rpID string, rpOrigins []string) (*AuthHandler, error) {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
@@ -129,7 +130,7 @@ func NewAuthHandler(logger *zap.Logger, gistClient *gist.Client, gistID string,
 		return nil, fmt.Errorf("create webauthn: %w", err)
 	}
 
-	return &AuthHandler{
+	h := &AuthHandler{
 		logger:          logger,
 		gistClient:      gistClient,
 		gistID:          gistID,
@@ -137,7 +138,15 @@ func NewAuthHandler(logger *zap.Logger, gistClient *gist.Client, gistID string,
 		loadedCh:        make(chan struct{}),
 		pendingSessions: make(map[string]*pendingSession),
 		cookieKey:       cookieKey,
-	}, nil
+	}
+
+	// Passkeys registered or removed by another instance are merged rather
+	// than overwritten
+	if merger, ok := gistClient.(storage.Merger); ok {
+		merger.SetMerger(passkeysFileName, h.mergeCredentials)
+	}
+
+	return h, nil
 }
 
 // IsEnabled returns true if passkey auth is configured.
@@ -257,22 +266,108 @@ func (h *AuthHandler) GetSession(r *http.Request) *SessionCookie {
 
 // saveCredentials saves passkeys to gist.
 func (h *AuthHandler) saveCredentials(ctx context.Context) error {
+	h.mu.RLock()
+	data, err := marshalPasskeys(h.credentials)
+	h.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := h.gistClient.Save(ctx, passkeysFileName, data, h.gistID); err != nil {
+		return fmt.Errorf("save passkeys to gist: %w", err)
+	}
+
+	return nil
+}
+
+// mergeCredentials merges passkeys changed by another instance since they
+// were last loaded. The in-memory credentials stand in for the local content,
+// since they include it plus anything changed since; they are updated to the
+// merged set so the next save doesn't undo the merge.
+func (h *AuthHandler) mergeCredentials(base, stored, local string) (string, error) {
+	baseStore, err := parsePasskeys(base)
+	if err != nil {
+		return "", fmt.Errorf("parse base passkeys: %w", err)
+	}
+	storedStore, err := parsePasskeys(stored)
+	if err != nil {
+		return "", fmt.Errorf("parse stored passkeys: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	before := len(h.credentials)
+	h.credentials = mergePasskeys(baseStore.Credentials, storedStore.Credentials, h.credentials)
+
+	h.logger.Info("merged passkeys changed by another instance",
+		zap.Int("before", before),
+		zap.Int("after", len(h.credentials)),
+	)
+
+	return marshalPasskeys(h.credentials)
+}
+
+// mergePasskeys three-way merges credential lists by ID. Local credentials
+// are kept unless they were removed from the stored list since base, and
+// stored credentials are added unless they were removed locally since base.
+// Credentials on both sides keep the higher sign count.
+func mergePasskeys(base, stored, local []PasskeyCredential) []PasskeyCredential {
+	inBase := make(map[string]bool, len(base))
+	for _, cred := range base {
+		inBase[string(cred.ID)] = true
+	}
+	storedByID := make(map[string]PasskeyCredential, len(stored))
+	for _, cred := range stored {
+		storedByID[string(cred.ID)] = cred
+	}
+
+	merged := make([]PasskeyCredential, 0, len(local)+len(stored))
+	inLocal := make(map[string]bool, len(local))
+	for _, cred := range local {
+		id := string(cred.ID)
+		inLocal[id] = true
+		remote, ok := storedByID[id]
+		if !ok && inBase[id] {
+			continue // Removed by the other instance
+		}
+		if ok && remote.SignCount > cred.SignCount {
+			cred.SignCount = remote.SignCount
+		}
+		merged = append(merged, cred)
+	}
+	for _, cred := range stored {
+		id := string(cred.ID)
+		if !inLocal[id] && !inBase[id] {
+			merged = append(merged, cred) // Registered by the other instance
+		}
+	}
+	return merged
+}
+
+// parsePasskeys parses passkeys.json content; empty content has no passkeys.
+func parsePasskeys(content string) (PasskeyStore, error) {
+	var store PasskeyStore
+	if content == "" {
+		return store, nil
+	}
+	err := json.Unmarshal([]byte(content), &store)
+	return store, err
+}
+
+// marshalPasskeys builds passkeys.json content.
+func marshalPasskeys(credentials []PasskeyCredential) (string, error) {
 	store := PasskeyStore{
 		Version:     1,
 		UpdatedAt:   time.Now(),
-		Credentials: h.credentials,
+		Credentials: credentials,
 	}
 
 	data, err := json.MarshalIndent(store, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshal passkeys: %w", err)
-	}
-
-	if err := h.gistClient.Save(ctx, passkeysFileName, string(data), h.gistID); err != nil {
-		return fmt.Errorf("save passkeys to gist: %w", err)
+		return "", fmt.Errorf("marshal passkeys: %w", err)
 	}
-
-	return nil
+	return string(data), nil
 }
 
 // signCookie creates an HMAC-signed cookie value.
@@ -703,9 +798,7 @@ func (h *AuthHandler) handleFinishRegister(w http.ResponseWriter, r *http.Reques
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	h.mu.RLock()
 	err = h.saveCredentials(ctx)
-	h.mu.RUnlock()
 
 	if err != nil {
 		h.logger.Error("failed to save credentials", zap.Error(err))
@@ -1112,9 +1205,7 @@ func (h *AuthHandler) removeCredential(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	h.mu.RLock()
 	err = h.saveCredentials(ctx)
-	h.mu.RUnlock()
 
 	if err != nil {
 		h.logger.Error("failed to save credentials after removal", zap.Error(err))
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func passkeyIDs(creds []PasskeyCredential) []string {
+	ids := make([]string, len(creds))
+	for i, cred := range creds {
+		ids[i] = string(cred.ID)
+	}
+	return ids
+}
+
+func TestMergePasskeys(t *testing.T) {
+	cred := func(id string, signCount uint32) PasskeyCredential {
+		return PasskeyCredential{ID: []byte(id), SignCount: signCount}
+	}
+
+	base := []PasskeyCredential{cred("a", 1), cred("b", 1), cred("c", 1)}
+	// The other instance removed b and registered d; a was used there
+	stored := []PasskeyCredential{cred("a", 5), cred("c", 1), cred("d", 0)}
+	// This instance removed c and registered e
+	local := []PasskeyCredential{cred("a", 2), cred("b", 1), cred("e", 0)}
+
+	merged := mergePasskeys(base, stored, local)
+	ids := passkeyIDs(merged)
+	if len(ids) != 3 || ids[0] != "a" || ids[1] != "e" || ids[2] != "d" {
+		t.Fatalf("expected [a e d], got %v", ids)
+	}
+	if merged[0].SignCount != 5 {
+		t.Errorf("expected the higher sign count, got %d", merged[0].SignCount)
+	}
+
+	// Without a base nothing counts as removed
+	ids = passkeyIDs(mergePasskeys(nil, stored, local))
+	if len(ids) != 5 {
+		t.Errorf("expected the union without a base, got %v", ids)
+	}
+}
+
+func TestAuthHandler_MergeCredentials(t *testing.T) {
+	h, err := NewAuthHandler(nil, NewMockGistStorage(), "passkeys-gist", "localhost", []string{"http://localhost:8080"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.credentials = []PasskeyCredential{{ID: []byte("a")}, {ID: []byte("local")}}
+
+	base, _ := marshalPasskeys([]PasskeyCredential{{ID: []byte("a")}})
+	stored, _ := marshalPasskeys([]PasskeyCredential{{ID: []byte("a")}, {ID: []byte("remote")}})
+
+	merged, err := h.mergeCredentials(base, stored, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var store PasskeyStore
+	if err := json.Unmarshal([]byte(merged), &store); err != nil {
+		t.Fatalf("invalid merged content: %v", err)
+	}
+	if ids := passkeyIDs(store.Credentials); len(ids) != 3 {
+		t.Errorf("expected both registrations kept, got %v", ids)
+	}
+	if len(h.credentials) != 3 {
+		t.Errorf("expected in-memory credentials updated, got %v", passkeyIDs(h.credentials))
+	}
+
+	if _, err := h.mergeCredentials("", "not json", ""); err == nil {
+		t.Error("expected error for unparseable stored passkeys")
+	}
+}
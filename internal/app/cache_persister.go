@@ -13,7 +13,7 @@ import (
 // CachePersister handles persisting the wallet cache and seen trades to GitHub Gist.
 type CachePersister struct {
 	logger             *zap.Logger
-	gistClient         *gist.Client
+	gistClient         gist.Storage
 	walletTracker      *WalletTracker
 	tradeMonitor       *TradeMonitor
 	uploadInterval     time.Duration
@@ -25,7 +25,7 @@ type CachePersister struct {
 // NewCachePersister creates a new cache persister.
 func NewCachePersister(
 	logger *zap.Logger,
-	gistClient *gist.Client,
+	gistClient gist.Storage,
 	walletTracker *WalletTracker,
 	tradeMonitor *TradeMonitor,
 	uploadInterval time.Duration,
@@ -133,15 +133,14 @@ func (cp *CachePersister) SaveSeenTrades(ctx context.Context) error {
 		return nil
 	}
 
-	count := cp.tradeMonitor.SeenTradesCount()
-	if count == 0 {
+	// Export the seen trades (with alert suppression windows)
+	snapshot := cp.tradeMonitor.ExportSeenTrades()
+	count := len(snapshot.Trades)
+	if count == 0 && len(snapshot.Suppression) == 0 {
 		cp.logger.Debug("no seen trades to save")
 		return nil
 	}
 
-	// Export the seen trades
-	snapshot := cp.tradeMonitor.ExportSeenTrades()
-
 	// Limit the number of trades to prevent gist from growing too large
 	// Keep the most recent trades (they're at the end of the slice since maps iterate randomly,
 	// but for deduplication purposes it doesn't matter which ones we keep)
@@ -162,6 +161,7 @@ func (cp *CachePersister) SaveSeenTrades(ctx context.Context) error {
 	cp.logger.Info("saved seen trades to gist",
 		zap.String("gistID", cp.gistClient.GetGistID()),
 		zap.Int("trades", len(snapshot.Trades)),
+		zap.Int("suppressionWindows", len(snapshot.Suppression)),
 	)
 
 	return nil
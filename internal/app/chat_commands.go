@@ -0,0 +1,436 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"polybot/clients/polymarketapi"
+	"polybot/config"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// chatTaskTimeout bounds wallet and holders commands, matching the tasks API.
+	chatTaskTimeout = 5 * time.Minute
+	// chatListLimit caps the rows listed in command replies.
+	chatListLimit = 10
+)
+
+// errWalletFilterUnchanged aborts a settings update that wouldn't change the
+// wallet filter.
+var errWalletFilterUnchanged = errors.New("wallet filter unchanged")
+
+// chatMarkup renders command replies for a chat platform.
+type chatMarkup struct {
+	prefix string // How commands are invoked, e.g. "/" or "/polybot "
+	bold   func(string) string
+	escape func(string) string
+}
+
+// telegramMarkup renders legacy Telegram Markdown.
+var telegramMarkup = chatMarkup{
+	prefix: "/",
+	bold:   func(s string) string { return "*" + s + "*" },
+	escape: escapeTelegram,
+}
+
+// discordMarkup renders Discord Markdown.
+var discordMarkup = chatMarkup{
+	prefix: "/polybot ",
+	bold:   func(s string) string { return "**" + s + "**" },
+	escape: escapeDiscord,
+}
+
+// usage formats a command usage line.
+func (m chatMarkup) usage(command string) string {
+	return fmt.Sprintf("Usage: `%s%s`", m.prefix, command)
+}
+
+// chatCommands implements the commands shared by the Telegram and Discord bots.
+// Replies are Markdown in the platform's dialect.
+type chatCommands struct {
+	logger     *zap.Logger
+	polymarket *polymarketapi.PolymarketApiClient
+	settings   *config.SettingsManager
+	mutes      *MuteList
+	stats      func() ServiceStats
+	markup     chatMarkup
+}
+
+// newChatCommands creates the shared command implementations. Any dependency
+// may be nil; commands needing it reply that they are unavailable.
+func newChatCommands(
+	logger *zap.Logger,
+	polymarket *polymarketapi.PolymarketApiClient,
+	settings *config.SettingsManager,
+	mutes *MuteList,
+	stats func() ServiceStats,
+	markup chatMarkup,
+) *chatCommands {
+	return &chatCommands{
+		logger:     logger,
+		polymarket: polymarket,
+		settings:   settings,
+		mutes:      mutes,
+		stats:      stats,
+		markup:     markup,
+	}
+}
+
+// wallet runs the wallet activity task.
+func (c *chatCommands) wallet(ctx context.Context, args []string) string {
+	if len(args) == 0 || !isWalletAddress(args[0]) {
+		return c.markup.usage("wallet <address> [1d|1w|2w|1m|3m|6m|1y]")
+	}
+	req := WalletActivityRequest{WalletAddress: strings.ToLower(args[0]), Duration: "1m"}
+	if len(args) > 1 && args[1] != "" {
+		switch args[1] {
+		case "1d", "1w", "2w", "1m", "3m", "6m", "1y":
+			req.Duration = args[1]
+		default:
+			return "Duration must be one of 1d, 1w, 2w, 1m, 3m, 6m, 1y."
+		}
+	}
+
+	taskCtx, cancel := context.WithTimeout(ctx, chatTaskTimeout)
+	defer cancel()
+
+	result, err := NewWalletActivityTask(c.polymarket, c.logger).Execute(taskCtx, req)
+	if err != nil {
+		c.logger.Error("wallet activity task execution failed", zap.Error(err))
+		return "❌ Wallet activity failed: " + c.markup.escape(err.Error())
+	}
+	return c.markup.formatWalletActivity(result)
+}
+
+// formatWalletActivity formats a wallet activity result.
+func (m chatMarkup) formatWalletActivity(result *WalletActivityResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s `%s` (%s)\n", m.bold("👛 Wallet Activity"), result.WalletAddress, result.Duration))
+	sb.WriteString(fmt.Sprintf("$%.0f cost basis · %d trades · %d markets\n",
+		result.TotalCostBasis, result.TotalTradeCount, result.TotalMarkets))
+
+	if len(result.Markets) > 0 {
+		sb.WriteString("\n" + m.bold("Top Markets") + "\n")
+		for i, market := range result.Markets {
+			if i == chatListLimit {
+				sb.WriteString(fmt.Sprintf("…and %d more\n", len(result.Markets)-i))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("• %s: $%.0f (%d trades)\n", m.escape(market.Title), market.TotalCostBasis, market.TradeCount))
+		}
+	}
+	if len(result.Errors) > 0 {
+		sb.WriteString(fmt.Sprintf("\n⚠️ %d errors while scanning\n", len(result.Errors)))
+	}
+	return sb.String()
+}
+
+// holders runs the market holders task.
+func (c *chatCommands) holders(ctx context.Context, args []string) string {
+	if len(args) == 0 || !isConditionID(args[0]) {
+		return c.markup.usage("holders <conditionId>")
+	}
+
+	taskCtx, cancel := context.WithTimeout(ctx, chatTaskTimeout)
+	defer cancel()
+
+	result, err := NewMarketHoldersTask(c.polymarket, c.logger).Execute(taskCtx, MarketHoldersRequest{
+		ConditionID: strings.ToLower(args[0]),
+		TopN:        5,
+	})
+	if err != nil {
+		c.logger.Error("market holders task execution failed", zap.Error(err))
+		return "❌ Market holders failed: " + c.markup.escape(err.Error())
+	}
+	return c.markup.formatMarketHolders(result)
+}
+
+// formatMarketHolders formats a market holders result.
+func (m chatMarkup) formatMarketHolders(result *MarketHoldersResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s\n", m.bold("👥 Holders"), m.escape(nz(result.Title, shortID(result.ConditionID)))))
+	sb.WriteString(fmt.Sprintf("%d traders · %d trades processed\n", result.TotalTraders, result.TradesProcessed))
+
+	for _, o := range result.Outcomes {
+		sb.WriteString(fmt.Sprintf("\n%s (%d holders)\n", m.bold(m.escape(o.Outcome)), o.TotalHolders))
+		for _, holder := range o.TopHolders {
+			wallet := fmt.Sprintf("`%s`", shortID(holder.Wallet))
+			if holder.ProfileURL != "" {
+				wallet = fmt.Sprintf("[%s](%s)", shortID(holder.Wallet), holder.ProfileURL)
+			}
+			sb.WriteString(fmt.Sprintf("• %s: %.0f shares @ $%.3f\n", wallet, holder.Size, holder.AvgPrice))
+		}
+	}
+	return sb.String()
+}
+
+// statsSummary summarizes ServiceStats.
+func (c *chatCommands) statsSummary() string {
+	if c.stats == nil {
+		return "Stats are not available."
+	}
+	stats := c.stats()
+
+	var sb strings.Builder
+	sb.WriteString(c.markup.bold("📊 Polybot Stats") + "\n\n")
+	sb.WriteString(fmt.Sprintf("Uptime: %s\n", stats.Uptime))
+	if stats.WebSocket.Enabled {
+		state := "disconnected"
+		if stats.WebSocket.Connected {
+			state = "connected"
+		}
+		sb.WriteString(fmt.Sprintf("WebSocket: %s · %d messages\n", state, stats.WebSocket.MessageCount))
+	}
+	sb.WriteString(fmt.Sprintf("Markets: %d\n", stats.Markets.Count))
+	sb.WriteString(fmt.Sprintf("Alerts: %d total · 1h: %d · 24h: %d · 7d: %d\n",
+		stats.Alerts.Total, stats.AlertsLastHour, stats.AlertsLast24h, stats.AlertsLast7d))
+	if stats.LastAlertAgo != "" {
+		sb.WriteString(fmt.Sprintf("Last alert: %s ago\n", stats.LastAlertAgo))
+	}
+	sb.WriteString(fmt.Sprintf("Wallet cache: %d · Seen trades: %d\n", stats.Caches.WalletCacheSize, stats.Caches.SeenTradesSize))
+	sb.WriteString(fmt.Sprintf("Dead letters: %d\n", stats.Notifications.DeadLetters))
+	if m := stats.Notifications.Mutes; m != nil {
+		sb.WriteString(fmt.Sprintf("Mutes: %d active · %d alerts suppressed\n", m.Active, m.Suppressed))
+	}
+	if f := stats.AlertFeedback; f != nil && f.Overall.Alerts > 0 {
+		sb.WriteString(fmt.Sprintf("False positives: %d of %d alerts (%.1f%%)\n",
+			f.Overall.FalsePositives, f.Overall.Alerts, f.Overall.FalsePositiveRate*100))
+	}
+
+	if len(stats.TopWallets) > 0 {
+		sb.WriteString("\n" + c.markup.bold("Top Wallets") + "\n")
+		for i, w := range stats.TopWallets {
+			if i == 5 {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("• `%s`: %d alerts\n", shortID(w.Address), w.Count))
+		}
+	}
+	return sb.String()
+}
+
+// watch adds wallets to (or removes them from) the wallet filter and saves
+// the change to settings.
+func (c *chatCommands) watch(ctx context.Context, args []string, add bool) string {
+	if c.settings == nil {
+		return "Settings are not available."
+	}
+
+	if len(args) == 0 {
+		if !add {
+			return c.markup.usage("unwatch <address…>")
+		}
+		return formatWalletFilter(c.settings.GetCurrentConfig().WalletFilter.SpecificWallets)
+	}
+	for _, arg := range args {
+		if !isWalletAddress(arg) {
+			return fmt.Sprintf("Not a wallet address: %s", c.markup.escape(arg))
+		}
+	}
+
+	var wallets []string
+	err := c.settings.Update(ctx, func(cfg *config.Config) error {
+		wallets = make([]string, 0, len(cfg.WalletFilter.SpecificWallets)+len(args))
+		seen := make(map[string]bool)
+		for _, w := range cfg.WalletFilter.SpecificWallets {
+			w = strings.ToLower(w)
+			if !seen[w] {
+				seen[w] = true
+				wallets = append(wallets, w)
+			}
+		}
+
+		changed := 0
+		for _, arg := range args {
+			w := strings.ToLower(arg)
+			if add && !seen[w] {
+				seen[w] = true
+				wallets = append(wallets, w)
+				changed++
+			} else if !add && seen[w] {
+				delete(seen, w)
+				changed++
+			}
+		}
+		if !add {
+			kept := wallets[:0]
+			for _, w := range wallets {
+				if seen[w] {
+					kept = append(kept, w)
+				}
+			}
+			wallets = kept
+		}
+		if changed == 0 {
+			return errWalletFilterUnchanged
+		}
+
+		cfg.WalletFilter.SpecificWallets = wallets
+		if len(wallets) == 0 {
+			cfg.WalletFilter.SpecificWallets = nil
+		}
+		return nil
+	})
+	if errors.Is(err, errWalletFilterUnchanged) {
+		return "No change. " + formatWalletFilter(wallets)
+	}
+	if err != nil {
+		c.logger.Error("failed to update wallet filter", zap.Error(err))
+		return "❌ Failed to update wallet filter: " + c.markup.escape(err.Error())
+	}
+	return "✅ Wallet filter updated. " + formatWalletFilter(wallets)
+}
+
+// formatWalletFilter describes the wallet filter.
+func formatWalletFilter(wallets []string) string {
+	if len(wallets) == 0 {
+		return "Monitoring all wallets."
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Monitoring only %d wallets:\n", len(wallets)))
+	for _, w := range wallets {
+		sb.WriteString(fmt.Sprintf("• `%s`\n", w))
+	}
+	return sb.String()
+}
+
+// mute mutes a wallet or market, or lists active mutes.
+func (c *chatCommands) mute(createdBy string, args []string) string {
+	if c.mutes == nil {
+		return "Mutes are not available."
+	}
+	if len(args) == 0 {
+		return c.markup.formatMutes(c.mutes.Active())
+	}
+	if len(args) < 2 {
+		return c.markup.usage("mute <address|conditionId> <duration>")
+	}
+
+	kind, ok := muteKindOf(args[0])
+	if !ok {
+		return fmt.Sprintf("Not a wallet address or condition ID: %s", c.markup.escape(args[0]))
+	}
+	duration, err := parseMuteDuration(args[1])
+	if err != nil {
+		return "Duration must be like 30m, 6h, 3d or 1w."
+	}
+	return c.muteTarget(kind, args[0], duration, createdBy)
+}
+
+// muteTarget mutes a validated wallet or market.
+func (c *chatCommands) muteTarget(kind MuteKind, target string, duration time.Duration, createdBy string) string {
+	if c.mutes == nil {
+		return "Mutes are not available."
+	}
+	mute := c.mutes.Mute(kind, strings.ToLower(target), duration, createdBy)
+
+	pst, _ := time.LoadLocation("America/Los_Angeles")
+	return fmt.Sprintf("🔇 Muted %s `%s` until %s", kind, mute.Target, mute.Until.In(pst).Format("1/2/2006, 3:04PM (MST)"))
+}
+
+// unmute removes a mute.
+func (c *chatCommands) unmute(args []string) string {
+	if c.mutes == nil {
+		return "Mutes are not available."
+	}
+	if len(args) == 0 {
+		return c.markup.usage("unmute <address|conditionId>")
+	}
+	kind, ok := muteKindOf(args[0])
+	if !ok {
+		return fmt.Sprintf("Not a wallet address or condition ID: %s", c.markup.escape(args[0]))
+	}
+	if !c.mutes.Unmute(kind, args[0]) {
+		return fmt.Sprintf("%s `%s` was not muted.", kind, strings.ToLower(args[0]))
+	}
+	return fmt.Sprintf("🔊 Unmuted %s `%s`", kind, strings.ToLower(args[0]))
+}
+
+// formatMutes lists active mutes.
+func (m chatMarkup) formatMutes(mutes []Mute) string {
+	if len(mutes) == 0 {
+		return "No active mutes."
+	}
+	pst, _ := time.LoadLocation("America/Los_Angeles")
+	var sb strings.Builder
+	sb.WriteString(m.bold("🔇 Active Mutes") + "\n")
+	for _, mute := range mutes {
+		sb.WriteString(fmt.Sprintf("• %s `%s` until %s\n", mute.Kind, mute.Target, mute.Until.In(pst).Format("1/2/2006, 3:04PM (MST)")))
+	}
+	return sb.String()
+}
+
+// muteKindOf infers whether a target is a wallet address or a market condition ID.
+func muteKindOf(target string) (MuteKind, bool) {
+	switch {
+	case isWalletAddress(target):
+		return MuteKindWallet, true
+	case isConditionID(target):
+		return MuteKindMarket, true
+	}
+	return "", false
+}
+
+// parseMuteDuration parses Go durations plus day ("3d") and week ("1w") suffixes.
+func parseMuteDuration(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(s, "d"), strings.HasSuffix(s, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			unit = 7 * 24 * time.Hour
+		}
+		var n float64
+		n, err = strconv.ParseFloat(s[:len(s)-1], 64)
+		d = time.Duration(n * float64(unit))
+	default:
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return d, nil
+}
+
+// isWalletAddress reports whether s looks like a 0x-prefixed 20-byte address.
+func isWalletAddress(s string) bool {
+	return isHexID(s, 40)
+}
+
+// isConditionID reports whether s looks like a 0x-prefixed 32-byte condition ID.
+func isConditionID(s string) bool {
+	return isHexID(s, 64)
+}
+
+// isHexID reports whether s is "0x" followed by digits hex characters.
+func isHexID(s string, digits int) bool {
+	if len(s) != digits+2 || !strings.HasPrefix(strings.ToLower(s), "0x") {
+		return false
+	}
+	for _, c := range s[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeTelegram escapes Telegram Markdown special characters.
+func escapeTelegram(s string) string {
+	return strings.NewReplacer("_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "`", "\\`").Replace(s)
+}
+
+// escapeDiscord escapes Discord Markdown special characters.
+func escapeDiscord(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "_", "\\_", "*", "\\*", "~", "\\~", "`", "\\`", "|", "\\|", ">", "\\>").Replace(s)
+}
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,6 +13,7 @@ import (
 	"time"
 
 	"polybot/clients/gist"
+	"polybot/clients/polymarketapi"
 	"polybot/config"
 
 	"go.uber.org/zap"
@@ -36,8 +39,21 @@ func (s ContrarianStats) ContrarianRate() float64 {
 	return float64(s.Wins) / float64(total)
 }
 
+// contrarianRecomputeDelay paces the closed-position fetches that recompute
+// wallets loaded from the legacy format.
+const contrarianRecomputeDelay = 500 * time.Millisecond
+
 // ContrarianCache tracks wallets with contrarian betting history.
-// Uses a compact text format: "address:wins:losses" per line.
+//
+// Stats are a ledger of closed positions keyed by (wallet, position), so a
+// position seen again when a wallet's stats are refetched counts only once.
+// Uses a compact text format, one wallet per line:
+// "address:wins:losses:+key,-key,..." where each key is a short hash of the
+// position's asset ID, prefixed + for a win and - for a loss.
+//
+// Lines in the legacy "address:wins:losses" format, whose counts were inflated
+// by recounting, are kept until the wallet is recomputed from fresh closed
+// positions (see RecomputeLegacy), then replaced.
 type ContrarianCache struct {
 	logger       *zap.Logger
 	gistClient   gist.Storage
@@ -46,6 +62,8 @@ type ContrarianCache struct {
 
 	mu      sync.RWMutex
 	wallets map[string]ContrarianStats // address -> stats
+	ledger  map[string]map[string]bool // address -> position key -> win
+	legacy  map[string]bool            // addresses with unkeyed legacy counts
 	dirty   bool                       // true if cache has unsaved changes
 
 	// Pending updates channel for async processing
@@ -55,7 +73,13 @@ type ContrarianCache struct {
 
 type walletUpdate struct {
 	address string
-	win     bool // true if contrarian win, false if contrarian loss
+	results []positionResult
+}
+
+// positionResult is a closed contrarian position.
+type positionResult struct {
+	key string // See contrarianPositionKey
+	win bool   // true if contrarian win, false if contrarian loss
 }
 
 // NewContrarianCache creates a new contrarian cache tracker.
@@ -83,6 +107,8 @@ func NewContrarianCache(logger *zap.Logger, cfg *config.Config) *ContrarianCache
 		config:      cfg.ContrarianCache,
 		githubToken: cfg.Gist.Token,
 		wallets:     make(map[string]ContrarianStats),
+		ledger:      make(map[string]map[string]bool),
+		legacy:      make(map[string]bool),
 		updateCh:    make(chan walletUpdate, 1000), // Buffer for async updates
 		doneCh:      make(chan struct{}),
 	}
@@ -90,7 +116,7 @@ func NewContrarianCache(logger *zap.Logger, cfg *config.Config) *ContrarianCache
 	return cc
 }
 
-// SetGistClient sets the gist client (useful for testing with mocks).
+// SetGistClient sets the storage backend (a local backend, or a mock in tests).
 func (cc *ContrarianCache) SetGistClient(client gist.Storage) {
 	cc.gistClient = client
 }
@@ -151,39 +177,154 @@ func (cc *ContrarianCache) periodicSave(ctx context.Context) {
 	}
 }
 
-// applyUpdate applies a single update to the cache.
+// applyUpdate adds a wallet's closed positions to the ledger, counting only
+// positions not seen before.
 func (cc *ContrarianCache) applyUpdate(update walletUpdate) {
 	cc.mu.Lock()
 	defer cc.mu.Unlock()
 
+	// Legacy counts are replaced by the first fresh closed-position data
+	if cc.legacy[update.address] {
+		delete(cc.legacy, update.address)
+		delete(cc.wallets, update.address)
+		cc.dirty = true
+	}
+	if len(update.results) == 0 {
+		return
+	}
+
+	positions := cc.ledger[update.address]
+	if positions == nil {
+		positions = make(map[string]bool)
+		cc.ledger[update.address] = positions
+	}
+
 	stats := cc.wallets[update.address]
-	if update.win {
-		if stats.Wins < 65535 {
-			stats.Wins++
+	added := 0
+	for _, result := range update.results {
+		if _, seen := positions[result.key]; seen {
+			continue
 		}
-	} else {
-		if stats.Losses < 65535 {
-			stats.Losses++
+		positions[result.key] = result.win
+		added++
+		if result.win {
+			if stats.Wins < 65535 {
+				stats.Wins++
+			}
+		} else {
+			if stats.Losses < 65535 {
+				stats.Losses++
+			}
 		}
 	}
-	cc.wallets[update.address] = stats
-	cc.dirty = true
+	if added > 0 {
+		cc.wallets[update.address] = stats
+		cc.dirty = true
+	}
 }
 
-// RecordContrarianResult queues an async update for a wallet's contrarian result.
-func (cc *ContrarianCache) RecordContrarianResult(address string, win bool) {
+// RecordClosedPositions queues an async update with the contrarian results
+// among a wallet's closed positions. Positions already recorded are ignored,
+// so the same positions can be passed on every refetch.
+func (cc *ContrarianCache) RecordClosedPositions(address string, positions []polymarketapi.ClosedPosition, threshold float64) {
 	if !cc.IsEnabled() {
 		return
 	}
 
 	// Non-blocking send
 	select {
-	case cc.updateCh <- walletUpdate{address: strings.ToLower(address), win: win}:
+	case cc.updateCh <- walletUpdate{address: strings.ToLower(address), results: contrarianResults(positions, threshold)}:
 	default:
 		cc.logger.Debug("contrarian update channel full, dropping update")
 	}
 }
 
+// RecomputeLegacy recomputes wallets loaded from the legacy format from fresh
+// closed positions, pacing the fetches. Wallets refetched in the meantime are
+// skipped. Returns when every legacy wallet was tried or the cache stops.
+func (cc *ContrarianCache) RecomputeLegacy(ctx context.Context, fetch func(ctx context.Context, wallet string) ([]polymarketapi.ClosedPosition, error)) {
+	if !cc.IsEnabled() {
+		return
+	}
+
+	cc.mu.RLock()
+	wallets := make([]string, 0, len(cc.legacy))
+	for address := range cc.legacy {
+		wallets = append(wallets, address)
+	}
+	cc.mu.RUnlock()
+	if len(wallets) == 0 {
+		return
+	}
+
+	cc.logger.Info("recomputing legacy contrarian stats", zap.Int("wallets", len(wallets)))
+
+	recomputed, failed := 0, 0
+	for i, address := range wallets {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-cc.doneCh:
+				return
+			case <-time.After(contrarianRecomputeDelay):
+			}
+		}
+
+		cc.mu.RLock()
+		pending := cc.legacy[address]
+		cc.mu.RUnlock()
+		if !pending {
+			continue
+		}
+
+		positions, err := fetch(ctx, address)
+		if err != nil {
+			failed++
+			cc.logger.Debug("failed to fetch closed positions for recompute",
+				zap.String("wallet", shortID(address)),
+				zap.Error(err),
+			)
+			continue
+		}
+		cc.applyUpdate(walletUpdate{address: address, results: contrarianResults(positions, cc.config.ContrarianThreshold)})
+		recomputed++
+	}
+
+	cc.logger.Info("recomputed legacy contrarian stats",
+		zap.Int("recomputed", recomputed),
+		zap.Int("failed", failed),
+	)
+}
+
+// contrarianResults returns the resolved positions entered at a contrarian
+// price, keyed by position.
+func contrarianResults(positions []polymarketapi.ClosedPosition, threshold float64) []positionResult {
+	if threshold <= 0 {
+		threshold = 0.20
+	}
+	var results []positionResult
+	for _, p := range positions {
+		if p.RealizedPnl == 0 || !IsContrarianPrice(p.AvgPrice, threshold) {
+			continue
+		}
+		results = append(results, positionResult{key: contrarianPositionKey(p), win: p.RealizedPnl > 0})
+	}
+	return results
+}
+
+// contrarianPositionKey identifies a closed position within a wallet: a short
+// hash of its asset ID (the outcome token), or of its condition ID when the
+// asset is missing. 48 bits keep collisions within one wallet negligible.
+func contrarianPositionKey(p polymarketapi.ClosedPosition) string {
+	id := p.Asset
+	if id == "" {
+		id = p.ConditionID + "/" + strconv.Itoa(p.OutcomeIndex)
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:6])
+}
+
 // GetStats returns the contrarian stats for a wallet.
 func (cc *ContrarianCache) GetStats(address string) (ContrarianStats, bool) {
 	cc.mu.RLock()
@@ -231,8 +372,10 @@ func (cc *ContrarianCache) Load(ctx context.Context) error {
 	cc.mu.Lock()
 	defer cc.mu.Unlock()
 
-	// Parse the compact format: "address:wins:losses" per line
+	// Parse the compact format: "address:wins:losses:keys" per line
 	cc.wallets = make(map[string]ContrarianStats)
+	cc.ledger = make(map[string]map[string]bool)
+	cc.legacy = make(map[string]bool)
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	lineNum := 0
 	for scanner.Scan() {
@@ -243,7 +386,7 @@ func (cc *ContrarianCache) Load(ctx context.Context) error {
 		}
 
 		parts := strings.Split(line, ":")
-		if len(parts) != 3 {
+		if len(parts) != 3 && len(parts) != 4 {
 			cc.logger.Debug("skipping malformed line", zap.Int("line", lineNum))
 			continue
 		}
@@ -260,10 +403,24 @@ func (cc *ContrarianCache) Load(ctx context.Context) error {
 			Wins:   uint16(wins),
 			Losses: uint16(losses),
 		}
+		if len(parts) == 3 {
+			cc.legacy[address] = true
+			continue
+		}
+
+		positions := make(map[string]bool)
+		for _, key := range strings.Split(parts[3], ",") {
+			if len(key) < 2 || (key[0] != '+' && key[0] != '-') {
+				continue
+			}
+			positions[key[1:]] = key[0] == '+'
+		}
+		cc.ledger[address] = positions
 	}
 
 	cc.logger.Info("loaded contrarian cache",
 		zap.Int("wallets", len(cc.wallets)),
+		zap.Int("legacy", len(cc.legacy)),
 	)
 
 	return nil
@@ -281,16 +438,32 @@ func (cc *ContrarianCache) Save(ctx context.Context) error {
 		return nil
 	}
 
-	// Check size limit - estimate ~50 bytes per entry
-	estimatedSize := len(cc.wallets) * 50
-	if int64(estimatedSize) > cc.config.MaxSizeBytes {
+	// Check size limit
+	if cc.estimatedSize() > cc.config.MaxSizeBytes {
 		cc.pruneOldest()
 	}
 
 	// Build compact format
 	var buf bytes.Buffer
 	for address, stats := range cc.wallets {
-		fmt.Fprintf(&buf, "%s:%d:%d\n", address, stats.Wins, stats.Losses)
+		fmt.Fprintf(&buf, "%s:%d:%d", address, stats.Wins, stats.Losses)
+		if positions := cc.ledger[address]; len(positions) > 0 {
+			buf.WriteByte(':')
+			first := true
+			for key, win := range positions {
+				if !first {
+					buf.WriteByte(',')
+				}
+				first = false
+				if win {
+					buf.WriteByte('+')
+				} else {
+					buf.WriteByte('-')
+				}
+				buf.WriteString(key)
+			}
+		}
+		buf.WriteByte('\n')
 	}
 
 	content := buf.String()
@@ -313,17 +486,35 @@ func (cc *ContrarianCache) Save(ctx context.Context) error {
 	return nil
 }
 
+// Estimated bytes per wallet line and per ledger key when saved.
+const (
+	contrarianEntryBytes = 50
+	contrarianKeyBytes   = 14
+)
+
+// estimatedSize estimates the saved size of the cache. Called with lock held.
+func (cc *ContrarianCache) estimatedSize() int64 {
+	size := int64(len(cc.wallets)) * contrarianEntryBytes
+	for _, positions := range cc.ledger {
+		size += int64(len(positions)) * contrarianKeyBytes
+	}
+	return size
+}
+
 // pruneOldest removes entries with the lowest total activity to stay under size limit.
 // Called with lock held.
 func (cc *ContrarianCache) pruneOldest() {
 	// Simple strategy: remove entries with total < 2 first, then < 3, etc.
-	targetSize := int(cc.config.MaxSizeBytes / 50) // ~50 bytes per entry
+	size := cc.estimatedSize()
 
-	for threshold := uint16(2); len(cc.wallets) > targetSize && threshold < 100; threshold++ {
+	for threshold := uint16(2); size > cc.config.MaxSizeBytes && threshold < 100; threshold++ {
 		for addr, stats := range cc.wallets {
 			if stats.Wins+stats.Losses < threshold {
+				size -= contrarianEntryBytes + int64(len(cc.ledger[addr]))*contrarianKeyBytes
 				delete(cc.wallets, addr)
-				if len(cc.wallets) <= targetSize {
+				delete(cc.ledger, addr)
+				delete(cc.legacy, addr)
+				if size <= cc.config.MaxSizeBytes {
 					break
 				}
 			}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"polybot/clients/polymarketapi"
 	"polybot/config"
 	"strconv"
 	"strings"
@@ -14,6 +15,16 @@ import (
 	"go.uber.org/zap"
 )
 
+// newContrarianUpdates returns a builder of updates, each with one closed
+// position the builder hasn't returned before.
+func newContrarianUpdates() func(address string, win bool) walletUpdate {
+	positions := 0
+	return func(address string, win bool) walletUpdate {
+		positions++
+		return walletUpdate{address: address, results: []positionResult{{key: fmt.Sprintf("pos%d", positions), win: win}}}
+	}
+}
+
 func TestContrarianStats(t *testing.T) {
 	stats := ContrarianStats{Wins: 5, Losses: 3}
 
@@ -61,6 +72,7 @@ func TestNewContrarianCache(t *testing.T) {
 }
 
 func TestContrarianCache_RecordAndGet(t *testing.T) {
+	contrarianUpdate := newContrarianUpdates()
 	cfg := &config.Config{
 		Gist: config.GistConfig{
 			Token: "test-token",
@@ -78,9 +90,9 @@ func TestContrarianCache_RecordAndGet(t *testing.T) {
 	cc := NewContrarianCache(zap.NewNop(), cfg)
 
 	// Manually apply updates (simulating what processUpdates does)
-	cc.applyUpdate(walletUpdate{address: "0xabc", win: true})
-	cc.applyUpdate(walletUpdate{address: "0xabc", win: true})
-	cc.applyUpdate(walletUpdate{address: "0xabc", win: false})
+	cc.applyUpdate(contrarianUpdate("0xabc", true))
+	cc.applyUpdate(contrarianUpdate("0xabc", true))
+	cc.applyUpdate(contrarianUpdate("0xabc", false))
 
 	stats, ok := cc.GetStats("0xabc")
 	if !ok {
@@ -95,6 +107,7 @@ func TestContrarianCache_RecordAndGet(t *testing.T) {
 }
 
 func TestContrarianCache_ShouldAlert(t *testing.T) {
+	contrarianUpdate := newContrarianUpdates()
 	cfg := &config.Config{
 		Gist: config.GistConfig{
 			Token: "test-token",
@@ -112,29 +125,29 @@ func TestContrarianCache_ShouldAlert(t *testing.T) {
 	cc := NewContrarianCache(zap.NewNop(), cfg)
 
 	// Wallet with 2 wins - should NOT alert (below MinWins)
-	cc.applyUpdate(walletUpdate{address: "0xlow", win: true})
-	cc.applyUpdate(walletUpdate{address: "0xlow", win: true})
+	cc.applyUpdate(contrarianUpdate("0xlow", true))
+	cc.applyUpdate(contrarianUpdate("0xlow", true))
 
 	if cc.ShouldAlert("0xlow") {
 		t.Error("expected no alert for wallet with only 2 wins")
 	}
 
 	// Wallet with 3 wins, 0 losses (100% rate) - should alert
-	cc.applyUpdate(walletUpdate{address: "0xhigh", win: true})
-	cc.applyUpdate(walletUpdate{address: "0xhigh", win: true})
-	cc.applyUpdate(walletUpdate{address: "0xhigh", win: true})
+	cc.applyUpdate(contrarianUpdate("0xhigh", true))
+	cc.applyUpdate(contrarianUpdate("0xhigh", true))
+	cc.applyUpdate(contrarianUpdate("0xhigh", true))
 
 	if !cc.ShouldAlert("0xhigh") {
 		t.Error("expected alert for wallet with 3 wins at 100% rate")
 	}
 
 	// Wallet with 3 wins, 3 losses (50% rate) - should NOT alert
-	cc.applyUpdate(walletUpdate{address: "0xmid", win: true})
-	cc.applyUpdate(walletUpdate{address: "0xmid", win: true})
-	cc.applyUpdate(walletUpdate{address: "0xmid", win: true})
-	cc.applyUpdate(walletUpdate{address: "0xmid", win: false})
-	cc.applyUpdate(walletUpdate{address: "0xmid", win: false})
-	cc.applyUpdate(walletUpdate{address: "0xmid", win: false})
+	cc.applyUpdate(contrarianUpdate("0xmid", true))
+	cc.applyUpdate(contrarianUpdate("0xmid", true))
+	cc.applyUpdate(contrarianUpdate("0xmid", true))
+	cc.applyUpdate(contrarianUpdate("0xmid", false))
+	cc.applyUpdate(contrarianUpdate("0xmid", false))
+	cc.applyUpdate(contrarianUpdate("0xmid", false))
 
 	if cc.ShouldAlert("0xmid") {
 		t.Error("expected no alert for wallet with 50% rate")
@@ -142,10 +155,10 @@ func TestContrarianCache_ShouldAlert(t *testing.T) {
 
 	// Wallet with 7 wins, 3 losses (70% rate) - should alert
 	for i := 0; i < 7; i++ {
-		cc.applyUpdate(walletUpdate{address: "0xedge", win: true})
+		cc.applyUpdate(contrarianUpdate("0xedge", true))
 	}
 	for i := 0; i < 3; i++ {
-		cc.applyUpdate(walletUpdate{address: "0xedge", win: false})
+		cc.applyUpdate(contrarianUpdate("0xedge", false))
 	}
 
 	if !cc.ShouldAlert("0xedge") {
@@ -154,6 +167,7 @@ func TestContrarianCache_ShouldAlert(t *testing.T) {
 }
 
 func TestContrarianCache_AddressNormalization(t *testing.T) {
+	contrarianUpdate := newContrarianUpdates()
 	cfg := &config.Config{
 		Gist: config.GistConfig{
 			Token: "test-token",
@@ -166,9 +180,9 @@ func TestContrarianCache_AddressNormalization(t *testing.T) {
 
 	cc := NewContrarianCache(zap.NewNop(), cfg)
 
-	// applyUpdate uses address as-is, but RecordContrarianResult lowercases
-	// The walletUpdate struct should have lowercase address (as RecordContrarianResult does)
-	cc.applyUpdate(walletUpdate{address: "0xabc123", win: true})
+	// applyUpdate uses address as-is, but RecordClosedPositions lowercases
+	// The walletUpdate struct should have lowercase address (as RecordClosedPositions does)
+	cc.applyUpdate(contrarianUpdate("0xabc123", true))
 
 	// GetStats lowercases the query, so both should work
 	stats, ok := cc.GetStats("0xabc123")
@@ -296,6 +310,7 @@ func TestIsContrarianPrice(t *testing.T) {
 }
 
 func TestContrarianCache_DirtyFlag(t *testing.T) {
+	contrarianUpdate := newContrarianUpdates()
 	cfg := &config.Config{
 		ContrarianCache: config.ContrarianCacheConfig{
 			GistID:   "test-gist",
@@ -309,7 +324,7 @@ func TestContrarianCache_DirtyFlag(t *testing.T) {
 		t.Error("expected dirty to be false initially")
 	}
 
-	cc.applyUpdate(walletUpdate{address: "0x1", win: true})
+	cc.applyUpdate(contrarianUpdate("0x1", true))
 
 	if !cc.dirty {
 		t.Error("expected dirty to be true after update")
@@ -317,6 +332,7 @@ func TestContrarianCache_DirtyFlag(t *testing.T) {
 }
 
 func TestContrarianCache_Overflow(t *testing.T) {
+	contrarianUpdate := newContrarianUpdates()
 	cfg := &config.Config{
 		ContrarianCache: config.ContrarianCacheConfig{
 			GistID:   "test-gist",
@@ -330,8 +346,8 @@ func TestContrarianCache_Overflow(t *testing.T) {
 	cc.wallets["0x1"] = ContrarianStats{Wins: 65535, Losses: 65535}
 
 	// Apply more updates - should not overflow
-	cc.applyUpdate(walletUpdate{address: "0x1", win: true})
-	cc.applyUpdate(walletUpdate{address: "0x1", win: false})
+	cc.applyUpdate(contrarianUpdate("0x1", true))
+	cc.applyUpdate(contrarianUpdate("0x1", false))
 
 	stats := cc.wallets["0x1"]
 	if stats.Wins != 65535 {
@@ -352,7 +368,7 @@ func TestContrarianCache_RecordContrarianResult_Disabled(t *testing.T) {
 	cc := NewContrarianCache(nil, cfg)
 
 	// Should not panic or block when disabled
-	cc.RecordContrarianResult("0x123", true)
+	cc.RecordClosedPositions("0x123", []polymarketapi.ClosedPosition{{Asset: "1", AvgPrice: 0.1, RealizedPnl: 10}}, 0.2)
 
 	if cc.Size() != 0 {
 		t.Error("expected no entries when disabled")
@@ -484,6 +500,7 @@ func TestContrarianCache_ProcessUpdates_DoneChannel(t *testing.T) {
 }
 
 func TestContrarianCache_ProcessUpdates_AppliesUpdates(t *testing.T) {
+	contrarianUpdate := newContrarianUpdates()
 	cfg := &config.Config{
 		Gist: config.GistConfig{
 			Token: "test-token",
@@ -504,9 +521,9 @@ func TestContrarianCache_ProcessUpdates_AppliesUpdates(t *testing.T) {
 	go cc.processUpdates(ctx)
 
 	// Send updates via channel
-	cc.updateCh <- walletUpdate{address: "0xtest1", win: true}
-	cc.updateCh <- walletUpdate{address: "0xtest1", win: false}
-	cc.updateCh <- walletUpdate{address: "0xtest2", win: true}
+	cc.updateCh <- contrarianUpdate("0xtest1", true)
+	cc.updateCh <- contrarianUpdate("0xtest1", false)
+	cc.updateCh <- contrarianUpdate("0xtest2", true)
 
 	// Give time to process
 	time.Sleep(50 * time.Millisecond)
@@ -529,6 +546,7 @@ func TestContrarianCache_ProcessUpdates_AppliesUpdates(t *testing.T) {
 }
 
 func TestContrarianCache_RecordContrarianResult_ChannelFull(t *testing.T) {
+	contrarianUpdate := newContrarianUpdates()
 	cfg := &config.Config{
 		Gist: config.GistConfig{
 			Token: "test-token",
@@ -544,11 +562,11 @@ func TestContrarianCache_RecordContrarianResult_ChannelFull(t *testing.T) {
 
 	// Fill the channel (buffer size is 1000)
 	for i := 0; i < 1000; i++ {
-		cc.updateCh <- walletUpdate{address: "0xfill", win: true}
+		cc.updateCh <- contrarianUpdate("0xfill", true)
 	}
 
 	// This should not block (non-blocking send with default case)
-	cc.RecordContrarianResult("0xoverflow", true)
+	cc.RecordClosedPositions("0xoverflow", []polymarketapi.ClosedPosition{{Asset: "1", AvgPrice: 0.1, RealizedPnl: 10}}, 0.2)
 
 	// Should complete without blocking
 }
@@ -639,6 +657,7 @@ func TestContrarianCache_PeriodicSave_NotEnabled(t *testing.T) {
 	cc := NewContrarianCache(zap.NewNop(), cfg)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	done := make(chan struct{})
 	go func() {
@@ -651,7 +670,6 @@ func TestContrarianCache_PeriodicSave_NotEnabled(t *testing.T) {
 	case <-done:
 		// Good - returned immediately
 	case <-time.After(50 * time.Millisecond):
-		cancel()
 		t.Error("periodicSave should return immediately when not enabled")
 	}
 }
@@ -1020,3 +1038,141 @@ func TestContrarianCache_Load_NotEnabled(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func newLedgerTestCache(mockGist *MockGistStorage) *ContrarianCache {
+	cfg := &config.Config{
+		ContrarianCache: config.ContrarianCacheConfig{
+			GistID:              "test-gist",
+			FileName:            "contrarian.txt",
+			MaxSizeBytes:        1000000,
+			ContrarianThreshold: 0.20,
+		},
+	}
+	cc := NewContrarianCache(zap.NewNop(), cfg)
+	cc.SetGistClient(mockGist)
+	return cc
+}
+
+func TestContrarianCache_LedgerCountsPositionsOnce(t *testing.T) {
+	cc := newLedgerTestCache(NewMockGistStorage())
+
+	positions := []polymarketapi.ClosedPosition{
+		{Asset: "111", AvgPrice: 0.10, RealizedPnl: 500},  // contrarian win
+		{Asset: "222", AvgPrice: 0.90, RealizedPnl: -200}, // contrarian loss
+		{Asset: "333", AvgPrice: 0.50, RealizedPnl: 100},  // not contrarian
+		{Asset: "444", AvgPrice: 0.05, RealizedPnl: 0},    // unresolved
+	}
+
+	// The same closed positions come back on every stats refetch
+	for i := 0; i < 5; i++ {
+		cc.applyUpdate(walletUpdate{address: "0xabc", results: contrarianResults(positions, 0.20)})
+	}
+	if stats, _ := cc.GetStats("0xabc"); stats.Wins != 1 || stats.Losses != 1 {
+		t.Errorf("expected 1 win and 1 loss, got %+v", stats)
+	}
+
+	// A newly closed position is added
+	positions = append(positions, polymarketapi.ClosedPosition{Asset: "555", AvgPrice: 0.15, RealizedPnl: 50})
+	cc.applyUpdate(walletUpdate{address: "0xabc", results: contrarianResults(positions, 0.20)})
+	if stats, _ := cc.GetStats("0xabc"); stats.Wins != 2 || stats.Losses != 1 {
+		t.Errorf("expected 2 wins and 1 loss, got %+v", stats)
+	}
+
+	// Wallets without contrarian positions aren't tracked
+	cc.applyUpdate(walletUpdate{address: "0xdef", results: contrarianResults(positions[2:4], 0.20)})
+	if _, ok := cc.GetStats("0xdef"); ok {
+		t.Error("expected no entry for a wallet without contrarian positions")
+	}
+}
+
+func TestContrarianCache_LedgerSaveLoad(t *testing.T) {
+	mockGist := NewMockGistStorage()
+	cc := newLedgerTestCache(mockGist)
+
+	positions := []polymarketapi.ClosedPosition{
+		{Asset: "111", AvgPrice: 0.10, RealizedPnl: 500},
+		{Asset: "222", AvgPrice: 0.90, RealizedPnl: -200},
+	}
+	cc.applyUpdate(walletUpdate{address: "0xabc", results: contrarianResults(positions, 0.20)})
+	if err := cc.Save(context.Background()); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if content := mockGist.GetContent("contrarian.txt"); !strings.HasPrefix(content, "0xabc:1:1:") {
+		t.Errorf("unexpected saved content: %q", content)
+	}
+
+	reloaded := newLedgerTestCache(mockGist)
+	if err := reloaded.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Positions recorded before the restart still count once
+	reloaded.applyUpdate(walletUpdate{address: "0xabc", results: contrarianResults(positions, 0.20)})
+	if stats, _ := reloaded.GetStats("0xabc"); stats.Wins != 1 || stats.Losses != 1 {
+		t.Errorf("expected 1 win and 1 loss after reload, got %+v", stats)
+	}
+}
+
+func TestContrarianCache_RecomputeLegacy(t *testing.T) {
+	mockGist := NewMockGistStorage()
+	// Inflated counts from the unkeyed format
+	mockGist.SetContent("contrarian.txt", "0xabc:900:12\n0xdef:40:1\n0xgone:7:0\n")
+	cc := newLedgerTestCache(mockGist)
+	if err := cc.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Legacy stats stay usable until recomputed
+	if stats, _ := cc.GetStats("0xabc"); stats.Wins != 900 {
+		t.Errorf("expected legacy stats before recompute, got %+v", stats)
+	}
+
+	// A regular refetch recomputes a wallet too
+	cc.applyUpdate(walletUpdate{address: "0xdef", results: contrarianResults([]polymarketapi.ClosedPosition{
+		{Asset: "9", AvgPrice: 0.1, RealizedPnl: 5},
+	}, 0.20)})
+
+	var fetched []string
+	cc.RecomputeLegacy(context.Background(), func(ctx context.Context, wallet string) ([]polymarketapi.ClosedPosition, error) {
+		fetched = append(fetched, wallet)
+		if wallet == "0xgone" {
+			return nil, nil
+		}
+		return []polymarketapi.ClosedPosition{
+			{Asset: "1", AvgPrice: 0.1, RealizedPnl: 5},
+			{Asset: "2", AvgPrice: 0.1, RealizedPnl: 5},
+			{Asset: "3", AvgPrice: 0.9, RealizedPnl: -5},
+		}, nil
+	})
+
+	if len(fetched) != 2 {
+		t.Errorf("expected only the pending legacy wallets fetched, got %v", fetched)
+	}
+	if stats, _ := cc.GetStats("0xabc"); stats.Wins != 2 || stats.Losses != 1 {
+		t.Errorf("expected recomputed stats, got %+v", stats)
+	}
+	if stats, _ := cc.GetStats("0xdef"); stats.Wins != 1 || stats.Losses != 0 {
+		t.Errorf("expected refetched stats, got %+v", stats)
+	}
+	if _, ok := cc.GetStats("0xgone"); ok {
+		t.Error("expected a wallet without contrarian positions dropped")
+	}
+	if len(cc.legacy) != 0 || !cc.dirty {
+		t.Errorf("expected no legacy wallets left and a pending save, got %v, dirty=%v", cc.legacy, cc.dirty)
+	}
+}
+
+func TestContrarianPositionKey(t *testing.T) {
+	a := contrarianPositionKey(polymarketapi.ClosedPosition{Asset: "123", ConditionID: "0xc"})
+	b := contrarianPositionKey(polymarketapi.ClosedPosition{Asset: "456", ConditionID: "0xc"})
+	if a == b || len(a) != 12 {
+		t.Errorf("expected distinct 12-char keys per asset, got %q and %q", a, b)
+	}
+
+	// Without an asset, the condition and outcome identify the position
+	yes := contrarianPositionKey(polymarketapi.ClosedPosition{ConditionID: "0xc", OutcomeIndex: 0})
+	no := contrarianPositionKey(polymarketapi.ClosedPosition{ConditionID: "0xc", OutcomeIndex: 1})
+	if yes == no {
+		t.Error("expected distinct keys per outcome")
+	}
+}
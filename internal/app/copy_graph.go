@@ -0,0 +1,37 @@
+package app
+
+import (
+	"strings"
+)
+
+// maxListedCopyPairs caps the pairs and copiers returned by the copy graph view.
+const maxListedCopyPairs = 25
+
+// CopyGraphResult is the result of the copy graph view. Without a wallet it
+// lists the top copiers and pairs; with one it lists the wallet's edges.
+type CopyGraphResult struct {
+	Wallet     string      `json:"wallet,omitempty"`
+	TopCopiers []TopCopier `json:"top_copiers,omitempty"`
+	TopPairs   []CopyEdge  `json:"top_pairs,omitempty"`
+	Leaders    []CopyEdge  `json:"leaders,omitempty"`   // Leaders the wallet copies
+	Followers  []CopyEdge  `json:"followers,omitempty"` // Wallets copying the wallet
+}
+
+// lookupCopyGraph returns the leader/follower pairs of a wallet, or the
+// strongest pairs overall if wallet is empty.
+func lookupCopyGraph(tracker *CopyTracker, wallet string) *CopyGraphResult {
+	wallet = strings.TrimSpace(wallet)
+	if wallet == "" {
+		return &CopyGraphResult{
+			TopCopiers: tracker.GetTopCopiers(maxListedCopyPairs),
+			TopPairs:   tracker.TopPairs(maxListedCopyPairs),
+		}
+	}
+
+	leaders, followers := tracker.WalletEdges(wallet)
+	return &CopyGraphResult{
+		Wallet:    wallet,
+		Leaders:   leaders,
+		Followers: followers,
+	}
+}
@@ -1,18 +1,37 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"polybot/clients/gist"
+
 	"go.uber.org/zap"
 )
 
+// Per-pair limits of the copy graph.
+const (
+	maxCopyLagSamples  = 50 // Most recent lags kept for the median
+	maxCopyPairMarkets = 20 // Most recently matched markets kept
+)
+
 // CopyTrackerConfig holds configuration for copy trading detection.
 type CopyTrackerConfig struct {
 	TimeWindow        time.Duration // How long after a leader trade to consider copies (e.g., 10 min)
 	MinCopyCount      int           // Minimum copy trades to trigger alert (e.g., 3)
 	LeaderMinWinRate  float64       // Minimum win rate to be considered a leader (e.g., 0.70)
 	LeaderMinResolved int           // Minimum resolved positions for win rate leaders
+
+	// Persistence
+	GistID       string
+	FileName     string
+	SaveInterval time.Duration
+	MaxPairs     int // Follower/leader pairs kept, least recently matched dropped first (0 = unlimited)
 }
 
 // DefaultCopyTrackerConfig returns sensible defaults.
@@ -22,29 +41,91 @@ func DefaultCopyTrackerConfig() CopyTrackerConfig {
 		MinCopyCount:      3,
 		LeaderMinWinRate:  0.70,
 		LeaderMinResolved: 5,
+		FileName:          "copy_tracker.json",
+		SaveInterval:      5 * time.Minute,
+		MaxPairs:          5000,
 	}
 }
 
 // LeaderTrade represents a recent trade by a leader wallet.
 type LeaderTrade struct {
-	LeaderAddress string
-	ConditionID   string // market
-	TokenID       string // which outcome
-	Side          string // BUY or SELL
-	Timestamp     time.Time
+	LeaderAddress string    `json:"leader"`
+	ConditionID   string    `json:"condition_id"` // market
+	TokenID       string    `json:"token_id"`     // which outcome
+	Side          string    `json:"side"`         // BUY or SELL
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// CopyEdge is a follower -> leader edge of the copy graph, weighted by how
+// often the follower matched the leader's trades.
+type CopyEdge struct {
+	Follower   string    `json:"follower"`
+	Leader     string    `json:"leader"`
+	Matches    int       `json:"matches"`
+	MedianLag  float64   `json:"median_lag_s"` // Median seconds between the leader's trade and the copy
+	LagSamples []float64 `json:"lag_samples"`  // Most recent lags in seconds
+	Markets    []string  `json:"markets"`      // Most recently matched markets, newest last
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+// CopyLeader is the latest stats of a wallet that qualified as a leader, kept
+// so qualification can be re-evaluated after a restart.
+type CopyLeader struct {
+	Address   string    `json:"address"`
+	WinCount  int       `json:"win_count"`
+	LossCount int       `json:"loss_count"`
+	WinRate   float64   `json:"win_rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CopyTrackerSnapshot is the persisted state format.
+type CopyTrackerSnapshot struct {
+	Version      int                   `json:"version"`
+	Timestamp    time.Time             `json:"timestamp"`
+	Edges        []CopyEdge            `json:"edges"`
+	Leaders      map[string]CopyLeader `json:"leaders"`       // address -> latest leader stats
+	LeaderTrades []LeaderTrade         `json:"leader_trades"` // leader trades still within the time window
+}
+
+// TopCopier is a follower and its total copy trades across leaders.
+type TopCopier struct {
+	Address   string `json:"address"`
+	CopyCount int    `json:"copy_count"`
+	Leaders   int    `json:"leaders"` // Distinct leaders copied
 }
 
 // CopyTracker detects potential copy trading behavior.
 // It tracks recent trades from "leader" wallets (high win rate or contrarian winners)
 // and flags wallets that consistently trade shortly after leaders.
+// The follower/leader pairs form a weighted graph that is persisted so copy
+// relationships survive restarts.
 type CopyTracker struct {
 	logger          *zap.Logger
 	config          CopyTrackerConfig
 	contrarianCache *ContrarianCache
+	gistClient      gist.Storage
 
 	mu                 sync.RWMutex
-	recentLeaderTrades []LeaderTrade  // trades from leaders in the time window
-	copyCount          map[string]int // wallet address -> number of detected copy trades
+	recentLeaderTrades []LeaderTrade                   // trades from leaders in the time window
+	copyCount          map[string]int                  // wallet address -> number of detected copy trades
+	edges              map[string]map[string]*CopyEdge // follower -> leader -> edge
+	pairs              int                             // number of edges
+	leaders            map[string]CopyLeader           // address -> latest leader stats
+
+	// Persistence
+	dirty  bool
+	doneCh chan struct{}
+
+	// now returns the current time (the tape's time during replays)
+	now func() time.Time
+}
+
+// CopyPair is a follower that has copied a leader.
+type CopyPair struct {
+	Follower string
+	Leader   string
+	Copies   int
 }
 
 // NewCopyTracker creates a new copy trading detector.
@@ -63,15 +144,75 @@ func NewCopyTracker(
 		contrarianCache:    contrarianCache,
 		recentLeaderTrades: make([]LeaderTrade, 0),
 		copyCount:          make(map[string]int),
+		edges:              make(map[string]map[string]*CopyEdge),
+		leaders:            make(map[string]CopyLeader),
+		doneCh:             make(chan struct{}),
+		now:                time.Now,
 	}
 }
 
+// SetGistClient sets the storage used to persist the copy graph.
+func (ct *CopyTracker) SetGistClient(client gist.Storage) {
+	ct.gistClient = client
+}
+
+// IsEnabled returns true if persistence is configured.
+func (ct *CopyTracker) IsEnabled() bool {
+	return ct.gistClient != nil && ct.config.GistID != ""
+}
+
+// Start begins periodic saving.
+func (ct *CopyTracker) Start(ctx context.Context) {
+	go ct.periodicSave(ctx)
+}
+
+// Stop gracefully shuts down, saving pending changes.
+func (ct *CopyTracker) Stop() {
+	close(ct.doneCh)
+}
+
 // IsLeader checks if a wallet qualifies as a "leader" based on win rate or contrarian history.
+// The stats of qualifying wallets are remembered while they qualify, so
+// qualification can be re-evaluated when the graph is loaded. They are only
+// rewritten when they change.
 func (ct *CopyTracker) IsLeader(walletStats *WalletStats) bool {
 	if walletStats == nil {
 		return false
 	}
 
+	qualifies := ct.qualifies(walletStats)
+
+	ct.mu.RLock()
+	leader, known := ct.leaders[walletStats.Wallet]
+	ct.mu.RUnlock()
+	changed := leader.WinCount != walletStats.WinCount ||
+		leader.LossCount != walletStats.LossCount ||
+		leader.WinRate != walletStats.WinRate
+	if known == qualifies && !(qualifies && changed) {
+		return qualifies
+	}
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if qualifies {
+		ct.leaders[walletStats.Wallet] = CopyLeader{
+			Address:   walletStats.Wallet,
+			WinCount:  walletStats.WinCount,
+			LossCount: walletStats.LossCount,
+			WinRate:   walletStats.WinRate,
+			UpdatedAt: ct.now(),
+		}
+	} else {
+		delete(ct.leaders, walletStats.Wallet)
+	}
+	ct.dirty = true
+
+	return qualifies
+}
+
+// qualifies checks the leader thresholds without recording anything.
+func (ct *CopyTracker) qualifies(walletStats *WalletStats) bool {
+
 	// Check high win rate
 	resolvedCount := walletStats.WinCount + walletStats.LossCount
 	if resolvedCount >= ct.config.LeaderMinResolved &&
@@ -97,8 +238,9 @@ func (ct *CopyTracker) RecordLeaderTrade(leaderAddress, conditionID, tokenID, si
 		ConditionID:   conditionID,
 		TokenID:       tokenID,
 		Side:          side,
-		Timestamp:     time.Now(),
+		Timestamp:     ct.now(),
 	})
+	ct.dirty = true
 
 	ct.logger.Debug("recorded leader trade",
 		zap.String("leader", shortID(leaderAddress)),
@@ -114,7 +256,7 @@ func (ct *CopyTracker) CheckForCopy(followerAddress, conditionID, tokenID, side
 	ct.mu.Lock()
 	defer ct.mu.Unlock()
 
-	cutoff := time.Now().Add(-ct.config.TimeWindow)
+	cutoff := ct.now().Add(-ct.config.TimeWindow)
 
 	// Look for matching leader trade
 	for _, lt := range ct.recentLeaderTrades {
@@ -130,6 +272,7 @@ func (ct *CopyTracker) CheckForCopy(followerAddress, conditionID, tokenID, side
 			lt.LeaderAddress != followerAddress {
 			// Found a match - this is a potential copy trade
 			ct.copyCount[followerAddress]++
+			ct.recordCopy(followerAddress, lt)
 
 			ct.logger.Debug("detected potential copy trade",
 				zap.String("follower", shortID(followerAddress)),
@@ -145,6 +288,96 @@ func (ct *CopyTracker) CheckForCopy(followerAddress, conditionID, tokenID, side
 	return false, ""
 }
 
+// recordCopy adds a match to the follower -> leader edge. Must hold ct.mu.
+func (ct *CopyTracker) recordCopy(followerAddress string, lt LeaderTrade) {
+	now := ct.now()
+	leaders, ok := ct.edges[followerAddress]
+	if !ok {
+		leaders = make(map[string]*CopyEdge)
+		ct.edges[followerAddress] = leaders
+	}
+	edge, ok := leaders[lt.LeaderAddress]
+	if !ok {
+		edge = &CopyEdge{Follower: followerAddress, Leader: lt.LeaderAddress, FirstSeen: now}
+		leaders[lt.LeaderAddress] = edge
+		ct.pairs++
+	}
+
+	edge.Matches++
+	edge.LastSeen = now
+	edge.LagSamples = append(edge.LagSamples, now.Sub(lt.Timestamp).Seconds())
+	if len(edge.LagSamples) > maxCopyLagSamples {
+		edge.LagSamples = edge.LagSamples[len(edge.LagSamples)-maxCopyLagSamples:]
+	}
+	edge.MedianLag = medianOf(edge.LagSamples)
+
+	// Move the market to the end so the oldest is dropped first
+	for i, market := range edge.Markets {
+		if market == lt.ConditionID {
+			edge.Markets = append(edge.Markets[:i], edge.Markets[i+1:]...)
+			break
+		}
+	}
+	edge.Markets = append(edge.Markets, lt.ConditionID)
+	if len(edge.Markets) > maxCopyPairMarkets {
+		edge.Markets = edge.Markets[len(edge.Markets)-maxCopyPairMarkets:]
+	}
+
+	ct.dirty = true
+	ct.prunePairs()
+}
+
+// prunePairs drops the least recently matched edges beyond MaxPairs. Must hold ct.mu.
+func (ct *CopyTracker) prunePairs() {
+	if ct.config.MaxPairs <= 0 || ct.pairs <= ct.config.MaxPairs {
+		return
+	}
+
+	all := make([]*CopyEdge, 0, ct.pairs)
+	for _, leaders := range ct.edges {
+		for _, edge := range leaders {
+			all = append(all, edge)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].LastSeen.Before(all[j].LastSeen) })
+
+	for _, edge := range all[:ct.pairs-ct.config.MaxPairs] {
+		ct.removeEdge(edge)
+	}
+}
+
+// removeEdge deletes an edge and its matches from the follower's copy count. Must hold ct.mu.
+func (ct *CopyTracker) removeEdge(edge *CopyEdge) {
+	leaders := ct.edges[edge.Follower]
+	if _, ok := leaders[edge.Leader]; !ok {
+		return
+	}
+	delete(leaders, edge.Leader)
+	if len(leaders) == 0 {
+		delete(ct.edges, edge.Follower)
+	}
+	ct.pairs--
+
+	ct.copyCount[edge.Follower] -= edge.Matches
+	if ct.copyCount[edge.Follower] <= 0 {
+		delete(ct.copyCount, edge.Follower)
+	}
+}
+
+// medianOf returns the median of values, or 0 if there are none.
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
 // ShouldAlert returns true if the follower has copied leaders enough times to warrant an alert.
 func (ct *CopyTracker) ShouldAlert(followerAddress string) bool {
 	ct.mu.RLock()
@@ -167,7 +400,7 @@ func (ct *CopyTracker) PruneOldTrades() int {
 	ct.mu.Lock()
 	defer ct.mu.Unlock()
 
-	cutoff := time.Now().Add(-ct.config.TimeWindow)
+	cutoff := ct.now().Add(-ct.config.TimeWindow)
 	pruned := 0
 
 	// Filter to keep only recent trades
@@ -199,47 +432,286 @@ func (ct *CopyTracker) ResetCopyCount(walletAddress string) {
 	defer ct.mu.Unlock()
 
 	delete(ct.copyCount, walletAddress)
+	ct.pairs -= len(ct.edges[walletAddress])
+	delete(ct.edges, walletAddress)
+	ct.dirty = true
 }
 
-// GetTopCopiers returns wallets with the highest copy counts.
-func (ct *CopyTracker) GetTopCopiers(limit int) []struct {
-	Address   string
-	CopyCount int
-} {
+// CopyPairs returns the follower/leader pairs with at least minCopies copies.
+func (ct *CopyTracker) CopyPairs(minCopies int) []CopyPair {
 	ct.mu.RLock()
 	defer ct.mu.RUnlock()
 
-	type copier struct {
-		Address   string
-		CopyCount int
+	var pairs []CopyPair
+	for follower, leaders := range ct.edges {
+		for leader, edge := range leaders {
+			if edge.Matches >= minCopies {
+				pairs = append(pairs, CopyPair{Follower: follower, Leader: leader, Copies: edge.Matches})
+			}
+		}
 	}
+	return pairs
+}
 
-	copiers := make([]copier, 0, len(ct.copyCount))
+// GetTopCopiers returns wallets with the highest copy counts.
+func (ct *CopyTracker) GetTopCopiers(limit int) []TopCopier {
+	ct.mu.RLock()
+	defer ct.mu.RUnlock()
+
+	copiers := make([]TopCopier, 0, len(ct.copyCount))
 	for addr, count := range ct.copyCount {
-		copiers = append(copiers, copier{Address: addr, CopyCount: count})
+		copiers = append(copiers, TopCopier{Address: addr, CopyCount: count, Leaders: len(ct.edges[addr])})
 	}
+	sort.Slice(copiers, func(i, j int) bool {
+		if copiers[i].CopyCount != copiers[j].CopyCount {
+			return copiers[i].CopyCount > copiers[j].CopyCount
+		}
+		return copiers[i].Address < copiers[j].Address
+	})
+
+	if limit > len(copiers) {
+		limit = len(copiers)
+	}
+	return copiers[:limit]
+}
 
-	// Sort by count descending (simple bubble sort for small lists)
-	for i := 0; i < len(copiers)-1; i++ {
-		for j := i + 1; j < len(copiers); j++ {
-			if copiers[j].CopyCount > copiers[i].CopyCount {
-				copiers[i], copiers[j] = copiers[j], copiers[i]
+// TopPairs returns the follower/leader edges with the most matches.
+func (ct *CopyTracker) TopPairs(limit int) []CopyEdge {
+	ct.mu.RLock()
+	defer ct.mu.RUnlock()
+
+	pairs := make([]CopyEdge, 0, ct.pairs)
+	for _, leaders := range ct.edges {
+		for _, edge := range leaders {
+			pairs = append(pairs, copyEdge(edge))
+		}
+	}
+	sortCopyEdges(pairs)
+
+	if limit > len(pairs) {
+		limit = len(pairs)
+	}
+	return pairs[:limit]
+}
+
+// WalletEdges returns the edges of a wallet: the leaders it copies and the
+// followers copying it, most matches first. Addresses match case-insensitively.
+func (ct *CopyTracker) WalletEdges(wallet string) (leaders, followers []CopyEdge) {
+	ct.mu.RLock()
+	defer ct.mu.RUnlock()
+
+	leaders, followers = []CopyEdge{}, []CopyEdge{}
+	for follower, byLeader := range ct.edges {
+		for leader, edge := range byLeader {
+			if strings.EqualFold(follower, wallet) {
+				leaders = append(leaders, copyEdge(edge))
+			}
+			if strings.EqualFold(leader, wallet) {
+				followers = append(followers, copyEdge(edge))
 			}
 		}
 	}
+	sortCopyEdges(leaders)
+	sortCopyEdges(followers)
+	return leaders, followers
+}
 
-	if limit > len(copiers) {
-		limit = len(copiers)
+// copyEdge returns a copy of an edge that doesn't share its slices.
+func copyEdge(edge *CopyEdge) CopyEdge {
+	c := *edge
+	c.LagSamples = append([]float64(nil), edge.LagSamples...)
+	c.Markets = append([]string(nil), edge.Markets...)
+	return c
+}
+
+// sortCopyEdges sorts edges by matches, most recently matched first on ties.
+func sortCopyEdges(edges []CopyEdge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Matches != edges[j].Matches {
+			return edges[i].Matches > edges[j].Matches
+		}
+		return edges[i].LastSeen.After(edges[j].LastSeen)
+	})
+}
+
+// periodicSave saves state periodically.
+func (ct *CopyTracker) periodicSave(ctx context.Context) {
+	ticker := time.NewTicker(ct.config.SaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Final save on shutdown
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = ct.Save(saveCtx)
+			cancel()
+			return
+		case <-ct.doneCh:
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = ct.Save(saveCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			if err := ct.Save(ctx); err != nil {
+				ct.logger.Warn("failed to save copy tracker state", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Load loads the copy graph from gist. Leaders are re-evaluated against the
+// current thresholds; edges to wallets that no longer qualify are dropped.
+func (ct *CopyTracker) Load(ctx context.Context) error {
+	if !ct.IsEnabled() {
+		return nil
 	}
 
-	result := make([]struct {
-		Address   string
-		CopyCount int
-	}, limit)
-	for i := 0; i < limit; i++ {
-		result[i].Address = copiers[i].Address
-		result[i].CopyCount = copiers[i].CopyCount
+	content, err := ct.gistClient.Load(ctx, ct.config.FileName, ct.config.GistID)
+	if err != nil {
+		return fmt.Errorf("load copy tracker: %w", err)
 	}
 
-	return result
+	if content == "" {
+		return nil
+	}
+
+	var snapshot CopyTrackerSnapshot
+	if err := json.Unmarshal([]byte(content), &snapshot); err != nil {
+		return fmt.Errorf("unmarshal copy tracker: %w", err)
+	}
+
+	// Re-evaluate every leader before taking the lock (the contrarian cache has its own)
+	qualified := make(map[string]bool)
+	evaluate := func(address string) {
+		if _, seen := qualified[address]; seen {
+			return
+		}
+		leader := snapshot.Leaders[address]
+		qualified[address] = ct.qualifies(&WalletStats{
+			Wallet:    address,
+			WinCount:  leader.WinCount,
+			LossCount: leader.LossCount,
+			WinRate:   leader.WinRate,
+		})
+	}
+	for address := range snapshot.Leaders {
+		evaluate(address)
+	}
+	for _, edge := range snapshot.Edges {
+		evaluate(edge.Leader)
+	}
+	for _, lt := range snapshot.LeaderTrades {
+		evaluate(lt.LeaderAddress)
+	}
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	ct.edges = make(map[string]map[string]*CopyEdge)
+	ct.copyCount = make(map[string]int)
+	ct.pairs = 0
+	dropped := 0
+	for i := range snapshot.Edges {
+		edge := snapshot.Edges[i]
+		if !qualified[edge.Leader] || edge.Matches <= 0 {
+			dropped++
+			continue
+		}
+		leaders, ok := ct.edges[edge.Follower]
+		if !ok {
+			leaders = make(map[string]*CopyEdge)
+			ct.edges[edge.Follower] = leaders
+		}
+		leaders[edge.Leader] = &edge
+		ct.copyCount[edge.Follower] += edge.Matches
+		ct.pairs++
+	}
+
+	ct.leaders = make(map[string]CopyLeader)
+	for address, leader := range snapshot.Leaders {
+		if qualified[address] {
+			ct.leaders[address] = leader
+		}
+	}
+
+	cutoff := ct.now().Add(-ct.config.TimeWindow)
+	ct.recentLeaderTrades = make([]LeaderTrade, 0, len(snapshot.LeaderTrades))
+	for _, lt := range snapshot.LeaderTrades {
+		if lt.Timestamp.After(cutoff) && qualified[lt.LeaderAddress] {
+			ct.recentLeaderTrades = append(ct.recentLeaderTrades, lt)
+		}
+	}
+
+	ct.prunePairs()
+	ct.dirty = dropped > 0
+
+	ct.logger.Info("loaded copy tracker state",
+		zap.Int("pairs", ct.pairs),
+		zap.Int("followers", len(ct.copyCount)),
+		zap.Int("droppedPairs", dropped),
+		zap.Int("leaderTrades", len(ct.recentLeaderTrades)),
+	)
+
+	return nil
+}
+
+// Save saves the copy graph to gist.
+func (ct *CopyTracker) Save(ctx context.Context) error {
+	if !ct.IsEnabled() {
+		return nil
+	}
+
+	ct.mu.Lock()
+	if !ct.dirty {
+		ct.mu.Unlock()
+		return nil
+	}
+
+	snapshot := CopyTrackerSnapshot{
+		Version:      1,
+		Timestamp:    ct.now(),
+		Edges:        make([]CopyEdge, 0, ct.pairs),
+		Leaders:      make(map[string]CopyLeader, len(ct.leaders)),
+		LeaderTrades: make([]LeaderTrade, 0, len(ct.recentLeaderTrades)),
+	}
+	for _, leaders := range ct.edges {
+		for _, edge := range leaders {
+			snapshot.Edges = append(snapshot.Edges, copyEdge(edge))
+		}
+	}
+	for address, leader := range ct.leaders {
+		snapshot.Leaders[address] = leader
+	}
+	cutoff := ct.now().Add(-ct.config.TimeWindow)
+	for _, lt := range ct.recentLeaderTrades {
+		if lt.Timestamp.After(cutoff) {
+			snapshot.LeaderTrades = append(snapshot.LeaderTrades, lt)
+		}
+	}
+
+	ct.dirty = false
+	ct.mu.Unlock()
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		ct.mu.Lock()
+		ct.dirty = true
+		ct.mu.Unlock()
+		return fmt.Errorf("marshal copy tracker: %w", err)
+	}
+
+	if err := ct.gistClient.Save(ctx, ct.config.FileName, string(data), ct.config.GistID); err != nil {
+		ct.mu.Lock()
+		ct.dirty = true
+		ct.mu.Unlock()
+		return fmt.Errorf("save copy tracker: %w", err)
+	}
+
+	ct.logger.Debug("saved copy tracker state",
+		zap.Int("pairs", len(snapshot.Edges)),
+		zap.Int("leaders", len(snapshot.Leaders)),
+	)
+
+	return nil
 }
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -82,6 +84,42 @@ func TestCopyTracker_IsLeader_HighWinRate(t *testing.T) {
 	}
 }
 
+func TestCopyTracker_IsLeader_RecordsChanges(t *testing.T) {
+	tracker := NewCopyTracker(zap.NewNop(), DefaultCopyTrackerConfig(), nil)
+	stats := &WalletStats{Wallet: "0xleader", WinCount: 6, LossCount: 2, WinRate: 0.75}
+
+	if !tracker.IsLeader(stats) || !tracker.dirty {
+		t.Fatal("expected a new leader recorded")
+	}
+
+	// Unchanged stats leave the tracker clean
+	tracker.dirty = false
+	if !tracker.IsLeader(stats) || tracker.dirty {
+		t.Error("expected unchanged stats not to mark the tracker dirty")
+	}
+
+	tracker.IsLeader(&WalletStats{Wallet: "0xleader", WinCount: 7, LossCount: 2, WinRate: 7.0 / 9})
+	if !tracker.dirty || tracker.leaders["0xleader"].WinCount != 7 {
+		t.Errorf("expected changed stats recorded, got %+v", tracker.leaders["0xleader"])
+	}
+
+	// A leader that stops qualifying is forgotten
+	tracker.dirty = false
+	if tracker.IsLeader(&WalletStats{Wallet: "0xleader", WinCount: 7, LossCount: 7, WinRate: 0.5}) {
+		t.Fatal("expected 0xleader to no longer qualify")
+	}
+	if _, ok := tracker.leaders["0xleader"]; ok || !tracker.dirty {
+		t.Error("expected the leader dropped")
+	}
+
+	// Leader trades are persisted too
+	tracker.dirty = false
+	tracker.RecordLeaderTrade("0xother", "cond1", "token1", "BUY")
+	if !tracker.dirty {
+		t.Error("expected a leader trade to mark the tracker dirty")
+	}
+}
+
 func TestCopyTracker_RecordLeaderTrade(t *testing.T) {
 	cfg := DefaultCopyTrackerConfig()
 	tracker := NewCopyTracker(zap.NewNop(), cfg, nil)
@@ -314,3 +352,188 @@ func TestCopyTracker_CheckForCopy_ExpiredTrade(t *testing.T) {
 		t.Error("expected expired leader trade to not be matched")
 	}
 }
+
+func TestCopyTracker_CopyPairs(t *testing.T) {
+	tracker := NewCopyTracker(zap.NewNop(), DefaultCopyTrackerConfig(), nil)
+
+	tracker.RecordLeaderTrade("0xleader", "cond1", "token1", "BUY")
+	tracker.CheckForCopy("0xfollower", "cond1", "token1", "BUY")
+	tracker.CheckForCopy("0xfollower", "cond1", "token1", "BUY")
+	tracker.CheckForCopy("0xother", "cond1", "token1", "BUY")
+
+	pairs := tracker.CopyPairs(2)
+	if len(pairs) != 1 {
+		t.Fatalf("expected 1 pair with 2+ copies, got %+v", pairs)
+	}
+	if pairs[0].Follower != "0xfollower" || pairs[0].Leader != "0xleader" || pairs[0].Copies != 2 {
+		t.Errorf("unexpected pair: %+v", pairs[0])
+	}
+
+	tracker.ResetCopyCount("0xfollower")
+	if pairs := tracker.CopyPairs(1); len(pairs) != 1 || pairs[0].Follower != "0xother" {
+		t.Errorf("expected reset follower dropped, got %+v", pairs)
+	}
+}
+
+func TestCopyTracker_Graph(t *testing.T) {
+	tracker := NewCopyTracker(zap.NewNop(), DefaultCopyTrackerConfig(), nil)
+
+	tracker.RecordLeaderTrade("0xleader", "cond1", "token1", "BUY")
+	tracker.RecordLeaderTrade("0xleader", "cond2", "token2", "BUY")
+	tracker.RecordLeaderTrade("0xother", "cond3", "token3", "SELL")
+	tracker.CheckForCopy("0xfollower", "cond1", "token1", "BUY")
+	tracker.CheckForCopy("0xfollower", "cond2", "token2", "BUY")
+	tracker.CheckForCopy("0xfollower", "cond1", "token1", "BUY")
+	tracker.CheckForCopy("0xfollower", "cond3", "token3", "SELL")
+
+	pairs := tracker.TopPairs(10)
+	if len(pairs) != 2 {
+		t.Fatalf("expected 2 pairs, got %+v", pairs)
+	}
+	top := pairs[0]
+	if top.Follower != "0xfollower" || top.Leader != "0xleader" || top.Matches != 3 {
+		t.Errorf("unexpected top pair: %+v", top)
+	}
+	// Markets are deduplicated, most recently matched last
+	if len(top.Markets) != 2 || top.Markets[0] != "cond2" || top.Markets[1] != "cond1" {
+		t.Errorf("unexpected markets: %v", top.Markets)
+	}
+	if len(top.LagSamples) != 3 || top.MedianLag != medianOf(top.LagSamples) {
+		t.Errorf("unexpected lags: %v, median %v", top.LagSamples, top.MedianLag)
+	}
+
+	copiers := tracker.GetTopCopiers(1)
+	if len(copiers) != 1 || copiers[0].CopyCount != 4 || copiers[0].Leaders != 2 {
+		t.Errorf("unexpected top copiers: %+v", copiers)
+	}
+
+	// Drill-down matches addresses case-insensitively
+	leaders, followers := tracker.WalletEdges("0xLEADER")
+	if len(leaders) != 0 || len(followers) != 1 || followers[0].Follower != "0xfollower" {
+		t.Errorf("unexpected leader edges: %+v %+v", leaders, followers)
+	}
+	graph := lookupCopyGraph(tracker, " 0xfollower ")
+	if graph.Wallet != "0xfollower" || len(graph.Leaders) != 2 || len(graph.Followers) != 0 || graph.TopPairs != nil {
+		t.Errorf("unexpected drill-down: %+v", graph)
+	}
+	if graph := lookupCopyGraph(tracker, ""); len(graph.TopPairs) != 2 || len(graph.TopCopiers) != 1 {
+		t.Errorf("unexpected overview: %+v", graph)
+	}
+}
+
+func TestMedianOf(t *testing.T) {
+	for _, tc := range []struct {
+		values []float64
+		want   float64
+	}{
+		{nil, 0},
+		{[]float64{5}, 5},
+		{[]float64{9, 1, 4}, 4},
+		{[]float64{8, 2, 4, 6}, 5},
+	} {
+		if got := medianOf(tc.values); got != tc.want {
+			t.Errorf("medianOf(%v) = %v, want %v", tc.values, got, tc.want)
+		}
+	}
+}
+
+func TestCopyTracker_MaxPairs(t *testing.T) {
+	cfg := DefaultCopyTrackerConfig()
+	cfg.MaxPairs = 2
+	tracker := NewCopyTracker(zap.NewNop(), cfg, nil)
+
+	tracker.RecordLeaderTrade("0xleader", "cond1", "token1", "BUY")
+	for _, follower := range []string{"0xa", "0xb", "0xa", "0xc"} {
+		tracker.CheckForCopy(follower, "cond1", "token1", "BUY")
+		time.Sleep(time.Millisecond)
+	}
+
+	// 0xb was matched least recently and is dropped with its copy count
+	if pairs := tracker.CopyPairs(1); len(pairs) != 2 {
+		t.Errorf("expected 2 pairs kept, got %+v", pairs)
+	}
+	if tracker.GetCopyCount("0xb") != 0 || tracker.GetCopyCount("0xa") != 2 {
+		t.Errorf("unexpected copy counts: b=%d a=%d", tracker.GetCopyCount("0xb"), tracker.GetCopyCount("0xa"))
+	}
+}
+
+func TestCopyTracker_SaveLoad(t *testing.T) {
+	cfg := DefaultCopyTrackerConfig()
+	cfg.GistID = "test-gist"
+	mockGist := NewMockGistStorage()
+
+	tracker := NewCopyTracker(zap.NewNop(), cfg, nil)
+	tracker.SetGistClient(mockGist)
+	if !tracker.IsEnabled() {
+		t.Fatal("expected persistence enabled")
+	}
+
+	strong := &WalletStats{Wallet: "0xstrong", WinCount: 9, LossCount: 1, WinRate: 0.90}
+	weak := &WalletStats{Wallet: "0xweak", WinCount: 6, LossCount: 2, WinRate: 0.75}
+	for _, leader := range []*WalletStats{strong, weak} {
+		if !tracker.IsLeader(leader) {
+			t.Fatalf("expected %s to be a leader", leader.Wallet)
+		}
+		tracker.RecordLeaderTrade(leader.Wallet, "cond-"+leader.Wallet, "token1", "BUY")
+		tracker.CheckForCopy("0xfollower", "cond-"+leader.Wallet, "token1", "BUY")
+	}
+	tracker.CheckForCopy("0xfollower", "cond-0xstrong", "token1", "BUY")
+
+	if err := tracker.Save(context.Background()); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if mockGist.GetContent("copy_tracker.json") == "" {
+		t.Fatal("expected the graph saved")
+	}
+
+	// Same thresholds: the whole graph comes back, including recent leader trades
+	loaded := NewCopyTracker(zap.NewNop(), cfg, nil)
+	loaded.SetGistClient(mockGist)
+	if err := loaded.Load(context.Background()); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if loaded.GetCopyCount("0xfollower") != 3 || len(loaded.TopPairs(10)) != 2 {
+		t.Errorf("unexpected loaded graph: %d copies, %+v", loaded.GetCopyCount("0xfollower"), loaded.TopPairs(10))
+	}
+	// Nothing changed since loading
+	mockGist.SetSaveError(errors.New("unexpected save"))
+	if err := loaded.Save(context.Background()); err != nil {
+		t.Errorf("expected no save when clean, got %v", err)
+	}
+	mockGist.SetSaveError(nil)
+	if isCopy, leader := loaded.CheckForCopy("0xother", "cond-0xstrong", "token1", "BUY"); !isCopy || leader != "0xstrong" {
+		t.Error("expected leader trades restored")
+	}
+
+	// Stricter thresholds: the weak leader no longer qualifies and its edges are dropped
+	strict := cfg
+	strict.LeaderMinWinRate = 0.85
+	reevaluated := NewCopyTracker(zap.NewNop(), strict, nil)
+	reevaluated.SetGistClient(mockGist)
+	if err := reevaluated.Load(context.Background()); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	pairs := reevaluated.TopPairs(10)
+	if len(pairs) != 1 || pairs[0].Leader != "0xstrong" || pairs[0].Matches != 2 {
+		t.Errorf("expected only the strong leader kept, got %+v", pairs)
+	}
+	if reevaluated.GetCopyCount("0xfollower") != 2 {
+		t.Errorf("expected copy count rebuilt from kept pairs, got %d", reevaluated.GetCopyCount("0xfollower"))
+	}
+	if _, ok := reevaluated.leaders["0xweak"]; ok {
+		t.Error("expected weak leader record dropped")
+	}
+}
+
+func TestCopyTracker_Load_NotEnabled(t *testing.T) {
+	tracker := NewCopyTracker(zap.NewNop(), DefaultCopyTrackerConfig(), nil)
+	if tracker.IsEnabled() {
+		t.Error("expected persistence disabled without storage")
+	}
+	if err := tracker.Load(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := tracker.Save(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"polybot/clients/gist"
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// DeadLetterQueueConfig holds configuration for the notification dead-letter queue.
+type DeadLetterQueueConfig struct {
+	GistID       string
+	FileName     string
+	SaveInterval time.Duration
+	MaxEntries   int // Max dead letters kept (oldest dropped first)
+}
+
+// DefaultDeadLetterQueueConfig returns sensible defaults.
+func DefaultDeadLetterQueueConfig() DeadLetterQueueConfig {
+	return DeadLetterQueueConfig{
+		FileName:     "notifier_dead_letters.json",
+		SaveInterval: 1 * time.Minute,
+		MaxEntries:   500,
+	}
+}
+
+// DeadLetterSnapshot is the persisted state format.
+type DeadLetterSnapshot struct {
+	Version     int                   `json:"version"`
+	Timestamp   time.Time             `json:"timestamp"`
+	DeadLetters []notifier.DeadLetter `json:"dead_letters"`
+}
+
+// DeadLetterQueue keeps alerts that notification channels failed to deliver,
+// persisted so they survive restarts. On startup they are redelivered to
+// their channels; dead letters of channels that no longer exist are kept for
+// inspection.
+type DeadLetterQueue struct {
+	logger     *zap.Logger
+	gistClient gist.Storage
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   DeadLetterQueueConfig
+
+	mu      sync.RWMutex
+	entries []notifier.DeadLetter // oldest first
+
+	// Persistence
+	dirty  bool
+	doneCh chan struct{}
+}
+
+// NewDeadLetterQueue creates a new dead-letter queue.
+func NewDeadLetterQueue(logger *zap.Logger, gistClient gist.Storage, config DeadLetterQueueConfig) *DeadLetterQueue {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &DeadLetterQueue{
+		logger:     logger.Named("dead-letters"),
+		gistClient: gistClient,
+		config:     config,
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// IsEnabled returns true if dead letters are persisted.
+func (q *DeadLetterQueue) IsEnabled() bool {
+	cfg := q.getConfig()
+	return q.gistClient != nil && cfg.GistID != ""
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (q *DeadLetterQueue) getConfig() DeadLetterQueueConfig {
+	q.configMu.RLock()
+	defer q.configMu.RUnlock()
+	return q.config
+}
+
+// UpdateConfig updates the dead-letter queue config.
+func (q *DeadLetterQueue) UpdateConfig(cfg DeadLetterQueueConfig) {
+	q.configMu.Lock()
+	defer q.configMu.Unlock()
+	q.config = cfg
+}
+
+// Start begins periodic saving.
+func (q *DeadLetterQueue) Start(ctx context.Context) {
+	go q.periodicSave(ctx)
+}
+
+// Stop gracefully shuts down, saving pending changes.
+func (q *DeadLetterQueue) Stop() {
+	close(q.doneCh)
+}
+
+// Add records an undeliverable alert. Usable as a notifier.DeadLetterHandler.
+func (q *DeadLetterQueue) Add(dl notifier.DeadLetter) {
+	cfg := q.getConfig()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.entries = append(q.entries, dl)
+	if cfg.MaxEntries > 0 && len(q.entries) > cfg.MaxEntries {
+		q.entries = q.entries[len(q.entries)-cfg.MaxEntries:]
+	}
+	q.dirty = true
+}
+
+// Entries returns a copy of the dead letters, oldest first.
+func (q *DeadLetterQueue) Entries() []notifier.DeadLetter {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	entries := make([]notifier.DeadLetter, len(q.entries))
+	copy(entries, q.entries)
+	return entries
+}
+
+// Redeliver requeues the dead letters of the given channels, keyed by name,
+// and removes them from the queue. Alerts that fail again are dead-lettered
+// anew. Returns the number of alerts requeued.
+func (q *DeadLetterQueue) Redeliver(channels map[string]notifier.Notifier) int {
+	q.mu.Lock()
+	var redeliver, kept []notifier.DeadLetter
+	for _, dl := range q.entries {
+		if _, ok := channels[dl.Channel]; ok {
+			redeliver = append(redeliver, dl)
+		} else {
+			kept = append(kept, dl)
+		}
+	}
+	if len(redeliver) > 0 {
+		q.entries = kept
+		q.dirty = true
+	}
+	q.mu.Unlock()
+
+	// Send outside the lock: a full channel queue dead-letters straight back
+	for _, dl := range redeliver {
+		channels[dl.Channel].SendTradeAlert(dl.Alert)
+	}
+	return len(redeliver)
+}
+
+// Count returns the number of dead letters.
+func (q *DeadLetterQueue) Count() int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return len(q.entries)
+}
+
+// periodicSave saves state periodically.
+func (q *DeadLetterQueue) periodicSave(ctx context.Context) {
+	interval := q.getConfig().SaveInterval
+	if interval <= 0 {
+		interval = DefaultDeadLetterQueueConfig().SaveInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Final save on shutdown
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = q.Save(saveCtx)
+			cancel()
+			return
+		case <-q.doneCh:
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = q.Save(saveCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			if err := q.Save(ctx); err != nil {
+				q.logger.Warn("failed to save dead letters", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Load loads state from gist.
+func (q *DeadLetterQueue) Load(ctx context.Context) error {
+	if !q.IsEnabled() {
+		return nil
+	}
+
+	cfg := q.getConfig()
+	content, err := q.gistClient.Load(ctx, cfg.FileName, cfg.GistID)
+	if err != nil {
+		return fmt.Errorf("load dead letters: %w", err)
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	var snapshot DeadLetterSnapshot
+	if err := json.Unmarshal([]byte(content), &snapshot); err != nil {
+		return fmt.Errorf("unmarshal dead letters: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Keep anything dead-lettered before the load finished
+	q.entries = append(snapshot.DeadLetters, q.entries...)
+
+	q.logger.Info("loaded dead letters",
+		zap.Int("deadLetters", len(q.entries)),
+	)
+
+	return nil
+}
+
+// Save saves state to gist.
+func (q *DeadLetterQueue) Save(ctx context.Context) error {
+	if !q.IsEnabled() {
+		return nil
+	}
+
+	q.mu.Lock()
+	if !q.dirty {
+		q.mu.Unlock()
+		return nil
+	}
+
+	snapshot := DeadLetterSnapshot{
+		Version:     1,
+		Timestamp:   time.Now(),
+		DeadLetters: q.entries,
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	q.dirty = false
+	q.mu.Unlock()
+
+	if err != nil {
+		q.mu.Lock()
+		q.dirty = true
+		q.mu.Unlock()
+		return fmt.Errorf("marshal dead letters: %w", err)
+	}
+
+	cfg := q.getConfig()
+	if err := q.gistClient.Save(ctx, cfg.FileName, string(data), cfg.GistID); err != nil {
+		q.mu.Lock()
+		q.dirty = true
+		q.mu.Unlock()
+		return fmt.Errorf("save dead letters: %w", err)
+	}
+
+	q.logger.Debug("saved dead letters",
+		zap.Int("deadLetters", len(snapshot.DeadLetters)),
+	)
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"polybot/clients/notifier"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestDeadLetterQueue_AddCapsEntries(t *testing.T) {
+	cfg := DefaultDeadLetterQueueConfig()
+	cfg.MaxEntries = 2
+	q := NewDeadLetterQueue(zap.NewNop(), nil, cfg)
+
+	for _, name := range []string{"a", "b", "c"} {
+		q.Add(notifier.DeadLetter{Channel: "discord", Alert: notifier.TradeAlert{TraderName: name}})
+	}
+
+	entries := q.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Alert.TraderName != "b" || entries[1].Alert.TraderName != "c" {
+		t.Errorf("expected oldest entry to be dropped, got %+v", entries)
+	}
+}
+
+func TestDeadLetterQueue_SaveAndLoad(t *testing.T) {
+	gistClient := NewMockGistStorage()
+	cfg := DefaultDeadLetterQueueConfig()
+	cfg.GistID = "gist123"
+
+	q := NewDeadLetterQueue(zap.NewNop(), gistClient, cfg)
+	if !q.IsEnabled() {
+		t.Fatal("expected queue to be enabled with gist configured")
+	}
+	q.Add(notifier.DeadLetter{
+		Channel:  "telegram",
+		Alert:    notifier.TradeAlert{TraderName: "trader", Reasons: []AlertReason{AlertReasonNewWallet}},
+		Error:    "telegram API returned status 429",
+		Attempts: 5,
+		FailedAt: time.Now(),
+	})
+
+	if err := q.Save(context.Background()); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	loaded := NewDeadLetterQueue(zap.NewNop(), gistClient, cfg)
+	if err := loaded.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	entries := loaded.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry after load, got %d", len(entries))
+	}
+	if entries[0].Channel != "telegram" || entries[0].Attempts != 5 || entries[0].Alert.Reasons[0] != AlertReasonNewWallet {
+		t.Errorf("unexpected loaded entry: %+v", entries[0])
+	}
+}
+
+func TestDeadLetterQueue_DisabledSaveIsNoop(t *testing.T) {
+	q := NewDeadLetterQueue(zap.NewNop(), NewMockGistStorage(), DefaultDeadLetterQueueConfig())
+	q.Add(notifier.DeadLetter{Channel: "discord"})
+
+	if err := q.Save(context.Background()); err != nil {
+		t.Errorf("expected no error when persistence is disabled, got %v", err)
+	}
+	if q.Count() != 1 {
+		t.Errorf("expected entry kept in memory, got %d", q.Count())
+	}
+}
+
+func TestDeadLetterQueue_Redeliver(t *testing.T) {
+	q := NewDeadLetterQueue(zap.NewNop(), nil, DefaultDeadLetterQueueConfig())
+	q.Add(notifier.DeadLetter{Channel: "discord", Alert: notifier.TradeAlert{TraderName: "a"}})
+	q.Add(notifier.DeadLetter{Channel: "webhook:gone", Alert: notifier.TradeAlert{TraderName: "b"}})
+	q.Add(notifier.DeadLetter{Channel: "discord", Alert: notifier.TradeAlert{TraderName: "c"}})
+
+	discord := &captureNotifier{}
+	if n := q.Redeliver(map[string]notifier.Notifier{"discord": discord}); n != 2 {
+		t.Fatalf("expected 2 alerts redelivered, got %d", n)
+	}
+	alerts := discord.Alerts()
+	if len(alerts) != 2 || alerts[0].TraderName != "a" || alerts[1].TraderName != "c" {
+		t.Errorf("unexpected redelivered alerts: %+v", alerts)
+	}
+
+	// Dead letters of unknown channels are kept
+	entries := q.Entries()
+	if len(entries) != 1 || entries[0].Channel != "webhook:gone" || !q.dirty {
+		t.Errorf("expected only the unknown channel's dead letter kept, got %+v", entries)
+	}
+}
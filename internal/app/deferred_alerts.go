@@ -0,0 +1,368 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/gist"
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// DeferredAlertDispatcherConfig holds configuration for deferred alert delivery.
+type DeferredAlertDispatcherConfig struct {
+	// Persistence
+	GistID   string
+	FileName string
+
+	CheckInterval time.Duration // How often to collect alerts from trackers
+	SentRetention time.Duration // How long sent markers are kept
+}
+
+// DefaultDeferredAlertDispatcherConfig returns sensible defaults.
+func DefaultDeferredAlertDispatcherConfig() DeferredAlertDispatcherConfig {
+	return DeferredAlertDispatcherConfig{
+		FileName:      "deferred_alerts.json",
+		CheckInterval: 1 * time.Minute,
+		SentRetention: 30 * 24 * time.Hour,
+	}
+}
+
+// DeferredAlertSnapshot is the persisted state format.
+type DeferredAlertSnapshot struct {
+	Version   int                  `json:"version"`
+	Timestamp time.Time            `json:"timestamp"`
+	Sent      map[string]time.Time `json:"sent"` // alert key -> sent time
+}
+
+// deferredAlert is a tracker alert converted for delivery.
+type deferredAlert struct {
+	key   string // Stable key used as the "already sent" marker
+	alert notifier.TradeAlert
+}
+
+// DeferredAlertDispatcher collects alerts that background trackers raise
+// outside the trade path (resolution-confirmed hedge removals, pre-move
+// positioning) and delivers them through the same path as live alerts.
+// Sent markers are persisted so restarts don't resend.
+type DeferredAlertDispatcher struct {
+	logger     *zap.Logger
+	gistClient gist.Storage
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   DeferredAlertDispatcherConfig
+
+	// Sources and sink
+	hedgeTracker   *HedgeTracker
+	patternTracker *PatternTracker
+	send           func(notifier.TradeAlert)
+
+	mu    sync.Mutex
+	sent  map[string]time.Time // alert key -> sent time
+	dirty bool
+
+	doneCh chan struct{}
+}
+
+// NewDeferredAlertDispatcher creates a new deferred alert dispatcher.
+// Alerts are delivered via send; hedgeTracker and patternTracker may be nil.
+func NewDeferredAlertDispatcher(
+	logger *zap.Logger,
+	gistClient gist.Storage,
+	config DeferredAlertDispatcherConfig,
+	hedgeTracker *HedgeTracker,
+	patternTracker *PatternTracker,
+	send func(notifier.TradeAlert),
+) *DeferredAlertDispatcher {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &DeferredAlertDispatcher{
+		logger:         logger.Named("deferred-alerts"),
+		gistClient:     gistClient,
+		config:         config,
+		hedgeTracker:   hedgeTracker,
+		patternTracker: patternTracker,
+		send:           send,
+		sent:           make(map[string]time.Time),
+		doneCh:         make(chan struct{}),
+	}
+}
+
+// IsEnabled returns true if sent markers are persisted.
+func (d *DeferredAlertDispatcher) IsEnabled() bool {
+	cfg := d.getConfig()
+	return d.gistClient != nil && cfg.GistID != ""
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (d *DeferredAlertDispatcher) getConfig() DeferredAlertDispatcherConfig {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+	return d.config
+}
+
+// UpdateConfig updates the dispatcher config.
+func (d *DeferredAlertDispatcher) UpdateConfig(cfg DeferredAlertDispatcherConfig) {
+	d.configMu.Lock()
+	defer d.configMu.Unlock()
+	d.config = cfg
+	d.logger.Info("deferred alert dispatcher config updated",
+		zap.Duration("checkInterval", cfg.CheckInterval),
+		zap.Duration("sentRetention", cfg.SentRetention),
+	)
+}
+
+// Start begins periodic dispatching.
+func (d *DeferredAlertDispatcher) Start(ctx context.Context) {
+	go d.run(ctx)
+}
+
+// Stop stops dispatching.
+func (d *DeferredAlertDispatcher) Stop() {
+	close(d.doneCh)
+}
+
+// run periodically collects and delivers deferred alerts.
+func (d *DeferredAlertDispatcher) run(ctx context.Context) {
+	interval := d.getConfig().CheckInterval
+	if interval <= 0 {
+		interval = DefaultDeferredAlertDispatcherConfig().CheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.doneCh:
+			return
+		case <-ticker.C:
+			d.Dispatch(ctx)
+		}
+	}
+}
+
+// Dispatch collects pending alerts from all trackers, sends those not sent
+// before, and persists the sent markers. Returns the number of alerts sent.
+func (d *DeferredAlertDispatcher) Dispatch(ctx context.Context) int {
+	var pending []deferredAlert
+	if d.hedgeTracker != nil {
+		for _, ha := range d.hedgeTracker.GetPendingFollowUpAlerts() {
+			pending = append(pending, hedgeFollowUpToDeferred(ha))
+		}
+	}
+	if d.patternTracker != nil {
+		for _, pa := range d.patternTracker.DrainDeferredAlerts() {
+			pending = append(pending, preMoveToDeferred(pa))
+		}
+	}
+
+	now := time.Now()
+	cfg := d.getConfig()
+
+	d.mu.Lock()
+	var toSend []notifier.TradeAlert
+	for _, p := range pending {
+		if _, ok := d.sent[p.key]; ok {
+			continue
+		}
+		d.sent[p.key] = now
+		d.dirty = true
+		toSend = append(toSend, p.alert)
+	}
+	d.pruneLocked(now, cfg.SentRetention)
+	d.mu.Unlock()
+
+	// Persist markers before sending so a crash can't cause a resend
+	if len(toSend) > 0 {
+		if err := d.Save(ctx); err != nil {
+			d.logger.Warn("failed to save deferred alert markers", zap.Error(err))
+		}
+	}
+
+	for _, alert := range toSend {
+		if d.send != nil {
+			d.send(alert)
+		}
+	}
+
+	if skipped := len(pending) - len(toSend); len(toSend) > 0 || skipped > 0 {
+		d.logger.Info("dispatched deferred alerts",
+			zap.Int("sent", len(toSend)),
+			zap.Int("alreadySent", skipped),
+		)
+	}
+	return len(toSend)
+}
+
+// pruneLocked drops sent markers older than retention (must hold lock).
+func (d *DeferredAlertDispatcher) pruneLocked(now time.Time, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	cutoff := now.Add(-retention)
+	for key, sentAt := range d.sent {
+		if sentAt.Before(cutoff) {
+			delete(d.sent, key)
+			d.dirty = true
+		}
+	}
+}
+
+// SentCount returns the number of remembered sent alerts.
+func (d *DeferredAlertDispatcher) SentCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.sent)
+}
+
+// hedgeFollowUpToDeferred converts a resolution-confirmed hedge removal.
+func hedgeFollowUpToDeferred(ha HedgeAlert) deferredAlert {
+	key := ha.ID
+	if key == "" {
+		key = fmt.Sprintf("%s:%s:%s", ha.Wallet, ha.ConditionID, ha.SoldSide)
+	}
+
+	return deferredAlert{
+		key: string(AlertReasonResolutionConfirmed) + ":" + key,
+		alert: notifier.TradeAlert{
+			TraderAddress:          ha.Wallet,
+			TraderName:             shortID(ha.Wallet),
+			WalletURL:              ha.WalletURL,
+			Side:                   "SELL",
+			Shares:                 ha.SoldSize,
+			Price:                  ha.SoldPrice,
+			Notional:               ha.SoldSize * ha.SoldPrice,
+			MarketTitle:            ha.MarketTitle,
+			MarketURL:              ha.MarketURL,
+			ConditionID:            ha.ConditionID,
+			Outcome:                ha.SoldSide,
+			HedgeYesSizeBefore:     ha.YesSizeBefore,
+			HedgeNoSizeBefore:      ha.NoSizeBefore,
+			HedgeSoldSide:          ha.SoldSide,
+			HasHedgeInfo:           true,
+			ResolutionWinner:       ha.WinningOutcome,
+			ResolutionRemovedLoser: ha.RemovedLoser,
+			HasResolutionInfo:      true,
+			Reasons:                []AlertReason{AlertReasonResolutionConfirmed},
+			Timestamp:              ha.Timestamp,
+		},
+	}
+}
+
+// preMoveToDeferred converts a pre-move positioning alert.
+func preMoveToDeferred(pa PatternAlert) deferredAlert {
+	key := pa.ID
+	if key == "" {
+		key = fmt.Sprintf("%s:%s:%d", pa.Wallet, pa.ConditionID, pa.Timestamp.Unix())
+	}
+
+	return deferredAlert{
+		key: string(AlertReasonPreMovePositioning) + ":" + key,
+		alert: notifier.TradeAlert{
+			TraderAddress:          pa.Wallet,
+			TraderName:             shortID(pa.Wallet),
+			WalletURL:              pa.WalletURL,
+			Side:                   strings.ToUpper(pa.Side),
+			Shares:                 pa.Size,
+			Price:                  pa.Price,
+			Notional:               pa.Notional,
+			MarketTitle:            pa.MarketTitle,
+			MarketURL:              pa.MarketURL,
+			ConditionID:            pa.ConditionID,
+			Outcome:                pa.Outcome,
+			PreMoveTotalTrades:     pa.PreMoveTotalTrades,
+			PreMoveSuccessfulMoves: pa.PreMoveSuccessfulMoves,
+			PreMoveAlphaScore:      pa.PreMoveAlphaScore,
+			PreMoveAvgMoveSize:     pa.PreMoveAvgMoveSize,
+			HasPreMoveInfo:         true,
+			Reasons:                []AlertReason{AlertReasonPreMovePositioning},
+			Timestamp:              pa.Timestamp,
+		},
+	}
+}
+
+// Load loads sent markers from gist.
+func (d *DeferredAlertDispatcher) Load(ctx context.Context) error {
+	if !d.IsEnabled() {
+		return nil
+	}
+
+	cfg := d.getConfig()
+	content, err := d.gistClient.Load(ctx, cfg.FileName, cfg.GistID)
+	if err != nil {
+		return fmt.Errorf("load deferred alerts: %w", err)
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	var snapshot DeferredAlertSnapshot
+	if err := json.Unmarshal([]byte(content), &snapshot); err != nil {
+		return fmt.Errorf("unmarshal deferred alerts: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if snapshot.Sent != nil {
+		d.sent = snapshot.Sent
+	}
+
+	d.logger.Info("loaded deferred alert markers",
+		zap.Int("sent", len(d.sent)),
+	)
+
+	return nil
+}
+
+// Save saves sent markers to gist.
+func (d *DeferredAlertDispatcher) Save(ctx context.Context) error {
+	if !d.IsEnabled() {
+		return nil
+	}
+
+	d.mu.Lock()
+	if !d.dirty {
+		d.mu.Unlock()
+		return nil
+	}
+
+	snapshot := DeferredAlertSnapshot{
+		Version:   1,
+		Timestamp: time.Now(),
+		Sent:      d.sent,
+	}
+
+	// Marshal under the lock since the map is shared
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	d.dirty = false
+	d.mu.Unlock()
+
+	if err != nil {
+		d.mu.Lock()
+		d.dirty = true
+		d.mu.Unlock()
+		return fmt.Errorf("marshal deferred alerts: %w", err)
+	}
+
+	cfg := d.getConfig()
+	if err := d.gistClient.Save(ctx, cfg.FileName, string(data), cfg.GistID); err != nil {
+		d.mu.Lock()
+		d.dirty = true
+		d.mu.Unlock()
+		return fmt.Errorf("save deferred alerts: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"context"
+	"polybot/clients/notifier"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestHedgeTrackerWithFollowUp() *HedgeTracker {
+	ht := NewHedgeTracker(zap.NewNop(), nil, nil, DefaultHedgeTrackerConfig())
+	ht.pendingEvents["0xabc:cond1:1"] = &HedgeRemovalEvent{
+		ID:             "0xabc:cond1:1",
+		Wallet:         "0xabc",
+		ConditionID:    "cond1",
+		MarketTitle:    "Test Market",
+		MarketSlug:     "test-market",
+		YesSizeBefore:  1000,
+		NoSizeBefore:   1000,
+		SoldSide:       "No",
+		SoldSize:       1000,
+		SoldPrice:      0.4,
+		Resolved:       true,
+		WinningOutcome: "Yes",
+		RemovedLoser:   true,
+	}
+	return ht
+}
+
+func TestDeferredAlertDispatcher_Dispatch(t *testing.T) {
+	ht := newTestHedgeTrackerWithFollowUp()
+	pt := NewPatternTracker(zap.NewNop(), nil, nil, DefaultPatternTrackerConfig())
+	pt.queueDeferredAlerts([]PatternAlert{{
+		ID:                 "0xdef:cond2:1",
+		Wallet:             "0xdef",
+		ConditionID:        "cond2",
+		Outcome:            "Yes",
+		Side:               "buy",
+		Price:              0.3,
+		Size:               100,
+		Notional:           30,
+		Reason:             "pre_move_positioning",
+		PreMoveTotalTrades: 10,
+		PreMoveAlphaScore:  0.8,
+		Timestamp:          time.Now(),
+	}})
+
+	var sent []notifier.TradeAlert
+	d := NewDeferredAlertDispatcher(zap.NewNop(), nil, DefaultDeferredAlertDispatcherConfig(), ht, pt,
+		func(alert notifier.TradeAlert) { sent = append(sent, alert) })
+
+	if n := d.Dispatch(context.Background()); n != 2 {
+		t.Fatalf("expected 2 alerts dispatched, got %d", n)
+	}
+
+	reasons := make(map[AlertReason]notifier.TradeAlert)
+	for _, a := range sent {
+		reasons[a.Reasons[0]] = a
+	}
+	confirmed, ok := reasons[AlertReasonResolutionConfirmed]
+	if !ok {
+		t.Fatal("expected resolution confirmed alert")
+	}
+	if !confirmed.HasResolutionInfo || confirmed.ResolutionWinner != "Yes" || confirmed.ConditionID != "cond1" || confirmed.Outcome != "No" {
+		t.Errorf("unexpected resolution confirmed alert: %+v", confirmed)
+	}
+	preMove, ok := reasons[AlertReasonPreMovePositioning]
+	if !ok {
+		t.Fatal("expected pre-move positioning alert")
+	}
+	if !preMove.HasPreMoveInfo || preMove.Side != "BUY" || preMove.PreMoveTotalTrades != 10 {
+		t.Errorf("unexpected pre-move alert: %+v", preMove)
+	}
+	for _, a := range sent {
+		if a.TraderName != shortID(a.TraderAddress) {
+			t.Errorf("expected trader name set, got %q for %s", a.TraderName, a.TraderAddress)
+		}
+	}
+
+	// Nothing left to deliver
+	if n := d.Dispatch(context.Background()); n != 0 {
+		t.Errorf("expected no alerts on second dispatch, got %d", n)
+	}
+}
+
+func TestDeferredAlertDispatcher_SkipsAlreadySent(t *testing.T) {
+	gistClient := NewMockGistStorage()
+	cfg := DefaultDeferredAlertDispatcherConfig()
+	cfg.GistID = "gist123"
+
+	var sentCount int
+	send := func(notifier.TradeAlert) { sentCount++ }
+
+	first := NewDeferredAlertDispatcher(zap.NewNop(), gistClient, cfg, newTestHedgeTrackerWithFollowUp(), nil, send)
+	if n := first.Dispatch(context.Background()); n != 1 {
+		t.Fatalf("expected 1 alert dispatched, got %d", n)
+	}
+	if gistClient.GetContent(cfg.FileName) == "" {
+		t.Fatal("expected sent markers to be saved")
+	}
+
+	// Simulate a restart where the hedge tracker lost its follow-up flag
+	restarted := NewDeferredAlertDispatcher(zap.NewNop(), gistClient, cfg, newTestHedgeTrackerWithFollowUp(), nil, send)
+	if err := restarted.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if n := restarted.Dispatch(context.Background()); n != 0 {
+		t.Errorf("expected already sent alert to be skipped, got %d", n)
+	}
+	if sentCount != 1 {
+		t.Errorf("expected alert to be sent once, got %d", sentCount)
+	}
+}
+
+func TestDeferredAlertDispatcher_PrunesOldMarkers(t *testing.T) {
+	d := NewDeferredAlertDispatcher(zap.NewNop(), nil, DefaultDeferredAlertDispatcherConfig(), nil, nil, nil)
+	d.sent["old"] = time.Now().Add(-31 * 24 * time.Hour)
+	d.sent["recent"] = time.Now()
+
+	d.Dispatch(context.Background())
+
+	if d.SentCount() != 1 {
+		t.Errorf("expected old marker to be pruned, got %d markers", d.SentCount())
+	}
+}
+
+func TestPatternTracker_DrainDeferredAlerts(t *testing.T) {
+	pt := NewPatternTracker(zap.NewNop(), nil, nil, DefaultPatternTrackerConfig())
+	for i := 0; i < maxDeferredPatternAlerts+10; i++ {
+		pt.queueDeferredAlerts([]PatternAlert{{Reason: "pre_move_positioning"}})
+	}
+
+	if alerts := pt.DrainDeferredAlerts(); len(alerts) != maxDeferredPatternAlerts {
+		t.Errorf("expected queue capped at %d, got %d", maxDeferredPatternAlerts, len(alerts))
+	}
+	if alerts := pt.DrainDeferredAlerts(); len(alerts) != 0 {
+		t.Errorf("expected queue to be empty after drain, got %d", len(alerts))
+	}
+}
@@ -0,0 +1,499 @@
+package app
+
+import (
+	"context"
+	"math"
+	"polybot/clients/notifier"
+	"time"
+)
+
+// NormalizedTrade is a trade from either ingestion path (WebSocket or polling)
+// reduced to the fields the detectors need.
+type NormalizedTrade struct {
+	Key         string // Deduplication key (tx hash + asset ID)
+	Wallet      string
+	TraderName  string
+	TokenID     string
+	ConditionID string
+	MarketTitle string
+	MarketSlug  string
+	MarketImage string
+	Outcome     string
+	Side        string // BUY or SELL
+	Size        float64
+	Price       float64
+	Notional    float64
+	Timestamp   time.Time
+	Fills       []notifier.Fill // Maker fills combined into the trade (WebSocket only)
+	BookBefore  *BookQuote      // Order book when the first fill arrived (WebSocket only)
+	BookAfter   *BookQuote      // Order book when the trade was evaluated (WebSocket only)
+}
+
+// IsBuy returns true if the trade is a buy.
+func (t *NormalizedTrade) IsBuy() bool {
+	return t.Side == "BUY"
+}
+
+// IsSell returns true if the trade is a sell.
+func (t *NormalizedTrade) IsSell() bool {
+	return t.Side == "SELL"
+}
+
+// Detection is the result of running a single detector against a trade.
+type Detection struct {
+	Reasons []AlertReason
+
+	// Enrich adds detector-specific details to the alert (optional).
+	// Only called if the trade passes the global filters and is alerted on.
+	Enrich func(alert *notifier.TradeAlert)
+}
+
+// Detector is a single alert heuristic.
+// Detectors are run in registration order, so reasons appear in a stable order.
+type Detector interface {
+	Name() string
+	Detect(ctx context.Context, trade *NormalizedTrade, stats *WalletStats, cfg TradeMonitorConfig) Detection
+}
+
+// specialAlertReasons bypass the win rate filter: they are interesting on their
+// own regardless of the trader's history.
+var specialAlertReasons = map[AlertReason]bool{
+	AlertReasonNewWallet:           true,
+	AlertReasonContrarianBet:       true,
+	AlertReasonMassiveTrade:        true,
+	AlertReasonContrarianWinner:    true,
+	AlertReasonCopyTrader:          true,
+	AlertReasonHedgeRemoval:        true,
+	AlertReasonAsymmetricExit:      true,
+	AlertReasonConvictionDoubling:  true,
+	AlertReasonPerfectExitTiming:   true,
+	AlertReasonStealthAccumulation: true,
+	AlertReasonMarketImpact:        true,
+	AlertReasonCoordinatedEntry:    true,
+}
+
+// hasSpecialReason returns true if any of the reasons bypasses the win rate filter.
+func hasSpecialReason(reasons []AlertReason) bool {
+	for _, r := range reasons {
+		if specialAlertReasons[r] {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultDetectors returns every built-in heuristic in alert order.
+func defaultDetectors(tm *TradeMonitor) []Detector {
+	return []Detector{
+		lowActivityDetector{},
+		highWinRateDetector{},
+		extremeBetDetector{},
+		&rapidTradingDetector{tm: tm},
+		newWalletDetector{},
+		contrarianBetDetector{},
+		massiveTradeDetector{},
+		&marketImpactDetector{tm: tm},
+		&coordinatedEntryDetector{tm: tm},
+		&contrarianWinnerDetector{tm: tm},
+		&copyTradeDetector{tm: tm},
+		&hedgeDetector{tm: tm},
+		&patternDetector{tm: tm},
+	}
+}
+
+// ---- Stateless detectors ----
+
+type lowActivityDetector struct{}
+
+func (lowActivityDetector) Name() string { return "low_activity" }
+
+func (lowActivityDetector) Detect(_ context.Context, _ *NormalizedTrade, stats *WalletStats, cfg TradeMonitorConfig) Detection {
+	if stats.UniqueMarkets < cfg.MaxMarketsForLow {
+		return Detection{Reasons: []AlertReason{AlertReasonLowActivity}}
+	}
+	return Detection{}
+}
+
+type highWinRateDetector struct{}
+
+func (highWinRateDetector) Name() string { return "high_win_rate" }
+
+// Detect flags high win rates on non-obvious bets. Wins on "obvious" bets
+// (e.g., buying at 98¢) are excluded from the suspicious win rate, and trades
+// at 95¢+ are skipped since they are obvious outcomes or cashing out.
+func (highWinRateDetector) Detect(_ context.Context, trade *NormalizedTrade, stats *WalletStats, cfg TradeMonitorConfig) Detection {
+	if trade.Price >= 0.95 {
+		return Detection{}
+	}
+	suspiciousCount := stats.SuspiciousWins + stats.SuspiciousLosses
+	if suspiciousCount >= cfg.MinResolvedForWinRate && stats.SuspiciousWinRate >= cfg.HighWinRateThreshold {
+		return Detection{Reasons: []AlertReason{AlertReasonHighWinRate}}
+	}
+	return Detection{}
+}
+
+type extremeBetDetector struct{}
+
+func (extremeBetDetector) Name() string { return "extreme_bet" }
+
+func (extremeBetDetector) Detect(_ context.Context, trade *NormalizedTrade, _ *WalletStats, cfg TradeMonitorConfig) Detection {
+	if trade.Price <= cfg.ExtremeLowPrice && trade.Notional >= cfg.ExtremeMinNotional {
+		return Detection{Reasons: []AlertReason{AlertReasonExtremeBet}}
+	}
+	return Detection{}
+}
+
+type newWalletDetector struct{}
+
+func (newWalletDetector) Name() string { return "new_wallet" }
+
+func (newWalletDetector) Detect(_ context.Context, trade *NormalizedTrade, stats *WalletStats, cfg TradeMonitorConfig) Detection {
+	if stats.UniqueMarkets <= cfg.NewWalletMaxMarkets && trade.Notional >= cfg.NewWalletMinNotional {
+		return Detection{Reasons: []AlertReason{AlertReasonNewWallet}}
+	}
+	return Detection{}
+}
+
+type contrarianBetDetector struct{}
+
+func (contrarianBetDetector) Name() string { return "contrarian_bet" }
+
+// Detect flags buys at low prices (betting against consensus).
+func (contrarianBetDetector) Detect(_ context.Context, trade *NormalizedTrade, _ *WalletStats, cfg TradeMonitorConfig) Detection {
+	if trade.IsBuy() && trade.Price <= cfg.ContrarianMaxPrice && trade.Notional >= cfg.ContrarianMinNotional {
+		return Detection{Reasons: []AlertReason{AlertReasonContrarianBet}}
+	}
+	return Detection{}
+}
+
+type massiveTradeDetector struct{}
+
+func (massiveTradeDetector) Name() string { return "massive_trade" }
+
+// Detect flags whale trades at non-obvious prices.
+func (massiveTradeDetector) Detect(_ context.Context, trade *NormalizedTrade, _ *WalletStats, cfg TradeMonitorConfig) Detection {
+	if trade.Notional >= cfg.MassiveTradeMinNotional && trade.Price <= cfg.MassiveTradeMaxPrice {
+		return Detection{Reasons: []AlertReason{AlertReasonMassiveTrade}}
+	}
+	return Detection{}
+}
+
+// marketImpact measures a trade's size relative to its market.
+type marketImpact struct {
+	Score     float64 // Largest ratio of a measure to its threshold
+	VolumePct float64 // Notional / 24h volume (0 if unknown)
+	DepthPct  float64 // Notional / book depth on the side taken (0 if unknown)
+	PriceMove float64 // Relative price move across the trade in its direction (0 if unknown)
+}
+
+// scoreImpact measures a trade against its market's 24h volume, the book depth
+// it took liquidity from, and the price move across it. Each measure is divided
+// by its threshold; measures that are disabled or unknown don't count.
+func scoreImpact(trade *NormalizedTrade, volume24h float64, cfg TradeMonitorConfig) marketImpact {
+	var m marketImpact
+	score := func(value, threshold float64) {
+		if value > 0 && threshold > 0 {
+			m.Score = math.Max(m.Score, value/threshold)
+		}
+	}
+
+	if volume24h > 0 {
+		m.VolumePct = trade.Notional / volume24h
+		score(m.VolumePct, cfg.ImpactVolumePct)
+	}
+
+	// A buy takes the asks and a sell the bids, as they stood before the order
+	if trade.BookBefore != nil {
+		depth := trade.BookBefore.AskDepth
+		if trade.IsSell() {
+			depth = trade.BookBefore.BidDepth
+		}
+		if depth > 0 {
+			m.DepthPct = trade.Notional / depth
+			score(m.DepthPct, cfg.ImpactDepthPct)
+		}
+	}
+
+	m.PriceMove = realizedMove(trade)
+	score(m.PriceMove, cfg.ImpactPriceMovePct)
+	return m
+}
+
+// realizedMove returns the relative price move across a trade in its direction
+// (up for buys, down for sells). It compares the book mid before and after the
+// order, falling back to the range of the order's fill prices.
+// Returns 0 if the price didn't move the trade's way or can't be measured.
+func realizedMove(trade *NormalizedTrade) float64 {
+	var before, after float64
+	switch {
+	case trade.BookBefore != nil && trade.BookAfter != nil && trade.BookBefore.Mid() > 0 && trade.BookAfter.Mid() > 0:
+		before, after = trade.BookBefore.Mid(), trade.BookAfter.Mid()
+	case len(trade.Fills) > 1:
+		// An order walks the book, so its fills run from the best price to the worst
+		low, high := trade.Fills[0].Price, trade.Fills[0].Price
+		for _, f := range trade.Fills[1:] {
+			low = math.Min(low, f.Price)
+			high = math.Max(high, f.Price)
+		}
+		before, after = low, high
+		if trade.IsSell() {
+			before, after = high, low
+		}
+	default:
+		return 0
+	}
+
+	if before <= 0 {
+		return 0
+	}
+	move := (after - before) / before
+	if trade.IsSell() {
+		move = -move
+	}
+	return math.Max(move, 0)
+}
+
+// ---- Stateful detectors (backed by trade monitor state or trackers) ----
+
+type rapidTradingDetector struct {
+	tm *TradeMonitor
+}
+
+func (d *rapidTradingDetector) Name() string { return "rapid_trading" }
+
+// Detect records the trade and flags multiple trades in a short window.
+func (d *rapidTradingDetector) Detect(_ context.Context, trade *NormalizedTrade, _ *WalletStats, _ TradeMonitorConfig) Detection {
+	if isRapid, _, _ := d.tm.checkRapidTrading(trade.Wallet, trade.Notional, trade.Timestamp); isRapid {
+		return Detection{Reasons: []AlertReason{AlertReasonRapidTrading}}
+	}
+	return Detection{}
+}
+
+type marketImpactDetector struct {
+	tm *TradeMonitor
+}
+
+func (d *marketImpactDetector) Name() string { return "market_impact" }
+
+// Detect flags trades that are large for their market, so whale detection
+// scales with market size rather than using a flat notional.
+func (d *marketImpactDetector) Detect(_ context.Context, trade *NormalizedTrade, _ *WalletStats, cfg TradeMonitorConfig) Detection {
+	if trade.Notional < cfg.ImpactMinNotional {
+		return Detection{}
+	}
+	volume, _ := d.tm.MarketVolume24h(trade.TokenID)
+	impact := scoreImpact(trade, volume, cfg)
+	if impact.Score < 1 {
+		return Detection{}
+	}
+	return Detection{
+		Reasons: []AlertReason{AlertReasonMarketImpact},
+		Enrich: func(alert *notifier.TradeAlert) {
+			alert.ImpactScore = impact.Score
+			alert.ImpactVolumePct = impact.VolumePct
+			alert.ImpactDepthPct = impact.DepthPct
+			alert.ImpactPriceMove = impact.PriceMove
+			alert.HasImpactInfo = true
+		},
+	}
+}
+
+type coordinatedEntryDetector struct {
+	tm *TradeMonitor
+}
+
+func (d *coordinatedEntryDetector) Name() string { return "coordinated_entry" }
+
+// Detect records buys by fresh wallets and flags the buy that completes a
+// cluster of them on the same outcome.
+func (d *coordinatedEntryDetector) Detect(_ context.Context, trade *NormalizedTrade, stats *WalletStats, cfg TradeMonitorConfig) Detection {
+	cluster, ok := d.tm.clusters.Record(trade, stats.UniqueMarkets, cfg)
+	if !ok {
+		return Detection{}
+	}
+	return Detection{
+		Reasons: []AlertReason{AlertReasonCoordinatedEntry},
+		Enrich:  cluster.enrich,
+	}
+}
+
+type contrarianWinnerDetector struct {
+	tm *TradeMonitor
+}
+
+func (d *contrarianWinnerDetector) Name() string { return "contrarian_winner" }
+
+// Detect flags known contrarian winners (from the historical cache) trading at
+// a non-obvious price (same threshold as massive trades).
+func (d *contrarianWinnerDetector) Detect(_ context.Context, trade *NormalizedTrade, _ *WalletStats, cfg TradeMonitorConfig) Detection {
+	cache := d.tm.contrarianCache
+	if cache != nil && trade.Price <= cfg.MassiveTradeMaxPrice && cache.ShouldAlert(trade.Wallet) {
+		return Detection{Reasons: []AlertReason{AlertReasonContrarianWinner}}
+	}
+	return Detection{}
+}
+
+type copyTradeDetector struct {
+	tm *TradeMonitor
+}
+
+func (d *copyTradeDetector) Name() string { return "copy_trader" }
+
+// Detect records leader trades and flags trades that copy a leader.
+func (d *copyTradeDetector) Detect(_ context.Context, trade *NormalizedTrade, stats *WalletStats, _ TradeMonitorConfig) Detection {
+	tracker := d.tm.copyTracker
+	if tracker == nil {
+		return Detection{}
+	}
+
+	// Check if this trader is a leader (high win rate or contrarian winner)
+	if tracker.IsLeader(stats) {
+		tracker.RecordLeaderTrade(trade.Wallet, trade.ConditionID, trade.TokenID, trade.Side)
+	}
+
+	// Check if this trade is copying a leader
+	if isCopy, _ := tracker.CheckForCopy(trade.Wallet, trade.ConditionID, trade.TokenID, trade.Side); isCopy {
+		if tracker.ShouldAlert(trade.Wallet) {
+			return Detection{Reasons: []AlertReason{AlertReasonCopyTrader}}
+		}
+	}
+	return Detection{}
+}
+
+type hedgeDetector struct {
+	tm *TradeMonitor
+}
+
+func (d *hedgeDetector) Name() string { return "hedge" }
+
+// Detect runs hedge removal and asymmetric exit detection on sells.
+func (d *hedgeDetector) Detect(ctx context.Context, trade *NormalizedTrade, _ *WalletStats, _ TradeMonitorConfig) Detection {
+	tracker := d.tm.hedgeTracker
+	if tracker == nil || !trade.IsSell() || trade.ConditionID == "" {
+		return Detection{}
+	}
+	if !tracker.ShouldCheckPositions(trade.Wallet, trade.ConditionID) {
+		return Detection{}
+	}
+
+	hedgeAlerts := tracker.ProcessTrade(ctx, trade.Wallet, trade.ConditionID, trade.MarketTitle, trade.MarketSlug, trade.Side, trade.Outcome, trade.Size, trade.Price)
+	if len(hedgeAlerts) == 0 {
+		return Detection{}
+	}
+
+	var reasons []AlertReason
+	for _, ha := range hedgeAlerts {
+		switch ha.Reason {
+		case "hedge_removal":
+			reasons = append(reasons, AlertReasonHedgeRemoval)
+		case "asymmetric_exit":
+			reasons = append(reasons, AlertReasonAsymmetricExit)
+		}
+	}
+
+	return Detection{
+		Reasons: reasons,
+		Enrich: func(alert *notifier.TradeAlert) {
+			for _, ha := range hedgeAlerts {
+				switch ha.Reason {
+				case "hedge_removal":
+					alert.HedgeYesSizeBefore = ha.YesSizeBefore
+					alert.HedgeNoSizeBefore = ha.NoSizeBefore
+					alert.HedgeYesSizeAfter = ha.YesSizeAfter
+					alert.HedgeNoSizeAfter = ha.NoSizeAfter
+					alert.HedgeSoldSide = ha.SoldSide
+					alert.HedgeSoldPct = ha.ReductionPct
+					alert.HasHedgeInfo = true
+				case "asymmetric_exit":
+					alert.AsymmetricWinExits = ha.WinExitCount
+					alert.AsymmetricLossExits = ha.LossExitCount
+					alert.AsymmetricWinAvgHoldSec = ha.AvgWinHoldTime.Seconds()
+					alert.AsymmetricLossAvgHoldSec = ha.AvgLossHoldTime.Seconds()
+					alert.AsymmetricRatio = ha.AsymmetricRatio
+					alert.HasAsymmetricInfo = true
+				}
+			}
+		},
+	}
+}
+
+type patternDetector struct {
+	tm *TradeMonitor
+}
+
+func (d *patternDetector) Name() string { return "pattern" }
+
+// Detect runs conviction doubling, stealth accumulation and exit timing detection.
+func (d *patternDetector) Detect(ctx context.Context, trade *NormalizedTrade, _ *WalletStats, _ TradeMonitorConfig) Detection {
+	tracker := d.tm.patternTracker
+	if tracker == nil {
+		return Detection{}
+	}
+
+	patternAlerts := tracker.ProcessTrade(ctx, ProcessTradeInput{
+		Wallet:      trade.Wallet,
+		ConditionID: trade.ConditionID,
+		MarketTitle: trade.MarketTitle,
+		MarketSlug:  trade.MarketSlug,
+		TokenID:     trade.TokenID,
+		Outcome:     trade.Outcome,
+		Side:        trade.Side,
+		Size:        trade.Size,
+		Price:       trade.Price,
+		Notional:    trade.Notional,
+		Timestamp:   trade.Timestamp,
+	})
+
+	var reasons []AlertReason
+	for _, pa := range patternAlerts {
+		switch pa.Reason {
+		case "conviction_doubling":
+			reasons = append(reasons, AlertReasonConvictionDoubling)
+		case "stealth_accumulation":
+			reasons = append(reasons, AlertReasonStealthAccumulation)
+		}
+	}
+
+	// Check for perfect exit timing (based on historical stats)
+	if tracker.ShouldAlertPerfectTiming(trade.Wallet) {
+		reasons = append(reasons, AlertReasonPerfectExitTiming)
+	}
+
+	exitStats := tracker.GetExitTimingStats(trade.Wallet)
+	if len(patternAlerts) == 0 && exitStats == nil {
+		return Detection{Reasons: reasons}
+	}
+
+	return Detection{
+		Reasons: reasons,
+		Enrich: func(alert *notifier.TradeAlert) {
+			for _, pa := range patternAlerts {
+				switch pa.Reason {
+				case "conviction_doubling":
+					alert.ConvictionExistingSize = pa.ConvictionExistingSize
+					alert.ConvictionExistingAvg = pa.ConvictionExistingAvg
+					alert.ConvictionCurrentPrice = pa.ConvictionCurrentPrice
+					alert.ConvictionLossPct = pa.ConvictionLossPct
+					alert.ConvictionAddedSize = pa.ConvictionAddedSize
+					alert.ConvictionAddedValue = pa.ConvictionAddedValue
+					alert.HasConvictionInfo = true
+				case "stealth_accumulation":
+					alert.StealthTradeCount = pa.StealthTradeCount
+					alert.StealthTotalSize = pa.StealthTotalSize
+					alert.StealthTotalValue = pa.StealthTotalValue
+					alert.StealthAvgPrice = pa.StealthAvgPrice
+					alert.StealthSpreadMins = pa.StealthSpreadMins
+					alert.HasStealthInfo = true
+				}
+			}
+
+			// Add perfect exit timing stats if available
+			if exitStats != nil && exitStats.VerifiedExits >= tracker.getConfig().PerfectExitMinExits {
+				alert.PerfectExitScore = exitStats.AvgTimingScore
+				alert.PerfectExitCount = exitStats.VerifiedExits
+				alert.PerfectExitPerfectCount = exitStats.PerfectExits
+				alert.HasPerfectExitInfo = true
+			}
+		},
+	}
+}
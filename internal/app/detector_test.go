@@ -0,0 +1,412 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"polybot/clients/notifier"
+	"polybot/clients/polymarketapi"
+	"polybot/clients/polymarketevents"
+	"sync"
+	"testing"
+	"time"
+)
+
+// captureNotifier records alerts sent through it.
+type captureNotifier struct {
+	mu     sync.Mutex
+	alerts []notifier.TradeAlert
+}
+
+func (c *captureNotifier) SendTradeAlert(alert notifier.TradeAlert) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.alerts = append(c.alerts, alert)
+}
+
+func (c *captureNotifier) Close() error { return nil }
+
+func (c *captureNotifier) Alerts() []notifier.TradeAlert {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make([]notifier.TradeAlert, len(c.alerts))
+	copy(result, c.alerts)
+	return result
+}
+
+// staticDetector always returns the configured reasons.
+type staticDetector struct {
+	reasons []AlertReason
+	calls   int
+}
+
+func (d *staticDetector) Name() string { return "static" }
+
+func (d *staticDetector) Detect(_ context.Context, _ *NormalizedTrade, _ *WalletStats, _ TradeMonitorConfig) Detection {
+	d.calls++
+	return Detection{
+		Reasons: d.reasons,
+		Enrich: func(alert *notifier.TradeAlert) {
+			alert.HasStealthInfo = true
+		},
+	}
+}
+
+func TestDetectors_StatelessHeuristics(t *testing.T) {
+	cfg := DefaultTradeMonitorConfig()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		detector Detector
+		trade    NormalizedTrade
+		stats    WalletStats
+		expected AlertReason
+	}{
+		{
+			name:     "low activity",
+			detector: lowActivityDetector{},
+			trade:    NormalizedTrade{Side: "BUY", Price: 0.5, Notional: 5000},
+			stats:    WalletStats{UniqueMarkets: 2},
+			expected: AlertReasonLowActivity,
+		},
+		{
+			name:     "high win rate",
+			detector: highWinRateDetector{},
+			trade:    NormalizedTrade{Side: "BUY", Price: 0.5, Notional: 5000},
+			stats:    WalletStats{UniqueMarkets: 50, SuspiciousWins: 10, SuspiciousWinRate: 1.0},
+			expected: AlertReasonHighWinRate,
+		},
+		{
+			name:     "extreme bet",
+			detector: extremeBetDetector{},
+			trade:    NormalizedTrade{Side: "BUY", Price: 0.02, Notional: 5000},
+			stats:    WalletStats{UniqueMarkets: 50},
+			expected: AlertReasonExtremeBet,
+		},
+		{
+			name:     "new wallet",
+			detector: newWalletDetector{},
+			trade:    NormalizedTrade{Side: "BUY", Price: 0.5, Notional: 20000},
+			stats:    WalletStats{UniqueMarkets: 1},
+			expected: AlertReasonNewWallet,
+		},
+		{
+			name:     "contrarian bet",
+			detector: contrarianBetDetector{},
+			trade:    NormalizedTrade{Side: "BUY", Price: 0.08, Notional: 6000},
+			stats:    WalletStats{UniqueMarkets: 50},
+			expected: AlertReasonContrarianBet,
+		},
+		{
+			name:     "massive trade",
+			detector: massiveTradeDetector{},
+			trade:    NormalizedTrade{Side: "SELL", Price: 0.6, Notional: 60000},
+			stats:    WalletStats{UniqueMarkets: 50},
+			expected: AlertReasonMassiveTrade,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := tt.detector.Detect(ctx, &tt.trade, &tt.stats, cfg)
+			if len(detection.Reasons) != 1 || detection.Reasons[0] != tt.expected {
+				t.Errorf("expected [%s], got %v", tt.expected, detection.Reasons)
+			}
+		})
+	}
+}
+
+func TestDetectors_NoMatch(t *testing.T) {
+	cfg := DefaultTradeMonitorConfig()
+	ctx := context.Background()
+	trade := NormalizedTrade{Side: "SELL", Price: 0.5, Notional: 5000}
+	stats := WalletStats{UniqueMarkets: 50}
+
+	for _, d := range []Detector{
+		lowActivityDetector{},
+		highWinRateDetector{},
+		extremeBetDetector{},
+		newWalletDetector{},
+		contrarianBetDetector{},
+		massiveTradeDetector{},
+	} {
+		if detection := d.Detect(ctx, &trade, &stats, cfg); len(detection.Reasons) != 0 {
+			t.Errorf("%s: expected no reasons, got %v", d.Name(), detection.Reasons)
+		}
+	}
+}
+
+func TestHighWinRateDetector_SkipsObviousPrice(t *testing.T) {
+	cfg := DefaultTradeMonitorConfig()
+	trade := NormalizedTrade{Side: "SELL", Price: 0.97, Notional: 5000}
+	stats := WalletStats{SuspiciousWins: 10, SuspiciousWinRate: 1.0}
+
+	if detection := (highWinRateDetector{}).Detect(context.Background(), &trade, &stats, cfg); len(detection.Reasons) != 0 {
+		t.Errorf("expected no reasons for obvious price, got %v", detection.Reasons)
+	}
+}
+
+func TestDefaultDetectors_AllRegistered(t *testing.T) {
+	monitor := NewTradeMonitor(nil, nil, nil, nil, nil, nil, DefaultTradeMonitorConfig())
+
+	names := make(map[string]bool)
+	for _, d := range monitor.Detectors() {
+		names[d.Name()] = true
+	}
+
+	for _, name := range []string{
+		"low_activity", "high_win_rate", "extreme_bet", "rapid_trading", "new_wallet",
+		"contrarian_bet", "massive_trade", "market_impact", "coordinated_entry", "contrarian_winner", "copy_trader",
+		"hedge", "pattern",
+	} {
+		if !names[name] {
+			t.Errorf("expected detector %q to be registered", name)
+		}
+	}
+}
+
+func TestScoreImpact(t *testing.T) {
+	cfg := DefaultTradeMonitorConfig()
+
+	// $5k into a $40k/day market is 12.5% of volume: 1.25x the 10% threshold
+	trade := NormalizedTrade{Side: "BUY", Notional: 5000}
+	if m := scoreImpact(&trade, 40000, cfg); m.VolumePct != 0.125 || m.Score != 1.25 {
+		t.Errorf("unexpected volume impact: %+v", m)
+	}
+	// The same trade is noise in a $10M/day market
+	if m := scoreImpact(&trade, 10_000_000, cfg); m.Score >= 1 {
+		t.Errorf("expected no impact in a deep market, got %+v", m)
+	}
+
+	// A buy is measured against the asks, a sell against the bids
+	trade.BookBefore = &BookQuote{BidDepth: 50000, AskDepth: 4000, BidLevels: 3, AskLevels: 3, BestBid: 0.40, BestAsk: 0.42}
+	if m := scoreImpact(&trade, 0, cfg); m.DepthPct != 1.25 || m.Score != 2.5 {
+		t.Errorf("unexpected buy depth impact: %+v", m)
+	}
+	trade.Side = "SELL"
+	if m := scoreImpact(&trade, 0, cfg); m.DepthPct != 0.1 || m.Score >= 1 {
+		t.Errorf("unexpected sell depth impact: %+v", m)
+	}
+
+	// Disabled thresholds still report the measure but don't score
+	cfg.ImpactDepthPct = 0
+	trade.Side = "BUY"
+	if m := scoreImpact(&trade, 0, cfg); m.DepthPct != 1.25 || m.Score != 0 {
+		t.Errorf("expected a disabled measure to not score, got %+v", m)
+	}
+}
+
+func TestRealizedMove(t *testing.T) {
+	before := &BookQuote{BestBid: 0.39, BestAsk: 0.41, BidLevels: 1, AskLevels: 1}
+	after := &BookQuote{BestBid: 0.43, BestAsk: 0.45, BidLevels: 1, AskLevels: 1}
+
+	buy := NormalizedTrade{Side: "BUY", BookBefore: before, BookAfter: after}
+	if move := realizedMove(&buy); math.Abs(move-0.1) > 1e-9 {
+		t.Errorf("expected a 10%% move from 40¢ to 44¢, got %v", move)
+	}
+	// A sell that the price moved up against doesn't count
+	sell := NormalizedTrade{Side: "SELL", BookBefore: before, BookAfter: after}
+	if move := realizedMove(&sell); move != 0 {
+		t.Errorf("expected no move against a sell, got %v", move)
+	}
+
+	// Without books, the fills' price range is used
+	sell = NormalizedTrade{Side: "SELL", Fills: []notifier.Fill{{Price: 0.50}, {Price: 0.45}, {Price: 0.40}}}
+	if move := realizedMove(&sell); math.Abs(move-0.2) > 1e-9 {
+		t.Errorf("expected a 20%% move from 50¢ to 40¢, got %v", move)
+	}
+	if move := realizedMove(&NormalizedTrade{Side: "BUY"}); move != 0 {
+		t.Errorf("expected no move without books or fills, got %v", move)
+	}
+}
+
+func TestMarketImpactDetector(t *testing.T) {
+	monitor := NewTradeMonitor(nil, nil, nil, nil, nil, nil, DefaultTradeMonitorConfig())
+	monitor.tokenToInfo["thin"] = &MarketInfo{ConditionID: "0xthin", Volume24h: 20000}
+	monitor.tokenToInfo["deep"] = &MarketInfo{ConditionID: "0xdeep", Volume24h: 50_000_000}
+	cfg := monitor.getConfig()
+	d := &marketImpactDetector{tm: monitor}
+
+	trade := NormalizedTrade{TokenID: "thin", Side: "BUY", Price: 0.5, Notional: 3000}
+	detection := d.Detect(context.Background(), &trade, &WalletStats{}, cfg)
+	if len(detection.Reasons) != 1 || detection.Reasons[0] != AlertReasonMarketImpact {
+		t.Fatalf("expected market impact in a thin market, got %v", detection.Reasons)
+	}
+	var alert notifier.TradeAlert
+	detection.Enrich(&alert)
+	if !alert.HasImpactInfo || alert.ImpactVolumePct != 0.15 || math.Abs(alert.ImpactScore-1.5) > 1e-9 {
+		t.Errorf("unexpected enrichment: %+v", alert)
+	}
+
+	trade.TokenID = "deep"
+	if detection := d.Detect(context.Background(), &trade, &WalletStats{}, cfg); len(detection.Reasons) != 0 {
+		t.Errorf("expected no reasons in a deep market, got %v", detection.Reasons)
+	}
+
+	// Below the notional floor even a thin market doesn't alert
+	trade = NormalizedTrade{TokenID: "thin", Side: "BUY", Price: 0.5, Notional: 1500}
+	if detection := d.Detect(context.Background(), &trade, &WalletStats{}, cfg); len(detection.Reasons) != 0 {
+		t.Errorf("expected no reasons below the notional floor, got %v", detection.Reasons)
+	}
+}
+
+func TestCoordinatedEntryDetector(t *testing.T) {
+	monitor := NewTradeMonitor(nil, nil, nil, nil, nil, nil, DefaultTradeMonitorConfig())
+	cfg := monitor.getConfig()
+	d := &coordinatedEntryDetector{tm: monitor}
+	start := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	var detection Detection
+	for i, wallet := range []string{"0xa", "0xb", "0xc", "0xd"} {
+		trade := NormalizedTrade{
+			Wallet: wallet, ConditionID: "0xmarket", TokenID: "yes", Side: "BUY",
+			Size: 12000, Price: 0.5, Notional: 6000, Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+		}
+		detection = d.Detect(context.Background(), &trade, &WalletStats{UniqueMarkets: 1}, cfg)
+		if i < 3 && len(detection.Reasons) != 0 {
+			t.Fatalf("expected no reasons before the 4th wallet, got %v", detection.Reasons)
+		}
+	}
+	if len(detection.Reasons) != 1 || detection.Reasons[0] != AlertReasonCoordinatedEntry {
+		t.Fatalf("expected coordinated entry, got %v", detection.Reasons)
+	}
+
+	var alert notifier.TradeAlert
+	detection.Enrich(&alert)
+	if !alert.HasClusterInfo || len(alert.ClusterWallets) != 4 || alert.ClusterNotional != 24000 ||
+		alert.ClusterShares != 48000 || alert.ClusterSpanSecs != 900 {
+		t.Errorf("unexpected enrichment: %+v", alert)
+	}
+
+	// Experienced wallets don't count toward a cluster
+	trade := NormalizedTrade{Wallet: "0xe", ConditionID: "0xother", TokenID: "yes", Side: "BUY", Notional: 50000, Timestamp: start}
+	if detection := d.Detect(context.Background(), &trade, &WalletStats{UniqueMarkets: 10}, cfg); len(detection.Reasons) != 0 {
+		t.Errorf("expected no reasons for an experienced wallet, got %v", detection.Reasons)
+	}
+}
+
+func TestHasSpecialReason(t *testing.T) {
+	if hasSpecialReason([]AlertReason{AlertReasonLowActivity, AlertReasonRapidTrading}) {
+		t.Error("expected low activity and rapid trading to not be special")
+	}
+	if !hasSpecialReason([]AlertReason{AlertReasonLowActivity, AlertReasonStealthAccumulation}) {
+		t.Error("expected stealth accumulation to be special")
+	}
+}
+
+func TestRegisterDetector_ReasonsAndEnrichment(t *testing.T) {
+	monitor, server, tracker := newTestTradeMonitor(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]any{})
+	})
+	defer server.Close()
+
+	capture := &captureNotifier{}
+	monitor.notifier = capture
+
+	custom := &staticDetector{reasons: []AlertReason{AlertReasonMassiveTrade}}
+	monitor.RegisterDetector(custom)
+
+	tracker.cache["0xabc"] = &WalletStats{Wallet: "0xabc", UniqueMarkets: 50, FetchedAt: time.Now()}
+
+	monitor.processTrade(context.Background(), polymarketapi.Trade{
+		TransactionHash: "0xhash",
+		Asset:           "asset1",
+		ProxyWallet:     "0xabc",
+		Side:            "buy",
+		Size:            5000,
+		Price:           0.5,
+	})
+
+	if custom.calls != 1 {
+		t.Fatalf("expected custom detector to run once, got %d", custom.calls)
+	}
+	alerts := capture.Alerts()
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].Side != "BUY" {
+		t.Errorf("expected side to be normalized to BUY, got %s", alerts[0].Side)
+	}
+	if !alerts[0].HasStealthInfo {
+		t.Error("expected detector enrichment to be applied")
+	}
+}
+
+func TestProcessPaths_ProduceIdenticalAlerts(t *testing.T) {
+	newMonitor := func() (*TradeMonitor, *captureNotifier, func()) {
+		monitor, server, tracker := newTestTradeMonitor(t, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode([]any{})
+		})
+		capture := &captureNotifier{}
+		monitor.notifier = capture
+		monitor.UpdateConfig(DefaultTradeMonitorConfig())
+		monitor.tokenToInfo["token-yes"] = &MarketInfo{
+			ConditionID: "cond1",
+			Title:       "Test Market",
+			Slug:        "test-market",
+			Outcomes:    []string{"Yes", "No"},
+			TokenIDs:    []string{"token-yes", "token-no"},
+		}
+		tracker.cache["0xwallet"] = &WalletStats{
+			Wallet:        "0xwallet",
+			UniqueMarkets: 1,
+			FetchedAt:     time.Now(),
+		}
+		return monitor, capture, server.Close
+	}
+
+	wsMonitor, wsCapture, closeWS := newMonitor()
+	defer closeWS()
+	pollMonitor, pollCapture, closePoll := newMonitor()
+	defer closePoll()
+
+	wsMonitor.processTradeEvent(context.Background(), &polymarketevents.TradeEvent{
+		EventType:       "trade",
+		AssetID:         "token-yes",
+		Price:           "0.08",
+		Size:            "150000",
+		Side:            "BUY",
+		TakerAddress:    "0xwallet",
+		Timestamp:       "0",
+		TransactionHash: "0xtx",
+	})
+	wsMonitor.flushOrders(context.Background(), true)
+	pollMonitor.processTrade(context.Background(), polymarketapi.Trade{
+		TransactionHash: "0xtx",
+		Asset:           "token-yes",
+		ProxyWallet:     "0xwallet",
+		ConditionID:     "cond1",
+		Title:           "Test Market",
+		Slug:            "test-market",
+		Outcome:         "Yes",
+		Side:            "BUY",
+		Size:            150000,
+		Price:           0.08,
+		Timestamp:       0,
+	})
+
+	wsAlerts := wsCapture.Alerts()
+	pollAlerts := pollCapture.Alerts()
+	if len(wsAlerts) != 1 || len(pollAlerts) != 1 {
+		t.Fatalf("expected 1 alert from each path, got ws=%d poll=%d", len(wsAlerts), len(pollAlerts))
+	}
+
+	ws, poll := wsAlerts[0], pollAlerts[0]
+	if len(ws.Reasons) != len(poll.Reasons) {
+		t.Fatalf("reason mismatch: ws=%v poll=%v", ws.Reasons, poll.Reasons)
+	}
+	for i := range ws.Reasons {
+		if ws.Reasons[i] != poll.Reasons[i] {
+			t.Errorf("reason %d mismatch: ws=%s poll=%s", i, ws.Reasons[i], poll.Reasons[i])
+		}
+	}
+	if ws.ConditionID != poll.ConditionID || ws.Outcome != poll.Outcome || ws.MarketURL != poll.MarketURL {
+		t.Errorf("market mismatch: ws=%s/%s/%s poll=%s/%s/%s",
+			ws.ConditionID, ws.Outcome, ws.MarketURL, poll.ConditionID, poll.Outcome, poll.MarketURL)
+	}
+	if ws.Notional != poll.Notional || ws.Side != poll.Side {
+		t.Errorf("trade mismatch: ws=%s %.2f poll=%s %.2f", ws.Side, ws.Notional, poll.Side, poll.Notional)
+	}
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"polybot/clients/discord"
+	"polybot/clients/polymarketapi"
+	"polybot/config"
+
+	"go.uber.org/zap"
+)
+
+// buttonMuteDuration is how long the alert mute buttons mute for.
+const buttonMuteDuration = 24 * time.Hour
+
+// DiscordInteractions is the part of the Discord client used for commands.
+// Implemented by discord.DiscordClient.
+type DiscordInteractions interface {
+	StartInteractions(guildID string, handler discord.InteractionHandler) error
+	StopInteractions()
+}
+
+// DiscordCommandHandler answers /polybot slash commands and the buttons on
+// alert embeds. Buttons mute the alert's wallet or market and record feedback,
+// so false positive rates per heuristic can be reported on the dashboard.
+type DiscordCommandHandler struct {
+	logger       *zap.Logger
+	discord      DiscordInteractions
+	commands     *chatCommands
+	feedback     *AlertFeedbackStore
+	guildID      string
+	allowedUsers map[string]bool // nil = anyone who can use the command
+}
+
+// NewDiscordCommandHandler creates a command handler. allowedUserIDs limits
+// who can run commands and press buttons; empty allows anyone.
+func NewDiscordCommandHandler(
+	logger *zap.Logger,
+	discordClient DiscordInteractions,
+	polymarket *polymarketapi.PolymarketApiClient,
+	settings *config.SettingsManager,
+	mutes *MuteList,
+	feedback *AlertFeedbackStore,
+	stats func() ServiceStats,
+	guildID string,
+	allowedUserIDs []string,
+) *DiscordCommandHandler {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	logger = logger.Named("discord-commands")
+	h := &DiscordCommandHandler{
+		logger:   logger,
+		discord:  discordClient,
+		commands: newChatCommands(logger, polymarket, settings, mutes, stats, discordMarkup),
+		feedback: feedback,
+		guildID:  guildID,
+	}
+	for _, id := range allowedUserIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			if h.allowedUsers == nil {
+				h.allowedUsers = make(map[string]bool)
+			}
+			h.allowedUsers[id] = true
+		}
+	}
+	return h
+}
+
+// Start registers the slash command and begins answering interactions.
+func (h *DiscordCommandHandler) Start() error {
+	if err := h.discord.StartInteractions(h.guildID, h.handle); err != nil {
+		return err
+	}
+	h.logger.Info("discord commands enabled",
+		zap.String("guildID", h.guildID),
+		zap.Int("allowedUsers", len(h.allowedUsers)),
+	)
+	return nil
+}
+
+// Stop stops answering interactions.
+func (h *DiscordCommandHandler) Stop() {
+	h.discord.StopInteractions()
+}
+
+// handle authorizes and runs an interaction, returning the Markdown reply.
+func (h *DiscordCommandHandler) handle(ctx context.Context, in discord.Interaction) string {
+	name := in.Command
+	if in.IsButton() {
+		name = in.Action
+	}
+
+	if h.allowedUsers != nil && !h.allowedUsers[in.UserID] {
+		h.logger.Warn("ignoring discord interaction from unauthorized user",
+			zap.String("userID", in.UserID),
+			zap.String("command", name),
+		)
+		return "⛔ You are not allowed to run commands."
+	}
+
+	h.logger.Info("discord interaction",
+		zap.String("command", name),
+		zap.String("target", in.Target),
+		zap.Any("options", in.Options),
+		zap.String("userID", in.UserID),
+	)
+
+	if in.IsButton() {
+		return h.button(ctx, in)
+	}
+	return h.command(ctx, in)
+}
+
+// command runs a /polybot subcommand.
+func (h *DiscordCommandHandler) command(ctx context.Context, in discord.Interaction) string {
+	switch in.Command {
+	case "wallet":
+		return h.commands.wallet(ctx, []string{in.Options["address"], in.Options["duration"]})
+	case "holders":
+		return h.commands.holders(ctx, []string{in.Options["condition_id"]})
+	case "stats":
+		return h.commands.statsSummary()
+	case "mute":
+		var args []string
+		for _, name := range []string{"target", "duration"} {
+			if v := in.Options[name]; v != "" {
+				args = append(args, v)
+			}
+		}
+		return h.commands.mute(interactionUser(in), args)
+	default:
+		return fmt.Sprintf("Unknown command %s.", escapeDiscord(in.Command))
+	}
+}
+
+// button handles an alert button.
+func (h *DiscordCommandHandler) button(ctx context.Context, in discord.Interaction) string {
+	by := interactionUser(in)
+
+	switch in.Action {
+	case discord.ActionMuteWallet:
+		if !isWalletAddress(in.Target) {
+			return "This alert has no wallet to mute."
+		}
+		h.recordFeedback(in.AlertID, FeedbackMutedWallet, by)
+		return h.commands.muteTarget(MuteKindWallet, in.Target, buttonMuteDuration, by)
+	case discord.ActionMuteMarket:
+		if !isConditionID(in.Target) {
+			return "This alert has no market to mute."
+		}
+		h.recordFeedback(in.AlertID, FeedbackMutedMarket, by)
+		return h.commands.muteTarget(MuteKindMarket, in.Target, buttonMuteDuration, by)
+	case discord.ActionFalsePositive:
+		alert, ok := h.recordFeedback(in.AlertID, FeedbackFalsePositive, by)
+		if !ok {
+			return "This alert is no longer tracked, so feedback can't be recorded."
+		}
+		return fmt.Sprintf("👎 Marked as a false positive (%s) by %s. Thanks!",
+			escapeDiscord(strings.Join(alert.Reasons, ", ")), escapeDiscord(by))
+	case discord.ActionShowHolders:
+		return h.commands.holders(ctx, []string{in.Target})
+	default:
+		return "Unknown button."
+	}
+}
+
+// recordFeedback records a verdict on the alert a button was attached to.
+func (h *DiscordCommandHandler) recordFeedback(alertID string, verdict FeedbackVerdict, by string) (FeedbackAlert, bool) {
+	if h.feedback == nil || alertID == "" {
+		return FeedbackAlert{}, false
+	}
+	return h.feedback.Record(alertID, verdict, by)
+}
+
+// interactionUser names the user behind an interaction.
+func interactionUser(in discord.Interaction) string {
+	if in.UserName != "" {
+		return in.UserName
+	}
+	return in.UserID
+}
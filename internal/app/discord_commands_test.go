@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"polybot/clients/discord"
+	"polybot/clients/notifier"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeDiscordInteractions records the handler registered by the command handler.
+type fakeDiscordInteractions struct {
+	handler discord.InteractionHandler
+	stopped bool
+}
+
+func (d *fakeDiscordInteractions) StartInteractions(guildID string, handler discord.InteractionHandler) error {
+	d.handler = handler
+	return nil
+}
+
+func (d *fakeDiscordInteractions) StopInteractions() { d.stopped = true }
+
+func newTestDiscordHandler(t *testing.T, allowedUsers ...string) (*DiscordCommandHandler, *fakeDiscordInteractions) {
+	t.Helper()
+	fake := &fakeDiscordInteractions{}
+	mutes := NewMuteList(zap.NewNop(), nil, DefaultMuteListConfig())
+	feedback := NewAlertFeedbackStore(zap.NewNop(), nil, DefaultAlertFeedbackConfig())
+	h := NewDiscordCommandHandler(zap.NewNop(), fake, nil, nil, mutes, feedback, nil, "guild", allowedUsers)
+	if err := h.Start(); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	return h, fake
+}
+
+func TestDiscordCommands_MuteButtonsRecordFeedback(t *testing.T) {
+	h, fake := newTestDiscordHandler(t)
+	h.feedback.RecordAlert(notifier.TradeAlert{
+		ID:            "alert1",
+		TraderAddress: testWallet,
+		ConditionID:   testMarket,
+		Reasons:       []notifier.AlertReason{notifier.AlertReasonNewWallet},
+	})
+	ctx := context.Background()
+
+	reply := fake.handler(ctx, discord.Interaction{UserName: "alice", Action: discord.ActionMuteWallet, Target: testWallet, AlertID: "alert1"})
+	if !strings.Contains(reply, "Muted wallet") {
+		t.Errorf("unexpected reply: %s", reply)
+	}
+	fake.handler(ctx, discord.Interaction{UserName: "alice", Action: discord.ActionMuteMarket, Target: testMarket, AlertID: "alert1"})
+
+	active := h.commands.mutes.Active()
+	if len(active) != 2 || active[0].CreatedBy != "alice" {
+		t.Fatalf("expected wallet and market mutes, got %+v", active)
+	}
+	if remaining := active[0].Until.Sub(active[0].CreatedAt); remaining != buttonMuteDuration {
+		t.Errorf("expected a 24h mute, got %v", remaining)
+	}
+
+	summary := h.feedback.Summary()
+	if summary.Overall.Muted != 1 || len(summary.Recent) != 2 {
+		t.Errorf("expected mutes recorded as feedback, got %+v", summary)
+	}
+}
+
+func TestDiscordCommands_FalsePositiveButton(t *testing.T) {
+	h, fake := newTestDiscordHandler(t)
+	h.feedback.RecordAlert(notifier.TradeAlert{
+		ID:      "alert1",
+		Reasons: []notifier.AlertReason{notifier.AlertReasonMassiveTrade},
+	})
+	ctx := context.Background()
+
+	reply := fake.handler(ctx, discord.Interaction{UserName: "alice", Action: discord.ActionFalsePositive, AlertID: "alert1"})
+	if !strings.Contains(reply, "false positive") || !strings.Contains(reply, "massive\\_trade") {
+		t.Errorf("unexpected reply: %s", reply)
+	}
+	if h.feedback.Summary().Overall.FalsePositives != 1 {
+		t.Error("expected false positive recorded")
+	}
+
+	reply = fake.handler(ctx, discord.Interaction{UserName: "alice", Action: discord.ActionFalsePositive, AlertID: "gone"})
+	if !strings.Contains(reply, "no longer tracked") {
+		t.Errorf("expected unknown alert reply, got: %s", reply)
+	}
+}
+
+func TestDiscordCommands_RejectsUnauthorizedUser(t *testing.T) {
+	h, fake := newTestDiscordHandler(t, "1001")
+
+	reply := fake.handler(context.Background(), discord.Interaction{UserID: "2002", Action: discord.ActionMuteWallet, Target: testWallet})
+	if !strings.Contains(reply, "not allowed") {
+		t.Errorf("expected a denial, got: %s", reply)
+	}
+	if len(h.commands.mutes.Active()) != 0 {
+		t.Error("expected unauthorized button not to mute")
+	}
+}
+
+func TestDiscordCommands_SlashMute(t *testing.T) {
+	h, fake := newTestDiscordHandler(t)
+	ctx := context.Background()
+
+	reply := fake.handler(ctx, discord.Interaction{UserName: "alice", Command: "mute", Options: map[string]string{"target": testMarket}})
+	if !strings.Contains(reply, "/polybot mute") {
+		t.Errorf("expected usage without a duration, got: %s", reply)
+	}
+
+	fake.handler(ctx, discord.Interaction{UserName: "alice", Command: "mute", Options: map[string]string{"target": testMarket, "duration": "3d"}})
+	if active := h.commands.mutes.Active(); len(active) != 1 || active[0].Kind != MuteKindMarket {
+		t.Fatalf("expected market mute, got %+v", active)
+	}
+
+	reply = fake.handler(ctx, discord.Interaction{Command: "mute", Options: map[string]string{}})
+	if !strings.Contains(reply, "**🔇 Active Mutes**") {
+		t.Errorf("expected Discord-formatted mute list, got: %s", reply)
+	}
+
+	h.Stop()
+	if !fake.stopped {
+		t.Error("expected interactions stopped")
+	}
+}
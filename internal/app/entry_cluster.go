@@ -0,0 +1,191 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/notifier"
+)
+
+// EntryCluster is a group of fresh wallets that bought the same outcome of a
+// market within the cluster window.
+type EntryCluster struct {
+	ConditionID string
+	TokenID     string
+	Wallets     []notifier.ClusterWallet // Largest first
+	Shares      float64
+	Notional    float64
+	First       time.Time // First buy in the cluster
+	Last        time.Time // Buy that completed the cluster
+}
+
+// enrich adds the cluster to an alert.
+func (c EntryCluster) enrich(alert *notifier.TradeAlert) {
+	alert.ClusterWallets = c.Wallets
+	alert.ClusterShares = c.Shares
+	alert.ClusterNotional = c.Notional
+	alert.ClusterSpanSecs = c.Last.Sub(c.First).Seconds()
+	alert.HasClusterInfo = true
+}
+
+// clusterEntry is one buy by a fresh wallet.
+type clusterEntry struct {
+	address  string
+	name     string
+	markets  int
+	at       time.Time
+	shares   float64
+	notional float64
+}
+
+// outcomeEntries holds the fresh-wallet buys of one market outcome.
+type outcomeEntries struct {
+	entries []clusterEntry       // Buys not yet part of a reported cluster
+	claimed map[string]time.Time // Wallets in a reported cluster -> their latest buy
+}
+
+// EntryClusters watches for insider rings that split a bet across many fresh
+// wallets, each too small to alert on alone. Buys of the same outcome by
+// wallets with few prior markets are grouped within a sliding window; once
+// enough distinct wallets with enough combined notional are in the window,
+// the cluster is reported once. Its wallets are then claimed until they stop
+// buying for a full window, so a second report needs a new group of wallets.
+type EntryClusters struct {
+	mu       sync.Mutex
+	outcomes map[string]*outcomeEntries // conditionID:tokenID -> buys
+}
+
+// NewEntryClusters creates an empty cluster tracker.
+func NewEntryClusters() *EntryClusters {
+	return &EntryClusters{
+		outcomes: make(map[string]*outcomeEntries),
+	}
+}
+
+// Record adds a buy by a wallet with uniqueMarkets prior markets and returns
+// the cluster it completes, if any.
+func (c *EntryClusters) Record(trade *NormalizedTrade, uniqueMarkets int, cfg TradeMonitorConfig) (EntryCluster, bool) {
+	if cfg.ClusterMinWallets < 2 || cfg.ClusterWindow <= 0 || !trade.IsBuy() ||
+		uniqueMarkets > cfg.ClusterMaxMarkets || trade.ConditionID == "" || trade.Wallet == "" {
+		return EntryCluster{}, false
+	}
+
+	key := trade.ConditionID + ":" + trade.TokenID
+	wallet := strings.ToLower(trade.Wallet)
+	cutoff := trade.Timestamp.Add(-cfg.ClusterWindow)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o, ok := c.outcomes[key]
+	if !ok {
+		o = &outcomeEntries{claimed: make(map[string]time.Time)}
+		c.outcomes[key] = o
+	}
+	o.prune(cutoff)
+
+	// Wallets already reported keep buying into the same cluster
+	if last, ok := o.claimed[wallet]; ok {
+		if trade.Timestamp.After(last) {
+			o.claimed[wallet] = trade.Timestamp
+		}
+		return EntryCluster{}, false
+	}
+
+	o.entries = append(o.entries, clusterEntry{
+		address:  trade.Wallet,
+		name:     trade.TraderName,
+		markets:  uniqueMarkets,
+		at:       trade.Timestamp,
+		shares:   trade.Size,
+		notional: trade.Notional,
+	})
+
+	cluster := EntryCluster{ConditionID: trade.ConditionID, TokenID: trade.TokenID}
+	wallets := make(map[string]*notifier.ClusterWallet)
+	lastBuy := make(map[string]time.Time)
+	for _, e := range o.entries {
+		k := strings.ToLower(e.address)
+		w, ok := wallets[k]
+		if !ok {
+			w = &notifier.ClusterWallet{
+				Address: e.address,
+				URL:     fmt.Sprintf("https://polymarket.com/profile/%s", e.address),
+			}
+			wallets[k] = w
+		}
+		if e.name != "" {
+			w.Name = e.name
+		}
+		w.UniqueMarkets = e.markets
+		w.Trades++
+		w.Shares += e.shares
+		w.Notional += e.notional
+		if e.at.After(lastBuy[k]) {
+			lastBuy[k] = e.at
+		}
+
+		cluster.Shares += e.shares
+		cluster.Notional += e.notional
+		if cluster.First.IsZero() || e.at.Before(cluster.First) {
+			cluster.First = e.at
+		}
+		if e.at.After(cluster.Last) {
+			cluster.Last = e.at
+		}
+	}
+	if len(wallets) < cfg.ClusterMinWallets || cluster.Notional < cfg.ClusterMinNotional {
+		return EntryCluster{}, false
+	}
+
+	for k, w := range wallets {
+		cluster.Wallets = append(cluster.Wallets, *w)
+		o.claimed[k] = lastBuy[k]
+	}
+	sort.Slice(cluster.Wallets, func(i, j int) bool {
+		a, b := cluster.Wallets[i], cluster.Wallets[j]
+		if a.Notional != b.Notional {
+			return a.Notional > b.Notional
+		}
+		return a.Address < b.Address
+	})
+	o.entries = nil
+	return cluster, true
+}
+
+// prune drops buys and claims older than the cutoff.
+func (o *outcomeEntries) prune(cutoff time.Time) {
+	kept := o.entries[:0]
+	for _, e := range o.entries {
+		if !e.at.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	o.entries = kept
+
+	for wallet, last := range o.claimed {
+		if last.Before(cutoff) {
+			delete(o.claimed, wallet)
+		}
+	}
+}
+
+// Retain drops the buys of markets that are no longer monitored.
+func (c *EntryClusters) Retain(conditionIDs []string) {
+	keep := make(map[string]bool, len(conditionIDs))
+	for _, id := range conditionIDs {
+		keep[id] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.outcomes {
+		conditionID, _, _ := strings.Cut(key, ":")
+		if !keep[conditionID] {
+			delete(c.outcomes, key)
+		}
+	}
+}
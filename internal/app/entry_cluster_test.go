@@ -0,0 +1,109 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+var clusterStart = time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+func clusterBuy(wallet string, minutes int, notional float64) *NormalizedTrade {
+	return &NormalizedTrade{
+		Wallet:      wallet,
+		ConditionID: "0xmarket",
+		TokenID:     "yes",
+		Side:        "BUY",
+		Price:       0.40,
+		Size:        notional / 0.40,
+		Notional:    notional,
+		Timestamp:   clusterStart.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+func TestEntryClusters_Cluster(t *testing.T) {
+	cfg := DefaultTradeMonitorConfig()
+	c := NewEntryClusters()
+
+	steps := []struct {
+		trade   *NormalizedTrade
+		markets int
+	}{
+		{clusterBuy("0xa", 0, 6000), 1},
+		{clusterBuy("0xA", 2, 2000), 1}, // Same wallet, summed
+		{clusterBuy("0xb", 5, 5000), 0},
+		{clusterBuy("0xexperienced", 6, 9000), 10}, // Too many markets
+		{&NormalizedTrade{Wallet: "0xseller", ConditionID: "0xmarket", TokenID: "yes", Side: "SELL", Notional: 9000, Timestamp: clusterStart}, 0},
+		{clusterBuy("0xc", 10, 4000), 2},
+	}
+	for _, s := range steps {
+		if _, ok := c.Record(s.trade, s.markets, cfg); ok {
+			t.Fatalf("unexpected cluster at %s", s.trade.Wallet)
+		}
+	}
+
+	cluster, ok := c.Record(clusterBuy("0xd", 20, 4000), 3, cfg)
+	if !ok {
+		t.Fatal("expected the 4th fresh wallet to complete a cluster")
+	}
+	if len(cluster.Wallets) != 4 || cluster.Notional != 21000 || cluster.Last.Sub(cluster.First) != 20*time.Minute {
+		t.Errorf("unexpected cluster: %+v", cluster)
+	}
+	top := cluster.Wallets[0]
+	if top.Address != "0xa" || top.Trades != 2 || top.Notional != 8000 || top.Shares != 20000 {
+		t.Errorf("unexpected top wallet: %+v", top)
+	}
+
+	// Claimed wallets keep buying without a second report
+	if _, ok := c.Record(clusterBuy("0xb", 25, 9000), 0, cfg); ok {
+		t.Error("expected claimed wallet not to start a new cluster")
+	}
+}
+
+func TestEntryClusters_MinNotional(t *testing.T) {
+	cfg := DefaultTradeMonitorConfig()
+	c := NewEntryClusters()
+
+	for i, wallet := range []string{"0xa", "0xb", "0xc", "0xd", "0xe"} {
+		if _, ok := c.Record(clusterBuy(wallet, i, 3000), 0, cfg); ok {
+			t.Fatalf("expected no cluster below $%.0f, got one at %s", cfg.ClusterMinNotional, wallet)
+		}
+	}
+	if _, ok := c.Record(clusterBuy("0xf", 5, 6000), 0, cfg); !ok {
+		t.Error("expected a cluster once combined notional reaches the minimum")
+	}
+}
+
+func TestEntryClusters_WindowExpiry(t *testing.T) {
+	cfg := DefaultTradeMonitorConfig()
+	c := NewEntryClusters()
+
+	c.Record(clusterBuy("0xa", 0, 8000), 0, cfg)
+	c.Record(clusterBuy("0xb", 5, 8000), 0, cfg)
+	c.Record(clusterBuy("0xc", 10, 8000), 0, cfg)
+
+	// The first buy has left the window by the time the 4th wallet arrives
+	if _, ok := c.Record(clusterBuy("0xd", 31, 8000), 0, cfg); ok {
+		t.Error("expected buys outside the window not to count")
+	}
+	if _, ok := c.Record(clusterBuy("0xe", 32, 8000), 0, cfg); !ok {
+		t.Error("expected a cluster from the buys within the window")
+	}
+}
+
+func TestEntryClusters_DisabledAndRetain(t *testing.T) {
+	cfg := DefaultTradeMonitorConfig()
+	cfg.ClusterMinWallets = 0
+	c := NewEntryClusters()
+
+	c.Record(clusterBuy("0xa", 0, 8000), 0, cfg)
+	if len(c.outcomes) != 0 {
+		t.Errorf("expected disabled clustering to record nothing, got %d outcomes", len(c.outcomes))
+	}
+
+	cfg.ClusterMinWallets = 4
+	c.Record(clusterBuy("0xa", 0, 8000), 0, cfg)
+	c.Retain([]string{"0xother"})
+	if len(c.outcomes) != 0 {
+		t.Errorf("expected unmonitored market dropped, got %d outcomes", len(c.outcomes))
+	}
+}
@@ -145,10 +145,12 @@ type AsymmetricExitStats struct {
 
 // HedgeAlert contains data for hedge-related notifications.
 type HedgeAlert struct {
+	ID          string // Removal event ID, set on follow-up alerts
 	Wallet      string
 	WalletURL   string
 	MarketTitle string
 	MarketURL   string
+	ConditionID string
 	Timestamp   time.Time
 	Reason      string // "hedge_removal", "asymmetric_exit", "hedge_followup"
 
@@ -220,6 +222,9 @@ type HedgeTracker struct {
 	dirty    bool
 	updateCh chan hedgeUpdate
 	doneCh   chan struct{}
+
+	// now returns the current time (the tape's time during replays)
+	now func() time.Time
 }
 
 // NewHedgeTracker creates a new hedge tracker.
@@ -244,6 +249,7 @@ func NewHedgeTracker(
 		lastPositionCheck: make(map[string]time.Time),
 		updateCh:          make(chan hedgeUpdate, 100),
 		doneCh:            make(chan struct{}),
+		now:               time.Now,
 	}
 }
 
@@ -358,7 +364,7 @@ func (ht *HedgeTracker) recordExitInternal(wallet string, record *ExitRecord) {
 		stats.RecentExits = stats.RecentExits[len(stats.RecentExits)-20:]
 	}
 
-	stats.LastUpdated = time.Now()
+	stats.LastUpdated = ht.now()
 }
 
 // periodicSave saves state periodically.
@@ -452,7 +458,7 @@ func (ht *HedgeTracker) processResolution(event *HedgeRemovalEvent, winningOutco
 	defer ht.mu.Unlock()
 
 	event.Resolved = true
-	event.ResolvedAt = time.Now()
+	event.ResolvedAt = ht.now()
 	event.WinningOutcome = winningOutcome
 
 	// Check if they removed the losing side (suspicious!)
@@ -477,7 +483,7 @@ func (ht *HedgeTracker) ShouldCheckPositions(wallet, conditionID string) bool {
 	defer ht.mu.Unlock()
 
 	key := wallet + ":" + conditionID
-	now := time.Now()
+	now := ht.now()
 
 	// Reset rate limit counter every minute
 	if now.Sub(ht.positionCheckReset) > time.Minute {
@@ -599,7 +605,7 @@ func (ht *HedgeTracker) ProcessTrade(
 			if reduction >= cfg.SignificantSellPct {
 				// Hedge removal detected!
 				event := &HedgeRemovalEvent{
-					ID:             fmt.Sprintf("%s:%s:%d", wallet, conditionID, time.Now().Unix()),
+					ID:             fmt.Sprintf("%s:%s:%d", wallet, conditionID, ht.now().Unix()),
 					Wallet:         wallet,
 					ConditionID:    conditionID,
 					MarketTitle:    marketTitle,
@@ -613,8 +619,8 @@ func (ht *HedgeTracker) ProcessTrade(
 					SoldPrice:      price,
 					YesSizeAfter:   yesSize,
 					NoSizeAfter:    noSize,
-					RemovedAt:      time.Now(),
-					AlertedAt:      time.Now(),
+					RemovedAt:      ht.now(),
+					AlertedAt:      ht.now(),
 				}
 
 				// Store event for resolution tracking
@@ -629,7 +635,7 @@ func (ht *HedgeTracker) ProcessTrade(
 					WalletURL:     fmt.Sprintf("https://polymarket.com/profile/%s", wallet),
 					MarketTitle:   marketTitle,
 					MarketURL:     fmt.Sprintf("https://polymarket.com/event/%s", marketSlug),
-					Timestamp:     time.Now(),
+					Timestamp:     ht.now(),
 					Reason:        "hedge_removal",
 					YesSizeBefore: prevHedge.YesSize,
 					NoSizeBefore:  prevHedge.NoSize,
@@ -660,7 +666,7 @@ func (ht *HedgeTracker) ProcessTrade(
 		NoSize:       noSize,
 		YesAvgPrice:  yesPrice,
 		NoAvgPrice:   noPrice,
-		LastUpdated:  time.Now(),
+		LastUpdated:  ht.now(),
 		IsHedged:     isHedged,
 	}
 
@@ -672,7 +678,7 @@ func (ht *HedgeTracker) ProcessTrade(
 		}
 	}
 	ht.hedgeStates[wallet].Positions[conditionID] = newPos
-	ht.hedgeStates[wallet].LastCheck = time.Now()
+	ht.hedgeStates[wallet].LastCheck = ht.now()
 	ht.dirty = true
 	ht.mu.Unlock()
 
@@ -684,7 +690,7 @@ func (ht *HedgeTracker) ProcessTrade(
 			alert := HedgeAlert{
 				Wallet:          wallet,
 				WalletURL:       fmt.Sprintf("https://polymarket.com/profile/%s", wallet),
-				Timestamp:       time.Now(),
+				Timestamp:       ht.now(),
 				Reason:          "asymmetric_exit",
 				AvgWinHoldTime:  time.Duration(stats.AvgWinHoldDuration) * time.Second,
 				AvgLossHoldTime: time.Duration(stats.AvgLossHoldDuration) * time.Second,
@@ -763,11 +769,13 @@ func (ht *HedgeTracker) GetPendingFollowUpAlerts() []HedgeAlert {
 	for _, event := range ht.pendingEvents {
 		if event.Resolved && event.RemovedLoser && !event.FollowUpAlerted {
 			alert := HedgeAlert{
+				ID:             event.ID,
 				Wallet:         event.Wallet,
 				WalletURL:      fmt.Sprintf("https://polymarket.com/profile/%s", event.Wallet),
 				MarketTitle:    event.MarketTitle,
 				MarketURL:      fmt.Sprintf("https://polymarket.com/event/%s", event.MarketSlug),
-				Timestamp:      time.Now(),
+				ConditionID:    event.ConditionID,
+				Timestamp:      ht.now(),
 				Reason:         "hedge_followup",
 				YesSizeBefore:  event.YesSizeBefore,
 				NoSizeBefore:   event.NoSizeBefore,
@@ -849,7 +857,7 @@ func (ht *HedgeTracker) Save(ctx context.Context) error {
 
 	snapshot := HedgeTrackerSnapshot{
 		Version:       1,
-		Timestamp:     time.Now(),
+		Timestamp:     ht.now(),
 		HedgeStates:   ht.hedgeStates,
 		PendingEvents: ht.pendingEvents,
 		ExitStats:     ht.exitStats,
@@ -0,0 +1,513 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// minBaselineStdDev floors the baseline's standard deviation (in USD), so a
+// market whose volume never varied doesn't score every trade as infinitely
+// anomalous.
+const minBaselineStdDev = 100.0
+
+// maxRecentMarketAlerts caps the market alerts kept for the dashboard.
+const maxRecentMarketAlerts = 10
+
+// MarketAnomalyConfig holds configuration for market-level anomaly detection.
+type MarketAnomalyConfig struct {
+	Enabled bool
+
+	// Volume spikes
+	Window            time.Duration // Volume window (e.g., 5m); windows start on multiples of it
+	BaselineWindows   int           // Prior windows the baseline is measured over (e.g., 12)
+	VolumeStdDevs     float64       // Standard deviations above the baseline mean to alert (e.g., 3)
+	VolumeMinNotional float64       // Minimum window notional for a volume spike (e.g., 25000)
+
+	// Price moves
+	PriceMove        float64       // Minimum price change within PriceWindow (e.g., 0.10 = 10¢)
+	PriceWindow      time.Duration // How far back a price move is measured (e.g., 15m)
+	PriceMinNotional float64       // Minimum notional traded during the move (e.g., 5000)
+
+	Cooldown   time.Duration // Minimum time between alerts of one kind per market
+	TopWallets int           // Contributing wallets listed per alert
+}
+
+// DefaultMarketAnomalyConfig returns sensible defaults.
+func DefaultMarketAnomalyConfig() MarketAnomalyConfig {
+	return MarketAnomalyConfig{
+		Enabled:           true,
+		Window:            5 * time.Minute,
+		BaselineWindows:   12,
+		VolumeStdDevs:     3.0,
+		VolumeMinNotional: 25000,
+		PriceMove:         0.10,
+		PriceWindow:       15 * time.Minute,
+		PriceMinNotional:  5000,
+		Cooldown:          1 * time.Hour,
+		TopWallets:        5,
+	}
+}
+
+// MarketAnomalyInfo is a market alert shown on the dashboard.
+type MarketAnomalyInfo struct {
+	Kind        string    `json:"kind"`
+	ConditionID string    `json:"condition_id"`
+	MarketTitle string    `json:"market_title"`
+	MarketURL   string    `json:"market_url,omitempty"`
+	Summary     string    `json:"summary"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// MarketAnomalyStats summarizes market anomaly detection.
+type MarketAnomalyStats struct {
+	Enabled      bool                `json:"enabled"`
+	Markets      int                 `json:"markets"` // Markets with a rolling series
+	VolumeSpikes int64               `json:"volume_spikes"`
+	PriceMoves   int64               `json:"price_moves"`
+	Muted        int64               `json:"muted"` // Alerts on muted markets
+	Recent       []MarketAnomalyInfo `json:"recent,omitempty"`
+}
+
+// volumeBucket is a market's trading in one window.
+type volumeBucket struct {
+	start    time.Time
+	trades   int
+	notional float64
+}
+
+// anomalyTrade is a trade kept for listing the wallets behind an anomaly.
+type anomalyTrade struct {
+	at       time.Time
+	wallet   string
+	name     string
+	tokenID  string
+	side     string
+	price    float64
+	notional float64
+}
+
+// marketSeries is the rolling time series of one market.
+type marketSeries struct {
+	title    string
+	slug     string
+	image    string
+	outcomes map[string]string // token ID -> outcome
+
+	firstSeen time.Time      // Timestamp of the market's first trade recorded
+	buckets   []volumeBucket // Oldest first; windows without trades are omitted
+	trades    []anomalyTrade // Oldest first, pruned to the longest window
+	lastAlert map[notifier.MarketAlertKind]time.Time
+}
+
+// MarketAnomalyMonitor keeps rolling per-market time series of trade count,
+// notional and price from the trade stream, and raises market-level alerts
+// when a window's volume is far above the market's baseline or the price moves
+// sharply in a short time. Time is taken from trade timestamps, so replays and
+// backfilled polls are measured as they happened.
+type MarketAnomalyMonitor struct {
+	logger *zap.Logger
+	target notifier.Notifier // Receives market alerts (may be nil)
+	mutes  *MuteList
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   MarketAnomalyConfig
+
+	mu           sync.Mutex
+	markets      map[string]*marketSeries // condition ID -> series
+	volumeSpikes int64
+	priceMoves   int64
+	muted        int64
+	recent       []MarketAnomalyInfo // Newest first
+}
+
+// NewMarketAnomalyMonitor creates a monitor that sends market alerts to target.
+func NewMarketAnomalyMonitor(logger *zap.Logger, config MarketAnomalyConfig, target notifier.Notifier) *MarketAnomalyMonitor {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &MarketAnomalyMonitor{
+		logger:  logger.Named("market-anomaly"),
+		target:  target,
+		config:  config,
+		markets: make(map[string]*marketSeries),
+	}
+}
+
+// SetMuteList sets the mutes checked before sending. Muted markets are still
+// counted and shown on the dashboard.
+func (m *MarketAnomalyMonitor) SetMuteList(mutes *MuteList) {
+	m.mutes = mutes
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (m *MarketAnomalyMonitor) getConfig() MarketAnomalyConfig {
+	m.configMu.RLock()
+	defer m.configMu.RUnlock()
+	return m.config
+}
+
+// UpdateConfig updates the monitor config.
+func (m *MarketAnomalyMonitor) UpdateConfig(cfg MarketAnomalyConfig) {
+	m.configMu.Lock()
+	m.config = cfg
+	m.configMu.Unlock()
+
+	m.logger.Info("market anomaly config updated",
+		zap.Bool("enabled", cfg.Enabled),
+		zap.Duration("window", cfg.Window),
+		zap.Float64("volumeStdDevs", cfg.VolumeStdDevs),
+		zap.Float64("priceMove", cfg.PriceMove),
+	)
+}
+
+// Record adds a trade to its market's series and sends any anomaly it completes.
+func (m *MarketAnomalyMonitor) Record(trade *NormalizedTrade) {
+	cfg := m.getConfig()
+	if !cfg.Enabled || cfg.Window <= 0 || trade.ConditionID == "" || trade.Timestamp.IsZero() {
+		return
+	}
+
+	m.mu.Lock()
+	series := m.markets[trade.ConditionID]
+	if series == nil {
+		series = &marketSeries{
+			outcomes:  make(map[string]string),
+			lastAlert: make(map[notifier.MarketAlertKind]time.Time),
+		}
+		m.markets[trade.ConditionID] = series
+	}
+	if series.firstSeen.IsZero() || trade.Timestamp.Before(series.firstSeen) {
+		series.firstSeen = trade.Timestamp
+	}
+	series.record(trade, cfg)
+
+	var alerts []notifier.MarketAlert
+	if alert, ok := m.checkVolume(trade, series, cfg); ok {
+		alerts = append(alerts, alert)
+	}
+	if alert, ok := m.checkPrice(trade, series, cfg); ok {
+		alerts = append(alerts, alert)
+	}
+	m.mu.Unlock()
+
+	for _, alert := range alerts {
+		m.send(alert)
+	}
+}
+
+// record adds a trade to the series and drops data older than the longest window.
+func (s *marketSeries) record(trade *NormalizedTrade, cfg MarketAnomalyConfig) {
+	if trade.MarketTitle != "" {
+		s.title = trade.MarketTitle
+	}
+	if trade.MarketSlug != "" {
+		s.slug = trade.MarketSlug
+	}
+	if trade.MarketImage != "" {
+		s.image = trade.MarketImage
+	}
+	if trade.Outcome != "" {
+		s.outcomes[trade.TokenID] = trade.Outcome
+	}
+
+	// Add the trade to its window's bucket, keeping buckets in order
+	start := trade.Timestamp.Truncate(cfg.Window)
+	idx := sort.Search(len(s.buckets), func(i int) bool { return !s.buckets[i].start.Before(start) })
+	if idx == len(s.buckets) || !s.buckets[idx].start.Equal(start) {
+		s.buckets = append(s.buckets, volumeBucket{})
+		copy(s.buckets[idx+1:], s.buckets[idx:])
+		s.buckets[idx] = volumeBucket{start: start}
+	}
+	s.buckets[idx].trades++
+	s.buckets[idx].notional += trade.Notional
+
+	idx = sort.Search(len(s.trades), func(i int) bool { return s.trades[i].at.After(trade.Timestamp) })
+	s.trades = append(s.trades, anomalyTrade{})
+	copy(s.trades[idx+1:], s.trades[idx:])
+	s.trades[idx] = anomalyTrade{
+		at:       trade.Timestamp,
+		wallet:   trade.Wallet,
+		name:     trade.TraderName,
+		tokenID:  trade.TokenID,
+		side:     trade.Side,
+		price:    trade.Price,
+		notional: trade.Notional,
+	}
+
+	// Keep the baseline's windows and the trades of the longest window
+	latest := s.buckets[len(s.buckets)-1].start
+	oldest := latest.Add(-time.Duration(cfg.BaselineWindows) * cfg.Window)
+	for len(s.buckets) > 0 && s.buckets[0].start.Before(oldest) {
+		s.buckets = s.buckets[1:]
+	}
+	keep := cfg.Window
+	if cfg.PriceWindow > keep {
+		keep = cfg.PriceWindow
+	}
+	cutoff := s.trades[len(s.trades)-1].at.Add(-keep)
+	for len(s.trades) > 0 && s.trades[0].at.Before(cutoff) {
+		s.trades = s.trades[1:]
+	}
+}
+
+// volumeBaseline returns the mean and standard deviation of notional per
+// window over the n windows before start. Windows without trades count as 0.
+func (s *marketSeries) volumeBaseline(start time.Time, window time.Duration, n int) (mean, stddev float64) {
+	values := make([]float64, n)
+	from := start.Add(-time.Duration(n) * window)
+	for _, b := range s.buckets {
+		if b.start.Before(from) || !b.start.Before(start) {
+			continue
+		}
+		values[int(b.start.Sub(from)/window)] = b.notional
+	}
+
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(n)
+	for _, v := range values {
+		stddev += (v - mean) * (v - mean)
+	}
+	stddev = math.Sqrt(stddev / float64(n))
+	return mean, stddev
+}
+
+// checkVolume flags a window whose notional is far above the market's baseline.
+// The market's baseline must have been observed in full, so nothing fires for a
+// market until it has been traded for BaselineWindows windows.
+func (m *MarketAnomalyMonitor) checkVolume(trade *NormalizedTrade, s *marketSeries, cfg MarketAnomalyConfig) (notifier.MarketAlert, bool) {
+	if cfg.BaselineWindows <= 0 || cfg.VolumeStdDevs <= 0 {
+		return notifier.MarketAlert{}, false
+	}
+
+	start := trade.Timestamp.Truncate(cfg.Window)
+	if s.firstSeen.After(start.Add(-time.Duration(cfg.BaselineWindows) * cfg.Window)) {
+		return notifier.MarketAlert{}, false
+	}
+
+	var current volumeBucket
+	for _, b := range s.buckets {
+		if b.start.Equal(start) {
+			current = b
+			break
+		}
+	}
+	if current.notional < cfg.VolumeMinNotional {
+		return notifier.MarketAlert{}, false
+	}
+
+	mean, stddev := s.volumeBaseline(start, cfg.Window, cfg.BaselineWindows)
+	z := (current.notional - mean) / math.Max(stddev, minBaselineStdDev)
+	if z < cfg.VolumeStdDevs || !s.cooledDown(notifier.MarketAlertVolumeSpike, trade.Timestamp, cfg.Cooldown) {
+		return notifier.MarketAlert{}, false
+	}
+
+	alert := s.newAlert(notifier.MarketAlertVolumeSpike, trade.ConditionID, start, trade.Timestamp, cfg.TopWallets)
+	alert.BaselineMean = mean
+	alert.BaselineStdDev = stddev
+	alert.ZScore = z
+	return alert, true
+}
+
+// checkPrice flags a token whose price moved more than PriceMove within
+// PriceWindow, measured from the lowest (or highest) trade in the window.
+func (m *MarketAnomalyMonitor) checkPrice(trade *NormalizedTrade, s *marketSeries, cfg MarketAnomalyConfig) (notifier.MarketAlert, bool) {
+	if cfg.PriceMove <= 0 || cfg.PriceWindow <= 0 || trade.Price <= 0 {
+		return notifier.MarketAlert{}, false
+	}
+
+	from := trade.Timestamp.Add(-cfg.PriceWindow)
+	var low, high *anomalyTrade
+	for i := range s.trades {
+		t := &s.trades[i]
+		if t.tokenID != trade.TokenID || t.at.Before(from) || t.at.After(trade.Timestamp) {
+			continue
+		}
+		if low == nil || t.price < low.price {
+			low = t
+		}
+		if high == nil || t.price > high.price {
+			high = t
+		}
+	}
+	if low == nil {
+		return notifier.MarketAlert{}, false
+	}
+
+	// The move runs from the opposite extreme to the current price
+	extreme := low
+	if high.price-trade.Price > trade.Price-low.price {
+		extreme = high
+	}
+	if math.Abs(trade.Price-extreme.price) < cfg.PriceMove {
+		return notifier.MarketAlert{}, false
+	}
+
+	alert := s.newAlert(notifier.MarketAlertPriceMove, trade.ConditionID, extreme.at, trade.Timestamp, cfg.TopWallets)
+	if alert.Notional < cfg.PriceMinNotional || !s.cooledDown(notifier.MarketAlertPriceMove, trade.Timestamp, cfg.Cooldown) {
+		return notifier.MarketAlert{}, false
+	}
+	alert.Outcome = s.outcomes[trade.TokenID]
+	alert.PriceBefore = extreme.price
+	alert.PriceAfter = trade.Price
+	return alert, true
+}
+
+// cooledDown reports whether the market can alert on kind again, and if so
+// starts a new cooldown.
+func (s *marketSeries) cooledDown(kind notifier.MarketAlertKind, at time.Time, cooldown time.Duration) bool {
+	if last, ok := s.lastAlert[kind]; ok && at.Before(last.Add(cooldown)) {
+		return false
+	}
+	s.lastAlert[kind] = at
+	return true
+}
+
+// newAlert builds a market alert over the trades in [start, end] with the
+// wallets that traded the most notional in it.
+func (s *marketSeries) newAlert(kind notifier.MarketAlertKind, conditionID string, start, end time.Time, topN int) notifier.MarketAlert {
+	alert := notifier.MarketAlert{
+		Kind:        kind,
+		ConditionID: conditionID,
+		MarketTitle: s.title,
+		MarketImage: s.image,
+		WindowStart: start,
+		WindowEnd:   end,
+		Timestamp:   end,
+	}
+	if s.slug != "" {
+		alert.MarketURL = fmt.Sprintf("https://polymarket.com/event/%s", s.slug)
+	}
+
+	wallets := make(map[string]*notifier.MarketAlertWallet)
+	for _, t := range s.trades {
+		if t.at.Before(start) || t.at.After(end) {
+			continue
+		}
+		alert.Trades++
+		alert.Notional += t.notional
+		if t.wallet == "" {
+			continue
+		}
+
+		key := strings.ToLower(t.wallet)
+		w := wallets[key]
+		if w == nil {
+			w = &notifier.MarketAlertWallet{
+				Address: t.wallet,
+				URL:     fmt.Sprintf("https://polymarket.com/profile/%s", t.wallet),
+			}
+			wallets[key] = w
+		}
+		w.Trades++
+		if t.name != "" {
+			w.Name = t.name
+		}
+		if t.side == "SELL" {
+			w.SellNotional += t.notional
+		} else {
+			w.BuyNotional += t.notional
+		}
+	}
+
+	for _, w := range wallets {
+		alert.TopWallets = append(alert.TopWallets, *w)
+	}
+	sort.Slice(alert.TopWallets, func(i, j int) bool {
+		a, b := alert.TopWallets[i], alert.TopWallets[j]
+		if a.Notional() != b.Notional() {
+			return a.Notional() > b.Notional()
+		}
+		return a.Address < b.Address
+	})
+	if topN > 0 && len(alert.TopWallets) > topN {
+		alert.TopWallets = alert.TopWallets[:topN]
+	}
+	return alert
+}
+
+// send counts the alert, keeps it for the dashboard and delivers it unless
+// the market is muted.
+func (m *MarketAnomalyMonitor) send(alert notifier.MarketAlert) {
+	muted := m.mutes != nil && m.mutes.IsMuted(notifier.TradeAlert{ConditionID: alert.ConditionID})
+
+	m.mu.Lock()
+	switch alert.Kind {
+	case notifier.MarketAlertVolumeSpike:
+		m.volumeSpikes++
+	case notifier.MarketAlertPriceMove:
+		m.priceMoves++
+	}
+	if muted {
+		m.muted++
+	}
+	m.recent = append([]MarketAnomalyInfo{{
+		Kind:        string(alert.Kind),
+		ConditionID: alert.ConditionID,
+		MarketTitle: alert.MarketTitle,
+		MarketURL:   alert.MarketURL,
+		Summary:     alert.Summary(),
+		Timestamp:   alert.Timestamp,
+	}}, m.recent...)
+	if len(m.recent) > maxRecentMarketAlerts {
+		m.recent = m.recent[:maxRecentMarketAlerts]
+	}
+	m.mu.Unlock()
+
+	m.logger.Info("MARKET ANOMALY",
+		zap.String("kind", string(alert.Kind)),
+		zap.String("market", alert.MarketTitle),
+		zap.String("summary", alert.Summary()),
+		zap.Int("wallets", len(alert.TopWallets)),
+		zap.Bool("muted", muted),
+	)
+
+	if muted {
+		return
+	}
+	if ma, ok := m.target.(notifier.MarketAlertNotifier); ok {
+		ma.SendMarketAlert(alert)
+	}
+}
+
+// Retain drops the series of markets that are no longer monitored.
+func (m *MarketAnomalyMonitor) Retain(conditionIDs []string) {
+	keep := make(map[string]bool, len(conditionIDs))
+	for _, id := range conditionIDs {
+		keep[id] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id := range m.markets {
+		if !keep[id] {
+			delete(m.markets, id)
+		}
+	}
+}
+
+// Stats returns market anomaly counters and the most recent market alerts.
+func (m *MarketAnomalyMonitor) Stats() MarketAnomalyStats {
+	cfg := m.getConfig()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return MarketAnomalyStats{
+		Enabled:      cfg.Enabled,
+		Markets:      len(m.markets),
+		VolumeSpikes: m.volumeSpikes,
+		PriceMoves:   m.priceMoves,
+		Muted:        m.muted,
+		Recent:       append([]MarketAnomalyInfo(nil), m.recent...),
+	}
+}
@@ -0,0 +1,256 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// marketAlertCapture records market alerts sent through it.
+type marketAlertCapture struct {
+	captureNotifier
+	marketMu sync.Mutex
+	market   []notifier.MarketAlert
+}
+
+func (c *marketAlertCapture) SendMarketAlert(alert notifier.MarketAlert) {
+	c.marketMu.Lock()
+	defer c.marketMu.Unlock()
+	c.market = append(c.market, alert)
+}
+
+func (c *marketAlertCapture) MarketAlerts() []notifier.MarketAlert {
+	c.marketMu.Lock()
+	defer c.marketMu.Unlock()
+	return append([]notifier.MarketAlert(nil), c.market...)
+}
+
+var anomalyStart = time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+func anomalyTradeAt(at time.Time, wallet, side string, price, notional float64) *NormalizedTrade {
+	return &NormalizedTrade{
+		Key:         fmt.Sprintf("%s-%d", wallet, at.UnixNano()),
+		Wallet:      wallet,
+		TokenID:     "tok-yes",
+		ConditionID: "0xmarket",
+		MarketTitle: "Will it rain?",
+		MarketSlug:  "will-it-rain",
+		Outcome:     "Yes",
+		Side:        side,
+		Price:       price,
+		Size:        notional / price,
+		Notional:    notional,
+		Timestamp:   at,
+	}
+}
+
+// seedBaseline records one quiet trade per window for the full baseline.
+func seedBaseline(m *MarketAnomalyMonitor, cfg MarketAnomalyConfig) time.Time {
+	for i := 0; i < cfg.BaselineWindows; i++ {
+		m.Record(anomalyTradeAt(anomalyStart.Add(time.Duration(i)*cfg.Window), "0xquiet", "BUY", 0.50, 1000))
+	}
+	return anomalyStart.Add(time.Duration(cfg.BaselineWindows) * cfg.Window)
+}
+
+func TestMarketAnomaly_VolumeSpike(t *testing.T) {
+	cfg := DefaultMarketAnomalyConfig()
+	target := &marketAlertCapture{}
+	m := NewMarketAnomalyMonitor(zap.NewNop(), cfg, target)
+
+	spike := seedBaseline(m, cfg)
+	m.Record(anomalyTradeAt(spike, "0xWhale", "BUY", 0.50, 15000))
+	m.Record(anomalyTradeAt(spike.Add(time.Minute), "0xshark", "BUY", 0.50, 8000))
+	if got := len(target.MarketAlerts()); got != 0 {
+		t.Fatalf("expected no alert below the min notional, got %d", got)
+	}
+	m.Record(anomalyTradeAt(spike.Add(2*time.Minute), "0xwhale", "SELL", 0.50, 5000))
+
+	alerts := target.MarketAlerts()
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 market alert, got %d", len(alerts))
+	}
+	a := alerts[0]
+	if a.Kind != notifier.MarketAlertVolumeSpike || a.Trades != 3 || a.Notional != 28000 {
+		t.Errorf("unexpected alert: %+v", a)
+	}
+	if a.BaselineMean != 1000 || a.BaselineStdDev != 0 || a.ZScore != 270 {
+		t.Errorf("unexpected baseline: mean %v, stddev %v, z %v", a.BaselineMean, a.BaselineStdDev, a.ZScore)
+	}
+	if !a.WindowStart.Equal(spike) || a.MarketURL != "https://polymarket.com/event/will-it-rain" {
+		t.Errorf("unexpected window or URL: %v %s", a.WindowStart, a.MarketURL)
+	}
+
+	// Wallets are combined case-insensitively and ranked by notional
+	if len(a.TopWallets) != 2 {
+		t.Fatalf("expected 2 top wallets, got %+v", a.TopWallets)
+	}
+	whale := a.TopWallets[0]
+	if whale.Address != "0xWhale" || whale.Trades != 2 || whale.BuyNotional != 15000 || whale.SellNotional != 5000 {
+		t.Errorf("unexpected top wallet: %+v", whale)
+	}
+	if a.TopWallets[1].Address != "0xshark" {
+		t.Errorf("expected 0xshark second, got %+v", a.TopWallets[1])
+	}
+
+	// Cooldown holds back the next spike
+	m.Record(anomalyTradeAt(spike.Add(3*time.Minute), "0xwhale", "BUY", 0.50, 30000))
+	if got := len(target.MarketAlerts()); got != 1 {
+		t.Errorf("expected cooldown to suppress the repeat, got %d alerts", got)
+	}
+
+	stats := m.Stats()
+	if stats.Markets != 1 || stats.VolumeSpikes != 1 || len(stats.Recent) != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMarketAnomaly_BaselineWarmUp(t *testing.T) {
+	cfg := DefaultMarketAnomalyConfig()
+	target := &marketAlertCapture{}
+	m := NewMarketAnomalyMonitor(zap.NewNop(), cfg, target)
+
+	// Without a full baseline observed, heavy volume is not an anomaly
+	m.Record(anomalyTradeAt(anomalyStart, "0xwhale", "BUY", 0.50, 100000))
+	if got := len(target.MarketAlerts()); got != 0 {
+		t.Errorf("expected no alert before the baseline fills, got %d", got)
+	}
+}
+
+func TestMarketAnomaly_NewMarketWarmUp(t *testing.T) {
+	cfg := DefaultMarketAnomalyConfig()
+	target := &marketAlertCapture{}
+	m := NewMarketAnomalyMonitor(zap.NewNop(), cfg, target)
+
+	// Another market's history is not a baseline for a market first seen now
+	spike := seedBaseline(m, cfg)
+	trade := anomalyTradeAt(spike, "0xwhale", "BUY", 0.50, 100000)
+	trade.ConditionID = "0xnew"
+	m.Record(trade)
+	if got := len(target.MarketAlerts()); got != 0 {
+		t.Errorf("expected no alert for a newly seen market, got %d", got)
+	}
+}
+
+func TestMarketAnomaly_VolumeWithinBaseline(t *testing.T) {
+	cfg := DefaultMarketAnomalyConfig()
+	cfg.VolumeMinNotional = 0
+	target := &marketAlertCapture{}
+	m := NewMarketAnomalyMonitor(zap.NewNop(), cfg, target)
+
+	// Windows alternating $1000 and $50000 make $60000 ordinary
+	for i := 0; i < cfg.BaselineWindows; i++ {
+		notional := 1000.0
+		if i%2 == 1 {
+			notional = 50000
+		}
+		m.Record(anomalyTradeAt(anomalyStart.Add(time.Duration(i)*cfg.Window), "0xquiet", "BUY", 0.50, notional))
+	}
+	m.Record(anomalyTradeAt(anomalyStart.Add(time.Duration(cfg.BaselineWindows)*cfg.Window), "0xwhale", "BUY", 0.50, 60000))
+
+	if got := len(target.MarketAlerts()); got != 0 {
+		t.Errorf("expected no alert within 3 std devs, got %d", got)
+	}
+}
+
+func TestMarketAnomaly_PriceMove(t *testing.T) {
+	cfg := DefaultMarketAnomalyConfig()
+	target := &marketAlertCapture{}
+	m := NewMarketAnomalyMonitor(zap.NewNop(), cfg, target)
+
+	m.Record(anomalyTradeAt(anomalyStart, "0xa", "BUY", 0.40, 3000))
+	m.Record(anomalyTradeAt(anomalyStart.Add(5*time.Minute), "0xb", "BUY", 0.45, 2000))
+	if got := len(target.MarketAlerts()); got != 0 {
+		t.Fatalf("expected no alert for a 5¢ move, got %d", got)
+	}
+	m.Record(anomalyTradeAt(anomalyStart.Add(10*time.Minute), "0xb", "BUY", 0.52, 4000))
+
+	alerts := target.MarketAlerts()
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 market alert, got %d", len(alerts))
+	}
+	a := alerts[0]
+	if a.Kind != notifier.MarketAlertPriceMove || a.Outcome != "Yes" || a.PriceBefore != 0.40 || a.PriceAfter != 0.52 {
+		t.Errorf("unexpected alert: %+v", a)
+	}
+	if a.Trades != 3 || a.Notional != 9000 || !a.WindowStart.Equal(anomalyStart) {
+		t.Errorf("unexpected window: %+v", a)
+	}
+	if len(a.TopWallets) != 2 || a.TopWallets[0].Address != "0xb" || a.TopWallets[0].Notional() != 6000 {
+		t.Errorf("unexpected top wallets: %+v", a.TopWallets)
+	}
+
+	// Moves older than the price window don't count
+	m.Record(anomalyTradeAt(anomalyStart.Add(2*time.Hour), "0xc", "SELL", 0.60, 9000))
+	m.Record(anomalyTradeAt(anomalyStart.Add(2*time.Hour+20*time.Minute), "0xc", "SELL", 0.52, 9000))
+	if got := len(target.MarketAlerts()); got != 1 {
+		t.Errorf("expected moves outside the window to be ignored, got %d alerts", got)
+	}
+}
+
+func TestMarketAnomaly_PriceMoveMinNotional(t *testing.T) {
+	cfg := DefaultMarketAnomalyConfig()
+	target := &marketAlertCapture{}
+	m := NewMarketAnomalyMonitor(zap.NewNop(), cfg, target)
+
+	m.Record(anomalyTradeAt(anomalyStart, "0xa", "SELL", 0.70, 500))
+	m.Record(anomalyTradeAt(anomalyStart.Add(time.Minute), "0xa", "SELL", 0.55, 500))
+
+	if got := len(target.MarketAlerts()); got != 0 {
+		t.Errorf("expected thin moves to be ignored, got %d alerts", got)
+	}
+}
+
+func TestMarketAnomaly_Muted(t *testing.T) {
+	cfg := DefaultMarketAnomalyConfig()
+	target := &marketAlertCapture{}
+	m := NewMarketAnomalyMonitor(zap.NewNop(), cfg, target)
+	mutes := NewMuteList(zap.NewNop(), nil, DefaultMuteListConfig())
+	mutes.Mute(MuteKindMarket, "0xmarket", time.Hour, "alice")
+	m.SetMuteList(mutes)
+
+	m.Record(anomalyTradeAt(anomalyStart, "0xa", "BUY", 0.40, 6000))
+	m.Record(anomalyTradeAt(anomalyStart.Add(time.Minute), "0xa", "BUY", 0.55, 6000))
+
+	if got := len(target.MarketAlerts()); got != 0 {
+		t.Errorf("expected muted market not to alert, got %d", got)
+	}
+	if stats := m.Stats(); stats.PriceMoves != 1 || stats.Muted != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMarketAnomaly_DisabledAndRetain(t *testing.T) {
+	cfg := DefaultMarketAnomalyConfig()
+	cfg.Enabled = false
+	m := NewMarketAnomalyMonitor(zap.NewNop(), cfg, nil)
+
+	m.Record(anomalyTradeAt(anomalyStart, "0xa", "BUY", 0.40, 6000))
+	if stats := m.Stats(); stats.Markets != 0 {
+		t.Errorf("expected disabled monitor to record nothing, got %+v", stats)
+	}
+
+	cfg.Enabled = true
+	m.UpdateConfig(cfg)
+	m.Record(anomalyTradeAt(anomalyStart, "0xa", "BUY", 0.40, 6000))
+	m.Retain([]string{"0xother"})
+	if stats := m.Stats(); stats.Markets != 0 {
+		t.Errorf("expected unmonitored market dropped, got %+v", stats)
+	}
+}
+
+func TestTradeMonitor_RecordsMarketAnomalies(t *testing.T) {
+	monitor := NewTradeMonitor(nil, nil, nil, nil, nil, nil, DefaultTradeMonitorConfig())
+	anomalies := NewMarketAnomalyMonitor(zap.NewNop(), DefaultMarketAnomalyConfig(), nil)
+	monitor.SetMarketAnomalyMonitor(anomalies)
+
+	// Trades below the alert minimum still feed the market series
+	monitor.processNormalizedTrade(t.Context(), anomalyTradeAt(anomalyStart, "0xa", "BUY", 0.40, 10))
+	if stats := anomalies.Stats(); stats.Markets != 1 {
+		t.Errorf("expected the trade recorded, got %+v", stats)
+	}
+}
@@ -0,0 +1,435 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/gist"
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// MessageThreadStoreConfig holds configuration for the message thread store.
+type MessageThreadStoreConfig struct {
+	Enabled          bool
+	AnnotateOutcomes bool          // Add the market outcome to thread roots on resolution
+	Retention        time.Duration // Threads idle longer than this start over
+	MaxThreads       int           // Threads kept (least recently used are dropped first)
+
+	// Persistence
+	GistID       string
+	FileName     string
+	SaveInterval time.Duration
+}
+
+// DefaultMessageThreadStoreConfig returns sensible defaults.
+func DefaultMessageThreadStoreConfig() MessageThreadStoreConfig {
+	return MessageThreadStoreConfig{
+		Enabled:          true,
+		AnnotateOutcomes: true,
+		Retention:        7 * 24 * time.Hour,
+		MaxThreads:       2000,
+		FileName:         "message_threads.json",
+		SaveInterval:     1 * time.Minute,
+	}
+}
+
+// MessageThread is the first message sent per channel about a wallet and market.
+type MessageThread struct {
+	Wallet      string                `json:"wallet"`
+	ConditionID string                `json:"condition_id"`
+	Roots       []notifier.MessageRef `json:"roots"` // One per channel
+	Replies     int                   `json:"replies"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// root returns the thread root on a channel.
+func (t *MessageThread) root(channel string) (notifier.MessageRef, bool) {
+	for _, ref := range t.Roots {
+		if ref.Channel == channel {
+			return ref, true
+		}
+	}
+	return notifier.MessageRef{}, false
+}
+
+// MessageThreadsSnapshot is the persisted state format.
+type MessageThreadsSnapshot struct {
+	Version   int                       `json:"version"`
+	Timestamp time.Time                 `json:"timestamp"`
+	Threads   map[string]*MessageThread `json:"threads"` // wallet:conditionID -> thread
+}
+
+// MessageThreadStore remembers the message each channel sent for the first
+// alert about a wallet and market, so later alerts (hedge removal, resolution,
+// repeat trades) are posted as replies and the market outcome can be added
+// to the original message.
+// Implements notifier.MessageThreads interface.
+type MessageThreadStore struct {
+	logger     *zap.Logger
+	gistClient gist.Storage
+	now        func() time.Time
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   MessageThreadStoreConfig
+
+	mu      sync.RWMutex
+	threads map[string]*MessageThread // wallet:conditionID -> thread
+
+	// Persistence
+	dirty  bool
+	doneCh chan struct{}
+}
+
+// NewMessageThreadStore creates an empty thread store.
+func NewMessageThreadStore(logger *zap.Logger, gistClient gist.Storage, config MessageThreadStoreConfig) *MessageThreadStore {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &MessageThreadStore{
+		logger:     logger.Named("message-threads"),
+		gistClient: gistClient,
+		config:     config,
+		now:        time.Now,
+		threads:    make(map[string]*MessageThread),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// IsEnabled returns true if threads are persisted.
+func (ts *MessageThreadStore) IsEnabled() bool {
+	cfg := ts.getConfig()
+	return ts.gistClient != nil && cfg.GistID != ""
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (ts *MessageThreadStore) getConfig() MessageThreadStoreConfig {
+	ts.configMu.RLock()
+	defer ts.configMu.RUnlock()
+	return ts.config
+}
+
+// UpdateConfig updates the thread store config.
+func (ts *MessageThreadStore) UpdateConfig(cfg MessageThreadStoreConfig) {
+	ts.configMu.Lock()
+	defer ts.configMu.Unlock()
+	ts.config = cfg
+}
+
+// isExpired reports whether a thread has been idle past the retention.
+func (ts *MessageThreadStore) isExpired(t *MessageThread, retention time.Duration) bool {
+	return retention > 0 && ts.now().Sub(t.UpdatedAt) > retention
+}
+
+// ThreadRoot returns the message that started the thread on a channel.
+// Implements notifier.MessageThreads interface.
+func (ts *MessageThreadStore) ThreadRoot(threadKey, channel string) (notifier.MessageRef, bool) {
+	cfg := ts.getConfig()
+	if !cfg.Enabled {
+		return notifier.MessageRef{}, false
+	}
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	t, ok := ts.threads[threadKey]
+	if !ok || ts.isExpired(t, cfg.Retention) {
+		return notifier.MessageRef{}, false
+	}
+	return t.root(channel)
+}
+
+// RecordMessage stores a delivered message. The first message per channel
+// stays the thread root; later ones count as replies.
+// Implements notifier.MessageThreads interface.
+func (ts *MessageThreadStore) RecordMessage(threadKey string, ref notifier.MessageRef) {
+	cfg := ts.getConfig()
+	if !cfg.Enabled || threadKey == "" {
+		return
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	t, ok := ts.threads[threadKey]
+	if !ok || ts.isExpired(t, cfg.Retention) {
+		wallet, conditionID, _ := strings.Cut(threadKey, ":")
+		t = &MessageThread{Wallet: wallet, ConditionID: conditionID}
+		ts.threads[threadKey] = t
+	}
+	if _, hasRoot := t.root(ref.Channel); hasRoot {
+		t.Replies++
+	} else {
+		t.Roots = append(t.Roots, ref)
+	}
+	t.UpdatedAt = ts.now()
+	ts.pruneLocked(cfg.MaxThreads)
+	ts.dirty = true
+}
+
+// pruneLocked drops the least recently used threads beyond maxThreads (must hold lock).
+func (ts *MessageThreadStore) pruneLocked(maxThreads int) {
+	if maxThreads <= 0 || len(ts.threads) <= maxThreads {
+		return
+	}
+
+	keys := make([]string, 0, len(ts.threads))
+	for key := range ts.threads {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return ts.threads[keys[i]].UpdatedAt.Before(ts.threads[keys[j]].UpdatedAt)
+	})
+	for _, key := range keys[:len(keys)-maxThreads] {
+		delete(ts.threads, key)
+	}
+}
+
+// Roots returns the thread roots for a wallet and market on every channel.
+func (ts *MessageThreadStore) Roots(wallet, conditionID string) []notifier.MessageRef {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	t, ok := ts.threads[strings.ToLower(wallet)+":"+conditionID]
+	if !ok {
+		return nil
+	}
+	return append([]notifier.MessageRef(nil), t.Roots...)
+}
+
+// Count returns the number of threads tracked.
+func (ts *MessageThreadStore) Count() int {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return len(ts.threads)
+}
+
+// MessageAnnotator edits a message a channel sent.
+// Implemented by notifier.QueuedNotifier.
+type MessageAnnotator interface {
+	Name() string
+	AnnotateMessage(ctx context.Context, ref notifier.MessageRef, note string) error
+}
+
+// AnnotateOutcomes adds the market outcome to the thread roots of resolved
+// alerts, on whichever of the channels sent them. Returns the number of
+// messages annotated.
+func (ts *MessageThreadStore) AnnotateOutcomes(ctx context.Context, resolved []TrackedAlert, channels []MessageAnnotator) int {
+	cfg := ts.getConfig()
+	if !cfg.Enabled || !cfg.AnnotateOutcomes || len(resolved) == 0 {
+		return 0
+	}
+
+	byName := make(map[string]MessageAnnotator, len(channels))
+	for _, ch := range channels {
+		byName[ch.Name()] = ch
+	}
+
+	// Alerts on the same wallet and market share a thread
+	byThread := make(map[string][]TrackedAlert)
+	var keys []string
+	for _, a := range resolved {
+		key := strings.ToLower(a.Wallet) + ":" + a.ConditionID
+		if _, seen := byThread[key]; !seen {
+			keys = append(keys, key)
+		}
+		byThread[key] = append(byThread[key], a)
+	}
+
+	annotated := 0
+	for _, key := range keys {
+		alerts := byThread[key]
+		roots := ts.Roots(alerts[0].Wallet, alerts[0].ConditionID)
+		if len(roots) == 0 {
+			continue
+		}
+
+		note := outcomeNote(alerts)
+		for _, ref := range roots {
+			ch, ok := byName[ref.Channel]
+			if !ok {
+				continue // Channel removed since the alert was sent
+			}
+
+			annotateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			err := ch.AnnotateMessage(annotateCtx, ref, note)
+			cancel()
+			if err != nil {
+				ts.logger.Warn("failed to annotate alert with outcome",
+					zap.String("channel", ref.Channel),
+					zap.String("messageId", ref.MessageID),
+					zap.Error(err),
+				)
+				continue
+			}
+			annotated++
+		}
+	}
+
+	if annotated > 0 {
+		ts.logger.Info("annotated alerts with market outcomes",
+			zap.Int("messages", annotated),
+		)
+	}
+	return annotated
+}
+
+// outcomeNote describes how a wallet's alerted trades in a resolved market did.
+func outcomeNote(alerts []TrackedAlert) string {
+	wins := 0
+	pnl := 0.0
+	for _, a := range alerts {
+		if a.Won {
+			wins++
+		}
+		pnl += a.CopyPnl
+	}
+
+	result := "✅ Alert won"
+	switch {
+	case len(alerts) > 1:
+		result = fmt.Sprintf("📊 %d of %d alerts won", wins, len(alerts))
+	case wins == 0:
+		result = "❌ Alert lost"
+	}
+
+	return fmt.Sprintf("🏁 Resolved: %s — %s (copy P&L %s)",
+		alerts[0].WinningOutcome, result, formatSignedUSD(pnl))
+}
+
+// formatSignedUSD formats a dollar amount with an explicit sign.
+func formatSignedUSD(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.2f", -v)
+	}
+	return fmt.Sprintf("+$%.2f", v)
+}
+
+// Start begins periodic saving.
+func (ts *MessageThreadStore) Start(ctx context.Context) {
+	go ts.periodicSave(ctx)
+}
+
+// Stop gracefully shuts down, saving pending changes.
+func (ts *MessageThreadStore) Stop() {
+	close(ts.doneCh)
+}
+
+// periodicSave saves state periodically.
+func (ts *MessageThreadStore) periodicSave(ctx context.Context) {
+	interval := ts.getConfig().SaveInterval
+	if interval <= 0 {
+		interval = DefaultMessageThreadStoreConfig().SaveInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Final save on shutdown
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = ts.Save(saveCtx)
+			cancel()
+			return
+		case <-ts.doneCh:
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = ts.Save(saveCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			if err := ts.Save(ctx); err != nil {
+				ts.logger.Warn("failed to save message threads", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Load loads state from gist.
+func (ts *MessageThreadStore) Load(ctx context.Context) error {
+	if !ts.IsEnabled() {
+		return nil
+	}
+
+	cfg := ts.getConfig()
+	content, err := ts.gistClient.Load(ctx, cfg.FileName, cfg.GistID)
+	if err != nil {
+		return fmt.Errorf("load message threads: %w", err)
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	var snapshot MessageThreadsSnapshot
+	if err := json.Unmarshal([]byte(content), &snapshot); err != nil {
+		return fmt.Errorf("unmarshal message threads: %w", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for key, t := range snapshot.Threads {
+		if _, exists := ts.threads[key]; !exists && !ts.isExpired(t, cfg.Retention) {
+			ts.threads[key] = t // Keep threads started before the load finished
+		}
+	}
+	ts.pruneLocked(cfg.MaxThreads)
+
+	ts.logger.Info("loaded message threads",
+		zap.Int("threads", len(ts.threads)),
+	)
+
+	return nil
+}
+
+// Save saves state to gist.
+func (ts *MessageThreadStore) Save(ctx context.Context) error {
+	if !ts.IsEnabled() {
+		return nil
+	}
+
+	ts.mu.Lock()
+	if !ts.dirty {
+		ts.mu.Unlock()
+		return nil
+	}
+
+	snapshot := MessageThreadsSnapshot{
+		Version:   1,
+		Timestamp: time.Now(),
+		Threads:   ts.threads,
+	}
+
+	// Marshal under the lock since the map is shared
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	ts.dirty = false
+	ts.mu.Unlock()
+
+	if err != nil {
+		ts.mu.Lock()
+		ts.dirty = true
+		ts.mu.Unlock()
+		return fmt.Errorf("marshal message threads: %w", err)
+	}
+
+	cfg := ts.getConfig()
+	if err := ts.gistClient.Save(ctx, cfg.FileName, string(data), cfg.GistID); err != nil {
+		ts.mu.Lock()
+		ts.dirty = true
+		ts.mu.Unlock()
+		return fmt.Errorf("save message threads: %w", err)
+	}
+
+	ts.logger.Debug("saved message threads",
+		zap.Int("threads", len(snapshot.Threads)),
+	)
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"polybot/clients/notifier"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// recordingAnnotator records notes added to messages on a channel.
+type recordingAnnotator struct {
+	name  string
+	notes map[string]string // messageID -> note
+}
+
+func (a *recordingAnnotator) Name() string { return a.name }
+
+func (a *recordingAnnotator) AnnotateMessage(ctx context.Context, ref notifier.MessageRef, note string) error {
+	if a.notes == nil {
+		a.notes = make(map[string]string)
+	}
+	a.notes[ref.MessageID] = note
+	return nil
+}
+
+func TestMessageThreadStore_KeepsFirstMessagePerChannel(t *testing.T) {
+	ts := NewMessageThreadStore(zap.NewNop(), nil, DefaultMessageThreadStoreConfig())
+	key := notifier.TradeAlert{TraderAddress: "0xABC", ConditionID: "0xmarket"}.ThreadKey()
+
+	ts.RecordMessage(key, notifier.MessageRef{Channel: "discord", MessageID: "1"})
+	ts.RecordMessage(key, notifier.MessageRef{Channel: "discord", MessageID: "2"})
+	ts.RecordMessage(key, notifier.MessageRef{Channel: "telegram", MessageID: "9"})
+
+	if root, ok := ts.ThreadRoot(key, "discord"); !ok || root.MessageID != "1" {
+		t.Errorf("expected first discord message as root, got %+v", root)
+	}
+	if root, ok := ts.ThreadRoot(key, "telegram"); !ok || root.MessageID != "9" {
+		t.Errorf("expected telegram root, got %+v", root)
+	}
+	if roots := ts.Roots("0xabc", "0xmarket"); len(roots) != 2 {
+		t.Errorf("expected one root per channel, got %+v", roots)
+	}
+}
+
+func TestMessageThreadStore_ExpiresIdleThreads(t *testing.T) {
+	cfg := DefaultMessageThreadStoreConfig()
+	cfg.Retention = time.Hour
+	ts := NewMessageThreadStore(zap.NewNop(), nil, cfg)
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	ts.now = func() time.Time { return now }
+
+	ts.RecordMessage("0xabc:0xmarket", notifier.MessageRef{Channel: "discord", MessageID: "1"})
+	now = now.Add(2 * time.Hour)
+
+	if _, ok := ts.ThreadRoot("0xabc:0xmarket", "discord"); ok {
+		t.Error("expected idle thread to expire")
+	}
+	ts.RecordMessage("0xabc:0xmarket", notifier.MessageRef{Channel: "discord", MessageID: "2"})
+	if root, _ := ts.ThreadRoot("0xabc:0xmarket", "discord"); root.MessageID != "2" {
+		t.Errorf("expected new alert to start a new thread, got %+v", root)
+	}
+}
+
+func TestMessageThreadStore_Disabled(t *testing.T) {
+	cfg := DefaultMessageThreadStoreConfig()
+	cfg.Enabled = false
+	ts := NewMessageThreadStore(zap.NewNop(), nil, cfg)
+
+	ts.RecordMessage("0xabc:0xmarket", notifier.MessageRef{Channel: "discord", MessageID: "1"})
+	if _, ok := ts.ThreadRoot("0xabc:0xmarket", "discord"); ok {
+		t.Error("expected no threads while disabled")
+	}
+}
+
+func TestMessageThreadStore_PrunesLeastRecentlyUsed(t *testing.T) {
+	cfg := DefaultMessageThreadStoreConfig()
+	cfg.MaxThreads = 2
+	ts := NewMessageThreadStore(zap.NewNop(), nil, cfg)
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	ts.now = func() time.Time { return now }
+
+	for _, key := range []string{"0xa:m", "0xb:m", "0xc:m"} {
+		now = now.Add(time.Minute)
+		ts.RecordMessage(key, notifier.MessageRef{Channel: "discord", MessageID: key})
+	}
+
+	if _, ok := ts.ThreadRoot("0xa:m", "discord"); ok {
+		t.Error("expected oldest thread pruned")
+	}
+	if ts.Count() != 2 {
+		t.Errorf("expected 2 threads, got %d", ts.Count())
+	}
+}
+
+func TestMessageThreadStore_AnnotateOutcomes(t *testing.T) {
+	ts := NewMessageThreadStore(zap.NewNop(), nil, DefaultMessageThreadStoreConfig())
+	ts.RecordMessage("0xabc:0xmarket", notifier.MessageRef{Channel: "discord", MessageID: "1"})
+	ts.RecordMessage("0xabc:0xmarket", notifier.MessageRef{Channel: "discord:removed", MessageID: "2"})
+
+	discord := &recordingAnnotator{name: "discord"}
+	resolved := []TrackedAlert{
+		{Wallet: "0xabc", ConditionID: "0xmarket", WinningOutcome: "Yes", Won: true, CopyPnl: 300},
+		{Wallet: "0xabc", ConditionID: "0xmarket", WinningOutcome: "Yes", Won: false, CopyPnl: -100},
+		{Wallet: "0xother", ConditionID: "0xmarket", WinningOutcome: "Yes"}, // Never threaded
+	}
+
+	if n := ts.AnnotateOutcomes(context.Background(), resolved, []MessageAnnotator{discord}); n != 1 {
+		t.Fatalf("expected 1 message annotated, got %d", n)
+	}
+	note := discord.notes["1"]
+	if !strings.Contains(note, "Resolved: Yes") || !strings.Contains(note, "1 of 2 alerts won") || !strings.Contains(note, "+$200.00") {
+		t.Errorf("unexpected note: %s", note)
+	}
+}
+
+func TestOutcomeNote_SingleAlert(t *testing.T) {
+	note := outcomeNote([]TrackedAlert{{WinningOutcome: "No", CopyPnl: -50}})
+	if note != "🏁 Resolved: No — ❌ Alert lost (copy P&L -$50.00)" {
+		t.Errorf("unexpected note: %s", note)
+	}
+}
+
+func TestMessageThreadStore_SaveAndLoad(t *testing.T) {
+	gistClient := NewMockGistStorage()
+	cfg := DefaultMessageThreadStoreConfig()
+	cfg.GistID = "gist123"
+
+	ts := NewMessageThreadStore(zap.NewNop(), gistClient, cfg)
+	ts.RecordMessage("0xabc:0xmarket", notifier.MessageRef{Channel: "telegram", ChatID: "chat", MessageID: "42"})
+	if err := ts.Save(context.Background()); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	loaded := NewMessageThreadStore(zap.NewNop(), gistClient, cfg)
+	if err := loaded.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	root, ok := loaded.ThreadRoot("0xabc:0xmarket", "telegram")
+	if !ok || root.ChatID != "chat" || root.MessageID != "42" {
+		t.Errorf("expected thread to survive a restart, got %+v", root)
+	}
+}
@@ -0,0 +1,356 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/gist"
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// MuteKind is what a mute applies to.
+type MuteKind string
+
+const (
+	MuteKindWallet MuteKind = "wallet" // Target is a trader address
+	MuteKindMarket MuteKind = "market" // Target is a market condition ID
+)
+
+// Mute suppresses notifications for a wallet or market until it expires.
+type Mute struct {
+	Kind      MuteKind  `json:"kind"`
+	Target    string    `json:"target"`
+	Until     time.Time `json:"until"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MuteListConfig holds configuration for mute persistence.
+type MuteListConfig struct {
+	GistID       string
+	FileName     string
+	SaveInterval time.Duration
+}
+
+// DefaultMuteListConfig returns sensible defaults.
+func DefaultMuteListConfig() MuteListConfig {
+	return MuteListConfig{
+		FileName:     "alert_mutes.json",
+		SaveInterval: 1 * time.Minute,
+	}
+}
+
+// MuteSnapshot is the persisted state format.
+type MuteSnapshot struct {
+	Version   int       `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	Mutes     []Mute    `json:"mutes"`
+}
+
+// MuteStats summarizes the mute list.
+type MuteStats struct {
+	Active     int   `json:"active"`
+	Suppressed int64 `json:"suppressed"` // Alerts not notified because of a mute
+}
+
+// MuteList holds temporary wallet and market mutes set from chat commands,
+// persisted so they survive restarts. Muted alerts are still recorded on the
+// dashboard but not sent to notifiers.
+type MuteList struct {
+	logger     *zap.Logger
+	gistClient gist.Storage
+	now        func() time.Time
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   MuteListConfig
+
+	mu         sync.RWMutex
+	mutes      map[string]Mute // muteKey(kind, target) -> mute
+	suppressed int64
+
+	// Persistence
+	dirty  bool
+	doneCh chan struct{}
+}
+
+// NewMuteList creates an empty mute list.
+func NewMuteList(logger *zap.Logger, gistClient gist.Storage, config MuteListConfig) *MuteList {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &MuteList{
+		logger:     logger.Named("mutes"),
+		gistClient: gistClient,
+		config:     config,
+		now:        time.Now,
+		mutes:      make(map[string]Mute),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// muteKey identifies a mute. Targets are matched case-insensitively.
+func muteKey(kind MuteKind, target string) string {
+	return string(kind) + ":" + strings.ToLower(strings.TrimSpace(target))
+}
+
+// IsEnabled returns true if mutes are persisted.
+func (m *MuteList) IsEnabled() bool {
+	cfg := m.getConfig()
+	return m.gistClient != nil && cfg.GistID != ""
+}
+
+// getConfig returns the current config in a thread-safe manner.
+func (m *MuteList) getConfig() MuteListConfig {
+	m.configMu.RLock()
+	defer m.configMu.RUnlock()
+	return m.config
+}
+
+// UpdateConfig updates the mute list config.
+func (m *MuteList) UpdateConfig(cfg MuteListConfig) {
+	m.configMu.Lock()
+	defer m.configMu.Unlock()
+	m.config = cfg
+}
+
+// Mute suppresses notifications for target for the given duration, replacing
+// any existing mute on the same target.
+func (m *MuteList) Mute(kind MuteKind, target string, duration time.Duration, createdBy string) Mute {
+	now := m.now()
+	mute := Mute{
+		Kind:      kind,
+		Target:    strings.TrimSpace(target),
+		Until:     now.Add(duration),
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mutes[muteKey(kind, target)] = mute
+	m.dirty = true
+
+	m.logger.Info("muted alerts",
+		zap.String("kind", string(kind)),
+		zap.String("target", mute.Target),
+		zap.Time("until", mute.Until),
+		zap.String("by", createdBy),
+	)
+	return mute
+}
+
+// Unmute removes a mute. Returns false if the target was not muted.
+func (m *MuteList) Unmute(kind MuteKind, target string) bool {
+	key := muteKey(kind, target)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mute, ok := m.mutes[key]
+	if !ok {
+		return false
+	}
+	delete(m.mutes, key)
+	m.dirty = true
+	return mute.Until.After(m.now())
+}
+
+// IsMuted reports whether the alert's wallet or market is muted, counting it
+// as suppressed if so.
+func (m *MuteList) IsMuted(alert notifier.TradeAlert) bool {
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range []string{
+		muteKey(MuteKindWallet, alert.TraderAddress),
+		muteKey(MuteKindMarket, alert.ConditionID),
+	} {
+		if mute, ok := m.mutes[key]; ok && mute.Until.After(now) {
+			m.suppressed++
+			return true
+		}
+	}
+	return false
+}
+
+// Active returns the unexpired mutes, soonest to expire first.
+func (m *MuteList) Active() []Mute {
+	now := m.now()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	active := make([]Mute, 0, len(m.mutes))
+	for _, mute := range m.mutes {
+		if mute.Until.After(now) {
+			active = append(active, mute)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Until.Before(active[j].Until)
+	})
+	return active
+}
+
+// Stats returns mute counters.
+func (m *MuteList) Stats() MuteStats {
+	active := len(m.Active())
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return MuteStats{
+		Active:     active,
+		Suppressed: m.suppressed,
+	}
+}
+
+// pruneExpired drops expired mutes.
+func (m *MuteList) pruneExpired() {
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, mute := range m.mutes {
+		if !mute.Until.After(now) {
+			delete(m.mutes, key)
+			m.dirty = true
+		}
+	}
+}
+
+// Start begins periodic saving.
+func (m *MuteList) Start(ctx context.Context) {
+	go m.periodicSave(ctx)
+}
+
+// Stop gracefully shuts down, saving pending changes.
+func (m *MuteList) Stop() {
+	close(m.doneCh)
+}
+
+// periodicSave prunes expired mutes and saves state periodically.
+func (m *MuteList) periodicSave(ctx context.Context) {
+	interval := m.getConfig().SaveInterval
+	if interval <= 0 {
+		interval = DefaultMuteListConfig().SaveInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Final save on shutdown
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = m.Save(saveCtx)
+			cancel()
+			return
+		case <-m.doneCh:
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_ = m.Save(saveCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			m.pruneExpired()
+			if err := m.Save(ctx); err != nil {
+				m.logger.Warn("failed to save mutes", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Load loads state from gist. Expired mutes are dropped.
+func (m *MuteList) Load(ctx context.Context) error {
+	if !m.IsEnabled() {
+		return nil
+	}
+
+	cfg := m.getConfig()
+	content, err := m.gistClient.Load(ctx, cfg.FileName, cfg.GistID)
+	if err != nil {
+		return fmt.Errorf("load mutes: %w", err)
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	var snapshot MuteSnapshot
+	if err := json.Unmarshal([]byte(content), &snapshot); err != nil {
+		return fmt.Errorf("unmarshal mutes: %w", err)
+	}
+
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, mute := range snapshot.Mutes {
+		key := muteKey(mute.Kind, mute.Target)
+		if _, exists := m.mutes[key]; exists || !mute.Until.After(now) {
+			continue // Keep mutes set before the load finished
+		}
+		m.mutes[key] = mute
+	}
+
+	m.logger.Info("loaded mutes",
+		zap.Int("mutes", len(m.mutes)),
+	)
+
+	return nil
+}
+
+// Save saves state to gist.
+func (m *MuteList) Save(ctx context.Context) error {
+	if !m.IsEnabled() {
+		return nil
+	}
+
+	m.mu.Lock()
+	if !m.dirty {
+		m.mu.Unlock()
+		return nil
+	}
+
+	snapshot := MuteSnapshot{
+		Version:   1,
+		Timestamp: time.Now(),
+		Mutes:     make([]Mute, 0, len(m.mutes)),
+	}
+	for _, mute := range m.mutes {
+		snapshot.Mutes = append(snapshot.Mutes, mute)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	m.dirty = false
+	m.mu.Unlock()
+
+	if err != nil {
+		m.mu.Lock()
+		m.dirty = true
+		m.mu.Unlock()
+		return fmt.Errorf("marshal mutes: %w", err)
+	}
+
+	cfg := m.getConfig()
+	if err := m.gistClient.Save(ctx, cfg.FileName, string(data), cfg.GistID); err != nil {
+		m.mu.Lock()
+		m.dirty = true
+		m.mu.Unlock()
+		return fmt.Errorf("save mutes: %w", err)
+	}
+
+	m.logger.Debug("saved mutes",
+		zap.Int("mutes", len(snapshot.Mutes)),
+	)
+
+	return nil
+}
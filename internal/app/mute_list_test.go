@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"polybot/clients/notifier"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMuteList_MutesWalletAndMarket(t *testing.T) {
+	m := NewMuteList(zap.NewNop(), nil, DefaultMuteListConfig())
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	m.Mute(MuteKindWallet, "0xABC", time.Hour, "alice")
+	m.Mute(MuteKindMarket, "0xmarket", time.Hour, "alice")
+
+	if !m.IsMuted(notifier.TradeAlert{TraderAddress: "0xabc", ConditionID: "other"}) {
+		t.Error("expected wallet to be muted case-insensitively")
+	}
+	if !m.IsMuted(notifier.TradeAlert{TraderAddress: "0xdef", ConditionID: "0xMARKET"}) {
+		t.Error("expected market to be muted")
+	}
+	if m.IsMuted(notifier.TradeAlert{TraderAddress: "0xdef", ConditionID: "other"}) {
+		t.Error("expected unmuted alert to pass")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if m.IsMuted(notifier.TradeAlert{TraderAddress: "0xabc"}) {
+		t.Error("expected mute to expire")
+	}
+
+	stats := m.Stats()
+	if stats.Active != 0 || stats.Suppressed != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMuteList_Unmute(t *testing.T) {
+	m := NewMuteList(zap.NewNop(), nil, DefaultMuteListConfig())
+	m.Mute(MuteKindWallet, "0xabc", time.Hour, "")
+
+	if !m.Unmute(MuteKindWallet, "0xABC") {
+		t.Error("expected unmute to find the mute")
+	}
+	if m.Unmute(MuteKindWallet, "0xabc") {
+		t.Error("expected second unmute to report nothing muted")
+	}
+	if m.IsMuted(notifier.TradeAlert{TraderAddress: "0xabc"}) {
+		t.Error("expected wallet to be unmuted")
+	}
+}
+
+func TestMuteList_SaveAndLoad(t *testing.T) {
+	gistClient := NewMockGistStorage()
+	cfg := DefaultMuteListConfig()
+	cfg.GistID = "gist123"
+
+	m := NewMuteList(zap.NewNop(), gistClient, cfg)
+	m.Mute(MuteKindWallet, "0xabc", time.Hour, "alice")
+	m.Mute(MuteKindMarket, "0xexpired", -time.Minute, "alice")
+
+	if err := m.Save(context.Background()); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	loaded := NewMuteList(zap.NewNop(), gistClient, cfg)
+	if err := loaded.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	active := loaded.Active()
+	if len(active) != 1 {
+		t.Fatalf("expected 1 active mute after load, got %d", len(active))
+	}
+	if active[0].Kind != MuteKindWallet || active[0].Target != "0xabc" || active[0].CreatedBy != "alice" {
+		t.Errorf("unexpected loaded mute: %+v", active[0])
+	}
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/notifier"
+)
+
+// orderFlushInterval is how often buffered orders are checked for completion.
+const orderFlushInterval = 250 * time.Millisecond
+
+// OrderAggregator combines WebSocket fills into logical orders. A market
+// order that fills against several makers arrives as one trade event per
+// fill, all sharing the transaction hash and taker. Fills are buffered for a
+// short window and released as a single trade with the summed size and the
+// volume-weighted price, so heuristics see the order rather than its pieces.
+type OrderAggregator struct {
+	mu      sync.Mutex
+	pending map[string]*pendingOrder // order key -> fills so far
+
+	combined int64 // Fills folded into an earlier fill of the same order
+
+	// now returns the current time (overridden in tests)
+	now func() time.Time
+}
+
+// pendingOrder is an order whose fills are still being collected.
+type pendingOrder struct {
+	trade    NormalizedTrade
+	due      time.Time
+	tradeIDs map[string]bool
+}
+
+// NewOrderAggregator creates an empty order aggregator.
+func NewOrderAggregator() *OrderAggregator {
+	return &OrderAggregator{
+		pending: make(map[string]*pendingOrder),
+		now:     time.Now,
+	}
+}
+
+// orderKey groups fills of one order: same transaction, taker, token and side.
+func orderKey(txHash string, trade *NormalizedTrade) string {
+	return txHash + ":" + strings.ToLower(trade.Wallet) + ":" + trade.TokenID + ":" + trade.Side
+}
+
+// Add buffers a single-fill trade until the window after its order's first
+// fill has passed. Fills already seen (by trade ID) are ignored.
+func (a *OrderAggregator) Add(txHash string, trade *NormalizedTrade, window time.Duration) {
+	key := orderKey(txHash, trade)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	order, ok := a.pending[key]
+	if !ok {
+		order = &pendingOrder{
+			trade:    *trade,
+			due:      a.now().Add(window),
+			tradeIDs: make(map[string]bool),
+		}
+		order.trade.Fills = append([]notifier.Fill(nil), trade.Fills...)
+		for _, f := range trade.Fills {
+			if f.TradeID != "" {
+				order.tradeIDs[f.TradeID] = true
+			}
+		}
+		a.pending[key] = order
+		return
+	}
+
+	for _, f := range trade.Fills {
+		if f.TradeID != "" {
+			if order.tradeIDs[f.TradeID] {
+				continue
+			}
+			order.tradeIDs[f.TradeID] = true
+		}
+		order.trade.Fills = append(order.trade.Fills, f)
+		order.trade.Size += f.Shares
+		order.trade.Notional += f.Shares * f.Price
+		a.combined++
+	}
+	if order.trade.Size > 0 {
+		order.trade.Price = order.trade.Notional / order.trade.Size
+	}
+	if trade.Timestamp.After(order.trade.Timestamp) {
+		order.trade.Timestamp = trade.Timestamp
+	}
+}
+
+// Due removes and returns the orders whose window has passed, oldest first.
+// Pass force to release every buffered order.
+func (a *OrderAggregator) Due(force bool) []*NormalizedTrade {
+	now := a.now()
+
+	a.mu.Lock()
+	var due []*pendingOrder
+	for key, order := range a.pending {
+		if force || !now.Before(order.due) {
+			due = append(due, order)
+			delete(a.pending, key)
+		}
+	}
+	a.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool { return due[i].due.Before(due[j].due) })
+	trades := make([]*NormalizedTrade, len(due))
+	for i, order := range due {
+		trades[i] = &order.trade
+	}
+	return trades
+}
+
+// Pending returns the number of orders still collecting fills.
+func (a *OrderAggregator) Pending() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.pending)
+}
+
+// CombinedFills returns how many fills were folded into an earlier fill of the same order.
+func (a *OrderAggregator) CombinedFills() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.combined
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"polybot/clients/notifier"
+	"polybot/clients/polymarketevents"
+)
+
+func aggregatorTestFill(wallet, tradeID string, shares, price float64) *NormalizedTrade {
+	return &NormalizedTrade{
+		Key:      "0xtx:token",
+		Wallet:   wallet,
+		TokenID:  "token",
+		Side:     "BUY",
+		Size:     shares,
+		Price:    price,
+		Notional: shares * price,
+		Fills:    []notifier.Fill{{TradeID: tradeID, Shares: shares, Price: price}},
+	}
+}
+
+func TestOrderAggregator_CombinesFills(t *testing.T) {
+	a := NewOrderAggregator()
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	a.Add("0xtx", aggregatorTestFill("0xTaker", "t1", 100, 0.40), 2*time.Second)
+	a.Add("0xtx", aggregatorTestFill("0xtaker", "t2", 300, 0.50), 2*time.Second)
+	a.Add("0xtx", aggregatorTestFill("0xtaker", "t2", 300, 0.50), 2*time.Second) // Redelivered
+	a.Add("0xtx", aggregatorTestFill("0xother", "t3", 50, 0.50), 2*time.Second)
+
+	if got := a.Due(false); len(got) != 0 {
+		t.Fatalf("expected orders held inside the window, got %d", len(got))
+	}
+
+	now = now.Add(2 * time.Second)
+	trades := a.Due(false)
+	if len(trades) != 2 {
+		t.Fatalf("expected one order per taker, got %d", len(trades))
+	}
+
+	order := trades[0]
+	if order.Wallet != "0xTaker" {
+		order = trades[1]
+	}
+	if order.Size != 400 || order.Notional != 190 || len(order.Fills) != 2 {
+		t.Errorf("unexpected order: size=%.0f notional=%.0f fills=%d", order.Size, order.Notional, len(order.Fills))
+	}
+	if order.Price < 0.4749 || order.Price > 0.4751 {
+		t.Errorf("expected VWAP of 0.475, got %f", order.Price)
+	}
+	if a.Pending() != 0 || a.CombinedFills() != 1 {
+		t.Errorf("expected no pending orders and 1 combined fill, got %d and %d", a.Pending(), a.CombinedFills())
+	}
+}
+
+func TestOrderAggregator_ForceFlush(t *testing.T) {
+	a := NewOrderAggregator()
+	a.Add("0xtx", aggregatorTestFill("0xtaker", "t1", 100, 0.5), time.Hour)
+
+	if got := a.Due(true); len(got) != 1 {
+		t.Errorf("expected forced flush to release the order, got %d", len(got))
+	}
+}
+
+func TestProcessTradeEvent_AggregatesFillsBeforeDetectors(t *testing.T) {
+	monitor, server, tracker := newTestTradeMonitor(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]any{})
+	})
+	defer server.Close()
+	capture := &captureNotifier{}
+	monitor.notifier = capture
+	monitor.UpdateConfig(DefaultTradeMonitorConfig())
+	monitor.tokenToInfo["token-yes"] = &MarketInfo{
+		ConditionID: "cond1",
+		Title:       "Test Market",
+		Outcomes:    []string{"Yes", "No"},
+		TokenIDs:    []string{"token-yes", "token-no"},
+	}
+	tracker.cache["0xwallet"] = &WalletStats{
+		Wallet:        "0xwallet",
+		UniqueMarkets: 1,
+		FetchedAt:     time.Now(),
+	}
+
+	// A $5000 market order filled against ten makers at $500 each
+	for i := 0; i < 10; i++ {
+		monitor.processTradeEvent(context.Background(), &polymarketevents.TradeEvent{
+			EventType:       "trade",
+			AssetID:         "token-yes",
+			Price:           "0.10",
+			Size:            "5000",
+			Side:            "BUY",
+			TakerAddress:    "0xwallet",
+			MakerAddress:    fmt.Sprintf("0xmaker%d", i),
+			Timestamp:       "0",
+			TransactionHash: "0xtx",
+			TradeID:         fmt.Sprintf("fill-%d", i),
+		})
+	}
+	if len(capture.Alerts()) != 0 {
+		t.Fatal("expected fills held until the order is complete")
+	}
+
+	monitor.flushOrders(context.Background(), true)
+
+	alerts := capture.Alerts()
+	if len(alerts) != 1 {
+		t.Fatalf("expected one alert for the order, got %d", len(alerts))
+	}
+	if alerts[0].Shares != 50000 || alerts[0].Notional != 5000 || len(alerts[0].Fills) != 10 {
+		t.Errorf("unexpected alert: shares=%.0f notional=%.0f fills=%d",
+			alerts[0].Shares, alerts[0].Notional, len(alerts[0].Fills))
+	}
+	if alerts[0].Fills[3].Maker != "0xmaker3" {
+		t.Errorf("expected maker fills attached in order, got %+v", alerts[0].Fills[3])
+	}
+	if monitor.FilterStats().AggregatedFills != 9 {
+		t.Errorf("expected 9 aggregated fills, got %d", monitor.FilterStats().AggregatedFills)
+	}
+}
@@ -0,0 +1,309 @@
+package app
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/notifier"
+	"polybot/clients/polymarketevents"
+)
+
+// bookPriceScale converts prices to integer ticks so a level matches however
+// its price string was formatted ("0.5" and "0.50").
+const bookPriceScale = 10000
+
+// BookLevel is one price level of an order book.
+type BookLevel struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// BookQuote summarizes a token's order book.
+type BookQuote struct {
+	TokenID   string    `json:"token_id"`
+	BestBid   float64   `json:"best_bid"`  // 0 if there are no bids
+	BestAsk   float64   `json:"best_ask"`  // 0 if there are no asks
+	BidDepth  float64   `json:"bid_depth"` // USD resting on the bid side
+	AskDepth  float64   `json:"ask_depth"` // USD resting on the ask side
+	BidLevels int       `json:"bid_levels"`
+	AskLevels int       `json:"ask_levels"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Set for the dashboard
+	MarketTitle string `json:"market_title,omitempty"`
+	Outcome     string `json:"outcome,omitempty"`
+}
+
+// HasBid reports whether the book has any bids.
+func (q BookQuote) HasBid() bool {
+	return q.BidLevels > 0
+}
+
+// HasAsk reports whether the book has any asks.
+func (q BookQuote) HasAsk() bool {
+	return q.AskLevels > 0
+}
+
+// Spread returns the gap between the best ask and best bid.
+// Returns 0 if either side is empty.
+func (q BookQuote) Spread() float64 {
+	if !q.HasBid() || !q.HasAsk() {
+		return 0
+	}
+	return q.BestAsk - q.BestBid
+}
+
+// Mid returns the midpoint of the best bid and ask, or the only side quoted.
+// Returns 0 for an empty book.
+func (q BookQuote) Mid() float64 {
+	switch {
+	case q.HasBid() && q.HasAsk():
+		return (q.BestBid + q.BestAsk) / 2
+	case q.HasBid():
+		return q.BestBid
+	case q.HasAsk():
+		return q.BestAsk
+	default:
+		return 0
+	}
+}
+
+// orderBook is the L2 book for one token.
+type orderBook struct {
+	bids      map[int64]float64 // price ticks -> size
+	asks      map[int64]float64
+	updatedAt time.Time
+}
+
+func newOrderBook() *orderBook {
+	return &orderBook{
+		bids: make(map[int64]float64),
+		asks: make(map[int64]float64),
+	}
+}
+
+// set replaces the size at a level; a size of 0 removes it.
+func (b *orderBook) set(levels map[int64]float64, price, size float64) {
+	if price <= 0 {
+		return
+	}
+	ticks := int64(math.Round(price * bookPriceScale))
+	if size <= 0 {
+		delete(levels, ticks)
+		return
+	}
+	levels[ticks] = size
+}
+
+// quote summarizes the book.
+func (b *orderBook) quote(tokenID string) BookQuote {
+	q := BookQuote{
+		TokenID:   tokenID,
+		BidLevels: len(b.bids),
+		AskLevels: len(b.asks),
+		UpdatedAt: b.updatedAt,
+	}
+	for ticks, size := range b.bids {
+		price := float64(ticks) / bookPriceScale
+		if price > q.BestBid {
+			q.BestBid = price
+		}
+		q.BidDepth += price * size
+	}
+	for ticks, size := range b.asks {
+		price := float64(ticks) / bookPriceScale
+		if q.BestAsk == 0 || price < q.BestAsk {
+			q.BestAsk = price
+		}
+		q.AskDepth += price * size
+	}
+	return q
+}
+
+// sortedLevels returns up to n levels, best price first (n <= 0 = all).
+func sortedLevels(levels map[int64]float64, descending bool, n int) []BookLevel {
+	result := make([]BookLevel, 0, len(levels))
+	for ticks, size := range levels {
+		result = append(result, BookLevel{Price: float64(ticks) / bookPriceScale, Size: size})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if descending {
+			return result[i].Price > result[j].Price
+		}
+		return result[i].Price < result[j].Price
+	})
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// setBookInfo adds the order book around the trade to an alert.
+func setBookInfo(alert *notifier.TradeAlert, trade *NormalizedTrade) {
+	if trade.BookAfter == nil {
+		return
+	}
+	alert.HasBookInfo = true
+	alert.BookBestBid = trade.BookAfter.BestBid
+	alert.BookBestAsk = trade.BookAfter.BestAsk
+	alert.BookBidDepth = trade.BookAfter.BidDepth
+	alert.BookAskDepth = trade.BookAfter.AskDepth
+	alert.BookPriceAfter = trade.BookAfter.Mid()
+	if trade.BookBefore != nil {
+		alert.BookPriceBefore = trade.BookBefore.Mid()
+	}
+}
+
+// OrderBookStats holds order book metrics for the dashboard.
+type OrderBookStats struct {
+	Books     int         `json:"books"`
+	Snapshots int64       `json:"snapshots"` // Full book snapshots applied
+	Deltas    int64       `json:"deltas"`    // Price level changes applied
+	Quotes    []BookQuote `json:"quotes,omitempty"`
+}
+
+// OrderBooks maintains an in-memory L2 order book per token from the market
+// channel's book snapshots and price_change deltas. Deltas for a token are
+// ignored until its first snapshot arrives, so partial books are never quoted.
+type OrderBooks struct {
+	mu        sync.RWMutex
+	books     map[string]*orderBook // token ID -> book
+	snapshots int64
+	deltas    int64
+
+	// now returns the current time (overridden in tests)
+	now func() time.Time
+}
+
+// NewOrderBooks creates an empty set of order books.
+func NewOrderBooks() *OrderBooks {
+	return &OrderBooks{
+		books: make(map[string]*orderBook),
+		now:   time.Now,
+	}
+}
+
+// ApplyBook replaces a token's book with a snapshot.
+func (o *OrderBooks) ApplyBook(event *polymarketevents.BookEvent) {
+	book := newOrderBook()
+	for _, l := range event.Bids {
+		book.set(book.bids, l.GetPriceFloat(), l.GetSizeFloat())
+	}
+	for _, l := range event.Asks {
+		book.set(book.asks, l.GetPriceFloat(), l.GetSizeFloat())
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	book.updatedAt = o.now()
+	o.books[event.AssetID] = book
+	o.snapshots++
+}
+
+// ApplyPriceChange applies level updates to the books they refer to.
+func (o *OrderBooks) ApplyPriceChange(event *polymarketevents.PriceChangeEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := o.now()
+	for _, c := range event.PriceChanges {
+		book, ok := o.books[c.AssetID]
+		if !ok {
+			continue
+		}
+		switch strings.ToUpper(c.Side) {
+		case "BUY":
+			book.set(book.bids, c.GetPriceFloat(), c.GetSizeFloat())
+		case "SELL":
+			book.set(book.asks, c.GetPriceFloat(), c.GetSizeFloat())
+		default:
+			continue
+		}
+		book.updatedAt = now
+		o.deltas++
+	}
+}
+
+// Quote returns a summary of a token's book.
+// Returns false if no snapshot has been received for the token.
+func (o *OrderBooks) Quote(tokenID string) (BookQuote, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	book, ok := o.books[tokenID]
+	if !ok {
+		return BookQuote{}, false
+	}
+	return book.quote(tokenID), true
+}
+
+// Bids returns up to n bid levels of a token's book, highest first (n <= 0 = all).
+func (o *OrderBooks) Bids(tokenID string, n int) []BookLevel {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	book, ok := o.books[tokenID]
+	if !ok {
+		return nil
+	}
+	return sortedLevels(book.bids, true, n)
+}
+
+// Asks returns up to n ask levels of a token's book, lowest first (n <= 0 = all).
+func (o *OrderBooks) Asks(tokenID string, n int) []BookLevel {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	book, ok := o.books[tokenID]
+	if !ok {
+		return nil
+	}
+	return sortedLevels(book.asks, false, n)
+}
+
+// Retain drops the books of tokens that are no longer subscribed.
+// Returns the number of books dropped.
+func (o *OrderBooks) Retain(tokenIDs []string) int {
+	keep := make(map[string]bool, len(tokenIDs))
+	for _, id := range tokenIDs {
+		keep[id] = true
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	dropped := 0
+	for id := range o.books {
+		if !keep[id] {
+			delete(o.books, id)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// Stats returns order book metrics with the quotes of the n deepest books
+// (by total USD resting on both sides).
+func (o *OrderBooks) Stats(n int) OrderBookStats {
+	o.mu.RLock()
+	stats := OrderBookStats{
+		Books:     len(o.books),
+		Snapshots: o.snapshots,
+		Deltas:    o.deltas,
+	}
+	quotes := make([]BookQuote, 0, len(o.books))
+	for id, book := range o.books {
+		quotes = append(quotes, book.quote(id))
+	}
+	o.mu.RUnlock()
+
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].BidDepth+quotes[i].AskDepth > quotes[j].BidDepth+quotes[j].AskDepth
+	})
+	if len(quotes) > n {
+		quotes = quotes[:n]
+	}
+	if len(quotes) > 0 {
+		stats.Quotes = quotes
+	}
+	return stats
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"polybot/clients/polymarketevents"
+)
+
+func testBookEvent(tokenID string) *polymarketevents.BookEvent {
+	return &polymarketevents.BookEvent{
+		EventType: "book",
+		AssetID:   tokenID,
+		Bids:      []polymarketevents.OrderSummary{{Price: "0.10", Size: "1000"}, {Price: "0.11", Size: "500"}},
+		Asks:      []polymarketevents.OrderSummary{{Price: "0.13", Size: "200"}, {Price: "0.15", Size: "1000"}},
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestOrderBooks_SnapshotAndDeltas(t *testing.T) {
+	books := NewOrderBooks()
+	books.ApplyBook(testBookEvent("token1"))
+
+	q, ok := books.Quote("token1")
+	if !ok {
+		t.Fatal("expected quote after snapshot")
+	}
+	if q.BestBid != 0.11 || q.BestAsk != 0.13 || !almostEqual(q.Spread(), 0.02) || !almostEqual(q.Mid(), 0.12) {
+		t.Errorf("unexpected top of book: %+v", q)
+	}
+	if !almostEqual(q.BidDepth, 155) || !almostEqual(q.AskDepth, 176) {
+		t.Errorf("unexpected depth: bid=%.2f ask=%.2f", q.BidDepth, q.AskDepth)
+	}
+
+	// A buy sweeps the 13¢ ask; the next level is quoted at "0.150"
+	books.ApplyPriceChange(&polymarketevents.PriceChangeEvent{
+		PriceChanges: []polymarketevents.PriceChange{
+			{AssetID: "token1", Price: "0.13", Size: "0", Side: "SELL"},
+			{AssetID: "token1", Price: "0.150", Size: "800", Side: "SELL"},
+			{AssetID: "token1", Price: "0.14", Size: "300", Side: "BUY"},
+			{AssetID: "unknown", Price: "0.5", Size: "10", Side: "BUY"},
+		},
+	})
+
+	q, _ = books.Quote("token1")
+	if q.BestBid != 0.14 || q.BestAsk != 0.15 || q.AskLevels != 1 {
+		t.Errorf("unexpected book after deltas: %+v", q)
+	}
+	if asks := books.Asks("token1", 0); len(asks) != 1 || asks[0].Size != 800 {
+		t.Errorf("expected ask level replaced, got %+v", asks)
+	}
+	if bids := books.Bids("token1", 2); len(bids) != 2 || bids[0].Price != 0.14 || bids[1].Price != 0.11 {
+		t.Errorf("expected best bids first, got %+v", bids)
+	}
+	if _, ok := books.Quote("unknown"); ok {
+		t.Error("expected deltas without a snapshot to be ignored")
+	}
+
+	stats := books.Stats(5)
+	if stats.Books != 1 || stats.Snapshots != 1 || stats.Deltas != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestOrderBooks_OneSidedAndRetain(t *testing.T) {
+	books := NewOrderBooks()
+	books.ApplyBook(&polymarketevents.BookEvent{AssetID: "token1", Bids: []polymarketevents.OrderSummary{{Price: "0.4", Size: "10"}}})
+	books.ApplyBook(testBookEvent("token2"))
+
+	q, _ := books.Quote("token1")
+	if q.HasAsk() || q.Spread() != 0 || q.Mid() != 0.4 {
+		t.Errorf("expected one-sided book quoted at the bid, got %+v", q)
+	}
+
+	if stats := books.Stats(1); len(stats.Quotes) != 1 || stats.Quotes[0].TokenID != "token2" {
+		t.Errorf("expected deepest book first, got %+v", stats.Quotes)
+	}
+
+	if dropped := books.Retain([]string{"token2"}); dropped != 1 {
+		t.Errorf("expected 1 book dropped, got %d", dropped)
+	}
+	if _, ok := books.Quote("token1"); ok {
+		t.Error("expected unsubscribed book dropped")
+	}
+}
+
+func TestProcessWebSocketMessage_AlertShowsBookMove(t *testing.T) {
+	monitor, server, tracker := newTestTradeMonitor(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]any{})
+	})
+	defer server.Close()
+	capture := &captureNotifier{}
+	monitor.notifier = capture
+	monitor.UpdateConfig(DefaultTradeMonitorConfig())
+	monitor.tokenToInfo["token1"] = &MarketInfo{
+		ConditionID: "cond1",
+		Title:       "Test Market",
+		Outcomes:    []string{"Yes", "No"},
+		TokenIDs:    []string{"token1", "token2"},
+	}
+	tracker.cache["0xwallet"] = &WalletStats{Wallet: "0xwallet", UniqueMarkets: 1, WinCount: 3, LossCount: 1, WinRate: 0.75, FetchedAt: time.Now()}
+
+	ctx := context.Background()
+	for _, msg := range []string{
+		`{"event_type":"book","asset_id":"token1","bids":[{"price":"0.11","size":"5000"}],"asks":[{"price":"0.13","size":"40000"},{"price":"0.27","size":"10000"}]}`,
+		`{"event_type":"trade","asset_id":"token1","price":"0.13","size":"40000","side":"BUY","taker_address":"0xwallet","transaction_hash":"0xtx","id":"f1"}`,
+		`{"event_type":"price_change","market":"cond1","price_changes":[{"asset_id":"token1","price":"0.13","size":"0","side":"SELL"}]}`,
+	} {
+		monitor.processWebSocketMessage(ctx, []byte(msg))
+	}
+	monitor.flushOrders(ctx, true)
+
+	alerts := capture.Alerts()
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	alert := alerts[0]
+	if !alert.HasBookInfo || !almostEqual(alert.BookPriceBefore, 0.12) || !almostEqual(alert.BookPriceAfter, 0.19) {
+		t.Errorf("expected book move from 12¢ to 19¢, got %+v", alert)
+	}
+	if alert.BookBestAsk != 0.27 || !alert.MovedMarket() {
+		t.Errorf("expected best ask 27¢ after the sweep, got %v", alert.BookBestAsk)
+	}
+
+	stats := monitor.OrderBookStats(5)
+	if len(stats.Quotes) != 1 || stats.Quotes[0].MarketTitle != "Test Market" || stats.Quotes[0].Outcome != "Yes" {
+		t.Errorf("expected dashboard quote labeled with its market, got %+v", stats.Quotes)
+	}
+}
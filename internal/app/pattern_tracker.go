@@ -183,12 +183,19 @@ type PreMoveStats struct {
 
 // PatternAlert is the unified alert type for all pattern detections.
 type PatternAlert struct {
+	ID          string // Source record ID, set on deferred alerts
 	Wallet      string
 	WalletURL   string
 	MarketTitle string
 	MarketURL   string
+	ConditionID string
+	Outcome     string
+	Side        string
+	Price       float64
+	Size        float64
+	Notional    float64
 	Timestamp   time.Time
-	Reason      string // "conviction_doubling", "perfect_exit_timing", "stealth_accumulation"
+	Reason      string // "conviction_doubling", "perfect_exit_timing", "stealth_accumulation", "pre_move_positioning"
 
 	// Conviction Doubling fields
 	ConvictionExistingSize float64
@@ -271,6 +278,9 @@ type PatternTracker struct {
 	pendingMoves map[string]*PreMoveRecord // id -> record awaiting verification
 	preMoveStats map[string]*PreMoveStats  // wallet -> stats
 
+	// Pre-move alerts raised by the background checker, awaiting dispatch
+	deferredAlerts []PatternAlert
+
 	// Rate limiting for position checks
 	lastPositionCheck  map[string]time.Time // wallet:conditionID -> last check time
 	positionCheckCount int
@@ -280,6 +290,9 @@ type PatternTracker struct {
 	dirty    bool
 	updateCh chan patternUpdate
 	doneCh   chan struct{}
+
+	// now returns the current time (the tape's time during replays)
+	now func() time.Time
 }
 
 type patternUpdate struct {
@@ -312,6 +325,7 @@ func NewPatternTracker(
 		lastPositionCheck:    make(map[string]time.Time),
 		updateCh:             make(chan patternUpdate, 100),
 		doneCh:               make(chan struct{}),
+		now:                  time.Now,
 	}
 }
 
@@ -358,7 +372,7 @@ func (pt *PatternTracker) ShouldCheckPosition(wallet, conditionID string) bool {
 	defer pt.mu.Unlock()
 
 	key := wallet + ":" + conditionID
-	now := time.Now()
+	now := pt.now()
 
 	// Reset rate limit counter if needed
 	if now.After(pt.positionCheckReset) {
@@ -515,7 +529,7 @@ func (pt *PatternTracker) trackAccumulation(input ProcessTradeInput) *PatternAle
 	defer pt.mu.Unlock()
 
 	key := fmt.Sprintf("%s:%s:%s", input.Wallet, input.ConditionID, input.Outcome)
-	now := time.Now()
+	now := pt.now()
 
 	// Get or create accumulation record
 	record, exists := pt.accumulations[key]
@@ -707,7 +721,7 @@ func (pt *PatternTracker) checkPendingExits(ctx context.Context) {
 	}
 	pt.mu.Unlock()
 
-	now := time.Now()
+	now := pt.now()
 	checkedCount := 0
 	maxChecks := 20 // Limit API calls per cycle
 
@@ -784,7 +798,7 @@ func (pt *PatternTracker) updateExitTimingStats(record *ExitTimingRecord) {
 	if record.TimingScore >= 0.95 {
 		stats.PerfectExits++
 	}
-	stats.LastUpdated = time.Now()
+	stats.LastUpdated = pt.now()
 }
 
 // GetExitTimingStats returns timing stats for a wallet.
@@ -828,11 +842,35 @@ func (pt *PatternTracker) runPreMoveChecker(ctx context.Context) {
 		case <-pt.doneCh:
 			return
 		case <-ticker.C:
-			pt.checkPendingMoves(ctx)
+			if alerts := pt.checkPendingMoves(ctx); len(alerts) > 0 {
+				pt.queueDeferredAlerts(alerts)
+			}
 		}
 	}
 }
 
+// maxDeferredPatternAlerts caps queued alerts if nothing drains them.
+const maxDeferredPatternAlerts = 100
+
+// queueDeferredAlerts holds alerts raised off the trade path until they are drained.
+func (pt *PatternTracker) queueDeferredAlerts(alerts []PatternAlert) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.deferredAlerts = append(pt.deferredAlerts, alerts...)
+	if len(pt.deferredAlerts) > maxDeferredPatternAlerts {
+		pt.deferredAlerts = pt.deferredAlerts[len(pt.deferredAlerts)-maxDeferredPatternAlerts:]
+	}
+}
+
+// DrainDeferredAlerts returns and clears alerts raised by background checks.
+func (pt *PatternTracker) DrainDeferredAlerts() []PatternAlert {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	alerts := pt.deferredAlerts
+	pt.deferredAlerts = nil
+	return alerts
+}
+
 // checkPendingMoves verifies moves that have waited long enough and generates alerts.
 func (pt *PatternTracker) checkPendingMoves(ctx context.Context) []PatternAlert {
 	cfg := pt.getConfig()
@@ -845,7 +883,7 @@ func (pt *PatternTracker) checkPendingMoves(ctx context.Context) []PatternAlert
 	}
 	pt.mu.Unlock()
 
-	now := time.Now()
+	now := pt.now()
 	checkedCount := 0
 	maxChecks := 20 // Limit API calls per cycle
 
@@ -985,7 +1023,7 @@ func (pt *PatternTracker) updatePreMoveStats(wallet string, successfulMove bool,
 		stats.AvgMoveSize = stats.TotalMoveSize / float64(stats.SuccessfulMoves)
 	}
 	stats.AlphaScore = float64(stats.SuccessfulMoves) / float64(stats.TotalTrades)
-	stats.LastUpdated = time.Now()
+	stats.LastUpdated = pt.now()
 }
 
 // checkPreMoveAlert checks if a wallet qualifies for pre-move positioning alert.
@@ -1010,7 +1048,7 @@ func (pt *PatternTracker) checkPreMoveAlert(record *PreMoveRecord) *PatternAlert
 	}
 
 	// Check cooldown
-	if time.Since(stats.LastAlertTime) < cfg.PreMoveAlertCooldown {
+	if pt.now().Sub(stats.LastAlertTime) < cfg.PreMoveAlertCooldown {
 		pt.mu.RUnlock()
 		return nil
 	}
@@ -1019,7 +1057,7 @@ func (pt *PatternTracker) checkPreMoveAlert(record *PreMoveRecord) *PatternAlert
 	// Update last alert time
 	pt.mu.Lock()
 	if s, ok := pt.preMoveStats[record.Wallet]; ok {
-		s.LastAlertTime = time.Now()
+		s.LastAlertTime = pt.now()
 	}
 	pt.mu.Unlock()
 
@@ -1032,11 +1070,18 @@ func (pt *PatternTracker) checkPreMoveAlert(record *PreMoveRecord) *PatternAlert
 	)
 
 	return &PatternAlert{
+		ID:                     record.ID,
 		Wallet:                 record.Wallet,
 		WalletURL:              fmt.Sprintf("https://polymarket.com/profile/%s", record.Wallet),
 		MarketTitle:            record.MarketTitle,
 		MarketURL:              fmt.Sprintf("https://polymarket.com/event/%s", record.MarketSlug),
-		Timestamp:              time.Now(),
+		ConditionID:            record.ConditionID,
+		Outcome:                record.Outcome,
+		Side:                   record.Side,
+		Price:                  record.TradePrice,
+		Size:                   record.TradeSize,
+		Notional:               record.TradeValue,
+		Timestamp:              pt.now(),
 		Reason:                 "pre_move_positioning",
 		PreMoveTotalTrades:     stats.TotalTrades,
 		PreMoveSuccessfulMoves: stats.SuccessfulMoves,
@@ -1164,7 +1209,7 @@ func (pt *PatternTracker) Save(ctx context.Context) error {
 	pt.mu.Lock()
 	snapshot := PatternTrackerSnapshot{
 		Version:         1,
-		Timestamp:       time.Now(),
+		Timestamp:       pt.now(),
 		PendingExits:    pt.pendingExits,
 		ExitTimingStats: pt.exitTimingStats,
 		Accumulations:   pt.accumulations,
@@ -0,0 +1,270 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"polybot/clients/polymarketapi"
+	"polybot/clients/polymarketevents"
+	"polybot/config"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ReplayStats summarizes a tape replay.
+type ReplayStats struct {
+	Frames   int           // Raw frames read from the tape
+	Events   int           // Individual events fed to the trade monitor
+	BadFrame int           // Frames that could not be split into events
+	TapeSpan time.Duration // Time between the first and last frame on the tape
+	Elapsed  time.Duration // Wall clock time the replay took
+}
+
+// replayClock is the current time during a tape replay: the time of the trade
+// being evaluated. The runner hands its Now to every time-windowed component
+// (the trade monitor's rapid trading window, order fill collection and order
+// books, the alert suppressor, and the copy, hedge and pattern trackers)
+// before they start, so windows see the trades as they happened live. Until
+// the first frame is read it follows the wall clock; after the tape ends it
+// stays at the last trade's time.
+type replayClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// Now returns the replay time.
+func (c *replayClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.now.IsZero() {
+		return time.Now()
+	}
+	return c.now
+}
+
+// set moves the replay clock to t.
+func (c *replayClock) set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// Replayer feeds a recorded WebSocket tape through the trade monitor.
+type Replayer struct {
+	logger  *zap.Logger
+	monitor *TradeMonitor
+	clock   *replayClock // Advanced to each trade's time; read by the monitor's components
+	speed   float64      // 1 = real time, >1 = accelerated, 0 = as fast as possible
+}
+
+// NewReplayer creates a new tape replayer. The monitor and its components
+// must already read clock.
+func NewReplayer(logger *zap.Logger, monitor *TradeMonitor, clock *replayClock, speed float64) *Replayer {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if speed < 0 {
+		speed = 0
+	}
+
+	return &Replayer{
+		logger:  logger.Named("replay"),
+		monitor: monitor,
+		clock:   clock,
+		speed:   speed,
+	}
+}
+
+// Run replays the tape at path. It returns when the tape ends or ctx is canceled.
+func (rp *Replayer) Run(ctx context.Context, path string) (ReplayStats, error) {
+	var stats ReplayStats
+
+	tape, err := polymarketevents.OpenTape(path)
+	if err != nil {
+		return stats, err
+	}
+	defer tape.Close()
+
+	rp.logger.Info("replaying tape",
+		zap.String("path", path),
+		zap.Float64("speed", rp.speed),
+	)
+
+	start := time.Now()
+	var first time.Time
+
+	for {
+		entry, err := tape.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stats, err
+		}
+
+		if first.IsZero() {
+			first = entry.Timestamp
+		}
+		stats.TapeSpan = entry.Timestamp.Sub(first)
+		rp.clock.set(entry.Timestamp)
+
+		// Pace the replay relative to the first frame on the tape
+		if rp.speed > 0 {
+			due := start.Add(time.Duration(float64(stats.TapeSpan) / rp.speed))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-ctx.Done():
+					stats.Elapsed = time.Since(start)
+					return stats, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		} else if ctx.Err() != nil {
+			stats.Elapsed = time.Since(start)
+			return stats, ctx.Err()
+		}
+
+		stats.Frames++
+		events, err := polymarketevents.SplitFrame(entry.Bytes())
+		if err != nil {
+			stats.BadFrame++
+			rp.logger.Warn("skipping bad tape frame", zap.Error(err))
+			continue
+		}
+		for _, event := range events {
+			rp.monitor.processWebSocketMessage(ctx, event)
+			stats.Events++
+		}
+
+		// Unpaced replays can't wait out the fill window, so evaluate orders frame by frame
+		rp.flushOrders(ctx, rp.speed == 0)
+	}
+	rp.flushOrders(ctx, true)
+
+	stats.Elapsed = time.Since(start)
+	rp.logger.Info("tape replay complete",
+		zap.Int("frames", stats.Frames),
+		zap.Int("events", stats.Events),
+		zap.Int("badFrames", stats.BadFrame),
+		zap.Duration("tapeSpan", stats.TapeSpan),
+		zap.Duration("elapsed", stats.Elapsed),
+	)
+	return stats, nil
+}
+
+// flushOrders evaluates orders that are due with the replay clock set to each
+// trade's time, falling back to the frame's time for trades without one.
+func (rp *Replayer) flushOrders(ctx context.Context, force bool) {
+	for _, trade := range rp.monitor.orders.Due(force) {
+		if trade.Timestamp.Unix() > 0 {
+			rp.clock.set(trade.Timestamp)
+		}
+		rp.monitor.processNormalizedTrade(ctx, trade)
+	}
+}
+
+// setupResponseCache routes Polymarket REST calls through the response cache.
+// Live runs record responses; replay runs are served from the cache only.
+func (r *Runner) setupResponseCache(tape config.TapeConfig) error {
+	cache, err := polymarketapi.LoadResponseCache(tape.RESTCacheFile)
+	if err != nil {
+		return err
+	}
+
+	mode := polymarketapi.ResponseCacheRecord
+	if tape.IsReplay() {
+		mode = polymarketapi.ResponseCacheReplay
+	}
+	r.clients.Polymarket.UseResponseCache(cache, mode)
+	r.responseCache = cache
+
+	entries, _, _ := cache.Stats()
+	r.clients.Logger.Info("polymarket response cache enabled",
+		zap.String("path", tape.RESTCacheFile),
+		zap.Bool("replay", tape.IsReplay()),
+		zap.Int("entries", entries),
+	)
+	return nil
+}
+
+// saveResponseCache writes the recorded REST responses to disk.
+func (r *Runner) saveResponseCache() {
+	if r.responseCache == nil {
+		return
+	}
+	if err := r.responseCache.Save(); err != nil {
+		r.clients.Logger.Warn("failed to save response cache", zap.Error(err))
+	}
+}
+
+// runResponseCacheSaver periodically saves the response cache while recording.
+func (r *Runner) runResponseCacheSaver(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.saveResponseCache()
+		}
+	}
+}
+
+// startTapeRecording attaches a tape recorder to the WebSocket client.
+func (r *Runner) startTapeRecording(dir string) error {
+	recorder, err := polymarketevents.NewTapeRecorder(r.clients.Logger, dir)
+	if err != nil {
+		return err
+	}
+	r.clients.PolymarketEvents.SetRecorder(recorder)
+	r.tapeRecorder = recorder
+	return nil
+}
+
+// stopTapeRecording detaches and closes the tape recorder.
+func (r *Runner) stopTapeRecording() {
+	if r.tapeRecorder == nil {
+		return
+	}
+	r.clients.PolymarketEvents.SetRecorder(nil)
+	if err := r.tapeRecorder.Close(); err != nil {
+		r.clients.Logger.Warn("failed to close tape", zap.Error(err))
+		return
+	}
+	r.clients.Logger.Info("websocket tape closed",
+		zap.String("path", r.tapeRecorder.Path()),
+		zap.Uint64("frames", r.tapeRecorder.Count()),
+	)
+}
+
+// runReplay replays the configured tape through the trade monitor.
+// The dashboard keeps serving the results until ctx is canceled.
+func (r *Runner) runReplay(ctx context.Context, tape config.TapeConfig) {
+	replayer := NewReplayer(r.clients.Logger, r.tradeMonitor, r.replayClock, tape.ReplaySpeed)
+	stats, err := replayer.Run(ctx, tape.ReplayFile)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		r.clients.Logger.Error("tape replay failed", zap.Error(err))
+		return
+	}
+
+	if r.responseCache != nil {
+		entries, hits, misses := r.responseCache.Stats()
+		r.clients.Logger.Info("replay response cache usage",
+			zap.Int("entries", entries),
+			zap.Uint64("hits", hits),
+			zap.Uint64("misses", misses),
+		)
+	}
+
+	filters := r.tradeMonitor.FilterStats()
+	r.clients.Logger.Info("replay finished",
+		zap.String("tape", tape.ReplayFile),
+		zap.Int("frames", stats.Frames),
+		zap.Int("events", stats.Events),
+		zap.Int("alerts", filters.AlertsSent),
+	)
+}
@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"polybot/clients/polymarketevents"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// writeTestTape writes frames to a tape with the given spacing between frames.
+func writeTestTape(t *testing.T, frames []string, spacing time.Duration) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tape.jsonl")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	enc := json.NewEncoder(file)
+	for i, frame := range frames {
+		entry := polymarketevents.TapeEntry{
+			Timestamp: start.Add(time.Duration(i) * spacing),
+			Frame:     json.RawMessage(frame),
+		}
+		if !json.Valid([]byte(frame)) {
+			// Non-JSON frames are stored as strings, like TapeRecorder does
+			entry.Frame, _ = json.Marshal(frame)
+		}
+		if err := enc.Encode(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+// newTestReplayClock drives monitor's time windows from a replay clock, as the
+// runner does in replay mode.
+func newTestReplayClock(monitor *TradeMonitor) *replayClock {
+	clock := &replayClock{}
+	monitor.setClock(clock.Now)
+	return clock
+}
+
+func TestReplayer_Unthrottled(t *testing.T) {
+	monitor, server, tracker := newTestTradeMonitor(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]any{})
+	})
+	defer server.Close()
+
+	capture := &captureNotifier{}
+	monitor.notifier = capture
+	tracker.cache["0xwallet"] = &WalletStats{Wallet: "0xwallet", UniqueMarkets: 1, WinCount: 3, LossCount: 1, WinRate: 0.75, FetchedAt: time.Now()}
+
+	path := writeTestTape(t, []string{
+		`{"event_type":"book","asset_id":"token1"}`,
+		`[{"event_type":"trade","asset_id":"token1","price":"0.5","size":"5000","side":"BUY","taker_address":"0xwallet","transaction_hash":"0xtx1"},{"event_type":"price_change","asset_id":"token1"}]`,
+		`{"event_type":"trade","asset_id":"token1","price":"0.5","size":"5000","side":"BUY","taker_address":"0xwallet","transaction_hash":"0xtx1"}`,
+		`[{"bad"`,
+	}, time.Hour)
+
+	replayer := NewReplayer(zap.NewNop(), monitor, newTestReplayClock(monitor), 0)
+	start := time.Now()
+	stats, err := replayer.Run(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("expected unthrottled replay to ignore tape timing")
+	}
+
+	if stats.Frames != 4 || stats.Events != 4 || stats.BadFrame != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.TapeSpan != 3*time.Hour {
+		t.Errorf("expected 3h tape span, got %v", stats.TapeSpan)
+	}
+
+	counts := monitor.EventTypeCounts()
+	if counts["trade"] != 2 || counts["book"] != 1 || counts["price_change"] != 1 {
+		t.Errorf("unexpected event type counts: %v", counts)
+	}
+
+	// The duplicate trade is deduplicated, just like on the live feed
+	if alerts := capture.Alerts(); len(alerts) != 1 {
+		t.Errorf("expected 1 alert, got %d", len(alerts))
+	}
+}
+
+func TestReplayer_Paced(t *testing.T) {
+	monitor := NewTradeMonitor(zap.NewNop(), nil, nil, nil, nil, nil, DefaultTradeMonitorConfig())
+
+	path := writeTestTape(t, []string{
+		`{"event_type":"book"}`,
+		`{"event_type":"book"}`,
+		`{"event_type":"book"}`,
+	}, time.Second)
+
+	// 2s of tape at 20x should take ~100ms
+	replayer := NewReplayer(zap.NewNop(), monitor, newTestReplayClock(monitor), 20)
+	stats, err := replayer.Run(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if stats.Elapsed < 90*time.Millisecond {
+		t.Errorf("expected replay to be paced, took %v", stats.Elapsed)
+	}
+	if stats.Elapsed > 2*time.Second {
+		t.Errorf("expected replay to be accelerated, took %v", stats.Elapsed)
+	}
+}
+
+func TestReplayer_ContextCanceled(t *testing.T) {
+	monitor := NewTradeMonitor(zap.NewNop(), nil, nil, nil, nil, nil, DefaultTradeMonitorConfig())
+
+	path := writeTestTape(t, []string{
+		`{"event_type":"book"}`,
+		`{"event_type":"book"}`,
+	}, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	replayer := NewReplayer(zap.NewNop(), monitor, newTestReplayClock(monitor), 1)
+	stats, err := replayer.Run(ctx, path)
+	if err == nil {
+		t.Error("expected context error")
+	}
+	if stats.Frames != 1 {
+		t.Errorf("expected 1 frame before cancel, got %d", stats.Frames)
+	}
+}
+
+func TestReplayer_MissingTape(t *testing.T) {
+	replayer := NewReplayer(nil, nil, &replayClock{}, 1)
+	if _, err := replayer.Run(context.Background(), filepath.Join(t.TempDir(), "missing.jsonl.gz")); err == nil {
+		t.Error("expected error for missing tape")
+	}
+}
+
+func TestReplayer_RapidTradingOnOldTape(t *testing.T) {
+	monitor, server, tracker := newTestTradeMonitor(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]any{})
+	})
+	defer server.Close()
+
+	capture := &captureNotifier{}
+	monitor.notifier = capture
+	cfg := monitor.getConfig()
+	cfg.RapidTradeWindow = 5 * time.Minute
+	cfg.RapidTradeMinCount = 3
+	cfg.RapidTradeMinTotal = 5000
+	monitor.UpdateConfig(cfg)
+	tracker.cache["0xwallet"] = &WalletStats{Wallet: "0xwallet", UniqueMarkets: 10, WinCount: 3, LossCount: 1, WinRate: 0.75, FetchedAt: time.Now()}
+
+	// Three trades a minute apart, recorded long ago
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC).Unix()
+	frames := make([]string, 3)
+	for i := range frames {
+		frames[i] = fmt.Sprintf(`{"event_type":"trade","asset_id":"token1","price":"0.5","size":"4000","side":"BUY","taker_address":"0xwallet","transaction_hash":"0xtx%d","timestamp":"%d"}`,
+			i, start+int64(i*60))
+	}
+	path := writeTestTape(t, frames, time.Minute)
+
+	if _, err := NewReplayer(zap.NewNop(), monitor, newTestReplayClock(monitor), 0).Run(context.Background(), path); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	rapid := false
+	for _, alert := range capture.Alerts() {
+		for _, reason := range alert.Reasons {
+			rapid = rapid || string(reason) == string(AlertReasonRapidTrading)
+		}
+	}
+	if !rapid {
+		t.Errorf("expected a rapid_trading alert, got %+v", capture.Alerts())
+	}
+}
+
+func TestReplayClock_TrackerWindows(t *testing.T) {
+	clock := &replayClock{}
+	if time.Since(clock.Now()) > time.Minute {
+		t.Error("expected the wall clock before the first frame")
+	}
+
+	tracker := NewCopyTracker(zap.NewNop(), CopyTrackerConfig{TimeWindow: 10 * time.Minute, MinCopyCount: 2}, nil)
+	tracker.now = clock.Now
+
+	// Replayed instantly, trades an hour apart on the tape are still outside the window
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	clock.set(start)
+	tracker.RecordLeaderTrade("0xleader", "cond1", "token1", "BUY")
+	clock.set(start.Add(time.Hour))
+	if isCopy, _ := tracker.CheckForCopy("0xlate", "cond1", "token1", "BUY"); isCopy {
+		t.Error("expected a trade an hour later on the tape not to be a copy")
+	}
+
+	clock.set(start)
+	tracker.RecordLeaderTrade("0xleader", "cond1", "token1", "BUY")
+	clock.set(start.Add(5 * time.Minute))
+	if isCopy, _ := tracker.CheckForCopy("0xfollower", "cond1", "token1", "BUY"); !isCopy {
+		t.Error("expected a trade 5 minutes later on the tape to be a copy")
+	}
+}
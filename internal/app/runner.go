@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	clts "polybot/clients"
+	"polybot/clients/notifier"
 	"polybot/clients/polymarketapi"
+	"polybot/clients/polymarketevents"
 	"polybot/config"
 	"runtime"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -50,11 +53,29 @@ type Runner struct {
 	copyTracker     *CopyTracker
 	hedgeTracker    *HedgeTracker
 	patternTracker  *PatternTracker
+	alertOutcomes   *AlertOutcomeTracker
+	deferredAlerts  *DeferredAlertDispatcher
+	deadLetters     *DeadLetterQueue
+	alertRouter     *AlertRouter
+	alertDigester   *AlertDigester
+	alertSuppressor *AlertSuppressor
+	marketAnomalies *MarketAnomalyMonitor
+	walletClusters  *WalletClusters
+	muteList        *MuteList
+	alertFeedback   *AlertFeedbackStore
+	messageThreads  *MessageThreadStore
+	telegramCmds    *TelegramCommandHandler
+	discordCmds     *DiscordCommandHandler
 	healthServer    *http.Server
 	startTime       time.Time
 
 	// Cached markets for WebSocket reconnection
 	lastMarkets []polymarketapi.GammaMarket
+
+	// Tape mode (optional): raw frame recorder and REST response cache
+	tapeRecorder  *polymarketevents.TapeRecorder
+	responseCache *polymarketapi.ResponseCache
+	replayClock   *replayClock // Replays only: the time of the trade being replayed
 }
 
 // ServiceStats holds comprehensive service statistics.
@@ -89,6 +110,15 @@ type ServiceStats struct {
 		TopVolume24h float64 `json:"top_volume_24h,omitempty"`
 	} `json:"markets"`
 
+	// L2 order books from the market channel (deepest books first)
+	OrderBooks *OrderBookStats `json:"order_books,omitempty"`
+
+	// Market-level volume spikes and price moves
+	MarketAnomalies *MarketAnomalyStats `json:"market_anomalies,omitempty"`
+
+	// Wallets linked as the same actor
+	WalletClusters *WalletClusterStats `json:"wallet_clusters,omitempty"`
+
 	// Filter stats (trades processed)
 	Filters struct {
 		SkippedLowNotional  int `json:"skipped_low_notional"`
@@ -115,6 +145,9 @@ type ServiceStats struct {
 		ConvictionDoubling  int `json:"conviction_doubling"`
 		PerfectExitTiming   int `json:"perfect_exit_timing"`
 		StealthAccumulation int `json:"stealth_accumulation"`
+		PreMovePositioning  int `json:"pre_move_positioning"`
+		MarketImpact        int `json:"market_impact"`
+		CoordinatedEntry    int `json:"coordinated_entry"`
 	} `json:"alerts"`
 
 	// Cache stats
@@ -135,6 +168,10 @@ type ServiceStats struct {
 			PendingEvents int `json:"pending_events"`
 			TrackedExits  int `json:"tracked_exits"`
 		} `json:"hedge_tracker"`
+		AlertOutcomes struct {
+			TrackedAlerts int `json:"tracked_alerts"`
+			PendingAlerts int `json:"pending_alerts"`
+		} `json:"alert_outcomes"`
 		PatternTracker struct {
 			PendingExits    int `json:"pending_exits"`
 			VerifiedWallets int `json:"verified_wallets"`
@@ -154,6 +191,12 @@ type ServiceStats struct {
 	// Top alerting markets
 	TopMarkets []MarketAlertInfo `json:"top_markets"`
 
+	// Alert outcome scoreboard (precision, hit rate and ROI per heuristic)
+	AlertOutcomes *AlertOutcomeSummary `json:"alert_outcomes,omitempty"`
+
+	// Alert feedback from Discord buttons (false positive rates per heuristic)
+	AlertFeedback *AlertFeedbackSummary `json:"alert_feedback,omitempty"`
+
 	// Monitored market names
 	MarketNames []string `json:"market_names"`
 
@@ -184,6 +227,25 @@ type ServiceStats struct {
 		DiscordChannelID string `json:"discord_channel_id,omitempty"`
 		TelegramEnabled  bool   `json:"telegram_enabled"`
 		TelegramChatID   string `json:"telegram_chat_id,omitempty"`
+		SlackEnabled     bool   `json:"slack_enabled"`
+		Webhooks         int    `json:"webhooks"`
+
+		// Per-channel delivery metrics
+		Delivery    []notifier.DeliveryStats `json:"delivery"`
+		DeadLetters int                      `json:"dead_letters"`
+
+		// Wallet+market threads follow-up alerts reply to
+		Threads int `json:"threads"`
+
+		// Alert routing rules and digest mode (nil in replay mode)
+		Routing *RoutingStats `json:"routing,omitempty"`
+		Digest  *DigestStats  `json:"digest,omitempty"`
+
+		// Wallet and market mutes set from chat commands
+		Mutes *MuteStats `json:"mutes,omitempty"`
+
+		// Repeat alerts merged into updates
+		Suppression *SuppressionStats `json:"suppression,omitempty"`
 	} `json:"notifications"`
 
 	// Runtime stats
@@ -235,7 +297,16 @@ func (r *Runner) OnConfigUpdate(cfg *config.Config) {
 			ContrarianMinNotional:   cfg.TradeMonitor.ContrarianMinNotional,
 			MassiveTradeMinNotional: cfg.TradeMonitor.MassiveTradeMinNotional,
 			MassiveTradeMaxPrice:    cfg.TradeMonitor.MassiveTradeMaxPrice,
+			ImpactMinNotional:       cfg.TradeMonitor.ImpactMinNotional,
+			ImpactVolumePct:         cfg.TradeMonitor.ImpactVolumePct,
+			ImpactDepthPct:          cfg.TradeMonitor.ImpactDepthPct,
+			ImpactPriceMovePct:      cfg.TradeMonitor.ImpactPriceMovePct,
+			ClusterMinWallets:       cfg.TradeMonitor.ClusterMinWallets,
+			ClusterMaxMarkets:       cfg.TradeMonitor.ClusterMaxMarkets,
+			ClusterWindow:           cfg.TradeMonitor.ClusterWindow,
+			ClusterMinNotional:      cfg.TradeMonitor.ClusterMinNotional,
 			ObviousPrice:            cfg.TradeMonitor.ObviousPrice,
+			FillAggregationWindow:   cfg.TradeMonitor.FillAggregationWindow,
 		})
 	}
 
@@ -287,6 +358,81 @@ func (r *Runner) OnConfigUpdate(cfg *config.Config) {
 			PreMoveAlertCooldown:     cfg.PatternTracker.PreMoveAlertCooldown,
 		})
 	}
+
+	// Update wallet filter (also edited by chat commands)
+	if r.tradeMonitor != nil {
+		r.tradeMonitor.SetWalletFilter(cfg.WalletFilter.SpecificWallets)
+	}
+	if r.muteList != nil {
+		r.muteList.UpdateConfig(MuteListConfig{
+			GistID:       cfg.Mutes.GistID,
+			FileName:     cfg.Mutes.FileName,
+			SaveInterval: cfg.Mutes.SaveInterval,
+		})
+	}
+	if r.alertFeedback != nil {
+		r.alertFeedback.UpdateConfig(AlertFeedbackConfig{
+			GistID:       cfg.Feedback.GistID,
+			FileName:     cfg.Feedback.FileName,
+			SaveInterval: cfg.Feedback.SaveInterval,
+			MaxAlerts:    cfg.Feedback.MaxAlerts,
+		})
+	}
+	if r.messageThreads != nil {
+		r.messageThreads.UpdateConfig(messageThreadStoreConfig(cfg.Threads))
+	}
+
+	// Update notifier queue and dead-letter config
+	for _, q := range r.clients.NotifierQueues {
+		q.UpdateConfig(clts.NotifierQueueConfig(cfg.NotifierQueue))
+	}
+	if r.alertDigester != nil {
+		r.alertDigester.UpdateConfig(alertDigesterConfig(cfg.Digest))
+	}
+	if r.alertSuppressor != nil {
+		r.alertSuppressor.UpdateConfig(alertSuppressorConfig(cfg.Suppression))
+	}
+	if r.marketAnomalies != nil {
+		r.marketAnomalies.UpdateConfig(marketAnomalyConfig(cfg.MarketAnomaly))
+	}
+	if r.walletClusters != nil {
+		r.walletClusters.UpdateConfig(walletClusterConfig(cfg.WalletCluster))
+	}
+	if r.alertRouter != nil {
+		r.alertRouter.UpdateConfig(cfg.Routing)
+		for _, q := range r.alertRouter.Queues() {
+			q.UpdateConfig(clts.NotifierQueueConfig(cfg.NotifierQueue))
+		}
+	}
+	if r.deadLetters != nil {
+		r.deadLetters.UpdateConfig(DeadLetterQueueConfig{
+			GistID:       cfg.NotifierQueue.DeadLetterGistID,
+			FileName:     cfg.NotifierQueue.DeadLetterFileName,
+			SaveInterval: cfg.NotifierQueue.DeadLetterSaveInterval,
+			MaxEntries:   cfg.NotifierQueue.DeadLetterMaxEntries,
+		})
+	}
+
+	// Update deferred alert dispatcher config
+	if r.deferredAlerts != nil {
+		r.deferredAlerts.UpdateConfig(DeferredAlertDispatcherConfig{
+			GistID:        cfg.DeferredAlerts.GistID,
+			FileName:      cfg.DeferredAlerts.FileName,
+			CheckInterval: cfg.DeferredAlerts.CheckInterval,
+			SentRetention: cfg.DeferredAlerts.SentRetention,
+		})
+	}
+
+	// Update alert outcome tracker config
+	if r.alertOutcomes != nil {
+		r.alertOutcomes.UpdateConfig(AlertOutcomeTrackerConfig{
+			GistID:                  cfg.AlertOutcomes.GistID,
+			FileName:                cfg.AlertOutcomes.FileName,
+			SaveInterval:            cfg.AlertOutcomes.SaveInterval,
+			ResolutionCheckInterval: cfg.AlertOutcomes.ResolutionCheckInterval,
+			MaxAlerts:               cfg.AlertOutcomes.MaxAlerts,
+		})
+	}
 }
 
 func (r *Runner) Run(ctx context.Context) error {
@@ -304,8 +450,25 @@ func (r *Runner) Run(ctx context.Context) error {
 		zap.Strings("categories", cfg.Markets.Categories),
 	)
 
+	// Route REST calls through the response cache (recorded live, served on replay)
+	if cfg.Tape.RESTCacheFile != "" {
+		if err := r.setupResponseCache(cfg.Tape); err != nil {
+			return fmt.Errorf("response cache setup failed: %w", err)
+		}
+	}
+
+	// Replays run every time window on the tape's time instead of the wall clock
+	now := time.Now
+	if cfg.Tape.IsReplay() {
+		r.replayClock = &replayClock{}
+		now = r.replayClock.Now
+	}
+
 	// Initialize contrarian cache (tracks wallets with contrarian betting history)
 	r.contrarianCache = NewContrarianCache(logger, cfg)
+	if !cfg.Storage.IsGist() {
+		r.contrarianCache.SetGistClient(r.clients.Storage)
+	}
 	if r.contrarianCache.IsEnabled() {
 		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
 		if err := r.contrarianCache.Load(loadCtx); err != nil {
@@ -328,6 +491,9 @@ func (r *Runner) Run(ctx context.Context) error {
 		r.contrarianCache,
 	)
 
+	// Recompute wallets from the legacy unkeyed format in the background
+	go r.contrarianCache.RecomputeLegacy(ctx, r.walletTracker.fetchClosedPositions)
+
 	// Initialize copy tracker
 	r.copyTracker = NewCopyTracker(
 		logger,
@@ -336,15 +502,33 @@ func (r *Runner) Run(ctx context.Context) error {
 			MinCopyCount:      cfg.TradeMonitor.CopyTradeMinCount,
 			LeaderMinWinRate:  cfg.TradeMonitor.CopyTradeLeaderMinWin,
 			LeaderMinResolved: cfg.TradeMonitor.CopyTradeLeaderMinRes,
+			GistID:            cfg.CopyTracker.GistID,
+			FileName:          cfg.CopyTracker.FileName,
+			SaveInterval:      cfg.CopyTracker.SaveInterval,
+			MaxPairs:          cfg.CopyTracker.MaxPairs,
 		},
 		r.contrarianCache,
 	)
+	r.copyTracker.now = now
+	r.copyTracker.SetGistClient(r.clients.Storage)
+	if r.copyTracker.IsEnabled() {
+		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := r.copyTracker.Load(loadCtx); err != nil {
+			logger.Warn("failed to load copy tracker from gist", zap.Error(err))
+		}
+		loadCancel()
+		r.copyTracker.Start(ctx)
+		_, followers := r.copyTracker.Stats()
+		logger.Info("copy tracker initialized",
+			zap.Int("followers", followers),
+		)
+	}
 
 	// Initialize hedge tracker
 	r.hedgeTracker = NewHedgeTracker(
 		logger,
 		r.clients.Polymarket,
-		r.clients.Gist,
+		r.clients.Storage,
 		HedgeTrackerConfig{
 			GistID:                  cfg.HedgeTracker.GistID,
 			FileName:                cfg.HedgeTracker.FileName,
@@ -359,6 +543,7 @@ func (r *Runner) Run(ctx context.Context) error {
 			ResolutionCheckInterval: cfg.HedgeTracker.ResolutionCheckInterval,
 		},
 	)
+	r.hedgeTracker.now = now
 	if r.hedgeTracker.IsEnabled() {
 		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
 		if err := r.hedgeTracker.Load(loadCtx); err != nil {
@@ -375,7 +560,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	r.patternTracker = NewPatternTracker(
 		logger,
 		r.clients.Polymarket,
-		r.clients.Gist,
+		r.clients.Storage,
 		PatternTrackerConfig{
 			GistID:                  cfg.PatternTracker.GistID,
 			FileName:                cfg.PatternTracker.FileName,
@@ -405,6 +590,7 @@ func (r *Runner) Run(ctx context.Context) error {
 			PreMoveAlertCooldown:    cfg.PatternTracker.PreMoveAlertCooldown,
 		},
 	)
+	r.patternTracker.now = now
 	if r.patternTracker.IsEnabled() {
 		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
 		if err := r.patternTracker.Load(loadCtx); err != nil {
@@ -420,6 +606,107 @@ func (r *Runner) Run(ctx context.Context) error {
 		)
 	}
 
+	// Initialize dead-letter queue for alerts the notifier channels fail to deliver
+	r.deadLetters = NewDeadLetterQueue(logger, r.clients.Storage, DeadLetterQueueConfig{
+		GistID:       cfg.NotifierQueue.DeadLetterGistID,
+		FileName:     cfg.NotifierQueue.DeadLetterFileName,
+		SaveInterval: cfg.NotifierQueue.DeadLetterSaveInterval,
+		MaxEntries:   cfg.NotifierQueue.DeadLetterMaxEntries,
+	})
+	if r.deadLetters.IsEnabled() {
+		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := r.deadLetters.Load(loadCtx); err != nil {
+			logger.Warn("failed to load dead letters from gist", zap.Error(err))
+		}
+		loadCancel()
+	}
+	r.deadLetters.Start(ctx)
+	channels := make(map[string]notifier.Notifier, len(r.clients.NotifierQueues))
+	for _, q := range r.clients.NotifierQueues {
+		q.SetDeadLetterHandler(r.deadLetters.Add)
+		channels[q.Name()] = q
+	}
+	if n := r.deadLetters.Redeliver(channels); n > 0 {
+		logger.Info("redelivering dead letters", zap.Int("alerts", n))
+	}
+
+	// Initialize wallet and market mutes (set from chat commands)
+	r.muteList = NewMuteList(logger, r.clients.Storage, MuteListConfig{
+		GistID:       cfg.Mutes.GistID,
+		FileName:     cfg.Mutes.FileName,
+		SaveInterval: cfg.Mutes.SaveInterval,
+	})
+	if r.muteList.IsEnabled() {
+		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := r.muteList.Load(loadCtx); err != nil {
+			logger.Warn("failed to load mutes from gist", zap.Error(err))
+		}
+		loadCancel()
+	}
+	r.muteList.Start(ctx)
+
+	// Initialize alert feedback (notified alerts and verdicts from alert buttons)
+	r.alertFeedback = NewAlertFeedbackStore(logger, r.clients.Storage, AlertFeedbackConfig{
+		GistID:       cfg.Feedback.GistID,
+		FileName:     cfg.Feedback.FileName,
+		SaveInterval: cfg.Feedback.SaveInterval,
+		MaxAlerts:    cfg.Feedback.MaxAlerts,
+	})
+	if r.alertFeedback.IsEnabled() {
+		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := r.alertFeedback.Load(loadCtx); err != nil {
+			logger.Warn("failed to load alert feedback from gist", zap.Error(err))
+		}
+		loadCancel()
+	}
+	r.alertFeedback.Start(ctx)
+
+	// Initialize message threads (follow-up alerts reply to the first alert per wallet and market)
+	r.messageThreads = NewMessageThreadStore(logger, r.clients.Storage, messageThreadStoreConfig(cfg.Threads))
+	if r.messageThreads.IsEnabled() {
+		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := r.messageThreads.Load(loadCtx); err != nil {
+			logger.Warn("failed to load message threads from gist", zap.Error(err))
+		}
+		loadCancel()
+	}
+	r.messageThreads.Start(ctx)
+	for _, q := range r.clients.NotifierQueues {
+		q.SetMessageThreads(r.messageThreads)
+	}
+
+	// Initialize alert outcome tracker (scores alerts once their markets resolve).
+	// Scoring runs in memory even when persistence is not configured.
+	r.alertOutcomes = NewAlertOutcomeTracker(
+		logger,
+		r.clients.Polymarket,
+		r.clients.Storage,
+		AlertOutcomeTrackerConfig{
+			GistID:                  cfg.AlertOutcomes.GistID,
+			FileName:                cfg.AlertOutcomes.FileName,
+			SaveInterval:            cfg.AlertOutcomes.SaveInterval,
+			ResolutionCheckInterval: cfg.AlertOutcomes.ResolutionCheckInterval,
+			MaxAlerts:               cfg.AlertOutcomes.MaxAlerts,
+		},
+	)
+	if r.alertOutcomes.IsEnabled() {
+		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := r.alertOutcomes.Load(loadCtx); err != nil {
+			logger.Warn("failed to load alert outcomes from gist", zap.Error(err))
+		}
+		loadCancel()
+	}
+	r.alertOutcomes.SetResolutionHandler(func(resolved []TrackedAlert) {
+		r.messageThreads.AnnotateOutcomes(ctx, resolved, r.messageAnnotators())
+	})
+	r.alertOutcomes.Start(ctx)
+	trackedAlerts, pendingAlerts := r.alertOutcomes.Stats()
+	logger.Info("alert outcome tracker initialized",
+		zap.Bool("persisted", r.alertOutcomes.IsEnabled()),
+		zap.Int("alerts", trackedAlerts),
+		zap.Int("pending", pendingAlerts),
+	)
+
 	// Initialize trade monitor with config
 	tradeMonitorCfg := TradeMonitorConfig{
 		PollInterval:          cfg.TradeMonitor.PollInterval,
@@ -438,7 +725,35 @@ func (r *Runner) Run(ctx context.Context) error {
 		ContrarianMinNotional:   cfg.TradeMonitor.ContrarianMinNotional,
 		MassiveTradeMinNotional: cfg.TradeMonitor.MassiveTradeMinNotional,
 		MassiveTradeMaxPrice:    cfg.TradeMonitor.MassiveTradeMaxPrice,
+		ImpactMinNotional:       cfg.TradeMonitor.ImpactMinNotional,
+		ImpactVolumePct:         cfg.TradeMonitor.ImpactVolumePct,
+		ImpactDepthPct:          cfg.TradeMonitor.ImpactDepthPct,
+		ImpactPriceMovePct:      cfg.TradeMonitor.ImpactPriceMovePct,
+		ClusterMinWallets:       cfg.TradeMonitor.ClusterMinWallets,
+		ClusterMaxMarkets:       cfg.TradeMonitor.ClusterMaxMarkets,
+		ClusterWindow:           cfg.TradeMonitor.ClusterWindow,
+		ClusterMinNotional:      cfg.TradeMonitor.ClusterMinNotional,
 		ObviousPrice:            cfg.TradeMonitor.ObviousPrice,
+		FillAggregationWindow:   cfg.TradeMonitor.FillAggregationWindow,
+	}
+	// Replays only log alerts and show them on the dashboard
+	var alertNotifier notifier.Notifier
+	if cfg.Tape.IsReplay() {
+		logger.Info("replay mode: notifications disabled",
+			zap.String("tape", cfg.Tape.ReplayFile),
+		)
+	} else {
+		// Digest mode batches alerts for the default chat channels; webhooks get every alert
+		defaultNotifier := r.clients.Notifier
+		if r.clients.ChatNotifier != nil && r.clients.WebhookNotifier != nil {
+			r.alertDigester = NewAlertDigester(logger, alertDigesterConfig(cfg.Digest), r.clients.ChatNotifier)
+			r.alertDigester.Start(ctx)
+			defaultNotifier = notifier.NewMultiNotifier(r.alertDigester, r.clients.WebhookNotifier)
+		}
+
+		// Routing rules pick destinations; unmatched alerts go to the default channels
+		r.alertRouter = NewAlertRouter(logger, cfg.Routing, defaultNotifier, r.newRouteNotifier)
+		alertNotifier = r.alertRouter
 	}
 	r.tradeMonitor = NewTradeMonitor(
 		logger,
@@ -446,12 +761,40 @@ func (r *Runner) Run(ctx context.Context) error {
 		r.walletTracker,
 		r.contrarianCache,
 		r.copyTracker,
-		r.clients.Notifier,
+		alertNotifier,
 		tradeMonitorCfg,
 	)
+	r.tradeMonitor.setClock(now)
+	if r.alertRouter != nil {
+		r.alertRouter.SetCategoryLookup(r.tradeMonitor.MarketCategories)
+	}
+	if r.alertDigester != nil {
+		r.alertDigester.SetAlertCountSource(r.tradeMonitor)
+	}
+	r.tradeMonitor.SetMuteList(r.muteList)
+	r.tradeMonitor.SetAlertFeedbackStore(r.alertFeedback)
+
+	// Merge repeat alerts into updates (windows are restored with the seen trades)
+	r.alertSuppressor = NewAlertSuppressor(logger, alertSuppressorConfig(cfg.Suppression))
+	r.alertSuppressor.now = now
+	r.tradeMonitor.SetAlertSuppressor(r.alertSuppressor)
+	r.alertSuppressor.Start(ctx)
+
+	// Market alerts skip routing and digests and go straight to the chat channels
+	var marketNotifier notifier.Notifier
+	if !cfg.Tape.IsReplay() {
+		marketNotifier = r.clients.Notifier
+	}
+	r.marketAnomalies = NewMarketAnomalyMonitor(logger, marketAnomalyConfig(cfg.MarketAnomaly), marketNotifier)
+	r.marketAnomalies.SetMuteList(r.muteList)
+	r.tradeMonitor.SetMarketAnomalyMonitor(r.marketAnomalies)
+
+	r.walletClusters = NewWalletClusters(logger, walletClusterConfig(cfg.WalletCluster))
+	r.walletClusters.SetCopyTracker(r.copyTracker)
+	r.tradeMonitor.SetWalletClusters(r.walletClusters)
 
 	// Wire up WebSocket events client
-	if r.clients.PolymarketEvents != nil {
+	if r.clients.PolymarketEvents != nil && !cfg.Tape.IsReplay() {
 		r.tradeMonitor.SetEventsClient(r.clients.PolymarketEvents)
 		logger.Info("WebSocket events client configured")
 	}
@@ -466,6 +809,34 @@ func (r *Runner) Run(ctx context.Context) error {
 		r.tradeMonitor.SetPatternTracker(r.patternTracker)
 	}
 
+	// Wire up alert outcome tracking
+	if r.alertOutcomes != nil {
+		r.tradeMonitor.SetAlertOutcomeTracker(r.alertOutcomes)
+	}
+
+	// Deliver alerts raised by background trackers through the trade monitor
+	r.deferredAlerts = NewDeferredAlertDispatcher(
+		logger,
+		r.clients.Storage,
+		DeferredAlertDispatcherConfig{
+			GistID:        cfg.DeferredAlerts.GistID,
+			FileName:      cfg.DeferredAlerts.FileName,
+			CheckInterval: cfg.DeferredAlerts.CheckInterval,
+			SentRetention: cfg.DeferredAlerts.SentRetention,
+		},
+		r.hedgeTracker,
+		r.patternTracker,
+		r.tradeMonitor.SendDeferredAlert,
+	)
+	if r.deferredAlerts.IsEnabled() {
+		loadCtx, loadCancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := r.deferredAlerts.Load(loadCtx); err != nil {
+			logger.Warn("failed to load deferred alert markers from gist", zap.Error(err))
+		}
+		loadCancel()
+	}
+	r.deferredAlerts.Start(ctx)
+
 	// Set up wallet filter if configured
 	if len(cfg.WalletFilter.SpecificWallets) > 0 {
 		r.tradeMonitor.SetWalletFilter(cfg.WalletFilter.SpecificWallets)
@@ -474,7 +845,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	// Initialize cache persister (needs tradeMonitor for seen trades persistence)
 	r.cachePersister = NewCachePersister(
 		logger,
-		r.clients.Gist,
+		r.clients.Storage,
 		r.walletTracker,
 		r.tradeMonitor,
 		cfg.Cache.SaveInterval,
@@ -515,10 +886,10 @@ func (r *Runner) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to update markets: %w", err)
 	}
 
-	// Connect WebSocket if available
-	if r.clients.PolymarketEvents != nil {
-		if err := r.connectWebSocket(ctx); err != nil {
-			logger.Warn("failed to connect WebSocket, falling back to polling", zap.Error(err))
+	// Record raw WebSocket frames to a tape if configured
+	if cfg.Tape.RecordDir != "" && r.clients.PolymarketEvents != nil && !cfg.Tape.IsReplay() {
+		if err := r.startTapeRecording(cfg.Tape.RecordDir); err != nil {
+			logger.Warn("failed to start tape recording", zap.Error(err))
 		}
 	}
 
@@ -528,13 +899,72 @@ func (r *Runner) Run(ctx context.Context) error {
 		logger.Info("health server started", zap.Int("port", cfg.HealthServer.Port))
 	}
 
-	go r.tradeMonitor.Run(ctx)
+	if cfg.Tape.IsReplay() {
+		r.runReplay(ctx, cfg.Tape)
+		<-ctx.Done()
+		logger.Info("runner shutting down")
+		r.shutdown()
+		return nil
+	}
+
+	// Connect WebSocket if available
+	if r.clients.PolymarketEvents != nil {
+		if err := r.connectWebSocket(ctx); err != nil {
+			logger.Warn("failed to connect WebSocket, falling back to polling", zap.Error(err))
+		}
+	}
+
+	// Closed once the monitor has evaluated the orders pending at shutdown
+	monitorDone := make(chan struct{})
+	go func() {
+		defer close(monitorDone)
+		r.tradeMonitor.Run(ctx)
+	}()
 
 	logger.Info("trade monitor started",
 		zap.Float64("minNotional", tradeMonitorCfg.MinNotional),
 		zap.Int("maxMarketsForLowActivity", tradeMonitorCfg.MaxMarketsForLow),
 	)
 
+	// Answer Telegram bot commands in the alert chat
+	if cfg.Telegram.CommandsEnabled && r.clients.Telegram != nil && r.clients.Telegram.IsEnabled() {
+		if len(cfg.Telegram.AllowedUserIDs) == 0 {
+			logger.Warn("TELEGRAM_ALLOWED_USER_IDS not set, anyone in the alert chat can run commands")
+		}
+		r.telegramCmds = NewTelegramCommandHandler(
+			logger,
+			r.clients.Telegram,
+			r.clients.Polymarket,
+			r.settingsManager,
+			r.muteList,
+			r.GetStats,
+			cfg.Telegram.AllowedUserIDs,
+		)
+		r.telegramCmds.Start(ctx)
+	}
+
+	// Answer Discord slash commands and alert buttons
+	if cfg.Discord.CommandsEnabled && r.clients.Discord != nil && r.clients.Discord.IsEnabled() {
+		if len(cfg.Discord.AllowedUserIDs) == 0 {
+			logger.Warn("DISCORD_ALLOWED_USER_IDS not set, anyone who can see the command can run it")
+		}
+		r.discordCmds = NewDiscordCommandHandler(
+			logger,
+			r.clients.Discord,
+			r.clients.Polymarket,
+			r.settingsManager,
+			r.muteList,
+			r.alertFeedback,
+			r.GetStats,
+			cfg.Discord.GuildID,
+			cfg.Discord.AllowedUserIDs,
+		)
+		if err := r.discordCmds.Start(); err != nil {
+			logger.Error("failed to start discord commands", zap.Error(err))
+			r.discordCmds = nil
+		}
+	}
+
 	// Start market refresh loop
 	go r.runMarketRefresher(ctx, cfg.Markets.TopMarketsCount, cfg.Markets.RefreshInterval)
 
@@ -546,19 +976,153 @@ func (r *Runner) Run(ctx context.Context) error {
 		go r.runWSReconnector(ctx)
 	}
 
+	// Periodically save recorded REST responses
+	if r.responseCache != nil {
+		go r.runResponseCacheSaver(ctx, cfg.Cache.SaveInterval)
+	}
+
 	<-ctx.Done()
 	logger.Info("runner shutting down")
 
+	// Let the monitor's final alerts reach the notifier queues before they close
+	<-monitorDone
+
 	// Close WebSocket connection
 	if r.clients.PolymarketEvents != nil {
 		_ = r.clients.PolymarketEvents.Close()
 	}
 
+	r.shutdown()
+	return nil
+}
+
+// newRouteNotifier creates the delivery queue for an alert routing destination.
+func (r *Runner) newRouteNotifier(dest config.RouteDestination) (*notifier.QueuedNotifier, error) {
+	q, err := r.clients.NewRouteNotifier(dest, clts.NotifierQueueConfig(r.liveConfig.Get().NotifierQueue))
+	if err != nil {
+		return nil, err
+	}
+	if r.deadLetters != nil {
+		q.SetDeadLetterHandler(r.deadLetters.Add)
+	}
+	if r.messageThreads != nil {
+		q.SetMessageThreads(r.messageThreads)
+	}
+	return q, nil
+}
+
+// messageAnnotators returns every delivery queue, including routed destinations.
+func (r *Runner) messageAnnotators() []MessageAnnotator {
+	annotators := make([]MessageAnnotator, 0, len(r.clients.NotifierQueues))
+	for _, q := range r.clients.NotifierQueues {
+		annotators = append(annotators, q)
+	}
+	if r.alertRouter != nil {
+		for _, q := range r.alertRouter.Queues() {
+			annotators = append(annotators, q)
+		}
+	}
+	return annotators
+}
+
+// messageThreadStoreConfig maps thread settings from config.
+func messageThreadStoreConfig(cfg config.ThreadsConfig) MessageThreadStoreConfig {
+	return MessageThreadStoreConfig{
+		Enabled:          cfg.Enabled,
+		AnnotateOutcomes: cfg.AnnotateOutcomes,
+		Retention:        cfg.Retention,
+		MaxThreads:       cfg.MaxThreads,
+		GistID:           cfg.GistID,
+		FileName:         cfg.FileName,
+		SaveInterval:     cfg.SaveInterval,
+	}
+}
+
+// alertSuppressorConfig maps suppression settings from config.
+func alertSuppressorConfig(cfg config.SuppressionConfig) AlertSuppressorConfig {
+	reasonCooldowns := make(map[AlertReason]time.Duration, len(cfg.ReasonCooldowns))
+	for _, rc := range cfg.ReasonCooldowns {
+		reasonCooldowns[AlertReason(strings.TrimSpace(rc.Reason))] = rc.Cooldown
+	}
+	return AlertSuppressorConfig{
+		Enabled:         cfg.Enabled,
+		Cooldown:        cfg.Cooldown,
+		ReasonCooldowns: reasonCooldowns,
+	}
+}
+
+// marketAnomalyConfig maps market anomaly settings from config.
+func marketAnomalyConfig(cfg config.MarketAnomalyConfig) MarketAnomalyConfig {
+	return MarketAnomalyConfig{
+		Enabled:           cfg.Enabled,
+		Window:            cfg.Window,
+		BaselineWindows:   cfg.BaselineWindows,
+		VolumeStdDevs:     cfg.VolumeStdDevs,
+		VolumeMinNotional: cfg.VolumeMinNotional,
+		PriceMove:         cfg.PriceMove,
+		PriceWindow:       cfg.PriceWindow,
+		PriceMinNotional:  cfg.PriceMinNotional,
+		Cooldown:          cfg.Cooldown,
+		TopWallets:        cfg.TopWallets,
+	}
+}
+
+// walletClusterConfig maps wallet clustering settings from config.
+func walletClusterConfig(cfg config.WalletClusterConfig) WalletClusterConfig {
+	return WalletClusterConfig{
+		Enabled:           cfg.Enabled,
+		MinNotional:       cfg.MinNotional,
+		CoTradeWindow:     cfg.CoTradeWindow,
+		CoTradeMinMarkets: cfg.CoTradeMinMarkets,
+		SizeWindow:        cfg.SizeWindow,
+		SizeTolerance:     cfg.SizeTolerance,
+		SizeMinMarkets:    cfg.SizeMinMarkets,
+		CopyMinCount:      cfg.CopyMinCount,
+		EvidenceMaxAge:    cfg.EvidenceMaxAge,
+	}
+}
+
+// alertDigesterConfig maps digest settings from config.
+func alertDigesterConfig(cfg config.DigestConfig) AlertDigesterConfig {
+	reasons := make([]AlertReason, 0, len(cfg.InstantReasons))
+	for _, r := range cfg.InstantReasons {
+		reasons = append(reasons, AlertReason(strings.TrimSpace(r)))
+	}
+	return AlertDigesterConfig{
+		Enabled:        cfg.Enabled,
+		Window:         cfg.Window,
+		TopN:           cfg.TopN,
+		InstantReasons: reasons,
+	}
+}
+
+// shutdown stops trackers (saving pending changes), closes the tape and
+// stops the health server.
+func (r *Runner) shutdown() {
+	// Close tape and save recorded REST responses
+	r.stopTapeRecording()
+	if r.responseCache != nil && !r.liveConfig.Get().Tape.IsReplay() {
+		r.saveResponseCache()
+	}
+
+	// Stop answering chat commands
+	if r.telegramCmds != nil {
+		r.telegramCmds.Stop()
+	}
+	if r.discordCmds != nil {
+		r.discordCmds.Stop()
+	}
+
 	// Stop contrarian cache (saves pending changes)
 	if r.contrarianCache != nil {
 		r.contrarianCache.Stop()
 	}
 
+	// Stop copy tracker (saves pending changes)
+	if r.copyTracker != nil {
+		r.copyTracker.Stop()
+	}
+
 	// Stop hedge tracker (saves pending changes)
 	if r.hedgeTracker != nil {
 		r.hedgeTracker.Stop()
@@ -569,14 +1133,72 @@ func (r *Runner) Run(ctx context.Context) error {
 		r.patternTracker.Stop()
 	}
 
+	// Stop alert outcome tracker (saves pending changes)
+	if r.alertOutcomes != nil {
+		r.alertOutcomes.Stop()
+	}
+
+	// Stop deferred alert dispatcher
+	if r.deferredAlerts != nil {
+		r.deferredAlerts.Stop()
+	}
+
+	// Stop sending updates (merged repeats are saved with the seen trades)
+	if r.alertSuppressor != nil {
+		r.alertSuppressor.Stop()
+	}
+
+	// Persist mutes
+	if r.muteList != nil {
+		saveCtx, saveCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := r.muteList.Save(saveCtx); err != nil {
+			r.clients.Logger.Warn("failed to save mutes", zap.Error(err))
+		}
+		saveCancel()
+		r.muteList.Stop()
+	}
+
+	// Persist alert feedback
+	if r.alertFeedback != nil {
+		saveCtx, saveCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := r.alertFeedback.Save(saveCtx); err != nil {
+			r.clients.Logger.Warn("failed to save alert feedback", zap.Error(err))
+		}
+		saveCancel()
+		r.alertFeedback.Stop()
+	}
+
+	// Close notifier queues (unsent alerts are dead-lettered), then persist dead letters
+	if r.alertRouter != nil {
+		_ = r.alertRouter.Close()
+	} else if r.clients.Notifier != nil {
+		_ = r.clients.Notifier.Close()
+	}
+	if r.deadLetters != nil {
+		saveCtx, saveCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := r.deadLetters.Save(saveCtx); err != nil {
+			r.clients.Logger.Warn("failed to save dead letters", zap.Error(err))
+		}
+		saveCancel()
+		r.deadLetters.Stop()
+	}
+
+	// Persist message threads (after the queues close so in-flight replies are kept)
+	if r.messageThreads != nil {
+		saveCtx, saveCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := r.messageThreads.Save(saveCtx); err != nil {
+			r.clients.Logger.Warn("failed to save message threads", zap.Error(err))
+		}
+		saveCancel()
+		r.messageThreads.Stop()
+	}
+
 	// Shutdown health server
 	if r.healthServer != nil {
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 		_ = r.healthServer.Shutdown(shutdownCtx)
 		shutdownCancel()
 	}
-
-	return nil
 }
 
 // connectWebSocket connects the WebSocket and subscribes to current markets.
@@ -754,6 +1376,7 @@ func (r *Runner) refreshTopMarkets(ctx context.Context, limit int) error {
 		zap.Int("skippedLowNotional", filterStats.SkippedLowNotional),
 		zap.Int("skippedNoWallet", filterStats.SkippedNoWallet),
 		zap.Int("skippedHighActivity", filterStats.SkippedHighActivity),
+		zap.Int64("aggregatedFills", filterStats.AggregatedFills),
 		zap.Int("alertsSent", filterStats.AlertsSent),
 		zap.Int("alertsLowActivity", filterStats.AlertsLowActivity),
 		zap.Int("alertsHighWinRate", filterStats.AlertsHighWinRate),
@@ -762,6 +1385,8 @@ func (r *Runner) refreshTopMarkets(ctx context.Context, limit int) error {
 		zap.Int("alertsNewWallet", filterStats.AlertsNewWallet),
 		zap.Int("alertsContrarianBet", filterStats.AlertsContrarianBet),
 		zap.Int("alertsMassiveTrade", filterStats.AlertsMassiveTrade),
+		zap.Int("alertsMarketImpact", filterStats.AlertsMarketImpact),
+		zap.Int("alertsCoordinatedEntry", filterStats.AlertsCoordinatedEntry),
 		zap.Int("alertsContrarianWinner", filterStats.AlertsContrarianWinner),
 		zap.Int("alertsCopyTrader", filterStats.AlertsCopyTrader),
 		zap.Int("alertsHedgeRemoval", filterStats.AlertsHedgeRemoval),
@@ -853,13 +1478,18 @@ func (r *Runner) GetStats() ServiceStats {
 		stats.Alerts.ConvictionDoubling = fs.AlertsConvictionDoubling
 		stats.Alerts.PerfectExitTiming = fs.AlertsPerfectExitTiming
 		stats.Alerts.StealthAccumulation = fs.AlertsStealthAccumulation
+		stats.Alerts.PreMovePositioning = fs.AlertsPreMovePositioning
+		stats.Alerts.MarketImpact = fs.AlertsMarketImpact
+		stats.Alerts.CoordinatedEntry = fs.AlertsCoordinatedEntry
 		// Total is sum of all heuristic counts (a single alert can trigger multiple heuristics)
 		stats.Alerts.Total = stats.Alerts.LowActivity + stats.Alerts.HighWinRate +
 			stats.Alerts.ExtremeBet + stats.Alerts.RapidTrading + stats.Alerts.NewWallet +
 			stats.Alerts.ContrarianBet + stats.Alerts.MassiveTrade + stats.Alerts.ContrarianWinner +
 			stats.Alerts.CopyTrader + stats.Alerts.HedgeRemoval + stats.Alerts.AsymmetricExit +
 			stats.Alerts.ResolutionConfirmed + stats.Alerts.ConvictionDoubling +
-			stats.Alerts.PerfectExitTiming + stats.Alerts.StealthAccumulation
+			stats.Alerts.PerfectExitTiming + stats.Alerts.StealthAccumulation +
+			stats.Alerts.PreMovePositioning + stats.Alerts.MarketImpact +
+			stats.Alerts.CoordinatedEntry
 	}
 
 	// Cache stats
@@ -892,6 +1522,14 @@ func (r *Runner) GetStats() ServiceStats {
 		stats.Trackers.PatternTracker.Accumulations = accum
 	}
 
+	if r.alertOutcomes != nil {
+		tracked, pending := r.alertOutcomes.Stats()
+		stats.Trackers.AlertOutcomes.TrackedAlerts = tracked
+		stats.Trackers.AlertOutcomes.PendingAlerts = pending
+		summary := r.alertOutcomes.Summary()
+		stats.AlertOutcomes = &summary
+	}
+
 	// New dashboard features
 	if r.tradeMonitor != nil {
 		// Recent alerts feed
@@ -906,6 +1544,10 @@ func (r *Runner) GetStats() ServiceStats {
 		// Monitored market names
 		stats.MarketNames = r.tradeMonitor.MonitoredMarkets()
 
+		// Order books (top 5 by depth)
+		books := r.tradeMonitor.OrderBookStats(5)
+		stats.OrderBooks = &books
+
 		// Alert rate (alerts per hour)
 		uptime := time.Since(r.startTime)
 		if uptime.Hours() > 0 {
@@ -931,6 +1573,14 @@ func (r *Runner) GetStats() ServiceStats {
 		// Sparkline data (7 days, 24 buckets = ~7h each)
 		stats.AlertSparkline7d = r.tradeMonitor.AlertHistoryBuckets(7*24*time.Hour, 24)
 	}
+	if r.marketAnomalies != nil {
+		anomalies := r.marketAnomalies.Stats()
+		stats.MarketAnomalies = &anomalies
+	}
+	if r.walletClusters != nil {
+		clusters := r.walletClusters.Stats()
+		stats.WalletClusters = &clusters
+	}
 
 	// Notification status
 	cfg := r.liveConfig.Get()
@@ -951,6 +1601,43 @@ func (r *Runner) GetStats() ServiceStats {
 		}
 	}
 
+	stats.Notifications.SlackEnabled = r.clients.Slack != nil && r.clients.Slack.IsEnabled()
+	stats.Notifications.Webhooks = len(r.clients.Webhooks)
+
+	stats.Notifications.Delivery = make([]notifier.DeliveryStats, 0, len(r.clients.NotifierQueues))
+	for _, q := range r.clients.NotifierQueues {
+		stats.Notifications.Delivery = append(stats.Notifications.Delivery, q.Stats())
+	}
+	if r.alertRouter != nil {
+		for _, q := range r.alertRouter.Queues() {
+			stats.Notifications.Delivery = append(stats.Notifications.Delivery, q.Stats())
+		}
+		routing := r.alertRouter.Stats()
+		stats.Notifications.Routing = &routing
+	}
+	if r.alertDigester != nil {
+		digest := r.alertDigester.Stats()
+		stats.Notifications.Digest = &digest
+	}
+	if r.deadLetters != nil {
+		stats.Notifications.DeadLetters = r.deadLetters.Count()
+	}
+	if r.messageThreads != nil {
+		stats.Notifications.Threads = r.messageThreads.Count()
+	}
+	if r.muteList != nil {
+		mutes := r.muteList.Stats()
+		stats.Notifications.Mutes = &mutes
+	}
+	if r.alertSuppressor != nil {
+		suppression := r.alertSuppressor.Stats()
+		stats.Notifications.Suppression = &suppression
+	}
+	if r.alertFeedback != nil {
+		feedback := r.alertFeedback.Summary()
+		stats.AlertFeedback = &feedback
+	}
+
 	// Runtime stats
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
@@ -309,10 +309,12 @@ func TestRunner_RunContextCancellation(t *testing.T) {
 		},
 	}
 
+	gistClient := gist.NewClient(zap.NewNop(), cfg)
 	clts := &clients.Clients{
 		Logger:     zap.NewNop(),
 		Polymarket: polymarketapi.NewPolymarketApiClient(zap.NewNop(), cfg),
-		Gist:       gist.NewClient(zap.NewNop(), cfg),
+		Gist:       gistClient,
+		Storage:    gistClient,
 	}
 
 	runner := NewRunner(clts, config.NewLiveConfig(cfg), nil, nil)
@@ -3,7 +3,10 @@ package app
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"polybot/clients/storage"
 	"polybot/config"
 	"time"
 
@@ -15,6 +18,7 @@ type SettingsHandler struct {
 	logger      *zap.Logger
 	settings    *config.SettingsManager
 	authHandler *AuthHandler
+	conflicts   storage.ConflictReporter // Optional; reports conflicting saves
 }
 
 // NewSettingsHandler creates a new SettingsHandler.
@@ -29,11 +33,18 @@ func NewSettingsHandler(logger *zap.Logger, settings *config.SettingsManager, au
 	}
 }
 
+// SetConflictReporter sets the storage whose conflicting saves are shown on
+// the settings page.
+func (h *SettingsHandler) SetConflictReporter(reporter storage.ConflictReporter) {
+	h.conflicts = reporter
+}
+
 // RegisterRoutes registers the settings routes on the given mux.
 func (h *SettingsHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/settings", h.handleSettingsPage)
 	mux.HandleFunc("/api/settings", h.handleSettingsAPI)
 	mux.HandleFunc("/api/settings/reset", h.handleSettingsReset)
+	mux.HandleFunc("/api/settings/reload", h.handleSettingsReload)
 	mux.HandleFunc("/api/settings/info", h.handleSettingsInfo)
 }
 
@@ -103,22 +114,38 @@ func (h *SettingsHandler) updateSettings(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var newConfig config.Config
-
-	// Start with current config as base
-	currentConfig := h.settings.GetCurrentConfig()
-	newConfig = *currentConfig
-
-	// Decode request body on top of current config
-	if err := json.NewDecoder(r.Body).Decode(&newConfig); err != nil {
-		h.logger.Error("failed to decode settings", zap.Error(err))
-		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Error("failed to read settings", zap.Error(err))
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
 
-	// Validate
-	validation := newConfig.Validate()
-	if !validation.Valid {
+	// Update and save
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	var decodeErr error
+	var validation config.ValidationResult
+	err = h.settings.Update(ctx, func(cfg *config.Config) error {
+		// Decode request body on top of current config
+		if decodeErr = json.Unmarshal(body, cfg); decodeErr != nil {
+			return decodeErr
+		}
+
+		// Validate
+		validation = cfg.Validate()
+		if !validation.Valid {
+			return errors.New("invalid settings")
+		}
+		return nil
+	})
+	switch {
+	case decodeErr != nil:
+		h.logger.Error("failed to decode settings", zap.Error(decodeErr))
+		http.Error(w, "Invalid JSON: "+decodeErr.Error(), http.StatusBadRequest)
+		return
+	case !validation.Valid:
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		_ = json.NewEncoder(w).Encode(map[string]any{
@@ -126,13 +153,7 @@ func (h *SettingsHandler) updateSettings(w http.ResponseWriter, r *http.Request)
 			"errors":  validation.Errors,
 		})
 		return
-	}
-
-	// Update and save
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
-	defer cancel()
-
-	if err := h.settings.UpdateAndSave(ctx, &newConfig); err != nil {
+	case err != nil:
 		h.logger.Error("failed to update settings", zap.Error(err))
 		http.Error(w, "Failed to update settings: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -159,23 +180,42 @@ func (h *SettingsHandler) handleSettingsReset(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Get defaults
-	defaults := config.Defaults()
-
-	// Preserve env-only fields from current config
-	current := h.settings.GetCurrentConfig()
-	defaults.Discord.BotToken = current.Discord.BotToken
-	defaults.Telegram.BotToken = current.Telegram.BotToken
-	defaults.Gist = current.Gist
-	defaults.ContrarianCache.GistID = current.ContrarianCache.GistID
-	defaults.HedgeTracker.GistID = current.HedgeTracker.GistID
-	defaults.PatternTracker.GistID = current.PatternTracker.GistID
-
 	// Update and save
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	if err := h.settings.UpdateAndSave(ctx, defaults); err != nil {
+	err := h.settings.Update(ctx, func(current *config.Config) error {
+		// Get defaults
+		defaults := config.Defaults()
+
+		// Preserve env-only fields from current config
+		defaults.Discord.BotToken = current.Discord.BotToken
+		defaults.Discord.CommandsEnabled = current.Discord.CommandsEnabled
+		defaults.Discord.GuildID = current.Discord.GuildID
+		defaults.Discord.AllowedUserIDs = current.Discord.AllowedUserIDs
+		defaults.Telegram.BotToken = current.Telegram.BotToken
+		defaults.Telegram.CommandsEnabled = current.Telegram.CommandsEnabled
+		defaults.Telegram.AllowedUserIDs = current.Telegram.AllowedUserIDs
+		defaults.Slack.BotToken = current.Slack.BotToken
+		defaults.Slack.ProdWebhookURL = current.Slack.ProdWebhookURL
+		defaults.Slack.BetaWebhookURL = current.Slack.BetaWebhookURL
+		defaults.Webhook.Endpoints = current.Webhook.Endpoints
+		defaults.Gist = current.Gist
+		defaults.ContrarianCache.GistID = current.ContrarianCache.GistID
+		defaults.HedgeTracker.GistID = current.HedgeTracker.GistID
+		defaults.CopyTracker.GistID = current.CopyTracker.GistID
+		defaults.PatternTracker.GistID = current.PatternTracker.GistID
+		defaults.AlertOutcomes.GistID = current.AlertOutcomes.GistID
+		defaults.DeferredAlerts.GistID = current.DeferredAlerts.GistID
+		defaults.NotifierQueue.DeadLetterGistID = current.NotifierQueue.DeadLetterGistID
+		defaults.Mutes.GistID = current.Mutes.GistID
+		defaults.Feedback.GistID = current.Feedback.GistID
+		defaults.Threads.GistID = current.Threads.GistID
+
+		*current = *defaults
+		return nil
+	})
+	if err != nil {
 		h.logger.Error("failed to reset settings", zap.Error(err))
 		http.Error(w, "Failed to reset settings: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -190,9 +230,47 @@ func (h *SettingsHandler) handleSettingsReset(w http.ResponseWriter, r *http.Req
 	})
 }
 
-// handleSettingsInfo returns metadata about settings state.
+// handleSettingsReload re-loads settings from the gist, discarding changes
+// that couldn't be saved because the settings were changed elsewhere.
+func (h *SettingsHandler) handleSettingsReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Check authentication
+	if !h.requireAuth(w, r) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := h.settings.ReloadSettings(ctx); err != nil {
+		h.logger.Error("failed to reload settings", zap.Error(err))
+		http.Error(w, "Failed to reload settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("settings reloaded from gist via API")
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"success":    true,
+		"applied_at": time.Now(),
+	})
+}
+
+// handleSettingsInfo returns metadata about settings state, with recent
+// conflicting saves across all persisted files.
 func (h *SettingsHandler) handleSettingsInfo(w http.ResponseWriter, _ *http.Request) {
-	info := h.settings.GetSettingsInfo()
+	info := struct {
+		config.SettingsInfo
+		Conflicts []storage.Conflict `json:"conflicts,omitempty"`
+	}{SettingsInfo: h.settings.GetSettingsInfo()}
+	if h.conflicts != nil {
+		info.Conflicts = h.conflicts.Conflicts()
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(info)
@@ -329,7 +407,8 @@ const settingsPageHTML = `<!DOCTYPE html>
         }
         input[type="text"],
         input[type="number"],
-        select {
+        select,
+        textarea {
             width: 100%;
             padding: 8px 12px;
             background: var(--bg-tertiary);
@@ -338,7 +417,13 @@ const settingsPageHTML = `<!DOCTYPE html>
             color: var(--text-primary);
             font-size: 14px;
         }
-        input:focus, select:focus {
+        textarea {
+            min-height: 140px;
+            font-family: monospace;
+            font-size: 13px;
+            resize: vertical;
+        }
+        input:focus, select:focus, textarea:focus {
             outline: none;
             border-color: var(--accent);
         }
@@ -456,8 +541,21 @@ const settingsPageHTML = `<!DOCTYPE html>
             border-radius: 6px;
             font-size: 13px;
         }
+        .conflict-notice {
+            background: var(--bg-secondary);
+            border: 1px solid var(--error);
+            border-radius: 6px;
+            padding: 12px 16px;
+            margin-bottom: 20px;
+            font-size: 13px;
+        }
+        .conflict-notice ul {
+            color: var(--text-secondary);
+            margin: 8px 0 8px 20px;
+        }
         .form-disabled input,
-        .form-disabled select {
+        .form-disabled select,
+        .form-disabled textarea {
             opacity: 0.6;
             cursor: not-allowed;
         }
@@ -616,6 +714,12 @@ const settingsPageHTML = `<!DOCTYPE html>
         Settings are read-only. Log in with a passkey to make changes.
     </div>
 
+    <div class="conflict-notice" id="conflictNotice" style="display: none;">
+        <div id="conflictText"></div>
+        <ul id="conflictList"></ul>
+        <button type="button" class="btn btn-secondary" id="conflictReload" onclick="reloadFromGist()">Reload from Gist</button>
+    </div>
+
     <form id="settingsForm">
         <!-- Trade Monitor Section -->
         <div class="section">
@@ -641,6 +745,13 @@ const settingsPageHTML = `<!DOCTYPE html>
                         <div class="help-text">How often to poll for trades (e.g., 10s, 1m)</div>
                     </div>
                 </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="tm_fill_aggregation_window">Fill Aggregation Window</label>
+                        <input type="text" id="tm_fill_aggregation_window" name="trade_monitor.fill_aggregation_window" placeholder="2s">
+                        <div class="help-text">How long to collect fills of one order before evaluating it (0s = each fill on its own)</div>
+                    </div>
+                </div>
                 <div class="form-row">
                     <div class="form-group">
                         <label for="tm_high_win_rate">High Win Rate Threshold</label>
@@ -689,6 +800,52 @@ const settingsPageHTML = `<!DOCTYPE html>
                         <input type="number" id="tm_massive_max_price" name="trade_monitor.massive_trade_max_price" step="0.01" min="0" max="1">
                     </div>
                 </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="tm_impact_min_notional">Market Impact Min Notional ($)</label>
+                        <input type="number" id="tm_impact_min_notional" name="trade_monitor.impact_min_notional" step="100" min="0">
+                    </div>
+                    <div class="form-group">
+                        <label for="tm_impact_volume_pct">Impact: Share of 24h Volume</label>
+                        <input type="number" id="tm_impact_volume_pct" name="trade_monitor.impact_volume_pct" step="0.01" min="0">
+                        <div class="help-text">0.10 = 10% of the market's 24h volume (0 = off)</div>
+                    </div>
+                </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="tm_impact_depth_pct">Impact: Share of Book Depth</label>
+                        <input type="number" id="tm_impact_depth_pct" name="trade_monitor.impact_depth_pct" step="0.05" min="0">
+                        <div class="help-text">0.50 = half the liquidity on the side taken (0 = off)</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="tm_impact_price_move_pct">Impact: Price Move</label>
+                        <input type="number" id="tm_impact_price_move_pct" name="trade_monitor.impact_price_move_pct" step="0.01" min="0">
+                        <div class="help-text">0.10 = price moved 10% across the trade (0 = off)</div>
+                    </div>
+                </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="tm_cluster_min_wallets">Coordinated Entry Min Wallets</label>
+                        <input type="number" id="tm_cluster_min_wallets" name="trade_monitor.cluster_min_wallets" min="0">
+                        <div class="help-text">Fresh wallets buying the same outcome (0 = off)</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="tm_cluster_max_markets">Coordinated Entry Max Markets</label>
+                        <input type="number" id="tm_cluster_max_markets" name="trade_monitor.cluster_max_markets" min="0">
+                        <div class="help-text">Wallets with this many prior markets or fewer count as fresh</div>
+                    </div>
+                </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="tm_cluster_window">Coordinated Entry Window</label>
+                        <input type="text" id="tm_cluster_window" name="trade_monitor.cluster_window" placeholder="30m">
+                    </div>
+                    <div class="form-group">
+                        <label for="tm_cluster_min_notional">Coordinated Entry Min Notional ($)</label>
+                        <input type="number" id="tm_cluster_min_notional" name="trade_monitor.cluster_min_notional" step="1000" min="0">
+                        <div class="help-text">Combined notional of the wallets' buys</div>
+                    </div>
+                </div>
                 <div class="form-row">
                     <div class="form-group">
                         <label for="tm_new_wallet_max_markets">New Wallet Max Markets</label>
@@ -754,6 +911,237 @@ const settingsPageHTML = `<!DOCTYPE html>
             </div>
         </div>
 
+        <!-- Alert Digest Section -->
+        <div class="section">
+            <div class="section-header" onclick="toggleSection(this)">
+                <span class="section-title">Alert Digest</span>
+                <span class="section-toggle">▼</span>
+            </div>
+            <div class="section-content">
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="digest_window">Window</label>
+                        <input type="text" id="digest_window" name="digest.window" placeholder="1h">
+                        <div class="help-text">One summary per window, sent on the hour/day boundary (e.g., 1h, 24h)</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="digest_top_n">Top N</label>
+                        <input type="number" id="digest_top_n" name="digest.top_n" min="1" max="25">
+                        <div class="help-text">Wallets, markets and trades listed per digest</div>
+                    </div>
+                </div>
+                <div class="form-group">
+                    <label for="digest_instant_reasons">Instant Reasons</label>
+                    <input type="text" id="digest_instant_reasons" name="digest.instant_reasons" placeholder="hedge_removal, resolution_confirmed">
+                    <div class="help-text">Comma-separated alert reasons still sent immediately</div>
+                </div>
+                <div class="form-group">
+                    <label class="checkbox-label">
+                        <input type="checkbox" id="digest_enabled" name="digest.enabled">
+                        <span>Enabled (Discord, Telegram and Slack; webhooks still get every alert)</span>
+                    </label>
+                </div>
+            </div>
+        </div>
+
+        <!-- Alert Cooldown Section -->
+        <div class="section">
+            <div class="section-header" onclick="toggleSection(this)">
+                <span class="section-title">Alert Cooldown</span>
+                <span class="section-toggle">▼</span>
+            </div>
+            <div class="section-content">
+                <div class="form-group">
+                    <label for="suppression_cooldown">Cooldown</label>
+                    <input type="text" id="suppression_cooldown" name="suppression.cooldown" placeholder="10m">
+                    <div class="help-text">Repeat alerts on the same wallet, market and side within this window are merged into one update with running totals</div>
+                </div>
+                <div class="form-group">
+                    <label for="suppression_reason_cooldowns">Reason Cooldowns</label>
+                    <input type="text" id="suppression_reason_cooldowns" name="suppression.reason_cooldowns" placeholder="hedge_removal=0s, rapid_trading=30m">
+                    <div class="help-text">Comma-separated reason=duration overrides; the shortest of an alert's reasons applies and 0s never suppresses</div>
+                </div>
+                <div class="form-group">
+                    <label class="checkbox-label">
+                        <input type="checkbox" id="suppression_enabled" name="suppression.enabled">
+                        <span>Enabled</span>
+                    </label>
+                </div>
+            </div>
+        </div>
+
+        <!-- Alert Routing Section -->
+        <div class="section">
+            <div class="section-header" onclick="toggleSection(this)">
+                <span class="section-title">Alert Routing</span>
+                <span class="section-toggle">▼</span>
+            </div>
+            <div class="section-content">
+                <div class="form-group">
+                    <label for="routing_destinations">Destinations (JSON)</label>
+                    <textarea id="routing_destinations" name="routing.destinations" spellcheck="false" placeholder='[{"name": "whales", "channel": "discord", "target": "123456789"}]'></textarea>
+                    <div class="help-text">Named channels alerts can be routed to. Channel is discord, telegram or slack; target is the channel or chat ID</div>
+                </div>
+                <div class="form-group">
+                    <label for="routing_rules">Rules (JSON)</label>
+                    <textarea id="routing_rules" name="routing.rules" spellcheck="false" placeholder='[{"name": "big-hedges", "reasons": ["hedge_removal"], "min_notional": 10000, "destinations": ["whales"]}]'></textarea>
+                    <div class="help-text">Alerts go to the destinations of every matching rule, or the default channels when no rule matches</div>
+                </div>
+            </div>
+        </div>
+
+        <!-- Alert Threads Section -->
+        <div class="section">
+            <div class="section-header" onclick="toggleSection(this)">
+                <span class="section-title">Alert Threads</span>
+                <span class="section-toggle">▼</span>
+            </div>
+            <div class="section-content">
+                <div class="form-group">
+                    <label for="threads_retention">Retention</label>
+                    <input type="text" id="threads_retention" name="threads.retention" placeholder="168h">
+                    <div class="help-text">Idle time before alerts on a wallet and market start a new thread</div>
+                </div>
+                <div class="form-group">
+                    <label class="checkbox-label">
+                        <input type="checkbox" id="threads_enabled" name="threads.enabled">
+                        <span>Post follow-up alerts as replies (Discord and Telegram)</span>
+                    </label>
+                </div>
+                <div class="form-group">
+                    <label class="checkbox-label">
+                        <input type="checkbox" id="threads_annotate_outcomes" name="threads.annotate_outcomes">
+                        <span>Add the market outcome to the first alert on resolution</span>
+                    </label>
+                </div>
+            </div>
+        </div>
+
+        <!-- Market Anomalies Section -->
+        <div class="section">
+            <div class="section-header" onclick="toggleSection(this)">
+                <span class="section-title">Market Anomalies</span>
+                <span class="section-toggle">▼</span>
+            </div>
+            <div class="section-content">
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="ma_window">Volume Window</label>
+                        <input type="text" id="ma_window" name="market_anomaly.window" placeholder="5m">
+                        <div class="help-text">Trade volume is bucketed into windows of this length</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="ma_baseline_windows">Baseline Windows</label>
+                        <input type="number" id="ma_baseline_windows" name="market_anomaly.baseline_windows" min="2" max="288">
+                        <div class="help-text">Prior windows the baseline is measured over</div>
+                    </div>
+                </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="ma_volume_stddevs">Volume Std Devs</label>
+                        <input type="number" id="ma_volume_stddevs" name="market_anomaly.volume_stddevs" step="0.5" min="0">
+                        <div class="help-text">Standard deviations above the baseline to alert</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="ma_volume_min_notional">Volume Min Notional ($)</label>
+                        <input type="number" id="ma_volume_min_notional" name="market_anomaly.volume_min_notional" step="1000" min="0">
+                    </div>
+                </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="ma_price_move">Price Move</label>
+                        <input type="number" id="ma_price_move" name="market_anomaly.price_move" step="0.01" min="0" max="1">
+                        <div class="help-text">Minimum price change (0.10 = 10¢)</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="ma_price_window">Price Window</label>
+                        <input type="text" id="ma_price_window" name="market_anomaly.price_window" placeholder="15m">
+                    </div>
+                    <div class="form-group">
+                        <label for="ma_price_min_notional">Price Move Min Notional ($)</label>
+                        <input type="number" id="ma_price_min_notional" name="market_anomaly.price_min_notional" step="1000" min="0">
+                    </div>
+                </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="ma_cooldown">Cooldown</label>
+                        <input type="text" id="ma_cooldown" name="market_anomaly.cooldown" placeholder="1h">
+                        <div class="help-text">Minimum time between alerts of one kind per market</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="ma_top_wallets">Top Wallets</label>
+                        <input type="number" id="ma_top_wallets" name="market_anomaly.top_wallets" min="1" max="25">
+                    </div>
+                </div>
+                <div class="form-group">
+                    <label class="checkbox-label">
+                        <input type="checkbox" id="ma_enabled" name="market_anomaly.enabled">
+                        <span>Alert on market volume spikes and sharp price moves</span>
+                    </label>
+                </div>
+            </div>
+        </div>
+
+        <!-- Wallet Clusters Section -->
+        <div class="section">
+            <div class="section-header" onclick="toggleSection(this)">
+                <span class="section-title">Wallet Clusters</span>
+                <span class="section-toggle">▼</span>
+            </div>
+            <div class="section-content">
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="wc_min_notional">Min Notional ($)</label>
+                        <input type="number" id="wc_min_notional" name="wallet_cluster.min_notional" step="100" min="0">
+                        <div class="help-text">Trades below this are not compared</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="wc_evidence_max_age">Evidence Max Age</label>
+                        <input type="text" id="wc_evidence_max_age" name="wallet_cluster.evidence_max_age" placeholder="168h">
+                        <div class="help-text">How long evidence short of a link is kept</div>
+                    </div>
+                </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="wc_co_trade_window">Co-Trade Window</label>
+                        <input type="text" id="wc_co_trade_window" name="wallet_cluster.co_trade_window" placeholder="10s">
+                        <div class="help-text">Same outcome and side within this time</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="wc_co_trade_min_markets">Co-Trade Min Markets</label>
+                        <input type="number" id="wc_co_trade_min_markets" name="wallet_cluster.co_trade_min_markets" min="1">
+                    </div>
+                </div>
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="wc_size_window">Sizing Window</label>
+                        <input type="text" id="wc_size_window" name="wallet_cluster.size_window" placeholder="1h">
+                        <div class="help-text">Identical sizes on one outcome within this time</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="wc_size_tolerance">Size Tolerance</label>
+                        <input type="number" id="wc_size_tolerance" name="wallet_cluster.size_tolerance" step="0.005" min="0" max="0.1">
+                        <div class="help-text">0.01 = within 1%</div>
+                    </div>
+                    <div class="form-group">
+                        <label for="wc_size_min_markets">Sizing Min Markets</label>
+                        <input type="number" id="wc_size_min_markets" name="wallet_cluster.size_min_markets" min="1">
+                    </div>
+                </div>
+                <div class="form-group">
+                    <label for="wc_copy_min_count">Copy Min Count</label>
+                    <input type="number" id="wc_copy_min_count" name="wallet_cluster.copy_min_count" min="0">
+                    <div class="help-text">Copies of one leader before a follower is linked (0 = off)</div>
+                </div>
+                <div class="form-group">
+                    <label class="checkbox-label">
+                        <input type="checkbox" id="wc_enabled" name="wallet_cluster.enabled">
+                        <span>Link wallets that appear to be the same actor</span>
+                    </label>
+                </div>
+            </div>
+        </div>
+
         <!-- Health Server Section -->
         <div class="section">
             <div class="section-header" onclick="toggleSection(this)">
@@ -921,7 +1309,7 @@ const settingsPageHTML = `<!DOCTYPE html>
         function setReadOnly(readonly) {
             isReadOnly = readonly;
             const form = document.getElementById('settingsForm');
-            const inputs = form.querySelectorAll('input, select');
+            const inputs = form.querySelectorAll('input, select, textarea');
             inputs.forEach(input => {
                 input.disabled = readonly;
             });
@@ -1318,6 +1706,8 @@ const settingsPageHTML = `<!DOCTYPE html>
             setValue('tm_min_notional', settings.trade_monitor?.min_notional);
             setValue('tm_obvious_price', settings.trade_monitor?.obvious_price);
             setValue('tm_poll_interval', formatDuration(settings.trade_monitor?.poll_interval || 0));
+            const fillWindow = settings.trade_monitor?.fill_aggregation_window;
+            setValue('tm_fill_aggregation_window', fillWindow ? formatDuration(fillWindow) : '0s');
             setValue('tm_high_win_rate', settings.trade_monitor?.high_win_rate_threshold);
             setValue('tm_min_resolved', settings.trade_monitor?.min_resolved_for_win_rate);
             setValue('tm_win_rate_max_entry', settings.trade_monitor?.win_rate_max_entry_price);
@@ -1327,6 +1717,14 @@ const settingsPageHTML = `<!DOCTYPE html>
             setValue('tm_contrarian_min_notional', settings.trade_monitor?.contrarian_min_notional);
             setValue('tm_massive_min_notional', settings.trade_monitor?.massive_trade_min_notional);
             setValue('tm_massive_max_price', settings.trade_monitor?.massive_trade_max_price);
+            setValue('tm_impact_min_notional', settings.trade_monitor?.impact_min_notional);
+            setValue('tm_impact_volume_pct', settings.trade_monitor?.impact_volume_pct);
+            setValue('tm_impact_depth_pct', settings.trade_monitor?.impact_depth_pct);
+            setValue('tm_impact_price_move_pct', settings.trade_monitor?.impact_price_move_pct);
+            setValue('tm_cluster_min_wallets', settings.trade_monitor?.cluster_min_wallets);
+            setValue('tm_cluster_max_markets', settings.trade_monitor?.cluster_max_markets);
+            setValue('tm_cluster_window', formatDuration(settings.trade_monitor?.cluster_window || 0));
+            setValue('tm_cluster_min_notional', settings.trade_monitor?.cluster_min_notional);
             setValue('tm_new_wallet_max_markets', settings.trade_monitor?.new_wallet_max_markets);
             setValue('tm_new_wallet_min_notional', settings.trade_monitor?.new_wallet_min_notional);
             setChecked('tm_use_websocket', settings.trade_monitor?.use_websocket);
@@ -1340,11 +1738,67 @@ const settingsPageHTML = `<!DOCTYPE html>
             setValue('cache_wallet_ttl', formatDuration(settings.cache?.wallet_cache_ttl || 0));
             setValue('cache_save_interval', formatDuration(settings.cache?.save_interval || 0));
 
+            // Alert Digest
+            setValue('digest_window', formatDuration(settings.digest?.window || 0));
+            setValue('digest_top_n', settings.digest?.top_n);
+            setValue('digest_instant_reasons', (settings.digest?.instant_reasons || []).join(', '));
+            setChecked('digest_enabled', settings.digest?.enabled);
+
+            // Alert Cooldown
+            setValue('suppression_cooldown', formatDuration(settings.suppression?.cooldown || 0));
+            setValue('suppression_reason_cooldowns', (settings.suppression?.reason_cooldowns || [])
+                .map(rc => rc.reason + '=' + (rc.cooldown ? formatDuration(rc.cooldown) : '0s')).join(', '));
+            setChecked('suppression_enabled', settings.suppression?.enabled);
+
+            // Alert Routing
+            setValue('routing_destinations', JSON.stringify(settings.routing?.destinations || [], null, 2));
+            setValue('routing_rules', JSON.stringify(settings.routing?.rules || [], null, 2));
+
+            // Alert Threads
+            setValue('threads_retention', formatDuration(settings.threads?.retention || 0));
+            setChecked('threads_enabled', settings.threads?.enabled);
+            setChecked('threads_annotate_outcomes', settings.threads?.annotate_outcomes);
+
+            // Market Anomalies
+            setValue('ma_window', formatDuration(settings.market_anomaly?.window || 0));
+            setValue('ma_baseline_windows', settings.market_anomaly?.baseline_windows);
+            setValue('ma_volume_stddevs', settings.market_anomaly?.volume_stddevs);
+            setValue('ma_volume_min_notional', settings.market_anomaly?.volume_min_notional);
+            setValue('ma_price_move', settings.market_anomaly?.price_move);
+            setValue('ma_price_window', formatDuration(settings.market_anomaly?.price_window || 0));
+            setValue('ma_price_min_notional', settings.market_anomaly?.price_min_notional);
+            setValue('ma_cooldown', formatDuration(settings.market_anomaly?.cooldown || 0));
+            setValue('ma_top_wallets', settings.market_anomaly?.top_wallets);
+            setChecked('ma_enabled', settings.market_anomaly?.enabled);
+
+            // Wallet Clusters
+            setValue('wc_min_notional', settings.wallet_cluster?.min_notional);
+            setValue('wc_evidence_max_age', formatDuration(settings.wallet_cluster?.evidence_max_age || 0));
+            setValue('wc_co_trade_window', formatDuration(settings.wallet_cluster?.co_trade_window || 0));
+            setValue('wc_co_trade_min_markets', settings.wallet_cluster?.co_trade_min_markets);
+            setValue('wc_size_window', formatDuration(settings.wallet_cluster?.size_window || 0));
+            setValue('wc_size_tolerance', settings.wallet_cluster?.size_tolerance);
+            setValue('wc_size_min_markets', settings.wallet_cluster?.size_min_markets);
+            setValue('wc_copy_min_count', settings.wallet_cluster?.copy_min_count);
+            setChecked('wc_enabled', settings.wallet_cluster?.enabled);
+
             // Health Server
             setValue('health_port', settings.health_server?.port);
             setChecked('health_enabled', settings.health_server?.enabled);
         }
 
+        function parseJSONList(id, label) {
+            const value = document.getElementById(id).value.trim();
+            if (!value) return [];
+            try {
+                const parsed = JSON.parse(value);
+                if (!Array.isArray(parsed)) throw new Error('expected a JSON array');
+                return parsed;
+            } catch (err) {
+                throw new Error(label + ': ' + err.message);
+            }
+        }
+
         function setValue(id, value) {
             const el = document.getElementById(id);
             if (el && value !== undefined) el.value = value;
@@ -1367,6 +1821,44 @@ const settingsPageHTML = `<!DOCTYPE html>
             }
             document.getElementById('lastUpdated').textContent =
                 info.last_updated ? new Date(info.last_updated).toLocaleString() : '-';
+            updateConflicts(info);
+        }
+
+        function updateConflicts(info) {
+            const notice = document.getElementById('conflictNotice');
+            const conflicts = info.conflicts || [];
+            if (!info.last_save_error && conflicts.length === 0) {
+                notice.style.display = 'none';
+                return;
+            }
+            document.getElementById('conflictText').textContent = info.last_save_error
+                ? 'Your last change was applied but not saved: ' + info.last_save_error +
+                  '. Reload to pick up the stored settings, then re-apply your change.'
+                : 'Another instance or a manual edit changed persisted files. Conflicting saves were refused or merged:';
+            document.getElementById('conflictReload').style.display = info.last_save_error ? '' : 'none';
+            const list = document.getElementById('conflictList');
+            list.innerHTML = '';
+            conflicts.forEach(c => {
+                const li = document.createElement('li');
+                li.textContent = new Date(c.at).toLocaleString() + ' - ' + c.filename + ' (' + c.namespace + '): ' + c.resolution;
+                list.appendChild(li);
+            });
+            notice.style.display = 'block';
+        }
+
+        async function reloadFromGist() {
+            if (!confirm('Discard unsaved changes and reload the stored settings?')) return;
+            try {
+                document.body.classList.add('loading');
+                const response = await fetch('/api/settings/reload', { method: 'POST' });
+                if (!response.ok) throw new Error(await response.text());
+                showToast('Settings reloaded from gist');
+                loadSettings();
+            } catch (err) {
+                showToast('Failed to reload: ' + err.message, 'error');
+            } finally {
+                document.body.classList.remove('loading');
+            }
         }
 
         function collectFormData() {
@@ -1375,6 +1867,7 @@ const settingsPageHTML = `<!DOCTYPE html>
                     min_notional: parseFloat(document.getElementById('tm_min_notional').value) || 0,
                     obvious_price: parseFloat(document.getElementById('tm_obvious_price').value) || 0,
                     poll_interval: parseDuration(document.getElementById('tm_poll_interval').value),
+                    fill_aggregation_window: parseDuration(document.getElementById('tm_fill_aggregation_window').value),
                     high_win_rate_threshold: parseFloat(document.getElementById('tm_high_win_rate').value) || 0,
                     min_resolved_for_win_rate: parseInt(document.getElementById('tm_min_resolved').value) || 0,
                     win_rate_max_entry_price: parseFloat(document.getElementById('tm_win_rate_max_entry').value) || 0,
@@ -1384,6 +1877,14 @@ const settingsPageHTML = `<!DOCTYPE html>
                     contrarian_min_notional: parseFloat(document.getElementById('tm_contrarian_min_notional').value) || 0,
                     massive_trade_min_notional: parseFloat(document.getElementById('tm_massive_min_notional').value) || 0,
                     massive_trade_max_price: parseFloat(document.getElementById('tm_massive_max_price').value) || 0,
+                    impact_min_notional: parseFloat(document.getElementById('tm_impact_min_notional').value) || 0,
+                    impact_volume_pct: parseFloat(document.getElementById('tm_impact_volume_pct').value) || 0,
+                    impact_depth_pct: parseFloat(document.getElementById('tm_impact_depth_pct').value) || 0,
+                    impact_price_move_pct: parseFloat(document.getElementById('tm_impact_price_move_pct').value) || 0,
+                    cluster_min_wallets: parseInt(document.getElementById('tm_cluster_min_wallets').value) || 0,
+                    cluster_max_markets: parseInt(document.getElementById('tm_cluster_max_markets').value) || 0,
+                    cluster_window: parseDuration(document.getElementById('tm_cluster_window').value),
+                    cluster_min_notional: parseFloat(document.getElementById('tm_cluster_min_notional').value) || 0,
                     new_wallet_max_markets: parseInt(document.getElementById('tm_new_wallet_max_markets').value) || 0,
                     new_wallet_min_notional: parseFloat(document.getElementById('tm_new_wallet_min_notional').value) || 0,
                     use_websocket: document.getElementById('tm_use_websocket').checked
@@ -1397,6 +1898,55 @@ const settingsPageHTML = `<!DOCTYPE html>
                     wallet_cache_ttl: parseDuration(document.getElementById('cache_wallet_ttl').value),
                     save_interval: parseDuration(document.getElementById('cache_save_interval').value)
                 },
+                digest: {
+                    enabled: document.getElementById('digest_enabled').checked,
+                    window: parseDuration(document.getElementById('digest_window').value),
+                    top_n: parseInt(document.getElementById('digest_top_n').value) || 0,
+                    instant_reasons: document.getElementById('digest_instant_reasons').value
+                        .split(',').map(r => r.trim()).filter(r => r)
+                },
+                suppression: {
+                    enabled: document.getElementById('suppression_enabled').checked,
+                    cooldown: parseDuration(document.getElementById('suppression_cooldown').value),
+                    reason_cooldowns: document.getElementById('suppression_reason_cooldowns').value
+                        .split(',').map(p => p.trim()).filter(p => p.includes('='))
+                        .map(p => {
+                            const [reason, cooldown] = p.split('=');
+                            return { reason: reason.trim(), cooldown: parseDuration(cooldown.trim()) };
+                        })
+                },
+                routing: {
+                    destinations: parseJSONList('routing_destinations', 'Routing destinations'),
+                    rules: parseJSONList('routing_rules', 'Routing rules')
+                },
+                threads: {
+                    enabled: document.getElementById('threads_enabled').checked,
+                    annotate_outcomes: document.getElementById('threads_annotate_outcomes').checked,
+                    retention: parseDuration(document.getElementById('threads_retention').value)
+                },
+                market_anomaly: {
+                    enabled: document.getElementById('ma_enabled').checked,
+                    window: parseDuration(document.getElementById('ma_window').value),
+                    baseline_windows: parseInt(document.getElementById('ma_baseline_windows').value) || 0,
+                    volume_stddevs: parseFloat(document.getElementById('ma_volume_stddevs').value) || 0,
+                    volume_min_notional: parseFloat(document.getElementById('ma_volume_min_notional').value) || 0,
+                    price_move: parseFloat(document.getElementById('ma_price_move').value) || 0,
+                    price_window: parseDuration(document.getElementById('ma_price_window').value),
+                    price_min_notional: parseFloat(document.getElementById('ma_price_min_notional').value) || 0,
+                    cooldown: parseDuration(document.getElementById('ma_cooldown').value),
+                    top_wallets: parseInt(document.getElementById('ma_top_wallets').value) || 0
+                },
+                wallet_cluster: {
+                    enabled: document.getElementById('wc_enabled').checked,
+                    min_notional: parseFloat(document.getElementById('wc_min_notional').value) || 0,
+                    co_trade_window: parseDuration(document.getElementById('wc_co_trade_window').value),
+                    co_trade_min_markets: parseInt(document.getElementById('wc_co_trade_min_markets').value) || 0,
+                    size_window: parseDuration(document.getElementById('wc_size_window').value),
+                    size_tolerance: parseFloat(document.getElementById('wc_size_tolerance').value) || 0,
+                    size_min_markets: parseInt(document.getElementById('wc_size_min_markets').value) || 0,
+                    copy_min_count: parseInt(document.getElementById('wc_copy_min_count').value) || 0,
+                    evidence_max_age: parseDuration(document.getElementById('wc_evidence_max_age').value)
+                },
                 health_server: {
                     port: parseInt(document.getElementById('health_port').value) || 8080,
                     enabled: document.getElementById('health_enabled').checked
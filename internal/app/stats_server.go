@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"polybot/clients/storage"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -27,18 +28,27 @@ func (r *Runner) startHealthServer(port int) {
 	// Register settings routes if settings manager is available
 	if r.settingsManager != nil {
 		settingsHandler := NewSettingsHandler(r.clients.Logger, r.settingsManager, r.authHandler)
+		if reporter, ok := r.clients.Storage.(storage.ConflictReporter); ok {
+			settingsHandler.SetConflictReporter(reporter)
+		}
 		settingsHandler.RegisterRoutes(mux)
 	}
 
 	// Register tasks routes (only if tasks gist is configured)
 	cfg := r.liveConfig.Get()
-	tasksHandler := NewTasksHandler(r.clients.Logger, r.clients.Polymarket, r.authHandler, r.clients.Gist, cfg.Gist.TasksGistID)
+	tasksHandler := NewTasksHandler(r.clients.Logger, r.clients.Polymarket, r.authHandler, r.clients.Storage, cfg.Gist.TasksGistID)
+	if r.tradeMonitor != nil {
+		tasksHandler.SetBacktestConfig(r.tradeMonitor.getConfig, cfg.TradeMonitor.WinRateMaxEntryPrice)
+	}
+	if r.walletClusters != nil {
+		tasksHandler.SetWalletClusters(r.walletClusters)
+	}
 	tasksEnabled := tasksHandler.IsEnabled()
 	r.clients.Logger.Info("tasks feature status",
 		zap.Bool("enabled", tasksEnabled),
 		zap.Bool("hasGistID", cfg.Gist.TasksGistID != ""),
-		zap.Bool("hasGistClient", r.clients.Gist != nil),
-		zap.Bool("gistClientEnabled", r.clients.Gist != nil && r.clients.Gist.IsEnabled()),
+		zap.Bool("hasStorage", r.clients.Storage != nil),
+		zap.Bool("storageEnabled", r.clients.Storage != nil && r.clients.Storage.IsEnabled()),
 	)
 	if tasksEnabled {
 		tasksHandler.RegisterRoutes(mux)
@@ -66,6 +76,26 @@ func (r *Runner) startHealthServer(port int) {
 		json.NewEncoder(w).Encode(stats)
 	})
 
+	// Copy trading graph: top leader/follower pairs, or one wallet's with ?wallet=
+	mux.HandleFunc("/api/copy-graph", func(w http.ResponseWriter, req *http.Request) {
+		if r.copyTracker == nil {
+			http.Error(w, "copy tracker not running", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(lookupCopyGraph(r.copyTracker, req.URL.Query().Get("wallet")))
+	})
+
+	// Alerts the notifier channels failed to deliver, oldest first
+	mux.HandleFunc("/api/dead-letters", func(w http.ResponseWriter, _ *http.Request) {
+		if r.deadLetters == nil {
+			http.Error(w, "dead-letter queue not running", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(r.deadLetters.Entries())
+	})
+
 	// WebSocket endpoint for real-time stats
 	mux.HandleFunc("/ws", func(w http.ResponseWriter, req *http.Request) {
 		conn, err := wsUpgrader.Upgrade(w, req, nil)
@@ -335,6 +365,8 @@ const dashboardHTML = `<!DOCTYPE html>
                     <option value="">All Heuristics</option>
                     <option value="high_win_rate">High Win Rate</option>
                     <option value="massive_trade">Massive Trade</option>
+                    <option value="market_impact">Market Impact</option>
+                    <option value="coordinated_entry">Coordinated Entry</option>
                     <option value="contrarian_winner">Contrarian Winner</option>
                     <option value="rapid_trading">Rapid Trading</option>
                     <option value="new_wallet">New Wallet</option>
@@ -347,6 +379,8 @@ const dashboardHTML = `<!DOCTYPE html>
                     <option value="stealth_accumulation">Stealth Accum</option>
                     <option value="conviction_doubling">Conviction Dbl</option>
                     <option value="asymmetric_exit">Asymmetric Exit</option>
+                    <option value="resolution_confirmed">Hedge Confirmed</option>
+                    <option value="pre_move_positioning">Pre-Move</option>
                 </select>
             </div>
             <div id="recentAlerts">
@@ -374,6 +408,8 @@ const dashboardHTML = `<!DOCTYPE html>
         <div class="alerts-grid">
             <div class="alert-item"><span class="stat-label">High Win Rate</span><br><span id="alertWinRate" class="alert-count green">-</span></div>
             <div class="alert-item"><span class="stat-label">Massive Trade</span><br><span id="alertMassive" class="alert-count yellow">-</span></div>
+            <div class="alert-item"><span class="stat-label">Market Impact</span><br><span id="alertImpact" class="alert-count yellow">-</span></div>
+            <div class="alert-item"><span class="stat-label">Coordinated Entry</span><br><span id="alertCluster" class="alert-count red">-</span></div>
             <div class="alert-item"><span class="stat-label">Rapid Trading</span><br><span id="alertRapid" class="alert-count blue">-</span></div>
             <div class="alert-item"><span class="stat-label">New Wallet</span><br><span id="alertNew" class="alert-count">-</span></div>
             <div class="alert-item"><span class="stat-label">Contrarian Bet</span><br><span id="alertContrarian" class="alert-count">-</span></div>
@@ -386,6 +422,8 @@ const dashboardHTML = `<!DOCTYPE html>
             <div class="alert-item"><span class="stat-label">Stealth Accum</span><br><span id="alertStealth" class="alert-count yellow">-</span></div>
             <div class="alert-item"><span class="stat-label">Conviction Dbl</span><br><span id="alertConviction" class="alert-count">-</span></div>
             <div class="alert-item"><span class="stat-label">Asymmetric Exit</span><br><span id="alertAsym" class="alert-count">-</span></div>
+            <div class="alert-item"><span class="stat-label">Hedge Confirmed</span><br><span id="alertResolution" class="alert-count red">-</span></div>
+            <div class="alert-item"><span class="stat-label">Pre-Move</span><br><span id="alertPreMove" class="alert-count green">-</span></div>
         </div>
     </div>
 
@@ -420,6 +458,59 @@ const dashboardHTML = `<!DOCTYPE html>
         </div>
     </div>
 
+    <div class="card" style="margin-top: 20px;">
+        <h3>🔁 Copy Trading Graph</h3>
+        <div class="watchlist-input">
+            <input type="text" id="copyGraphWallet" class="search-input" placeholder="Wallet address to drill into..." onkeypress="if(event.key==='Enter')loadCopyGraph(this.value)">
+            <button class="watchlist-btn" onclick="loadCopyGraph('')">Top pairs</button>
+        </div>
+        <div id="copyGraph">
+            <div style="color: var(--text-secondary); text-align: center; padding: 20px;">No copy trades yet</div>
+        </div>
+    </div>
+
+    <div class="grid" style="margin-top: 20px;">
+        <div class="card">
+            <h3>🎯 Alert Outcomes by Heuristic</h3>
+            <div class="stat-row" style="font-size: 12px; color: var(--text-secondary); margin-bottom: 8px;">
+                <span>Precision <strong id="outcomePrecision">-</strong></span>
+                <span>Hit rate <strong id="outcomeHitRate">-</strong></span>
+                <span>Copy ROI <strong id="outcomeROI">-</strong></span>
+                <span>Pending <strong id="outcomePending">-</strong></span>
+            </div>
+            <div id="outcomeByReason">
+                <div style="color: var(--text-secondary); text-align: center; padding: 20px;">No resolved alerts yet</div>
+            </div>
+        </div>
+        <div class="card">
+            <h3>🧩 Alert Outcomes by Combination</h3>
+            <div id="outcomeByCombo">
+                <div style="color: var(--text-secondary); text-align: center; padding: 20px;">No resolved alerts yet</div>
+            </div>
+        </div>
+    </div>
+
+    <div class="grid" style="margin-top: 20px;">
+        <div class="card">
+            <h3>👎 Alert Feedback by Heuristic</h3>
+            <div class="stat-row" style="font-size: 12px; color: var(--text-secondary); margin-bottom: 8px;">
+                <span>False positives <strong id="feedbackFalsePositives">-</strong></span>
+                <span>FP rate <strong id="feedbackRate">-</strong></span>
+                <span>Muted <strong id="feedbackMuted">-</strong></span>
+                <span>Alerts <strong id="feedbackAlerts">-</strong></span>
+            </div>
+            <div id="feedbackByReason">
+                <div style="color: var(--text-secondary); text-align: center; padding: 20px;">No feedback yet</div>
+            </div>
+        </div>
+        <div class="card">
+            <h3>🗳️ Recent Feedback</h3>
+            <div id="feedbackRecent">
+                <div style="color: var(--text-secondary); text-align: center; padding: 20px;">No feedback yet</div>
+            </div>
+        </div>
+    </div>
+
     <div class="card" style="margin-top: 20px;">
         <h3>👁️ Wallet Watchlist</h3>
         <div class="watchlist-input">
@@ -480,6 +571,32 @@ const dashboardHTML = `<!DOCTYPE html>
             </div>
         </div>
 
+        <div class="card">
+            <h3>📖 Order Books</h3>
+            <div class="stat-row">
+                <span class="stat-label">Books</span>
+                <span id="bookCount" class="stat-value">-</span>
+            </div>
+            <div class="stat-row">
+                <span class="stat-label">Snapshots / Deltas</span>
+                <span id="bookUpdates" class="stat-value">-</span>
+            </div>
+            <div id="bookQuotes" style="margin-top: 8px;"></div>
+        </div>
+
+        <div class="card">
+            <h3>📈 Market Anomalies</h3>
+            <div class="stat-row">
+                <span class="stat-label">Markets Tracked</span>
+                <span id="anomalyMarkets" class="stat-value">-</span>
+            </div>
+            <div class="stat-row">
+                <span class="stat-label">Volume Spikes / Price Moves</span>
+                <span id="anomalyCounts" class="stat-value">-</span>
+            </div>
+            <div id="anomalyRecent" style="margin-top: 8px;"></div>
+        </div>
+
         <div class="card">
             <h3>📢 Notifications</h3>
             <div class="stat-row" style="cursor: pointer;" onclick="toggleNotifDetails('discord')">
@@ -498,6 +615,23 @@ const dashboardHTML = `<!DOCTYPE html>
                 <div style="font-size: 12px; color: #8b949e;">Chat ID:</div>
                 <div id="telegramChatID" style="font-family: monospace; font-size: 13px; color: #58a6ff;">-</div>
             </div>
+            <div class="stat-row">
+                <span class="stat-label">Slack</span>
+                <span id="slackStatus" class="status-badge disabled">-</span>
+            </div>
+            <div class="stat-row">
+                <span class="stat-label">Webhooks</span>
+                <span id="webhookCount" class="stat-value">-</span>
+            </div>
+            <div class="stat-row">
+                <span class="stat-label">Digest Mode</span>
+                <span id="digestStatus" class="stat-value">-</span>
+            </div>
+            <div id="deliveryStats" style="margin-top: 8px;"></div>
+            <div class="stat-row">
+                <span class="stat-label">Dead Letters</span>
+                <span id="deadLetters" class="stat-value">-</span>
+            </div>
         </div>
 
         <div class="card">
@@ -711,6 +845,41 @@ const dashboardHTML = `<!DOCTYPE html>
                         .join('');
                 }
 
+                // Order books
+                if (s.order_books) {
+                    const ob = s.order_books;
+                    const cents = (p) => (p * 100).toFixed(1) + '¢';
+                    const usd = (v) => '$' + Math.round(v).toLocaleString();
+                    const esc = (v) => String(v).replace(/[&<>"']/g, c => ({ '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' }[c]));
+                    document.getElementById('bookCount').textContent = ob.books;
+                    document.getElementById('bookUpdates').textContent = ob.snapshots.toLocaleString() + ' / ' + ob.deltas.toLocaleString();
+                    document.getElementById('bookQuotes').innerHTML = (ob.quotes || []).map(q => {
+                        const title = esc((q.market_title || q.token_id).substring(0, 40)) + (q.outcome ? ' · ' + esc(q.outcome) : '');
+                        const bid = q.bid_levels > 0 ? cents(q.best_bid) : '-';
+                        const ask = q.ask_levels > 0 ? cents(q.best_ask) : '-';
+                        const spread = q.bid_levels > 0 && q.ask_levels > 0 ? ' (' + cents(q.best_ask - q.best_bid) + ')' : '';
+                        return '<div class="wallet-row">' +
+                            '<span class="stat-label" title="' + usd(q.bid_depth) + ' bid depth / ' + usd(q.ask_depth) + ' ask depth">' + title + '</span>' +
+                            '<span class="wallet-count">' + bid + ' / ' + ask + spread + '</span>' +
+                            '</div>';
+                    }).join('');
+                }
+
+                // Market anomalies
+                if (s.market_anomalies) {
+                    const ma = s.market_anomalies;
+                    const esc = (v) => String(v).replace(/[&<>"']/g, c => ({ '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' }[c]));
+                    document.getElementById('anomalyMarkets').textContent = ma.enabled ? ma.markets : 'Disabled';
+                    document.getElementById('anomalyCounts').textContent = ma.volume_spikes + ' / ' + ma.price_moves;
+                    document.getElementById('anomalyRecent').innerHTML = (ma.recent || []).map(a => {
+                        const icon = a.kind === 'price_move' ? '⚡' : '📈';
+                        return '<div class="wallet-row">' +
+                            '<span class="stat-label" title="' + esc(a.summary) + '">' + icon + ' ' + esc((a.market_title || a.condition_id).substring(0, 40)) + '</span>' +
+                            '<span class="wallet-count">' + new Date(a.timestamp).toLocaleTimeString() + '</span>' +
+                            '</div>';
+                    }).join('');
+                }
+
                 // Notification status
                 const discordEl = document.getElementById('discordStatus');
                 const telegramEl = document.getElementById('telegramStatus');
@@ -731,6 +900,24 @@ const dashboardHTML = `<!DOCTYPE html>
                     telegramEl.className = 'status-badge disabled';
                 }
 
+                const slackEl = document.getElementById('slackStatus');
+                slackEl.textContent = s.notifications.slack_enabled ? '✓ Enabled' : '✗ Disabled';
+                slackEl.className = 'status-badge ' + (s.notifications.slack_enabled ? 'enabled' : 'disabled');
+                document.getElementById('webhookCount').textContent = s.notifications.webhooks || 0;
+                const digest = s.notifications.digest;
+                document.getElementById('digestStatus').textContent = digest && digest.enabled
+                    ? 'Every ' + digest.window + ' · ' + digest.pending + ' pending · ' + digest.digests + ' sent'
+                    : 'Off';
+
+                // Delivery queues
+                document.getElementById('deliveryStats').innerHTML = (s.notifications.delivery || []).map(d =>
+                    '<div class="stat-row" title="' + (d.last_error ? 'Last error: ' + d.last_error.replace(/"/g, '&quot;') : 'No errors') + '">' +
+                    '<span class="stat-label">' + d.channel.charAt(0).toUpperCase() + d.channel.slice(1) + ' delivery</span>' +
+                    '<span class="stat-value">' + d.sent + ' sent · ' + d.queued + ' queued · ' + d.retries + ' retries · ' + d.dead_lettered + ' failed</span>' +
+                    '</div>'
+                ).join('');
+                document.getElementById('deadLetters').textContent = s.notifications.dead_letters;
+
                 // Filters
                 document.getElementById('skipLow').textContent = s.filters.skipped_low_notional.toLocaleString();
                 document.getElementById('skipNoWallet').textContent = s.filters.skipped_no_wallet.toLocaleString();
@@ -755,6 +942,8 @@ const dashboardHTML = `<!DOCTYPE html>
                 document.getElementById('lastAlertAgo').textContent = s.last_alert_ago || 'Never';
                 document.getElementById('alertWinRate').textContent = s.alerts.high_win_rate;
                 document.getElementById('alertMassive').textContent = s.alerts.massive_trade;
+                document.getElementById('alertImpact').textContent = s.alerts.market_impact || 0;
+                document.getElementById('alertCluster').textContent = s.alerts.coordinated_entry || 0;
                 document.getElementById('alertRapid').textContent = s.alerts.rapid_trading;
                 document.getElementById('alertNew').textContent = s.alerts.new_wallet;
                 document.getElementById('alertContrarian').textContent = s.alerts.contrarian_bet;
@@ -767,6 +956,8 @@ const dashboardHTML = `<!DOCTYPE html>
                 document.getElementById('alertStealth').textContent = s.alerts.stealth_accumulation;
                 document.getElementById('alertConviction').textContent = s.alerts.conviction_doubling;
                 document.getElementById('alertAsym').textContent = s.alerts.asymmetric_exit;
+                document.getElementById('alertResolution').textContent = s.alerts.resolution_confirmed;
+                document.getElementById('alertPreMove').textContent = s.alerts.pre_move_positioning;
 
                 // Time-based alert counts
                 document.getElementById('alerts1h').textContent = s.alerts_last_hour || 0;
@@ -827,6 +1018,64 @@ const dashboardHTML = `<!DOCTYPE html>
                     topMarketsEl.innerHTML = '<div style="color: var(--text-secondary); text-align: center; padding: 20px;">No alerts yet</div>';
                 }
 
+                // Alert outcome scoreboard
+                if (s.alert_outcomes) {
+                    const o = s.alert_outcomes;
+                    const pct = (v) => (v * 100).toFixed(1) + '%';
+                    const hasResolved = o.overall.resolved > 0;
+                    document.getElementById('outcomePrecision').textContent = hasResolved ? pct(o.overall.precision) : '-';
+                    document.getElementById('outcomeHitRate').textContent = o.overall.alerts > 0 ? pct(o.overall.hit_rate) : '-';
+                    document.getElementById('outcomeROI').textContent = hasResolved ? pct(o.overall.roi) : '-';
+                    document.getElementById('outcomePending').textContent = o.pending;
+
+                    const renderOutcomes = (rows) => {
+                        const resolvedRows = (rows || []).filter(r => r.resolved > 0).slice(0, 10);
+                        if (resolvedRows.length === 0) {
+                            return '<div style="color: var(--text-secondary); text-align: center; padding: 20px;">No resolved alerts yet</div>';
+                        }
+                        return resolvedRows.map(r => {
+                            const roiColor = r.roi >= 0 ? 'var(--accent-green)' : 'var(--accent-red)';
+                            return '<div class="wallet-row">' +
+                                '<span class="stat-label" title="' + r.wins + ' wins / ' + r.losses + ' losses of ' + r.alerts + ' alerts">' + r.key.replace(/\+/g, ' + ') + '</span>' +
+                                '<span class="wallet-count">' + pct(r.precision) + ' prec · ' + pct(r.hit_rate) + ' hit · ' +
+                                '<span style="color: ' + roiColor + ';">' + pct(r.roi) + ' ROI</span> (' + r.resolved + ')</span>' +
+                                '</div>';
+                        }).join('');
+                    };
+                    document.getElementById('outcomeByReason').innerHTML = renderOutcomes(o.by_reason);
+                    document.getElementById('outcomeByCombo').innerHTML = renderOutcomes(o.by_combo);
+                }
+
+                // Alert feedback from Discord buttons
+                if (s.alert_feedback) {
+                    const f = s.alert_feedback;
+                    const pct = (v) => (v * 100).toFixed(1) + '%';
+                    const esc = (v) => String(v).replace(/[&<>"']/g, c => ({ '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' }[c]));
+                    const emptyFeedback = '<div style="color: var(--text-secondary); text-align: center; padding: 20px;">No feedback yet</div>';
+                    document.getElementById('feedbackFalsePositives').textContent = f.overall.false_positives;
+                    document.getElementById('feedbackRate').textContent = f.overall.alerts > 0 ? pct(f.overall.false_positive_rate) : '-';
+                    document.getElementById('feedbackMuted').textContent = f.overall.muted;
+                    document.getElementById('feedbackAlerts').textContent = f.overall.alerts;
+
+                    const reasonRows = (f.by_reason || []).filter(r => r.false_positives > 0 || r.muted > 0).slice(0, 10);
+                    document.getElementById('feedbackByReason').innerHTML = reasonRows.length === 0 ? emptyFeedback : reasonRows.map(r => {
+                        return '<div class="wallet-row">' +
+                            '<span class="stat-label">' + r.key + '</span>' +
+                            '<span class="wallet-count">' + r.false_positives + ' FP · ' + pct(r.false_positive_rate) + ' · ' + r.muted + ' muted (' + r.alerts + ')</span>' +
+                            '</div>';
+                    }).join('');
+
+                    const verdictLabels = { false_positive: '👎 False positive', muted_wallet: '🔇 Wallet muted', muted_market: '🔇 Market muted' };
+                    const recent = f.recent || [];
+                    document.getElementById('feedbackRecent').innerHTML = recent.length === 0 ? emptyFeedback : recent.map(r => {
+                        const title = esc(r.market_title || r.wallet);
+                        return '<div class="wallet-row">' +
+                            '<span class="stat-label" title="' + esc((r.reasons || []).join(', ')) + '">' + (verdictLabels[r.verdict] || r.verdict) + ' · ' + title + '</span>' +
+                            '<span class="wallet-count">' + esc(r.by || '') + ' · ' + new Date(r.at).toLocaleString() + '</span>' +
+                            '</div>';
+                    }).join('');
+                }
+
                 // Runtime
                 const formatBytes = (bytes) => {
                     if (bytes < 1024) return bytes + ' B';
@@ -950,6 +1199,8 @@ const dashboardHTML = `<!DOCTYPE html>
                 { name: 'High Win', value: alerts.high_win_rate || 0, color: 'var(--accent-green)' },
                 { name: 'Contrarian Win', value: alerts.contrarian_winner || 0, color: 'var(--accent-green)' },
                 { name: 'Massive', value: alerts.massive_trade || 0, color: 'var(--accent-yellow)' },
+                { name: 'Impact', value: alerts.market_impact || 0, color: 'var(--accent-yellow)' },
+                { name: 'Cluster', value: alerts.coordinated_entry || 0, color: 'var(--accent-red)' },
                 { name: 'Extreme', value: alerts.extreme_bet || 0, color: 'var(--accent-red)' },
                 { name: 'Rapid', value: alerts.rapid_trading || 0, color: 'var(--accent-blue)' },
                 { name: 'New Wallet', value: alerts.new_wallet || 0, color: 'var(--accent-purple)' },
@@ -1142,6 +1393,62 @@ const dashboardHTML = `<!DOCTYPE html>
             }).join('');
         }
 
+        // Copy trading graph (top pairs, or one wallet's leaders and followers)
+        let copyGraphWallet = '';
+
+        function copyGraphAddr(addr) {
+            const short = addr.substring(0, 8) + '...' + addr.substring(addr.length - 6);
+            return '<a href="#" class="wallet-addr" style="text-decoration: none;" onclick="loadCopyGraph(\'' + addr + '\'); return false;">' + short + '</a>';
+        }
+
+        function copyGraphLag(seconds) {
+            return seconds < 60 ? Math.round(seconds) + 's' : (seconds / 60).toFixed(1) + 'm';
+        }
+
+        function renderCopyEdges(title, edges) {
+            if (!edges || edges.length === 0) {
+                return '<div style="color: var(--text-secondary); font-size: 13px; padding: 8px 0;">' + title + ': none</div>';
+            }
+            return '<div class="stat-label" style="margin: 8px 0 4px;">' + title + '</div>' + edges.map(e =>
+                '<div class="wallet-row">' +
+                    '<span>' + copyGraphAddr(e.follower) + ' → ' + copyGraphAddr(e.leader) + '</span>' +
+                    '<span class="wallet-count" title="' + (e.markets || []).join(', ') + '">' +
+                        e.matches + ' copies · median ' + copyGraphLag(e.median_lag_s) + ' · ' + (e.markets || []).length + ' markets' +
+                    '</span>' +
+                '</div>'
+            ).join('');
+        }
+
+        function loadCopyGraph(wallet) {
+            copyGraphWallet = wallet.trim();
+            document.getElementById('copyGraphWallet').value = copyGraphWallet;
+            fetch('/api/copy-graph?wallet=' + encodeURIComponent(copyGraphWallet))
+                .then(r => r.ok ? r.json() : Promise.reject(r.statusText))
+                .then(g => {
+                    const el = document.getElementById('copyGraph');
+                    if (g.wallet) {
+                        el.innerHTML = renderCopyEdges('Leaders copied by ' + copyGraphAddr(g.wallet), g.leaders) +
+                            renderCopyEdges('Followers of ' + copyGraphAddr(g.wallet), g.followers);
+                        return;
+                    }
+                    if (!g.top_pairs || g.top_pairs.length === 0) {
+                        el.innerHTML = '<div style="color: var(--text-secondary); text-align: center; padding: 20px;">No copy trades yet</div>';
+                        return;
+                    }
+                    const copiers = (g.top_copiers || []).slice(0, 5).map(c =>
+                        '<div class="wallet-row">' + copyGraphAddr(c.address) +
+                        '<span class="wallet-count">' + c.copy_count + ' copies of ' + c.leaders + ' leaders</span></div>'
+                    ).join('');
+                    el.innerHTML = '<div class="stat-label" style="margin-bottom: 4px;">Top copiers</div>' + copiers +
+                        renderCopyEdges('Top pairs', g.top_pairs);
+                })
+                .catch(err => console.error('Failed to fetch copy graph:', err));
+        }
+
+        // Refresh the top pairs unless drilled into a wallet
+        loadCopyGraph('');
+        setInterval(() => { if (!copyGraphWallet) loadCopyGraph(''); }, 60000);
+
         // Initialize watchlist
         renderWatchlist();
 
@@ -0,0 +1,437 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"polybot/clients/polymarketapi"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// BacktestRequest is the request for the backtest task.
+type BacktestRequest struct {
+	Markets      []MarketSelection `json:"markets"`      // Markets to test (if empty, recently closed markets are used)
+	Query        string            `json:"query"`        // Search filter for closed markets
+	LookbackDays int               `json:"lookbackDays"` // Only use markets closed in the last N days (default 30)
+	MaxMarkets   int               `json:"maxMarkets"`   // Maximum markets to test (default 10)
+	MinNotional  float64           `json:"minNotional"`  // Override the monitor's minimum notional (0 = use monitor config)
+}
+
+// BacktestReasonStats summarizes how a single alert reason performed.
+type BacktestReasonStats struct {
+	Reason      string  `json:"reason"`
+	Alerts      int     `json:"alerts"`      // Alerts that included this reason
+	Winning     int     `json:"winning"`     // Alerts that backed the winning outcome
+	WinningRate float64 `json:"winningRate"` // Winning / Alerts
+}
+
+// BacktestAlert is a single alert that would have fired.
+type BacktestAlert struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Wallet         string    `json:"wallet"`
+	ConditionID    string    `json:"conditionId"`
+	Title          string    `json:"title"`
+	Outcome        string    `json:"outcome"`
+	WinningOutcome string    `json:"winningOutcome"`
+	Side           string    `json:"side"`
+	Price          float64   `json:"price"`
+	Notional       float64   `json:"notional"`
+	Reasons        []string  `json:"reasons"`
+	BackedWinner   bool      `json:"backedWinner"`
+}
+
+// BacktestResult is the result of the backtest task.
+type BacktestResult struct {
+	Status           string                `json:"status"`
+	MarketsProcessed int                   `json:"marketsProcessed"`
+	TradesProcessed  int                   `json:"tradesProcessed"`
+	TradesEvaluated  int                   `json:"tradesEvaluated"` // Trades above the minimum notional
+	WalletsAnalyzed  int                   `json:"walletsAnalyzed"`
+	TotalAlerts      int                   `json:"totalAlerts"`
+	WinningAlerts    int                   `json:"winningAlerts"`
+	Reasons          []BacktestReasonStats `json:"reasons"`
+	Alerts           []BacktestAlert       `json:"alerts"`
+	DurationMs       int64                 `json:"durationMs"`
+	Errors           []string              `json:"errors,omitempty"`
+}
+
+// backtestMarket is a resolved market included in a backtest.
+type backtestMarket struct {
+	conditionID    string
+	title          string
+	winningOutcome string
+}
+
+// walletHistory holds a wallet's full history, filtered per trade to avoid look-ahead.
+type walletHistory struct {
+	activity  []polymarketapi.Activity
+	positions []polymarketapi.ClosedPosition
+	truncated bool // Activity hit the API limit, so early history may be missing
+}
+
+// BacktestTask replays historical trades from resolved markets through the
+// same heuristics as the live trade monitor.
+//
+// Detectors that depend on live tracker state (contrarian winner, copy trading,
+// hedges and patterns) have no history to draw from and do not fire.
+type BacktestTask struct {
+	polymarket           *polymarketapi.PolymarketApiClient
+	logger               *zap.Logger
+	config               TradeMonitorConfig
+	winRateMaxEntryPrice float64
+}
+
+// NewBacktestTask creates a new task instance using the given monitor config.
+// winRateMaxEntryPrice matches the wallet tracker's suspicious win rate threshold.
+func NewBacktestTask(
+	polymarket *polymarketapi.PolymarketApiClient,
+	logger *zap.Logger,
+	config TradeMonitorConfig,
+	winRateMaxEntryPrice float64,
+) *BacktestTask {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if winRateMaxEntryPrice <= 0 {
+		winRateMaxEntryPrice = 0.70 // Same default as the wallet tracker
+	}
+	return &BacktestTask{
+		polymarket:           polymarket,
+		logger:               logger,
+		config:               config,
+		winRateMaxEntryPrice: winRateMaxEntryPrice,
+	}
+}
+
+// Execute runs the backtest.
+func (t *BacktestTask) Execute(
+	ctx context.Context,
+	req BacktestRequest,
+) (*BacktestResult, error) {
+	startTime := time.Now()
+
+	if req.LookbackDays <= 0 {
+		req.LookbackDays = 30
+	}
+	if req.MaxMarkets <= 0 {
+		req.MaxMarkets = 10
+	}
+
+	cfg := t.config
+	if req.MinNotional > 0 {
+		cfg.MinNotional = req.MinNotional
+	}
+
+	result := &BacktestResult{
+		Status:  "running",
+		Reasons: []BacktestReasonStats{},
+		Alerts:  []BacktestAlert{},
+		Errors:  []string{},
+	}
+
+	markets := t.resolveMarkets(ctx, req, result)
+	if len(markets) == 0 {
+		result.Errors = append(result.Errors, "No resolved markets to backtest")
+		result.Status = "completed"
+		result.DurationMs = time.Since(startTime).Milliseconds()
+		return result, nil
+	}
+
+	// Collect trades from every market, keeping only those large enough to alert on
+	marketByID := make(map[string]backtestMarket)
+	var trades []polymarketapi.Trade
+	for _, m := range markets {
+		if ctx.Err() != nil {
+			result.Status = "cancelled"
+			result.DurationMs = time.Since(startTime).Milliseconds()
+			return result, ctx.Err()
+		}
+
+		marketTrades, err := t.fetchMarketTrades(ctx, m.conditionID)
+		if err != nil {
+			result.Errors = append(result.Errors, "Failed to fetch some trades for "+m.title+": "+err.Error())
+		}
+		result.MarketsProcessed++
+		result.TradesProcessed += len(marketTrades)
+		marketByID[m.conditionID] = m
+
+		for _, trade := range marketTrades {
+			if trade.ConditionID == "" {
+				trade.ConditionID = m.conditionID
+			}
+			if trade.Size*trade.Price >= cfg.MinNotional {
+				trades = append(trades, trade)
+			}
+		}
+	}
+
+	// Replay in time order so rapid trading windows behave as they did live
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Timestamp < trades[j].Timestamp
+	})
+	result.TradesEvaluated = len(trades)
+
+	// A fresh monitor with no trackers or notifier, driven by the replay clock
+	var replayNow time.Time
+	monitor := NewTradeMonitor(t.logger, nil, nil, nil, nil, nil, cfg)
+	monitor.clock = func() time.Time { return replayNow }
+
+	histories := make(map[string]*walletHistory)
+	reasonStats := make(map[AlertReason]*BacktestReasonStats)
+	seen := make(map[string]struct{})
+	truncatedWallets := 0
+
+	for _, trade := range trades {
+		if ctx.Err() != nil {
+			result.Status = "cancelled"
+			result.DurationMs = time.Since(startTime).Milliseconds()
+			return result, ctx.Err()
+		}
+
+		normalized := monitor.normalizeTrade(trade)
+		if normalized.Wallet == "" {
+			continue
+		}
+		if _, ok := seen[normalized.Key]; ok {
+			continue
+		}
+		seen[normalized.Key] = struct{}{}
+
+		history, ok := histories[normalized.Wallet]
+		if !ok {
+			history = t.fetchWalletHistory(ctx, normalized.Wallet)
+			histories[normalized.Wallet] = history
+			if history.truncated {
+				truncatedWallets++
+			}
+		}
+
+		replayNow = normalized.Timestamp
+		stats := history.statsAt(normalized.Wallet, trade.Timestamp, t.winRateMaxEntryPrice)
+
+		reasons, _, verdict := monitor.evaluateTrade(ctx, normalized, stats, cfg)
+		if verdict != verdictAlert {
+			continue
+		}
+
+		market := marketByID[trade.ConditionID]
+		backedWinner := backsWinner(normalized, market.winningOutcome)
+
+		alert := BacktestAlert{
+			Timestamp:      normalized.Timestamp,
+			Wallet:         normalized.Wallet,
+			ConditionID:    trade.ConditionID,
+			Title:          market.title,
+			Outcome:        normalized.Outcome,
+			WinningOutcome: market.winningOutcome,
+			Side:           normalized.Side,
+			Price:          normalized.Price,
+			Notional:       normalized.Notional,
+			BackedWinner:   backedWinner,
+		}
+		for _, reason := range reasons {
+			alert.Reasons = append(alert.Reasons, string(reason))
+
+			rs, ok := reasonStats[reason]
+			if !ok {
+				rs = &BacktestReasonStats{Reason: string(reason)}
+				reasonStats[reason] = rs
+			}
+			rs.Alerts++
+			if backedWinner {
+				rs.Winning++
+			}
+		}
+
+		result.Alerts = append(result.Alerts, alert)
+		result.TotalAlerts++
+		if backedWinner {
+			result.WinningAlerts++
+		}
+	}
+
+	for _, rs := range reasonStats {
+		if rs.Alerts > 0 {
+			rs.WinningRate = float64(rs.Winning) / float64(rs.Alerts)
+		}
+		result.Reasons = append(result.Reasons, *rs)
+	}
+	sort.Slice(result.Reasons, func(i, j int) bool {
+		if result.Reasons[i].Alerts != result.Reasons[j].Alerts {
+			return result.Reasons[i].Alerts > result.Reasons[j].Alerts
+		}
+		return result.Reasons[i].Reason < result.Reasons[j].Reason
+	})
+
+	if truncatedWallets > 0 {
+		result.Errors = append(result.Errors,
+			fmt.Sprintf("%d wallets hit the 500 activity limit; their early history may be incomplete", truncatedWallets))
+	}
+
+	result.WalletsAnalyzed = len(histories)
+	result.Status = "completed"
+	result.DurationMs = time.Since(startTime).Milliseconds()
+
+	t.logger.Info("backtest completed",
+		zap.Int("markets", result.MarketsProcessed),
+		zap.Int("tradesEvaluated", result.TradesEvaluated),
+		zap.Int("alerts", result.TotalAlerts),
+		zap.Int("winningAlerts", result.WinningAlerts),
+		zap.Int64("durationMs", result.DurationMs),
+	)
+
+	return result, nil
+}
+
+// resolveMarkets returns the resolved markets to test, with their winning outcomes.
+func (t *BacktestTask) resolveMarkets(ctx context.Context, req BacktestRequest, result *BacktestResult) []backtestMarket {
+	var markets []backtestMarket
+
+	if len(req.Markets) > 0 {
+		for _, sel := range req.Markets {
+			m := backtestMarket{
+				conditionID:    sel.ConditionID,
+				title:          sel.Title,
+				winningOutcome: sel.WinningOutcome,
+			}
+			if m.winningOutcome == "" {
+				market, err := t.polymarket.GetMarketByConditionID(ctx, sel.ConditionID)
+				if err != nil {
+					result.Errors = append(result.Errors, "Failed to fetch market "+sel.ConditionID+": "+err.Error())
+					continue
+				}
+				m.winningOutcome, _ = market.GetWinningOutcome()
+				if m.title == "" {
+					m.title = market.Question
+				}
+			}
+			if m.winningOutcome == "" {
+				result.Errors = append(result.Errors, "Market has not resolved: "+m.conditionID)
+				continue
+			}
+			markets = append(markets, m)
+			if len(markets) >= req.MaxMarkets {
+				break
+			}
+		}
+		return markets
+	}
+
+	closedAfter := time.Now().AddDate(0, 0, -req.LookbackDays)
+	closed, err := t.polymarket.GetClosedMarketsWithOptions(ctx, req.MaxMarkets, 0, polymarketapi.MarketSearchOptions{
+		Query:       req.Query,
+		ClosedAfter: &closedAfter,
+	})
+	if err != nil {
+		t.logger.Warn("failed to fetch closed markets", zap.Error(err))
+		result.Errors = append(result.Errors, "Failed to fetch closed markets: "+err.Error())
+		return nil
+	}
+
+	for i := range closed {
+		winner, _ := closed[i].GetWinningOutcome()
+		if winner == "" {
+			continue
+		}
+		markets = append(markets, backtestMarket{
+			conditionID:    closed[i].ConditionID,
+			title:          closed[i].Question,
+			winningOutcome: winner,
+		})
+		if len(markets) >= req.MaxMarkets {
+			break
+		}
+	}
+	return markets
+}
+
+// fetchMarketTrades fetches all trades for a market with pagination.
+func (t *BacktestTask) fetchMarketTrades(ctx context.Context, conditionID string) ([]polymarketapi.Trade, error) {
+	var all []polymarketapi.Trade
+	cursor := ""
+	maxIterations := 50 // Safety limit
+
+	for i := 0; i < maxIterations; i++ {
+		trades, err := t.polymarket.GetMarketTrades(ctx, conditionID, 1000, cursor)
+		if err != nil {
+			t.logger.Warn("failed to fetch trades page",
+				zap.String("conditionId", conditionID),
+				zap.String("cursor", cursor),
+				zap.Error(err),
+			)
+			return all, err
+		}
+		all = append(all, trades...)
+
+		if len(trades) < 1000 {
+			break
+		}
+		cursor = trades[len(trades)-1].ID
+	}
+
+	return all, nil
+}
+
+// fetchWalletHistory fetches the same data the wallet tracker uses for live stats.
+func (t *BacktestTask) fetchWalletHistory(ctx context.Context, wallet string) *walletHistory {
+	history := &walletHistory{}
+
+	activity, err := t.polymarket.GetUserActivity(ctx, wallet, 500)
+	if err != nil {
+		t.logger.Debug("failed to fetch wallet activity",
+			zap.String("wallet", shortID(wallet)),
+			zap.Error(err),
+		)
+	}
+	history.activity = activity
+	history.truncated = len(activity) >= 500
+
+	for offset := 0; offset < 100; offset += 50 {
+		positions, err := t.polymarket.GetClosedPositions(ctx, wallet, 50, offset)
+		if err != nil {
+			t.logger.Debug("failed to fetch closed positions",
+				zap.String("wallet", shortID(wallet)),
+				zap.Error(err),
+			)
+			break
+		}
+		history.positions = append(history.positions, positions...)
+		if len(positions) < 50 {
+			break
+		}
+	}
+
+	return history
+}
+
+// statsAt computes wallet stats using only activity and positions closed before ts.
+func (h *walletHistory) statsAt(wallet string, ts int64, maxEntryPrice float64) *WalletStats {
+	var activity []polymarketapi.Activity
+	for _, a := range h.activity {
+		if a.Timestamp < ts {
+			activity = append(activity, a)
+		}
+	}
+
+	var positions []polymarketapi.ClosedPosition
+	for _, p := range h.positions {
+		if p.Timestamp < ts {
+			positions = append(positions, p)
+		}
+	}
+
+	return computeWalletStats(wallet, activity, positions, maxEntryPrice)
+}
+
+// backsWinner reports whether a trade backed the winning outcome:
+// buying the winner or selling a loser.
+func backsWinner(trade *NormalizedTrade, winningOutcome string) bool {
+	isWinner := strings.EqualFold(trade.Outcome, winningOutcome)
+	if trade.IsBuy() {
+		return isWinner
+	}
+	return !isWinner
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"polybot/clients/polymarketapi"
+	"polybot/config"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func newTestBacktestTask(t *testing.T, handler http.HandlerFunc) (*BacktestTask, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	cfg := &config.Config{
+		Polymarket: config.PolymarketConfig{
+			DataAPIURL:  server.URL,
+			GammaAPIURL: server.URL,
+		},
+	}
+	apiClient := polymarketapi.NewPolymarketApiClient(zap.NewNop(), cfg)
+	return NewBacktestTask(apiClient, zap.NewNop(), DefaultTradeMonitorConfig(), 0.70), server
+}
+
+func TestBacktestTask_Execute(t *testing.T) {
+	const tradeTime = int64(1700000000)
+
+	task, server := newTestBacktestTask(t, func(w http.ResponseWriter, r *http.Request) {
+		user := r.URL.Query().Get("user")
+		switch r.URL.Path {
+		case "/trades":
+			json.NewEncoder(w).Encode([]polymarketapi.Trade{
+				// New wallet backing the winner at long odds
+				{ID: "1", TransactionHash: "0x1", Asset: "yes", ProxyWallet: "0xnew", Side: "BUY", Outcome: "Yes", Size: 150000, Price: 0.08, Timestamp: tradeTime},
+				// New wallet backing the loser at long odds
+				{ID: "2", TransactionHash: "0x2", Asset: "no", ProxyWallet: "0xloser", Side: "BUY", Outcome: "No", Size: 150000, Price: 0.08, Timestamp: tradeTime + 60},
+				// Experienced wallet whose wins all resolved after the trade
+				{ID: "3", TransactionHash: "0x3", Asset: "no", ProxyWallet: "0xfuture", Side: "BUY", Outcome: "No", Size: 10000, Price: 0.5, Timestamp: tradeTime + 120},
+				// Below the minimum notional
+				{ID: "4", TransactionHash: "0x4", Asset: "yes", ProxyWallet: "0xsmall", Side: "BUY", Outcome: "Yes", Size: 10, Price: 0.5, Timestamp: tradeTime},
+			})
+		case "/activity":
+			var activity []polymarketapi.Activity
+			if user == "0xfuture" {
+				for i := 0; i < 50; i++ {
+					activity = append(activity, polymarketapi.Activity{ConditionID: string(rune('a' + i)), Timestamp: tradeTime - 1000})
+				}
+			}
+			json.NewEncoder(w).Encode(activity)
+		case "/closed-positions":
+			var positions []polymarketapi.ClosedPosition
+			if user == "0xfuture" {
+				for i := 0; i < 10; i++ {
+					positions = append(positions, polymarketapi.ClosedPosition{AvgPrice: 0.4, RealizedPnl: 100, Timestamp: tradeTime + 1000})
+				}
+			}
+			json.NewEncoder(w).Encode(positions)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	result, err := task.Execute(context.Background(), BacktestRequest{
+		Markets: []MarketSelection{{ConditionID: "cond1", Title: "Test Market", WinningOutcome: "Yes"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != "completed" {
+		t.Errorf("expected completed status, got %s", result.Status)
+	}
+	if result.TradesProcessed != 4 || result.TradesEvaluated != 3 {
+		t.Errorf("expected 4 processed and 3 evaluated trades, got %d and %d", result.TradesProcessed, result.TradesEvaluated)
+	}
+	if result.TotalAlerts != 2 || result.WinningAlerts != 1 {
+		t.Fatalf("expected 2 alerts with 1 winner, got %d with %d", result.TotalAlerts, result.WinningAlerts)
+	}
+	for _, alert := range result.Alerts {
+		if alert.Wallet == "0xfuture" {
+			t.Error("expected no alert from wins that resolved after the trade")
+		}
+		if alert.ConditionID != "cond1" || alert.Title != "Test Market" {
+			t.Errorf("expected market details on alert, got %s/%s", alert.ConditionID, alert.Title)
+		}
+	}
+
+	var newWallet *BacktestReasonStats
+	for i := range result.Reasons {
+		if result.Reasons[i].Reason == string(AlertReasonNewWallet) {
+			newWallet = &result.Reasons[i]
+		}
+	}
+	if newWallet == nil {
+		t.Fatalf("expected new wallet reason stats, got %+v", result.Reasons)
+	}
+	if newWallet.Alerts != 2 || newWallet.Winning != 1 || newWallet.WinningRate != 0.5 {
+		t.Errorf("unexpected new wallet stats: %+v", *newWallet)
+	}
+}
+
+func TestBacktestTask_NoMarkets(t *testing.T) {
+	task, server := newTestBacktestTask(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]any{})
+	})
+	defer server.Close()
+
+	result, err := task.Execute(context.Background(), BacktestRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TotalAlerts != 0 || len(result.Errors) == 0 {
+		t.Errorf("expected no alerts and an explanatory error, got %d alerts, errors %v", result.TotalAlerts, result.Errors)
+	}
+}
+
+func TestWalletHistory_StatsAt(t *testing.T) {
+	history := &walletHistory{
+		activity: []polymarketapi.Activity{
+			{ConditionID: "a", Timestamp: 100},
+			{ConditionID: "b", Timestamp: 300},
+		},
+		positions: []polymarketapi.ClosedPosition{
+			{RealizedPnl: 10, AvgPrice: 0.5, Timestamp: 150},
+			{RealizedPnl: -10, AvgPrice: 0.5, Timestamp: 250},
+		},
+	}
+
+	stats := history.statsAt("0xabc", 200, 0.70)
+	if stats.UniqueMarkets != 1 {
+		t.Errorf("expected 1 market before ts, got %d", stats.UniqueMarkets)
+	}
+	if stats.WinCount != 1 || stats.LossCount != 0 {
+		t.Errorf("expected 1 win and 0 losses before ts, got %d/%d", stats.WinCount, stats.LossCount)
+	}
+}
+
+func TestBacksWinner(t *testing.T) {
+	tests := []struct {
+		side     string
+		outcome  string
+		expected bool
+	}{
+		{"BUY", "Yes", true},
+		{"BUY", "No", false},
+		{"SELL", "No", true},
+		{"SELL", "Yes", false},
+	}
+
+	for _, tt := range tests {
+		trade := &NormalizedTrade{Side: tt.side, Outcome: tt.outcome}
+		if got := backsWinner(trade, "Yes"); got != tt.expected {
+			t.Errorf("%s %s: expected %v, got %v", tt.side, tt.outcome, tt.expected, got)
+		}
+	}
+}
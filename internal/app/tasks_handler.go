@@ -16,8 +16,15 @@ type TasksHandler struct {
 	logger      *zap.Logger
 	polymarket  *polymarketapi.PolymarketApiClient
 	authHandler *AuthHandler
-	gist        *gist.Client
+	gist        gist.Storage
 	tasksGistID string
+
+	// Backtest settings, mirroring the live trade monitor
+	monitorConfig        func() TradeMonitorConfig
+	winRateMaxEntryPrice float64
+
+	// Live wallet clusters (may be nil)
+	walletClusters *WalletClusters
 }
 
 // NewTasksHandler creates a new TasksHandler.
@@ -25,7 +32,7 @@ func NewTasksHandler(
 	logger *zap.Logger,
 	polymarket *polymarketapi.PolymarketApiClient,
 	authHandler *AuthHandler,
-	gistClient *gist.Client,
+	gistClient gist.Storage,
 	tasksGistID string,
 ) *TasksHandler {
 	if logger == nil {
@@ -40,6 +47,17 @@ func NewTasksHandler(
 	}
 }
 
+// SetBacktestConfig sets the trade monitor config used by backtests.
+func (h *TasksHandler) SetBacktestConfig(monitorConfig func() TradeMonitorConfig, winRateMaxEntryPrice float64) {
+	h.monitorConfig = monitorConfig
+	h.winRateMaxEntryPrice = winRateMaxEntryPrice
+}
+
+// SetWalletClusters sets the clustering subsystem shown by the wallet cluster view.
+func (h *TasksHandler) SetWalletClusters(clusters *WalletClusters) {
+	h.walletClusters = clusters
+}
+
 // IsEnabled returns true if the tasks feature is enabled (gist configured).
 func (h *TasksHandler) IsEnabled() bool {
 	return h.tasksGistID != "" && h.gist != nil && h.gist.IsEnabled()
@@ -57,6 +75,8 @@ type SavedTask struct {
 	Result               *MultiMarketWinnersResult `json:"result,omitempty"`
 	WalletActivityResult *WalletActivityResult     `json:"walletActivityResult,omitempty"`
 	MarketHoldersResult  *MarketHoldersResult      `json:"marketHoldersResult,omitempty"`
+	BacktestResult       *BacktestResult           `json:"backtestResult,omitempty"`
+	WalletClustersResult *WalletClustersResult     `json:"walletClustersResult,omitempty"`
 	Error                string                    `json:"error,omitempty"`
 }
 
@@ -75,6 +95,8 @@ func (h *TasksHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/tasks/history", h.handleTasksHistory)
 	mux.HandleFunc("/api/tasks/wallet-activity", h.handleWalletActivity)
 	mux.HandleFunc("/api/tasks/market-holders", h.handleMarketHolders)
+	mux.HandleFunc("/api/tasks/backtest", h.handleBacktest)
+	mux.HandleFunc("/api/tasks/wallet-clusters", h.handleWalletClusters)
 }
 
 // requireAuth checks if the request is authenticated (when auth is configured).
@@ -509,6 +531,79 @@ func (h *TasksHandler) handleMarketHolders(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(result)
 }
 
+// handleBacktest replays resolved markets through the alert heuristics.
+func (h *TasksHandler) handleBacktest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.requireAuth(w, r) {
+		return
+	}
+
+	var req BacktestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	cfg := DefaultTradeMonitorConfig()
+	if h.monitorConfig != nil {
+		cfg = h.monitorConfig()
+	}
+
+	// Create task and execute
+	task := NewBacktestTask(h.polymarket, h.logger, cfg, h.winRateMaxEntryPrice)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+
+	result, err := task.Execute(ctx, req)
+	if err != nil {
+		h.logger.Error("backtest task execution failed", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Task execution failed: " + err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleWalletClusters shows the live wallet clusters matching a wallet or cluster ID.
+func (h *TasksHandler) handleWalletClusters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.requireAuth(w, r) {
+		return
+	}
+
+	var req WalletClustersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if h.walletClusters == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Wallet clustering is not running"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lookupWalletClusters(h.walletClusters, req))
+}
+
 const tasksPageHTML = `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -1505,6 +1600,12 @@ const tasksPageHTML = `<!DOCTYPE html>
                 <div class="task-item" data-task="market-holders" onclick="switchTask('market-holders')">
                     Market Holders
                 </div>
+                <div class="task-item" data-task="backtest" onclick="switchTask('backtest')">
+                    Alert Backtest
+                </div>
+                <div class="task-item" data-task="wallet-clusters" onclick="switchTask('wallet-clusters')">
+                    Wallet Clusters
+                </div>
             </div>
             <div class="finished-tasks-section" id="finishedTasksSection">
                 <h2>Finished Tasks</h2>
@@ -1660,6 +1761,76 @@ const tasksPageHTML = `<!DOCTYPE html>
                     </button>
                 </div>
             </div>
+
+            <!-- Alert Backtest Task -->
+            <div id="backtest" class="task-panel" style="display: none;">
+                <div class="task-header">
+                    <h2>Alert Backtest</h2>
+                    <p>Replay trades from resolved markets through the alert heuristics</p>
+                </div>
+
+                <div class="section">
+                    <h3>Markets</h3>
+                    <p style="color: var(--text-secondary); font-size: 13px; margin-bottom: 12px;">
+                        Recently resolved markets are replayed in time order using the current monitor settings.
+                        Wallet stats only use history from before each trade. Contrarian winner, copy trading,
+                        hedge and pattern alerts depend on live state and are not evaluated.
+                    </p>
+                    <div class="task-options">
+                        <label>
+                            Search (optional):
+                            <input type="text" id="backtestQuery" placeholder="e.g. election" autocomplete="off">
+                        </label>
+                        <label>
+                            Closed in last (days):
+                            <input type="number" id="backtestLookbackDays" value="30" min="1" max="365">
+                        </label>
+                        <label>
+                            Max markets:
+                            <input type="number" id="backtestMaxMarkets" value="10" min="1" max="50">
+                        </label>
+                        <label>
+                            Min notional ($, 0 = monitor setting):
+                            <input type="number" id="backtestMinNotional" value="0" min="0">
+                        </label>
+                    </div>
+                </div>
+
+                <div class="section">
+                    <button class="btn btn-primary" id="runBacktestTaskBtn" onclick="runBacktestTask()">
+                        Run Backtest
+                    </button>
+                </div>
+            </div>
+
+            <!-- Wallet Clusters Task -->
+            <div id="wallet-clusters" class="task-panel" style="display: none;">
+                <div class="task-header">
+                    <h2>Wallet Clusters</h2>
+                    <p>Show wallets the live monitor has linked as the same actor</p>
+                </div>
+
+                <div class="section">
+                    <h3>Show Cluster</h3>
+                    <p style="color: var(--text-secondary); font-size: 13px; margin-bottom: 12px;">
+                        Wallets are linked when they trade the same markets within seconds of each other, trade
+                        identical sizes, or one keeps copying the other. Links are built from trades seen since
+                        the last restart. Leave empty to list the largest clusters.
+                    </p>
+                    <div class="task-options">
+                        <label>
+                            Wallet address or cluster ID:
+                            <input type="text" id="walletClustersQuery" placeholder="0x... or C-1a2b3c4d" autocomplete="off">
+                        </label>
+                    </div>
+                </div>
+
+                <div class="section">
+                    <button class="btn btn-primary" id="runWalletClustersTaskBtn" onclick="runWalletClustersTask()">
+                        Show Cluster
+                    </button>
+                </div>
+            </div>
         </div>
     </div>
 
@@ -1723,6 +1894,8 @@ const tasksPageHTML = `<!DOCTYPE html>
                         endTime: t.endTime ? new Date(t.endTime) : null,
                         result: t.result,
                         walletActivityResult: t.walletActivityResult,
+                        backtestResult: t.backtestResult,
+                        walletClustersResult: t.walletClustersResult,
                         error: t.error,
                         markets: [] // Not needed for display
                     }));
@@ -1749,6 +1922,8 @@ const tasksPageHTML = `<!DOCTYPE html>
                         endTime: t.endTime,
                         result: t.result,
                         walletActivityResult: t.walletActivityResult,
+                        backtestResult: t.backtestResult,
+                        walletClustersResult: t.walletClustersResult,
                         error: t.error
                     }));
 
@@ -3004,6 +3179,403 @@ const tasksPageHTML = `<!DOCTYPE html>
         document.addEventListener('DOMContentLoaded', () => {
             setupHoldersMarketSearch();
         });
+
+        // Alert Backtest Task
+        function runBacktestTask() {
+            const taskId = ++taskIdCounter;
+            const query = document.getElementById('backtestQuery').value.trim();
+            const lookbackDays = parseInt(document.getElementById('backtestLookbackDays').value) || 30;
+            const maxMarkets = parseInt(document.getElementById('backtestMaxMarkets').value) || 10;
+            const minNotional = parseFloat(document.getElementById('backtestMinNotional').value) || 0;
+
+            const task = {
+                id: taskId,
+                type: 'backtest',
+                name: 'Alert Backtest',
+                description: maxMarkets + ' markets, last ' + lookbackDays + 'd' + (query ? ' matching "' + query + '"' : ''),
+                status: 'running',
+                startTime: new Date(),
+                query: query,
+                lookbackDays: lookbackDays,
+                maxMarkets: maxMarkets,
+                minNotional: minNotional,
+                backtestResult: null,
+                error: null
+            };
+
+            runningTasks.push(task);
+            updateRunningTasksUI();
+            showToast('Task started', 'success');
+
+            executeBacktestTask(task);
+        }
+
+        async function executeBacktestTask(task) {
+            try {
+                const response = await fetch('/api/tasks/backtest', {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({
+                        query: task.query,
+                        lookbackDays: task.lookbackDays,
+                        maxMarkets: task.maxMarkets,
+                        minNotional: task.minNotional
+                    })
+                });
+
+                if (!response.ok) {
+                    const err = await response.json();
+                    throw new Error(err.error || 'Task failed');
+                }
+
+                const data = await response.json();
+                task.status = 'completed';
+                task.backtestResult = data;
+                task.endTime = new Date();
+
+                showToast('Task completed: ' + data.totalAlerts + ' alerts', 'success');
+            } catch (err) {
+                task.status = 'failed';
+                task.error = err.message;
+                task.endTime = new Date();
+                showToast('Task failed: ' + err.message, 'error');
+            }
+
+            updateRunningTasksUI();
+            saveTaskHistory();
+        }
+
+        function formatPercent(rate) {
+            return (rate * 100).toFixed(1) + '%';
+        }
+
+        function openBacktestModal(task) {
+            currentModalTask = task;
+
+            const modal = document.getElementById('taskModal');
+            const title = document.getElementById('modalTitle');
+            const body = document.getElementById('modalBody');
+            const exportBtn = document.getElementById('exportCsvBtn');
+
+            title.textContent = task.name + ' #' + task.id;
+            exportBtn.style.display = (task.status === 'completed' && task.backtestResult) ? 'inline-block' : 'none';
+
+            let statusClass = task.status;
+            let statusText = task.status.charAt(0).toUpperCase() + task.status.slice(1);
+
+            let html = '<div class="modal-status ' + statusClass + '">' + statusText + '</div>';
+            html += '<p style="margin-bottom: 16px; color: var(--text-secondary);">' + escapeHtml(task.description) + '</p>';
+
+            if (task.status === 'failed' && task.error) {
+                html += '<div style="padding: 12px; background: rgba(248, 81, 73, 0.1); border-radius: 6px; color: var(--error); margin-bottom: 16px;">' + escapeHtml(task.error) + '</div>';
+            }
+
+            if (task.backtestResult) {
+                const r = task.backtestResult;
+                const overallRate = r.totalAlerts > 0 ? r.winningAlerts / r.totalAlerts : 0;
+                html += '<div class="results-summary">';
+                html += '<div class="summary-stat"><span class="value">' + r.marketsProcessed + '</span><span class="label">Markets</span></div>';
+                html += '<div class="summary-stat"><span class="value">' + r.tradesEvaluated + '</span><span class="label">Trades Evaluated</span></div>';
+                html += '<div class="summary-stat"><span class="value">' + r.totalAlerts + '</span><span class="label">Alerts</span></div>';
+                html += '<div class="summary-stat"><span class="value">' + formatPercent(overallRate) + '</span><span class="label">Backed Winner</span></div>';
+                html += '<div class="summary-stat"><span class="value">' + (r.durationMs / 1000).toFixed(1) + 's</span><span class="label">Duration</span></div>';
+                html += '</div>';
+
+                if (r.reasons && r.reasons.length > 0) {
+                    html += '<h4 style="font-size: 14px; margin: 16px 0 8px 0;">By Alert Reason</h4>';
+                    html += '<table class="results-table"><thead><tr><th>Reason</th><th>Alerts</th><th>Backed Winner</th><th>Rate</th></tr></thead><tbody>';
+                    r.reasons.forEach(reason => {
+                        html += '<tr>';
+                        html += '<td>' + escapeHtml(reason.reason) + '</td>';
+                        html += '<td>' + reason.alerts + '</td>';
+                        html += '<td>' + reason.winning + '</td>';
+                        html += '<td>' + formatPercent(reason.winningRate) + '</td>';
+                        html += '</tr>';
+                    });
+                    html += '</tbody></table>';
+                } else {
+                    html += '<p style="color: var(--text-secondary);">No alerts would have fired.</p>';
+                }
+
+                if (r.alerts && r.alerts.length > 0) {
+                    html += '<h4 style="font-size: 14px; margin: 16px 0 8px 0;">Alerts</h4>';
+                    html += '<table class="results-table"><thead><tr><th>Time</th><th>Wallet</th><th>Market</th><th>Trade</th><th>Notional</th><th>Reasons</th><th>Winner</th></tr></thead><tbody>';
+                    r.alerts.forEach(alert => {
+                        html += '<tr>';
+                        html += '<td>' + new Date(alert.timestamp).toLocaleString() + '</td>';
+                        html += '<td class="wallet-address"><a href="https://polymarket.com/profile/' + alert.wallet + '" target="_blank">' + alert.wallet.substring(0, 6) + '...' + alert.wallet.substring(alert.wallet.length - 4) + '</a></td>';
+                        html += '<td>' + escapeHtml(alert.title.substring(0, 40)) + (alert.title.length > 40 ? '...' : '') + '</td>';
+                        html += '<td>' + alert.side + ' ' + escapeHtml(alert.outcome) + ' @ $' + alert.price.toFixed(3) + '</td>';
+                        html += '<td>$' + formatNumber(alert.notional) + '</td>';
+                        html += '<td>' + escapeHtml(alert.reasons.join(', ')) + '</td>';
+                        html += '<td style="color: ' + (alert.backedWinner ? 'var(--success)' : 'var(--error)') + ';">' + (alert.backedWinner ? 'Yes' : 'No') + '</td>';
+                        html += '</tr>';
+                    });
+                    html += '</tbody></table>';
+                }
+
+                if (r.errors && r.errors.length > 0) {
+                    html += '<div style="margin-top: 16px;"><h4 style="font-size: 14px; margin-bottom: 8px; color: var(--warning);">Warnings</h4>';
+                    r.errors.forEach(err => {
+                        html += '<div style="font-size: 12px; color: var(--text-secondary); margin-bottom: 4px;">' + escapeHtml(err) + '</div>';
+                    });
+                    html += '</div>';
+                }
+            } else if (task.status === 'running') {
+                html += '<div style="display: flex; align-items: center; gap: 12px; color: var(--text-secondary);"><div class="spinner"></div>Replaying trades...</div>';
+            }
+
+            body.innerHTML = html;
+            modal.classList.add('show');
+        }
+
+        // Update openTaskModal to handle backtests
+        const originalOpenTaskModalBacktest = openTaskModal;
+        openTaskModal = function(taskId) {
+            const task = runningTasks.find(t => t.id === taskId);
+            if (!task) return;
+
+            if (task.type === 'backtest') {
+                openBacktestModal(task);
+                return;
+            }
+
+            originalOpenTaskModalBacktest(taskId);
+        };
+
+        // Update the running tasks UI to show backtest results
+        const originalUpdateRunningTasksUIBacktest = updateRunningTasksUI;
+        updateRunningTasksUI = function() {
+            originalUpdateRunningTasksUIBacktest();
+
+            runningTasks.filter(t => t.type === 'backtest' && t.status !== 'running').forEach(task => {
+                const item = document.querySelector(` + "`" + `.finished-task-item .running-task-info[onclick="openTaskModal(${task.id})"]` + "`" + `);
+                if (item && task.backtestResult) {
+                    const meta = item.querySelector('.running-task-meta');
+                    if (meta) {
+                        const duration = ((task.endTime - task.startTime) / 1000).toFixed(1);
+                        meta.textContent = duration + 's - ' + task.backtestResult.totalAlerts + ' alerts';
+                    }
+                }
+            });
+        };
+
+        // Generate CSV for backtests
+        function generateBacktestCsv(task) {
+            const r = task.backtestResult;
+            let csv = 'Reason,Alerts,Backed Winner,Rate\n';
+
+            r.reasons.forEach(reason => {
+                csv += reason.reason + ',' + reason.alerts + ',' + reason.winning + ',' + reason.winningRate.toFixed(4) + '\n';
+            });
+
+            csv += '\nALERTS\n';
+            csv += 'Timestamp,Wallet Address,Condition ID,Market,Side,Outcome,Winning Outcome,Price,Notional,Reasons,Backed Winner\n';
+            r.alerts.forEach(alert => {
+                csv += alert.timestamp + ',' + alert.wallet + ',' + alert.conditionId + ',"' + alert.title.replace(/"/g, '""') + '",' + alert.side + ',"' + alert.outcome + '","' + alert.winningOutcome + '",' + alert.price.toFixed(4) + ',' + alert.notional.toFixed(2) + ',"' + alert.reasons.join(';') + '",' + alert.backedWinner + '\n';
+            });
+
+            csv += '\nSUMMARY\n';
+            csv += 'Markets Processed,' + r.marketsProcessed + '\n';
+            csv += 'Trades Processed,' + r.tradesProcessed + '\n';
+            csv += 'Trades Evaluated,' + r.tradesEvaluated + '\n';
+            csv += 'Wallets Analyzed,' + r.walletsAnalyzed + '\n';
+            csv += 'Total Alerts,' + r.totalAlerts + '\n';
+            csv += 'Backed Winner,' + r.winningAlerts + '\n';
+
+            return csv;
+        }
+
+        // Update CSV export to handle backtests
+        const originalExportTaskToCsvBacktest = exportTaskToCsv;
+        exportTaskToCsv = function() {
+            if (!currentModalTask) return;
+
+            if (currentModalTask.type === 'backtest' && currentModalTask.backtestResult) {
+                const csv = generateBacktestCsv(currentModalTask);
+                const filename = 'backtest-' + currentModalTask.id + '.csv';
+                downloadCsv(csv, filename);
+                showToast('CSV exported', 'success');
+                return;
+            }
+
+            originalExportTaskToCsvBacktest();
+        };
+
+        // Wallet Clusters Task
+        function runWalletClustersTask() {
+            const taskId = ++taskIdCounter;
+            const query = document.getElementById('walletClustersQuery').value.trim();
+
+            const task = {
+                id: taskId,
+                type: 'wallet-clusters',
+                name: 'Wallet Clusters',
+                description: query ? 'Cluster of ' + query : 'All clusters',
+                status: 'running',
+                startTime: new Date(),
+                query: query,
+                walletClustersResult: null,
+                error: null
+            };
+
+            runningTasks.push(task);
+            updateRunningTasksUI();
+
+            executeWalletClustersTask(task);
+        }
+
+        async function executeWalletClustersTask(task) {
+            try {
+                const response = await fetch('/api/tasks/wallet-clusters', {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ query: task.query })
+                });
+
+                if (!response.ok) {
+                    const err = await response.json();
+                    throw new Error(err.error || 'Task failed');
+                }
+
+                const data = await response.json();
+                task.status = 'completed';
+                task.walletClustersResult = data;
+                task.endTime = new Date();
+            } catch (err) {
+                task.status = 'failed';
+                task.error = err.message;
+                task.endTime = new Date();
+                showToast('Task failed: ' + err.message, 'error');
+            }
+
+            updateRunningTasksUI();
+            saveTaskHistory();
+            openWalletClustersModal(task);
+        }
+
+        function shortWallet(address) {
+            return address.substring(0, 6) + '...' + address.substring(address.length - 4);
+        }
+
+        function walletLinkHtml(address) {
+            return '<a href="https://polymarket.com/profile/' + address + '" target="_blank">' + shortWallet(address) + '</a>';
+        }
+
+        function describeWalletLink(link) {
+            const parts = [];
+            if (link.coTradeMarkets) parts.push('co-traded ' + link.coTradeMarkets + ' markets');
+            if (link.sizeMarkets) parts.push('same sizes in ' + link.sizeMarkets + ' markets');
+            if (link.copies) parts.push(link.copies + ' copies');
+            if (link.funder) parts.push('funded by ' + shortWallet(link.funder));
+            return parts.join(', ');
+        }
+
+        function openWalletClustersModal(task) {
+            currentModalTask = task;
+
+            const modal = document.getElementById('taskModal');
+            const title = document.getElementById('modalTitle');
+            const body = document.getElementById('modalBody');
+            const exportBtn = document.getElementById('exportCsvBtn');
+
+            title.textContent = task.name + ' #' + task.id;
+            exportBtn.style.display = (task.status === 'completed' && task.walletClustersResult && task.walletClustersResult.clusters.length > 0) ? 'inline-block' : 'none';
+
+            let statusClass = task.status;
+            let statusText = task.status.charAt(0).toUpperCase() + task.status.slice(1);
+
+            let html = '<div class="modal-status ' + statusClass + '">' + statusText + '</div>';
+            html += '<p style="margin-bottom: 16px; color: var(--text-secondary);">' + escapeHtml(task.description) + '</p>';
+
+            if (task.status === 'failed' && task.error) {
+                html += '<div style="padding: 12px; background: rgba(248, 81, 73, 0.1); border-radius: 6px; color: var(--error); margin-bottom: 16px;">' + escapeHtml(task.error) + '</div>';
+            }
+
+            if (task.walletClustersResult) {
+                const r = task.walletClustersResult;
+                if (r.clusters.length === 0) {
+                    html += '<p style="color: var(--text-secondary);">' + (r.query ? 'No cluster found for ' + escapeHtml(r.query) + '.' : 'No wallets have been linked yet.') + '</p>';
+                } else if (!r.query && r.total > r.clusters.length) {
+                    html += '<p style="color: var(--text-secondary); margin-bottom: 8px;">Showing the ' + r.clusters.length + ' largest of ' + r.total + ' clusters.</p>';
+                }
+
+                r.clusters.forEach(cluster => {
+                    html += '<h4 style="font-size: 14px; margin: 16px 0 8px 0;">' + escapeHtml(cluster.id) + ' · ' + cluster.wallets.length + ' wallets · ' + escapeHtml(cluster.signals.join(', ')) + '</h4>';
+                    html += '<table class="results-table"><thead><tr><th>Wallet</th><th>Linked To</th><th>Evidence</th></tr></thead><tbody>';
+                    cluster.links.forEach(link => {
+                        html += '<tr>';
+                        html += '<td class="wallet-address">' + walletLinkHtml(link.a) + '</td>';
+                        html += '<td class="wallet-address">' + walletLinkHtml(link.b) + '</td>';
+                        html += '<td>' + escapeHtml(describeWalletLink(link)) + '</td>';
+                        html += '</tr>';
+                    });
+                    html += '</tbody></table>';
+                });
+            } else if (task.status === 'running') {
+                html += '<div style="display: flex; align-items: center; gap: 12px; color: var(--text-secondary);"><div class="spinner"></div>Loading clusters...</div>';
+            }
+
+            body.innerHTML = html;
+            modal.classList.add('show');
+        }
+
+        // Update openTaskModal to handle wallet clusters
+        const originalOpenTaskModalWalletClusters = openTaskModal;
+        openTaskModal = function(taskId) {
+            const task = runningTasks.find(t => t.id === taskId);
+            if (!task) return;
+
+            if (task.type === 'wallet-clusters') {
+                openWalletClustersModal(task);
+                return;
+            }
+
+            originalOpenTaskModalWalletClusters(taskId);
+        };
+
+        // Update the running tasks UI to show cluster counts
+        const originalUpdateRunningTasksUIWalletClusters = updateRunningTasksUI;
+        updateRunningTasksUI = function() {
+            originalUpdateRunningTasksUIWalletClusters();
+
+            runningTasks.filter(t => t.type === 'wallet-clusters' && t.status !== 'running').forEach(task => {
+                const item = document.querySelector(` + "`" + `.finished-task-item .running-task-info[onclick="openTaskModal(${task.id})"]` + "`" + `);
+                if (item && task.walletClustersResult) {
+                    const meta = item.querySelector('.running-task-meta');
+                    if (meta) {
+                        meta.textContent = task.walletClustersResult.clusters.length + ' clusters';
+                    }
+                }
+            });
+        };
+
+        // Generate CSV for wallet clusters
+        function generateWalletClustersCsv(task) {
+            let csv = 'Cluster ID,Wallet A,Wallet B,Signals,Co-Traded Markets,Same Size Markets,Copies,Funder\n';
+            task.walletClustersResult.clusters.forEach(cluster => {
+                cluster.links.forEach(link => {
+                    csv += cluster.id + ',' + link.a + ',' + link.b + ',"' + link.signals.join(';') + '",' + (link.coTradeMarkets || 0) + ',' + (link.sizeMarkets || 0) + ',' + (link.copies || 0) + ',' + (link.funder || '') + '\n';
+                });
+            });
+            return csv;
+        }
+
+        // Update CSV export to handle wallet clusters
+        const originalExportTaskToCsvWalletClusters = exportTaskToCsv;
+        exportTaskToCsv = function() {
+            if (!currentModalTask) return;
+
+            if (currentModalTask.type === 'wallet-clusters' && currentModalTask.walletClustersResult) {
+                const csv = generateWalletClustersCsv(currentModalTask);
+                const filename = 'wallet-clusters-' + currentModalTask.id + '.csv';
+                downloadCsv(csv, filename);
+                showToast('CSV exported', 'success');
+                return;
+            }
+
+            originalExportTaskToCsvWalletClusters();
+        };
     </script>
 </body>
 </html>`
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"strings"
+)
+
+// maxListedClusters caps the clusters returned when listing them all.
+const maxListedClusters = 50
+
+// WalletClustersRequest is the request for the wallet cluster view.
+type WalletClustersRequest struct {
+	Query string `json:"query"` // Wallet address or cluster ID (empty = all clusters)
+}
+
+// WalletClustersResult is the result of the wallet cluster view.
+type WalletClustersResult struct {
+	Status   string          `json:"status"`
+	Query    string          `json:"query,omitempty"`
+	Clusters []WalletCluster `json:"clusters"`
+	Total    int             `json:"total"` // Clusters known, before the limit
+}
+
+// lookupWalletClusters finds the cluster matching a wallet address or cluster
+// ID, or lists the largest clusters if the query is empty.
+func lookupWalletClusters(clusters *WalletClusters, req WalletClustersRequest) *WalletClustersResult {
+	query := strings.TrimSpace(req.Query)
+	result := &WalletClustersResult{
+		Status:   "completed",
+		Query:    query,
+		Clusters: []WalletCluster{},
+	}
+
+	if query == "" {
+		all := clusters.Clusters()
+		result.Total = len(all)
+		if len(all) > maxListedClusters {
+			all = all[:maxListedClusters]
+		}
+		result.Clusters = append(result.Clusters, all...)
+		return result
+	}
+
+	var cluster WalletCluster
+	var ok bool
+	if strings.HasPrefix(strings.ToLower(query), "0x") {
+		cluster, ok = clusters.ClusterOf(query)
+	} else {
+		cluster, ok = clusters.Cluster(query)
+	}
+	if ok {
+		result.Clusters = append(result.Clusters, cluster)
+		result.Total = 1
+	}
+	return result
+}
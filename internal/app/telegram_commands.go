@@ -0,0 +1,227 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/polymarketapi"
+	"polybot/clients/telegram"
+	"polybot/config"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// telegramPollTimeout is how long each getUpdates long poll waits for messages.
+	telegramPollTimeout = 30 * time.Second
+	// telegramPollRetryDelay is how long to wait after a failed poll.
+	telegramPollRetryDelay = 5 * time.Second
+	// maxTelegramCommandAge drops commands queued while the bot was down.
+	maxTelegramCommandAge = 5 * time.Minute
+)
+
+// TelegramBot is the part of the Telegram client used for commands.
+// Implemented by telegram.TelegramClient.
+type TelegramBot interface {
+	ChatID() string
+	GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]telegram.Update, error)
+	SendReply(ctx context.Context, replyToMessageID int64, text string) error
+}
+
+// TelegramCommandHandler answers bot commands sent in the alert chat, so
+// wallets and markets can be investigated from a phone without the web UI.
+// Updates are read with long-polled getUpdates; only messages from the alert
+// chat (and, if configured, from allowed user IDs) are handled.
+type TelegramCommandHandler struct {
+	logger       *zap.Logger
+	bot          TelegramBot
+	commands     *chatCommands
+	allowedUsers map[string]bool // nil = anyone in the chat
+	now          func() time.Time
+
+	offset int64
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTelegramCommandHandler creates a command handler. allowedUserIDs limits
+// who can run commands; empty allows anyone in the alert chat.
+func NewTelegramCommandHandler(
+	logger *zap.Logger,
+	bot TelegramBot,
+	polymarket *polymarketapi.PolymarketApiClient,
+	settings *config.SettingsManager,
+	mutes *MuteList,
+	stats func() ServiceStats,
+	allowedUserIDs []string,
+) *TelegramCommandHandler {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	logger = logger.Named("telegram-commands")
+	h := &TelegramCommandHandler{
+		logger:   logger,
+		bot:      bot,
+		commands: newChatCommands(logger, polymarket, settings, mutes, stats, telegramMarkup),
+		now:      time.Now,
+	}
+	for _, id := range allowedUserIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			if h.allowedUsers == nil {
+				h.allowedUsers = make(map[string]bool)
+			}
+			h.allowedUsers[id] = true
+		}
+	}
+	return h
+}
+
+// Start begins polling for commands.
+func (h *TelegramCommandHandler) Start(ctx context.Context) {
+	ctx, h.cancel = context.WithCancel(ctx)
+	h.wg.Add(1)
+	go h.run(ctx)
+
+	h.logger.Info("telegram commands enabled",
+		zap.String("chatID", h.bot.ChatID()),
+		zap.Int("allowedUsers", len(h.allowedUsers)),
+	)
+}
+
+// Stop ends polling and waits for running commands to finish.
+func (h *TelegramCommandHandler) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	h.wg.Wait()
+}
+
+// run polls for updates until stopped.
+func (h *TelegramCommandHandler) run(ctx context.Context) {
+	defer h.wg.Done()
+
+	for {
+		updates, err := h.bot.GetUpdates(ctx, h.offset, telegramPollTimeout)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			h.logger.Warn("failed to get telegram updates", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(telegramPollRetryDelay):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			h.offset = u.UpdateID + 1
+			h.handleUpdate(ctx, u)
+		}
+	}
+}
+
+// handleUpdate authorizes and runs a command message.
+func (h *TelegramCommandHandler) handleUpdate(ctx context.Context, u telegram.Update) {
+	msg := u.Message
+	if msg == nil {
+		return
+	}
+	name, args := msg.Command()
+	if name == "" {
+		return
+	}
+
+	if strconv.FormatInt(msg.Chat.ID, 10) != h.bot.ChatID() {
+		h.logger.Warn("ignoring telegram command from unauthorized chat",
+			zap.Int64("chatID", msg.Chat.ID),
+			zap.String("command", name),
+		)
+		return
+	}
+	if sent := time.Unix(msg.Date, 0); msg.Date > 0 && h.now().Sub(sent) > maxTelegramCommandAge {
+		h.logger.Info("ignoring stale telegram command",
+			zap.String("command", name),
+			zap.Time("sentAt", sent),
+		)
+		return
+	}
+	if h.allowedUsers != nil && !h.allowedUsers[msg.SenderID()] {
+		h.logger.Warn("ignoring telegram command from unauthorized user",
+			zap.String("userID", msg.SenderID()),
+			zap.String("command", name),
+		)
+		h.reply(ctx, msg, "⛔ You are not allowed to run commands.")
+		return
+	}
+
+	h.logger.Info("telegram command",
+		zap.String("command", name),
+		zap.Strings("args", args),
+		zap.String("userID", msg.SenderID()),
+	)
+
+	switch name {
+	case "wallet", "holders":
+		// Tasks page through the Polymarket API and can take minutes
+		h.reply(ctx, msg, "⏳ Running…")
+		h.wg.Add(1)
+		go func() {
+			defer h.wg.Done()
+			h.reply(ctx, msg, h.execute(ctx, msg, name, args))
+		}()
+	default:
+		h.reply(ctx, msg, h.execute(ctx, msg, name, args))
+	}
+}
+
+// execute runs a command and returns the Markdown reply.
+func (h *TelegramCommandHandler) execute(ctx context.Context, msg *telegram.Message, name string, args []string) string {
+	switch name {
+	case "start", "help":
+		return telegramCommandHelp
+	case "wallet":
+		return h.commands.wallet(ctx, args)
+	case "holders":
+		return h.commands.holders(ctx, args)
+	case "stats":
+		return h.commands.statsSummary()
+	case "watch":
+		return h.commands.watch(ctx, args, true)
+	case "unwatch":
+		return h.commands.watch(ctx, args, false)
+	case "mute":
+		createdBy := msg.SenderID()
+		if msg.From != nil && msg.From.Username != "" {
+			createdBy = msg.From.Username
+		}
+		return h.commands.mute(createdBy, args)
+	case "unmute":
+		return h.commands.unmute(args)
+	default:
+		return fmt.Sprintf("Unknown command /%s. Try /help.", escapeTelegram(name))
+	}
+}
+
+const telegramCommandHelp = "*🤖 Polybot Commands*\n\n" +
+	"`/wallet <address> [1d|1w|2w|1m|3m|6m|1y]` — wallet activity\n" +
+	"`/holders <conditionId>` — top holders of a market\n" +
+	"`/stats` — service stats\n" +
+	"`/watch [address…]` — add to the wallet filter (no args lists it)\n" +
+	"`/unwatch <address…>` — remove from the wallet filter\n" +
+	"`/mute <address|conditionId> <duration>` — mute alerts (e.g. 6h, 3d)\n" +
+	"`/mute` — list active mutes\n" +
+	"`/unmute <address|conditionId>` — remove a mute"
+
+// reply sends a reply, logging failures.
+func (h *TelegramCommandHandler) reply(ctx context.Context, msg *telegram.Message, text string) {
+	if err := h.bot.SendReply(ctx, msg.MessageID, text); err != nil {
+		h.logger.Error("failed to send telegram reply", zap.Error(err))
+	}
+}
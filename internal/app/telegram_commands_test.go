@@ -0,0 +1,267 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"polybot/clients/telegram"
+	"polybot/config"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	testChatID  = "-100123"
+	testWallet  = "0x1111111111111111111111111111111111111111"
+	testWallet2 = "0x2222222222222222222222222222222222222222"
+	testMarket  = "0x3333333333333333333333333333333333333333333333333333333333333333"
+)
+
+// fakeTelegramBot records replies sent by the command handler.
+type fakeTelegramBot struct {
+	mu      sync.Mutex
+	replies []string
+}
+
+func (b *fakeTelegramBot) ChatID() string { return testChatID }
+
+func (b *fakeTelegramBot) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]telegram.Update, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (b *fakeTelegramBot) SendReply(ctx context.Context, replyToMessageID int64, text string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.replies = append(b.replies, text)
+	return nil
+}
+
+func (b *fakeTelegramBot) Replies() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.replies...)
+}
+
+func newTestCommandHandler(t *testing.T, allowedUsers ...string) (*TelegramCommandHandler, *fakeTelegramBot, *config.SettingsManager) {
+	t.Helper()
+	bot := &fakeTelegramBot{}
+	settings := config.NewSettingsManager(zap.NewNop(), nil, "", config.NewLiveConfig(config.Defaults()))
+	mutes := NewMuteList(zap.NewNop(), nil, DefaultMuteListConfig())
+	h := NewTelegramCommandHandler(zap.NewNop(), bot, nil, settings, mutes, nil, allowedUsers)
+	return h, bot, settings
+}
+
+func commandUpdate(chatID, userID int64, text string) telegram.Update {
+	return telegram.Update{
+		UpdateID: 1,
+		Message: &telegram.Message{
+			MessageID: 10,
+			From:      &telegram.User{ID: userID, Username: "alice"},
+			Chat:      telegram.Chat{ID: chatID},
+			Date:      time.Now().Unix(),
+			Text:      text,
+		},
+	}
+}
+
+func TestTelegramCommands_IgnoresOtherChats(t *testing.T) {
+	h, bot, _ := newTestCommandHandler(t)
+
+	h.handleUpdate(context.Background(), commandUpdate(-999, 42, "/help"))
+
+	if len(bot.Replies()) != 0 {
+		t.Errorf("expected no reply outside the alert chat, got %v", bot.Replies())
+	}
+}
+
+func TestTelegramCommands_RejectsUnauthorizedUser(t *testing.T) {
+	h, bot, _ := newTestCommandHandler(t, "42")
+
+	h.handleUpdate(context.Background(), commandUpdate(-100123, 7, "/mute "+testWallet+" 1h"))
+
+	replies := bot.Replies()
+	if len(replies) != 1 || !strings.Contains(replies[0], "not allowed") {
+		t.Fatalf("expected a denial, got %v", replies)
+	}
+	if len(h.commands.mutes.Active()) != 0 {
+		t.Error("expected unauthorized command not to run")
+	}
+}
+
+func TestTelegramCommands_IgnoresStaleCommands(t *testing.T) {
+	h, bot, _ := newTestCommandHandler(t)
+	u := commandUpdate(-100123, 42, "/help")
+	u.Message.Date = time.Now().Add(-time.Hour).Unix()
+
+	h.handleUpdate(context.Background(), u)
+
+	if len(bot.Replies()) != 0 {
+		t.Errorf("expected commands queued while down to be ignored, got %v", bot.Replies())
+	}
+}
+
+func TestTelegramCommands_MuteAndUnmute(t *testing.T) {
+	h, bot, _ := newTestCommandHandler(t, "42")
+	ctx := context.Background()
+
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/mute "+testMarket+" 2d"))
+	active := h.commands.mutes.Active()
+	if len(active) != 1 || active[0].Kind != MuteKindMarket || active[0].CreatedBy != "alice" {
+		t.Fatalf("expected market mute, got %+v", active)
+	}
+	if remaining := time.Until(active[0].Until); remaining < 47*time.Hour || remaining > 48*time.Hour {
+		t.Errorf("expected a 2 day mute, got %v remaining", remaining)
+	}
+
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/mute"))
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/unmute "+testMarket))
+	if len(h.commands.mutes.Active()) != 0 {
+		t.Error("expected mute to be removed")
+	}
+
+	replies := bot.Replies()
+	if len(replies) != 3 || !strings.Contains(replies[1], testMarket) || !strings.Contains(replies[2], "Unmuted") {
+		t.Errorf("unexpected replies: %v", replies)
+	}
+}
+
+func TestTelegramCommands_WatchAndUnwatch(t *testing.T) {
+	h, _, settings := newTestCommandHandler(t)
+	ctx := context.Background()
+
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/watch "+strings.ToUpper(testWallet[:2])+testWallet[2:]+" "+testWallet2))
+	wallets := settings.GetCurrentConfig().WalletFilter.SpecificWallets
+	if len(wallets) != 2 || wallets[0] != testWallet || wallets[1] != testWallet2 {
+		t.Fatalf("expected both wallets watched, got %v", wallets)
+	}
+
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/unwatch "+testWallet))
+	wallets = settings.GetCurrentConfig().WalletFilter.SpecificWallets
+	if len(wallets) != 1 || wallets[0] != testWallet2 {
+		t.Fatalf("expected one wallet left, got %v", wallets)
+	}
+
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/unwatch "+testWallet2))
+	if wallets := settings.GetCurrentConfig().WalletFilter.SpecificWallets; wallets != nil {
+		t.Errorf("expected filter cleared, got %v", wallets)
+	}
+}
+
+func TestChatCommands_ConcurrentWatchAcrossBots(t *testing.T) {
+	settings := config.NewSettingsManager(zap.NewNop(), nil, "", config.NewLiveConfig(config.Defaults()))
+	bots := []*chatCommands{
+		newChatCommands(zap.NewNop(), nil, settings, nil, nil, telegramMarkup),
+		newChatCommands(zap.NewNop(), nil, settings, nil, nil, discordMarkup),
+	}
+
+	// Each bot watches its own wallets at the same time; no edit is lost
+	const perBot = 20
+	var wg sync.WaitGroup
+	for b, bot := range bots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perBot; i++ {
+				bot.watch(context.Background(), []string{fmt.Sprintf("0x%039d%d", i, b)}, true)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if wallets := settings.GetCurrentConfig().WalletFilter.SpecificWallets; len(wallets) != 2*perBot {
+		t.Errorf("expected %d wallets watched, got %d", 2*perBot, len(wallets))
+	}
+}
+
+func TestChatCommands_WatchConcurrentWithWebEdits(t *testing.T) {
+	settings := config.NewSettingsManager(zap.NewNop(), nil, "", config.NewLiveConfig(config.Defaults()))
+	chat := newChatCommands(zap.NewNop(), nil, settings, nil, nil, telegramMarkup)
+	web := NewSettingsHandler(zap.NewNop(), settings, nil)
+
+	// Chat watches and settings page edits interleave; neither loses the other's changes
+	const edits = 200
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < edits; i++ {
+			chat.watch(context.Background(), []string{fmt.Sprintf("0x%040d", i)}, true)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= edits; i++ {
+			body := fmt.Sprintf(`{"trade_monitor":{"min_notional":%d}}`, i*100)
+			rec := httptest.NewRecorder()
+			web.updateSettings(rec, httptest.NewRequest(http.MethodPost, "/api/settings", strings.NewReader(body)))
+			if rec.Code != http.StatusOK {
+				t.Errorf("settings update failed: %d %s", rec.Code, rec.Body.String())
+			}
+		}
+	}()
+	wg.Wait()
+
+	cfg := settings.GetCurrentConfig()
+	if len(cfg.WalletFilter.SpecificWallets) != edits {
+		t.Errorf("expected %d wallets watched, got %d", edits, len(cfg.WalletFilter.SpecificWallets))
+	}
+	if cfg.TradeMonitor.MinNotional != edits*100 {
+		t.Errorf("expected the last web edit applied, got %v", cfg.TradeMonitor.MinNotional)
+	}
+}
+
+func TestTelegramCommands_RejectsBadArguments(t *testing.T) {
+	h, bot, _ := newTestCommandHandler(t)
+	ctx := context.Background()
+
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/mute 0xabc 1h"))
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/mute "+testWallet+" soon"))
+	h.handleUpdate(ctx, commandUpdate(-100123, 42, "/frobnicate"))
+
+	replies := bot.Replies()
+	if len(replies) != 3 {
+		t.Fatalf("expected 3 replies, got %v", replies)
+	}
+	if !strings.Contains(replies[0], "Not a wallet address") ||
+		!strings.Contains(replies[1], "Duration") ||
+		!strings.Contains(replies[2], "Unknown command") {
+		t.Errorf("unexpected replies: %v", replies)
+	}
+	if len(h.commands.mutes.Active()) != 0 {
+		t.Error("expected no mutes from bad commands")
+	}
+}
+
+func TestParseMuteDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30m", 30 * time.Minute, false},
+		{"6h", 6 * time.Hour, false},
+		{"3d", 72 * time.Hour, false},
+		{"1w", 7 * 24 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"0h", 0, true},
+		{"-1h", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseMuteDuration(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseMuteDuration(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseMuteDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
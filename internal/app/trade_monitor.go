@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
 	"polybot/clients/notifier"
 	"polybot/clients/polymarketapi"
@@ -44,8 +46,23 @@ type TradeMonitorConfig struct {
 	MassiveTradeMinNotional  float64 // Minimum notional for massive trade alerts (e.g., 50000)
 	MassiveTradeMaxPrice     float64 // Max entry price to alert on massive trades (e.g., 0.70 = ignore obvious 70¢+ bets)
 
+	// Market impact detection (size relative to the market; 0 disables a measure)
+	ImpactMinNotional   float64 // Minimum notional so thin markets don't alert on small trades (e.g., 2000)
+	ImpactVolumePct     float64 // Notional as a share of the market's 24h volume (e.g., 0.10 = 10%)
+	ImpactDepthPct      float64 // Notional as a share of the book depth on the side taken (e.g., 0.50 = 50%)
+	ImpactPriceMovePct  float64 // Relative price move across the trade (e.g., 0.10 = 10%)
+
+	// Coordinated entry detection (fresh wallets buying the same outcome together; 0 wallets disables)
+	ClusterMinWallets  int           // Distinct fresh wallets needed (e.g., 4)
+	ClusterMaxMarkets  int           // Max prior markets for a wallet to count as fresh (e.g., 3)
+	ClusterWindow      time.Duration // How close together the buys must be (e.g., 30m)
+	ClusterMinNotional float64       // Minimum combined notional (e.g., 20000)
+
 	// Global obvious price filter - skip ALL alerts above this price
 	ObviousPrice float64 // Max price to alert on (e.g., 0.85 = skip alerts for trades at 85¢+)
+
+	// Order aggregation (WebSocket only)
+	FillAggregationWindow time.Duration // How long to collect fills of one order before evaluating it (0 = evaluate each fill)
 }
 
 // DefaultTradeMonitorConfig returns sensible defaults.
@@ -67,7 +84,16 @@ func DefaultTradeMonitorConfig() TradeMonitorConfig {
 		ContrarianMinNotional:   5000,          // $5000 minimum for contrarian alerts
 		MassiveTradeMinNotional: 50000,         // $50000 minimum for massive trade alerts
 		MassiveTradeMaxPrice:    0.70,          // Only alert on massive trades at 70¢ or below
+		ImpactMinNotional:       2000,          // $2000 minimum for market impact alerts
+		ImpactVolumePct:         0.10,          // 10% of the market's 24h volume
+		ImpactDepthPct:          0.50,          // Half the resting liquidity on the side taken
+		ImpactPriceMovePct:      0.10,          // Moved the price 10%
+		ClusterMinWallets:       4,             // 4+ fresh wallets on the same outcome
+		ClusterMaxMarkets:       3,             // 0-3 prior markets = fresh
+		ClusterWindow:           30 * time.Minute, // Within 30 minutes
+		ClusterMinNotional:      20000,         // $20000 combined
 		ObviousPrice:            0.75,          // Skip all alerts for trades at 85¢ or above
+		FillAggregationWindow:   2 * time.Second, // Collect an order's fills for 2s
 	}
 }
 
@@ -91,6 +117,9 @@ const (
 	AlertReasonConvictionDoubling  = notifier.AlertReasonConvictionDoubling
 	AlertReasonPerfectExitTiming   = notifier.AlertReasonPerfectExitTiming
 	AlertReasonStealthAccumulation = notifier.AlertReasonStealthAccumulation
+	AlertReasonPreMovePositioning  = notifier.AlertReasonPreMovePositioning
+	AlertReasonMarketImpact        = notifier.AlertReasonMarketImpact
+	AlertReasonCoordinatedEntry    = notifier.AlertReasonCoordinatedEntry
 )
 
 // MarketInfo holds metadata about a market for enriching WebSocket events.
@@ -101,6 +130,8 @@ type MarketInfo struct {
 	Image       string
 	Outcomes    []string // e.g., ["Yes", "No"]
 	TokenIDs    []string // Token IDs for this market
+	Categories  []string // Lowercased Gamma category and tag slugs, e.g. ["sports", "nba"]
+	Volume24h   float64  // Gamma 24h volume in USD as of the last market refresh
 }
 
 // TradeMonitor monitors trades via WebSocket and alerts on low-activity wallet activity.
@@ -113,8 +144,13 @@ type TradeMonitor struct {
 	copyTracker     *CopyTracker
 	hedgeTracker    *HedgeTracker
 	patternTracker  *PatternTracker
+	outcomeTracker  *AlertOutcomeTracker
 	notifier        notifier.Notifier
 
+	// Alert heuristics, run in order for every trade from either ingestion path
+	detectorsMu sync.RWMutex
+	detectors   []Detector
+
 	// Config with mutex for hot-reload support
 	configMu sync.RWMutex
 	config   TradeMonitorConfig
@@ -125,6 +161,15 @@ type TradeMonitor struct {
 	tokenToInfo map[string]*MarketInfo // token ID -> market info
 	allTokenIDs []string               // all subscribed token IDs
 
+	// Combines WebSocket fills of one order into a single trade
+	orders *OrderAggregator
+
+	// L2 order books from the market channel (WebSocket only)
+	books *OrderBooks
+
+	// Fresh-wallet buys grouped per market outcome for coordinated entry detection
+	clusters *EntryClusters
+
 	// Track seen trades to avoid duplicates
 	seenMu     sync.Mutex
 	seenTrades map[string]struct{}
@@ -160,18 +205,40 @@ type TradeMonitor struct {
 	alertsConvictionDoubling    int
 	alertsPerfectExitTiming     int
 	alertsStealthAccumulation   int
+	alertsPreMovePositioning    int
+	alertsMarketImpact          int
+	alertsCoordinatedEntry      int
 
 	// Rapid trading detection - track recent trades per wallet
 	recentTradesMu sync.Mutex
 	recentTrades   map[string][]recentTrade // wallet -> recent trades
 
+	// clock returns the current time (the trade's time during replays and backtests)
+	clock func() time.Time
+
 	// WebSocket connection state
 	wsConnectedMu sync.RWMutex
 	wsConnected   bool
 
 	// Wallet filter (nil = monitor all wallets)
+	walletFilterMu  sync.RWMutex
 	specificWallets map[string]bool
 
+	// Wallets and markets muted from chat commands (may be nil)
+	muteList *MuteList
+
+	// Notified alerts indexed for feedback buttons (may be nil)
+	feedback *AlertFeedbackStore
+
+	// Merges repeat alerts on a wallet, market and side (may be nil)
+	suppressor *AlertSuppressor
+
+	// Market-level volume and price anomalies (may be nil)
+	anomalies *MarketAnomalyMonitor
+
+	// Links wallets that appear to be the same actor (may be nil)
+	walletClusters *WalletClusters
+
 	// Recent alerts for dashboard feed (last 10)
 	recentAlertsMu sync.RWMutex
 	recentAlerts   []RecentAlertInfo
@@ -248,7 +315,7 @@ func NewTradeMonitor(
 		logger = zap.NewNop()
 	}
 
-	return &TradeMonitor{
+	tm := &TradeMonitor{
 		logger:          logger,
 		apiClient:       apiClient,
 		walletTracker:   walletTracker,
@@ -256,6 +323,9 @@ func NewTradeMonitor(
 		copyTracker:     copyTracker,
 		notifier:        notif,
 		config:          config,
+		orders:          NewOrderAggregator(),
+		books:           NewOrderBooks(),
+		clusters:        NewEntryClusters(),
 		seenTrades:      make(map[string]struct{}),
 		seenMarkets:     make(map[string]struct{}),
 		eventTypes:      make(map[string]int),
@@ -265,7 +335,11 @@ func NewTradeMonitor(
 		alertsByWallet:  make(map[string]int),
 		alertHistory:    make([]time.Time, 0, 1000),
 		alertsByMarket:  make(map[string]*MarketAlertInfo),
+		clock:           time.Now,
 	}
+	tm.detectors = defaultDetectors(tm)
+
+	return tm
 }
 
 // SetEventsClient sets the WebSocket events client.
@@ -273,6 +347,15 @@ func (tm *TradeMonitor) SetEventsClient(client *polymarketevents.PolymarketEvent
 	tm.eventsClient = client
 }
 
+// setClock makes the monitor's time windows (rapid trading, order fill
+// collection and book staleness) read now instead of the wall clock. Call it
+// before the monitor starts processing trades.
+func (tm *TradeMonitor) setClock(now func() time.Time) {
+	tm.clock = now
+	tm.orders.now = now
+	tm.books.now = now
+}
+
 // SetHedgeTracker sets the hedge tracker for hedge pattern detection.
 func (tm *TradeMonitor) SetHedgeTracker(tracker *HedgeTracker) {
 	tm.hedgeTracker = tracker
@@ -283,9 +366,17 @@ func (tm *TradeMonitor) SetPatternTracker(tracker *PatternTracker) {
 	tm.patternTracker = tracker
 }
 
+// SetAlertOutcomeTracker sets the tracker that scores alerts after resolution.
+func (tm *TradeMonitor) SetAlertOutcomeTracker(tracker *AlertOutcomeTracker) {
+	tm.outcomeTracker = tracker
+}
+
 // SetWalletFilter sets the wallet filter. Only trades from these wallets will be processed.
 // Pass nil or empty slice to monitor all wallets.
+// Safe to call while monitoring (the filter is hot-reloaded from settings).
 func (tm *TradeMonitor) SetWalletFilter(wallets []string) {
+	tm.walletFilterMu.Lock()
+	defer tm.walletFilterMu.Unlock()
 	if len(wallets) == 0 {
 		tm.specificWallets = nil
 		return
@@ -298,9 +389,43 @@ func (tm *TradeMonitor) SetWalletFilter(wallets []string) {
 		zap.Int("walletCount", len(tm.specificWallets)))
 }
 
+// SetMuteList sets the mutes checked before alerts are sent to notifiers.
+func (tm *TradeMonitor) SetMuteList(mutes *MuteList) {
+	tm.muteList = mutes
+}
+
+// SetAlertFeedbackStore sets the store that indexes notified alerts for feedback buttons.
+func (tm *TradeMonitor) SetAlertFeedbackStore(store *AlertFeedbackStore) {
+	tm.feedback = store
+}
+
+// SetAlertSuppressor sets the suppressor that merges repeat alerts into
+// updates, and sends its updates through sendAlert.
+func (tm *TradeMonitor) SetAlertSuppressor(suppressor *AlertSuppressor) {
+	tm.suppressor = suppressor
+	if suppressor != nil {
+		suppressor.SetUpdateHandler(tm.sendAlert)
+	}
+}
+
+// SetMarketAnomalyMonitor sets the monitor every new trade is recorded in,
+// regardless of the trade filters.
+func (tm *TradeMonitor) SetMarketAnomalyMonitor(monitor *MarketAnomalyMonitor) {
+	tm.anomalies = monitor
+}
+
+// SetWalletClusters sets the clustering subsystem every new trade is recorded
+// in, regardless of the trade filters. Alerting wallets are tagged with their
+// cluster.
+func (tm *TradeMonitor) SetWalletClusters(clusters *WalletClusters) {
+	tm.walletClusters = clusters
+}
+
 // shouldProcessWallet returns true if the wallet should be processed.
 // Returns true for all wallets if no filter is set.
 func (tm *TradeMonitor) shouldProcessWallet(address string) bool {
+	tm.walletFilterMu.RLock()
+	defer tm.walletFilterMu.RUnlock()
 	if tm.specificWallets == nil {
 		return true // No filter, process all
 	}
@@ -372,6 +497,8 @@ func (tm *TradeMonitor) UpdateMarkets(markets []polymarketapi.GammaMarket) error
 			Image:       m.Image,
 			Outcomes:    outcomes,
 			TokenIDs:    tokenIDs,
+			Categories:  marketCategories(m),
+			Volume24h:   m.Volume24hr,
 		}
 
 		for _, tokenID := range tokenIDs {
@@ -392,6 +519,15 @@ func (tm *TradeMonitor) UpdateMarkets(markets []polymarketapi.GammaMarket) error
 	tm.markets = conditionIDs
 	tm.mu.Unlock()
 
+	tm.books.Retain(newTokenIDs)
+	tm.clusters.Retain(conditionIDs)
+	if tm.anomalies != nil {
+		tm.anomalies.Retain(conditionIDs)
+	}
+	if tm.walletClusters != nil {
+		tm.walletClusters.Retain(conditionIDs)
+	}
+
 	// Update WebSocket subscriptions if connected
 	wsConnected := tm.IsWSConnected()
 	if tm.eventsClient != nil && wsConnected {
@@ -422,6 +558,34 @@ func (tm *TradeMonitor) UpdateMarkets(markets []polymarketapi.GammaMarket) error
 	return nil
 }
 
+// OrderBooks returns the L2 order books maintained from the market channel.
+func (tm *TradeMonitor) OrderBooks() *OrderBooks {
+	return tm.books
+}
+
+// OrderBookStats returns order book metrics with the n deepest books
+// labeled with their market and outcome.
+func (tm *TradeMonitor) OrderBookStats(n int) OrderBookStats {
+	stats := tm.books.Stats(n)
+
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	for i, q := range stats.Quotes {
+		info := tm.tokenToInfo[q.TokenID]
+		if info == nil {
+			continue
+		}
+		stats.Quotes[i].MarketTitle = info.Title
+		for j, tokenID := range info.TokenIDs {
+			if tokenID == q.TokenID && j < len(info.Outcomes) {
+				stats.Quotes[i].Outcome = info.Outcomes[j]
+				break
+			}
+		}
+	}
+	return stats
+}
+
 // GetTokenIDs returns all subscribed token IDs.
 func (tm *TradeMonitor) GetTokenIDs() []string {
 	tm.mu.RLock()
@@ -429,6 +593,44 @@ func (tm *TradeMonitor) GetTokenIDs() []string {
 	return tm.allTokenIDs
 }
 
+// MarketCategories returns the categories of a monitored market, or nil if unknown.
+func (tm *TradeMonitor) MarketCategories(conditionID string) []string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	for _, info := range tm.tokenToInfo {
+		if info.ConditionID == conditionID {
+			return info.Categories
+		}
+	}
+	return nil
+}
+
+// MarketVolume24h returns the 24h volume of the market a token belongs to.
+// Returns false if the token is not monitored.
+func (tm *TradeMonitor) MarketVolume24h(tokenID string) (float64, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	info := tm.tokenToInfo[tokenID]
+	if info == nil {
+		return 0, false
+	}
+	return info.Volume24h, true
+}
+
+// marketCategories collects a market's Gamma category and tag slugs, lowercased.
+func marketCategories(m polymarketapi.GammaMarket) []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, c := range append([]string{m.Category}, m.TagSlugs...) {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c != "" && !seen[c] {
+			seen[c] = true
+			categories = append(categories, c)
+		}
+	}
+	return categories
+}
+
 // Run starts the trade monitoring loop using WebSocket events.
 func (tm *TradeMonitor) Run(ctx context.Context) {
 	cfg := tm.getConfig()
@@ -454,12 +656,23 @@ func (tm *TradeMonitor) runWebSocket(ctx context.Context) {
 	msgCh := tm.eventsClient.Messages()
 	errCh := tm.eventsClient.Errors()
 
+	flushTicker := time.NewTicker(orderFlushInterval)
+	defer flushTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			tm.logger.Info("trade monitor shutting down")
+			// Evaluate orders still collecting fills, as replays do. ctx is
+			// already canceled, so lookups get a short context of their own.
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			tm.flushOrders(flushCtx, true)
+			cancel()
 			return
 
+		case <-flushTicker.C:
+			tm.flushOrders(ctx, false)
+
 		case msg := <-msgCh:
 			// Mark as connected when we receive messages
 			tm.wsConnectedMu.Lock()
@@ -489,6 +702,19 @@ func (tm *TradeMonitor) processWebSocketMessage(ctx context.Context, msg []byte)
 	tm.eventTypes[eventType]++
 	tm.eventTypesMu.Unlock()
 
+	switch eventType {
+	case "book":
+		if book := polymarketevents.ParseBookEvent(msg); book != nil {
+			tm.books.ApplyBook(book)
+		}
+		return
+	case "price_change":
+		if change := polymarketevents.ParsePriceChangeEvent(msg); change != nil {
+			tm.books.ApplyPriceChange(change)
+		}
+		return
+	}
+
 	event := polymarketevents.ParseTradeEvent(msg)
 	if event == nil {
 		return // Not a trade event (ParseTradeEvent already filters to trade/last_trade_price)
@@ -506,111 +732,19 @@ func (tm *TradeMonitor) processWebSocketMessage(ctx context.Context, msg []byte)
 
 // processTradeEvent processes a WebSocket trade event.
 func (tm *TradeMonitor) processTradeEvent(ctx context.Context, event *polymarketevents.TradeEvent) {
-	// Get config once at the start for thread-safe access
-	cfg := tm.getConfig()
-
-	// Create trade key for deduplication
-	tradeKey := fmt.Sprintf("%s:%s", event.TransactionHash, event.AssetID)
-
-	tm.seenMu.Lock()
-	if _, seen := tm.seenTrades[tradeKey]; seen {
-		tm.seenMu.Unlock()
-		return
-	}
-	tm.seenTrades[tradeKey] = struct{}{}
-	tm.seenMu.Unlock()
-
-	// Get price and size
-	price := event.GetPriceFloat()
-	size := event.GetSizeFloat()
-	notional := price * size
-
-	if notional < cfg.MinNotional {
-		tm.filterStatsMu.Lock()
-		tm.skippedLowNotional++
-		tm.filterStatsMu.Unlock()
-		return
-	}
-
 	// Look up market info
 	tm.mu.RLock()
 	info := tm.tokenToInfo[event.AssetID]
 	tm.mu.RUnlock()
 
-	// Determine trader address (taker is usually the one we care about)
+	// Determine trader address (taker is usually the one we care about).
+	// last_trade_price events don't include wallet addresses.
 	traderAddr := event.TakerAddress
 	if traderAddr == "" {
 		traderAddr = event.MakerAddress
 	}
-	if traderAddr == "" {
-		// last_trade_price events don't include wallet addresses, skip them
-		tm.filterStatsMu.Lock()
-		tm.skippedNoWallet++
-		tm.filterStatsMu.Unlock()
-		return
-	}
-
-	// Check wallet filter
-	if !tm.shouldProcessWallet(traderAddr) {
-		tm.filterStatsMu.Lock()
-		tm.skippedWalletFilter++
-		tm.filterStatsMu.Unlock()
-		return
-	}
-
-	// Check wallet activity and win rate
-	isLow, stats, err := tm.walletTracker.IsLowActivity(
-		ctx,
-		traderAddr,
-		cfg.MaxMarketsForLow,
-	)
-	if err != nil {
-		tm.logger.Warn("failed to check wallet activity",
-			zap.String("wallet", shortID(traderAddr)),
-			zap.Error(err),
-		)
-		return
-	}
-
-	// Build alert reasons
-	var reasons []AlertReason
-
-	// Check for low activity
-	if isLow {
-		reasons = append(reasons, AlertReasonLowActivity)
-	}
-
-	// Check for high win rate on non-obvious bets (entry price below threshold)
-	// This filters out wins on "obvious" bets (e.g., buying at 98¢)
-	// Also skip if this is a BUY/SELL at a high price (obvious outcome or cashing out)
-	resolvedCount := stats.WinCount + stats.LossCount
-	suspiciousCount := stats.SuspiciousWins + stats.SuspiciousLosses
-	isObviousBuy := strings.EqualFold(event.Side, "BUY") && price >= 0.95
-	isObviousSell := strings.EqualFold(event.Side, "SELL") && price >= 0.95
-	if suspiciousCount >= cfg.MinResolvedForWinRate &&
-		stats.SuspiciousWinRate >= cfg.HighWinRateThreshold &&
-		!isObviousBuy && !isObviousSell {
-		reasons = append(reasons, AlertReasonHighWinRate)
-	}
-
-	// Check for extreme low odds bet (longshot/contrarian)
-	if price <= cfg.ExtremeLowPrice && notional >= cfg.ExtremeMinNotional {
-		reasons = append(reasons, AlertReasonExtremeBet)
-	}
-
-	// Check for rapid trading (multiple trades in short window)
-	tradeTime := time.Unix(event.GetTimestampUnix(), 0)
-	isRapid, _, _ := tm.checkRapidTrading(traderAddr, notional, tradeTime)
-	if isRapid {
-		reasons = append(reasons, AlertReasonRapidTrading)
-	}
-
-	// Check for new wallet making large bet
-	if stats.UniqueMarkets <= cfg.NewWalletMaxMarkets && notional >= cfg.NewWalletMinNotional {
-		reasons = append(reasons, AlertReasonNewWallet)
-	}
 
-	// Determine outcome from token ID position (needed for hedge tracking)
+	// Determine outcome from token ID position
 	outcome := "Unknown"
 	if info != nil && len(info.TokenIDs) > 0 && len(info.Outcomes) > 0 {
 		for i, tokenID := range info.TokenIDs {
@@ -621,230 +755,53 @@ func (tm *TradeMonitor) processTradeEvent(ctx context.Context, event *polymarket
 		}
 	}
 
-	// Check for contrarian bet (buying at low prices = betting against consensus)
-	isBuy := strings.EqualFold(event.Side, "BUY")
-	if isBuy && price <= cfg.ContrarianMaxPrice && notional >= cfg.ContrarianMinNotional {
-		reasons = append(reasons, AlertReasonContrarianBet)
-	}
-
-	// Check for massive trade (whale alert) - only at non-obvious prices
-	if notional >= cfg.MassiveTradeMinNotional && price <= cfg.MassiveTradeMaxPrice {
-		reasons = append(reasons, AlertReasonMassiveTrade)
-	}
-
-	// Check for known contrarian winner (from historical cache)
-	// Only alert if the current trade is at a non-obvious price (same threshold as massive trades)
-	if tm.contrarianCache != nil && tm.contrarianCache.ShouldAlert(traderAddr) && price <= cfg.MassiveTradeMaxPrice {
-		reasons = append(reasons, AlertReasonContrarianWinner)
-	}
-
-	// Copy trading detection
-	if tm.copyTracker != nil {
-		// Get market condition ID for copy tracking
-		var conditionID, tokenID string
-		if info != nil {
-			conditionID = info.ConditionID
-		}
-		tokenID = event.AssetID
-
-		// Check if this trader is a leader (high win rate or contrarian winner)
-		if tm.copyTracker.IsLeader(stats) {
-			tm.copyTracker.RecordLeaderTrade(traderAddr, conditionID, tokenID, strings.ToUpper(event.Side))
-		}
+	price := event.GetPriceFloat()
+	size := event.GetSizeFloat()
 
-		// Check if this trade is copying a leader
-		if isCopy, _ := tm.copyTracker.CheckForCopy(traderAddr, conditionID, tokenID, strings.ToUpper(event.Side)); isCopy {
-			if tm.copyTracker.ShouldAlert(traderAddr) {
-				reasons = append(reasons, AlertReasonCopyTrader)
-			}
-		}
+	trade := NormalizedTrade{
+		Key:        fmt.Sprintf("%s:%s", event.TransactionHash, event.AssetID),
+		Wallet:     traderAddr,
+		TraderName: shortID(traderAddr),
+		TokenID:    event.AssetID,
+		Outcome:    outcome,
+		Side:       strings.ToUpper(event.Side),
+		Size:       size,
+		Price:      price,
+		Notional:   price * size,
+		Timestamp:  time.Unix(event.GetTimestampUnix(), 0),
+		Fills: []notifier.Fill{{
+			TradeID: event.TradeID,
+			Maker:   event.MakerAddress,
+			Shares:  size,
+			Price:   price,
+		}},
 	}
-
-	// Hedge pattern detection (for SELL trades)
-	isSell := strings.EqualFold(event.Side, "SELL")
-	var hedgeAlerts []HedgeAlert
-	if tm.hedgeTracker != nil && isSell {
-		var conditionID, marketTitle, marketSlug string
-		if info != nil {
-			conditionID = info.ConditionID
-			marketTitle = info.Title
-			marketSlug = info.Slug
-		}
-		if conditionID != "" && tm.hedgeTracker.ShouldCheckPositions(traderAddr, conditionID) {
-			hedgeAlerts = tm.hedgeTracker.ProcessTrade(ctx, traderAddr, conditionID, marketTitle, marketSlug, event.Side, outcome, size, price)
-			for _, ha := range hedgeAlerts {
-				if ha.Reason == "hedge_removal" {
-					reasons = append(reasons, AlertReasonHedgeRemoval)
-				} else if ha.Reason == "asymmetric_exit" {
-					reasons = append(reasons, AlertReasonAsymmetricExit)
-				}
-			}
-		}
+	if info != nil {
+		trade.ConditionID = info.ConditionID
+		trade.MarketTitle = info.Title
+		trade.MarketSlug = info.Slug
+		trade.MarketImage = info.Image
 	}
-
-	// Advanced pattern detection (conviction doubling, stealth accumulation, exit timing)
-	var patternAlerts []PatternAlert
-	if tm.patternTracker != nil {
-		var conditionID, marketTitle, marketSlug string
-		if info != nil {
-			conditionID = info.ConditionID
-			marketTitle = info.Title
-			marketSlug = info.Slug
-		}
-		patternAlerts = tm.patternTracker.ProcessTrade(ctx, ProcessTradeInput{
-			Wallet:      traderAddr,
-			ConditionID: conditionID,
-			MarketTitle: marketTitle,
-			MarketSlug:  marketSlug,
-			TokenID:     event.AssetID,
-			Outcome:     outcome,
-			Side:        strings.ToUpper(event.Side),
-			Size:        size,
-			Price:       price,
-			Notional:    notional,
-			Timestamp:   tradeTime,
-		})
-		for _, pa := range patternAlerts {
-			switch pa.Reason {
-			case "conviction_doubling":
-				reasons = append(reasons, AlertReasonConvictionDoubling)
-			case "stealth_accumulation":
-				reasons = append(reasons, AlertReasonStealthAccumulation)
-			}
-		}
-		// Check for perfect exit timing (based on historical stats)
-		if tm.patternTracker.ShouldAlertPerfectTiming(traderAddr) {
-			reasons = append(reasons, AlertReasonPerfectExitTiming)
-		}
+	if quote, ok := tm.books.Quote(event.AssetID); ok {
+		trade.BookBefore = &quote
 	}
 
-	// Skip if no alert reasons
-	if len(reasons) == 0 {
-		tm.filterStatsMu.Lock()
-		tm.skippedHighActivity++
-		tm.filterStatsMu.Unlock()
+	// An order filling against several makers arrives as one event per fill.
+	// Collect them so the detectors see the whole order.
+	if window := tm.getConfig().FillAggregationWindow; window > 0 && event.TransactionHash != "" {
+		tm.orders.Add(event.TransactionHash, &trade, window)
 		return
 	}
 
-	// Skip obvious trades (high price = high probability outcome)
-	if cfg.ObviousPrice > 0 && price >= cfg.ObviousPrice {
-		tm.filterStatsMu.Lock()
-		tm.skippedObvious++
-		tm.filterStatsMu.Unlock()
-		return
-	}
-
-	// Skip traders with no resolved positions (N/A win rate) or win rate <= 50%
-	// Exception: allow special alerts regardless of win rate
-	hasSpecialReason := false
-	for _, r := range reasons {
-		if r == AlertReasonNewWallet || r == AlertReasonContrarianBet || r == AlertReasonMassiveTrade || r == AlertReasonContrarianWinner || r == AlertReasonCopyTrader || r == AlertReasonHedgeRemoval || r == AlertReasonAsymmetricExit || r == AlertReasonConvictionDoubling || r == AlertReasonPerfectExitTiming || r == AlertReasonStealthAccumulation {
-			hasSpecialReason = true
-			break
-		}
-	}
-	if !hasSpecialReason && (resolvedCount == 0 || stats.WinRate <= 0.50) {
-		return
-	}
-
-	// Build wallet and market URLs
-	walletURL := fmt.Sprintf("https://polymarket.com/profile/%s", traderAddr)
-	var marketURL string
-	var marketTitle, marketImage string
-	var conditionID string
-	if info != nil {
-		marketTitle = info.Title
-		marketImage = info.Image
-		conditionID = info.ConditionID
-		if info.Slug != "" {
-			marketURL = fmt.Sprintf("https://polymarket.com/event/%s", info.Slug)
-		}
-	}
-
-	// Fetch inventory for this wallet in this market
-	inv := tm.fetchInventory(ctx, traderAddr, conditionID, outcome, isSell)
-
-	// Build and send alert
-	alert := notifier.TradeAlert{
-		TraderName:        shortID(traderAddr),
-		TraderAddress:     traderAddr,
-		WalletURL:         walletURL,
-		Side:              strings.ToUpper(event.Side),
-		Shares:            size,
-		Price:             price,
-		Notional:          notional,
-		MarketTitle:       marketTitle,
-		MarketURL:         marketURL,
-		MarketImage:       marketImage,
-		ConditionID:       conditionID,
-		Outcome:           outcome,
-		UniqueMarkets:     stats.UniqueMarkets,
-		WinRate:           stats.WinRate,
-		WinCount:          stats.WinCount,
-		LossCount:         stats.LossCount,
-		InventoryShares:   inv.Shares,
-		InventoryAvgPrice: inv.AvgPrice,
-		InventoryValue:    inv.CurrentValue,
-		HasInventory:      inv.HasInventory,
-		ClosedCostBasis:   inv.ClosedCostBasis,
-		ClosedRealizedPnl: inv.ClosedRealizedPnl,
-		HasClosedInfo:     inv.HasClosedInfo,
-		Reasons:           reasons,
-		Timestamp:         tradeTime,
-	}
-
-	// Add hedge info if available
-	for _, ha := range hedgeAlerts {
-		if ha.Reason == "hedge_removal" {
-			alert.HedgeYesSizeBefore = ha.YesSizeBefore
-			alert.HedgeNoSizeBefore = ha.NoSizeBefore
-			alert.HedgeYesSizeAfter = ha.YesSizeAfter
-			alert.HedgeNoSizeAfter = ha.NoSizeAfter
-			alert.HedgeSoldSide = ha.SoldSide
-			alert.HedgeSoldPct = ha.ReductionPct
-			alert.HasHedgeInfo = true
-		} else if ha.Reason == "asymmetric_exit" {
-			alert.AsymmetricWinExits = ha.WinExitCount
-			alert.AsymmetricLossExits = ha.LossExitCount
-			alert.AsymmetricWinAvgHoldSec = ha.AvgWinHoldTime.Seconds()
-			alert.AsymmetricLossAvgHoldSec = ha.AvgLossHoldTime.Seconds()
-			alert.AsymmetricRatio = ha.AsymmetricRatio
-			alert.HasAsymmetricInfo = true
-		}
-	}
-
-	// Add pattern alert info if available
-	for _, pa := range patternAlerts {
-		switch pa.Reason {
-		case "conviction_doubling":
-			alert.ConvictionExistingSize = pa.ConvictionExistingSize
-			alert.ConvictionExistingAvg = pa.ConvictionExistingAvg
-			alert.ConvictionCurrentPrice = pa.ConvictionCurrentPrice
-			alert.ConvictionLossPct = pa.ConvictionLossPct
-			alert.ConvictionAddedSize = pa.ConvictionAddedSize
-			alert.ConvictionAddedValue = pa.ConvictionAddedValue
-			alert.HasConvictionInfo = true
-		case "stealth_accumulation":
-			alert.StealthTradeCount = pa.StealthTradeCount
-			alert.StealthTotalSize = pa.StealthTotalSize
-			alert.StealthTotalValue = pa.StealthTotalValue
-			alert.StealthAvgPrice = pa.StealthAvgPrice
-			alert.StealthSpreadMins = pa.StealthSpreadMins
-			alert.HasStealthInfo = true
-		}
-	}
+	tm.processNormalizedTrade(ctx, &trade)
+}
 
-	// Add perfect exit timing stats if available
-	if tm.patternTracker != nil {
-		if stats := tm.patternTracker.GetExitTimingStats(traderAddr); stats != nil && stats.VerifiedExits >= tm.patternTracker.config.PerfectExitMinExits {
-			alert.PerfectExitScore = stats.AvgTimingScore
-			alert.PerfectExitCount = stats.VerifiedExits
-			alert.PerfectExitPerfectCount = stats.PerfectExits
-			alert.HasPerfectExitInfo = true
-		}
+// flushOrders evaluates buffered orders whose fills are complete.
+// Pass force to evaluate every buffered order.
+func (tm *TradeMonitor) flushOrders(ctx context.Context, force bool) {
+	for _, trade := range tm.orders.Due(force) {
+		tm.processNormalizedTrade(ctx, trade)
 	}
-
-	tm.sendAlert(alert)
 }
 
 // runPolling runs the fallback polling mode.
@@ -906,161 +863,196 @@ func (tm *TradeMonitor) IsWSConnected() bool {
 }
 
 func (tm *TradeMonitor) processTrade(ctx context.Context, trade polymarketapi.Trade) {
-	// Get config once at the start for thread-safe access
-	cfg := tm.getConfig()
+	tm.processNormalizedTrade(ctx, tm.normalizeTrade(trade))
+}
 
-	// Skip if already seen
-	tradeKey := tm.tradeKey(trade)
-	tm.seenMu.Lock()
-	if _, seen := tm.seenTrades[tradeKey]; seen {
-		tm.seenMu.Unlock()
-		return
+// normalizeTrade converts a data API trade into a NormalizedTrade.
+func (tm *TradeMonitor) normalizeTrade(trade polymarketapi.Trade) *NormalizedTrade {
+	return &NormalizedTrade{
+		Key:         tm.tradeKey(trade),
+		Wallet:      trade.ProxyWallet,
+		TraderName:  tm.traderDisplayName(trade),
+		TokenID:     trade.Asset,
+		ConditionID: trade.ConditionID,
+		MarketTitle: trade.Title,
+		MarketSlug:  trade.Slug,
+		MarketImage: trade.Icon,
+		Outcome:     trade.Outcome,
+		Side:        strings.ToUpper(trade.Side),
+		Size:        trade.Size,
+		Price:       trade.Price,
+		Notional:    trade.Size * trade.Price,
+		Timestamp:   time.Unix(trade.Timestamp, 0),
 	}
-	tm.seenTrades[tradeKey] = struct{}{}
-	tm.seenMu.Unlock()
+}
 
-	// Calculate notional value
-	notional := trade.Size * trade.Price
-	if notional < cfg.MinNotional {
-		return
-	}
+// RegisterDetector adds a heuristic to the detection pipeline.
+// Detectors run in registration order after the built-in ones.
+func (tm *TradeMonitor) RegisterDetector(d Detector) {
+	tm.detectorsMu.Lock()
+	defer tm.detectorsMu.Unlock()
+	tm.detectors = append(tm.detectors, d)
+}
 
-	// Check wallet activity and win rate
-	isLow, stats, err := tm.walletTracker.IsLowActivity(
-		ctx,
-		trade.ProxyWallet,
-		cfg.MaxMarketsForLow,
-	)
-	if err != nil {
-		tm.logger.Warn("failed to check wallet activity",
-			zap.String("wallet", shortID(trade.ProxyWallet)),
-			zap.Error(err),
-		)
-		return
-	}
+// Detectors returns the registered detectors in run order.
+func (tm *TradeMonitor) Detectors() []Detector {
+	tm.detectorsMu.RLock()
+	defer tm.detectorsMu.RUnlock()
+	result := make([]Detector, len(tm.detectors))
+	copy(result, tm.detectors)
+	return result
+}
 
-	// Build alert reasons
+// runDetectors runs every registered detector against the trade and collects
+// the reasons and enrichers in registration order.
+func (tm *TradeMonitor) runDetectors(ctx context.Context, trade *NormalizedTrade, stats *WalletStats, cfg TradeMonitorConfig) ([]AlertReason, []func(*notifier.TradeAlert)) {
 	var reasons []AlertReason
-
-	// Check for low activity
-	if isLow {
-		reasons = append(reasons, AlertReasonLowActivity)
+	var enrichers []func(*notifier.TradeAlert)
+	for _, d := range tm.Detectors() {
+		detection := d.Detect(ctx, trade, stats, cfg)
+		reasons = append(reasons, detection.Reasons...)
+		if detection.Enrich != nil {
+			enrichers = append(enrichers, detection.Enrich)
+		}
 	}
+	return reasons, enrichers
+}
 
-	// Check for high win rate on non-obvious bets (entry price below threshold)
-	// This filters out wins on "obvious" bets (e.g., buying at 98¢)
-	// Also skip if this is a BUY/SELL at a high price (obvious outcome or cashing out)
-	resolvedCount := stats.WinCount + stats.LossCount
-	suspiciousCount := stats.SuspiciousWins + stats.SuspiciousLosses
-	isObviousBuy := strings.EqualFold(trade.Side, "BUY") && trade.Price >= 0.95
-	isObviousSell := strings.EqualFold(trade.Side, "SELL") && trade.Price >= 0.95
-	if suspiciousCount >= cfg.MinResolvedForWinRate &&
-		stats.SuspiciousWinRate >= cfg.HighWinRateThreshold &&
-		!isObviousBuy && !isObviousSell {
-		reasons = append(reasons, AlertReasonHighWinRate)
-	}
+// tradeVerdict is the outcome of running a trade through the alert heuristics.
+type tradeVerdict int
 
-	// Check for extreme low odds bet (longshot/contrarian)
-	if trade.Price <= cfg.ExtremeLowPrice && notional >= cfg.ExtremeMinNotional {
-		reasons = append(reasons, AlertReasonExtremeBet)
-	}
+const (
+	verdictAlert      tradeVerdict = iota // Trade should be alerted on
+	verdictNoReasons                      // No detector matched
+	verdictObvious                        // Price is above the obvious price threshold
+	verdictLowWinRate                     // Trader has no resolved positions or a win rate <= 50%
+)
 
-	// Check for rapid trading (multiple trades in short window)
-	tradeTime := time.Unix(trade.Timestamp, 0)
-	isRapid, _, _ := tm.checkRapidTrading(trade.ProxyWallet, notional, tradeTime)
-	if isRapid {
-		reasons = append(reasons, AlertReasonRapidTrading)
-	}
+// evaluateTrade runs the detectors and the post-detection filters for a trade.
+// It is shared by live monitoring and backtesting so both apply identical rules.
+func (tm *TradeMonitor) evaluateTrade(ctx context.Context, trade *NormalizedTrade, stats *WalletStats, cfg TradeMonitorConfig) ([]AlertReason, []func(*notifier.TradeAlert), tradeVerdict) {
+	reasons, enrichers := tm.runDetectors(ctx, trade, stats, cfg)
 
-	// Check for new wallet making large bet
-	if stats.UniqueMarkets <= cfg.NewWalletMaxMarkets && notional >= cfg.NewWalletMinNotional {
-		reasons = append(reasons, AlertReasonNewWallet)
+	// Skip if no alert reasons
+	if len(reasons) == 0 {
+		return nil, nil, verdictNoReasons
 	}
 
-	// Check for contrarian bet (buying at low prices = betting against consensus)
-	isBuy := strings.EqualFold(trade.Side, "BUY")
-	if isBuy && trade.Price <= cfg.ContrarianMaxPrice && notional >= cfg.ContrarianMinNotional {
-		reasons = append(reasons, AlertReasonContrarianBet)
+	// Skip obvious trades (high price = high probability outcome)
+	if cfg.ObviousPrice > 0 && trade.Price >= cfg.ObviousPrice {
+		return reasons, nil, verdictObvious
 	}
 
-	// Check for massive trade (whale alert) - only at non-obvious prices
-	if notional >= cfg.MassiveTradeMinNotional && trade.Price <= cfg.MassiveTradeMaxPrice {
-		reasons = append(reasons, AlertReasonMassiveTrade)
+	// Skip traders with no resolved positions (N/A win rate) or win rate <= 50%
+	// Exception: allow special alerts regardless of win rate
+	resolvedCount := stats.WinCount + stats.LossCount
+	if !hasSpecialReason(reasons) && (resolvedCount == 0 || stats.WinRate <= 0.50) {
+		return reasons, nil, verdictLowWinRate
 	}
 
-	// Check for known contrarian winner (from historical cache)
-	// Only alert if the current trade is at a non-obvious price (same threshold as massive trades)
-	if tm.contrarianCache != nil && tm.contrarianCache.ShouldAlert(trade.ProxyWallet) && trade.Price <= cfg.MassiveTradeMaxPrice {
-		reasons = append(reasons, AlertReasonContrarianWinner)
+	return reasons, enrichers, verdictAlert
+}
+
+// processNormalizedTrade runs a trade from either ingestion path through
+// dedup, filters, the detector pipeline and alerting.
+func (tm *TradeMonitor) processNormalizedTrade(ctx context.Context, trade *NormalizedTrade) {
+	// Get config once at the start for thread-safe access
+	cfg := tm.getConfig()
+
+	// Skip if already seen
+	tm.seenMu.Lock()
+	if _, seen := tm.seenTrades[trade.Key]; seen {
+		tm.seenMu.Unlock()
+		return
 	}
+	tm.seenTrades[trade.Key] = struct{}{}
+	tm.seenMu.Unlock()
 
-	// Copy trading detection
-	if tm.copyTracker != nil {
-		conditionID := trade.ConditionID
-		tokenID := trade.Asset
+	if quote, ok := tm.books.Quote(trade.TokenID); ok {
+		trade.BookAfter = &quote
+	}
 
-		// Check if this trader is a leader (high win rate or contrarian winner)
-		if tm.copyTracker.IsLeader(stats) {
-			tm.copyTracker.RecordLeaderTrade(trade.ProxyWallet, conditionID, tokenID, strings.ToUpper(trade.Side))
-		}
+	// Market series see every trade, including those too small to alert on
+	if tm.anomalies != nil {
+		tm.anomalies.Record(trade)
+	}
+	if tm.walletClusters != nil {
+		tm.walletClusters.Record(trade)
+	}
 
-		// Check if this trade is copying a leader
-		if isCopy, _ := tm.copyTracker.CheckForCopy(trade.ProxyWallet, conditionID, tokenID, strings.ToUpper(trade.Side)); isCopy {
-			if tm.copyTracker.ShouldAlert(trade.ProxyWallet) {
-				reasons = append(reasons, AlertReasonCopyTrader)
-			}
-		}
+	if trade.Notional < cfg.MinNotional {
+		tm.filterStatsMu.Lock()
+		tm.skippedLowNotional++
+		tm.filterStatsMu.Unlock()
+		return
 	}
 
-	// Skip if no alert reasons
-	if len(reasons) == 0 {
+	if trade.Wallet == "" {
+		tm.filterStatsMu.Lock()
+		tm.skippedNoWallet++
+		tm.filterStatsMu.Unlock()
 		return
 	}
 
-	// Skip obvious trades (high price = high probability outcome)
-	if cfg.ObviousPrice > 0 && trade.Price >= cfg.ObviousPrice {
+	// Check wallet filter
+	if !tm.shouldProcessWallet(trade.Wallet) {
 		tm.filterStatsMu.Lock()
-		tm.skippedObvious++
+		tm.skippedWalletFilter++
 		tm.filterStatsMu.Unlock()
 		return
 	}
 
-	// Skip traders with no resolved positions (N/A win rate) or win rate <= 50%
-	// Exception: allow special alerts regardless of win rate
-	hasSpecialReason := false
-	for _, r := range reasons {
-		if r == AlertReasonNewWallet || r == AlertReasonContrarianBet || r == AlertReasonMassiveTrade || r == AlertReasonContrarianWinner || r == AlertReasonCopyTrader || r == AlertReasonHedgeRemoval || r == AlertReasonAsymmetricExit {
-			hasSpecialReason = true
-			break
-		}
+	// Fetch wallet activity and win rate
+	stats, err := tm.walletTracker.GetStats(ctx, trade.Wallet)
+	if err != nil {
+		tm.logger.Warn("failed to check wallet activity",
+			zap.String("wallet", shortID(trade.Wallet)),
+			zap.Error(err),
+		)
+		return
 	}
-	if !hasSpecialReason && (resolvedCount == 0 || stats.WinRate <= 0.50) {
+	if tm.walletClusters != nil {
+		stats = tm.walletClusters.Annotate(ctx, stats)
+	}
+
+	reasons, enrichers, verdict := tm.evaluateTrade(ctx, trade, stats, cfg)
+	switch verdict {
+	case verdictNoReasons:
+		tm.filterStatsMu.Lock()
+		tm.skippedHighActivity++
+		tm.filterStatsMu.Unlock()
+		return
+	case verdictObvious:
+		tm.filterStatsMu.Lock()
+		tm.skippedObvious++
+		tm.filterStatsMu.Unlock()
+		return
+	case verdictLowWinRate:
 		return
 	}
 
 	// Build wallet and market URLs
-	walletURL := fmt.Sprintf("https://polymarket.com/profile/%s", trade.ProxyWallet)
+	walletURL := fmt.Sprintf("https://polymarket.com/profile/%s", trade.Wallet)
 	var marketURL string
-	if trade.Slug != "" {
-		marketURL = fmt.Sprintf("https://polymarket.com/event/%s", trade.Slug)
+	if trade.MarketSlug != "" {
+		marketURL = fmt.Sprintf("https://polymarket.com/event/%s", trade.MarketSlug)
 	}
 
 	// Fetch inventory for this wallet in this market
-	isSell := strings.EqualFold(trade.Side, "SELL")
-	inv := tm.fetchInventory(ctx, trade.ProxyWallet, trade.ConditionID, trade.Outcome, isSell)
+	inv := tm.fetchInventory(ctx, trade.Wallet, trade.ConditionID, trade.Outcome, trade.IsSell())
 
-	// Create and send alert
+	// Build and send alert
 	alert := notifier.TradeAlert{
-		TraderName:        tm.traderDisplayName(trade),
-		TraderAddress:     trade.ProxyWallet,
+		TraderName:        trade.TraderName,
+		TraderAddress:     trade.Wallet,
 		WalletURL:         walletURL,
 		Side:              trade.Side,
 		Shares:            trade.Size,
 		Price:             trade.Price,
-		Notional:          notional,
-		MarketTitle:       trade.Title,
+		Notional:          trade.Notional,
+		MarketTitle:       trade.MarketTitle,
 		MarketURL:         marketURL,
-		MarketImage:       trade.Icon,
+		MarketImage:       trade.MarketImage,
 		ConditionID:       trade.ConditionID,
 		Outcome:           trade.Outcome,
 		UniqueMarkets:     stats.UniqueMarkets,
@@ -1074,8 +1066,17 @@ func (tm *TradeMonitor) processTrade(ctx context.Context, trade polymarketapi.Tr
 		ClosedCostBasis:   inv.ClosedCostBasis,
 		ClosedRealizedPnl: inv.ClosedRealizedPnl,
 		HasClosedInfo:     inv.HasClosedInfo,
+		Fills:             trade.Fills,
 		Reasons:           reasons,
-		Timestamp:         tradeTime,
+		Timestamp:         trade.Timestamp,
+	}
+
+	setBookInfo(&alert, trade)
+	setWalletClusterInfo(&alert, stats)
+
+	// Add detector-specific details (hedge, pattern, exit timing info)
+	for _, enrich := range enrichers {
+		enrich(&alert)
 	}
 
 	tm.sendAlert(alert)
@@ -1167,7 +1168,33 @@ func (tm *TradeMonitor) fetchInventory(ctx context.Context, wallet, conditionID,
 	return result
 }
 
+// SendDeferredAlert delivers an alert raised outside the trade path (e.g. by a
+// background tracker) through the same stats and notifier path as live alerts.
+func (tm *TradeMonitor) SendDeferredAlert(alert notifier.TradeAlert) {
+	tm.sendAlert(alert)
+}
+
+// newAlertID derives a short stable ID for an alert.
+func newAlertID(alert notifier.TradeAlert) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%s|%s|%d|%.6f",
+		strings.ToLower(alert.TraderAddress), alert.ConditionID, alert.Outcome, alert.Side,
+		alert.Timestamp.UnixNano(), alert.Notional)))
+	return hex.EncodeToString(sum[:8])
+}
+
 func (tm *TradeMonitor) sendAlert(alert notifier.TradeAlert) {
+	// Repeats of a recent alert are merged into a later update
+	if tm.suppressor != nil {
+		var send bool
+		if alert, send = tm.suppressor.Check(alert); !send {
+			return
+		}
+	}
+
+	if alert.ID == "" {
+		alert.ID = newAlertID(alert)
+	}
+
 	tm.filterStatsMu.Lock()
 	tm.alertsSent++
 	for _, r := range alert.Reasons {
@@ -1202,6 +1229,12 @@ func (tm *TradeMonitor) sendAlert(alert notifier.TradeAlert) {
 			tm.alertsPerfectExitTiming++
 		case AlertReasonStealthAccumulation:
 			tm.alertsStealthAccumulation++
+		case AlertReasonPreMovePositioning:
+			tm.alertsPreMovePositioning++
+		case AlertReasonMarketImpact:
+			tm.alertsMarketImpact++
+		case AlertReasonCoordinatedEntry:
+			tm.alertsCoordinatedEntry++
 		}
 	}
 	tm.filterStatsMu.Unlock()
@@ -1302,6 +1335,26 @@ func (tm *TradeMonitor) sendAlert(alert notifier.TradeAlert) {
 		zap.String("winRecord", fmt.Sprintf("%d-%d", alert.WinCount, alert.LossCount)),
 	)
 
+	// Track the alert so it can be scored once the market resolves
+	// (updates repeat an alert that is already tracked)
+	if tm.outcomeTracker != nil && !alert.IsUpdate {
+		tm.outcomeTracker.RecordAlert(alert)
+	}
+
+	// Muted wallets and markets stay on the dashboard but aren't notified
+	if tm.muteList != nil && tm.muteList.IsMuted(alert) {
+		tm.logger.Debug("alert muted",
+			zap.String("address", shortID(alert.TraderAddress)),
+			zap.String("market", alert.MarketTitle),
+		)
+		return
+	}
+
+	// Index the alert so feedback buttons can refer back to it
+	if tm.feedback != nil {
+		tm.feedback.RecordAlert(alert)
+	}
+
 	// Send to all registered notifiers
 	if tm.notifier != nil {
 		tm.notifier.SendTradeAlert(alert)
@@ -1330,7 +1383,7 @@ func (tm *TradeMonitor) checkRapidTrading(wallet string, notional float64, trade
 	tm.recentTradesMu.Lock()
 	defer tm.recentTradesMu.Unlock()
 
-	now := time.Now()
+	now := tm.clock()
 	cutoff := now.Add(-cfg.RapidTradeWindow)
 
 	// Get existing trades and filter to recent ones
@@ -1368,7 +1421,7 @@ func (tm *TradeMonitor) pruneRecentTrades() {
 	tm.recentTradesMu.Lock()
 	defer tm.recentTradesMu.Unlock()
 
-	cutoff := time.Now().Add(-cfg.RapidTradeWindow)
+	cutoff := tm.clock().Add(-cfg.RapidTradeWindow)
 	for wallet, trades := range tm.recentTrades {
 		var recent []recentTrade
 		for _, t := range trades {
@@ -1389,6 +1442,9 @@ type SeenTradesSnapshot struct {
 	Version   int       `json:"version"`
 	Timestamp time.Time `json:"timestamp"`
 	Trades    []string  `json:"trades"`
+
+	// Open alert suppression windows, so repeats stay merged across restarts
+	Suppression []SuppressionWindow `json:"suppression,omitempty"`
 }
 
 // ExportSeenTrades exports the seen trades as a snapshot.
@@ -1401,16 +1457,31 @@ func (tm *TradeMonitor) ExportSeenTrades() *SeenTradesSnapshot {
 		trades = append(trades, key)
 	}
 
-	return &SeenTradesSnapshot{
+	snapshot := &SeenTradesSnapshot{
 		Version:   1,
 		Timestamp: time.Now(),
 		Trades:    trades,
 	}
+	if tm.suppressor != nil {
+		snapshot.Suppression = tm.suppressor.Export()
+	}
+	return snapshot
 }
 
 // ImportSeenTrades imports a snapshot of seen trades.
 func (tm *TradeMonitor) ImportSeenTrades(snapshot *SeenTradesSnapshot) int {
-	if snapshot == nil || len(snapshot.Trades) == 0 {
+	if snapshot == nil {
+		return 0
+	}
+
+	if tm.suppressor != nil && len(snapshot.Suppression) > 0 {
+		windows := tm.suppressor.Import(snapshot.Suppression)
+		tm.logger.Info("imported alert suppression windows",
+			zap.Int("windows", windows),
+		)
+	}
+
+	if len(snapshot.Trades) == 0 {
 		return 0
 	}
 
@@ -1465,6 +1536,7 @@ type FilterStats struct {
 	SkippedNoWallet            int
 	SkippedHighActivity        int
 	SkippedObvious             int
+	AggregatedFills            int64 // WebSocket fills combined into an earlier fill's order
 	AlertsSent                 int
 	AlertsLowActivity          int
 	AlertsHighWinRate          int
@@ -1481,6 +1553,9 @@ type FilterStats struct {
 	AlertsConvictionDoubling   int
 	AlertsPerfectExitTiming    int
 	AlertsStealthAccumulation  int
+	AlertsPreMovePositioning   int
+	AlertsMarketImpact         int
+	AlertsCoordinatedEntry     int
 }
 
 // FilterStats returns the current filter statistics.
@@ -1492,6 +1567,7 @@ func (tm *TradeMonitor) FilterStats() FilterStats {
 		SkippedNoWallet:           tm.skippedNoWallet,
 		SkippedHighActivity:       tm.skippedHighActivity,
 		SkippedObvious:            tm.skippedObvious,
+		AggregatedFills:           tm.orders.CombinedFills(),
 		AlertsSent:                tm.alertsSent,
 		AlertsLowActivity:         tm.alertsLowActivity,
 		AlertsHighWinRate:         tm.alertsHighWinRate,
@@ -1508,6 +1584,9 @@ func (tm *TradeMonitor) FilterStats() FilterStats {
 		AlertsConvictionDoubling:  tm.alertsConvictionDoubling,
 		AlertsPerfectExitTiming:   tm.alertsPerfectExitTiming,
 		AlertsStealthAccumulation: tm.alertsStealthAccumulation,
+		AlertsPreMovePositioning:  tm.alertsPreMovePositioning,
+		AlertsMarketImpact:        tm.alertsMarketImpact,
+		AlertsCoordinatedEntry:    tm.alertsCoordinatedEntry,
 	}
 }
 
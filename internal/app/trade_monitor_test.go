@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"polybot/clients/notifier"
 	"polybot/clients/polymarketapi"
+	"polybot/clients/polymarketevents"
 	"polybot/config"
 	"testing"
 	"time"
@@ -368,6 +369,89 @@ func TestSendAlert_NoNotifier(t *testing.T) {
 	monitor.sendAlert(alert)
 }
 
+func TestSendAlert_MutedNotNotified(t *testing.T) {
+	capture := &captureNotifier{}
+	monitor := NewTradeMonitor(zap.NewNop(), nil, nil, nil, nil, capture, DefaultTradeMonitorConfig())
+	mutes := NewMuteList(zap.NewNop(), nil, DefaultMuteListConfig())
+	mutes.Mute(MuteKindWallet, "0xMUTED", time.Hour, "")
+	monitor.SetMuteList(mutes)
+
+	monitor.sendAlert(notifier.TradeAlert{TraderAddress: "0xmuted", Side: "BUY"})
+	monitor.sendAlert(notifier.TradeAlert{TraderAddress: "0xother", Side: "BUY"})
+
+	if alerts := capture.Alerts(); len(alerts) != 1 || alerts[0].TraderAddress != "0xother" {
+		t.Errorf("expected only the unmuted alert notified, got %+v", alerts)
+	}
+	if len(monitor.RecentAlerts()) != 2 {
+		t.Error("expected muted alerts to stay on the dashboard")
+	}
+}
+
+func TestSendAlert_IndexesAlertForFeedback(t *testing.T) {
+	capture := &captureNotifier{}
+	monitor := NewTradeMonitor(zap.NewNop(), nil, nil, nil, nil, capture, DefaultTradeMonitorConfig())
+	feedback := NewAlertFeedbackStore(zap.NewNop(), nil, DefaultAlertFeedbackConfig())
+	monitor.SetAlertFeedbackStore(feedback)
+
+	monitor.sendAlert(notifier.TradeAlert{TraderAddress: "0xabc", ConditionID: "0xmarket", Side: "BUY", Timestamp: time.Now()})
+
+	alerts := capture.Alerts()
+	if len(alerts) != 1 || len(alerts[0].ID) != 16 {
+		t.Fatalf("expected notified alert to carry an ID, got %+v", alerts)
+	}
+	if _, ok := feedback.Alert(alerts[0].ID); !ok {
+		t.Error("expected alert indexed for feedback")
+	}
+}
+
+func TestSendAlert_SuppressesRepeats(t *testing.T) {
+	capture := &captureNotifier{}
+	monitor := NewTradeMonitor(zap.NewNop(), nil, nil, nil, nil, capture, DefaultTradeMonitorConfig())
+	suppressor := NewAlertSuppressor(zap.NewNop(), DefaultAlertSuppressorConfig())
+	now := time.Now()
+	suppressor.now = func() time.Time { return now }
+	monitor.SetAlertSuppressor(suppressor)
+
+	alert := notifier.TradeAlert{
+		TraderAddress: "0xabc",
+		ConditionID:   "0xmarket",
+		Side:          "BUY",
+		Shares:        100,
+		Price:         0.5,
+		Notional:      50,
+		Reasons:       []AlertReason{AlertReasonMassiveTrade},
+		Timestamp:     now,
+	}
+	monitor.sendAlert(alert)
+	monitor.sendAlert(alert)
+	monitor.sendAlert(alert)
+
+	if alerts := capture.Alerts(); len(alerts) != 1 {
+		t.Fatalf("expected repeats suppressed, got %d alerts", len(alerts))
+	}
+
+	// Restarting carries the open window over with the seen trades
+	snapshot := monitor.ExportSeenTrades()
+	if len(snapshot.Suppression) != 1 {
+		t.Fatalf("expected suppression window in snapshot, got %+v", snapshot.Suppression)
+	}
+	restored := NewTradeMonitor(zap.NewNop(), nil, nil, nil, nil, nil, DefaultTradeMonitorConfig())
+	restoredSuppressor := NewAlertSuppressor(zap.NewNop(), DefaultAlertSuppressorConfig())
+	restored.SetAlertSuppressor(restoredSuppressor)
+	restored.ImportSeenTrades(snapshot)
+	if stats := restoredSuppressor.Stats(); stats.Windows != 1 {
+		t.Errorf("expected window restored, got %+v", stats)
+	}
+
+	now = now.Add(11 * time.Minute)
+	suppressor.Flush()
+
+	alerts := capture.Alerts()
+	if len(alerts) != 2 || !alerts[1].IsUpdate || alerts[1].MergedFills != 2 || alerts[1].TotalNotional != 150 {
+		t.Errorf("expected update with the merged repeats, got %+v", alerts)
+	}
+}
+
 func TestSendAlert_BuildsCorrectURLs(t *testing.T) {
 	alert := notifier.TradeAlert{
 		TraderAddress: "0xWALLET",
@@ -1162,3 +1246,31 @@ func TestShouldProcessWallet(t *testing.T) {
 	}
 }
 
+func TestTradeMonitor_ShutdownFlushesOrders(t *testing.T) {
+	monitor, server, tracker := newTestTradeMonitor(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]any{})
+	})
+	defer server.Close()
+
+	capture := &captureNotifier{}
+	monitor.notifier = capture
+	monitor.SetEventsClient(polymarketevents.NewPolymarketEventsClient(nil))
+	cfg := monitor.getConfig()
+	cfg.FillAggregationWindow = time.Minute
+	monitor.UpdateConfig(cfg)
+	tracker.cache["0xwallet"] = &WalletStats{Wallet: "0xwallet", UniqueMarkets: 1, WinCount: 3, LossCount: 1, WinRate: 0.75, FetchedAt: time.Now()}
+
+	// The order is still collecting fills when the monitor shuts down
+	monitor.processWebSocketMessage(context.Background(), []byte(`{"event_type":"trade","asset_id":"token1","price":"0.5","size":"5000","side":"BUY","taker_address":"0xwallet","transaction_hash":"0xtx1"}`))
+	if monitor.orders.Pending() != 1 {
+		t.Fatalf("expected 1 pending order, got %d", monitor.orders.Pending())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	monitor.Run(ctx)
+
+	if monitor.orders.Pending() != 0 || len(capture.Alerts()) != 1 {
+		t.Errorf("expected the pending order flushed and alerted, got %d pending and %d alerts", monitor.orders.Pending(), len(capture.Alerts()))
+	}
+}
@@ -0,0 +1,645 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+// maxClusterMarketTrades caps the recent trades kept per market for comparison.
+const maxClusterMarketTrades = 1000
+
+// maxFunderWallets ignores funding sources shared by more wallets than this.
+// Such addresses are exchanges and bridges rather than one actor.
+const maxFunderWallets = 25
+
+// clusterSweepInterval is how often stale pair evidence is dropped.
+const clusterSweepInterval = 1 * time.Hour
+
+// Signals that link two wallets.
+const (
+	LinkSignalCoTrading = "co-trading" // Trade the same markets within seconds of each other
+	LinkSignalSizing    = "sizing"     // Trade identical sizes in the same markets
+	LinkSignalCopying   = "copying"    // One keeps copying the other's trades
+	LinkSignalFunding   = "funding"    // Funded from the same address
+)
+
+// WalletClusterConfig holds configuration for wallet clustering.
+type WalletClusterConfig struct {
+	Enabled     bool
+	MinNotional float64 // Trades below this are not compared (e.g., 1000)
+
+	// Co-trading: same outcome and side within seconds
+	CoTradeWindow     time.Duration // e.g., 10s
+	CoTradeMinMarkets int           // Distinct markets co-traded before a link (e.g., 3)
+
+	// Identical sizing: same outcome, side and share count
+	SizeWindow     time.Duration // e.g., 1h
+	SizeTolerance  float64       // Relative difference counted as identical (e.g., 0.01 = 1%)
+	SizeMinMarkets int           // Distinct markets with identical sizes before a link (e.g., 3)
+
+	CopyMinCount   int           // Copies of one leader before a follower is linked (0 = off)
+	EvidenceMaxAge time.Duration // How long evidence and funders are kept after last seen (e.g., 7 days)
+}
+
+// DefaultWalletClusterConfig returns sensible defaults.
+func DefaultWalletClusterConfig() WalletClusterConfig {
+	return WalletClusterConfig{
+		Enabled:           true,
+		MinNotional:       1000,
+		CoTradeWindow:     10 * time.Second,
+		CoTradeMinMarkets: 3,
+		SizeWindow:        1 * time.Hour,
+		SizeTolerance:     0.01,
+		SizeMinMarkets:    3,
+		CopyMinCount:      5,
+		EvidenceMaxAge:    7 * 24 * time.Hour,
+	}
+}
+
+// FundingSource looks up where wallets got their funds, e.g. from an
+// on-chain indexer. Wallets funded from the same address are linked.
+type FundingSource interface {
+	// Funder returns the address that first funded the wallet, or "" if unknown.
+	Funder(ctx context.Context, wallet string) (string, error)
+}
+
+// WalletLink is the evidence that two wallets are the same actor.
+type WalletLink struct {
+	A              string   `json:"a"`
+	B              string   `json:"b"`
+	Signals        []string `json:"signals"`
+	CoTradeMarkets int      `json:"coTradeMarkets,omitempty"` // Markets traded within seconds of each other
+	SizeMarkets    int      `json:"sizeMarkets,omitempty"`    // Markets traded with identical sizes
+	Copies         int      `json:"copies,omitempty"`         // Times one copied the other
+	Funder         string   `json:"funder,omitempty"`         // Shared funding source
+}
+
+// addSignal records a signal once.
+func (l *WalletLink) addSignal(signal string) {
+	for _, s := range l.Signals {
+		if s == signal {
+			return
+		}
+	}
+	l.Signals = append(l.Signals, signal)
+}
+
+// WalletCluster is a group of wallets connected by links.
+type WalletCluster struct {
+	ID      string       `json:"id"`
+	Wallets []string     `json:"wallets"` // Lowercased, sorted
+	Links   []WalletLink `json:"links"`
+	Signals []string     `json:"signals"` // Every signal among the links
+}
+
+// WalletClusterStats summarizes wallet clustering.
+type WalletClusterStats struct {
+	Enabled  bool `json:"enabled"`
+	Clusters int  `json:"clusters"`
+	Wallets  int  `json:"wallets"` // Wallets in a cluster
+	Links    int  `json:"links"`
+	Pairs    int  `json:"pairs"` // Wallet pairs with evidence, linked or not
+}
+
+// walletPair is two lowercased wallets, a < b.
+type walletPair struct {
+	a, b string
+}
+
+func newWalletPair(x, y string) walletPair {
+	if x > y {
+		x, y = y, x
+	}
+	return walletPair{a: x, b: y}
+}
+
+// pairEvidence is what two wallets have been seen doing together.
+type pairEvidence struct {
+	coTrade  map[string]struct{} // Condition IDs co-traded
+	sizes    map[string]struct{} // Condition IDs traded with identical sizes
+	lastSeen time.Time
+}
+
+// funderLookup is a wallet's funder and when it was looked up.
+type funderLookup struct {
+	funder string // "" = looked up, unknown
+	at     time.Time
+}
+
+// clusterTrade is a trade kept for comparison with later trades in its market.
+type clusterTrade struct {
+	wallet  string
+	tokenID string
+	side    string
+	shares  float64
+	at      time.Time
+}
+
+// WalletClusters links proxy wallets that appear to be the same actor, using
+// signals derived from the trade stream: trading the same outcome within
+// seconds of each other, trading identical sizes, and copy-tracker
+// leader/follower pairs. An optional FundingSource adds links between wallets
+// funded from the same address. Linked wallets form clusters, identified by
+// a short ID derived from the cluster's lowest address.
+//
+// Time is taken from trade timestamps, so replays are linked as they happened.
+type WalletClusters struct {
+	logger *zap.Logger
+
+	// Config with mutex for hot-reload support
+	configMu sync.RWMutex
+	config   WalletClusterConfig
+
+	copies  *CopyTracker  // May be nil
+	funding FundingSource // May be nil
+
+	mu        sync.Mutex
+	trades    map[string][]clusterTrade // condition ID -> recent trades, oldest first
+	evidence  map[walletPair]*pairEvidence
+	funders   map[string]funderLookup
+	lastSweep time.Time
+
+	// Clusters are rebuilt only after the evidence or copy pairs change
+	cache       []WalletCluster
+	cacheIndex  map[string]int // wallet -> index in cache
+	cacheCopies []CopyPair     // Copy pairs the cache was built from
+	stale       bool
+}
+
+// NewWalletClusters creates an empty wallet clustering subsystem.
+func NewWalletClusters(logger *zap.Logger, config WalletClusterConfig) *WalletClusters {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &WalletClusters{
+		logger:   logger.Named("wallet-clusters"),
+		config:   config,
+		trades:   make(map[string][]clusterTrade),
+		evidence: make(map[walletPair]*pairEvidence),
+		funders:  make(map[string]funderLookup),
+		stale:    true,
+	}
+}
+
+// SetCopyTracker sets the tracker whose leader/follower pairs are linked.
+func (c *WalletClusters) SetCopyTracker(tracker *CopyTracker) {
+	c.copies = tracker
+}
+
+// SetFundingSource sets the source used to link wallets by funder.
+func (c *WalletClusters) SetFundingSource(source FundingSource) {
+	c.funding = source
+}
+
+func (c *WalletClusters) getConfig() WalletClusterConfig {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+	return c.config
+}
+
+// UpdateConfig updates the configuration (for hot-reload support).
+func (c *WalletClusters) UpdateConfig(cfg WalletClusterConfig) {
+	c.configMu.Lock()
+	c.config = cfg
+	c.configMu.Unlock()
+
+	c.mu.Lock()
+	c.stale = true
+	c.mu.Unlock()
+}
+
+// Record compares a trade with the recent trades of its market and adds
+// co-trading and sizing evidence for each wallet it matches.
+func (c *WalletClusters) Record(trade *NormalizedTrade) {
+	cfg := c.getConfig()
+	if !cfg.Enabled || trade.Wallet == "" || trade.ConditionID == "" || trade.Notional < cfg.MinNotional {
+		return
+	}
+
+	wallet := strings.ToLower(trade.Wallet)
+	side := strings.ToUpper(trade.Side)
+	keep := max(cfg.CoTradeWindow, cfg.SizeWindow)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	trades := c.trades[trade.ConditionID]
+	start := 0
+	for start < len(trades) && trades[start].at.Before(trade.Timestamp.Add(-keep)) {
+		start++
+	}
+	trades = trades[start:]
+
+	for _, t := range trades {
+		if t.wallet == wallet || t.tokenID != trade.TokenID || t.side != side {
+			continue
+		}
+		gap := trade.Timestamp.Sub(t.at).Abs()
+		coTrade := gap <= cfg.CoTradeWindow
+		sized := gap <= cfg.SizeWindow && sameSize(t.shares, trade.Size, cfg.SizeTolerance)
+		if !coTrade && !sized {
+			continue
+		}
+
+		pair := newWalletPair(wallet, t.wallet)
+		ev, ok := c.evidence[pair]
+		if !ok {
+			ev = &pairEvidence{coTrade: make(map[string]struct{}), sizes: make(map[string]struct{})}
+			c.evidence[pair] = ev
+		}
+		if coTrade {
+			c.addEvidence(ev.coTrade, trade.ConditionID, cfg.CoTradeMinMarkets)
+		}
+		if sized {
+			c.addEvidence(ev.sizes, trade.ConditionID, cfg.SizeMinMarkets)
+		}
+		if trade.Timestamp.After(ev.lastSeen) {
+			ev.lastSeen = trade.Timestamp
+		}
+	}
+
+	trades = append(trades, clusterTrade{
+		wallet:  wallet,
+		tokenID: trade.TokenID,
+		side:    side,
+		shares:  trade.Size,
+		at:      trade.Timestamp,
+	})
+	if len(trades) > maxClusterMarketTrades {
+		trades = trades[len(trades)-maxClusterMarketTrades:]
+	}
+	c.trades[trade.ConditionID] = trades
+
+	if trade.Timestamp.Sub(c.lastSweep) >= clusterSweepInterval {
+		c.sweep(trade.Timestamp.Add(-cfg.EvidenceMaxAge), cfg)
+		c.lastSweep = trade.Timestamp
+	}
+}
+
+// addEvidence adds a market to a pair's evidence, marking the clusters stale
+// if it changes a link.
+func (c *WalletClusters) addEvidence(markets map[string]struct{}, conditionID string, minMarkets int) {
+	if _, ok := markets[conditionID]; ok {
+		return
+	}
+	markets[conditionID] = struct{}{}
+	if len(markets) >= minMarkets {
+		c.stale = true
+	}
+}
+
+// sameSize reports whether two share counts are identical within tolerance.
+// Round lots (multiples of 100 shares) are used by too many unrelated traders
+// to count.
+func sameSize(a, b, tolerance float64) bool {
+	if a <= 0 || b <= 0 {
+		return false
+	}
+	if isRoundLot(a) || isRoundLot(b) {
+		return false
+	}
+	return math.Abs(a-b) <= tolerance*math.Max(a, b)
+}
+
+func isRoundLot(shares float64) bool {
+	return math.Mod(shares, 100) == 0
+}
+
+// sweep drops evidence last seen before the cutoff and funders looked up more
+// than EvidenceMaxAge ago. Funders are looked up in real time, not trade time.
+func (c *WalletClusters) sweep(cutoff time.Time, cfg WalletClusterConfig) {
+	for pair, ev := range c.evidence {
+		if ev.lastSeen.Before(cutoff) {
+			if ev.linked(cfg) {
+				c.stale = true
+			}
+			delete(c.evidence, pair)
+		}
+	}
+
+	funderCutoff := time.Now().Add(-cfg.EvidenceMaxAge)
+	for wallet, lookup := range c.funders {
+		if lookup.at.Before(funderCutoff) {
+			if lookup.funder != "" {
+				c.stale = true
+			}
+			delete(c.funders, wallet)
+		}
+	}
+}
+
+// linked reports whether the evidence is enough to link the pair.
+func (ev *pairEvidence) linked(cfg WalletClusterConfig) bool {
+	return len(ev.coTrade) >= cfg.CoTradeMinMarkets || len(ev.sizes) >= cfg.SizeMinMarkets
+}
+
+// Retain drops the recent trades of markets that are no longer monitored.
+// Evidence already gathered is kept.
+func (c *WalletClusters) Retain(conditionIDs []string) {
+	keep := make(map[string]bool, len(conditionIDs))
+	for _, id := range conditionIDs {
+		keep[id] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range c.trades {
+		if !keep[id] {
+			delete(c.trades, id)
+		}
+	}
+}
+
+// lookupFunder fetches a wallet's funder once, if a funding source is set.
+func (c *WalletClusters) lookupFunder(ctx context.Context, wallet string) {
+	if c.funding == nil {
+		return
+	}
+	c.mu.Lock()
+	_, known := c.funders[wallet]
+	c.mu.Unlock()
+	if known {
+		return
+	}
+
+	funder, err := c.funding.Funder(ctx, wallet)
+	if err != nil {
+		c.logger.Warn("failed to look up wallet funder",
+			zap.String("wallet", shortID(wallet)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	c.mu.Lock()
+	c.funders[wallet] = funderLookup{funder: strings.ToLower(funder), at: time.Now()}
+	if funder != "" {
+		c.stale = true
+	}
+	c.mu.Unlock()
+}
+
+// links builds every link from the current evidence. Must hold mu.
+func (c *WalletClusters) links(cfg WalletClusterConfig, copyPairs []CopyPair) map[walletPair]*WalletLink {
+	links := make(map[walletPair]*WalletLink)
+	link := func(pair walletPair) *WalletLink {
+		l, ok := links[pair]
+		if !ok {
+			l = &WalletLink{A: pair.a, B: pair.b}
+			links[pair] = l
+		}
+		return l
+	}
+
+	for pair, ev := range c.evidence {
+		if len(ev.coTrade) >= cfg.CoTradeMinMarkets {
+			l := link(pair)
+			l.CoTradeMarkets = len(ev.coTrade)
+			l.addSignal(LinkSignalCoTrading)
+		}
+		if len(ev.sizes) >= cfg.SizeMinMarkets {
+			l := link(pair)
+			l.SizeMarkets = len(ev.sizes)
+			l.addSignal(LinkSignalSizing)
+		}
+	}
+
+	for _, p := range copyPairs {
+		pair := newWalletPair(strings.ToLower(p.Follower), strings.ToLower(p.Leader))
+		if pair.a == pair.b {
+			continue
+		}
+		l := link(pair)
+		l.Copies += p.Copies
+		l.addSignal(LinkSignalCopying)
+	}
+
+	// Wallets sharing a funder are linked to the lowest of them
+	funded := make(map[string][]string)
+	for wallet, lookup := range c.funders {
+		if lookup.funder != "" && lookup.funder != wallet {
+			funded[lookup.funder] = append(funded[lookup.funder], wallet)
+		}
+	}
+	for funder, wallets := range funded {
+		if len(wallets) < 2 || len(wallets) > maxFunderWallets {
+			continue
+		}
+		sort.Strings(wallets)
+		for _, w := range wallets[1:] {
+			l := link(newWalletPair(wallets[0], w))
+			l.Funder = funder
+			l.addSignal(LinkSignalFunding)
+		}
+	}
+
+	return links
+}
+
+// clusters returns every cluster, largest first, and the index of each
+// wallet's cluster. They are cached until the evidence or copy pairs change.
+// The result is shared and must not be modified.
+func (c *WalletClusters) clusters() ([]WalletCluster, map[string]int) {
+	cfg := c.getConfig()
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	var copyPairs []CopyPair
+	if c.copies != nil && cfg.CopyMinCount > 0 {
+		copyPairs = c.copies.CopyPairs(cfg.CopyMinCount)
+		sort.Slice(copyPairs, func(i, j int) bool {
+			if copyPairs[i].Follower != copyPairs[j].Follower {
+				return copyPairs[i].Follower < copyPairs[j].Follower
+			}
+			return copyPairs[i].Leader < copyPairs[j].Leader
+		})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.stale && slices.Equal(copyPairs, c.cacheCopies) {
+		return c.cache, c.cacheIndex
+	}
+	c.cache = buildWalletClusters(c.links(cfg, copyPairs))
+	c.cacheIndex = make(map[string]int)
+	for i, cluster := range c.cache {
+		for _, w := range cluster.Wallets {
+			c.cacheIndex[w] = i
+		}
+	}
+	c.cacheCopies = copyPairs
+	c.stale = false
+	return c.cache, c.cacheIndex
+}
+
+// buildWalletClusters groups linked wallets into clusters, largest first.
+func buildWalletClusters(links map[walletPair]*WalletLink) []WalletCluster {
+
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(w string) string {
+		p, ok := parent[w]
+		if !ok || p == w {
+			parent[w] = w
+			return w
+		}
+		root := find(p)
+		parent[w] = root
+		return root
+	}
+	for pair := range links {
+		ra, rb := find(pair.a), find(pair.b)
+		if ra != rb {
+			if ra > rb {
+				ra, rb = rb, ra
+			}
+			parent[rb] = ra
+		}
+	}
+
+	groups := make(map[string]*WalletCluster)
+	for wallet := range parent {
+		root := find(wallet)
+		g, ok := groups[root]
+		if !ok {
+			g = &WalletCluster{}
+			groups[root] = g
+		}
+		g.Wallets = append(g.Wallets, wallet)
+	}
+	for pair, l := range links {
+		g := groups[find(pair.a)]
+		g.Links = append(g.Links, *l)
+	}
+
+	result := make([]WalletCluster, 0, len(groups))
+	for _, g := range groups {
+		sort.Strings(g.Wallets)
+		g.ID = walletClusterID(g.Wallets[0])
+		sort.Slice(g.Links, func(i, j int) bool {
+			if g.Links[i].A != g.Links[j].A {
+				return g.Links[i].A < g.Links[j].A
+			}
+			return g.Links[i].B < g.Links[j].B
+		})
+		signals := make(map[string]bool)
+		for _, l := range g.Links {
+			for _, s := range l.Signals {
+				signals[s] = true
+			}
+		}
+		g.Signals = orderedSignals(signals)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Wallets) != len(result[j].Wallets) {
+			return len(result[i].Wallets) > len(result[j].Wallets)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// orderedSignals lists signals in a fixed order.
+func orderedSignals(signals map[string]bool) []string {
+	var result []string
+	for _, s := range []string{LinkSignalCoTrading, LinkSignalSizing, LinkSignalCopying, LinkSignalFunding} {
+		if signals[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// walletClusterID derives a short cluster ID from its lowest wallet address.
+// The ID changes if a lower address later joins the cluster.
+func walletClusterID(wallet string) string {
+	h := fnv.New32a()
+	h.Write([]byte(wallet))
+	return fmt.Sprintf("C-%08x", h.Sum32())
+}
+
+// Clusters returns every cluster, largest first.
+func (c *WalletClusters) Clusters() []WalletCluster {
+	clusters, _ := c.clusters()
+	return slices.Clone(clusters)
+}
+
+// Cluster returns the cluster with the given ID.
+func (c *WalletClusters) Cluster(id string) (WalletCluster, bool) {
+	clusters, _ := c.clusters()
+	for _, cluster := range clusters {
+		if strings.EqualFold(cluster.ID, id) {
+			return cluster, true
+		}
+	}
+	return WalletCluster{}, false
+}
+
+// ClusterOf returns the cluster a wallet belongs to.
+func (c *WalletClusters) ClusterOf(wallet string) (WalletCluster, bool) {
+	clusters, index := c.clusters()
+	i, ok := index[strings.ToLower(wallet)]
+	if !ok {
+		return WalletCluster{}, false
+	}
+	return clusters[i], true
+}
+
+// Annotate looks up the wallet's funder if needed and returns a copy of stats
+// with its cluster set. Returns stats unchanged if the wallet isn't in one.
+func (c *WalletClusters) Annotate(ctx context.Context, stats *WalletStats) *WalletStats {
+	if stats == nil || !c.getConfig().Enabled {
+		return stats
+	}
+	wallet := strings.ToLower(stats.Wallet)
+	c.lookupFunder(ctx, wallet)
+
+	cluster, ok := c.ClusterOf(wallet)
+	if !ok {
+		return stats
+	}
+	annotated := *stats
+	annotated.ClusterID = cluster.ID
+	annotated.ClusterSize = len(cluster.Wallets)
+	annotated.ClusterSignals = cluster.Signals
+	return &annotated
+}
+
+// Stats returns clustering metrics for the dashboard.
+func (c *WalletClusters) Stats() WalletClusterStats {
+	cfg := c.getConfig()
+	stats := WalletClusterStats{Enabled: cfg.Enabled}
+	clusters, _ := c.clusters()
+	for _, cluster := range clusters {
+		stats.Clusters++
+		stats.Wallets += len(cluster.Wallets)
+		stats.Links += len(cluster.Links)
+	}
+
+	c.mu.Lock()
+	stats.Pairs = len(c.evidence)
+	c.mu.Unlock()
+	return stats
+}
+
+// setWalletClusterInfo adds the wallet's cluster to an alert.
+func setWalletClusterInfo(alert *notifier.TradeAlert, stats *WalletStats) {
+	if stats.ClusterID == "" {
+		return
+	}
+	alert.WalletClusterID = stats.ClusterID
+	alert.WalletClusterSize = stats.ClusterSize
+	alert.WalletClusterSignals = stats.ClusterSignals
+}
@@ -0,0 +1,306 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"polybot/clients/notifier"
+
+	"go.uber.org/zap"
+)
+
+var walletClusterStart = time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+func walletClusterTrade(wallet, conditionID string, at time.Duration, shares float64) *NormalizedTrade {
+	return &NormalizedTrade{
+		Wallet:      wallet,
+		ConditionID: conditionID,
+		TokenID:     conditionID + "-yes",
+		Side:        "BUY",
+		Price:       0.50,
+		Size:        shares,
+		Notional:    shares * 0.50,
+		Timestamp:   walletClusterStart.Add(at),
+	}
+}
+
+// staticFunding returns a fixed funder per wallet.
+type staticFunding struct {
+	funders map[string]string
+	calls   int
+}
+
+func (f *staticFunding) Funder(_ context.Context, wallet string) (string, error) {
+	f.calls++
+	funder, ok := f.funders[wallet]
+	if !ok {
+		return "", errors.New("indexer unavailable")
+	}
+	return funder, nil
+}
+
+func TestWalletClusters_CoTrading(t *testing.T) {
+	c := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+
+	for i := 0; i < 3; i++ {
+		market := fmt.Sprintf("0xmarket%d", i)
+		at := time.Duration(i) * time.Hour
+		c.Record(walletClusterTrade("0xAAA", market, at, 4000))
+		c.Record(walletClusterTrade("0xbbb", market, at+5*time.Second, 5000))
+		// Too late to count as co-trading
+		c.Record(walletClusterTrade("0xccc", market, at+time.Minute, 6000))
+
+		if _, ok := c.ClusterOf("0xaaa"); ok != (i == 2) {
+			t.Fatalf("after %d markets: expected linked=%v", i+1, i == 2)
+		}
+	}
+
+	cluster, ok := c.ClusterOf("0xAAA")
+	if !ok || len(cluster.Wallets) != 2 || cluster.Wallets[0] != "0xaaa" || cluster.Wallets[1] != "0xbbb" {
+		t.Fatalf("unexpected cluster: %+v", cluster)
+	}
+	if len(cluster.Links) != 1 || cluster.Links[0].CoTradeMarkets != 3 || cluster.Signals[0] != LinkSignalCoTrading {
+		t.Errorf("unexpected links: %+v", cluster.Links)
+	}
+	if cluster.ID != walletClusterID("0xaaa") {
+		t.Errorf("expected ID from the lowest wallet, got %s", cluster.ID)
+	}
+	if _, ok := c.ClusterOf("0xccc"); ok {
+		t.Error("expected 0xccc not to be linked")
+	}
+	if found, ok := c.Cluster(cluster.ID); !ok || len(found.Wallets) != 2 {
+		t.Errorf("expected lookup by ID, got %+v", found)
+	}
+}
+
+func TestWalletClusters_Sizing(t *testing.T) {
+	c := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+
+	for i := 0; i < 3; i++ {
+		market := fmt.Sprintf("0xmarket%d", i)
+		at := time.Duration(i) * 2 * time.Hour
+		c.Record(walletClusterTrade("0xaaa", market, at, 4321))
+		c.Record(walletClusterTrade("0xbbb", market, at+20*time.Minute, 4330))
+		// Round lots are too common to count
+		c.Record(walletClusterTrade("0xccc", market, at+30*time.Minute, 5000))
+		c.Record(walletClusterTrade("0xddd", market, at+50*time.Minute, 5000))
+	}
+
+	cluster, ok := c.ClusterOf("0xbbb")
+	if !ok || len(cluster.Wallets) != 2 || cluster.Links[0].SizeMarkets != 3 || cluster.Signals[0] != LinkSignalSizing {
+		t.Errorf("unexpected cluster: %+v", cluster)
+	}
+	if _, ok := c.ClusterOf("0xccc"); ok {
+		t.Error("expected round lots not to link wallets")
+	}
+}
+
+func TestWalletClusters_IgnoresOtherSidesAndSmallTrades(t *testing.T) {
+	c := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+
+	for i := 0; i < 3; i++ {
+		market := fmt.Sprintf("0xmarket%d", i)
+		c.Record(walletClusterTrade("0xaaa", market, 0, 4321))
+
+		sell := walletClusterTrade("0xbbb", market, time.Second, 4321)
+		sell.Side = "SELL"
+		c.Record(sell)
+
+		c.Record(walletClusterTrade("0xccc", market, time.Second, 1001)) // $500 notional
+	}
+
+	if stats := c.Stats(); stats.Clusters != 0 || stats.Pairs != 0 {
+		t.Errorf("expected no evidence, got %+v", stats)
+	}
+}
+
+func TestWalletClusters_CopyPairsAndFunding(t *testing.T) {
+	cfg := DefaultWalletClusterConfig()
+	cfg.CopyMinCount = 2
+	c := NewWalletClusters(zap.NewNop(), cfg)
+
+	copies := NewCopyTracker(zap.NewNop(), DefaultCopyTrackerConfig(), nil)
+	copies.RecordLeaderTrade("0xLeader", "cond1", "token1", "BUY")
+	copies.CheckForCopy("0xfollower", "cond1", "token1", "BUY")
+	copies.CheckForCopy("0xfollower", "cond1", "token1", "BUY")
+	c.SetCopyTracker(copies)
+
+	funding := &staticFunding{funders: map[string]string{
+		"0xfollower": "0xFunder",
+		"0xnewbie":   "0xfunder",
+	}}
+	c.SetFundingSource(funding)
+
+	stats := c.Annotate(context.Background(), &WalletStats{Wallet: "0xFollower", UniqueMarkets: 2})
+	if stats.ClusterSize != 2 || len(stats.ClusterSignals) != 1 || stats.ClusterSignals[0] != LinkSignalCopying {
+		t.Fatalf("expected copy link, got %+v", stats)
+	}
+
+	stats = c.Annotate(context.Background(), &WalletStats{Wallet: "0xnewbie"})
+	if stats.ClusterSize != 3 {
+		t.Fatalf("expected the shared funder to join the cluster, got %+v", stats)
+	}
+	if want := []string{LinkSignalCopying, LinkSignalFunding}; fmt.Sprint(stats.ClusterSignals) != fmt.Sprint(want) {
+		t.Errorf("expected signals %v, got %v", want, stats.ClusterSignals)
+	}
+
+	// Funders are looked up once; failures are retried
+	c.Annotate(context.Background(), &WalletStats{Wallet: "0xnewbie"})
+	c.Annotate(context.Background(), &WalletStats{Wallet: "0xunknown"})
+	c.Annotate(context.Background(), &WalletStats{Wallet: "0xunknown"})
+	if funding.calls != 4 {
+		t.Errorf("expected 4 funder lookups, got %d", funding.calls)
+	}
+}
+
+func TestWalletClusters_Annotate(t *testing.T) {
+	c := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+	original := &WalletStats{Wallet: "0xaaa"}
+	if got := c.Annotate(context.Background(), original); got != original || got.ClusterID != "" {
+		t.Errorf("expected unlinked stats unchanged, got %+v", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		market := fmt.Sprintf("0xmarket%d", i)
+		c.Record(walletClusterTrade("0xaaa", market, 0, 4000))
+		c.Record(walletClusterTrade("0xbbb", market, time.Second, 4000))
+	}
+
+	got := c.Annotate(context.Background(), original)
+	if got == original || got.ClusterID == "" || got.ClusterSize != 2 {
+		t.Fatalf("expected an annotated copy, got %+v", got)
+	}
+	if original.ClusterID != "" {
+		t.Error("expected the cached stats not to be modified")
+	}
+
+	var alert notifier.TradeAlert
+	setWalletClusterInfo(&alert, got)
+	if alert.WalletClusterID != got.ClusterID || alert.WalletClusterSize != 2 {
+		t.Errorf("unexpected alert cluster: %+v", alert)
+	}
+}
+
+func TestWalletClusters_SweepAndRetain(t *testing.T) {
+	c := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+
+	// One co-traded market is evidence, not a link
+	c.Record(walletClusterTrade("0xaaa", "0xold", 0, 4000))
+	c.Record(walletClusterTrade("0xbbb", "0xold", time.Second, 4000))
+	if stats := c.Stats(); stats.Pairs != 1 {
+		t.Fatalf("expected 1 pair, got %+v", stats)
+	}
+
+	c.Retain([]string{"0xnew"})
+	c.Record(walletClusterTrade("0xccc", "0xnew", 8*24*time.Hour, 4000))
+	if stats := c.Stats(); stats.Pairs != 0 {
+		t.Errorf("expected stale evidence swept, got %+v", stats)
+	}
+	if len(c.trades) != 1 {
+		t.Errorf("expected only the monitored market kept, got %d", len(c.trades))
+	}
+}
+
+func TestWalletClusters_SweepExpiresLinksAndFunders(t *testing.T) {
+	c := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+	for i := 0; i < 3; i++ {
+		market := fmt.Sprintf("0xmarket%d", i)
+		c.Record(walletClusterTrade("0xaaa", market, 0, 4000))
+		c.Record(walletClusterTrade("0xbbb", market, time.Second, 4000))
+	}
+	c.funders["0xccc"] = funderLookup{funder: "0xfunder", at: time.Now().Add(-8 * 24 * time.Hour)}
+	c.funders["0xddd"] = funderLookup{funder: "0xfunder", at: time.Now()}
+	if stats := c.Stats(); stats.Clusters != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", stats)
+	}
+
+	// Links not seen again within the max age expire, as do old funder lookups
+	c.Record(walletClusterTrade("0xeee", "0xmarket0", 8*24*time.Hour, 4000))
+	if stats := c.Stats(); stats.Clusters != 0 || stats.Pairs != 0 {
+		t.Errorf("expected the stale link swept, got %+v", stats)
+	}
+	if _, ok := c.funders["0xccc"]; ok || len(c.funders) != 1 {
+		t.Errorf("expected only the recent funder lookup kept, got %+v", c.funders)
+	}
+}
+
+func TestWalletClusters_CachesClusters(t *testing.T) {
+	c := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+	for i := 0; i < 3; i++ {
+		market := fmt.Sprintf("0xmarket%d", i)
+		c.Record(walletClusterTrade("0xaaa", market, 0, 4000))
+		c.Record(walletClusterTrade("0xbbb", market, time.Second, 4000))
+	}
+	if _, ok := c.ClusterOf("0xaaa"); !ok || c.stale {
+		t.Fatal("expected a fresh cached cluster")
+	}
+
+	// Evidence short of a link leaves the cache in place
+	c.Record(walletClusterTrade("0xccc", "0xmarket0", 2*time.Second, 4000))
+	if c.stale {
+		t.Error("expected evidence short of a link not to invalidate the cache")
+	}
+
+	// A new link rebuilds it
+	c.Record(walletClusterTrade("0xccc", "0xmarket1", 2*time.Second, 4000))
+	c.Record(walletClusterTrade("0xccc", "0xmarket2", 2*time.Second, 4000))
+	if !c.stale {
+		t.Fatal("expected a new link to invalidate the cache")
+	}
+	if cluster, ok := c.ClusterOf("0xccc"); !ok || len(cluster.Wallets) != 3 {
+		t.Errorf("expected the rebuilt cluster to include 0xccc, got %+v", cluster)
+	}
+}
+
+func TestWalletClusters_Disabled(t *testing.T) {
+	cfg := DefaultWalletClusterConfig()
+	cfg.Enabled = false
+	c := NewWalletClusters(zap.NewNop(), cfg)
+
+	c.Record(walletClusterTrade("0xaaa", "0xmarket", 0, 4000))
+	c.Record(walletClusterTrade("0xbbb", "0xmarket", time.Second, 4000))
+	if len(c.trades) != 0 || len(c.evidence) != 0 {
+		t.Error("expected disabled clustering to record nothing")
+	}
+}
+
+func TestLookupWalletClusters(t *testing.T) {
+	c := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+	for i := 0; i < 3; i++ {
+		market := fmt.Sprintf("0xmarket%d", i)
+		c.Record(walletClusterTrade("0xaaa", market, 0, 4000))
+		c.Record(walletClusterTrade("0xbbb", market, time.Second, 4000))
+	}
+
+	all := lookupWalletClusters(c, WalletClustersRequest{})
+	if all.Total != 1 || len(all.Clusters) != 1 {
+		t.Fatalf("expected 1 cluster, got %+v", all)
+	}
+	id := all.Clusters[0].ID
+
+	if r := lookupWalletClusters(c, WalletClustersRequest{Query: " 0xBBB "}); len(r.Clusters) != 1 || r.Clusters[0].ID != id {
+		t.Errorf("expected lookup by wallet, got %+v", r)
+	}
+	if r := lookupWalletClusters(c, WalletClustersRequest{Query: id}); len(r.Clusters) != 1 {
+		t.Errorf("expected lookup by ID, got %+v", r)
+	}
+	if r := lookupWalletClusters(c, WalletClustersRequest{Query: "0xccc"}); len(r.Clusters) != 0 || r.Total != 0 {
+		t.Errorf("expected no cluster for an unlinked wallet, got %+v", r)
+	}
+}
+
+func TestTradeMonitor_RecordsWalletClusters(t *testing.T) {
+	monitor := NewTradeMonitor(nil, nil, nil, nil, nil, nil, DefaultTradeMonitorConfig())
+	clusters := NewWalletClusters(zap.NewNop(), DefaultWalletClusterConfig())
+	monitor.SetWalletClusters(clusters)
+
+	// Trades below the alert minimum are still compared
+	monitor.processNormalizedTrade(t.Context(), &NormalizedTrade{Key: "a", Wallet: "0xaaa", ConditionID: "0xm", TokenID: "t", Side: "BUY", Size: 2500, Notional: 1250, Timestamp: walletClusterStart})
+	monitor.processNormalizedTrade(t.Context(), &NormalizedTrade{Key: "b", Wallet: "0xbbb", ConditionID: "0xm", TokenID: "t", Side: "BUY", Size: 2500, Notional: 1250, Timestamp: walletClusterStart})
+	if stats := clusters.Stats(); stats.Pairs != 1 {
+		t.Errorf("expected the trades compared, got %+v", stats)
+	}
+}
@@ -22,6 +22,11 @@ type WalletStats struct {
 	SuspiciousLosses   int     // Losses where entry price was below threshold
 	SuspiciousWinRate  float64 // Win rate counting only non-obvious entry prices
 	FetchedAt          time.Time
+
+	// Set by WalletClusters when the wallet is linked to others
+	ClusterID      string
+	ClusterSize    int      // Wallets in the cluster, including this one
+	ClusterSignals []string // How the cluster is linked, e.g. "co-trading"
 }
 
 // WalletTracker caches wallet statistics to avoid repeated API calls.
@@ -122,16 +127,8 @@ func (wt *WalletTracker) fetchStats(ctx context.Context, wallet string) (*Wallet
 		return nil, err
 	}
 
-	// Count unique markets from activity
-	marketsSeen := make(map[string]struct{})
-	for _, a := range activity {
-		if a.ConditionID != "" {
-			marketsSeen[a.ConditionID] = struct{}{}
-		}
-	}
-
-	// Fetch closed positions to calculate win rate (API limits to 50 per request)
-	positions, err := wt.apiClient.GetClosedPositions(ctx, wallet, 50, 0)
+	// Fetch closed positions to calculate win rate
+	positions, err := wt.fetchClosedPositions(ctx, wallet)
 	if err != nil {
 		wt.logger.Warn("failed to fetch closed positions, win rate unavailable",
 			zap.String("wallet", shortID(wallet)),
@@ -141,78 +138,13 @@ func (wt *WalletTracker) fetchStats(ctx context.Context, wallet string) (*Wallet
 		positions = nil
 	}
 
-	// Fetch second batch if we got a full first batch
-	if len(positions) == 50 {
-		positions2, err := wt.apiClient.GetClosedPositions(ctx, wallet, 50, 50)
-		if err != nil {
-			wt.logger.Warn("failed to fetch second batch of closed positions",
-				zap.String("wallet", shortID(wallet)),
-				zap.Error(err),
-			)
-		} else {
-			positions = append(positions, positions2...)
-		}
+	// Track contrarian results asynchronously; each position counts once
+	if wt.contrarianCache != nil {
+		wt.contrarianCache.RecordClosedPositions(wallet, positions, wt.contrarianThreshold)
 	}
 
-	winCount := 0
-	lossCount := 0
-	suspiciousWins := 0
-	suspiciousLosses := 0
-
-	for _, p := range positions {
-		isWin := p.RealizedPnl > 0
-		isLoss := p.RealizedPnl < 0
-
-		// Check if this was a "non-obvious" bet (entry price below threshold)
-		// A bet at 0.98 is obvious (near-certain win), but 0.40 requires conviction
-		isSuspiciousEntry := p.AvgPrice <= wt.winRateMaxEntryPrice
-
-		if isWin {
-			winCount++
-			if isSuspiciousEntry {
-				suspiciousWins++
-			}
-		} else if isLoss {
-			lossCount++
-			if isSuspiciousEntry {
-				suspiciousLosses++
-			}
-		}
-
-		// Track contrarian results asynchronously
-		if wt.contrarianCache != nil && (isWin || isLoss) {
-			isContrarian := IsContrarianPrice(p.AvgPrice, wt.contrarianThreshold)
-			if isContrarian {
-				wt.contrarianCache.RecordContrarianResult(wallet, isWin)
-			}
-		}
-	}
-
-	winRate := 0.0
-	total := winCount + lossCount
-	if total > 0 {
-		winRate = float64(winCount) / float64(total)
-	}
-
-	// Calculate suspicious win rate (only counting non-obvious bets)
-	suspiciousWinRate := 0.0
-	suspiciousTotal := suspiciousWins + suspiciousLosses
-	if suspiciousTotal > 0 {
-		suspiciousWinRate = float64(suspiciousWins) / float64(suspiciousTotal)
-	}
-
-	stats := &WalletStats{
-		Wallet:            wallet,
-		UniqueMarkets:     len(marketsSeen),
-		TotalTrades:       len(activity),
-		WinCount:          winCount,
-		LossCount:         lossCount,
-		WinRate:           winRate,
-		SuspiciousWins:    suspiciousWins,
-		SuspiciousLosses:  suspiciousLosses,
-		SuspiciousWinRate: suspiciousWinRate,
-		FetchedAt:         time.Now(),
-	}
+	stats := computeWalletStats(wallet, activity, positions, wt.winRateMaxEntryPrice)
+	stats.FetchedAt = time.Now()
 
 	wt.logger.Debug("fetched wallet stats",
 		zap.String("wallet", shortID(wallet)),
@@ -226,6 +158,29 @@ func (wt *WalletTracker) fetchStats(ctx context.Context, wallet string) (*Wallet
 	return stats, nil
 }
 
+// fetchClosedPositions fetches a wallet's 100 most recent closed positions
+// (API limits to 50 per request).
+func (wt *WalletTracker) fetchClosedPositions(ctx context.Context, wallet string) ([]polymarketapi.ClosedPosition, error) {
+	positions, err := wt.apiClient.GetClosedPositions(ctx, wallet, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch second batch if we got a full first batch
+	if len(positions) == 50 {
+		positions2, err := wt.apiClient.GetClosedPositions(ctx, wallet, 50, 50)
+		if err != nil {
+			wt.logger.Warn("failed to fetch second batch of closed positions",
+				zap.String("wallet", shortID(wallet)),
+				zap.Error(err),
+			)
+		} else {
+			positions = append(positions, positions2...)
+		}
+	}
+	return positions, nil
+}
+
 // CacheSize returns the current number of cached wallets.
 func (wt *WalletTracker) CacheSize() int {
 	wt.mu.RLock()
@@ -393,3 +348,72 @@ func (wt *WalletTracker) ImportCacheJSON(data []byte) (int, error) {
 
 	return wt.ImportCache(&snapshot), nil
 }
+
+// computeWalletStats derives wallet stats from activity and closed positions.
+// Positions with an entry price at or below maxEntryPrice count towards the
+// suspicious win rate.
+func computeWalletStats(
+	wallet string,
+	activity []polymarketapi.Activity,
+	positions []polymarketapi.ClosedPosition,
+	maxEntryPrice float64,
+) *WalletStats {
+	// Count unique markets from activity
+	marketsSeen := make(map[string]struct{})
+	for _, a := range activity {
+		if a.ConditionID != "" {
+			marketsSeen[a.ConditionID] = struct{}{}
+		}
+	}
+
+	winCount := 0
+	lossCount := 0
+	suspiciousWins := 0
+	suspiciousLosses := 0
+
+	for _, p := range positions {
+		isWin := p.RealizedPnl > 0
+		isLoss := p.RealizedPnl < 0
+
+		// Check if this was a "non-obvious" bet (entry price below threshold)
+		// A bet at 0.98 is obvious (near-certain win), but 0.40 requires conviction
+		isSuspiciousEntry := p.AvgPrice <= maxEntryPrice
+
+		if isWin {
+			winCount++
+			if isSuspiciousEntry {
+				suspiciousWins++
+			}
+		} else if isLoss {
+			lossCount++
+			if isSuspiciousEntry {
+				suspiciousLosses++
+			}
+		}
+	}
+
+	winRate := 0.0
+	total := winCount + lossCount
+	if total > 0 {
+		winRate = float64(winCount) / float64(total)
+	}
+
+	// Calculate suspicious win rate (only counting non-obvious bets)
+	suspiciousWinRate := 0.0
+	suspiciousTotal := suspiciousWins + suspiciousLosses
+	if suspiciousTotal > 0 {
+		suspiciousWinRate = float64(suspiciousWins) / float64(suspiciousTotal)
+	}
+
+	return &WalletStats{
+		Wallet:            wallet,
+		UniqueMarkets:     len(marketsSeen),
+		TotalTrades:       len(activity),
+		WinCount:          winCount,
+		LossCount:         lossCount,
+		WinRate:           winRate,
+		SuspiciousWins:    suspiciousWins,
+		SuspiciousLosses:  suspiciousLosses,
+		SuspiciousWinRate: suspiciousWinRate,
+	}
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"io"
 	"os"
 	"os/signal"
 	clts "polybot/clients"
+	"polybot/clients/storage"
 	"polybot/config"
 	"polybot/internal/app"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -17,6 +20,13 @@ import (
 const (
 	// loadTimeout is the maximum time to wait for loading from gist
 	loadTimeout = 30 * time.Second
+
+	// migrateTimeout is the maximum time to wait for a gist migration
+	migrateTimeout = 5 * time.Minute
+
+	// settingsNamespace holds passkeys on non-gist storage backends when
+	// SETTINGS_GIST_ID is unset
+	settingsNamespace = "settings"
 )
 
 func main() {
@@ -30,18 +40,51 @@ func main() {
 	envConfig := config.Load()
 	logger.Info("starting bot", zap.Bool("isProd", envConfig.IsProd))
 
+	// Replays must not touch live state
+	if envConfig.Tape.IsReplay() {
+		logger.Info("replay mode: persistence disabled", zap.String("tape", envConfig.Tape.ReplayFile))
+		envConfig.DisablePersistence()
+	}
+
+	// Get settings Gist ID from env
+	settingsGistID := os.Getenv("SETTINGS_GIST_ID")
+	if envConfig.Tape.IsReplay() {
+		settingsGistID = ""
+	}
+
+	// Other backends persist every component; gists set so far are migration sources
+	var migrateIDs []string
+	if envConfig.Storage.MigrateFromGist {
+		migrateIDs = envConfig.GistIDs()
+		if settingsGistID != "" && !slices.Contains(migrateIDs, settingsGistID) {
+			migrateIDs = append(migrateIDs, settingsGistID)
+		}
+	}
+	envConfig.ApplyStorageNamespaces()
+	if settingsGistID == "" && !envConfig.Storage.IsGist() {
+		settingsGistID = settingsNamespace
+	}
+
 	// Create LiveConfig with env config as initial value
 	liveConfig := config.NewLiveConfig(envConfig)
 
 	// Initialize clients (needed for Gist access)
 	logger.Info("instantiating clients")
-	clients := clts.NewClients(logger, envConfig)
+	clients, err := clts.NewClients(logger, envConfig)
+	if err != nil {
+		logger.Fatal("failed to instantiate clients", zap.Error(err))
+	}
+	if closer, ok := clients.Storage.(io.Closer); ok {
+		defer closer.Close()
+	}
+	logger.Info("storage backend", zap.String("backend", envConfig.Storage.Backend))
 
-	// Get settings Gist ID from env
-	settingsGistID := os.Getenv("SETTINGS_GIST_ID")
+	if len(migrateIDs) > 0 && !envConfig.Storage.IsGist() {
+		migrateFromGist(logger, clients, migrateIDs)
+	}
 
 	// Create SettingsManager
-	settingsManager := config.NewSettingsManager(logger, clients.Gist, settingsGistID, liveConfig)
+	settingsManager := config.NewSettingsManager(logger, clients.Storage, settingsGistID, liveConfig)
 
 	// Load settings from Gist if enabled
 	if settingsManager.IsEnabled() {
@@ -73,7 +116,7 @@ func main() {
 	if settingsGistID != "" && rpID != "" && rpOriginsStr != "" {
 		rpOrigins := strings.Split(rpOriginsStr, ",")
 		var err error
-		authHandler, err = app.NewAuthHandler(logger, clients.Gist, settingsGistID, rpID, rpOrigins)
+		authHandler, err = app.NewAuthHandler(logger, clients.Storage, settingsGistID, rpID, rpOrigins)
 		if err != nil {
 			logger.Warn("failed to create auth handler", zap.Error(err))
 		} else {
@@ -101,3 +144,25 @@ func main() {
 		logger.Fatal("runner failed", zap.Error(err))
 	}
 }
+
+// migrateFromGist copies gist files that the storage backend doesn't have
+// yet. Files already stored locally are never overwritten.
+func migrateFromGist(logger *zap.Logger, clients *clts.Clients, gistIDs []string) {
+	if !clients.Gist.IsEnabled() {
+		logger.Warn("storage migration requires GITHUB_TOKEN, skipping")
+		return
+	}
+
+	logger.Info("migrating gists to storage backend", zap.Strings("gist_ids", gistIDs))
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	result, err := storage.Migrate(ctx, logger, clients.Gist, clients.Storage, gistIDs)
+	if err != nil {
+		logger.Warn("storage migration incomplete", zap.Error(err))
+	}
+	logger.Info("storage migration finished",
+		zap.Int("copied", result.Copied),
+		zap.Int("skipped", result.Skipped),
+	)
+}